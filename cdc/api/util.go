@@ -38,6 +38,10 @@ var httpBadRequestError = []*errors.Error{
 	cerror.ErrChangeFeedNotExists, cerror.ErrTargetTsBeforeStartTs, cerror.ErrTableIneligible,
 	cerror.ErrFilterRuleInvalid, cerror.ErrChangefeedUpdateRefused, cerror.ErrMySQLConnectionError,
 	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist, cerror.ErrSchedulerRequestFailed,
+	cerror.ErrUpstreamIsDefault, cerror.ErrUpstreamInUse, cerror.ErrInvalidNamespace,
+	cerror.ErrInvalidNamespaceInfo, cerror.ErrNamespaceNotExists, cerror.ErrNamespaceQuotaExceeded,
+	cerror.ErrHeldDDLNotFound, cerror.ErrVerificationNotFound, cerror.ErrVerificationIsRunning,
+	cerror.ErrBackfillInProgress,
 }
 
 const (
@@ -231,9 +235,10 @@ func ForwardToOwner(c *gin.Context, p capture.Capture) {
 	}
 }
 
-// HandleOwnerDrainCapture schedule drain the target capture
+// HandleOwnerDrainCapture starts, queries or cancels draining the target
+// capture according to the query type.
 func HandleOwnerDrainCapture(
-	ctx context.Context, capture capture.Capture, captureID string,
+	ctx context.Context, capture capture.Capture, query scheduler.Query,
 ) (*model.DrainCaptureResp, error) {
 	// Use buffered channel to prevent blocking owner.
 	done := make(chan error, 1)
@@ -242,10 +247,6 @@ func HandleOwnerDrainCapture(
 		return nil, errors.Trace(err)
 	}
 
-	query := scheduler.Query{
-		CaptureID: captureID,
-	}
-
 	o.DrainCapture(&query, done)
 
 	select {
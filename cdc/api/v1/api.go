@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
+	"github.com/pingcap/tiflow/cdc/scheduler"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/retry"
@@ -822,7 +823,8 @@ func (h *OpenAPI) DrainCapture(c *gin.Context) {
 		return
 	}
 
-	resp, err := api.HandleOwnerDrainCapture(ctx, h.capture, target)
+	resp, err := api.HandleOwnerDrainCapture(
+		ctx, h.capture, scheduler.Query{CaptureID: target})
 	if err != nil {
 		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
 		return
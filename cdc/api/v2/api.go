@@ -17,22 +17,26 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/tiflow/cdc/api/middleware"
 	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/pkg/verification"
 )
 
 // OpenAPIV2 provides CDC v2 APIs
 type OpenAPIV2 struct {
 	capture capture.Capture
 	helpers APIV2Helpers
+	// verifications runs consistency checks of changefeeds, they are
+	// only served by the owner.
+	verifications *verification.Manager
 }
 
 // NewOpenAPIV2 creates a new OpenAPIV2.
 func NewOpenAPIV2(c capture.Capture) OpenAPIV2 {
-	return OpenAPIV2{c, APIV2HelpersImpl{}}
+	return OpenAPIV2{c, APIV2HelpersImpl{}, verification.NewManager()}
 }
 
 // NewOpenAPIV2ForTest creates a new OpenAPIV2.
 func NewOpenAPIV2ForTest(c capture.Capture, h APIV2Helpers) OpenAPIV2 {
-	return OpenAPIV2{c, h}
+	return OpenAPIV2{c, h, verification.NewManager()}
 }
 
 // RegisterOpenAPIV2Routes registers routes for OpenAPI
@@ -46,6 +50,7 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	v2.GET("health", api.health)
 	v2.GET("status", api.serverStatus)
 	v2.POST("log", api.setLogLevel)
+	v2.GET("sorter/disk_usage", api.getSorterDiskUsage)
 
 	// changefeed apis
 	changefeedGroup := v2.Group("/changefeeds")
@@ -58,11 +63,21 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	changefeedGroup.GET("/:changefeed_id/meta_info", api.getChangeFeedMetaInfo)
 	changefeedGroup.POST("/:changefeed_id/resume", api.resumeChangefeed)
 	changefeedGroup.POST("/:changefeed_id/pause", api.pauseChangefeed)
+	changefeedGroup.GET("/:changefeed_id/events", api.listChangefeedEvents)
+	changefeedGroup.GET("/:changefeed_id/held_ddl", api.getHeldDDL)
+	changefeedGroup.POST("/:changefeed_id/held_ddl", api.handleHeldDDL)
+	changefeedGroup.POST("/:changefeed_id/backfill", api.backfillTable)
+	changefeedGroup.POST("/:changefeed_id/verification", api.startVerification)
+	changefeedGroup.GET("/:changefeed_id/verification", api.getVerification)
+	changefeedGroup.DELETE("/:changefeed_id/verification", api.cancelVerification)
 
 	// capture apis
 	captureGroup := v2.Group("/captures")
 	captureGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	captureGroup.POST("/:capture_id/drain", api.drainCapture)
+	captureGroup.GET("/:capture_id/drain", api.getDrainCapture)
+	captureGroup.DELETE("/:capture_id/drain", api.cancelDrainCapture)
+	captureGroup.GET("/:capture_id/drain/ready", api.drainCaptureReady)
 	captureGroup.GET("", api.listCaptures)
 
 	// processor apis
@@ -75,6 +90,20 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	verifyTableGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	verifyTableGroup.POST("", api.verifyTable)
 
+	// upstream apis
+	upstreamGroup := v2.Group("/upstreams")
+	upstreamGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	upstreamGroup.GET("", api.listUpstreams)
+	upstreamGroup.DELETE("/:upstream_id", api.removeUpstream)
+
+	// namespace apis
+	namespaceGroup := v2.Group("/namespaces")
+	namespaceGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	namespaceGroup.GET("", api.listNamespaces)
+	namespaceGroup.GET("/:namespace", api.getNamespace)
+	namespaceGroup.PUT("/:namespace", api.putNamespace)
+	namespaceGroup.DELETE("/:namespace", api.deleteNamespace)
+
 	// unsafe apis
 	unsafeGroup := v2.Group("/unsafe")
 	unsafeGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
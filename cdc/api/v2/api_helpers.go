@@ -155,7 +155,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	}
 
 	cfStatus, err := statusProvider.GetChangeFeedStatus(ctx,
-		model.DefaultChangeFeedID(cfg.ID))
+		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID})
 	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
 		return nil, err
 	}
@@ -179,7 +179,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 		ctx,
 		pdClient,
 		ensureGCServiceID,
-		model.DefaultChangeFeedID(cfg.ID),
+		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
 		ensureTTL, cfg.StartTs); err != nil {
 		if !cerror.ErrStartTsBeforeGC.Equal(err) {
 			return nil, cerror.ErrPDEtcdAPIError.Wrap(err)
@@ -188,6 +188,11 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	}
 
 	// verify target ts
+	targetTs, err := cfg.getTargetTs()
+	if err != nil {
+		return nil, err
+	}
+	cfg.TargetTs = targetTs
 	if cfg.TargetTs > 0 && cfg.TargetTs <= cfg.StartTs {
 		return nil, cerror.ErrTargetTsBeforeStartTs.GenWithStackByArgs(
 			cfg.TargetTs, cfg.StartTs)
@@ -318,13 +323,17 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 	}
 
 	var configUpdated, sinkURIUpdated bool
-	if cfg.TargetTs != 0 {
-		if cfg.TargetTs <= newInfo.StartTs {
+	targetTs, err := cfg.getTargetTs()
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+	}
+	if targetTs != 0 {
+		if targetTs <= newInfo.StartTs {
 			return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStack(
 				"can not update target_ts:%d less than start_ts:%d",
-				cfg.TargetTs, newInfo.StartTs)
+				targetTs, newInfo.StartTs)
 		}
-		newInfo.TargetTs = cfg.TargetTs
+		newInfo.TargetTs = targetTs
 	}
 	if cfg.ReplicaConfig != nil {
 		configUpdated = true
@@ -428,7 +437,7 @@ func (APIV2HelpersImpl) verifyResumeChangefeedConfig(ctx context.Context,
 		ctx,
 		pdClient,
 		gcServiceID,
-		model.DefaultChangeFeedID(changefeedID.ID),
+		changefeedID,
 		gcTTL, checkpointTs)
 	if err != nil {
 		if !cerror.ErrStartTsBeforeGC.Equal(err) {
@@ -14,19 +14,53 @@
 package v2
 
 import (
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/scheduler"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
 const apiOpVarCaptureID = "capture_id"
 
 // drainCapture remove all tables at the given capture.
+// @Summary Drain a capture
+// @Description move all tables out of the given capture, remaining tables
+// @Description are force-moved after the optional timeout
+// @Tags capture,v2
+// @Accept json
+// @Produce json
+// @Param capture_id path string true "capture_id"
+// @Param drainConfig body DrainCaptureConfig false "drain config"
+// @Success 202 {object} model.DrainCaptureResp
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/captures/{capture_id}/drain [post]
 func (h *OpenAPIV2) drainCapture(c *gin.Context) {
 	captureID := c.Param(apiOpVarCaptureID)
 
+	// The body is optional for compatibility.
+	cfg := new(DrainCaptureConfig)
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		// The content length is unknown for chunked requests, so decode the
+		// body and treat io.EOF as an empty body.
+		if err := c.ShouldBindJSON(cfg); err != nil && errors.Cause(err) != io.EOF {
+			_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+			return
+		}
+	}
+	query := scheduler.Query{
+		CaptureID: captureID,
+		Tp:        scheduler.DrainQueryStart,
+	}
+	if cfg.Timeout > 0 {
+		query.Deadline = time.Now().Add(time.Duration(cfg.Timeout) * time.Second)
+	}
+
 	ctx := c.Request.Context()
 	captures, err := h.capture.StatusProvider().GetCaptures(ctx)
 	if err != nil {
@@ -71,7 +105,7 @@ func (h *OpenAPIV2) drainCapture(c *gin.Context) {
 		return
 	}
 
-	resp, err := api.HandleOwnerDrainCapture(ctx, h.capture, target)
+	resp, err := api.HandleOwnerDrainCapture(ctx, h.capture, query)
 	if err != nil {
 		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
 		return
@@ -80,6 +114,77 @@ func (h *OpenAPIV2) drainCapture(c *gin.Context) {
 	c.JSON(http.StatusAccepted, resp)
 }
 
+// getDrainCapture gets the draining progress of the given capture.
+// @Summary Get drain capture progress
+// @Description get the tables that remain on the given capture
+// @Tags capture,v2
+// @Produce json
+// @Param capture_id path string true "capture_id"
+// @Success 200 {object} model.DrainCaptureResp
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/captures/{capture_id}/drain [get]
+func (h *OpenAPIV2) getDrainCapture(c *gin.Context) {
+	resp, err := h.queryDrainCapture(c, scheduler.DrainQueryProgress)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// cancelDrainCapture cancels draining the given capture.
+// @Summary Cancel draining a capture
+// @Description stop draining the given capture, the capture accepts tables again
+// @Tags capture,v2
+// @Produce json
+// @Param capture_id path string true "capture_id"
+// @Success 200 {object} model.DrainCaptureResp
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/captures/{capture_id}/drain [delete]
+func (h *OpenAPIV2) cancelDrainCapture(c *gin.Context) {
+	resp, err := h.queryDrainCapture(c, scheduler.DrainQueryCancel)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// drainCaptureReady reports whether the given capture has been drained.
+// It is meant to be polled by a preStop hook before stopping the capture.
+// @Summary Check whether a capture is drained
+// @Description return 200 if the given capture is being drained or has been
+// @Description drained, and no table remains on it, otherwise 503
+// @Tags capture,v2
+// @Produce json
+// @Param capture_id path string true "capture_id"
+// @Success 200 {object} model.DrainCaptureResp
+// @Failure 503 {object} model.DrainCaptureResp
+// @Router /api/v2/captures/{capture_id}/drain/ready [get]
+func (h *OpenAPIV2) drainCaptureReady(c *gin.Context) {
+	resp, err := h.queryDrainCapture(c, scheduler.DrainQueryProgress)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusServiceUnavailable, err)
+		return
+	}
+	// A capture which is not drained is not ready even if it has no table,
+	// otherwise tables can be scheduled to it after it is ready.
+	if !resp.Draining || resp.CurrentTableCount != 0 {
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *OpenAPIV2) queryDrainCapture(
+	c *gin.Context, tp scheduler.DrainQueryType,
+) (*model.DrainCaptureResp, error) {
+	return api.HandleOwnerDrainCapture(c.Request.Context(), h.capture, scheduler.Query{
+		CaptureID: c.Param(apiOpVarCaptureID),
+		Tp:        tp,
+	})
+}
+
 // listCaptures lists all captures
 // @Summary List captures
 // @Description list all captures in cdc cluster
@@ -113,6 +218,7 @@ func (h *OpenAPIV2) listCaptures(c *gin.Context) {
 				IsOwner:       isOwner,
 				AdvertiseAddr: c.AdvertiseAddr,
 				ClusterID:     etcdClient.GetClusterID(),
+				Labels:        c.Labels,
 			})
 	}
 	resp := &ListResponse[Capture]{
@@ -16,14 +16,18 @@ package v2
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
 	"github.com/pingcap/tiflow/cdc/model"
 	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/errors"
 	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
 	"github.com/stretchr/testify/require"
@@ -141,3 +145,114 @@ func TestListCaptures(t *testing.T) {
 		}
 	}
 }
+
+func TestDrainCaptureProgress(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cp := mock_capture.NewMockCapture(ctrl)
+	owner := mock_owner.NewMockOwner(ctrl)
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+
+	remaining := 1
+	draining := true
+	var queryTypes []scheduler.DrainQueryType
+	owner.EXPECT().DrainCapture(gomock.Any(), gomock.Any()).
+		Do(func(query *scheduler.Query, done chan<- error) {
+			require.Equal(t, "capture-id", query.CaptureID)
+			queryTypes = append(queryTypes, query.Tp)
+			query.Resp = &model.DrainCaptureResp{
+				CurrentTableCount: remaining,
+				Draining:          draining,
+			}
+			close(done)
+		}).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	request := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), method, url, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("GET", "/api/v2/captures/capture-id/drain")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := &model.DrainCaptureResp{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(resp))
+	require.Equal(t, 1, resp.CurrentTableCount)
+
+	// Not ready until all tables are moved out.
+	w = request("GET", "/api/v2/captures/capture-id/drain/ready")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	remaining = 0
+	w = request("GET", "/api/v2/captures/capture-id/drain/ready")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = request("DELETE", "/api/v2/captures/capture-id/drain")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []scheduler.DrainQueryType{
+		scheduler.DrainQueryProgress, scheduler.DrainQueryProgress,
+		scheduler.DrainQueryProgress, scheduler.DrainQueryCancel,
+	}, queryTypes)
+
+	// Not ready if the capture is not drained, even if it has no table.
+	draining = false
+	w = request("GET", "/api/v2/captures/capture-id/drain/ready")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestDrainCaptureBody(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cp := mock_capture.NewMockCapture(ctrl)
+	owner := mock_owner.NewMockOwner(ctrl)
+	statusProvider := mock_owner.NewMockStatusProvider(ctrl)
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: "owner-id"}, nil).AnyTimes()
+	statusProvider.EXPECT().GetCaptures(gomock.Any()).Return([]*model.CaptureInfo{
+		{ID: "owner-id"}, {ID: "capture-id"},
+	}, nil).AnyTimes()
+
+	var deadlines []time.Time
+	owner.EXPECT().DrainCapture(gomock.Any(), gomock.Any()).
+		Do(func(query *scheduler.Query, done chan<- error) {
+			deadlines = append(deadlines, query.Deadline)
+			query.Resp = &model.DrainCaptureResp{}
+			close(done)
+		}).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	request := func(body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(),
+			"POST", "/api/v2/captures/capture-id/drain", body)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The body is optional.
+	w := request(nil)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	// The content length of a chunked body is unknown.
+	w = request(io.NopCloser(strings.NewReader("")))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	w = request(io.NopCloser(strings.NewReader(`{"timeout": 60}`)))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, deadlines, 3)
+	require.True(t, deadlines[0].IsZero())
+	require.True(t, deadlines[1].IsZero())
+	require.False(t, deadlines[2].IsZero())
+
+	w = request(io.NopCloser(strings.NewReader(`{"timeout": `)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, deadlines, 3)
+}
@@ -14,7 +14,9 @@
 package v2
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"sort"
 	"strings"
@@ -23,9 +25,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/validator"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/security"
@@ -42,8 +47,23 @@ const (
 	apiOpVarChangefeedState = "state"
 	// apiOpVarChangefeedID is the key of changefeed ID in HTTP API
 	apiOpVarChangefeedID = "changefeed_id"
+	// apiOpVarNamespace is the key of changefeed namespace in HTTP API
+	apiOpVarNamespace = "namespace"
 )
 
+// getChangefeedID returns the changefeed ID of the request, the namespace is
+// taken from the query parameter and defaults to the default namespace.
+func getChangefeedID(c *gin.Context) model.ChangeFeedID {
+	namespace := c.Query(apiOpVarNamespace)
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	return model.ChangeFeedID{
+		Namespace: namespace,
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+}
+
 // createChangefeed handles create changefeed request,
 // it returns the changefeed's changefeedInfo that it just created
 // CreateChangefeed creates a changefeed
@@ -112,13 +132,18 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 			ctx,
 			pdClient,
 			h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating),
-			model.DefaultChangeFeedID(cfg.ID),
+			model.ChangeFeedID{Namespace: info.Namespace, ID: info.ID},
 		)
 		if err != nil {
 			_ = c.Error(err)
 			return
 		}
 	}()
+	if err := h.checkNamespaceQuota(ctx, info); err != nil {
+		needRemoveGCSafePoint = true
+		_ = c.Error(err)
+		return
+	}
 	upstreamInfo := &model.UpstreamInfo{
 		ID:            info.UpstreamID,
 		PDEndpoints:   strings.Join(cfg.PDAddrs, ","),
@@ -159,7 +184,7 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	err = h.capture.GetEtcdClient().CreateChangefeedInfo(ctx,
 		upstreamInfo,
 		info,
-		model.DefaultChangeFeedID(info.ID))
+		model.ChangeFeedID{Namespace: info.Namespace, ID: info.ID})
 	if err != nil {
 		needRemoveGCSafePoint = true
 		_ = c.Error(err)
@@ -216,12 +241,15 @@ func hasRunningImport(ctx context.Context, cli *clientv3.Client) error {
 // @Accept json
 // @Produce json
 // @Param state query string false "state"
+// @Param namespace query string false "changefeed namespace"
 // @Success 200 {array} ChangefeedCommonInfo
 // @Failure 500 {object} model.HTTPError
 // @Router /api/v2/changefeeds [get]
 func (h *OpenAPIV2) listChangeFeeds(c *gin.Context) {
 	ctx := c.Request.Context()
 	state := c.Query(apiOpVarChangefeedState)
+	// changefeeds of all namespaces are listed if namespace is not given
+	namespace := c.Query(apiOpVarNamespace)
 	statuses, err := h.capture.StatusProvider().GetAllChangeFeedStatuses(ctx)
 	if err != nil {
 		_ = c.Error(err)
@@ -238,6 +266,9 @@ func (h *OpenAPIV2) listChangeFeeds(c *gin.Context) {
 	changefeeds := make([]model.ChangeFeedID, 0)
 
 	for cfID := range statuses {
+		if namespace != "" && cfID.Namespace != namespace {
+			continue
+		}
 		changefeeds = append(changefeeds, cfID)
 	}
 	sort.Slice(changefeeds, func(i, j int) bool {
@@ -255,7 +286,7 @@ func (h *OpenAPIV2) listChangeFeeds(c *gin.Context) {
 		}
 		cfStatus := statuses[cfID]
 
-		if !cfInfo.State.IsNeeded(state) {
+		if !cfInfo.DisplayState().IsNeeded(state) {
 			// if the value of `state` is not 'all', only return changefeed
 			// with state 'normal', 'stopped', 'failed'
 			continue
@@ -266,12 +297,14 @@ func (h *OpenAPIV2) listChangeFeeds(c *gin.Context) {
 			UpstreamID:   cfInfo.UpstreamID,
 			Namespace:    cfID.Namespace,
 			ID:           cfID.ID,
-			FeedState:    cfInfo.State,
+			FeedState:    cfInfo.DisplayState(),
 			RunningError: cfInfo.Error,
+			Warning:      toAPIWarning(cfInfo.Warning),
 		}
-		// if the state is normal, we shall not return the error info
+		// if the state is normal or warning, we shall not return the error info
 		// because changefeed will is retrying. errors will confuse the users
-		if commonInfo.FeedState == model.StateNormal {
+		if commonInfo.FeedState == model.StateNormal ||
+			commonInfo.FeedState == model.StateWarning {
 			commonInfo.RunningError = nil
 		}
 
@@ -344,6 +377,8 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 // Can only update a changefeed's: TargetTs, SinkURI,
 // ReplicaConfig, PDAddrs, CAPath, CertPath, KeyPath,
 // SyncPointEnabled, SyncPointInterval
+// The TargetTs and OnFinish of a running changefeed can be
+// updated without pausing it.
 // UpdateChangefeed updates a changefeed
 // @Summary Update a changefeed
 // @Description Update a changefeed
@@ -351,6 +386,7 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
 // @Param changefeedConfig body ChangefeedConfig true "changefeed config"
 // @Success 200 {object} ChangeFeedInfo
 // @Failure 500,400 {object} model.HTTPError
@@ -358,7 +394,7 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -372,7 +408,10 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 	}
 
 	switch oldCfInfo.State {
-	case model.StateStopped, model.StateFailed:
+	case model.StateStopped, model.StateFailed, model.StateFinished:
+	case model.StateNormal, model.StateError:
+		h.updateRunningChangefeed(c, changefeedID, oldCfInfo)
+		return
 	default:
 		_ = c.Error(
 			cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
@@ -453,6 +492,155 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 		cfStatus.ResolvedTs, cfStatus.CheckpointTs, nil, true))
 }
 
+// updateRunningChangefeed updates the target ts and on-finish action of a
+// running changefeed through the owner, so that the changefeed is not restarted.
+func (h *OpenAPIV2) updateRunningChangefeed(
+	c *gin.Context, changefeedID model.ChangeFeedID, oldCfInfo *model.ChangeFeedInfo,
+) {
+	ctx := c.Request.Context()
+
+	updateCfConfig := newTargetUpdateConfig(oldCfInfo)
+	if err := c.BindJSON(updateCfConfig); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	updated, err := isTargetOnlyUpdate(updateCfConfig, oldCfInfo)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !updated {
+		_ = c.Error(
+			cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+				"can only update target_ts and on_finish when the changefeed is running, " +
+					"pause it to update other configs",
+			),
+		)
+		return
+	}
+
+	targetTs, err := updateCfConfig.getTargetTs()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if targetTs != 0 {
+		currentTs, err := getUpstreamCurrentTs(h.capture, oldCfInfo.UpstreamID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		// Data before the current ts may have been written to the downstream,
+		// so the target ts of a running changefeed can only be set to a future ts.
+		if targetTs <= currentTs {
+			_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStack(
+				"can not update target_ts:%d less than the current ts:%d "+
+					"when the changefeed is running", targetTs, currentTs))
+			return
+		}
+	}
+
+	newCfInfo, err := oldCfInfo.Clone()
+	if err != nil {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(err.Error()))
+		return
+	}
+	if targetTs != 0 {
+		newCfInfo.TargetTs = targetTs
+	}
+	var onFinish *config.OnFinishConfig
+	if updateCfConfig.ReplicaConfig != nil && updateCfConfig.ReplicaConfig.OnFinish != nil {
+		onFinish = updateCfConfig.ReplicaConfig.ToInternalReplicaConfig().OnFinish
+		if err := onFinish.ValidateAndAdjust(); err != nil {
+			_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err))
+			return
+		}
+		newCfInfo.Config.OnFinish = onFinish
+		if err := validator.ValidateFinishMarker(newCfInfo.SinkURI, newCfInfo.Config); err != nil {
+			_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err))
+			return
+		}
+	}
+	if targetTs == 0 && onFinish == nil {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs("changefeed config is the same with the old one, do nothing"))
+		return
+	}
+
+	cfStatus, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// The owner refuses the target ts as well if the checkpoint passes it
+	// before the job is handled.
+	if targetTs != 0 && targetTs <= cfStatus.CheckpointTs {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStack(
+			"can not update target_ts:%d less than or equal to the checkpoint ts:%d",
+			targetTs, cfStatus.CheckpointTs))
+		return
+	}
+
+	job := model.AdminJob{
+		CfID:     changefeedID,
+		Type:     model.AdminUpdateTarget,
+		TargetTs: targetTs,
+		OnFinish: onFinish,
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	cfStatus, err = h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, toAPIModel(newCfInfo,
+		cfStatus.ResolvedTs, cfStatus.CheckpointTs, nil, true))
+}
+
+// newTargetUpdateConfig returns the config which an update request of a
+// running changefeed is decoded into. Replica configs which are not in the
+// request keep the values of the old config, so that only fields set by the
+// request are compared by isTargetOnlyUpdate. OnFinish is nil unless it's set
+// by the request.
+func newTargetUpdateConfig(oldInfo *model.ChangeFeedInfo) *ChangefeedConfig {
+	replicaConfig := ToAPIReplicaConfig(oldInfo.Config)
+	replicaConfig.OnFinish = nil
+	return &ChangefeedConfig{ReplicaConfig: replicaConfig}
+}
+
+// isTargetOnlyUpdate returns true if the update config only changes
+// the target ts or the on-finish action of the changefeed.
+func isTargetOnlyUpdate(cfg *ChangefeedConfig, oldInfo *model.ChangeFeedInfo) (bool, error) {
+	if cfg.SinkURI != "" && cfg.SinkURI != oldInfo.SinkURI {
+		return false, nil
+	}
+	if len(cfg.PDAddrs) != 0 || cfg.CAPath != "" ||
+		cfg.CertPath != "" || cfg.KeyPath != "" {
+		return false, nil
+	}
+	if cfg.ReplicaConfig == nil {
+		return true, nil
+	}
+
+	newCfg := *cfg.ReplicaConfig
+	newCfg.OnFinish = nil
+	oldCfg := ToAPIReplicaConfig(oldInfo.Config)
+	oldCfg.OnFinish = nil
+	newData, err := json.Marshal(&newCfg)
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	oldData, err := json.Marshal(oldCfg)
+	if err != nil {
+		return false, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return bytes.Equal(newData, oldData), nil
+}
+
 // getChangefeed get detailed info of a changefeed
 // @Summary Get changefeed
 // @Description get detail information of a changefeed
@@ -460,12 +648,13 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
 // @Success 200 {object} ChangeFeedInfo
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v2/changefeeds/{changefeed_id} [get]
 func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(
 			cerror.ErrAPIInvalidParam.GenWithStack(
@@ -516,6 +705,13 @@ func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
 	}
 	detail := toAPIModel(cfInfo, status.ResolvedTs,
 		status.CheckpointTs, taskStatus, true)
+	detail.SnapshotProgress = toAPISnapshotProgress(status.Snapshot)
+	if status.Backfill != nil {
+		detail.Backfill = &TableBackfill{
+			TableID:          status.Backfill.TableID,
+			SnapshotProgress: toAPISnapshotProgress(status.Backfill.Snapshot),
+		}
+	}
 	c.JSON(http.StatusOK, detail)
 }
 
@@ -527,12 +723,13 @@ func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "changefeed namespace"
 // @Success 200 {object} EmptyResponse
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v2/changefeeds/{changefeed_id} [delete]
 func (h *OpenAPIV2) deleteChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -581,12 +778,263 @@ func (h *OpenAPIV2) deleteChangefeed(c *gin.Context) {
 	c.JSON(http.StatusOK, &EmptyResponse{})
 }
 
+// listChangefeedEvents lists the history events of a changefeed
+// @Summary List changefeed events
+// @Description list the recent state changes, admin jobs, errors and
+// @Description executed DDLs of a changefeed, ordered by time
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Success 200 {array} ChangefeedEvent
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/events [get]
+func (h *OpenAPIV2) listChangefeedEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	// make sure the changefeed exists
+	_, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	history, err := h.capture.GetEtcdClient().GetChangefeedHistory(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	events := make([]ChangefeedEvent, 0, len(history.Events))
+	for _, e := range history.Events {
+		events = append(events, ChangefeedEvent{
+			Time:     e.Time,
+			Type:     string(e.Type),
+			Message:  e.Message,
+			State:    e.State,
+			Code:     e.Code,
+			CommitTs: e.CommitTs,
+		})
+	}
+	resp := &ListResponse[ChangefeedEvent]{
+		Total: len(events),
+		Items: events,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getHeldDDL returns the DDL held for approval of a changefeed
+// @Summary Get the held DDL of a changefeed
+// @Description get the DDL held for manual approval before it is executed
+// @Description to the downstream, see the ddl_approval replica config
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Success 200 {object} HeldDDL
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/held_ddl [get]
+func (h *OpenAPIV2) getHeldDDL(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if status.HeldDDL == nil {
+		_ = c.Error(cerror.ErrHeldDDLNotFound.GenWithStackByArgs(changefeedID.ID))
+		return
+	}
+	c.JSON(http.StatusOK, toAPIHeldDDL(status.HeldDDL))
+}
+
+// handleHeldDDL approves, skips or replaces the held DDL of a changefeed
+// @Summary Handle the held DDL of a changefeed
+// @Description approve, skip or replace the DDL held for manual approval,
+// @Description the replication of tables blocked by it continues afterwards
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Param handleConfig body HandleDDLConfig true "decision on the held ddl"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/held_ddl [post]
+func (h *OpenAPIV2) handleHeldDDL(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := new(HandleDDLConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	decision := &model.DDLDecision{
+		Action: model.DDLDecisionAction(cfg.Action),
+		Query:  cfg.Query,
+	}
+	if err := decision.Validate(); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if decision.Action == model.DDLDecisionReplace {
+		if _, err := parser.New().ParseOneStmt(decision.Query, "", ""); err != nil {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid query to replace the held ddl: %s", err.Error()))
+			return
+		}
+	}
+
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if status.HeldDDL == nil {
+		_ = c.Error(cerror.ErrHeldDDLNotFound.GenWithStackByArgs(changefeedID.ID))
+		return
+	}
+	if cfg.CommitTs != status.HeldDDL.CommitTs {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"the commit ts of the held ddl is %d, not %d",
+			status.HeldDDL.CommitTs, cfg.CommitTs))
+		return
+	}
+
+	job := model.AdminJob{
+		CfID:            changefeedID,
+		Type:            model.AdminHandleDDL,
+		HeldDDLCommitTs: cfg.CommitTs,
+		DDLDecision:     decision,
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// backfillTable re-syncs a table of a running changefeed
+// @Summary Backfill a table of a changefeed
+// @Description export the snapshot of a table to the sink again and replicate
+// @Description it from the snapshot ts, other tables keep being replicated
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Param backfillConfig body BackfillTableConfig true "the table to backfill"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/backfill [post]
+func (h *OpenAPIV2) backfillTable(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := new(BackfillTableConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.TableID <= 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid table_id: %d", cfg.TableID))
+		return
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if info.State != model.StateNormal {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"can not backfill a table of changefeed in %s state", info.State))
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if status.Snapshot != nil && !status.Snapshot.Finished {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"can not backfill a table before the initial snapshot is exported"))
+		return
+	}
+	if status.Backfill != nil && !status.Backfill.IsFinished() {
+		_ = c.Error(cerror.ErrBackfillInProgress.GenWithStackByArgs(
+			status.Backfill.TableID, changefeedID.ID))
+		return
+	}
+
+	job := model.AdminJob{
+		CfID:            changefeedID,
+		Type:            model.AdminBackfillTable,
+		BackfillTableID: cfg.TableID,
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+func toAPIHeldDDL(heldDDL *model.HeldDDL) *HeldDDL {
+	res := &HeldDDL{
+		CommitTs: heldDDL.CommitTs,
+		Type:     heldDDL.Type,
+		Schema:   heldDDL.Schema,
+		Table:    heldDDL.Table,
+		Query:    heldDDL.Query,
+	}
+	if heldDDL.Decision != nil {
+		res.Decision = &DDLDecision{
+			Action: string(heldDDL.Decision.Action),
+			Query:  heldDDL.Decision.Query,
+		}
+	}
+	return res
+}
+
+func toAPISnapshotProgress(progress *model.SnapshotProgress) *SnapshotProgress {
+	if progress == nil {
+		return nil
+	}
+	return &SnapshotProgress{
+		SnapshotTs:   progress.SnapshotTs,
+		DoneSpans:    progress.DoneSpans,
+		ExportedRows: progress.ExportedRows,
+		Finished:     progress.Finished,
+	}
+}
+
 // todo: remove this API
 // getChangeFeedMetaInfo returns the metaInfo of a changefeed
 func (h *OpenAPIV2) getChangeFeedMetaInfo(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -639,13 +1087,14 @@ func (h *OpenAPIV2) getChangeFeedMetaInfo(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "changefeed namespace"
 // @Param resumeConfig body ResumeChangefeedConfig true "resume config"
 // @Success 200 {object} EmptyResponse
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v2/changefeeds/{changefeed_id}/resume [post]
 func (h *OpenAPIV2) resumeChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	err := model.ValidateChangefeedID(changefeedID.ID)
 	if err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
@@ -734,13 +1183,14 @@ func (h *OpenAPIV2) resumeChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
 // @Success 200 {object} EmptyResponse
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v2/changefeeds/{changefeed_id}/pause [post]
 func (h *OpenAPIV2) pauseChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -774,9 +1224,11 @@ func toAPIModel(
 ) *ChangeFeedInfo {
 	var runningError *RunningError
 
-	// if the state is normal, we shall not return the error info
+	// if the state is normal or warning, we shall not return the error info
 	// because changefeed will is retrying. errors will confuse the users
-	if info.State != model.StateNormal && info.Error != nil {
+	state := info.DisplayState()
+	if state != model.StateNormal && state != model.StateWarning &&
+		info.Error != nil {
 		runningError = &RunningError{
 			Addr:    info.Error.Addr,
 			Code:    info.Error.Code,
@@ -803,8 +1255,9 @@ func toAPIModel(
 		TargetTs:       info.TargetTs,
 		AdminJobType:   info.AdminJobType,
 		Config:         ToAPIReplicaConfig(info.Config),
-		State:          info.State,
+		State:          state,
 		Error:          runningError,
+		Warning:        toAPIWarning(info.Warning),
 		CreatorVersion: info.CreatorVersion,
 		CheckpointTs:   checkpointTs,
 		ResolvedTs:     resolvedTs,
@@ -814,6 +1267,21 @@ func toAPIModel(
 	return apiInfoModel
 }
 
+func toAPIWarning(warning *model.ChangefeedWarning) *ChangefeedWarning {
+	if warning == nil || warning.Error == nil {
+		return nil
+	}
+	return &ChangefeedWarning{
+		RunningError: RunningError{
+			Addr:    warning.Error.Addr,
+			Code:    warning.Error.Code,
+			Message: warning.Error.Message,
+		},
+		RetryCount:    warning.RetryCount,
+		NextRetryTime: warning.NextRetryTime,
+	}
+}
+
 func getCaptureDefaultUpstream(cp capture.Capture) (*upstream.Upstream, error) {
 	upManager, err := cp.GetUpstreamManager()
 	if err != nil {
@@ -826,6 +1294,23 @@ func getCaptureDefaultUpstream(cp capture.Capture) (*upstream.Upstream, error) {
 	return up, nil
 }
 
+// getUpstreamCurrentTs returns the current ts of the given upstream.
+func getUpstreamCurrentTs(cp capture.Capture, upstreamID uint64) (uint64, error) {
+	upManager, err := cp.GetUpstreamManager()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	up, ok := upManager.Get(upstreamID)
+	if !ok {
+		return 0, cerror.ErrUpstreamNotFound.GenWithStackByArgs(upstreamID)
+	}
+	now, err := up.PDClock.CurrentTime()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return oracle.GoTimeToTS(now), nil
+}
+
 func getUpstreamPDConfig(up *upstream.Upstream) PDConfig {
 	return PDConfig{
 		PDAddrs:  up.PdEndpoints,
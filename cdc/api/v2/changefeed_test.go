@@ -18,11 +18,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	tidbkv "github.com/pingcap/tidb/kv"
@@ -169,7 +171,7 @@ func TestCreateChangefeed(t *testing.T) {
 	require.Contains(t, respErr.Code, "ErrSinkURIInvalid")
 	require.Equal(t, http.StatusBadRequest, w.Code)
 
-	// case 5:
+	// case 6:
 	helpers.EXPECT().
 		getEtcdClient(gomock.Any(), gomock.Any()).
 		Return(testEtcdCluster.RandClient(), nil)
@@ -190,10 +192,37 @@ func TestCreateChangefeed(t *testing.T) {
 			require.EqualValues(t, cfg.SinkURI, mysqlSink)
 			return &model.ChangeFeedInfo{
 				UpstreamID: 1,
+				Namespace:  model.DefaultNamespace,
 				ID:         cfg.ID,
 				SinkURI:    cfg.SinkURI,
+				Config:     config.GetDefaultReplicaConfig(),
 			}, nil
 		}).AnyTimes()
+
+	// case 5: the quota of the namespace is exceeded
+	etcdClient.EXPECT().
+		GetNamespaceInfo(gomock.Any(), model.DefaultNamespace).
+		Return(&model.NamespaceInfo{Name: model.DefaultNamespace, MaxChangefeeds: 1}, nil)
+	statusProvider.changefeedInfos = map[model.ChangeFeedID]*model.ChangeFeedInfo{
+		model.DefaultChangeFeedID("other"): {},
+	}
+	cfConfig.SinkURI = mysqlSink
+	body, err = json.Marshal(&cfConfig)
+	require.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), create.method,
+		create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	err = json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrNamespaceQuotaExceeded")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	etcdClient.EXPECT().
+		GetNamespaceInfo(gomock.Any(), model.DefaultNamespace).
+		Return(nil, cerrors.ErrNamespaceNotExists.GenWithStackByArgs(model.DefaultNamespace)).
+		AnyTimes()
+
 	etcdClient.EXPECT().
 		CreateChangefeedInfo(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(cerrors.ErrPDEtcdAPIError).Times(1)
@@ -211,7 +240,7 @@ func TestCreateChangefeed(t *testing.T) {
 	require.Contains(t, respErr.Code, "ErrPDEtcdAPIError")
 	require.Equal(t, http.StatusInternalServerError, w.Code)
 
-	// case 6: success
+	// case 7: success
 	helpers.EXPECT().
 		getEtcdClient(gomock.Any(), gomock.Any()).
 		Return(testEtcdCluster.RandClient(), nil)
@@ -318,6 +347,67 @@ func TestGetChangeFeed(t *testing.T) {
 	require.Nil(t, resp.Error)
 }
 
+func TestListChangefeedEvents(t *testing.T) {
+	t.Parallel()
+
+	events := testCase{url: "/api/v2/changefeeds/%s/events", method: "GET"}
+	statusProvider := &mockStatusProvider{}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	// case 1: changefeed not exists
+	validID := "changefeed-valid-id"
+	statusProvider.err = cerrors.ErrChangeFeedNotExists.GenWithStackByArgs(validID)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		events.method, fmt.Sprintf(events.url, validID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	respErr := model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
+
+	// case 2: success
+	statusProvider.err = nil
+	statusProvider.changefeedInfo = &model.ChangeFeedInfo{ID: validID}
+	history := &model.ChangefeedHistory{}
+	history.Append(&model.ChangefeedEvent{
+		Time:    time.Now(),
+		Type:    model.ChangefeedEventStateChanged,
+		Message: "changefeed state is changed from normal to error",
+		State:   model.StateError,
+	})
+	history.Append(&model.ChangefeedEvent{
+		Time:     time.Now(),
+		Type:     model.ChangefeedEventDDL,
+		Message:  "create table t (id int primary key)",
+		CommitTs: 100,
+	})
+	etcdClient.EXPECT().
+		GetChangefeedHistory(gomock.Any(), model.DefaultChangeFeedID(validID)).
+		Return(history, nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		events.method, fmt.Sprintf(events.url, validID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ListResponse[ChangefeedEvent]{}
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 2, resp.Total)
+	require.Equal(t, model.StateError, resp.Items[0].State)
+	require.Equal(t, string(model.ChangefeedEventDDL), resp.Items[1].Type)
+	require.Equal(t, uint64(100), resp.Items[1].CommitTs)
+}
+
 func TestUpdateChangefeed(t *testing.T) {
 	t.Parallel()
 	update := testCase{url: "/api/v2/changefeeds/%s", method: "PUT"}
@@ -356,7 +446,7 @@ func TestUpdateChangefeed(t *testing.T) {
 	require.Contains(t, respErr.Code, "ErrChangeFeedNotExists")
 	require.Equal(t, http.StatusBadRequest, w.Code)
 
-	// case 3: changefeed not stopped
+	// case 3: changefeed not stopped, only target can be updated
 	oldCfInfo := &model.ChangeFeedInfo{
 		ID:         validID,
 		State:      "normal",
@@ -366,9 +456,11 @@ func TestUpdateChangefeed(t *testing.T) {
 	}
 	statusProvider.err = nil
 	statusProvider.changefeedInfo = oldCfInfo
+	body, err := json.Marshal(&ChangefeedConfig{SinkURI: "blackhole://new"})
+	require.Nil(t, err)
 	w = httptest.NewRecorder()
 	req, _ = http.NewRequestWithContext(context.Background(), update.method,
-		fmt.Sprintf(update.url, validID), nil)
+		fmt.Sprintf(update.url, validID), bytes.NewReader(body))
 	router.ServeHTTP(w, req)
 	respErr = model.HTTPError{}
 	err = json.NewDecoder(w.Body).Decode(&respErr)
@@ -417,7 +509,7 @@ func TestUpdateChangefeed(t *testing.T) {
 		verifyUpstream(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(cerrors.ErrUpstreamMissMatch).Times(1)
 	updateCfg := &ChangefeedConfig{}
-	body, err := json.Marshal(&updateCfg)
+	body, err = json.Marshal(&updateCfg)
 	require.Nil(t, err)
 	w = httptest.NewRecorder()
 	req, _ = http.NewRequestWithContext(context.Background(), update.method,
@@ -582,6 +674,25 @@ func TestListChangeFeeds(t *testing.T) {
 	require.Equal(t, 2, resp2.Total)
 	// changefeed info must be sorted by ID
 	require.Equal(t, true, sorted(resp2.Items))
+
+	// case 3: only list changefeeds in the given namespace
+	for namespace, total := range map[string]int{
+		model.DefaultNamespace: 3,
+		"ns1":                  0,
+	} {
+		w = httptest.NewRecorder()
+		req3, _ := http.NewRequestWithContext(
+			context.Background(),
+			"GET",
+			"/api/v2/changefeeds?state=all&namespace="+namespace,
+			nil,
+		)
+		router.ServeHTTP(w, req3)
+		resp3 := ListResponse[model.ChangefeedCommonInfo]{}
+		err = json.NewDecoder(w.Body).Decode(&resp3)
+		require.Nil(t, err)
+		require.Equal(t, total, resp3.Total)
+	}
 }
 
 func TestVerifyTable(t *testing.T) {
@@ -932,6 +1043,205 @@ func TestPauseChangefeed(t *testing.T) {
 	require.Equal(t, "{}", w.Body.String())
 }
 
+func TestHeldDDL(t *testing.T) {
+	heldDDL := testCase{url: "/api/v2/changefeeds/%s/held_ddl", method: "GET"}
+	handleDDL := testCase{url: "/api/v2/changefeeds/%s/held_ddl", method: "POST"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	statusProvider := &mockStatusProvider{}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	var jobs []model.AdminJob
+	owner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(adminJob model.AdminJob, done chan<- error) {
+			jobs = append(jobs, adminJob)
+			close(done)
+		}).AnyTimes()
+
+	doRequest := func(tc testCase, body any) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.Nil(t, err)
+			reader = bytes.NewReader(data)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), tc.method,
+			fmt.Sprintf(tc.url, changeFeedID.ID), reader)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	requireErrCode := func(w *httptest.ResponseRecorder, code string) {
+		respErr := model.HTTPError{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		require.Contains(t, respErr.Code, code)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	// case 1: no ddl is held
+	statusProvider.changefeedStatus = &model.ChangeFeedStatus{CheckpointTs: 100}
+	requireErrCode(doRequest(heldDDL, nil), "ErrHeldDDLNotFound")
+	requireErrCode(doRequest(handleDDL, &HandleDDLConfig{
+		CommitTs: 100, DDLDecision: DDLDecision{Action: "approve"},
+	}), "ErrHeldDDLNotFound")
+
+	// case 2: get the held ddl
+	statusProvider.changefeedStatus.HeldDDL = &model.HeldDDL{
+		CommitTs: 100,
+		Type:     "drop table",
+		Schema:   "test",
+		Table:    "t",
+		Query:    "DROP TABLE `test`.`t`",
+	}
+	w := doRequest(heldDDL, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := &HeldDDL{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(resp))
+	require.Equal(t, toAPIHeldDDL(statusProvider.changefeedStatus.HeldDDL), resp)
+
+	// case 3: invalid decisions
+	requireErrCode(doRequest(handleDDL, &HandleDDLConfig{
+		CommitTs: 100, DDLDecision: DDLDecision{Action: "drop"},
+	}), "ErrAPIInvalidParam")
+	requireErrCode(doRequest(handleDDL, &HandleDDLConfig{
+		CommitTs: 100, DDLDecision: DDLDecision{Action: "replace"},
+	}), "ErrAPIInvalidParam")
+	requireErrCode(doRequest(handleDDL, &HandleDDLConfig{
+		CommitTs: 100, DDLDecision: DDLDecision{Action: "replace", Query: "rename t"},
+	}), "ErrAPIInvalidParam")
+	requireErrCode(doRequest(handleDDL, &HandleDDLConfig{
+		CommitTs: 99, DDLDecision: DDLDecision{Action: "approve"},
+	}), "ErrAPIInvalidParam")
+	require.Empty(t, jobs)
+
+	// case 4: success
+	w = doRequest(handleDDL, &HandleDDLConfig{
+		CommitTs: 100,
+		DDLDecision: DDLDecision{
+			Action: "replace",
+			Query:  "RENAME TABLE `test`.`t` TO `test`.`t_dropped`",
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []model.AdminJob{{
+		CfID:            changeFeedID,
+		Type:            model.AdminHandleDDL,
+		HeldDDLCommitTs: 100,
+		DDLDecision: &model.DDLDecision{
+			Action: model.DDLDecisionReplace,
+			Query:  "RENAME TABLE `test`.`t` TO `test`.`t_dropped`",
+		},
+	}}, jobs)
+}
+
+func TestBackfillTable(t *testing.T) {
+	backfill := testCase{url: "/api/v2/changefeeds/%s/backfill", method: "POST"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	statusProvider := &mockStatusProvider{}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	var jobs []model.AdminJob
+	owner.EXPECT().EnqueueJob(gomock.Any(), gomock.Any()).
+		Do(func(adminJob model.AdminJob, done chan<- error) {
+			jobs = append(jobs, adminJob)
+			close(done)
+		}).AnyTimes()
+
+	doRequest := func(cfg *BackfillTableConfig) *httptest.ResponseRecorder {
+		data, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), backfill.method,
+			fmt.Sprintf(backfill.url, changeFeedID.ID), bytes.NewReader(data))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	requireErrCode := func(w *httptest.ResponseRecorder, code string) {
+		respErr := model.HTTPError{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		require.Contains(t, respErr.Code, code)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	statusProvider.changefeedInfo = &model.ChangeFeedInfo{State: model.StateStopped}
+	statusProvider.changefeedStatus = &model.ChangeFeedStatus{
+		CheckpointTs: 100,
+		Backfill: &model.TableBackfill{
+			TableID:  1,
+			Snapshot: &model.SnapshotProgress{SnapshotTs: 90},
+		},
+	}
+
+	// case 1: invalid table id
+	requireErrCode(doRequest(&BackfillTableConfig{}), "ErrAPIInvalidParam")
+
+	// case 2: the changefeed is not running
+	requireErrCode(doRequest(&BackfillTableConfig{TableID: 2}), "ErrAPIInvalidParam")
+
+	// case 3: another table is being backfilled
+	statusProvider.changefeedInfo.State = model.StateNormal
+	requireErrCode(doRequest(&BackfillTableConfig{TableID: 2}), "ErrBackfillInProgress")
+	require.Empty(t, jobs)
+
+	// case 4: success
+	statusProvider.changefeedStatus.Backfill.Snapshot.Finished = true
+	w := doRequest(&BackfillTableConfig{TableID: 2})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []model.AdminJob{{
+		CfID:            changeFeedID,
+		Type:            model.AdminBackfillTable,
+		BackfillTableID: 2,
+	}}, jobs)
+}
+
+func TestIsTargetOnlyUpdate(t *testing.T) {
+	t.Parallel()
+
+	oldInfo := &model.ChangeFeedInfo{
+		SinkURI: "blackhole://",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	for _, tc := range []struct {
+		body     string
+		expected bool
+	}{
+		{body: `{"target_ts": 10}`, expected: true},
+		{body: `{"replica_config": {"on_finish": {"action": "remove"}}}`, expected: true},
+		{body: `{"sink_uri": "blackhole://", "replica_config": null}`, expected: true},
+		{body: `{"sink_uri": "blackhole://new"}`, expected: false},
+		{body: `{"replica_config": {"memory_quota": 1}}`, expected: false},
+	} {
+		cfg := newTargetUpdateConfig(oldInfo)
+		require.NoError(t, json.Unmarshal([]byte(tc.body), cfg))
+		updated, err := isTargetOnlyUpdate(cfg, oldInfo)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, updated, tc.body)
+	}
+
+	// The full config with a changed on-finish action is a target only update.
+	cfg := newTargetUpdateConfig(oldInfo)
+	body, err := json.Marshal(&ChangefeedConfig{ReplicaConfig: ToAPIReplicaConfig(oldInfo.Config)})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(body, cfg))
+	cfg.ReplicaConfig.OnFinish = &OnFinishConfig{Action: "remove"}
+	updated, err := isTargetOnlyUpdate(cfg, oldInfo)
+	require.NoError(t, err)
+	require.True(t, updated)
+}
+
 func TestHasRunningImport(t *testing.T) {
 	integration.BeforeTestExternal(t)
 	testEtcdCluster := integration.NewClusterV3(
@@ -72,6 +72,10 @@ var defaultAPIConfig = &ReplicaConfig{
 		IntegrityCheckLevel:   config.GetDefaultReplicaConfig().Integrity.IntegrityCheckLevel,
 		CorruptionHandleLevel: config.GetDefaultReplicaConfig().Integrity.CorruptionHandleLevel,
 	},
+	OnFinish: &OnFinishConfig{
+		Action:      config.GetDefaultReplicaConfig().OnFinish.Action,
+		WriteMarker: config.GetDefaultReplicaConfig().OnFinish.WriteMarker,
+	},
 }
 
 func TestDefaultReplicaConfig(t *testing.T) {
@@ -80,6 +84,7 @@ func TestDefaultReplicaConfig(t *testing.T) {
 	cfg := GetDefaultReplicaConfig()
 	require.NotNil(t, cfg.Scheduler)
 	require.NotNil(t, cfg.Integrity)
+	require.NotNil(t, cfg.OnFinish)
 	cfg2 := cfg.toInternalReplicaConfigWithOriginConfig(&config.ReplicaConfig{})
 	require.Equal(t, config.GetDefaultReplicaConfig(), cfg2)
 	cfg3 := ToAPIReplicaConfig(config.GetDefaultReplicaConfig())
@@ -146,10 +151,22 @@ func TestToAPIReplicaConfig(t *testing.T) {
 	cfg.Scheduler = &config.ChangefeedSchedulerConfig{
 		EnableTableAcrossNodes: true, RegionThreshold: 10001, WriteKeyThreshold: 10001,
 	}
+	cfg.DDLApproval = &config.DDLApprovalConfig{
+		DDLTypes: []string{"drop table", "truncate table"},
+	}
+	cfg.BDRConflict = &config.BDRConflictConfig{
+		Policy:             config.BDRConflictPolicySourcePriority,
+		VersionColumn:      "version",
+		DownstreamSourceID: 2,
+		SourcePriority:     []uint64{1, 2},
+		ConflictTable:      config.DefaultBDRConflictTable,
+	}
 	cfg2 := ToAPIReplicaConfig(cfg).ToInternalReplicaConfig()
 	require.Equal(t, "", cfg2.Sink.DispatchRules[0].DispatcherRule)
 	cfg.Sink.DispatchRules[0].DispatcherRule = ""
 	require.Equal(t, cfg, cfg2)
+	// bdr conflict policies are kebab-case values rather than keys.
+	cfg.BDRConflict.Policy = ""
 	cfgJSON, err := json.Marshal(ToAPIReplicaConfig(cfg))
 	require.Nil(t, err)
 	require.False(t, strings.Contains(string(cfgJSON), "-"))
@@ -0,0 +1,227 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	"github.com/pingcap/tiflow/pkg/version"
+	"go.uber.org/zap"
+)
+
+// listNamespaces lists all namespaces which have quotas
+// @Summary List namespaces
+// @Description list all namespaces which have quotas and their changefeeds
+// @Tags namespace,v2
+// @Produce json
+// @Success 200 {object} ListResponse[NamespaceInfo]
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/namespaces [get]
+func (h *OpenAPIV2) listNamespaces(c *gin.Context) {
+	ctx := c.Request.Context()
+	infos, err := h.capture.GetEtcdClient().GetNamespaceInfos(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	changefeeds, err := h.getNamespaceChangefeeds(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := &ListResponse[NamespaceInfo]{}
+	for name, info := range infos {
+		item := toAPINamespaceInfo(info)
+		item.Changefeeds = changefeeds[name]
+		resp.Items = append(resp.Items, *item)
+	}
+	sort.Slice(resp.Items, func(i, j int) bool {
+		return resp.Items[i].Name < resp.Items[j].Name
+	})
+	resp.Total = len(resp.Items)
+	c.JSON(http.StatusOK, resp)
+}
+
+// getNamespace gets the quotas of a namespace
+// @Summary Get a namespace
+// @Description get the quotas of a namespace and its changefeeds
+// @Tags namespace,v2
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Success 200 {object} NamespaceInfo
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/namespaces/{namespace} [get]
+func (h *OpenAPIV2) getNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	info, err := h.capture.GetEtcdClient().
+		GetNamespaceInfo(ctx, c.Param(apiOpVarNamespace))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	changefeeds, err := h.getNamespaceChangefeeds(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := toAPINamespaceInfo(info)
+	resp.Changefeeds = changefeeds[info.Name]
+	c.JSON(http.StatusOK, resp)
+}
+
+// putNamespace creates or updates the quotas of a namespace
+// @Summary Create or update a namespace
+// @Description create or update the quotas of a namespace, the new quotas
+// @Description take effect when changefeeds in the namespace are started
+// @Tags namespace,v2
+// @Accept json
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Param namespaceInfo body NamespaceInfo true "namespace quotas"
+// @Success 200 {object} NamespaceInfo
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/namespaces/{namespace} [put]
+func (h *OpenAPIV2) putNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	cfg := &NamespaceInfo{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	name := c.Param(apiOpVarNamespace)
+	if cfg.Name != "" && cfg.Name != name {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"namespace name %s does not match the path %s", cfg.Name, name))
+		return
+	}
+	cfg.Name = name
+
+	info := cfg.toInternalNamespaceInfo()
+	if err := info.Validate(); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	etcdClient := h.capture.GetEtcdClient()
+	if err := checkNamespaceInfoSupported(ctx, etcdClient); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := etcdClient.PutNamespaceInfo(ctx, info); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("namespace is updated", zap.Any("namespace", info))
+	c.JSON(http.StatusOK, toAPINamespaceInfo(info))
+}
+
+// deleteNamespace deletes the quotas of a namespace
+// @Summary Delete a namespace
+// @Description delete the quotas of a namespace, changefeeds in the
+// @Description namespace are not limited anymore
+// @Tags namespace,v2
+// @Produce json
+// @Param namespace path string true "namespace"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/namespaces/{namespace} [delete]
+func (h *OpenAPIV2) deleteNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param(apiOpVarNamespace)
+	etcdClient := h.capture.GetEtcdClient()
+	if _, err := etcdClient.GetNamespaceInfo(ctx, name); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := checkNamespaceInfoSupported(ctx, etcdClient); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := etcdClient.DeleteNamespaceInfo(ctx, name); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("namespace is deleted", zap.String("namespace", name))
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// checkNamespaceInfoSupported returns an error if some captures can not
+// recognize the namespace key in etcd, they would fail to parse the key.
+func checkNamespaceInfoSupported(ctx context.Context, etcdClient etcd.CDCEtcdClient) error {
+	_, captures, err := etcdClient.GetCaptures(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	clusterVersion, err := version.GetTiCDCClusterVersion(
+		model.ListVersionsFromCaptureInfos(captures))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !clusterVersion.ShouldStoreNamespaceInfo() {
+		return cerror.ErrVersionIncompatible.GenWithStackByArgs(
+			"namespaces can not be managed until all captures are upgraded")
+	}
+	return nil
+}
+
+// getNamespaceChangefeeds returns the IDs of changefeeds grouped by namespace.
+func (h *OpenAPIV2) getNamespaceChangefeeds(ctx context.Context) (
+	map[string][]string, error,
+) {
+	infos, err := h.capture.StatusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changefeeds := make(map[string][]string)
+	for id := range infos {
+		changefeeds[id.Namespace] = append(changefeeds[id.Namespace], id.ID)
+	}
+	for _, ids := range changefeeds {
+		sort.Strings(ids)
+	}
+	return changefeeds, nil
+}
+
+// checkNamespaceQuota checks whether a new changefeed can be created under
+// the quota of its namespace.
+func (h *OpenAPIV2) checkNamespaceQuota(
+	ctx context.Context, info *model.ChangeFeedInfo,
+) error {
+	ns, err := h.capture.GetEtcdClient().GetNamespaceInfo(ctx, info.Namespace)
+	if err != nil {
+		if cerror.ErrNamespaceNotExists.Equal(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	infos, err := h.capture.StatusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var others []*model.ChangeFeedInfo
+	for id, other := range infos {
+		if id.Namespace == info.Namespace && id.ID != info.ID {
+			others = append(others, other)
+		}
+	}
+	return ns.CheckChangefeed(info, others)
+}
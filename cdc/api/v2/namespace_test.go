@@ -0,0 +1,186 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAndGetNamespaces(t *testing.T) {
+	t.Parallel()
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	statusProvider := &mockStatusProvider{}
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	statusProvider.changefeedInfos = map[model.ChangeFeedID]*model.ChangeFeedInfo{
+		{Namespace: "team-a", ID: "cf-b"}: {},
+		{Namespace: "team-a", ID: "cf-a"}: {},
+		model.DefaultChangeFeedID("cf-c"): {},
+	}
+
+	// list namespaces
+	etcdClient.EXPECT().GetNamespaceInfos(gomock.Any()).
+		Return(map[string]*model.NamespaceInfo{
+			"team-b": {Name: "team-b", AllowedSinkSchemes: []string{"kafka"}},
+			"team-a": {Name: "team-a", MaxDeclaredMemoryQuota: 1024, MaxChangefeeds: 2},
+		}, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		"GET", "/api/v2/namespaces", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ListResponse[NamespaceInfo]{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, 2, resp.Total)
+	require.Equal(t, "team-a", resp.Items[0].Name)
+	require.Equal(t, uint64(1024), resp.Items[0].MaxDeclaredMemoryQuota)
+	require.Equal(t, []string{"cf-a", "cf-b"}, resp.Items[0].Changefeeds)
+	require.Equal(t, "team-b", resp.Items[1].Name)
+	require.Empty(t, resp.Items[1].Changefeeds)
+
+	// get a namespace
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "team-a").
+		Return(&model.NamespaceInfo{Name: "team-a", MaxChangefeeds: 2}, nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		"GET", "/api/v2/namespaces/team-a", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	info := NamespaceInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&info))
+	require.Equal(t, 2, info.MaxChangefeeds)
+	require.Equal(t, []string{"cf-a", "cf-b"}, info.Changefeeds)
+
+	// get a namespace which does not exist
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "team-c").
+		Return(nil, cerror.ErrNamespaceNotExists.GenWithStackByArgs("team-c"))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		"GET", "/api/v2/namespaces/team-c", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrNamespaceNotExists")
+}
+
+func TestPutAndDeleteNamespace(t *testing.T) {
+	t.Parallel()
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	doRequest := func(method, name string, info *NamespaceInfo) *httptest.ResponseRecorder {
+		var body []byte
+		if info != nil {
+			var err error
+			body, err = json.Marshal(info)
+			require.Nil(t, err)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), method,
+			fmt.Sprintf("/api/v2/namespaces/%s", name), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	requireErrCode := func(w *httptest.ResponseRecorder, code string) {
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		respErr := model.HTTPError{}
+		require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		require.Contains(t, respErr.Code, code)
+	}
+
+	// case 1: the name mismatches with the path
+	w := doRequest("PUT", "team-a", &NamespaceInfo{Name: "team-b"})
+	requireErrCode(w, "ErrAPIInvalidParam")
+
+	// case 2: invalid namespace name
+	w = doRequest("PUT", "team_a", &NamespaceInfo{})
+	requireErrCode(w, "ErrInvalidNamespace")
+
+	// case 3: invalid quotas
+	w = doRequest("PUT", "team-a", &NamespaceInfo{MaxChangefeeds: -1})
+	requireErrCode(w, "ErrInvalidNamespaceInfo")
+
+	// case 4: some captures can not recognize the namespace key
+	etcdClient.EXPECT().GetCaptures(gomock.Any()).Return(int64(0), []*model.CaptureInfo{
+		{ID: "capture-1", Version: "v7.2.0"},
+		{ID: "capture-2", Version: "v7.1.0"},
+	}, nil)
+	w = doRequest("PUT", "team-a", &NamespaceInfo{MaxDeclaredMemoryQuota: 1024})
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrVersionIncompatible")
+
+	// case 5: success
+	etcdClient.EXPECT().GetCaptures(gomock.Any()).Return(int64(0), []*model.CaptureInfo{
+		{ID: "capture-1", Version: "v7.2.0"},
+	}, nil).Times(2)
+	etcdClient.EXPECT().PutNamespaceInfo(gomock.Any(), &model.NamespaceInfo{
+		Name:                   "team-a",
+		MaxDeclaredMemoryQuota: 1024,
+		AllowedSinkSchemes:     []string{"kafka"},
+		CaptureLabels:          map[string]string{"zone": "z1"},
+	}).Return(nil)
+	w = doRequest("PUT", "team-a", &NamespaceInfo{
+		MaxDeclaredMemoryQuota: 1024,
+		AllowedSinkSchemes:     []string{"Kafka"},
+		CaptureLabels:          map[string]string{"zone": "z1"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	info := NamespaceInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&info))
+	require.Equal(t, "team-a", info.Name)
+	require.Equal(t, []string{"kafka"}, info.AllowedSinkSchemes)
+
+	// case 6: delete a namespace which does not exist
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "team-b").
+		Return(nil, cerror.ErrNamespaceNotExists.GenWithStackByArgs("team-b"))
+	w = doRequest("DELETE", "team-b", nil)
+	requireErrCode(w, "ErrNamespaceNotExists")
+
+	// case 7: delete a namespace
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "team-a").
+		Return(&model.NamespaceInfo{Name: "team-a"}, nil)
+	etcdClient.EXPECT().DeleteNamespaceInfo(gomock.Any(), "team-a").Return(nil)
+	w = doRequest("DELETE", "team-a", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+}
@@ -30,11 +30,12 @@ import (
 // @Success 200 {object} ProcessorDetail
 // @Failure 500,400 {object} model.HTTPError
 // @Param   changefeed_id   path    string  true  "changefeed ID"
+// @Param   namespace   query    string  false  "changefeed namespace"
 // @Param   capture_id   path    string  true  "capture ID"
 // @Router	/api/v2/processors/{changefeed_id}/{capture_id} [get]
 func (h *OpenAPIV2) getProcessor(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(
 			cerror.ErrAPIInvalidParam.GenWithStack(
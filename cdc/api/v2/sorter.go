@@ -0,0 +1,52 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getSorterDiskUsage gets the disk usage of the sorter of a TiCDC node
+// @Summary Get the disk usage of the sorter of a TiCDC node
+// @Description Get bytes of events stored by changefeeds and tables in the
+// sorter of the TiCDC node which receives the request.
+// @Tags common,v2
+// @Produce json
+// @Success 200 {object} SorterDiskUsage
+// @Failure 500,400 {object} model.HTTPError
+// @Router	/api/v2/sorter/disk_usage [get]
+func (h *OpenAPIV2) getSorterDiskUsage(c *gin.Context) {
+	resp := SorterDiskUsage{Changefeeds: []ChangefeedSorterDiskUsage{}}
+	for _, usage := range h.capture.GetSorterDiskUsage() {
+		changefeed := ChangefeedSorterDiskUsage{
+			Namespace: usage.ChangefeedID.Namespace,
+			ID:        usage.ChangefeedID.ID,
+			Bytes:     usage.Bytes,
+			Quota:     usage.Quota,
+			Tables:    make([]TableSorterDiskUsage, 0, len(usage.Tables)),
+		}
+		for tableID, bytes := range usage.Tables {
+			changefeed.Tables = append(changefeed.Tables,
+				TableSorterDiskUsage{TableID: tableID, Bytes: bytes})
+		}
+		sort.Slice(changefeed.Tables, func(i, j int) bool {
+			return changefeed.Tables[i].TableID < changefeed.Tables[j].TableID
+		})
+		resp.Changefeeds = append(resp.Changefeeds, changefeed)
+	}
+	c.JSON(http.StatusOK, &resp)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/factory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSorterDiskUsage(t *testing.T) {
+	usage := testCase{url: "/api/v2/sorter/disk_usage", method: "GET"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().GetSorterDiskUsage().Return([]factory.ChangefeedDiskUsage{{
+		ChangefeedID: model.DefaultChangeFeedID("test"),
+		Bytes:        30,
+		Quota:        100,
+		Tables:       map[model.TableID]int64{2: 20, 1: 10},
+	}})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), usage.method, usage.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := SorterDiskUsage{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, []ChangefeedSorterDiskUsage{{
+		Namespace: model.DefaultNamespace,
+		ID:        "test",
+		Bytes:     30,
+		Quota:     100,
+		Tables:    []TableSorterDiskUsage{{TableID: 1, Bytes: 10}, {TableID: 2, Bytes: 20}},
+	}}, resp.Changefeeds)
+}
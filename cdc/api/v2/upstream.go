@@ -0,0 +1,215 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"go.uber.org/zap"
+)
+
+const apiOpVarUpstreamID = "upstream_id"
+
+// listUpstreams lists all upstreams of the TiCDC cluster
+// @Summary List upstreams
+// @Description list all upstream TiDB clusters of all namespaces and the changefeeds using them
+// @Tags upstream,v2
+// @Produce json
+// @Success 200 {object} ListResponse[UpstreamStatus]
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/upstreams [get]
+func (h *OpenAPIV2) listUpstreams(c *gin.Context) {
+	ctx := c.Request.Context()
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infos, err := h.capture.GetEtcdClient().GetUpstreamInfos(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	changefeeds, err := h.getUpstreamChangefeeds(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	statuses := make(map[uint64]*UpstreamStatus, len(infos)+1)
+	if up, err := upManager.GetDefaultUpstream(); err == nil {
+		status := &UpstreamStatus{
+			ID:        up.ID,
+			PDConfig:  getUpstreamPDConfig(up),
+			IsDefault: true,
+		}
+		if up.GCManager != nil {
+			status.GCSafepoint = up.GCManager.LastSafePointTs()
+		}
+		statuses[up.ID] = status
+	}
+	for id, info := range infos {
+		if _, ok := statuses[id]; ok {
+			continue
+		}
+		status := &UpstreamStatus{
+			ID: id,
+			PDConfig: PDConfig{
+				PDAddrs:       strings.Split(info.PDEndpoints, ","),
+				CAPath:        info.CAPath,
+				CertPath:      info.CertPath,
+				KeyPath:       info.KeyPath,
+				CertAllowedCN: info.CertAllowedCN,
+			},
+		}
+		if up, ok := upManager.Get(id); ok && up.IsNormal() {
+			status.GCSafepoint = up.GCManager.LastSafePointTs()
+		}
+		statuses[id] = status
+	}
+
+	resp := &ListResponse[UpstreamStatus]{}
+	for id, status := range statuses {
+		status.Changefeeds = changefeeds[id]
+		status.IsIdle = len(status.Changefeeds) == 0
+		resp.Items = append(resp.Items, *status)
+	}
+	sort.Slice(resp.Items, func(i, j int) bool {
+		return resp.Items[i].ID < resp.Items[j].ID
+	})
+	resp.Total = len(resp.Items)
+	c.JSON(http.StatusOK, resp)
+}
+
+// removeUpstream removes an idle upstream from the TiCDC cluster
+// @Summary Remove an upstream
+// @Description remove an upstream which is not used by any changefeed of any namespace,
+// @Description the TiCDC service GC safepoint of the upstream is removed as well
+// @Tags upstream,v2
+// @Produce json
+// @Param upstream_id path string true "upstream_id"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/upstreams/{upstream_id} [delete]
+func (h *OpenAPIV2) removeUpstream(c *gin.Context) {
+	ctx := c.Request.Context()
+	upstreamID, err := strconv.ParseUint(c.Param(apiOpVarUpstreamID), 10, 64)
+	if err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid upstream_id: %s", c.Param(apiOpVarUpstreamID)))
+		return
+	}
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if up, err := upManager.GetDefaultUpstream(); err == nil && up.ID == upstreamID {
+		_ = c.Error(cerror.ErrUpstreamIsDefault.GenWithStackByArgs(upstreamID))
+		return
+	}
+
+	etcdClient := h.capture.GetEtcdClient()
+	infos, err := etcdClient.GetUpstreamInfos(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	info, ok := infos[upstreamID]
+	if !ok {
+		_ = c.Error(cerror.ErrUpstreamNotFound.GenWithStackByArgs(upstreamID))
+		return
+	}
+	// The GC safepoint and the upstream are shared by all namespaces, so the
+	// upstream info is deleted from all namespaces only if no changefeed of
+	// any namespace uses it, and then upstream managers of all captures close
+	// the upstream when they tick.
+	if err := etcdClient.DeleteUpstreamInfo(ctx, upstreamID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := h.removeUpstreamGCSafepoint(ctx, upstreamID, info); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if err := upManager.Remove(upstreamID); err != nil &&
+		!cerror.ErrUpstreamNotFound.Equal(err) {
+		_ = c.Error(err)
+		return
+	}
+	log.Info("upstream is removed", zap.Uint64("upstreamID", upstreamID),
+		zap.String("pd", info.PDEndpoints))
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
+
+// getUpstreamChangefeeds returns the IDs of changefeeds grouped by upstream ID.
+func (h *OpenAPIV2) getUpstreamChangefeeds(ctx context.Context) (
+	map[uint64][]string, error,
+) {
+	infos, err := h.capture.StatusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changefeeds := make(map[uint64][]string)
+	for id, info := range infos {
+		changefeeds[info.UpstreamID] = append(changefeeds[info.UpstreamID], id.ID)
+	}
+	for _, ids := range changefeeds {
+		sort.Strings(ids)
+	}
+	return changefeeds, nil
+}
+
+// removeUpstreamGCSafepoint removes the TiCDC service GC safepoint from
+// the given upstream.
+func (h *OpenAPIV2) removeUpstreamGCSafepoint(ctx context.Context,
+	upstreamID uint64, info *model.UpstreamInfo,
+) error {
+	gcServiceID := h.capture.GetEtcdClient().GetGCServiceID()
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if up, ok := upManager.Get(upstreamID); ok && up.IsNormal() {
+		return gc.RemoveServiceGCSafepoint(ctx, up.PDClient, gcServiceID)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	pdClient, err := h.helpers.getPDClient(timeoutCtx,
+		strings.Split(info.PDEndpoints, ","),
+		&security.Credential{
+			CAPath:        info.CAPath,
+			CertPath:      info.CertPath,
+			KeyPath:       info.KeyPath,
+			CertAllowedCN: info.CertAllowedCN,
+		})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err)
+	}
+	defer pdClient.Close()
+	return gc.RemoveServiceGCSafepoint(ctx, pdClient, gcServiceID)
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/etcd"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUpstreams(t *testing.T) {
+	t.Parallel()
+
+	list := testCase{url: "/api/v2/upstreams", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	statusProvider := &mockStatusProvider{}
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&mockPDClient{}), nil).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+
+	etcdClient.EXPECT().GetUpstreamInfos(gomock.Any()).
+		Return(map[model.UpstreamID]*model.UpstreamInfo{
+			0: {ID: 0, PDEndpoints: "http://127.0.0.1:2379"},
+			1: {ID: 1, PDEndpoints: "http://127.0.0.2:2379,http://127.0.0.3:2379"},
+			2: {ID: 2, PDEndpoints: "http://127.0.0.4:2379", CAPath: "ca.pem"},
+		}, nil)
+	statusProvider.changefeedInfos = map[model.ChangeFeedID]*model.ChangeFeedInfo{
+		model.DefaultChangeFeedID("cf-b"): {UpstreamID: 1},
+		model.DefaultChangeFeedID("cf-a"): {UpstreamID: 1},
+		model.DefaultChangeFeedID("cf-c"): {UpstreamID: 0},
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		list.method, list.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ListResponse[UpstreamStatus]{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 3, resp.Total)
+
+	require.True(t, resp.Items[0].IsDefault)
+	require.Equal(t, []string{"cf-c"}, resp.Items[0].Changefeeds)
+
+	require.Equal(t, uint64(1), resp.Items[1].ID)
+	require.False(t, resp.Items[1].IsDefault)
+	require.False(t, resp.Items[1].IsIdle)
+	require.Equal(t, []string{"cf-a", "cf-b"}, resp.Items[1].Changefeeds)
+	require.Equal(t,
+		[]string{"http://127.0.0.2:2379", "http://127.0.0.3:2379"},
+		resp.Items[1].PDAddrs)
+
+	require.Equal(t, uint64(2), resp.Items[2].ID)
+	require.True(t, resp.Items[2].IsIdle)
+	require.Equal(t, "ca.pem", resp.Items[2].CAPath)
+}
+
+func TestRemoveUpstream(t *testing.T) {
+	t.Parallel()
+
+	remove := testCase{url: "/api/v2/upstreams/%s", method: "DELETE"}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	statusProvider := &mockStatusProvider{}
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&mockPDClient{}), nil).AnyTimes()
+	etcdClient.EXPECT().GetGCServiceID().
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	doRequest := func(id string) (int, model.HTTPError) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(),
+			remove.method, fmt.Sprintf(remove.url, id), nil)
+		router.ServeHTTP(w, req)
+		respErr := model.HTTPError{}
+		if w.Code != http.StatusOK {
+			require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		}
+		return w.Code, respErr
+	}
+
+	// case 1: invalid upstream id
+	code, respErr := doRequest("abc")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: the default upstream can not be removed
+	code, respErr = doRequest("0")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Code, "ErrUpstreamIsDefault")
+
+	infos := map[uint64]*model.UpstreamInfo{
+		1: {ID: 1, PDEndpoints: "http://127.0.0.2:2379"},
+		2: {ID: 2, PDEndpoints: "http://127.0.0.4:2379"},
+	}
+	etcdClient.EXPECT().GetUpstreamInfos(gomock.Any()).Return(infos, nil).AnyTimes()
+
+	// case 3: the upstream is used by a changefeed of any namespace
+	etcdClient.EXPECT().DeleteUpstreamInfo(gomock.Any(), uint64(1)).
+		Return(cerror.ErrUpstreamInUse.GenWithStackByArgs(1, []string{"team-a/cf-a"}))
+	code, respErr = doRequest("1")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Code, "ErrUpstreamInUse")
+	require.Contains(t, respErr.Error, "team-a/cf-a")
+
+	// case 4: success
+	helpers.EXPECT().getPDClient(gomock.Any(),
+		[]string{"http://127.0.0.4:2379"}, gomock.Any()).
+		Return(&mockPDClient{}, nil)
+	etcdClient.EXPECT().DeleteUpstreamInfo(gomock.Any(), uint64(2)).Return(nil)
+	code, _ = doRequest("2")
+	require.Equal(t, http.StatusOK, code)
+
+	// case 5: the upstream does not exist in any namespace
+	code, respErr = doRequest("3")
+	require.NotEqual(t, http.StatusOK, code)
+	require.Contains(t, respErr.Code, "ErrUpstreamNotFound")
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/verification"
+)
+
+// startVerification starts a consistency check of a changefeed
+// @Summary Start a consistency check of a changefeed
+// @Description compare checksums of the upstream at the primary ts and the
+// @Description downstream at the secondary ts of a syncpoint chunk by chunk,
+// @Description only changefeeds with a MySQL-compatible sink and syncpoint
+// @Description enabled can be verified
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Param verifyConfig body VerifyConfig true "verify config"
+// @Success 200 {object} verification.Status
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/verification [post]
+func (h *OpenAPIV2) startVerification(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := new(VerifyConfig)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	upstreamURI, err := url.Parse(cfg.UpstreamURI)
+	if err != nil || !sink.IsMySQLCompatibleScheme(upstreamURI.Scheme) {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"upstream uri must be a MySQL-compatible uri of the upstream TiDB"))
+		return
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	sinkURI, err := url.Parse(info.SinkURI)
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrSinkURIInvalid, err))
+		return
+	}
+	if !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"only changefeeds with a MySQL-compatible sink can be verified"))
+		return
+	}
+	if !info.Config.EnableSyncPoint {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"syncpoint of the changefeed must be enabled to verify it"))
+		return
+	}
+
+	err = h.verifications.Start(&verification.Config{
+		ChangefeedID:  changefeedID,
+		UpstreamURI:   cfg.UpstreamURI,
+		SinkURI:       info.SinkURI,
+		ReplicaConfig: info.Config,
+		PrimaryTs:     cfg.PrimaryTs,
+		ChunkSize:     cfg.ChunkSize,
+		Concurrency:   cfg.Concurrency,
+		QPS:           cfg.QPS,
+	})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.verifications.Status(changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// getVerification returns the last consistency check of a changefeed
+// @Summary Get the consistency check of a changefeed
+// @Description get the progress of the running consistency check, or the
+// @Description report of the last one, including differing chunks and rows
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Success 200 {object} verification.Status
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/verification [get]
+func (h *OpenAPIV2) getVerification(c *gin.Context) {
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	status, err := h.verifications.Status(changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// cancelVerification cancels the running consistency check of a changefeed
+// @Summary Cancel the consistency check of a changefeed
+// @Description cancel the running consistency check of a changefeed
+// @Tags changefeed,v2
+// @Accept json
+// @Produce json
+// @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace  query  string  false  "changefeed namespace"
+// @Success 200 {object} EmptyResponse
+// @Failure 500,400 {object} model.HTTPError
+// @Router /api/v2/changefeeds/{changefeed_id}/verification [delete]
+func (h *OpenAPIV2) cancelVerification(c *gin.Context) {
+	changefeedID := getChangefeedID(c)
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	if err := h.verifications.Cancel(changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &EmptyResponse{})
+}
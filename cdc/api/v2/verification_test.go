@@ -0,0 +1,91 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerification(t *testing.T) {
+	t.Parallel()
+
+	verify := testCase{url: "/api/v2/changefeeds/%s/verification", method: "POST"}
+	get := testCase{url: "/api/v2/changefeeds/%s/verification", method: "GET"}
+	cancel := testCase{url: "/api/v2/changefeeds/%s/verification", method: "DELETE"}
+	statusProvider := &mockStatusProvider{}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	validID := "changefeed-valid-id"
+
+	request := func(tc testCase, cfg *VerifyConfig) (int, model.HTTPError) {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(),
+			tc.method, fmt.Sprintf(tc.url, validID), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		respErr := model.HTTPError{}
+		if w.Code != http.StatusOK {
+			require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+		}
+		return w.Code, respErr
+	}
+
+	// case 1: invalid upstream uri
+	code, respErr := request(verify, &VerifyConfig{UpstreamURI: "kafka://127.0.0.1:9092/"})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+
+	// case 2: the sink is not MySQL-compatible
+	cfg := &VerifyConfig{UpstreamURI: "mysql://root@127.0.0.1:4000/"}
+	statusProvider.changefeedInfo = &model.ChangeFeedInfo{
+		ID:      validID,
+		SinkURI: "blackhole://",
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	code, respErr = request(verify, cfg)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Error, "MySQL-compatible sink")
+
+	// case 3: syncpoint is not enabled
+	statusProvider.changefeedInfo.SinkURI = "tidb://root@127.0.0.1:4000/"
+	code, respErr = request(verify, cfg)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Error, "syncpoint")
+
+	// case 4: no verification has been started
+	code, respErr = request(get, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Code, "ErrVerificationNotFound")
+	code, respErr = request(cancel, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, respErr.Code, "ErrVerificationNotFound")
+}
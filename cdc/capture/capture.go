@@ -66,6 +66,9 @@ type Capture interface {
 
 	GetUpstreamManager() (*upstream.Manager, error)
 	GetEtcdClient() etcd.CDCEtcdClient
+	// GetSorterDiskUsage returns disk usages of changefeeds in the sorter of
+	// the capture.
+	GetSorterDiskUsage() []factory.ChangefeedDiskUsage
 	// IsReady returns if the cdc server is ready
 	// currently only check if ettcd data migration is done
 	IsReady() bool
@@ -179,6 +182,14 @@ func (c *captureImpl) GetEtcdClient() etcd.CDCEtcdClient {
 	return c.EtcdClient
 }
 
+// GetSorterDiskUsage implements Capture.
+func (c *captureImpl) GetSorterDiskUsage() []factory.ChangefeedDiskUsage {
+	if c.sortEngineFactory == nil {
+		return nil
+	}
+	return c.sortEngineFactory.DiskUsage()
+}
+
 // reset the capture before run it.
 func (c *captureImpl) reset(ctx context.Context) error {
 	lease, err := c.EtcdClient.GetEtcdClient().Grant(ctx, int64(c.config.CaptureSessionTTL))
@@ -197,6 +208,7 @@ func (c *captureImpl) reset(ctx context.Context) error {
 		ID:            uuid.New().String(),
 		AdvertiseAddr: c.config.AdvertiseAddr,
 		Version:       version.ReleaseVersion,
+		Labels:        c.config.Labels,
 	}
 
 	if c.upstreamManager != nil {
@@ -12,6 +12,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/pingcap/tiflow/cdc/model"
 	owner "github.com/pingcap/tiflow/cdc/owner"
+	factory "github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/factory"
 	etcd "github.com/pingcap/tiflow/pkg/etcd"
 	upstream "github.com/pingcap/tiflow/pkg/upstream"
 )
@@ -109,6 +110,20 @@ func (mr *MockCaptureMockRecorder) GetOwnerCaptureInfo(ctx interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnerCaptureInfo", reflect.TypeOf((*MockCapture)(nil).GetOwnerCaptureInfo), ctx)
 }
 
+// GetSorterDiskUsage mocks base method.
+func (m *MockCapture) GetSorterDiskUsage() []factory.ChangefeedDiskUsage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSorterDiskUsage")
+	ret0, _ := ret[0].([]factory.ChangefeedDiskUsage)
+	return ret0
+}
+
+// GetSorterDiskUsage indicates an expected call of GetSorterDiskUsage.
+func (mr *MockCaptureMockRecorder) GetSorterDiskUsage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSorterDiskUsage", reflect.TypeOf((*MockCapture)(nil).GetSorterDiskUsage))
+}
+
 // GetUpstreamManager mocks base method.
 func (m *MockCapture) GetUpstreamManager() (*upstream.Manager, error) {
 	m.ctrl.T.Helper()
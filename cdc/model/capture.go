@@ -28,6 +28,8 @@ type CaptureInfo struct {
 	ID            CaptureID `json:"id"`
 	AdvertiseAddr string    `json:"address"`
 	Version       string    `json:"version"`
+	// Labels are user-defined labels of the capture, e.g. zone and rack.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Marshal using json.Marshal.
@@ -82,6 +82,10 @@ const (
 	StateStopped  FeedState = "stopped"
 	StateRemoved  FeedState = "removed"
 	StateFinished FeedState = "finished"
+	// StateWarning means the changefeed is still recovering from a retryable
+	// error. It is never persisted, it is only shown to users in place of
+	// StateNormal or StateError, see ChangeFeedInfo.DisplayState.
+	StateWarning FeedState = "warning"
 )
 
 // ToInt return an int for each `FeedState`, only use this for metrics.
@@ -99,6 +103,8 @@ func (s FeedState) ToInt() int {
 		return 4
 	case StateRemoved:
 		return 5
+	case StateWarning:
+		return 6
 	}
 	// -1 for unknown feed state
 	return -1
@@ -111,7 +117,7 @@ func (s FeedState) IsNeeded(need string) bool {
 	}
 	if need == "" {
 		switch s {
-		case StateNormal:
+		case StateNormal, StateWarning:
 			return true
 		case StateStopped:
 			return true
@@ -144,6 +150,9 @@ type ChangeFeedInfo struct {
 	Config *config.ReplicaConfig `json:"config"`
 	State  FeedState             `json:"state"`
 	Error  *RunningError         `json:"error"`
+	// Warning is the retryable error the changefeed is retrying, it is
+	// cleared once the changefeed recovers.
+	Warning *ChangefeedWarning `json:"warning,omitempty"`
 
 	CreatorVersion string `json:"creator-version"`
 	// Epoch is the epoch of a changefeed, changes on every restart.
@@ -230,6 +239,24 @@ func (info *ChangeFeedInfo) GetTargetTs() uint64 {
 	return uint64(math.MaxUint64)
 }
 
+// DisplayState returns the state of the changefeed shown to users. A normal
+// changefeed, or one backing off from an error, is shown in StateWarning if
+// a retryable error is recorded in its warning.
+func (info *ChangeFeedInfo) DisplayState() FeedState {
+	if info.Warning == nil {
+		return info.State
+	}
+	switch info.State {
+	case StateNormal:
+		return StateWarning
+	case StateError:
+		if info.Error == nil || !info.Error.IsChangefeedUnRetryableError() {
+			return StateWarning
+		}
+	}
+	return info.State
+}
+
 // Marshal returns the json marshal format of a ChangeFeedInfo
 func (info *ChangeFeedInfo) Marshal() (string, error) {
 	data, err := json.Marshal(info)
@@ -285,6 +312,10 @@ func (info *ChangeFeedInfo) VerifyAndComplete() error {
 		info.Config.Integrity = defaultConfig.Integrity
 	}
 
+	if info.Config.OnFinish == nil {
+		info.Config.OnFinish = defaultConfig.OnFinish
+	}
+
 	return nil
 }
 
@@ -0,0 +1,124 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// MaxChangefeedHistoryEvents is the max number of events kept in the
+	// history of a changefeed, the oldest events are dropped first.
+	MaxChangefeedHistoryEvents = 100
+	// MaxChangefeedHistorySize is the max size in bytes of the encoded
+	// history of a changefeed, the oldest events are dropped first.
+	MaxChangefeedHistorySize = 64 * 1024
+	// maxChangefeedEventMessageLength is the max length of the message of an
+	// event, longer messages are truncated.
+	maxChangefeedEventMessageLength = 1024
+)
+
+// ChangefeedEventType is the type of changefeed history event.
+type ChangefeedEventType string
+
+// All ChangefeedEventType
+const (
+	// ChangefeedEventStateChanged means the state of the changefeed is changed.
+	ChangefeedEventStateChanged ChangefeedEventType = "state-changed"
+	// ChangefeedEventAdminJob means an admin job is handled by the owner.
+	ChangefeedEventAdminJob ChangefeedEventType = "admin-job"
+	// ChangefeedEventError means an error is reported.
+	ChangefeedEventError ChangefeedEventType = "error"
+	// ChangefeedEventWarning means a warning is reported.
+	ChangefeedEventWarning ChangefeedEventType = "warning"
+	// ChangefeedEventDDL means a DDL is executed in the downstream.
+	ChangefeedEventDDL ChangefeedEventType = "ddl"
+)
+
+// ChangefeedEvent is an entry of the changefeed history.
+type ChangefeedEvent struct {
+	Time    time.Time           `json:"time"`
+	Type    ChangefeedEventType `json:"type"`
+	Message string              `json:"message"`
+	// State is the state of the changefeed after the event happens.
+	State FeedState `json:"state,omitempty"`
+	// Code is the error code of error and warning events.
+	Code string `json:"code,omitempty"`
+	// CommitTs is the commit ts of the DDL events.
+	CommitTs uint64 `json:"commit-ts,omitempty"`
+}
+
+// isDuplicated returns true if the two events have the same content,
+// the time of the events is ignored.
+func (e *ChangefeedEvent) isDuplicated(other *ChangefeedEvent) bool {
+	return e.Type == other.Type && e.Message == other.Message &&
+		e.State == other.State && e.Code == other.Code &&
+		e.CommitTs == other.CommitTs
+}
+
+// ChangefeedHistory stores a bounded list of events of a changefeed,
+// ordered by the time they happen.
+type ChangefeedHistory struct {
+	Events []*ChangefeedEvent `json:"events"`
+}
+
+// Append appends an event to the history, it returns false if the event
+// is the same as the last one. The message of the event is truncated to
+// maxChangefeedEventMessageLength, and the oldest events are dropped if the
+// number of events exceeds MaxChangefeedHistoryEvents or the encoded size
+// exceeds MaxChangefeedHistorySize.
+func (h *ChangefeedHistory) Append(event *ChangefeedEvent) bool {
+	if len(event.Message) > maxChangefeedEventMessageLength {
+		event.Message = event.Message[:maxChangefeedEventMessageLength] + "..."
+	}
+	if len(h.Events) > 0 && h.Events[len(h.Events)-1].isDuplicated(event) {
+		return false
+	}
+	h.Events = append(h.Events, event)
+	if len(h.Events) > MaxChangefeedHistoryEvents {
+		h.Events = h.Events[len(h.Events)-MaxChangefeedHistoryEvents:]
+	}
+	// keep the newest events whose total size fits in the limit, the size
+	// of an event is the length of its json encoding plus a separator.
+	size := len(`{"events":[]}`)
+	for i := len(h.Events) - 1; i >= 0; i-- {
+		data, err := json.Marshal(h.Events[i])
+		if err != nil {
+			continue
+		}
+		size += len(data) + 1
+		if size > MaxChangefeedHistorySize {
+			h.Events = h.Events[i+1:]
+			break
+		}
+	}
+	return true
+}
+
+// Marshal returns json encoded string of ChangefeedHistory, only contains necessary fields stored in storage
+func (h *ChangefeedHistory) Marshal() (string, error) {
+	data, err := json.Marshal(h)
+	return string(data), cerror.WrapError(cerror.ErrMarshalFailed, err)
+}
+
+// Unmarshal unmarshals into *ChangefeedHistory from json marshal byte slice
+func (h *ChangefeedHistory) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, h)
+	return errors.Annotatef(
+		cerror.WrapError(cerror.ErrUnmarshalFailed, err), "Unmarshal data: %v", data)
+}
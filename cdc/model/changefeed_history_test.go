@@ -0,0 +1,99 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedHistoryAppend(t *testing.T) {
+	t.Parallel()
+
+	history := &ChangefeedHistory{}
+	event := &ChangefeedEvent{
+		Time:    time.Now(),
+		Type:    ChangefeedEventError,
+		Message: "sink error",
+		Code:    "CDC:ErrSinkURIInvalid",
+	}
+	require.True(t, history.Append(event))
+	// the same event reported again is ignored
+	dup := *event
+	dup.Time = event.Time.Add(time.Second)
+	require.False(t, history.Append(&dup))
+	require.Len(t, history.Events, 1)
+
+	for i := 0; i < MaxChangefeedHistoryEvents+10; i++ {
+		require.True(t, history.Append(&ChangefeedEvent{
+			Time:     time.Now(),
+			Type:     ChangefeedEventDDL,
+			Message:  fmt.Sprintf("create table t%d (id int primary key)", i),
+			CommitTs: uint64(i),
+		}))
+	}
+	require.Len(t, history.Events, MaxChangefeedHistoryEvents)
+	require.Equal(t, uint64(10), history.Events[0].CommitTs)
+	require.Equal(t, uint64(MaxChangefeedHistoryEvents+9),
+		history.Events[MaxChangefeedHistoryEvents-1].CommitTs)
+}
+
+func TestChangefeedHistoryAppendLargeEvents(t *testing.T) {
+	t.Parallel()
+
+	history := &ChangefeedHistory{}
+	for i := 0; i < MaxChangefeedHistoryEvents; i++ {
+		require.True(t, history.Append(&ChangefeedEvent{
+			Time:    time.Now(),
+			Type:    ChangefeedEventError,
+			Message: fmt.Sprintf("%d: %s", i, strings.Repeat("x", 4096)),
+		}))
+	}
+	// long messages are truncated
+	last := history.Events[len(history.Events)-1]
+	require.Len(t, last.Message, maxChangefeedEventMessageLength+len("..."))
+	require.True(t, strings.HasPrefix(last.Message,
+		fmt.Sprintf("%d: ", MaxChangefeedHistoryEvents-1)))
+
+	// the oldest events are dropped to keep the size in the limit
+	require.Less(t, len(history.Events), MaxChangefeedHistoryEvents)
+	data, err := history.Marshal()
+	require.Nil(t, err)
+	require.LessOrEqual(t, len(data), MaxChangefeedHistorySize)
+	require.Greater(t, len(data), MaxChangefeedHistorySize-2*maxChangefeedEventMessageLength)
+}
+
+func TestChangefeedHistoryMarshal(t *testing.T) {
+	t.Parallel()
+
+	history := &ChangefeedHistory{}
+	history.Append(&ChangefeedEvent{
+		Time:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Type:    ChangefeedEventStateChanged,
+		Message: "changefeed state is changed to stopped",
+		State:   StateStopped,
+	})
+	data, err := history.Marshal()
+	require.Nil(t, err)
+	require.Equal(t, `{"events":[{"time":"2023-01-01T00:00:00Z","type":"state-changed",`+
+		`"message":"changefeed state is changed to stopped","state":"stopped"}]}`, data)
+
+	decoded := &ChangefeedHistory{}
+	require.Nil(t, decoded.Unmarshal([]byte(data)))
+	require.Equal(t, history, decoded)
+}
@@ -17,6 +17,8 @@ import (
 	"encoding/binary"
 
 	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
 	codecv1 "github.com/pingcap/tiflow/cdc/model/codec/v1"
 	"github.com/tinylib/msgp/msgp"
@@ -62,6 +64,11 @@ func postUnmarshal(r *model.RedoLog) {
 				workaroundColumn(c, &r.RedoRow.PreColumns[i])
 			}
 		}
+		if len(row.Columns) > 0 {
+			row.ColInfos = colInfosFromRedo(row.Columns, r.RedoRow.Columns)
+		} else {
+			row.ColInfos = colInfosFromRedo(row.PreColumns, r.RedoRow.PreColumns)
+		}
 		r.RedoRow.Columns = nil
 		r.RedoRow.PreColumns = nil
 	}
@@ -73,6 +80,26 @@ func postUnmarshal(r *model.RedoLog) {
 	}
 }
 
+// colInfosFromRedo rebuilds field types of columns, so that table infos can
+// be rebuilt from redo logs. It returns nil for logs written without field
+// types of columns.
+func colInfosFromRedo(cols []*model.Column, redoCols []model.RedoColumn) []rowcodec.ColInfo {
+	var colInfos []rowcodec.ColInfo
+	for i, c := range cols {
+		if c == nil || redoCols[i].Flen == 0 {
+			continue
+		}
+		if colInfos == nil {
+			colInfos = make([]rowcodec.ColInfo, len(cols))
+		}
+		ft := types.NewFieldType(c.Type)
+		ft.SetFlen(redoCols[i].Flen)
+		ft.SetDecimal(redoCols[i].Decimal)
+		colInfos[i].Ft = ft
+	}
+	return colInfos
+}
+
 func preMarshal(r *model.RedoLog) {
 	// Workaround empty byte slice for msgp#247
 	workaroundColumn := func(redoC *model.RedoColumn) {
@@ -84,25 +111,35 @@ func preMarshal(r *model.RedoLog) {
 		}
 	}
 
+	// Field types are recorded to rebuild table infos from redo logs.
+	fieldType := func(redoC *model.RedoColumn, colInfos []rowcodec.ColInfo, i int) {
+		if i < len(colInfos) && colInfos[i].Ft != nil {
+			redoC.Flen = colInfos[i].Ft.GetFlen()
+			redoC.Decimal = colInfos[i].Ft.GetDecimal()
+		}
+	}
+
 	if r.RedoRow.Row != nil {
 		row := r.RedoRow.Row
 		r.RedoRow.Columns = make([]model.RedoColumn, 0, len(row.Columns))
 		r.RedoRow.PreColumns = make([]model.RedoColumn, 0, len(row.PreColumns))
-		for _, c := range row.Columns {
+		for i, c := range row.Columns {
 			redoC := model.RedoColumn{}
 			if c != nil {
 				redoC.Value = c.Value
 				redoC.Flag = uint64(c.Flag)
 				workaroundColumn(&redoC)
+				fieldType(&redoC, row.ColInfos, i)
 			}
 			r.RedoRow.Columns = append(r.RedoRow.Columns, redoC)
 		}
-		for _, c := range row.PreColumns {
+		for i, c := range row.PreColumns {
 			redoC := model.RedoColumn{}
 			if c != nil {
 				redoC.Value = c.Value
 				redoC.Flag = uint64(c.Flag)
 				workaroundColumn(&redoC)
+				fieldType(&redoC, row.ColInfos, i)
 			}
 			r.RedoRow.PreColumns = append(r.RedoRow.PreColumns, redoC)
 		}
@@ -19,6 +19,8 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
 	codecv1 "github.com/pingcap/tiflow/cdc/model/codec/v1"
 	"github.com/stretchr/testify/require"
@@ -253,3 +255,51 @@ func TestDDLRedoConvert(t *testing.T) {
 	require.Zero(t, len(data))
 	require.Equal(t, ddl, redoLog2.RedoDDL.DDL)
 }
+
+func TestRowRedoConvertWithFieldTypes(t *testing.T) {
+	t.Parallel()
+
+	decimalType := types.NewFieldType(mysql.TypeNewDecimal)
+	decimalType.SetFlen(10)
+	decimalType.SetDecimal(2)
+	varcharType := types.NewFieldType(mysql.TypeVarchar)
+	varcharType.SetFlen(32)
+	row := &model.RowChangedEvent{
+		StartTs:  100,
+		CommitTs: 120,
+		Table:    &model.TableName{Schema: "test", Table: "table1", TableID: 57},
+		Columns: []*model.Column{{
+			Name:  "a1",
+			Type:  mysql.TypeNewDecimal,
+			Value: "1.00",
+		}, {
+			Name:  "a2",
+			Type:  mysql.TypeVarchar,
+			Value: []byte("char"),
+		}, nil},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, Ft: decimalType}, {ID: 2, Ft: varcharType}, {ID: 3},
+		},
+	}
+
+	redoLog := &model.RedoLog{RedoRow: model.RedoRowChangedEvent{Row: row}}
+	data, err := MarshalRedoLog(redoLog, nil)
+	require.Nil(t, err)
+
+	redoLog2, _, err := UnmarshalRedoLog(data)
+	require.Nil(t, err)
+	colInfos := redoLog2.RedoRow.Row.ColInfos
+	require.Len(t, colInfos, 3)
+	require.Equal(t, 10, colInfos[0].Ft.GetFlen())
+	require.Equal(t, 2, colInfos[0].Ft.GetDecimal())
+	require.Equal(t, 32, colInfos[1].Ft.GetFlen())
+	require.Nil(t, colInfos[2].Ft)
+
+	// Logs written without field types don't have column infos.
+	row.ColInfos = nil
+	data, err = MarshalRedoLog(redoLog, nil)
+	require.Nil(t, err)
+	redoLog2, _, err = UnmarshalRedoLog(data)
+	require.Nil(t, err)
+	require.Nil(t, redoLog2.RedoRow.Row.ColInfos)
+}
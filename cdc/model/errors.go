@@ -15,6 +15,7 @@ package model
 
 import (
 	"errors"
+	"time"
 
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -30,3 +31,16 @@ type RunningError struct {
 func (r RunningError) IsChangefeedUnRetryableError() bool {
 	return cerror.IsChangefeedUnRetryableError(errors.New(r.Message + r.Code))
 }
+
+// ChangefeedWarning records a retryable error that a changefeed is retrying,
+// along with the progress of the retry.
+type ChangefeedWarning struct {
+	Error *RunningError `json:"error"`
+	// RetryCount is the number of retries since the changefeed was last normal.
+	RetryCount int `json:"retry-count"`
+	// NextRetryTime is the time when the changefeed will be restarted.
+	NextRetryTime time.Time `json:"next-retry-time"`
+	// CheckpointTs is the checkpoint ts when the error happens, the changefeed
+	// is considered recovered once its checkpoint ts exceeds this value.
+	CheckpointTs uint64 `json:"checkpoint-ts"`
+}
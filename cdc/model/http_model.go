@@ -61,7 +61,8 @@ func NewHTTPError(err error) HTTPError {
 }
 
 // Liveness is the liveness status of a capture.
-// Liveness can only be changed from alive to stopping, and no way back.
+// Liveness can only be changed from alive to stopping, and it changes back
+// only if draining the capture is cancelled.
 type Liveness int32
 
 const (
@@ -77,6 +78,13 @@ func (l *Liveness) Store(v Liveness) bool {
 		(*int32)(l), int32(LivenessCaptureAlive), int32(v))
 }
 
+// Resume changes the liveness from stopping back to alive, it is used when
+// draining the capture is cancelled. Returns true if it success.
+func (l *Liveness) Resume() bool {
+	return atomic.CompareAndSwapInt32(
+		(*int32)(l), int32(LivenessCaptureStopping), int32(LivenessCaptureAlive))
+}
+
 // Load the liveness.
 func (l *Liveness) Load() Liveness {
 	return Liveness(atomic.LoadInt32((*int32)(l)))
@@ -218,6 +226,25 @@ type DrainCaptureRequest struct {
 // DrainCaptureResp is response for manual `DrainCapture`
 type DrainCaptureResp struct {
 	CurrentTableCount int `json:"current_table_count"`
+	// Draining is true if the capture is being drained or has been drained,
+	// it is false if draining the capture is never started or is canceled.
+	Draining bool `json:"draining"`
+	// Tables is the progress of table spans on the draining capture.
+	Tables []DrainTableProgress `json:"tables,omitempty"`
+}
+
+// DrainTableProgress is the progress of draining a table span.
+type DrainTableProgress struct {
+	Namespace    string  `json:"namespace"`
+	ChangefeedID string  `json:"changefeed_id"`
+	TableID      TableID `json:"table_id"`
+	Span         string  `json:"span"`
+	// State is the replication state of the span, e.g. Replicating,
+	// Prepare and Commit.
+	State string `json:"state"`
+	// DestCapture is the capture the span is moving to, it is empty if
+	// the span is not moving yet.
+	DestCapture CaptureID `json:"dest_capture,omitempty"`
 }
 
 // MoveTableReq is the request for `MoveTable`
@@ -0,0 +1,147 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// NamespaceInfo describes the quotas of a namespace, it is stored in etcd.
+// Changefeeds in a namespace without NamespaceInfo are not limited.
+type NamespaceInfo struct {
+	Name string `json:"name"`
+	// MaxDeclaredMemoryQuota is an admission limit in bytes on the sum of
+	// the memory quotas declared by running changefeeds in the namespace,
+	// 0 means no limit. It is checked when a changefeed is created, updated
+	// or resumed, and it does not bound the memory used at runtime, which is
+	// limited by the memory quota of each changefeed on each capture.
+	MaxDeclaredMemoryQuota uint64 `json:"max-declared-memory-quota"`
+	// MaxChangefeeds is the max number of changefeeds in the namespace,
+	// 0 means no limit.
+	MaxChangefeeds int `json:"max-changefeeds"`
+	// AllowedSinkSchemes is the sink schemes that changefeeds in the
+	// namespace can use, empty means all schemes are allowed.
+	AllowedSinkSchemes []string `json:"allowed-sink-schemes,omitempty"`
+	// CaptureLabels is the labels of captures that changefeeds in the
+	// namespace are scheduled to, they override labels with the same keys
+	// set by changefeeds.
+	CaptureLabels map[string]string `json:"capture-labels,omitempty"`
+}
+
+// Marshal using json.Marshal.
+func (n *NamespaceInfo) Marshal() ([]byte, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// Unmarshal from binary data.
+func (n *NamespaceInfo) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, n)
+	return errors.Annotatef(cerror.WrapError(cerror.ErrUnmarshalFailed, err),
+		"unmarshal data: %v", data)
+}
+
+// Validate checks the NamespaceInfo and normalizes the sink schemes.
+func (n *NamespaceInfo) Validate() error {
+	if err := ValidateNamespace(n.Name); err != nil {
+		return err
+	}
+	if err := config.ValidateCaptureLabels(n.CaptureLabels); err != nil {
+		return cerror.ErrInvalidNamespaceInfo.GenWithStackByArgs(err.Error())
+	}
+	if n.MaxChangefeeds < 0 {
+		return cerror.ErrInvalidNamespaceInfo.GenWithStackByArgs(
+			"max-changefeeds can not be negative")
+	}
+	for i, scheme := range n.AllowedSinkSchemes {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if scheme == "" {
+			return cerror.ErrInvalidNamespaceInfo.GenWithStackByArgs(
+				"allowed-sink-schemes can not contain an empty scheme")
+		}
+		n.AllowedSinkSchemes[i] = scheme
+	}
+	return nil
+}
+
+// IsSinkAllowed returns true if changefeeds in the namespace can
+// replicate to the given sink.
+func (n *NamespaceInfo) IsSinkAllowed(sinkURI string) bool {
+	if len(n.AllowedSinkSchemes) == 0 {
+		return true
+	}
+	uri, err := url.Parse(sinkURI)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(uri.Scheme)
+	for _, allowed := range n.AllowedSinkSchemes {
+		if scheme == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckChangefeed checks whether the changefeed can run in the namespace,
+// others are the other changefeeds in the same namespace.
+func (n *NamespaceInfo) CheckChangefeed(
+	info *ChangeFeedInfo, others []*ChangeFeedInfo,
+) error {
+	if !n.IsSinkAllowed(info.SinkURI) {
+		return cerror.ErrNamespaceQuotaExceeded.GenWithStackByArgs(info.ID, n.Name,
+			fmt.Sprintf("allowed sink schemes are %v", n.AllowedSinkSchemes))
+	}
+	if n.MaxChangefeeds > 0 && len(others) >= n.MaxChangefeeds {
+		return cerror.ErrNamespaceQuotaExceeded.GenWithStackByArgs(info.ID, n.Name,
+			fmt.Sprintf("max changefeeds is %d", n.MaxChangefeeds))
+	}
+	if n.MaxDeclaredMemoryQuota > 0 {
+		total := info.Config.MemoryQuota
+		for _, other := range others {
+			if other.consumesMemory() {
+				total += other.Config.MemoryQuota
+			}
+		}
+		if total > n.MaxDeclaredMemoryQuota {
+			return cerror.ErrNamespaceQuotaExceeded.GenWithStackByArgs(info.ID, n.Name,
+				fmt.Sprintf("max declared memory quota is %d, but %d is declared",
+					n.MaxDeclaredMemoryQuota, total))
+		}
+	}
+	return nil
+}
+
+// consumesMemory returns true if the changefeed is running or is going to
+// run, and its memory quota should be counted in the namespace.
+func (info *ChangeFeedInfo) consumesMemory() bool {
+	if info.Config == nil {
+		return false
+	}
+	switch info.State {
+	case StateStopped, StateFailed, StateFinished, StateRemoved:
+		return false
+	}
+	return true
+}
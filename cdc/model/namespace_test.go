@@ -0,0 +1,86 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceInfoValidate(t *testing.T) {
+	t.Parallel()
+
+	info := &NamespaceInfo{Name: "team-a", AllowedSinkSchemes: []string{" Kafka", "mysql"}}
+	require.Nil(t, info.Validate())
+	require.Equal(t, []string{"kafka", "mysql"}, info.AllowedSinkSchemes)
+
+	info = &NamespaceInfo{Name: "team_a"}
+	require.True(t, cerror.ErrInvalidNamespace.Equal(info.Validate()))
+
+	info = &NamespaceInfo{Name: "team-a", MaxChangefeeds: -1}
+	require.True(t, cerror.ErrInvalidNamespaceInfo.Equal(info.Validate()))
+
+	info = &NamespaceInfo{Name: "team-a", AllowedSinkSchemes: []string{""}}
+	require.True(t, cerror.ErrInvalidNamespaceInfo.Equal(info.Validate()))
+}
+
+func TestNamespaceInfoCheckChangefeed(t *testing.T) {
+	t.Parallel()
+
+	newInfo := func(id, sinkURI string, quota uint64, state FeedState) *ChangeFeedInfo {
+		cfg := config.GetDefaultReplicaConfig()
+		cfg.MemoryQuota = quota
+		return &ChangeFeedInfo{ID: id, SinkURI: sinkURI, Config: cfg, State: state}
+	}
+	ns := &NamespaceInfo{
+		Name:                   "team-a",
+		MaxDeclaredMemoryQuota: 1024,
+		MaxChangefeeds:         3,
+		AllowedSinkSchemes:     []string{"kafka"},
+	}
+	others := []*ChangeFeedInfo{
+		newInfo("cf-1", "kafka://127.0.0.1:9092/a", 512, StateNormal),
+		newInfo("cf-2", "kafka://127.0.0.1:9092/b", 1024, StateStopped),
+	}
+
+	require.Nil(t, ns.CheckChangefeed(
+		newInfo("cf-3", "Kafka://127.0.0.1:9092/c", 512, StateNormal), others))
+
+	// sink scheme is not allowed
+	err := ns.CheckChangefeed(
+		newInfo("cf-3", "mysql://127.0.0.1:3306/", 512, StateNormal), others)
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.Contains(t, err.Error(), "allowed sink schemes")
+
+	// max declared memory quota is exceeded, the stopped changefeed is not counted
+	err = ns.CheckChangefeed(
+		newInfo("cf-3", "kafka://127.0.0.1:9092/c", 513, StateNormal), others)
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.Contains(t, err.Error(), "memory quota")
+
+	// too many changefeeds
+	others = append(others, newInfo("cf-3", "kafka://127.0.0.1:9092/c", 0, StateFailed))
+	err = ns.CheckChangefeed(
+		newInfo("cf-4", "kafka://127.0.0.1:9092/d", 0, StateNormal), others)
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.Contains(t, err.Error(), "max changefeeds")
+
+	// namespace without limits
+	ns = &NamespaceInfo{Name: "team-b"}
+	require.Nil(t, ns.CheckChangefeed(
+		newInfo("cf-4", "mysql://127.0.0.1:3306/", 1<<30, StateNormal), others))
+}
@@ -16,10 +16,12 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/errors"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -32,6 +34,15 @@ type AdminJob struct {
 	Type                  AdminJobType
 	Error                 *RunningError
 	OverwriteCheckpointTs uint64
+	// TargetTs and OnFinish are only used by AdminUpdateTarget.
+	TargetTs uint64
+	OnFinish *config.OnFinishConfig
+	// HeldDDLCommitTs and DDLDecision are only used by AdminHandleDDL,
+	// HeldDDLCommitTs is the commit ts of the held DDL to decide on.
+	HeldDDLCommitTs uint64
+	DDLDecision     *DDLDecision
+	// BackfillTableID is only used by AdminBackfillTable.
+	BackfillTableID TableID
 }
 
 // All AdminJob types
@@ -41,6 +52,15 @@ const (
 	AdminResume
 	AdminRemove
 	AdminFinish
+	// AdminUpdateTarget updates the target ts and the on-finish action
+	// of a running changefeed, it does not change the changefeed state.
+	AdminUpdateTarget
+	// AdminHandleDDL decides how to handle a DDL held for approval,
+	// it does not change the changefeed state.
+	AdminHandleDDL
+	// AdminBackfillTable re-syncs a table of a running changefeed from a
+	// snapshot, it does not change the changefeed state.
+	AdminBackfillTable
 )
 
 // String implements fmt.Stringer interface.
@@ -56,6 +76,12 @@ func (t AdminJobType) String() string {
 		return "remove changefeed"
 	case AdminFinish:
 		return "finish changefeed"
+	case AdminUpdateTarget:
+		return "update changefeed target"
+	case AdminHandleDDL:
+		return "handle held ddl"
+	case AdminBackfillTable:
+		return "backfill table"
 	}
 	return "unknown"
 }
@@ -94,6 +120,10 @@ type TaskPosition struct {
 
 	// Error when error happens
 	Error *RunningError `json:"error"`
+	// Snapshot is the progress of spans whose snapshots are exported by the
+	// capture, it is kept until the snapshot is finished so that the export
+	// of a span can be resumed on any capture.
+	Snapshot []*SpanSnapshotProgress `json:"snapshot,omitempty"`
 }
 
 // Marshal returns the json marshal format of a TaskStatus
@@ -129,6 +159,9 @@ func (tp *TaskPosition) Clone() *TaskPosition {
 			Message: tp.Error.Message,
 		}
 	}
+	for _, progress := range tp.Snapshot {
+		ret.Snapshot = append(ret.Snapshot, progress.Clone())
+	}
 	return ret
 }
 
@@ -266,6 +299,155 @@ type ChangeFeedStatus struct {
 	// a table's DDL job that had not finished when the changefeed was stopped.
 	MinTableBarrierTs uint64       `json:"min-table-barrier-ts"`
 	AdminJobType      AdminJobType `json:"admin-job-type"`
+	// HeldDDL is the DDL held for manual approval before it is executed,
+	// it is nil if there is no held DDL.
+	HeldDDL *HeldDDL `json:"held-ddl,omitempty"`
+	// Snapshot is the progress of exporting the initial snapshot, it is nil
+	// if the changefeed is not created in the snapshot mode.
+	Snapshot *SnapshotProgress `json:"snapshot,omitempty"`
+	// Backfill is the last table backfill of the changefeed, it is kept
+	// after it's finished until another table is backfilled.
+	Backfill *TableBackfill `json:"backfill,omitempty"`
+}
+
+// TableBackfill re-syncs a table of a running changefeed. The table is
+// removed from all captures, and then it's added again at the snapshot ts,
+// the processor exports its snapshot to the sink before replicating it. The
+// progress of the table is held at the snapshot ts meanwhile, so that neither
+// the snapshot nor the incremental data after it can be garbage collected.
+type TableBackfill struct {
+	TableID TableID `json:"table-id"`
+	// Snapshot is nil until the backfill is started, the snapshot ts is the
+	// checkpoint ts of the changefeed at that time.
+	Snapshot *SnapshotProgress `json:"snapshot,omitempty"`
+}
+
+// IsFinished returns true if the snapshot of the table is exported.
+func (b *TableBackfill) IsFinished() bool {
+	return b.Snapshot != nil && b.Snapshot.Finished
+}
+
+// SnapshotProgress is the progress of exporting the snapshot of a changefeed,
+// either the initial snapshot or the snapshot of a backfilled table. Spans are
+// exported by processors, which record the progress of each span in their task
+// positions, only a summary of them is kept here.
+type SnapshotProgress struct {
+	// SnapshotTs is the ts of the snapshot, it is the start ts of the
+	// changefeed for the initial snapshot.
+	SnapshotTs   uint64 `json:"snapshot-ts"`
+	ExportedRows uint64 `json:"exported-rows"`
+	DoneSpans    int    `json:"done-spans"`
+	Finished     bool   `json:"finished"`
+}
+
+// Clone returns a copy of the SnapshotProgress.
+func (p *SnapshotProgress) Clone() *SnapshotProgress {
+	if p == nil {
+		return nil
+	}
+	res := *p
+	return &res
+}
+
+// SpanSnapshotProgress is the progress of exporting the snapshot of a span,
+// it is recorded in the task position of the capture exporting the span.
+type SpanSnapshotProgress struct {
+	// The span is stored field by field, tablepb.Span is not able to be
+	// unmarshaled from JSON.
+	TableID    TableID `json:"table-id"`
+	StartKey   []byte  `json:"start-key"`
+	EndKey     []byte  `json:"end-key"`
+	SnapshotTs uint64  `json:"snapshot-ts"`
+	// ResumeKey is the last exported key, the export is resumed after it.
+	ResumeKey    []byte `json:"resume-key,omitempty"`
+	ExportedRows uint64 `json:"exported-rows"`
+	Done         bool   `json:"done"`
+}
+
+// NewSpanSnapshotProgress creates the progress of a span which is not
+// exported yet.
+func NewSpanSnapshotProgress(span tablepb.Span, snapshotTs uint64) *SpanSnapshotProgress {
+	return &SpanSnapshotProgress{
+		TableID:    span.TableID,
+		StartKey:   append([]byte(nil), span.StartKey...),
+		EndKey:     append([]byte(nil), span.EndKey...),
+		SnapshotTs: snapshotTs,
+	}
+}
+
+// Span returns the span being exported.
+func (p *SpanSnapshotProgress) Span() tablepb.Span {
+	return tablepb.Span{TableID: p.TableID, StartKey: p.StartKey, EndKey: p.EndKey}
+}
+
+// Clone returns a deep copy of the SpanSnapshotProgress.
+func (p *SpanSnapshotProgress) Clone() *SpanSnapshotProgress {
+	res := *p
+	res.StartKey = append([]byte(nil), p.StartKey...)
+	res.EndKey = append([]byte(nil), p.EndKey...)
+	res.ResumeKey = append([]byte(nil), p.ResumeKey...)
+	return &res
+}
+
+// DDLDecisionAction is the action an operator takes on a held DDL.
+type DDLDecisionAction string
+
+const (
+	// DDLDecisionApprove executes the held DDL as it is.
+	DDLDecisionApprove DDLDecisionAction = "approve"
+	// DDLDecisionSkip skips the held DDL, it is not executed to the downstream.
+	DDLDecisionSkip DDLDecisionAction = "skip"
+	// DDLDecisionReplace executes another statement instead of the held DDL.
+	DDLDecisionReplace DDLDecisionAction = "replace"
+)
+
+// DDLDecision is the decision of an operator on a held DDL.
+type DDLDecision struct {
+	Action DDLDecisionAction `json:"action"`
+	// Query is the statement executed instead of the held DDL,
+	// it is only used by DDLDecisionReplace.
+	Query string `json:"query,omitempty"`
+}
+
+// Validate checks whether the decision is valid.
+func (d *DDLDecision) Validate() error {
+	switch d.Action {
+	case DDLDecisionApprove, DDLDecisionSkip:
+		if d.Query != "" {
+			return cerror.ErrAPIInvalidParam.GenWithStack(
+				"query can only be used when the held ddl is replaced")
+		}
+	case DDLDecisionReplace:
+		if strings.TrimSpace(d.Query) == "" {
+			return cerror.ErrAPIInvalidParam.GenWithStack(
+				"query is required to replace the held ddl")
+		}
+	default:
+		return cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid ddl decision action: %s, must be one of %s, %s and %s",
+			d.Action, DDLDecisionApprove, DDLDecisionSkip, DDLDecisionReplace)
+	}
+	return nil
+}
+
+// HeldDDL is a DDL held before it is executed to the downstream, because
+// its type needs manual approval, see config.DDLApprovalConfig.
+// Replication of the tables it blocks pauses at its commit ts until an
+// operator makes a decision.
+type HeldDDL struct {
+	CommitTs uint64 `json:"commit-ts"`
+	Type     string `json:"type"`
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	Query    string `json:"query"`
+	// Decision is nil until an operator makes a decision.
+	Decision *DDLDecision `json:"decision,omitempty"`
+}
+
+// IsHeldDDLOf returns true if the held DDL is the given DDL event.
+func (h *HeldDDL) IsHeldDDLOf(ddl *DDLEvent) bool {
+	return h != nil && ddl != nil &&
+		h.CommitTs == ddl.CommitTs && h.Query == ddl.Query
 }
 
 // Marshal returns json encoded string of ChangeFeedStatus, only contains necessary fields stored in storage
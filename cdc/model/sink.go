@@ -42,6 +42,8 @@ const (
 	MessageTypeDDL
 	// MessageTypeResolved is resolved type of message key
 	MessageTypeResolved
+	// MessageTypeSyncPoint is syncpoint type of message key
+	MessageTypeSyncPoint
 )
 
 // ColumnFlagType is for encapsulating the flag operations for different flags.
@@ -332,6 +334,36 @@ type RowChangedEvent struct {
 	SplitTxn bool `json:"-" msg:"-"`
 	// ReplicatingTs is ts when a table starts replicating events to downstream.
 	ReplicatingTs Ts `json:"-" msg:"-"`
+	// TxnBoundary is the position of the row in its transaction, it's only set
+	// when transaction boundaries are enabled for MQ sinks.
+	TxnBoundary *TxnBoundary `json:"-" msg:"-"`
+}
+
+// TxnBoundary describes the position of a row in the rows of its upstream
+// transaction in the same table, the transaction is identified by the StartTs.
+// An upstream transaction changing several tables has a boundary per table,
+// the whole transaction can't be reassembled from a single table.
+//
+//msgp:ignore TxnBoundary
+type TxnBoundary struct {
+	// TableRowIndex is the 1-based index of the row in the rows of the
+	// transaction in its table.
+	TableRowIndex int
+	// TableRowCount is the number of rows of the transaction in the table,
+	// rather than in the whole upstream transaction.
+	TableRowCount int
+}
+
+// IsBegin returns true if the row is the first row of the transaction in
+// the table.
+func (b *TxnBoundary) IsBegin() bool {
+	return b.TableRowIndex == 1
+}
+
+// IsCommit returns true if the row is the last row of the transaction in
+// the table.
+func (b *TxnBoundary) IsCommit() bool {
+	return b.TableRowIndex == b.TableRowCount
 }
 
 // GetCommitTs returns the commit timestamp of this event.
@@ -462,6 +494,10 @@ type RedoColumn struct {
 	// msgp transforms empty byte slice into nil, PTAL msgp#247.
 	ValueIsEmptyBytes bool   `msg:"value-is-empty-bytes"`
 	Flag              uint64 `msg:"flag"`
+	// Flen and Decimal are from the field type of the column, they are used
+	// to rebuild column infos when logs are applied to non-MySQL sinks.
+	Flen    int `msg:"flen"`
+	Decimal int `msg:"decimal"`
 }
 
 // BuildTiDBTableInfo builds a TiDB TableInfo from given information.
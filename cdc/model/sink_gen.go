@@ -425,6 +425,18 @@ func (z *RedoColumn) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "Flag")
 				return
 			}
+		case "flen":
+			z.Flen, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "Flen")
+				return
+			}
+		case "decimal":
+			z.Decimal, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "Decimal")
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -437,10 +449,10 @@ func (z *RedoColumn) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z RedoColumn) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 3
+func (z *RedoColumn) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 5
 	// write "column"
-	err = en.Append(0x83, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
+	err = en.Append(0x85, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
 	if err != nil {
 		return
 	}
@@ -469,15 +481,35 @@ func (z RedoColumn) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "Flag")
 		return
 	}
+	// write "flen"
+	err = en.Append(0xa4, 0x66, 0x6c, 0x65, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.Flen)
+	if err != nil {
+		err = msgp.WrapError(err, "Flen")
+		return
+	}
+	// write "decimal"
+	err = en.Append(0xa7, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.Decimal)
+	if err != nil {
+		err = msgp.WrapError(err, "Decimal")
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z RedoColumn) MarshalMsg(b []byte) (o []byte, err error) {
+func (z *RedoColumn) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 3
+	// map header, size 5
 	// string "column"
-	o = append(o, 0x83, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
+	o = append(o, 0x85, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
 	o, err = msgp.AppendIntf(o, z.Value)
 	if err != nil {
 		err = msgp.WrapError(err, "Value")
@@ -489,6 +521,12 @@ func (z RedoColumn) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "flag"
 	o = append(o, 0xa4, 0x66, 0x6c, 0x61, 0x67)
 	o = msgp.AppendUint64(o, z.Flag)
+	// string "flen"
+	o = append(o, 0xa4, 0x66, 0x6c, 0x65, 0x6e)
+	o = msgp.AppendInt(o, z.Flen)
+	// string "decimal"
+	o = append(o, 0xa7, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c)
+	o = msgp.AppendInt(o, z.Decimal)
 	return
 }
 
@@ -528,6 +566,18 @@ func (z *RedoColumn) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "Flag")
 				return
 			}
+		case "flen":
+			z.Flen, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Flen")
+				return
+			}
+		case "decimal":
+			z.Decimal, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Decimal")
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -541,8 +591,8 @@ func (z *RedoColumn) UnmarshalMsg(bts []byte) (o []byte, err error) {
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z RedoColumn) Msgsize() (s int) {
-	s = 1 + 7 + msgp.GuessSize(z.Value) + 21 + msgp.BoolSize + 5 + msgp.Uint64Size
+func (z *RedoColumn) Msgsize() (s int) {
+	s = 1 + 7 + msgp.GuessSize(z.Value) + 21 + msgp.BoolSize + 5 + msgp.Uint64Size + 5 + msgp.IntSize + 8 + msgp.IntSize
 	return
 }
 
@@ -1118,100 +1168,30 @@ func (z *RedoRowChangedEvent) DecodeMsg(dc *msgp.Reader) (err error) {
 				z.Columns = make([]RedoColumn, zb0002)
 			}
 			for za0001 := range z.Columns {
-				var zb0003 uint32
-				zb0003, err = dc.ReadMapHeader()
+				err = z.Columns[za0001].DecodeMsg(dc)
 				if err != nil {
 					err = msgp.WrapError(err, "Columns", za0001)
 					return
 				}
-				for zb0003 > 0 {
-					zb0003--
-					field, err = dc.ReadMapKeyPtr()
-					if err != nil {
-						err = msgp.WrapError(err, "Columns", za0001)
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "column":
-						z.Columns[za0001].Value, err = dc.ReadIntf()
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001, "Value")
-							return
-						}
-					case "value-is-empty-bytes":
-						z.Columns[za0001].ValueIsEmptyBytes, err = dc.ReadBool()
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001, "ValueIsEmptyBytes")
-							return
-						}
-					case "flag":
-						z.Columns[za0001].Flag, err = dc.ReadUint64()
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001, "Flag")
-							return
-						}
-					default:
-						err = dc.Skip()
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001)
-							return
-						}
-					}
-				}
 			}
 		case "pre-columns":
-			var zb0004 uint32
-			zb0004, err = dc.ReadArrayHeader()
+			var zb0003 uint32
+			zb0003, err = dc.ReadArrayHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "PreColumns")
 				return
 			}
-			if cap(z.PreColumns) >= int(zb0004) {
-				z.PreColumns = (z.PreColumns)[:zb0004]
+			if cap(z.PreColumns) >= int(zb0003) {
+				z.PreColumns = (z.PreColumns)[:zb0003]
 			} else {
-				z.PreColumns = make([]RedoColumn, zb0004)
+				z.PreColumns = make([]RedoColumn, zb0003)
 			}
 			for za0002 := range z.PreColumns {
-				var zb0005 uint32
-				zb0005, err = dc.ReadMapHeader()
+				err = z.PreColumns[za0002].DecodeMsg(dc)
 				if err != nil {
 					err = msgp.WrapError(err, "PreColumns", za0002)
 					return
 				}
-				for zb0005 > 0 {
-					zb0005--
-					field, err = dc.ReadMapKeyPtr()
-					if err != nil {
-						err = msgp.WrapError(err, "PreColumns", za0002)
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "column":
-						z.PreColumns[za0002].Value, err = dc.ReadIntf()
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002, "Value")
-							return
-						}
-					case "value-is-empty-bytes":
-						z.PreColumns[za0002].ValueIsEmptyBytes, err = dc.ReadBool()
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002, "ValueIsEmptyBytes")
-							return
-						}
-					case "flag":
-						z.PreColumns[za0002].Flag, err = dc.ReadUint64()
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002, "Flag")
-							return
-						}
-					default:
-						err = dc.Skip()
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002)
-							return
-						}
-					}
-				}
 			}
 		default:
 			err = dc.Skip()
@@ -1255,35 +1235,9 @@ func (z *RedoRowChangedEvent) EncodeMsg(en *msgp.Writer) (err error) {
 		return
 	}
 	for za0001 := range z.Columns {
-		// map header, size 3
-		// write "column"
-		err = en.Append(0x83, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
-		if err != nil {
-			return
-		}
-		err = en.WriteIntf(z.Columns[za0001].Value)
+		err = z.Columns[za0001].EncodeMsg(en)
 		if err != nil {
-			err = msgp.WrapError(err, "Columns", za0001, "Value")
-			return
-		}
-		// write "value-is-empty-bytes"
-		err = en.Append(0xb4, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2d, 0x69, 0x73, 0x2d, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2d, 0x62, 0x79, 0x74, 0x65, 0x73)
-		if err != nil {
-			return
-		}
-		err = en.WriteBool(z.Columns[za0001].ValueIsEmptyBytes)
-		if err != nil {
-			err = msgp.WrapError(err, "Columns", za0001, "ValueIsEmptyBytes")
-			return
-		}
-		// write "flag"
-		err = en.Append(0xa4, 0x66, 0x6c, 0x61, 0x67)
-		if err != nil {
-			return
-		}
-		err = en.WriteUint64(z.Columns[za0001].Flag)
-		if err != nil {
-			err = msgp.WrapError(err, "Columns", za0001, "Flag")
+			err = msgp.WrapError(err, "Columns", za0001)
 			return
 		}
 	}
@@ -1298,35 +1252,9 @@ func (z *RedoRowChangedEvent) EncodeMsg(en *msgp.Writer) (err error) {
 		return
 	}
 	for za0002 := range z.PreColumns {
-		// map header, size 3
-		// write "column"
-		err = en.Append(0x83, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
-		if err != nil {
-			return
-		}
-		err = en.WriteIntf(z.PreColumns[za0002].Value)
+		err = z.PreColumns[za0002].EncodeMsg(en)
 		if err != nil {
-			err = msgp.WrapError(err, "PreColumns", za0002, "Value")
-			return
-		}
-		// write "value-is-empty-bytes"
-		err = en.Append(0xb4, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2d, 0x69, 0x73, 0x2d, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2d, 0x62, 0x79, 0x74, 0x65, 0x73)
-		if err != nil {
-			return
-		}
-		err = en.WriteBool(z.PreColumns[za0002].ValueIsEmptyBytes)
-		if err != nil {
-			err = msgp.WrapError(err, "PreColumns", za0002, "ValueIsEmptyBytes")
-			return
-		}
-		// write "flag"
-		err = en.Append(0xa4, 0x66, 0x6c, 0x61, 0x67)
-		if err != nil {
-			return
-		}
-		err = en.WriteUint64(z.PreColumns[za0002].Flag)
-		if err != nil {
-			err = msgp.WrapError(err, "PreColumns", za0002, "Flag")
+			err = msgp.WrapError(err, "PreColumns", za0002)
 			return
 		}
 	}
@@ -1352,39 +1280,21 @@ func (z *RedoRowChangedEvent) MarshalMsg(b []byte) (o []byte, err error) {
 	o = append(o, 0xa7, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73)
 	o = msgp.AppendArrayHeader(o, uint32(len(z.Columns)))
 	for za0001 := range z.Columns {
-		// map header, size 3
-		// string "column"
-		o = append(o, 0x83, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
-		o, err = msgp.AppendIntf(o, z.Columns[za0001].Value)
+		o, err = z.Columns[za0001].MarshalMsg(o)
 		if err != nil {
-			err = msgp.WrapError(err, "Columns", za0001, "Value")
+			err = msgp.WrapError(err, "Columns", za0001)
 			return
 		}
-		// string "value-is-empty-bytes"
-		o = append(o, 0xb4, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2d, 0x69, 0x73, 0x2d, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2d, 0x62, 0x79, 0x74, 0x65, 0x73)
-		o = msgp.AppendBool(o, z.Columns[za0001].ValueIsEmptyBytes)
-		// string "flag"
-		o = append(o, 0xa4, 0x66, 0x6c, 0x61, 0x67)
-		o = msgp.AppendUint64(o, z.Columns[za0001].Flag)
 	}
 	// string "pre-columns"
 	o = append(o, 0xab, 0x70, 0x72, 0x65, 0x2d, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73)
 	o = msgp.AppendArrayHeader(o, uint32(len(z.PreColumns)))
 	for za0002 := range z.PreColumns {
-		// map header, size 3
-		// string "column"
-		o = append(o, 0x83, 0xa6, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e)
-		o, err = msgp.AppendIntf(o, z.PreColumns[za0002].Value)
+		o, err = z.PreColumns[za0002].MarshalMsg(o)
 		if err != nil {
-			err = msgp.WrapError(err, "PreColumns", za0002, "Value")
+			err = msgp.WrapError(err, "PreColumns", za0002)
 			return
 		}
-		// string "value-is-empty-bytes"
-		o = append(o, 0xb4, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2d, 0x69, 0x73, 0x2d, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2d, 0x62, 0x79, 0x74, 0x65, 0x73)
-		o = msgp.AppendBool(o, z.PreColumns[za0002].ValueIsEmptyBytes)
-		// string "flag"
-		o = append(o, 0xa4, 0x66, 0x6c, 0x61, 0x67)
-		o = msgp.AppendUint64(o, z.PreColumns[za0002].Flag)
 	}
 	return
 }
@@ -1437,100 +1347,30 @@ func (z *RedoRowChangedEvent) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				z.Columns = make([]RedoColumn, zb0002)
 			}
 			for za0001 := range z.Columns {
-				var zb0003 uint32
-				zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+				bts, err = z.Columns[za0001].UnmarshalMsg(bts)
 				if err != nil {
 					err = msgp.WrapError(err, "Columns", za0001)
 					return
 				}
-				for zb0003 > 0 {
-					zb0003--
-					field, bts, err = msgp.ReadMapKeyZC(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "Columns", za0001)
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "column":
-						z.Columns[za0001].Value, bts, err = msgp.ReadIntfBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001, "Value")
-							return
-						}
-					case "value-is-empty-bytes":
-						z.Columns[za0001].ValueIsEmptyBytes, bts, err = msgp.ReadBoolBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001, "ValueIsEmptyBytes")
-							return
-						}
-					case "flag":
-						z.Columns[za0001].Flag, bts, err = msgp.ReadUint64Bytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001, "Flag")
-							return
-						}
-					default:
-						bts, err = msgp.Skip(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "Columns", za0001)
-							return
-						}
-					}
-				}
 			}
 		case "pre-columns":
-			var zb0004 uint32
-			zb0004, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "PreColumns")
 				return
 			}
-			if cap(z.PreColumns) >= int(zb0004) {
-				z.PreColumns = (z.PreColumns)[:zb0004]
+			if cap(z.PreColumns) >= int(zb0003) {
+				z.PreColumns = (z.PreColumns)[:zb0003]
 			} else {
-				z.PreColumns = make([]RedoColumn, zb0004)
+				z.PreColumns = make([]RedoColumn, zb0003)
 			}
 			for za0002 := range z.PreColumns {
-				var zb0005 uint32
-				zb0005, bts, err = msgp.ReadMapHeaderBytes(bts)
+				bts, err = z.PreColumns[za0002].UnmarshalMsg(bts)
 				if err != nil {
 					err = msgp.WrapError(err, "PreColumns", za0002)
 					return
 				}
-				for zb0005 > 0 {
-					zb0005--
-					field, bts, err = msgp.ReadMapKeyZC(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "PreColumns", za0002)
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "column":
-						z.PreColumns[za0002].Value, bts, err = msgp.ReadIntfBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002, "Value")
-							return
-						}
-					case "value-is-empty-bytes":
-						z.PreColumns[za0002].ValueIsEmptyBytes, bts, err = msgp.ReadBoolBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002, "ValueIsEmptyBytes")
-							return
-						}
-					case "flag":
-						z.PreColumns[za0002].Flag, bts, err = msgp.ReadUint64Bytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002, "Flag")
-							return
-						}
-					default:
-						bts, err = msgp.Skip(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "PreColumns", za0002)
-							return
-						}
-					}
-				}
 			}
 		default:
 			bts, err = msgp.Skip(bts)
@@ -1554,11 +1394,11 @@ func (z *RedoRowChangedEvent) Msgsize() (s int) {
 	}
 	s += 8 + msgp.ArrayHeaderSize
 	for za0001 := range z.Columns {
-		s += 1 + 7 + msgp.GuessSize(z.Columns[za0001].Value) + 21 + msgp.BoolSize + 5 + msgp.Uint64Size
+		s += z.Columns[za0001].Msgsize()
 	}
 	s += 12 + msgp.ArrayHeaderSize
 	for za0002 := range z.PreColumns {
-		s += 1 + 7 + msgp.GuessSize(z.PreColumns[za0002].Value) + 21 + msgp.BoolSize + 5 + msgp.Uint64Size
+		s += z.PreColumns[za0002].Msgsize()
 	}
 	return
 }
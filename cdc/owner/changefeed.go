@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
@@ -38,6 +39,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	redoCfg "github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/sink/observer"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/prometheus/client_golang/prometheus"
@@ -120,6 +122,16 @@ type changefeed struct {
 	downstreamObserver observer.Observer
 	observerLastTick   *atomic.Time
 
+	// checkNamespaceQuota checks whether the changefeed can be initialized
+	// under the quota of its namespace, it is set by the owner.
+	checkNamespaceQuota func() error
+	// namespaceCaptureLabels returns the capture labels of the namespace
+	// of the changefeed, it is set by the owner.
+	namespaceCaptureLabels func() map[string]string
+	// multiCapturesWarned is true if it has been warned that a changefeed
+	// requiring global level transaction atomicity runs with several captures.
+	multiCapturesWarned bool
+
 	newDDLPuller func(ctx context.Context,
 		replicaConfig *config.ReplicaConfig,
 		up *upstream.Upstream,
@@ -212,6 +224,7 @@ func (c *changefeed) Tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 		return nil
 	})
 	c.state.CheckCaptureAlive(ctx.GlobalVars().CaptureInfo.ID)
+	c.feedStateManager.historyEnabled = isHistoryEnabled(captures)
 	err := c.tick(ctx, captures)
 
 	// The tick duration is recorded only if changefeed has completed initialization
@@ -286,9 +299,16 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 		return nil
 	}
 
+	if !c.initialized && c.checkNamespaceQuota != nil {
+		if err := c.checkNamespaceQuota(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	if err := c.initialize(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	c.warnGlobalTxnAtomicity(captures)
 
 	select {
 	case err := <-c.errCh:
@@ -300,11 +320,20 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 	if !c.ddlSink.isInitialized() {
 		return nil
 	}
+	c.updateSnapshotProgress(checkpointTs)
+	// The decision on the held DDL is made by an operator and persisted
+	// in the changefeed status.
+	c.ddlManager.heldDDL = c.state.Status.HeldDDL
 	// TODO: pass table checkpointTs when we support concurrent process ddl
 	allPhysicalTables, minTableBarrierTs, barrier, err := c.ddlManager.tick(ctx, checkpointTs, nil)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	c.updateHeldDDL(c.ddlManager.heldDDL)
+	allPhysicalTables, wait := c.handleBackfill(checkpointTs, allPhysicalTables)
+	if wait {
+		return nil
+	}
 
 	otherBarrierTs, err := c.handleBarrier(ctx)
 	if err != nil {
@@ -446,6 +475,22 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 	return nil
 }
 
+// warnGlobalTxnAtomicity warns once when a changefeed requiring global level
+// transaction atomicity runs with several captures. Tables are pinned to one
+// capture, but they are moved one by one when the capture is drained or
+// fails, transactions spanning tables on different captures are split then.
+func (c *changefeed) warnGlobalTxnAtomicity(captures map[model.CaptureID]*model.CaptureInfo) {
+	multiCaptures := c.state.Info.Config.Sink.TxnAtomicity.IsGlobal() && len(captures) > 1
+	if multiCaptures && !c.multiCapturesWarned {
+		log.Warn("global level transaction atomicity is not guaranteed "+
+			"while tables are moved between captures",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Int("captures", len(captures)))
+	}
+	c.multiCapturesWarned = multiCaptures
+}
+
 func (c *changefeed) initialize(ctx cdcContext.Context) (err error) {
 	if c.initialized || c.state.Status == nil {
 		// If `c.state.Status` is nil it means the changefeed struct is just created, it needs to
@@ -638,11 +683,34 @@ LOOP:
 		c.redoDDLMgr,
 		c.redoMetaMgr,
 		downstreamType,
-		c.state.Info.Config.BDRMode)
+		c.state.Info.Config.BDRMode,
+		c.state.Info.Config.DDLApproval,
+		func(ddl *model.DDLEvent) {
+			c.feedStateManager.recordEvent(&model.ChangefeedEvent{
+				Type:     model.ChangefeedEventDDL,
+				Message:  ddl.Query,
+				CommitTs: ddl.CommitTs,
+			})
+		})
 
 	// create scheduler
 	cfg := *c.cfg
 	cfg.ChangefeedSettings = c.state.Info.Config.Scheduler
+	if c.namespaceCaptureLabels != nil {
+		if labels := c.namespaceCaptureLabels(); len(labels) != 0 {
+			cfg.ChangefeedSettings = cfg.ChangefeedSettings.WithCaptureLabels(labels)
+		}
+	}
+	if c.state.Info.Config.Sink.TxnAtomicity.IsGlobal() {
+		// Upstream transactions spanning multiple tables are assembled by the
+		// sink of a processor, so all tables are replicated by one capture.
+		settings := &config.ChangefeedSchedulerConfig{}
+		if cfg.ChangefeedSettings != nil {
+			*settings = *cfg.ChangefeedSettings
+		}
+		settings.SingleCapture = true
+		cfg.ChangefeedSettings = settings
+	}
 	epoch := c.state.Info.Epoch
 	c.scheduler, err = c.newScheduler(ctx, c.upstream, epoch, &cfg)
 	if err != nil {
@@ -835,6 +903,9 @@ func (c *changefeed) preflightCheck(captures map[model.CaptureID]*model.CaptureI
 						MinTableBarrierTs: c.state.Info.StartTs,
 						AdminJobType:      model.AdminNone,
 					}
+					if c.state.Info.Config.Snapshot.IsEnabled() {
+						status.Snapshot = &model.SnapshotProgress{SnapshotTs: c.state.Info.StartTs}
+					}
 					return status, true, nil
 				}
 				return status, false, nil
@@ -879,6 +950,9 @@ func (c *changefeed) preflightCheck(captures map[model.CaptureID]*model.CaptureI
 // handleBarrier calculates the barrierTs of the changefeed.
 // barrierTs is used to control the data that can be flush to downstream.
 func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
+	// The target ts can be updated when the changefeed is running,
+	// so the finish barrier must be refreshed in every tick.
+	c.barriers.Update(finishBarrier, c.state.Info.GetTargetTs())
 	barrierTp, barrierTs := c.barriers.Min()
 
 	c.metricsChangefeedBarrierTsGauge.Set(float64(oracle.ExtractPhysical(barrierTs)))
@@ -903,6 +977,11 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 		c.barriers.Update(syncPointBarrier, nextSyncPointTs)
 	case finishBarrier:
 		if fullyBlocked {
+			if c.state.Info.Config.OnFinish.NeedWriteMarker() {
+				if err := c.ddlSink.emitFinishMarker(ctx, barrierTs); err != nil {
+					return 0, errors.Trace(err)
+				}
+			}
 			c.feedStateManager.MarkFinished()
 		}
 		return barrierTs, nil
@@ -953,6 +1032,185 @@ func (c *changefeed) updateStatus(checkpointTs, resolvedTs, minTableBarrierTs mo
 		})
 }
 
+// updateSnapshotProgress summarizes the progress of the initial snapshot in
+// the changefeed status. Processors export the snapshot of each span before
+// it's replicated, so the progress of the span is held at the snapshot ts
+// until the export is finished, and DDLs after it are not executed until
+// then. The snapshot is finished after the checkpoint passes the snapshot ts.
+func (c *changefeed) updateSnapshotProgress(checkpointTs model.Ts) {
+	snapshot := c.state.Status.Snapshot
+	if snapshot == nil || snapshot.Finished {
+		return
+	}
+	progress := c.summarizeSnapshotProgress(snapshot)
+	progress.Finished = checkpointTs > progress.SnapshotTs
+	if *progress == *snapshot {
+		return
+	}
+	c.state.PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil || status.Snapshot == nil ||
+				status.Snapshot.SnapshotTs != progress.SnapshotTs {
+				return status, false, nil
+			}
+			status.Snapshot = progress
+			return status, true, nil
+		})
+	if progress.Finished {
+		log.Info("initial snapshot is finished",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Uint64("snapshotTs", progress.SnapshotTs),
+			zap.Int("spans", progress.DoneSpans),
+			zap.Uint64("rows", progress.ExportedRows))
+	}
+}
+
+// summarizeSnapshotProgress summarizes the progress of spans recorded in task
+// positions. A span is recorded by more than one capture if it is moved, the
+// furthest progress of it is used.
+func (c *changefeed) summarizeSnapshotProgress(
+	snapshot *model.SnapshotProgress,
+) *model.SnapshotProgress {
+	spans := spanz.NewHashMap[*model.SpanSnapshotProgress]()
+	for _, position := range c.state.TaskPositions {
+		for _, progress := range position.Snapshot {
+			if progress.SnapshotTs != snapshot.SnapshotTs {
+				continue
+			}
+			span := progress.Span()
+			last, ok := spans.Get(span)
+			if !ok || progress.Done || (!last.Done && progress.ExportedRows > last.ExportedRows) {
+				spans.ReplaceOrInsert(span, progress)
+			}
+		}
+	}
+	res := &model.SnapshotProgress{SnapshotTs: snapshot.SnapshotTs, Finished: snapshot.Finished}
+	spans.Range(func(_ tablepb.Span, progress *model.SpanSnapshotProgress) bool {
+		res.ExportedRows += progress.ExportedRows
+		if progress.Done {
+			res.DoneSpans++
+		}
+		return true
+	})
+	return res
+}
+
+// handleBackfill drives the backfill of a table. The table is left out of the
+// tables to schedule until no capture replicates it, and then it's added back
+// at the checkpoint, which is persisted as the snapshot ts. The processor
+// exports the snapshot of the table before it's replicated, so the progress
+// of the table is held at the snapshot ts until the export is finished. It
+// returns the tables to schedule, and true if tables should not be scheduled
+// until the snapshot ts is persisted.
+func (c *changefeed) handleBackfill(
+	checkpointTs model.Ts, allPhysicalTables []model.TableID,
+) ([]model.TableID, bool) {
+	backfill := c.state.Status.Backfill
+	if backfill == nil || backfill.IsFinished() {
+		return allPhysicalTables, false
+	}
+
+	if backfill.Snapshot != nil {
+		progress := c.summarizeSnapshotProgress(backfill.Snapshot)
+		// The checkpoint passes the snapshot ts after the table is
+		// replicating, which means its snapshot is exported.
+		progress.Finished = checkpointTs > progress.SnapshotTs
+		if *progress != *backfill.Snapshot {
+			c.patchBackfill(backfill.TableID, progress, false)
+		}
+		if progress.Finished {
+			log.Info("the snapshot of the backfilled table is exported",
+				zap.String("namespace", c.id.Namespace),
+				zap.String("changefeed", c.id.ID),
+				zap.Int64("tableID", backfill.TableID),
+				zap.Uint64("snapshotTs", backfill.Snapshot.SnapshotTs))
+			c.feedStateManager.recordEvent(&model.ChangefeedEvent{
+				Type: model.ChangefeedEventAdminJob,
+				Message: fmt.Sprintf("backfill table finished, tableID: %d",
+					backfill.TableID),
+				CommitTs: backfill.Snapshot.SnapshotTs,
+			})
+		}
+		return allPhysicalTables, false
+	}
+
+	tables := make([]model.TableID, 0, len(allPhysicalTables))
+	for _, tableID := range allPhysicalTables {
+		if tableID != backfill.TableID {
+			tables = append(tables, tableID)
+		}
+	}
+	if len(tables) == len(allPhysicalTables) {
+		log.Warn("the table to backfill is not replicated by the changefeed",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Int64("tableID", backfill.TableID))
+		c.patchBackfill(backfill.TableID, nil, true)
+		c.feedStateManager.recordEvent(&model.ChangefeedEvent{
+			Type: model.ChangefeedEventAdminJob,
+			Message: fmt.Sprintf("backfill table ignored, tableID: %d, "+
+				"it is not replicated", backfill.TableID),
+		})
+		return allPhysicalTables, false
+	}
+	// The table must be replicated from the snapshot ts after it's removed.
+	if !c.scheduler.IsTableRemoved(backfill.TableID) {
+		return tables, false
+	}
+	c.patchBackfill(backfill.TableID, &model.SnapshotProgress{SnapshotTs: checkpointTs}, false)
+	log.Info("start to backfill the table",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Int64("tableID", backfill.TableID),
+		zap.Uint64("snapshotTs", checkpointTs))
+	return tables, true
+}
+
+// patchBackfill updates the snapshot progress of the backfilled table in the
+// changefeed status, the backfill is removed if remove is true.
+func (c *changefeed) patchBackfill(
+	tableID model.TableID, progress *model.SnapshotProgress, remove bool,
+) {
+	c.state.PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil || status.Backfill == nil || status.Backfill.TableID != tableID {
+				return status, false, nil
+			}
+			if remove {
+				status.Backfill = nil
+			} else {
+				status.Backfill.Snapshot = progress
+			}
+			return status, true, nil
+		})
+}
+
+// updateHeldDDL persists the DDL held for approval in the changefeed status,
+// the decision on it is kept if it is the same DDL.
+func (c *changefeed) updateHeldDDL(heldDDL *model.HeldDDL) {
+	c.state.PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil {
+				return nil, false, nil
+			}
+			if heldDDL == nil {
+				if status.HeldDDL == nil {
+					return status, false, nil
+				}
+				status.HeldDDL = nil
+				return status, true, nil
+			}
+			if status.HeldDDL != nil &&
+				status.HeldDDL.CommitTs == heldDDL.CommitTs &&
+				status.HeldDDL.Query == heldDDL.Query {
+				return status, false, nil
+			}
+			status.HeldDDL = heldDDL
+			return status, true, nil
+		})
+}
+
 func (c *changefeed) Close(ctx cdcContext.Context) {
 	startTime := time.Now()
 	c.releaseResources(ctx)
@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/redo"
 	"github.com/pingcap/tiflow/pkg/sink/observer"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/stretchr/testify/require"
@@ -96,6 +97,7 @@ type mockDDLSink struct {
 	}
 	syncPoint    model.Ts
 	syncPointHis []model.Ts
+	finishMarker model.Ts
 
 	wg sync.WaitGroup
 }
@@ -132,6 +134,11 @@ func (m *mockDDLSink) emitSyncPoint(ctx context.Context, checkpointTs uint64) er
 	return nil
 }
 
+func (m *mockDDLSink) emitFinishMarker(ctx context.Context, targetTs uint64) error {
+	m.finishMarker = targetTs
+	return nil
+}
+
 func (m *mockDDLSink) emitCheckpointTs(ts uint64, tables []*model.TableInfo) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -159,7 +166,8 @@ func (m *mockDDLSink) Barrier(ctx context.Context) error {
 }
 
 type mockScheduler struct {
-	currentTables []model.TableID
+	currentTables   []model.TableID
+	drainingCapture model.CaptureID
 }
 
 func (m *mockScheduler) Tick(
@@ -180,10 +188,37 @@ func (m *mockScheduler) MoveTable(tableID model.TableID, target model.CaptureID)
 func (m *mockScheduler) Rebalance() {}
 
 // DrainCapture implement scheduler interface
-func (m *mockScheduler) DrainCapture(target model.CaptureID) (int, error) {
+func (m *mockScheduler) DrainCapture(
+	target model.CaptureID, deadline time.Time,
+) (int, error) {
 	return 0, nil
 }
 
+// DrainProgress implement scheduler interface
+func (m *mockScheduler) DrainProgress(target model.CaptureID) []model.DrainTableProgress {
+	return nil
+}
+
+// IsCaptureDraining implement scheduler interface
+func (m *mockScheduler) IsCaptureDraining(target model.CaptureID) bool {
+	return m.drainingCapture == target
+}
+
+// CancelDrainCapture implement scheduler interface
+func (m *mockScheduler) CancelDrainCapture(target model.CaptureID) bool {
+	return false
+}
+
+// IsTableRemoved implement scheduler interface
+func (m *mockScheduler) IsTableRemoved(tableID model.TableID) bool {
+	for _, id := range m.currentTables {
+		if id == tableID {
+			return false
+		}
+	}
+	return true
+}
+
 // Close closes the scheduler and releases resources.
 func (m *mockScheduler) Close(ctx context.Context) {}
 
@@ -306,6 +341,51 @@ func TestChangefeedHandleError(t *testing.T) {
 	require.Equal(t, cf.state.Info.Error.Message, "fake error")
 }
 
+func TestGlobalTxnAtomicityMultiCaptures(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx.ChangefeedVars().Info.Config.Sink.TxnAtomicity = config.AtomicityLevel("global")
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	var schedulerCfg *config.SchedulerConfig
+	cf.newScheduler = func(
+		ctx cdcContext.Context, up *upstream.Upstream, epoch uint64,
+		cfg *config.SchedulerConfig,
+	) (scheduler.Scheduler, error) {
+		schedulerCfg = cfg
+		return &mockScheduler{}, nil
+	}
+	// pre check
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	// initialize with one capture
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	require.Nil(t, cf.state.Info.Error)
+	require.True(t, cf.initialized)
+	// All tables are pinned to one capture by the scheduler.
+	require.True(t, schedulerCfg.ChangefeedSettings.SingleCapture)
+	require.False(t, cf.state.Info.Config.Scheduler.SingleCapture)
+	require.False(t, cf.multiCapturesWarned)
+
+	// The changefeed keeps replicating after a capture joins.
+	captures["capture-2"] = &model.CaptureInfo{ID: "capture-2"}
+	for i := 0; i < 3; i++ {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		require.Nil(t, cf.state.Info.Error)
+		require.Nil(t, cf.state.Info.Warning)
+		require.Equal(t, model.StateNormal, cf.state.Info.State)
+		require.True(t, cf.multiCapturesWarned)
+	}
+
+	// Warn again if another capture joins after the capture leaves.
+	delete(captures, "capture-2")
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	require.False(t, cf.multiCapturesWarned)
+}
+
 func TestExecDDL(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -388,6 +468,68 @@ func TestExecDDL(t *testing.T) {
 	require.Contains(t, cf.scheduler.(*mockScheduler).currentTables, job.TableID)
 }
 
+func TestExecDDLWithApproval(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	job := helper.DDL2Job("create database test0")
+	startTs := job.BinlogInfo.FinishedTS + 1000
+
+	ctx := cdcContext.NewContext4Test(context.Background(), true)
+	ctx.ChangefeedVars().Info.StartTs = startTs
+	ctx.ChangefeedVars().Info.Config.DDLApproval = &config.DDLApprovalConfig{
+		DDLTypes: []string{"create schema"},
+	}
+
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	cf.upstream.KVStorage = helper.Storage()
+	defer cf.Close(ctx)
+	tickThreeTime := func() {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	// pre check and initialize
+	tickThreeTime()
+
+	job = helper.DDL2Job("create database test1")
+	mockDDLPuller := cf.ddlManager.ddlPuller.(*mockDDLPuller)
+	mockDDLSink := cf.ddlManager.ddlSink.(*mockDDLSink)
+	mockDDLPuller.resolvedTs = startTs + 1000
+	job.BinlogInfo.FinishedTS = mockDDLPuller.resolvedTs
+	ddlCommitTs := job.BinlogInfo.FinishedTS
+	mockDDLPuller.ddlQueue = append(mockDDLPuller.ddlQueue, job)
+	tickThreeTime()
+	mockDDLPuller.resolvedTs += 1000
+	tickThreeTime()
+
+	// the DDL is held and the checkpoint is blocked at its commit ts
+	require.Equal(t, ddlCommitTs, cf.state.Status.CheckpointTs)
+	require.Nil(t, mockDDLSink.ddlExecuting)
+	heldDDL := cf.state.Status.HeldDDL
+	require.NotNil(t, heldDDL)
+	require.Equal(t, ddlCommitTs, heldDDL.CommitTs)
+	require.Equal(t, "create database test1", heldDDL.Query)
+	require.Nil(t, heldDDL.Decision)
+
+	// the DDL is executed after it is approved
+	cf.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.HeldDDL.Decision = &model.DDLDecision{Action: model.DDLDecisionApprove}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	tickThreeTime()
+	require.Equal(t, "create database test1", mockDDLSink.ddlExecuting.Query)
+	require.NotNil(t, cf.state.Status.HeldDDL)
+
+	mockDDLSink.ddlDone = true
+	tickThreeTime()
+	require.Equal(t, mockDDLPuller.resolvedTs, cf.state.Status.CheckpointTs)
+	require.Nil(t, cf.state.Status.HeldDDL)
+}
+
 func TestEmitCheckpointTs(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -512,6 +654,37 @@ func TestFinished(t *testing.T) {
 	require.Equal(t, cf.state.Info.State, model.StateFinished)
 }
 
+func TestFinishedWithOnFinishAction(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx.ChangefeedVars().Info.TargetTs = ctx.ChangefeedVars().Info.StartTs + 1000
+	ctx.ChangefeedVars().Info.Config.OnFinish = &config.OnFinishConfig{
+		Action:      config.FinishActionPause,
+		WriteMarker: true,
+	}
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+
+	// pre check
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	// initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	mockDDLSink := cf.ddlSink.(*mockDDLSink)
+	mockDDLPuller := cf.ddlManager.ddlPuller.(*mockDDLPuller)
+	mockDDLPuller.resolvedTs += 2000
+	// tick many times to make sure the change feed is stopped
+	for i := 0; i <= 10; i++ {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	require.Equal(t, cf.state.Status.CheckpointTs, cf.state.Info.TargetTs)
+	require.Equal(t, cf.state.Info.TargetTs, mockDDLSink.finishMarker)
+	require.Equal(t, model.StateStopped, cf.state.Info.State)
+}
+
 func TestRemoveChangefeed(t *testing.T) {
 	baseCtx, cancel := context.WithCancel(context.Background())
 	ctx := cdcContext.NewContext4Test(baseCtx, true)
@@ -636,3 +809,147 @@ func TestBarrierAdvance(t *testing.T) {
 		require.Less(t, cf.state.Info.StartTs+10, barrier)
 	}
 }
+
+func TestSnapshotMode(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.DDL2Job("create database test0")
+	job := helper.DDL2Job("create table test0.t1(id int primary key)")
+	tableID := job.TableID
+	startTs := job.BinlogInfo.FinishedTS + 1000
+
+	ctx := cdcContext.NewContext4Test(context.Background(), true)
+	ctx.ChangefeedVars().Info.StartTs = startTs
+	ctx.ChangefeedVars().Info.Config.Snapshot = &config.SnapshotConfig{Enable: true}
+
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	cf.upstream.KVStorage = helper.Storage()
+	defer cf.Close(ctx)
+	tickThreeTime := func() {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	// pre check and initialize
+	tickThreeTime()
+	require.Equal(t, startTs, cf.state.Status.Snapshot.SnapshotTs)
+
+	// tables are scheduled while the snapshot is being exported by
+	// processors, the progress of spans in task positions is summarized,
+	// and a span recorded by two captures is counted once
+	span := spanz.TableIDToComparableSpan(tableID)
+	exporting := model.NewSpanSnapshotProgress(span, startTs)
+	exporting.ExportedRows = 10
+	exported := model.NewSpanSnapshotProgress(span, startTs)
+	exported.ExportedRows = 20
+	exported.Done = true
+	other := model.NewSpanSnapshotProgress(spanz.TableIDToComparableSpan(tableID+1), startTs)
+	other.ExportedRows = 5
+	captures["capture-2"] = &model.CaptureInfo{ID: "capture-2"}
+	for captureID, progress := range map[model.CaptureID][]*model.SpanSnapshotProgress{
+		ctx.GlobalVars().CaptureInfo.ID: {exporting},
+		"capture-2":                     {exported, other},
+	} {
+		progress := progress
+		cf.state.PatchTaskPosition(captureID,
+			func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+				return &model.TaskPosition{Snapshot: progress}, true, nil
+			})
+	}
+	tester.MustApplyPatches()
+	mockDDLPuller := cf.ddlManager.ddlPuller.(*mockDDLPuller)
+	mockDDLPuller.resolvedTs = startTs
+	tickThreeTime()
+	require.Contains(t, cf.scheduler.(*mockScheduler).currentTables, tableID)
+	require.Equal(t, startTs, cf.state.Status.CheckpointTs)
+	require.Equal(t, &model.SnapshotProgress{
+		SnapshotTs: startTs, ExportedRows: 25, DoneSpans: 1,
+	}, cf.state.Status.Snapshot)
+
+	// the snapshot is finished after the checkpoint passes the snapshot ts
+	mockDDLPuller.resolvedTs = startTs + 1000
+	tickThreeTime()
+	require.Less(t, startTs, cf.state.Status.CheckpointTs)
+	require.True(t, cf.state.Status.Snapshot.Finished)
+}
+
+func TestBackfillTableOfChangefeed(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.DDL2Job("create database test0")
+	job := helper.DDL2Job("create table test0.t1(id int primary key)")
+	tableID := job.TableID
+	startTs := job.BinlogInfo.FinishedTS + 1000
+
+	ctx := cdcContext.NewContext4Test(context.Background(), true)
+	ctx.ChangefeedVars().Info.StartTs = startTs
+
+	// the history is recorded only if all captures can recognize it
+	ctx.GlobalVars().CaptureInfo.Version = "v7.2.0"
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	cf.upstream.KVStorage = helper.Storage()
+	defer cf.Close(ctx)
+	tick := func() {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	tickThreeTime := func() {
+		tick()
+		tick()
+		tick()
+	}
+	// pre check and initialize
+	tickThreeTime()
+	mockDDLPuller := cf.ddlManager.ddlPuller.(*mockDDLPuller)
+	mockScheduler := cf.scheduler.(*mockScheduler)
+	mockDDLPuller.resolvedTs = startTs + 1000
+	tickThreeTime()
+	snapshotTs := cf.state.Status.CheckpointTs
+	require.Equal(t, mockDDLPuller.resolvedTs, snapshotTs)
+	require.Contains(t, mockScheduler.currentTables, tableID)
+
+	// the table is removed from captures first
+	cf.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Backfill = &model.TableBackfill{TableID: tableID}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	tick()
+	require.NotContains(t, mockScheduler.currentTables, tableID)
+	require.Nil(t, cf.state.Status.Backfill.Snapshot)
+
+	// the checkpoint is persisted as the snapshot ts after the table is
+	// removed, and tables are not scheduled until it's persisted
+	tick()
+	require.NotContains(t, mockScheduler.currentTables, tableID)
+	require.Equal(t, snapshotTs, cf.state.Status.Backfill.Snapshot.SnapshotTs)
+
+	// the table is added again at the snapshot ts, the processor exports
+	// its snapshot before replicating it
+	tick()
+	require.Contains(t, mockScheduler.currentTables, tableID)
+	require.False(t, cf.state.Status.Backfill.IsFinished())
+	require.Equal(t, snapshotTs, cf.state.Status.CheckpointTs)
+
+	// the backfill is finished after the checkpoint passes the snapshot ts
+	mockDDLPuller.resolvedTs += 1000
+	tickThreeTime()
+	require.Contains(t, mockScheduler.currentTables, tableID)
+	require.True(t, cf.state.Status.Backfill.IsFinished())
+	require.Equal(t, mockDDLPuller.resolvedTs, cf.state.Status.CheckpointTs)
+	events := cf.state.History.Events
+	require.Equal(t, fmt.Sprintf("backfill table finished, tableID: %d", tableID),
+		events[len(events)-1].Message)
+
+	// a table which is not replicated is not backfilled
+	cf.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Backfill = &model.TableBackfill{TableID: tableID + 100}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	tickThreeTime()
+	require.Nil(t, cf.state.Status.Backfill)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/config"
 	"go.uber.org/zap"
 )
 
@@ -116,6 +117,15 @@ type ddlManager struct {
 	BDRMode       bool
 	sinkType      model.DownstreamType
 	ddlResolvedTs model.Ts
+
+	// ddlApproval is the DDL types held for manual approval before execution.
+	ddlApproval *config.DDLApprovalConfig
+	// heldDDL is the DDL held for manual approval, it is nil if no DDL is held.
+	// The changefeed syncs it with the changefeed status before and after
+	// each tick, so that the decision of an operator can be read from it.
+	heldDDL *model.HeldDDL
+	// onDDLExecuted is called after a DDL is executed in the downstream.
+	onDDLExecuted func(ddl *model.DDLEvent)
 }
 
 func newDDLManager(
@@ -129,6 +139,8 @@ func newDDLManager(
 	redoMetaManager redo.MetaManager,
 	sinkType model.DownstreamType,
 	bdrMode bool,
+	ddlApproval *config.DDLApprovalConfig,
+	onDDLExecuted func(ddl *model.DDLEvent),
 ) *ddlManager {
 	log.Info("create ddl manager",
 		zap.String("namaspace", changefeedID.Namespace),
@@ -153,6 +165,8 @@ func newDDLManager(
 		sinkType:        model.DB,
 		tableCheckpoint: make(map[model.TableName]model.Ts),
 		pendingDDLs:     make(map[model.TableName][]*model.DDLEvent),
+		ddlApproval:     ddlApproval,
+		onDDLExecuted:   onDDLExecuted,
 	}
 }
 
@@ -277,18 +291,20 @@ func (m *ddlManager) tick(
 		}
 
 		if m.shouldExecDDL(nextDDL) {
-			log.Info("execute a ddl event",
-				zap.String("query", nextDDL.Query),
-				zap.Uint64("commitTs", nextDDL.CommitTs),
-				zap.Uint64("checkpointTs", m.checkpointTs))
-
 			if m.executingDDL == nil {
-				m.executingDDL = nextDDL
+				m.executingDDL = m.approveDDL(nextDDL)
 			}
 
-			err := m.executeDDL(ctx)
-			if err != nil {
-				return nil, minTableBarrierTs, barrier, err
+			if m.executingDDL != nil {
+				log.Info("execute a ddl event",
+					zap.String("query", m.executingDDL.Query),
+					zap.Uint64("commitTs", m.executingDDL.CommitTs),
+					zap.Uint64("checkpointTs", m.checkpointTs))
+
+				err := m.executeDDL(ctx)
+				if err != nil {
+					return nil, minTableBarrierTs, barrier, err
+				}
 			}
 		}
 	}
@@ -356,23 +372,89 @@ func (m *ddlManager) executeDDL(ctx context.Context) error {
 		return err
 	}
 	if done {
-		tableName := m.executingDDL.TableInfo.TableName
 		log.Info("execute a ddl event successfully",
 			zap.String("ddl", m.executingDDL.Query),
 			zap.Uint64("commitTs", m.executingDDL.CommitTs),
-			zap.Stringer("table", tableName),
+			zap.Stringer("table", m.executingDDL.TableInfo.TableName),
 		)
-		// Set it to nil first to accelerate GC.
-		m.pendingDDLs[tableName][0] = nil
-		m.pendingDDLs[tableName] = m.pendingDDLs[tableName][1:]
-		m.schema.DoGC(m.executingDDL.CommitTs - 1)
 		m.justSentDDL = m.executingDDL
 		m.executingDDL = nil
-		m.cleanCache()
+		m.ddlFinished(m.justSentDDL)
+		m.onDDLExecuted(m.justSentDDL)
 	}
 	return nil
 }
 
+// ddlFinished removes a DDL which is executed or skipped from pendingDDLs.
+func (m *ddlManager) ddlFinished(ddl *model.DDLEvent) {
+	tableName := ddl.TableInfo.TableName
+	// Set it to nil first to accelerate GC.
+	m.pendingDDLs[tableName][0] = nil
+	m.pendingDDLs[tableName] = m.pendingDDLs[tableName][1:]
+	m.schema.DoGC(ddl.CommitTs - 1)
+	// The held DDL is always the finished one, because DDLs are only
+	// executed after the held DDL is handled.
+	m.heldDDL = nil
+	m.cleanCache()
+}
+
+// approveDDL checks whether the next DDL needs manual approval, and returns
+// the DDL to execute. It returns nil if the DDL is held or skipped.
+func (m *ddlManager) approveDDL(nextDDL *model.DDLEvent) *model.DDLEvent {
+	if m.heldDDL != nil && !m.heldDDL.IsHeldDDLOf(nextDDL) {
+		// The held DDL is not pending anymore, for example, the changefeed
+		// is resumed with a checkpoint ts larger than its commit ts.
+		log.Info("the held ddl is not pending anymore, drop it",
+			zap.String("namespace", m.changfeedID.Namespace),
+			zap.String("changefeed", m.changfeedID.ID),
+			zap.Any("heldDDL", m.heldDDL))
+		m.heldDDL = nil
+	}
+	if !m.ddlApproval.NeedApproval(nextDDL.Type) {
+		return nextDDL
+	}
+	if m.heldDDL == nil {
+		m.heldDDL = &model.HeldDDL{
+			CommitTs: nextDDL.CommitTs,
+			Type:     nextDDL.Type.String(),
+			Schema:   nextDDL.TableInfo.TableName.Schema,
+			Table:    nextDDL.TableInfo.TableName.Table,
+			Query:    nextDDL.Query,
+		}
+		log.Warn("a ddl is held for approval, it is not executed until "+
+			"it is approved, skipped or replaced",
+			zap.String("namespace", m.changfeedID.Namespace),
+			zap.String("changefeed", m.changfeedID.ID),
+			zap.String("query", nextDDL.Query),
+			zap.Uint64("commitTs", nextDDL.CommitTs))
+		return nil
+	}
+
+	decision := m.heldDDL.Decision
+	if decision == nil {
+		return nil
+	}
+	log.Info("handle the held ddl",
+		zap.String("namespace", m.changfeedID.Namespace),
+		zap.String("changefeed", m.changfeedID.ID),
+		zap.String("query", nextDDL.Query),
+		zap.Uint64("commitTs", nextDDL.CommitTs),
+		zap.Any("decision", decision))
+	switch decision.Action {
+	case model.DDLDecisionSkip:
+		m.ddlFinished(nextDDL)
+		return nil
+	case model.DDLDecisionReplace:
+		// Only the statement executed to the downstream is replaced,
+		// the schema is still changed by the upstream DDL.
+		replaced := *nextDDL
+		replaced.Query = decision.Query
+		return &replaced
+	default:
+		return nextDDL
+	}
+}
+
 // getNextDDL returns the next ddl event to execute.
 func (m *ddlManager) getNextDDL() *model.DDLEvent {
 	if m.executingDDL != nil {
@@ -386,7 +468,10 @@ func (m *ddlManager) getNextDDL() *model.DDLEvent {
 			delete(m.pendingDDLs, tb)
 			continue
 		}
-		if res == nil || res.CommitTs > ddls[0].CommitTs {
+		// Prefer the held DDL if there are DDLs with the same commit ts,
+		// so that other DDLs don't take its place.
+		if res == nil || res.CommitTs > ddls[0].CommitTs ||
+			(res.CommitTs == ddls[0].CommitTs && m.heldDDL.IsHeldDDLOf(ddls[0])) {
 			res = ddls[0]
 		}
 	}
@@ -14,6 +14,7 @@
 package owner
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -46,7 +47,7 @@ func createDDLManagerForTest(t *testing.T) *ddlManager {
 		ddlPuller,
 		schema,
 		nil, nil,
-		model.DB, false)
+		model.DB, false, nil, func(*model.DDLEvent) {})
 	return res
 }
 
@@ -451,3 +452,90 @@ func TestIsGlobalDDL(t *testing.T) {
 		require.Equal(t, c.ret, isGlobalDDL(c.ddl))
 	}
 }
+
+func TestApproveDDL(t *testing.T) {
+	dm := createDDLManagerForTest(t)
+	mockDDLSink := dm.ddlSink.(*mockDDLSink)
+	dm.ddlApproval = &config2.DDLApprovalConfig{DDLTypes: []string{"drop table"}}
+	addDDL := func(tableID int64, tableName string,
+		actionType timodel.ActionType, commitTs uint64, query string,
+	) *model.DDLEvent {
+		ddl := newFakeDDLEvent(tableID, tableName, actionType, commitTs)
+		ddl.Query = query
+		dm.pendingDDLs[ddl.TableInfo.TableName] = append(
+			dm.pendingDDLs[ddl.TableInfo.TableName], ddl)
+		return ddl
+	}
+
+	// a DDL which doesn't need approval is executed directly
+	ddl1 := addDDL(1, "test_1", timodel.ActionAddColumn, 1, "alter table test_1 add column c int")
+	require.Equal(t, ddl1, dm.approveDDL(ddl1))
+	require.Nil(t, dm.heldDDL)
+
+	// the DDL is held until an operator makes a decision
+	ddl2 := addDDL(2, "test_2", timodel.ActionDropTable, 2, "drop table test_2")
+	require.Nil(t, dm.approveDDL(ddl2))
+	require.Equal(t, &model.HeldDDL{
+		CommitTs: 2,
+		Type:     timodel.ActionDropTable.String(),
+		Table:    "test_2",
+		Query:    "drop table test_2",
+	}, dm.heldDDL)
+	require.Nil(t, dm.approveDDL(ddl2))
+
+	// the replaced statement is executed instead of the held DDL
+	dm.heldDDL = &model.HeldDDL{
+		CommitTs: 2,
+		Query:    "drop table test_2",
+		Decision: &model.DDLDecision{
+			Action: model.DDLDecisionReplace,
+			Query:  "rename table test_2 to test_2_dropped",
+		},
+	}
+	dm.executingDDL = dm.approveDDL(ddl2)
+	require.Equal(t, "rename table test_2 to test_2_dropped", dm.executingDDL.Query)
+	require.Equal(t, "drop table test_2", ddl2.Query)
+	var executed []string
+	dm.onDDLExecuted = func(ddl *model.DDLEvent) {
+		executed = append(executed, ddl.Query)
+	}
+	require.Nil(t, dm.executeDDL(context.Background()))
+	require.Empty(t, executed)
+	mockDDLSink.ddlDone = true
+	require.Nil(t, dm.executeDDL(context.Background()))
+	require.Equal(t, "rename table test_2 to test_2_dropped", mockDDLSink.ddlExecuting.Query)
+	// The executed statement is recorded once the DDL is finished.
+	require.Equal(t, []string{"rename table test_2 to test_2_dropped"}, executed)
+	require.Nil(t, dm.executingDDL)
+	require.Nil(t, dm.heldDDL)
+	require.Empty(t, dm.pendingDDLs[ddl2.TableInfo.TableName])
+
+	// the skipped DDL is removed without being executed
+	ddl3 := addDDL(3, "test_3", timodel.ActionDropTable, 3, "drop table test_3")
+	require.Nil(t, dm.approveDDL(ddl3))
+	dm.heldDDL = &model.HeldDDL{
+		CommitTs: 3,
+		Query:    "drop table test_3",
+		Decision: &model.DDLDecision{Action: model.DDLDecisionSkip},
+	}
+	dm.justSentDDL = nil
+	require.Nil(t, dm.approveDDL(ddl3))
+	require.Nil(t, dm.heldDDL)
+	require.Nil(t, dm.justSentDDL)
+	require.Empty(t, dm.pendingDDLs[ddl3.TableInfo.TableName])
+
+	// the held DDL is preferred among DDLs with the same commit ts
+	dm.pendingDDLs = make(map[model.TableName][]*model.DDLEvent)
+	addDDL(4, "test_4", timodel.ActionDropTable, 4, "drop table test_4")
+	ddl5 := addDDL(5, "test_5", timodel.ActionDropTable, 4, "drop table test_5")
+	dm.heldDDL = &model.HeldDDL{CommitTs: 4, Query: "drop table test_5"}
+	for i := 0; i < 10; i++ {
+		require.Equal(t, ddl5, dm.getNextDDL())
+	}
+
+	// a held DDL which is not pending anymore is dropped
+	dm.heldDDL = &model.HeldDDL{CommitTs: 1, Query: "drop table test_0"}
+	ddl6 := newFakeDDLEvent(6, "test_6", timodel.ActionAddColumn, 6)
+	require.Equal(t, ddl6, dm.approveDDL(ddl6))
+	require.Nil(t, dm.heldDDL)
+}
@@ -54,14 +54,18 @@ type DDLSink interface {
 	// the caller of this function can call again and again until a true returned
 	emitDDLEvent(ctx context.Context, ddl *model.DDLEvent) (bool, error)
 	emitSyncPoint(ctx context.Context, checkpointTs uint64) error
+	// emitFinishMarker writes a marker to downstream when the changefeed
+	// reaches its target ts.
+	emitFinishMarker(ctx context.Context, targetTs uint64) error
 	// close the ddlsink, cancel running goroutine.
 	close(ctx context.Context) error
 	isInitialized() bool
 }
 
 type ddlSinkImpl struct {
-	lastSyncPoint  model.Ts
-	syncPointStore syncpointstore.SyncPointStore
+	lastSyncPoint    model.Ts
+	lastFinishMarkTs model.Ts
+	syncPointStore   syncpointstore.SyncPointStore
 
 	// It is used to record the checkpointTs and the names of the table at that time.
 	mu struct {
@@ -124,20 +128,27 @@ func ddlSinkInitializer(ctx context.Context, a *ddlSinkImpl) error {
 	}
 	a.sink = s
 
-	if !a.info.Config.EnableSyncPoint {
+	// The finish marker is written as a syncpoint, so the syncpoint store
+	// is also needed in this case.
+	if !a.info.Config.EnableSyncPoint && !a.info.Config.OnFinish.NeedWriteMarker() {
 		return nil
 	}
+	failpoint.Inject("DDLSinkInitializeSlowly", func() {
+		time.Sleep(time.Second * 5)
+	})
+	return errors.Trace(a.initSyncPointStore(ctx))
+}
+
+func (s *ddlSinkImpl) initSyncPointStore(ctx context.Context) error {
 	syncPointStore, err := syncpointstore.NewSyncPointStore(
-		ctx, a.changefeedID, a.info.SinkURI, a.info.Config.SyncPointRetention)
+		ctx, s.changefeedID, s.info.SinkURI, s.info.Config.SyncPointRetention,
+		s.writeSyncPoint)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	failpoint.Inject("DDLSinkInitializeSlowly", func() {
-		time.Sleep(time.Second * 5)
-	})
-	a.syncPointStore = syncPointStore
+	s.syncPointStore = syncPointStore
 
-	if err := a.syncPointStore.CreateSyncTable(ctx); err != nil {
+	if err := s.syncPointStore.CreateSyncTable(ctx); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
@@ -331,6 +342,39 @@ func (s *ddlSinkImpl) emitSyncPoint(ctx context.Context, checkpointTs uint64) er
 	return s.syncPointStore.SinkSyncPoint(ctx, s.changefeedID, checkpointTs)
 }
 
+// writeSyncPoint writes a syncpoint marker to sinks which are not
+// MySQL-compatible, it's used by the syncpoint store.
+func (s *ddlSinkImpl) writeSyncPoint(ctx context.Context, ts uint64) error {
+	s.mu.Lock()
+	tables := s.mu.currentTables
+	s.mu.Unlock()
+	return s.sink.WriteSyncPoint(ctx, ts, tables)
+}
+
+// emitFinishMarker writes a syncpoint at the target ts, which is a row of
+// the syncpoint table for MySQL-compatible sinks, and a syncpoint marker for
+// MQ and storage sinks.
+func (s *ddlSinkImpl) emitFinishMarker(ctx context.Context, targetTs uint64) error {
+	if targetTs == s.lastFinishMarkTs {
+		return nil
+	}
+	// The marker may be enabled after the sink is initialized.
+	if s.syncPointStore == nil {
+		if err := s.initSyncPointStore(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := s.syncPointStore.SinkSyncPoint(ctx, s.changefeedID, targetTs); err != nil {
+		return errors.Trace(err)
+	}
+	s.lastFinishMarkTs = targetTs
+	log.Info("finish marker is written to downstream",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Uint64("targetTs", targetTs))
+	return nil
+}
+
 func (s *ddlSinkImpl) close(ctx context.Context) (err error) {
 	s.cancel()
 	// they will both be nil if changefeed return an error in initializing
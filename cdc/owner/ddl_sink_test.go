@@ -23,6 +23,8 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/ddlsink"
+	"github.com/pingcap/tiflow/cdc/syncpointstore"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/stretchr/testify/require"
@@ -31,6 +33,8 @@ import (
 type mockSink struct {
 	ddlsink.Sink
 	checkpointTs model.Ts
+	syncPointTs  model.Ts
+	tables       []*model.TableInfo
 	ddl          *model.DDLEvent
 	ddlMu        sync.Mutex
 	ddlError     error
@@ -51,6 +55,14 @@ func (m *mockSink) WriteCheckpointTs(ctx context.Context,
 	return nil
 }
 
+func (m *mockSink) WriteSyncPoint(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	atomic.StoreUint64(&m.syncPointTs, ts)
+	m.tables = tables
+	return nil
+}
+
 func (m *mockSink) Close() {}
 
 func (m *mockSink) GetDDL() *model.DDLEvent {
@@ -93,6 +105,50 @@ func TestCheckpoint(t *testing.T) {
 	require.Nil(t, waitCheckpointGrowingUp(mSink, 10))
 }
 
+func TestEmitSyncPointMarker(t *testing.T) {
+	ddlSink, mSink := newDDLSink4Test(func(err error) {})
+	s := ddlSink.(*ddlSinkImpl)
+	s.sink = mSink
+	s.info.SinkURI = "kafka://127.0.0.1:9092/test?protocol=open-protocol"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	syncPointStore, err := syncpointstore.NewSyncPointStore(ctx, s.changefeedID,
+		s.info.SinkURI, time.Hour, s.writeSyncPoint)
+	require.Nil(t, err)
+	s.syncPointStore = syncPointStore
+	tables := []*model.TableInfo{{TableName: model.TableName{Schema: "test", Table: "t"}}}
+	ddlSink.emitCheckpointTs(5, tables)
+
+	// The syncpoint marker is written by the ddl sink with current tables.
+	require.Nil(t, ddlSink.emitSyncPoint(ctx, 10))
+	require.Equal(t, uint64(10), atomic.LoadUint64(&mSink.syncPointTs))
+	require.Equal(t, tables, mSink.tables)
+}
+
+func TestEmitFinishMarker(t *testing.T) {
+	ddlSink, mSink := newDDLSink4Test(func(err error) {})
+	s := ddlSink.(*ddlSinkImpl)
+	s.sink = mSink
+	s.info.SinkURI = "kafka://127.0.0.1:9092/test?protocol=open-protocol"
+	s.info.Config = config.GetDefaultReplicaConfig()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tables := []*model.TableInfo{{TableName: model.TableName{Schema: "test", Table: "t"}}}
+	ddlSink.emitCheckpointTs(5, tables)
+
+	// The finish marker is a syncpoint marker rather than a checkpoint, the
+	// syncpoint store is created if the marker is enabled after the sink is
+	// initialized.
+	require.Nil(t, s.syncPointStore)
+	require.Nil(t, ddlSink.emitFinishMarker(ctx, 10))
+	require.NotNil(t, s.syncPointStore)
+	require.Equal(t, uint64(10), atomic.LoadUint64(&mSink.syncPointTs))
+	require.Equal(t, uint64(0), atomic.LoadUint64(&mSink.checkpointTs))
+	require.Equal(t, tables, mSink.tables)
+}
+
 func TestExecDDLEvents(t *testing.T) {
 	ddlSink, mSink := newDDLSink4Test(func(err error) {})
 
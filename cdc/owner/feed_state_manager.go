@@ -15,15 +15,18 @@ package owner
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
 	"go.uber.org/zap"
@@ -56,6 +59,9 @@ type feedStateManager struct {
 	// shouldBeRemoved = true means the changefeed is removed
 	// shouldBeRemoved = false means the changefeed is paused
 	shouldBeRemoved bool
+	// historyEnabled is false if some captures can not recognize the
+	// history key in etcd, it is updated by the changefeed on every tick.
+	historyEnabled bool
 
 	adminJobQueue   []*model.AdminJob
 	stateHistory    [defaultStateWindowSize]model.FeedState
@@ -115,6 +121,7 @@ func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adm
 	defer func() {
 		if m.shouldBeRunning {
 			m.patchState(model.StateNormal)
+			m.clearRecoveredWarning()
 		} else {
 			m.cleanUpInfos()
 		}
@@ -153,6 +160,25 @@ func (m *feedStateManager) ShouldRemoved() bool {
 	return m.shouldBeRemoved
 }
 
+// checkUpdateTarget returns an error if the job updates the target ts to a ts
+// not greater than the checkpoint ts of the changefeed, so that the job is
+// refused instead of being ignored when it's handled.
+func checkUpdateTarget(
+	state *orchestrator.ChangefeedReactorState, job *model.AdminJob,
+) error {
+	if job.Type != model.AdminUpdateTarget || job.TargetTs == 0 ||
+		state == nil || state.Info == nil {
+		return nil
+	}
+	checkpointTs := state.Info.GetCheckpointTs(state.Status)
+	if job.TargetTs <= checkpointTs {
+		return cerrors.ErrChangefeedUpdateRefused.GenWithStack(
+			"can not update target_ts:%d less than or equal to the checkpoint ts:%d",
+			job.TargetTs, checkpointTs)
+	}
+	return nil
+}
+
 func (m *feedStateManager) MarkFinished() {
 	if m.state == nil {
 		// when state is nil, it means that Tick has never been called
@@ -167,7 +193,8 @@ func (m *feedStateManager) MarkFinished() {
 
 func (m *feedStateManager) PushAdminJob(job *model.AdminJob) {
 	switch job.Type {
-	case model.AdminStop, model.AdminResume, model.AdminRemove:
+	case model.AdminStop, model.AdminResume, model.AdminRemove,
+		model.AdminUpdateTarget, model.AdminHandleDDL, model.AdminBackfillTable:
 	default:
 		log.Panic("Can not handle this job",
 			zap.String("namespace", m.state.ID.Namespace),
@@ -185,6 +212,7 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 	log.Info("handle admin job",
 		zap.String("namespace", m.state.ID.Namespace),
 		zap.String("changefeed", m.state.ID.ID), zap.Any("job", job))
+	m.recordAdminJob(job)
 	switch job.Type {
 	case model.AdminStop:
 		switch m.state.Info.State {
@@ -212,29 +240,8 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 			return
 		}
 
-		m.shouldBeRunning = false
-		m.shouldBeRemoved = true
 		jobsPending = true
-
-		// remove info
-		m.state.PatchInfo(func(info *model.ChangeFeedInfo) (
-			*model.ChangeFeedInfo, bool, error,
-		) {
-			return nil, true, nil
-		})
-		// remove changefeedStatus
-		m.state.PatchStatus(
-			func(status *model.ChangeFeedStatus) (
-				*model.ChangeFeedStatus, bool, error,
-			) {
-				return nil, true, nil
-			})
-		checkpointTs := m.state.Info.GetCheckpointTs(m.state.Status)
-
-		log.Info("the changefeed is removed",
-			zap.String("namespace", m.state.ID.Namespace),
-			zap.String("changefeed", m.state.ID.ID),
-			zap.Uint64("checkpointTs", checkpointTs))
+		m.removeChangefeed()
 	case model.AdminResume:
 		switch m.state.Info.State {
 		case model.StateFailed, model.StateError, model.StateStopped, model.StateFinished:
@@ -266,6 +273,10 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 				info.Error = nil
 				changed = true
 			}
+			if info.Warning != nil {
+				info.Warning = nil
+				changed = true
+			}
 			return info, changed, nil
 		})
 
@@ -303,7 +314,112 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		}
 		m.shouldBeRunning = false
 		jobsPending = true
-		m.patchState(model.StateFinished)
+		switch action := m.state.Info.Config.OnFinish.GetAction(); action {
+		case config.FinishActionPause:
+			m.patchState(model.StateStopped)
+		case config.FinishActionRemove:
+			m.removeChangefeed()
+		default:
+			m.patchState(model.StateFinished)
+		}
+	case model.AdminUpdateTarget:
+		switch m.state.Info.State {
+		case model.StateNormal, model.StateError:
+		default:
+			log.Warn("can not update the target of the changefeed in the current state",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID),
+				zap.String("changefeedState", string(m.state.Info.State)), zap.Any("job", job))
+			return
+		}
+		checkpointTs := m.state.Info.GetCheckpointTs(m.state.Status)
+		if job.TargetTs != 0 && job.TargetTs <= checkpointTs {
+			log.Warn("can not update the target ts to a ts less than the checkpoint ts",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID),
+				zap.Uint64("checkpointTs", checkpointTs), zap.Any("job", job))
+			return
+		}
+		m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			changed := false
+			if info == nil {
+				return nil, changed, nil
+			}
+			if job.TargetTs != 0 && info.TargetTs != job.TargetTs {
+				info.TargetTs = job.TargetTs
+				changed = true
+			}
+			if job.OnFinish != nil {
+				info.Config.OnFinish = job.OnFinish
+				changed = true
+			}
+			return info, changed, nil
+		})
+		log.Info("the changefeed target is updated",
+			zap.String("namespace", m.state.ID.Namespace),
+			zap.String("changefeed", m.state.ID.ID),
+			zap.Uint64("targetTs", job.TargetTs),
+			zap.Any("onFinish", job.OnFinish))
+	case model.AdminHandleDDL:
+		var heldDDL *model.HeldDDL
+		if m.state.Status != nil {
+			heldDDL = m.state.Status.HeldDDL
+		}
+		if heldDDL == nil || heldDDL.CommitTs != job.HeldDDLCommitTs ||
+			job.DDLDecision == nil {
+			log.Warn("can not handle the held ddl, it is not held anymore",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID),
+				zap.Any("heldDDL", heldDDL), zap.Any("job", job))
+			return
+		}
+		m.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil || status.HeldDDL == nil ||
+				status.HeldDDL.CommitTs != job.HeldDDLCommitTs {
+				return status, false, nil
+			}
+			status.HeldDDL.Decision = job.DDLDecision
+			return status, true, nil
+		})
+		log.Info("the decision on the held ddl is made",
+			zap.String("namespace", m.state.ID.Namespace),
+			zap.String("changefeed", m.state.ID.ID),
+			zap.Any("heldDDL", heldDDL),
+			zap.Any("decision", job.DDLDecision))
+	case model.AdminBackfillTable:
+		switch m.state.Info.State {
+		case model.StateNormal:
+		default:
+			log.Warn("can not backfill a table of the changefeed in the current state",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID),
+				zap.String("changefeedState", string(m.state.Info.State)), zap.Any("job", job))
+			return
+		}
+		if m.state.Status == nil || m.state.Status.Snapshot != nil && !m.state.Status.Snapshot.Finished {
+			log.Warn("can not backfill a table before the initial snapshot is exported",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID), zap.Any("job", job))
+			return
+		}
+		if backfill := m.state.Status.Backfill; backfill != nil && !backfill.IsFinished() {
+			log.Warn("can not backfill a table while another table is being backfilled",
+				zap.String("namespace", m.state.ID.Namespace),
+				zap.String("changefeed", m.state.ID.ID),
+				zap.Int64("backfillingTableID", backfill.TableID), zap.Any("job", job))
+			return
+		}
+		m.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			if status == nil {
+				return nil, false, nil
+			}
+			status.Backfill = &model.TableBackfill{TableID: job.BackfillTableID}
+			return status, true, nil
+		})
+		log.Info("the table is going to be backfilled",
+			zap.String("namespace", m.state.ID.Namespace),
+			zap.String("changefeed", m.state.ID.ID),
+			zap.Int64("tableID", job.BackfillTableID))
 	default:
 		log.Warn("Unknown admin job", zap.Any("adminJob", job),
 			zap.String("namespace", m.state.ID.Namespace),
@@ -312,6 +428,43 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 	return
 }
 
+// removeChangefeed removes the changefeed info and status from etcd.
+func (m *feedStateManager) removeChangefeed() {
+	m.shouldBeRunning = false
+	m.shouldBeRemoved = true
+
+	// remove info
+	m.state.PatchInfo(func(info *model.ChangeFeedInfo) (
+		*model.ChangeFeedInfo, bool, error,
+	) {
+		return nil, true, nil
+	})
+	// remove changefeedStatus
+	m.state.PatchStatus(
+		func(status *model.ChangeFeedStatus) (
+			*model.ChangeFeedStatus, bool, error,
+		) {
+			return nil, true, nil
+		})
+	// remove the history, it is useless once the changefeed is removed.
+	// A history left by a mixed-version cluster is removed by the owner
+	// after all captures are upgraded.
+	if m.historyEnabled {
+		m.state.PatchHistory(
+			func(history *model.ChangefeedHistory) (
+				*model.ChangefeedHistory, bool, error,
+			) {
+				return nil, history != nil, nil
+			})
+	}
+	checkpointTs := m.state.Info.GetCheckpointTs(m.state.Status)
+
+	log.Info("the changefeed is removed",
+		zap.String("namespace", m.state.ID.Namespace),
+		zap.String("changefeed", m.state.ID.ID),
+		zap.Uint64("checkpointTs", checkpointTs))
+}
+
 func (m *feedStateManager) popAdminJob() *model.AdminJob {
 	if len(m.adminJobQueue) == 0 {
 		return nil
@@ -344,6 +497,14 @@ func (m *feedStateManager) patchState(feedState model.FeedState) {
 	default:
 		log.Panic("Unreachable")
 	}
+	if m.state.Info != nil && m.state.Info.State != feedState {
+		m.recordEvent(&model.ChangefeedEvent{
+			Type: model.ChangefeedEventStateChanged,
+			Message: fmt.Sprintf("changefeed state is changed from %s to %s",
+				m.state.Info.State, feedState),
+			State: feedState,
+		})
+	}
 	m.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
 		if status == nil {
 			return status, false, nil
@@ -383,10 +544,21 @@ func (m *feedStateManager) patchState(feedState model.FeedState) {
 	})
 }
 
+// cleanUpInfos removes the task positions of the changefeed which should not
+// be running. The progress of snapshots is kept, so that exported spans are
+// not exported again after the changefeed runs again.
 func (m *feedStateManager) cleanUpInfos() {
 	for captureID := range m.state.TaskPositions {
 		m.state.PatchTaskPosition(captureID, func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
-			return nil, position != nil, nil
+			if position == nil {
+				return nil, false, nil
+			}
+			if len(position.Snapshot) == 0 {
+				return nil, true, nil
+			}
+			changed := position.CheckPointTs != 0 || position.ResolvedTs != 0 ||
+				position.Count != 0 || position.Error != nil
+			return &model.TaskPosition{Snapshot: position.Snapshot}, changed, nil
 		})
 	}
 }
@@ -423,6 +595,19 @@ func (m *feedStateManager) errorsReportedByProcessors() []*model.RunningError {
 }
 
 func (m *feedStateManager) handleError(errs ...*model.RunningError) {
+	for _, err := range errs {
+		// retryable errors are recorded as warnings
+		eventType := model.ChangefeedEventWarning
+		if cerrors.IsChangefeedFastFailErrorCode(errors.RFCErrorCode(err.Code)) ||
+			err.IsChangefeedUnRetryableError() {
+			eventType = model.ChangefeedEventError
+		}
+		m.recordEvent(&model.ChangefeedEvent{
+			Type:    eventType,
+			Message: err.Message,
+			Code:    err.Code,
+		})
+	}
 	// if there are a fastFail error in errs, we can just fastFail the changefeed
 	// and no need to patch other error to the changefeed info
 	for _, err := range errs {
@@ -488,6 +673,7 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 		if m.isChangefeedStable() {
 			m.resetErrBackoff()
 		}
+		m.patchWarning(errs[len(errs)-1])
 	} else {
 		if m.state.Info.State == model.StateNormal {
 			m.lastErrorTime = time.Unix(0, 0)
@@ -531,6 +717,112 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 	}
 }
 
+// patchWarning records the retryable error with the retry progress, so that
+// a changefeed being retried can be told apart from a stuck one.
+func (m *feedStateManager) patchWarning(err *model.RunningError) {
+	checkpointTs := m.state.Info.GetCheckpointTs(m.state.Status)
+	nextRetryTime := m.lastErrorTime.Add(m.backoffInterval)
+	m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		retryCount := 1
+		if info.Warning != nil {
+			retryCount = info.Warning.RetryCount + 1
+		}
+		info.Warning = &model.ChangefeedWarning{
+			Error:         err,
+			RetryCount:    retryCount,
+			NextRetryTime: nextRetryTime,
+			CheckpointTs:  checkpointTs,
+		}
+		return info, true, nil
+	})
+}
+
+// clearRecoveredWarning clears the warning of a running changefeed once its
+// checkpoint ts advances past the one recorded in the warning. The warning is
+// only metadata of the changefeed, the state of it is not changed.
+func (m *feedStateManager) clearRecoveredWarning() {
+	if m.state.Info == nil || m.state.Info.Warning == nil {
+		return
+	}
+	warning := m.state.Info.Warning
+	checkpointTs := m.state.Info.GetCheckpointTs(m.state.Status)
+	if checkpointTs <= warning.CheckpointTs {
+		return
+	}
+	m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.Warning == nil {
+			return info, false, nil
+		}
+		info.Warning = nil
+		return info, true, nil
+	})
+	log.Info("changefeed recovers from the retryable error",
+		zap.String("namespace", m.state.ID.Namespace),
+		zap.String("changefeed", m.state.ID.ID),
+		zap.Int("retryCount", warning.RetryCount),
+		zap.Uint64("checkpointTs", checkpointTs))
+}
+
+// recordAdminJob records an admin job handled by the owner in the history.
+func (m *feedStateManager) recordAdminJob(job *model.AdminJob) {
+	message := job.Type.String()
+	if job.Type == model.AdminUpdateTarget {
+		message = fmt.Sprintf("%s, targetTs: %d", message, job.TargetTs)
+		if job.OnFinish != nil {
+			message = fmt.Sprintf("%s, onFinish: %s", message, job.OnFinish.GetAction())
+		}
+	}
+	if job.Type == model.AdminHandleDDL && job.DDLDecision != nil {
+		message = fmt.Sprintf("%s, commitTs: %d, action: %s",
+			message, job.HeldDDLCommitTs, job.DDLDecision.Action)
+		if job.DDLDecision.Query != "" {
+			message = fmt.Sprintf("%s, query: %s", message, job.DDLDecision.Query)
+		}
+	}
+	if job.Type == model.AdminBackfillTable {
+		message = fmt.Sprintf("%s, tableID: %d", message, job.BackfillTableID)
+	}
+	m.recordEvent(&model.ChangefeedEvent{
+		Type:    model.ChangefeedEventAdminJob,
+		Message: message,
+	})
+}
+
+// recordEvent appends an event to the history of the changefeed,
+// the history is persisted in etcd so that it survives owner changes.
+func (m *feedStateManager) recordEvent(event *model.ChangefeedEvent) {
+	// the history is removed with the changefeed, do not recreate it.
+	if m.shouldBeRemoved || !m.historyEnabled {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	m.state.PatchHistory(func(history *model.ChangefeedHistory) (*model.ChangefeedHistory, bool, error) {
+		if history == nil {
+			history = &model.ChangefeedHistory{}
+		}
+		return history, history.Append(event), nil
+	})
+}
+
+// isHistoryEnabled returns true if all captures can recognize the history
+// key of changefeeds.
+func isHistoryEnabled(captures map[model.CaptureID]*model.CaptureInfo) bool {
+	versions := make([]string, 0, len(captures))
+	for _, capture := range captures {
+		versions = append(versions, capture.Version)
+	}
+	clusterVersion, err := version.GetTiCDCClusterVersion(versions)
+	if err != nil {
+		return false
+	}
+	return clusterVersion.ShouldRecordChangefeedHistory()
+}
+
 // GenerateChangefeedEpoch generates a unique changefeed epoch.
 func GenerateChangefeedEpoch(ctx context.Context, pdClient pd.Client) uint64 {
 	phyTs, logical, err := pdClient.GetTS(ctx)
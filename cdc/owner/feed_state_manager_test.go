@@ -26,6 +26,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/stretchr/testify/require"
 	pd "github.com/tikv/pd/client"
@@ -64,6 +65,7 @@ func newFeedStateManager4Test(
 
 	f.resetErrBackoff()
 	f.lastErrorTime = time.Unix(0, 0)
+	f.historyEnabled = true
 
 	return f
 }
@@ -252,6 +254,342 @@ func TestMarkFinished(t *testing.T) {
 	require.Equal(t, state.Status.AdminJobType, model.AdminFinish)
 }
 
+func TestMarkFinishedInWarningState(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{
+			SinkURI: "123", State: model.StateNormal, Config: &config.ReplicaConfig{},
+			Warning: &model.ChangefeedWarning{CheckpointTs: 100},
+		}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, model.StateWarning, state.Info.DisplayState())
+
+	manager.MarkFinished()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateFinished, state.Info.State)
+	require.Equal(t, model.AdminFinish, state.Info.AdminJobType)
+}
+
+func TestMarkFinishedWithOnFinishAction(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	for _, tc := range []struct {
+		action        string
+		expectedState model.FeedState
+		removed       bool
+	}{
+		{action: config.FinishActionPause, expectedState: model.StateStopped},
+		{action: config.FinishActionRemove, removed: true},
+	} {
+		manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+		state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+			ctx.ChangefeedVars().ID)
+		tester := orchestrator.NewReactorStateTester(t, state, nil)
+		state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			require.Nil(t, info)
+			return &model.ChangeFeedInfo{
+				SinkURI: "123",
+				Config: &config.ReplicaConfig{
+					OnFinish: &config.OnFinishConfig{Action: tc.action},
+				},
+			}, true, nil
+		})
+		state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			require.Nil(t, status)
+			return &model.ChangeFeedStatus{}, true, nil
+		})
+		tester.MustApplyPatches()
+		manager.Tick(state)
+		tester.MustApplyPatches()
+		require.True(t, manager.ShouldRunning())
+
+		manager.MarkFinished()
+		manager.Tick(state)
+		tester.MustApplyPatches()
+
+		require.False(t, manager.ShouldRunning())
+		require.Equal(t, tc.removed, manager.ShouldRemoved())
+		if tc.removed {
+			require.Nil(t, state.Info)
+			require.Nil(t, state.Status)
+			continue
+		}
+		require.Equal(t, tc.expectedState, state.Info.State)
+	}
+}
+
+func TestUpdateTarget(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{
+			SinkURI: "123", StartTs: 100, TargetTs: 200,
+			Config: config.GetDefaultReplicaConfig(),
+		}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{CheckpointTs: 150}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+
+	// the target ts can not be less than the checkpoint ts, the job is
+	// refused by the owner before it's pushed
+	job := &model.AdminJob{
+		CfID:     ctx.ChangefeedVars().ID,
+		Type:     model.AdminUpdateTarget,
+		TargetTs: 120,
+	}
+	err := checkUpdateTarget(state, job)
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(err))
+	require.Nil(t, checkUpdateTarget(state, &model.AdminJob{
+		CfID:     ctx.ChangefeedVars().ID,
+		Type:     model.AdminUpdateTarget,
+		TargetTs: 300,
+	}))
+	manager.PushAdminJob(job)
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, uint64(200), state.Info.TargetTs)
+
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:     ctx.ChangefeedVars().ID,
+		Type:     model.AdminUpdateTarget,
+		TargetTs: 300,
+		OnFinish: &config.OnFinishConfig{Action: config.FinishActionPause},
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, uint64(300), state.Info.TargetTs)
+	require.Equal(t, config.FinishActionPause, state.Info.Config.OnFinish.GetAction())
+}
+
+func TestHandleHeldDDL(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: config.GetDefaultReplicaConfig()}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{
+			CheckpointTs: 150,
+			HeldDDL:      &model.HeldDDL{CommitTs: 150, Query: "drop table t"},
+		}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	// the decision on a DDL which is not held is ignored
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:            ctx.ChangefeedVars().ID,
+		Type:            model.AdminHandleDDL,
+		HeldDDLCommitTs: 100,
+		DDLDecision:     &model.DDLDecision{Action: model.DDLDecisionApprove},
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Nil(t, state.Status.HeldDDL.Decision)
+
+	decision := &model.DDLDecision{
+		Action: model.DDLDecisionReplace,
+		Query:  "rename table t to t_dropped",
+	}
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:            ctx.ChangefeedVars().ID,
+		Type:            model.AdminHandleDDL,
+		HeldDDLCommitTs: 150,
+		DDLDecision:     decision,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, decision, state.Status.HeldDDL.Decision)
+	events := state.History.Events
+	require.Equal(t, "handle held ddl, commitTs: 150, action: replace, "+
+		"query: rename table t to t_dropped", events[len(events)-1].Message)
+}
+
+func TestBackfillTable(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: config.GetDefaultReplicaConfig()}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{
+			CheckpointTs: 150,
+			Backfill: &model.TableBackfill{
+				TableID:  1,
+				Snapshot: &model.SnapshotProgress{SnapshotTs: 100},
+			},
+		}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	// only one table can be backfilled at a time
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:            ctx.ChangefeedVars().ID,
+		Type:            model.AdminBackfillTable,
+		BackfillTableID: 2,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.EqualValues(t, 1, state.Status.Backfill.TableID)
+
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Backfill.Snapshot.Finished = true
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.PushAdminJob(&model.AdminJob{
+		CfID:            ctx.ChangefeedVars().ID,
+		Type:            model.AdminBackfillTable,
+		BackfillTableID: 2,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, &model.TableBackfill{TableID: 2}, state.Status.Backfill)
+	require.EqualValues(t, 150, state.Status.CheckpointTs)
+	events := state.History.Events
+	require.Equal(t, "backfill table, tableID: 2", events[len(events)-1].Message)
+}
+
+func TestChangefeedHistory(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminStop,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateStopped, state.Info.State)
+
+	// the error reported when the changefeed is stopped is recorded too
+	manager.handleError(&model.RunningError{
+		Code:    "CDC:ErrSinkURIInvalid",
+		Message: "sink uri invalid",
+	})
+	tester.MustApplyPatches()
+
+	events := state.History.Events
+	require.Len(t, events, 4)
+	require.Equal(t, model.ChangefeedEventStateChanged, events[0].Type)
+	require.Equal(t, model.StateNormal, events[0].State)
+	require.Equal(t, model.ChangefeedEventAdminJob, events[1].Type)
+	require.Equal(t, model.AdminStop.String(), events[1].Message)
+	require.Equal(t, model.ChangefeedEventStateChanged, events[2].Type)
+	require.Equal(t, model.StateStopped, events[2].State)
+	// a retryable error is recorded as a warning
+	require.Equal(t, model.ChangefeedEventWarning, events[3].Type)
+	require.Equal(t, "CDC:ErrSinkURIInvalid", events[3].Code)
+
+	// the history is removed with the changefeed
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminRemove,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Nil(t, state.History)
+	require.False(t, state.Exist())
+}
+
+func TestChangefeedHistoryDisabled(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	// some captures can not recognize the history key
+	manager.historyEnabled = false
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminStop,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateStopped, state.Info.State)
+	require.Nil(t, state.History)
+
+	require.False(t, isHistoryEnabled(map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {ID: "capture-1", Version: "v7.2.0"},
+		"capture-2": {ID: "capture-2", Version: "v7.1.0"},
+	}))
+	require.True(t, isHistoryEnabled(map[model.CaptureID]*model.CaptureInfo{
+		"capture-1": {ID: "capture-1", Version: "v7.2.0"},
+		"capture-2": {ID: "capture-2", Version: "v7.2.0-alpha-10-g1234567"},
+	}))
+}
+
 func TestCleanUpInfos(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
@@ -270,6 +608,16 @@ func TestCleanUpInfos(t *testing.T) {
 		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
 			return &model.TaskPosition{}, true, nil
 		})
+	snapshot := []*model.SpanSnapshotProgress{
+		model.NewSpanSnapshotProgress(spanz.TableIDToComparableSpan(1), 10),
+	}
+	state.PatchTaskPosition("capture-2",
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			return &model.TaskPosition{
+				CheckPointTs: 10,
+				Snapshot:     snapshot,
+			}, true, nil
+		})
 	tester.MustApplyPatches()
 	require.Contains(t, state.TaskPositions, ctx.GlobalVars().CaptureInfo.ID)
 	manager.Tick(state)
@@ -284,6 +632,8 @@ func TestCleanUpInfos(t *testing.T) {
 	require.Equal(t, state.Info.AdminJobType, model.AdminFinish)
 	require.Equal(t, state.Status.AdminJobType, model.AdminFinish)
 	require.NotContains(t, state.TaskPositions, ctx.GlobalVars().CaptureInfo.ID)
+	// the progress of snapshots is kept
+	require.Equal(t, &model.TaskPosition{Snapshot: snapshot}, state.TaskPositions["capture-2"])
 }
 
 func TestHandleError(t *testing.T) {
@@ -648,6 +998,73 @@ func TestBackoffNeverStops(t *testing.T) {
 	}
 }
 
+func TestChangefeedWarning(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(100, 100, 0, 1.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{CheckpointTs: 100}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	// a retryable error is reported, the changefeed is stopped as before and
+	// the warning is recorded.
+	state.PatchTaskPosition(ctx.GlobalVars().CaptureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			return &model.TaskPosition{Error: &model.RunningError{
+				Addr:    ctx.GlobalVars().CaptureInfo.AdvertiseAddr,
+				Code:    "[CDC:ErrEtcdSessionDone]",
+				Message: "fake error for test",
+			}}, true, nil
+		})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateError, state.Info.State)
+	require.Equal(t, model.StateWarning, state.Info.DisplayState())
+	require.NotNil(t, state.Info.Warning)
+	require.Equal(t, 1, state.Info.Warning.RetryCount)
+	require.Equal(t, uint64(100), state.Info.Warning.CheckpointTs)
+	require.Equal(t, "[CDC:ErrEtcdSessionDone]", state.Info.Warning.Error.Code)
+	require.False(t, state.Info.Warning.NextRetryTime.IsZero())
+
+	// the changefeed is restarted after backoff, the warning is kept.
+	time.Sleep(100 * time.Millisecond)
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, model.StateWarning, state.Info.DisplayState())
+
+	// the checkpoint doesn't advance, the warning is kept.
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.NotNil(t, state.Info.Warning)
+
+	// the checkpoint advances, the warning is cleared.
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 200
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateNormal, state.Info.State)
+	require.Equal(t, model.StateNormal, state.Info.DisplayState())
+	require.Nil(t, state.Info.Warning)
+	require.True(t, manager.ShouldRunning())
+}
+
 func TestUpdateChangefeedEpoch(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	// Set a long backoff time
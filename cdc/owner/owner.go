@@ -114,6 +114,12 @@ type ownerImpl struct {
 	// NOTICE: Do not use it in a method other than tick unexpectedly,
 	//         as it is not a thread-safe value.
 	changefeedTicked bool
+	// drainingCaptures are captures which are being drained or have been
+	// drained, they are removed once draining is canceled or the capture
+	// is offline. It's only used if no changefeed is scheduled, otherwise
+	// draining is derived from the capture liveness reported to schedulers,
+	// which is kept after the owner is changed.
+	drainingCaptures map[model.CaptureID]struct{}
 
 	newChangefeed func(
 		id model.ChangeFeedID,
@@ -155,6 +161,11 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	}
 
 	o.captures = state.Captures
+	for id := range o.drainingCaptures {
+		if _, ok := o.captures[id]; !ok {
+			delete(o.drainingCaptures, id)
+		}
+	}
 	o.updateMetrics()
 
 	// handleJobs() should be called before clusterVersionConsistent(), because
@@ -193,6 +204,15 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 				up = o.upstreamManager.AddUpstream(upstreamInfo)
 			}
 			cfReactor = o.newChangefeed(changefeedID, changefeedState, up, o.cfg)
+			cfReactor.checkNamespaceQuota = func() error {
+				return checkNamespaceQuota(state, changefeedID)
+			}
+			cfReactor.namespaceCaptureLabels = func() map[string]string {
+				if ns, ok := state.Namespaces[changefeedID.Namespace]; ok {
+					return ns.CaptureLabels
+				}
+				return nil
+			}
 			o.changefeeds[changefeedID] = cfReactor
 		}
 		ctx = cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
@@ -227,6 +247,36 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	return state, nil
 }
 
+// checkNamespaceQuota checks whether the changefeed can be admitted under the
+// quota of its namespace. Only changefeeds created before it are counted, so
+// that the existing changefeeds are not affected by the newly created ones.
+func checkNamespaceQuota(
+	state *orchestrator.GlobalReactorState, changefeedID model.ChangeFeedID,
+) error {
+	ns, ok := state.Namespaces[changefeedID.Namespace]
+	if !ok {
+		return nil
+	}
+	cfState, ok := state.Changefeeds[changefeedID]
+	if !ok || cfState.Info == nil {
+		return nil
+	}
+	info := cfState.Info
+	var others []*model.ChangeFeedInfo
+	for id, other := range state.Changefeeds {
+		if id == changefeedID || id.Namespace != changefeedID.Namespace ||
+			other.Info == nil {
+			continue
+		}
+		if other.Info.CreateTime.After(info.CreateTime) ||
+			(other.Info.CreateTime.Equal(info.CreateTime) && id.ID > changefeedID.ID) {
+			continue
+		}
+		others = append(others, other.Info)
+	}
+	return ns.CheckChangefeed(info, others)
+}
+
 // EnqueueJob enqueues an admin job into an internal queue,
 // and the Owner will handle the job in the next tick
 // `done` must be buffered to prevent blocking owner.
@@ -307,6 +357,11 @@ func (o *ownerImpl) cleanUpChangefeed(state *orchestrator.ChangefeedReactorState
 	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
 		return nil, status != nil, nil
 	})
+	if isHistoryEnabled(o.captures) {
+		state.PatchHistory(func(history *model.ChangefeedHistory) (*model.ChangefeedHistory, bool, error) {
+			return nil, history != nil, nil
+		})
+	}
 	for captureID := range state.TaskPositions {
 		state.PatchTaskPosition(captureID, func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
 			return nil, position != nil, nil
@@ -388,23 +443,27 @@ func (o *ownerImpl) clusterVersionConsistent(captures map[model.CaptureID]*model
 }
 
 func (o *ownerImpl) handleDrainCaptures(ctx context.Context, query *scheduler.Query, done chan<- error) {
-	if err := o.upstreamManager.Visit(func(upstream *upstream.Upstream) error {
-		if err := version.CheckStoreVersion(ctx, upstream.PDClient, 0); err != nil {
-			return errors.Trace(err)
+	if query.Tp == scheduler.DrainQueryStart {
+		if err := o.upstreamManager.Visit(func(upstream *upstream.Upstream) error {
+			if err := version.CheckStoreVersion(ctx, upstream.PDClient, 0); err != nil {
+				return errors.Trace(err)
+			}
+			return nil
+		}); err != nil {
+			log.Info("owner handle drain capture failed, since check upstream store version failed",
+				zap.String("target", query.CaptureID), zap.Error(err))
+			query.Resp = &model.DrainCaptureResp{CurrentTableCount: 0}
+			done <- err
+			close(done)
+			return
 		}
-		return nil
-	}); err != nil {
-		log.Info("owner handle drain capture failed, since check upstream store version failed",
-			zap.String("target", query.CaptureID), zap.Error(err))
-		query.Resp = &model.DrainCaptureResp{CurrentTableCount: 0}
-		done <- err
-		close(done)
-		return
 	}
 
 	var (
 		changefeedWithTableCount int
 		totalTableCount          int
+		tables                   []model.DrainTableProgress
+		draining                 bool
 		err                      error
 	)
 	for _, changefeed := range o.changefeeds {
@@ -430,19 +489,48 @@ func (o *ownerImpl) handleDrainCaptures(ctx context.Context, query *scheduler.Qu
 			totalTableCount++
 			continue
 		}
-		count, e := changefeed.scheduler.DrainCapture(query.CaptureID)
-		if e != nil {
-			err = e
+		var count int
+		switch query.Tp {
+		case scheduler.DrainQueryStart:
+			count, err = changefeed.scheduler.DrainCapture(query.CaptureID, query.Deadline)
+		case scheduler.DrainQueryCancel:
+			changefeed.scheduler.CancelDrainCapture(query.CaptureID)
+		}
+		if err != nil {
 			break
 		}
+		if changefeed.scheduler.IsCaptureDraining(query.CaptureID) {
+			draining = true
+		}
+		progress := changefeed.scheduler.DrainProgress(query.CaptureID)
+		if query.Tp != scheduler.DrainQueryStart {
+			count = len(progress)
+		}
+		tables = append(tables, progress...)
 		if count > 0 {
 			changefeedWithTableCount++
 		}
 		totalTableCount += count
 	}
 
+	if err == nil {
+		switch query.Tp {
+		case scheduler.DrainQueryStart:
+			if o.drainingCaptures == nil {
+				o.drainingCaptures = make(map[model.CaptureID]struct{})
+			}
+			o.drainingCaptures[query.CaptureID] = struct{}{}
+		case scheduler.DrainQueryCancel:
+			delete(o.drainingCaptures, query.CaptureID)
+		}
+	}
+	if _, ok := o.drainingCaptures[query.CaptureID]; ok {
+		draining = true
+	}
 	query.Resp = &model.DrainCaptureResp{
 		CurrentTableCount: totalTableCount,
+		Draining:          draining,
+		Tables:            tables,
 	}
 
 	if err != nil {
@@ -455,6 +543,8 @@ func (o *ownerImpl) handleDrainCaptures(ctx context.Context, query *scheduler.Qu
 
 	log.Info("owner handle drain capture",
 		zap.String("target", query.CaptureID),
+		zap.Int("queryType", int(query.Tp)),
+		zap.Time("deadline", query.Deadline),
 		zap.Int("changefeedWithTableCount", changefeedWithTableCount),
 		zap.Int("totalTableCount", totalTableCount))
 	close(done)
@@ -473,6 +563,10 @@ func (o *ownerImpl) handleJobs(ctx context.Context) {
 		}
 		switch job.Tp {
 		case ownerJobTypeAdminJob:
+			if err := checkUpdateTarget(cfReactor.state, job.AdminJob); err != nil {
+				job.done <- err
+				break
+			}
 			cfReactor.feedStateManager.PushAdminJob(job.AdminJob)
 		case ownerJobTypeScheduleTable:
 			// Scheduler is created lazily, it is nil before initialization.
@@ -511,6 +605,9 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			ret[cfID].ResolvedTs = cfReactor.state.Status.ResolvedTs
 			ret[cfID].CheckpointTs = cfReactor.state.Status.CheckpointTs
 			ret[cfID].AdminJobType = cfReactor.state.Status.AdminJobType
+			ret[cfID].HeldDDL = cfReactor.state.Status.HeldDDL
+			ret[cfID].Snapshot = cfReactor.state.Status.Snapshot
+			ret[cfID].Backfill = cfReactor.state.Status.Backfill
 		}
 		query.Data = ret
 	case QueryAllChangeFeedInfo:
@@ -580,6 +677,7 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 				ID:            captureInfo.ID,
 				AdvertiseAddr: captureInfo.AdvertiseAddr,
 				Version:       captureInfo.Version,
+				Labels:        captureInfo.Labels,
 			})
 		}
 		query.Data = ret
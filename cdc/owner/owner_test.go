@@ -718,6 +718,69 @@ func TestCalculateGCSafepointTs(t *testing.T) {
 	require.Equal(t, expectForceUpdateMap, forceUpdateMap)
 }
 
+func TestCheckNamespaceQuota(t *testing.T) {
+	t.Parallel()
+
+	state := orchestrator.NewGlobalState(etcd.DefaultCDCClusterID)
+	createTime := time.Now()
+	for i := 0; i < 3; i++ {
+		cfID := model.ChangeFeedID{Namespace: "team-a", ID: fmt.Sprintf("cf-%d", i)}
+		cfg := config.GetDefaultReplicaConfig()
+		cfg.MemoryQuota = 512
+		state.Changefeeds[cfID] = &orchestrator.ChangefeedReactorState{
+			ID: cfID,
+			Info: &model.ChangeFeedInfo{
+				ID:         cfID.ID,
+				Namespace:  cfID.Namespace,
+				SinkURI:    "kafka://127.0.0.1:9092/topic",
+				CreateTime: createTime.Add(time.Duration(i) * time.Second),
+				Config:     cfg,
+				State:      model.StateNormal,
+			},
+		}
+	}
+	cfID := model.DefaultChangeFeedID("cf-0")
+	state.Changefeeds[cfID] = &orchestrator.ChangefeedReactorState{
+		ID: cfID,
+		Info: &model.ChangeFeedInfo{
+			ID:         cfID.ID,
+			Namespace:  cfID.Namespace,
+			SinkURI:    "mysql://127.0.0.1:3306/",
+			CreateTime: createTime,
+			Config:     config.GetDefaultReplicaConfig(),
+			State:      model.StateNormal,
+		},
+	}
+
+	// namespaces without quota are not limited
+	for id := range state.Changefeeds {
+		require.Nil(t, checkNamespaceQuota(state, id))
+	}
+
+	// only the changefeeds created earlier are counted
+	state.Namespaces["team-a"] = &model.NamespaceInfo{Name: "team-a", MaxDeclaredMemoryQuota: 1024}
+	require.Nil(t, checkNamespaceQuota(state, model.ChangeFeedID{Namespace: "team-a", ID: "cf-0"}))
+	require.Nil(t, checkNamespaceQuota(state, model.ChangeFeedID{Namespace: "team-a", ID: "cf-1"}))
+	err := checkNamespaceQuota(state, model.ChangeFeedID{Namespace: "team-a", ID: "cf-2"})
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.True(t, cerror.IsChangefeedUnRetryableError(err))
+
+	// stopped changefeeds do not consume the memory quota
+	state.Changefeeds[model.ChangeFeedID{Namespace: "team-a", ID: "cf-0"}].Info.State = model.StateStopped
+	require.Nil(t, checkNamespaceQuota(state, model.ChangeFeedID{Namespace: "team-a", ID: "cf-2"}))
+
+	state.Namespaces["team-a"] = &model.NamespaceInfo{Name: "team-a", MaxChangefeeds: 2}
+	err = checkNamespaceQuota(state, model.ChangeFeedID{Namespace: "team-a", ID: "cf-2"})
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+
+	state.Namespaces["team-a"] = &model.NamespaceInfo{
+		Name: "team-a", AllowedSinkSchemes: []string{"mysql"},
+	}
+	err = checkNamespaceQuota(state, model.ChangeFeedID{Namespace: "team-a", ID: "cf-0"})
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.Nil(t, checkNamespaceQuota(state, cfID))
+}
+
 // AsyncStop should cleanup jobs and reject.
 func TestAsyncStop(t *testing.T) {
 	t.Parallel()
@@ -799,6 +862,57 @@ func TestHandleDrainCapturesSchedulerNotReady(t *testing.T) {
 	require.Nil(t, <-done)
 }
 
+func TestHandleDrainCapturesDraining(t *testing.T) {
+	t.Parallel()
+
+	pdClient := &gc.MockPDClient{
+		GetAllStoresFunc: func(
+			ctx context.Context, opts ...pd.GetStoreOption,
+		) ([]*metapb.Store, error) {
+			return nil, nil
+		},
+	}
+	o := &ownerImpl{
+		changefeeds:     make(map[model.ChangeFeedID]*changefeed),
+		upstreamManager: upstream.NewManager4Test(pdClient),
+	}
+	ctx := context.Background()
+	drain := func(tp scheduler.DrainQueryType) *model.DrainCaptureResp {
+		query := &scheduler.Query{CaptureID: "test", Tp: tp}
+		done := make(chan error, 1)
+		o.handleDrainCaptures(ctx, query, done)
+		require.Nil(t, <-done)
+		return query.Resp.(*model.DrainCaptureResp)
+	}
+
+	// The capture is not draining even if it has no table.
+	resp := drain(scheduler.DrainQueryProgress)
+	require.Equal(t, 0, resp.CurrentTableCount)
+	require.False(t, resp.Draining)
+
+	require.True(t, drain(scheduler.DrainQueryStart).Draining)
+	// Draining is kept after all tables are moved out.
+	require.True(t, drain(scheduler.DrainQueryProgress).Draining)
+	require.False(t, drain(scheduler.DrainQueryCancel).Draining)
+	require.False(t, drain(scheduler.DrainQueryProgress).Draining)
+
+	// The owner is changed, draining is reported by the scheduler, which
+	// derives it from the capture liveness.
+	o = &ownerImpl{
+		changefeeds:     make(map[model.ChangeFeedID]*changefeed),
+		upstreamManager: upstream.NewManager4Test(pdClient),
+	}
+	o.changefeeds[model.ChangeFeedID{}] = &changefeed{
+		scheduler: &mockScheduler{drainingCapture: "test"},
+		state: &orchestrator.ChangefeedReactorState{
+			Info: &model.ChangeFeedInfo{State: model.StateNormal},
+		},
+	}
+	resp = drain(scheduler.DrainQueryProgress)
+	require.Equal(t, 0, resp.CurrentTableCount)
+	require.True(t, resp.Draining)
+}
+
 type healthScheduler struct {
 	scheduler.Scheduler
 	scheduler.InfoProvider
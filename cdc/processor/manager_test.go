@@ -292,3 +292,73 @@ func TestManagerLiveness(t *testing.T) {
 	s.liveness.Store(model.LivenessCaptureStopping)
 	require.Equal(t, model.LivenessCaptureStopping, p.liveness.Load())
 }
+
+func TestManagerRecreateProcessorWithWarning(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(false)
+	s := &managerTester{}
+	s.resetSuit(ctx, t)
+
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+	s.state.Changefeeds[changefeedID] = orchestrator.NewChangefeedReactorState(
+		etcd.DefaultCDCClusterID, changefeedID)
+	s.state.Changefeeds[changefeedID].PatchInfo(
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			return &model.ChangeFeedInfo{
+				SinkURI:    "blackhole://",
+				CreateTime: time.Now(),
+				StartTs:    0,
+				TargetTs:   math.MaxUint64,
+				Config:     config.GetDefaultReplicaConfig(),
+			}, true, nil
+		})
+	s.state.Changefeeds[changefeedID].PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			return &model.ChangeFeedStatus{}, true, nil
+		})
+	s.tester.MustApplyPatches()
+	_, err := s.manager.Tick(ctx, s.state)
+	s.tester.MustApplyPatches()
+	require.Nil(t, err)
+	require.Len(t, s.manager.processors, 1)
+
+	// the owner stops the changefeed due to a retryable error, and records
+	// the error as the warning of the changefeed.
+	s.state.Changefeeds[changefeedID].PatchInfo(
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			info.State = model.StateError
+			info.Warning = &model.ChangefeedWarning{
+				RetryCount:    1,
+				NextRetryTime: time.Now(),
+			}
+			return info, true, nil
+		})
+	s.state.Changefeeds[changefeedID].PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			status.AdminJobType = model.AdminStop
+			return status, true, nil
+		})
+	s.tester.MustApplyPatches()
+	_, err = s.manager.Tick(ctx, s.state)
+	s.tester.MustApplyPatches()
+	require.Nil(t, err)
+	require.Len(t, s.manager.processors, 0)
+
+	// the warning is cleared before the changefeed is restarted, the
+	// processor is created again whatever the warning is.
+	s.state.Changefeeds[changefeedID].PatchInfo(
+		func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+			info.State = model.StateNormal
+			info.Warning = nil
+			return info, true, nil
+		})
+	s.state.Changefeeds[changefeedID].PatchStatus(
+		func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+			status.AdminJobType = model.AdminNone
+			return status, true, nil
+		})
+	s.tester.MustApplyPatches()
+	_, err = s.manager.Tick(ctx, s.state)
+	s.tester.MustApplyPatches()
+	require.Nil(t, err)
+	require.Len(t, s.manager.processors, 1)
+}
@@ -115,6 +115,20 @@ func (m *MemQuota) TryAcquire(nBytes uint64) bool {
 	}
 }
 
+// TryAcquireWithReserved is like TryAcquire, but it keeps reservedBytes of
+// the memory quota available for other acquirers.
+func (m *MemQuota) TryAcquireWithReserved(nBytes, reservedBytes uint64) bool {
+	for {
+		usedBytes := m.usedBytes.Load()
+		if usedBytes+nBytes+reservedBytes > m.totalBytes {
+			return false
+		}
+		if m.usedBytes.CompareAndSwap(usedBytes, usedBytes+nBytes) {
+			return true
+		}
+	}
+}
+
 // ForceAcquire is used to force acquire the memory quota.
 func (m *MemQuota) ForceAcquire(nBytes uint64) {
 	m.usedBytes.Add(nBytes)
@@ -34,6 +34,20 @@ func TestMemQuotaTryAcquire(t *testing.T) {
 	require.False(t, m.TryAcquire(1))
 }
 
+func TestMemQuotaTryAcquireWithReserved(t *testing.T) {
+	t.Parallel()
+
+	m := NewMemQuota(model.DefaultChangeFeedID("1"), 100, "")
+	defer m.Close()
+
+	require.True(t, m.TryAcquireWithReserved(50, 20))
+	require.False(t, m.TryAcquireWithReserved(40, 20))
+	require.True(t, m.TryAcquireWithReserved(30, 20))
+	// The reserved quota is still available without reservation.
+	require.True(t, m.TryAcquire(20))
+	require.False(t, m.TryAcquire(1))
+}
+
 func TestMemQuotaForceAcquire(t *testing.T) {
 	t.Parallel()
 
@@ -42,6 +42,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -64,7 +65,8 @@ type processor struct {
 	upstream     *upstream.Upstream
 	lastSchemaTs model.Ts
 
-	filter filter.Filter
+	filter   filter.Filter
+	timezone *time.Location
 
 	// To manager DDL events and schema storage.
 	ddlHandler component[*ddlHandler]
@@ -77,6 +79,15 @@ type processor struct {
 
 	sinkManager component[*sinkmanager.SinkManager]
 
+	// snapshots are spans whose snapshots are being exported, see
+	// snapshotTableSpan.
+	snapshots *spanz.HashMap[*spanSnapshot]
+	// snapshotProgress is the progress of exports which is not recorded in
+	// the task position yet.
+	snapshotProgress    *spanz.HashMap[*model.SpanSnapshotProgress]
+	snapshotPersistedAt time.Time
+	newSnapshotExporter func(progress *model.SpanSnapshotProgress) (snapshotExporter, error)
+
 	initialized bool
 
 	lazyInit func(ctx cdcContext.Context) error
@@ -171,6 +182,10 @@ func (p *processor) AddTableSpan(
 		}
 	}
 
+	if ok, err := p.snapshotTableSpan(span, startTs); !ok || err != nil {
+		return false, errors.Trace(err)
+	}
+
 	// table not found, can happen in 2 cases
 	// 1. this is a new table scheduling request, create the table and make it `replicating`
 	// 2. `prepare` phase for 2 phase scheduling, create the table and make it `preparing`
@@ -201,6 +216,7 @@ func (p *processor) RemoveTableSpan(span tablepb.Span) bool {
 		return false
 	}
 
+	p.stopSnapshot(span)
 	_, exist := p.sinkManager.r.GetTableState(span)
 	if !exist {
 		log.Warn("Table which will be deleted is not found",
@@ -378,6 +394,8 @@ func (p *processor) getStatsFromSourceManagerAndSinkManager(
 		},
 	}
 
+	stats.EventRate, stats.ByteRate = p.sinkManager.r.SampleTableReceivedRates(span)
+
 	sortStats := p.sourceManager.r.GetTableSorterStats(span)
 	stats.StageCheckpoints["sorter-ingress"] = tablepb.Checkpoint{
 		CheckpointTs: sortStats.ReceivedMaxCommitTs,
@@ -402,12 +420,14 @@ func newProcessor(
 	cfg *config.SchedulerConfig,
 ) *processor {
 	p := &processor{
-		changefeed:      state,
-		upstream:        up,
-		changefeedID:    changefeedID,
-		captureInfo:     captureInfo,
-		liveness:        liveness,
-		changefeedEpoch: changefeedEpoch,
+		changefeed:       state,
+		upstream:         up,
+		changefeedID:     changefeedID,
+		captureInfo:      captureInfo,
+		liveness:         liveness,
+		changefeedEpoch:  changefeedEpoch,
+		snapshots:        spanz.NewHashMap[*spanSnapshot](),
+		snapshotProgress: spanz.NewHashMap[*model.SpanSnapshotProgress](),
 
 		metricSyncTableNumGauge: syncTableNumGauge.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
@@ -424,6 +444,7 @@ func newProcessor(
 	}
 	p.lazyInit = p.lazyInitImpl
 	p.newAgent = p.newAgentImpl
+	p.newSnapshotExporter = p.newSnapshotExporterImpl
 	p.cfg = cfg
 	return p
 }
@@ -564,6 +585,7 @@ func (p *processor) tick(ctx cdcContext.Context) error {
 		p.updateBarrierTs(barrier)
 	}
 	p.doGCSchemaStorage()
+	p.updateSnapshotProgress()
 
 	return nil
 }
@@ -618,6 +640,7 @@ func (p *processor) lazyInitImpl(etcdCtx cdcContext.Context) (err error) {
 	stdCtx = contextutil.PutCaptureAddrInCtx(stdCtx, p.globalVars.CaptureInfo.AdvertiseAddr)
 
 	tz := contextutil.TimezoneFromCtx(stdCtx)
+	p.timezone = tz
 	p.filter, err = filter.NewFilter(p.changefeed.Info.Config, util.GetTimeZoneName(tz))
 	if err != nil {
 		return errors.Trace(err)
@@ -883,6 +906,10 @@ func (p *processor) Close() error {
 		zap.String("namespace", p.changefeedID.Namespace),
 		zap.String("changefeed", p.changefeedID.ID))
 
+	p.snapshots.Range(func(span tablepb.Span, _ *spanSnapshot) bool {
+		p.stopSnapshot(span)
+		return true
+	})
 	p.sinkManager.stop(p.changefeedID)
 	p.sinkManager.r = nil
 	p.sourceManager.stop(p.changefeedID)
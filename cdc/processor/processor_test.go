@@ -20,6 +20,7 @@ import (
 	"math"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -642,3 +643,197 @@ func TestProcessorDostNotStuckInInit(t *testing.T) {
 	require.Nil(t, p.Close())
 	tester.MustApplyPatches()
 }
+
+type mockSnapshotExporter struct {
+	progress *model.SpanSnapshotProgress
+	exported chan<- *mockSnapshotExporter
+	resultCh chan error
+}
+
+func (e *mockSnapshotExporter) Run(ctx context.Context) error {
+	e.exported <- e
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-e.resultCh:
+		if err == nil {
+			e.progress.Done = true
+		}
+		return err
+	}
+}
+
+func (e *mockSnapshotExporter) Progress() *model.SpanSnapshotProgress {
+	return e.progress.Clone()
+}
+
+func initSnapshotProcessor4Test(
+	ctx cdcContext.Context, t *testing.T,
+) (*processor, *orchestrator.ReactorStateTester, chan *mockSnapshotExporter) {
+	liveness := model.LivenessCaptureAlive
+	p, tester := initProcessor4Test(ctx, t, &liveness)
+	// init tick
+	require.Nil(t, p.Tick(ctx))
+	tester.MustApplyPatches()
+	// Do a no operation tick to lazy init the processor.
+	require.Nil(t, p.Tick(ctx))
+	tester.MustApplyPatches()
+
+	exported := make(chan *mockSnapshotExporter, 16)
+	p.newSnapshotExporter = func(progress *model.SpanSnapshotProgress) (snapshotExporter, error) {
+		return &mockSnapshotExporter{
+			progress: progress, exported: exported, resultCh: make(chan error, 1),
+		}, nil
+	}
+	return p, tester, exported
+}
+
+func TestBackfillTableSpan(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	p, tester, exported := initSnapshotProcessor4Test(ctx, t)
+	snapshotRetryIntervalBak := snapshotRetryInterval
+	snapshotRetryInterval = 0
+	defer func() {
+		snapshotRetryInterval = snapshotRetryIntervalBak
+	}()
+
+	// the backfilled table waits for the snapshot ts, other tables are
+	// added normally
+	span := spanz.TableIDToComparableSpan(1)
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Backfill = &model.TableBackfill{TableID: 1}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	done, err := p.AddTableSpan(ctx, span, 20, false)
+	require.Nil(t, err)
+	require.False(t, done)
+	done, err = p.AddTableSpan(ctx, spanz.TableIDToComparableSpan(2), 20, false)
+	require.Nil(t, err)
+	require.True(t, done)
+
+	// the span is added after its snapshot is exported, it's exported
+	// again if the export fails
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Backfill.Snapshot = &model.SnapshotProgress{SnapshotTs: 20}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	done, err = p.AddTableSpan(ctx, span, 20, false)
+	require.Nil(t, err)
+	require.False(t, done)
+	exporter := <-exported
+	require.Equal(t, span, exporter.progress.Span())
+	require.Equal(t, model.Ts(20), exporter.progress.SnapshotTs)
+	exporter.resultCh <- errors.New("fake error")
+	require.Eventually(t, func() bool {
+		done, err = p.AddTableSpan(ctx, span, 20, false)
+		require.Nil(t, err)
+		require.False(t, done)
+		return len(exported) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	exporter = <-exported
+	require.Equal(t, span, exporter.progress.Span())
+	_, ok := p.sinkManager.r.GetTableState(span)
+	require.False(t, ok)
+	exporter.resultCh <- nil
+	require.Eventually(t, func() bool {
+		done, err = p.AddTableSpan(ctx, span, 20, false)
+		require.Nil(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	_, ok = p.sinkManager.r.GetTableState(span)
+	require.True(t, ok)
+
+	// spans added at other ts are not exported
+	done, err = p.AddTableSpan(ctx, spanz.TableIDToComparableSpan(1), 30, false)
+	require.Nil(t, err)
+	require.True(t, done)
+
+	// the export is stopped if the processor is closed
+	span = tablepb.Span{TableID: 1, StartKey: []byte{1}, EndKey: []byte{2}}
+	done, err = p.AddTableSpan(ctx, span, 20, false)
+	require.Nil(t, err)
+	require.False(t, done)
+	require.Equal(t, span, (<-exported).progress.Span())
+	require.Nil(t, p.Close())
+	require.Equal(t, 0, p.snapshots.Len())
+}
+
+func TestInitialSnapshotTableSpan(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	p, tester, exported := initSnapshotProcessor4Test(ctx, t)
+	snapshotPersistIntervalBak := snapshotPersistInterval
+	snapshotPersistInterval = 0
+	defer func() {
+		snapshotPersistInterval = snapshotPersistIntervalBak
+	}()
+
+	// span 1 is partially exported and span 2 is exported by another capture
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Snapshot = &model.SnapshotProgress{SnapshotTs: 20}
+		return status, true, nil
+	})
+	p.changefeed.PatchTaskPosition("capture-2",
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			progress1 := model.NewSpanSnapshotProgress(span1, 20)
+			progress1.ResumeKey = []byte{1}
+			progress1.ExportedRows = 10
+			progress2 := model.NewSpanSnapshotProgress(span2, 20)
+			progress2.Done = true
+			return &model.TaskPosition{
+				Snapshot: []*model.SpanSnapshotProgress{progress1, progress2},
+			}, true, nil
+		})
+	tester.MustApplyPatches()
+
+	// span 1 is resumed from the progress of the other capture
+	done, err := p.AddTableSpan(ctx, span1, 20, false)
+	require.Nil(t, err)
+	require.False(t, done)
+	exporter := <-exported
+	require.Equal(t, span1, exporter.progress.Span())
+	require.Equal(t, []byte{1}, exporter.progress.ResumeKey)
+	require.Equal(t, uint64(10), exporter.progress.ExportedRows)
+	done, err = p.AddTableSpan(ctx, span2, 20, false)
+	require.Nil(t, err)
+	require.True(t, done)
+	// spans added after the snapshot ts are not exported
+	done, err = p.AddTableSpan(ctx, spanz.TableIDToComparableSpan(3), 30, false)
+	require.Nil(t, err)
+	require.True(t, done)
+
+	// the progress of running exports is recorded in the task position
+	exporter.progress.ResumeKey = []byte{2}
+	require.Nil(t, p.Tick(ctx))
+	tester.MustApplyPatches()
+	snapshot := p.changefeed.TaskPositions[p.captureInfo.ID].Snapshot
+	require.Len(t, snapshot, 1)
+	require.Equal(t, []byte{2}, snapshot[0].ResumeKey)
+	require.False(t, snapshot[0].Done)
+
+	// the exported span is recorded at once
+	exporter.resultCh <- nil
+	require.Eventually(t, func() bool {
+		done, err = p.AddTableSpan(ctx, span1, 20, false)
+		require.Nil(t, err)
+		return done
+	}, 5*time.Second, 10*time.Millisecond)
+	tester.MustApplyPatches()
+	snapshot = p.changefeed.TaskPositions[p.captureInfo.ID].Snapshot
+	require.Len(t, snapshot, 1)
+	require.True(t, snapshot[0].Done)
+
+	// the progress is removed after the snapshot is finished
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.Snapshot.Finished = true
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	require.Nil(t, p.Tick(ctx))
+	tester.MustApplyPatches()
+	require.Nil(t, p.changefeed.TaskPositions[p.captureInfo.ID].Snapshot)
+	require.Nil(t, p.Close())
+}
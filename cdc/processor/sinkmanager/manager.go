@@ -15,6 +15,7 @@ package sinkmanager
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/factory"
 	"github.com/pingcap/tiflow/cdc/sink/metrics/tablesink"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -85,6 +87,17 @@ type SinkManager struct {
 
 	// sinkFactory used to create table sink.
 	sinkFactory *factory.SinkFactory
+	// globalTxnAtomicity indicates whether upstream transactions spanning
+	// multiple tables should be written atomically.
+	globalTxnAtomicity bool
+	// globalTxnReservedMem is the sink memory quota reserved for the slowest
+	// table with global level atomicity.
+	globalTxnReservedMem uint64
+	// getChangefeedTables returns the IDs of all physical tables replicated
+	// by the changefeed at the given ts, it's used with global level atomicity.
+	getChangefeedTables func(ctx context.Context, ts model.Ts) ([]model.TableID, error)
+	// filter is used to get the tables replicated by the changefeed.
+	filter filter.Filter
 	// tableSinks is a map from tableID to tableSink.
 	tableSinks spanz.SyncMap
 
@@ -140,6 +153,8 @@ func New(
 		sinkTaskChan:        make(chan *sinkTask),
 		sinkWorkerAvailable: make(chan struct{}, 1),
 
+		globalTxnAtomicity: changefeedInfo.Config.Sink.TxnAtomicity.IsGlobal(),
+
 		metricsTableSinkTotalRows: tablesink.TotalRowsCountCounter.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 	}
@@ -160,6 +175,16 @@ func New(
 		m.sinkMemQuota = memquota.NewMemQuota(changefeedID, changefeedInfo.Config.MemoryQuota, "sink")
 		m.redoMemQuota = memquota.NewMemQuota(changefeedID, 0, "redo")
 	}
+	if m.globalTxnAtomicity {
+		m.globalTxnReservedMem = changefeedInfo.Config.MemoryQuota / 4
+		if m.redoDMLMgr != nil {
+			m.globalTxnReservedMem = changefeedInfo.Config.MemoryQuota / 4 / 4
+		}
+		if m.globalTxnReservedMem < requestMemSize {
+			m.globalTxnReservedMem = requestMemSize
+		}
+		m.getChangefeedTables = m.getChangefeedTablesFromSchema
+	}
 
 	m.ready = make(chan struct{})
 	return m
@@ -178,6 +203,14 @@ func (m *SinkManager) Run(ctx context.Context) (err error) {
 		m.wg.Wait()
 	}()
 
+	if m.globalTxnAtomicity {
+		m.filter, err = filter.NewFilter(m.changefeedInfo.Config, "")
+		if err != nil {
+			close(m.ready)
+			return errors.Trace(err)
+		}
+	}
+
 	m.sinkFactory, err = factory.New(
 		m.managerCtx,
 		m.changefeedInfo.SinkURI,
@@ -195,6 +228,9 @@ func (m *SinkManager) Run(ctx context.Context) (err error) {
 	}
 
 	m.backgroundGC(managerErrors)
+	if m.globalTxnAtomicity {
+		m.backgroundUpdateGlobalResolvedTs()
+	}
 
 	m.sinkEg, sinkCtx = errgroup.WithContext(taskCtx)
 	m.redoEg, redoCtx = errgroup.WithContext(taskCtx)
@@ -347,6 +383,106 @@ func (m *SinkManager) backgroundGC(errors chan<- error) {
 	}()
 }
 
+// backgroundUpdateGlobalResolvedTs periodically tells the sink the minimal
+// resolved ts of all table sinks, so that transactions spanning multiple
+// tables can be written once all their parts have arrived.
+func (m *SinkManager) backgroundUpdateGlobalResolvedTs() {
+	ticker := time.NewTicker(defaultGenerateTaskInterval)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.managerCtx.Done():
+				return
+			case <-ticker.C:
+				if resolvedTs, ok := m.getGlobalResolvedTs(m.managerCtx); ok {
+					m.sinkFactory.UpdateGlobalResolvedTs(resolvedTs)
+				}
+			}
+		}
+	}()
+}
+
+// getGlobalResolvedTs returns the minimal resolved mark of all table sinks.
+// It returns false if some tables of the changefeed are not replicating on
+// this processor, e.g. they are being moved to or from another capture.
+// Parts of transactions on these tables can be missing here, so nothing is
+// written until all tables are replicated by this processor again.
+func (m *SinkManager) getGlobalResolvedTs(ctx context.Context) (model.Ts, bool) {
+	resolvedTs := uint64(math.MaxUint64)
+	replicating := true
+	localTables := make(map[model.TableID]struct{})
+	m.tableSinks.Range(func(span tablepb.Span, value interface{}) bool {
+		wrapper := value.(*tableSinkWrapper)
+		if wrapper.getState() != tablepb.TableStateReplicating {
+			replicating = false
+			return false
+		}
+		localTables[span.TableID] = struct{}{}
+		if mark := wrapper.getSinkResolvedMark(); mark < resolvedTs {
+			resolvedTs = mark
+		}
+		return true
+	})
+	if !replicating || len(localTables) == 0 {
+		return 0, false
+	}
+
+	// Transactions committed at resolvedTs can only write tables which exist
+	// before it. Tables created at resolvedTs are added after the DDL is
+	// executed, and tables dropped at it are removed after that.
+	tables, err := m.getChangefeedTables(ctx, resolvedTs-1)
+	if err != nil {
+		log.Warn("failed to get tables of the changefeed",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Uint64("ts", resolvedTs-1),
+			zap.Error(err))
+		return 0, false
+	}
+	for _, tableID := range tables {
+		if _, ok := localTables[tableID]; !ok {
+			log.Debug("global resolved ts is blocked by a table not replicated here",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Int64("tableID", tableID),
+				zap.Uint64("resolvedTs", resolvedTs))
+			return 0, false
+		}
+	}
+	return resolvedTs, true
+}
+
+// getChangefeedTablesFromSchema returns the IDs of all physical tables
+// replicated by the changefeed at the given ts, the same as the owner
+// schedules them.
+func (m *SinkManager) getChangefeedTablesFromSchema(
+	ctx context.Context, ts model.Ts,
+) ([]model.TableID, error) {
+	snap, err := m.schemaStorage.GetSnapshot(ctx, ts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	forceReplicate := m.changefeedInfo.Config.ForceReplicate
+	tables := make([]model.TableID, 0)
+	snap.IterTables(true, func(tableInfo *model.TableInfo) {
+		if m.filter.ShouldIgnoreTable(tableInfo.TableName.Schema, tableInfo.TableName.Table) ||
+			!tableInfo.IsEligible(forceReplicate) {
+			return
+		}
+		if pi := tableInfo.GetPartitionInfo(); pi != nil {
+			for _, partition := range pi.Definitions {
+				tables = append(tables, partition.ID)
+			}
+		} else {
+			tables = append(tables, tableInfo.ID)
+		}
+	})
+	return tables, nil
+}
+
 // generateSinkTasks generates tasks to fetch data from the source manager.
 func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 	// Task upperbound is limited by barrierTs and schemaResolvedTs.
@@ -417,7 +553,7 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 			}
 
 			// No available memory, skip this round directly.
-			if !m.sinkMemQuota.TryAcquire(requestMemSize) {
+			if !m.tryAcquireSinkTaskMem(i == 0) {
 				break LOOP
 			}
 
@@ -493,6 +629,20 @@ func (m *SinkManager) generateSinkTasks(ctx context.Context) error {
 	}
 }
 
+// tryAcquireSinkTaskMem tries to acquire the memory quota for a sink task,
+// slowest indicates whether the task is for the slowest table.
+func (m *SinkManager) tryAcquireSinkTaskMem(slowest bool) bool {
+	// With global level atomicity, events of other tables are held by the
+	// sink until the slowest table catches up, and their memory is released
+	// only after they are flushed. So a part of the quota is reserved for the
+	// slowest table, otherwise it deadlocks.
+	reservedMem := uint64(0)
+	if m.globalTxnAtomicity && !slowest {
+		reservedMem = m.globalTxnReservedMem
+	}
+	return m.sinkMemQuota.TryAcquireWithReserved(requestMemSize, reservedMem)
+}
+
 func (m *SinkManager) generateRedoTasks(ctx context.Context) error {
 	// We use the table's resolved ts as the upper bound to fetch events.
 	getUpperBound := func(tableSinkUpperBoundTs model.Ts) engine.Position {
@@ -886,6 +1036,16 @@ func (m *SinkManager) GetTableStats(span tablepb.Span) TableStats {
 	}
 }
 
+// SampleTableReceivedRates returns the number of events and bytes received
+// per second by the table since the last sample.
+func (m *SinkManager) SampleTableReceivedRates(span tablepb.Span) (eventRate, byteRate uint64) {
+	value, ok := m.tableSinks.Load(span)
+	if !ok {
+		return 0, 0
+	}
+	return value.(*tableSinkWrapper).sampleReceivedRates(time.Now())
+}
+
 // ReceivedEvents returns the number of events received by all table sinks.
 func (m *SinkManager) ReceivedEvents() int64 {
 	totalReceivedEvents := int64(0)
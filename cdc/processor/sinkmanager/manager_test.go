@@ -18,11 +18,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -149,7 +152,7 @@ func TestRemoveTable(t *testing.T) {
 	manager.schemaStorage.AdvanceResolvedTs(5)
 	// Check all the events are sent to sink and record the memory usage.
 	require.Eventually(t, func() bool {
-		return manager.sinkMemQuota.GetUsedBytes() == 968
+		return manager.sinkMemQuota.GetUsedBytes() == 1000
 	}, 5*time.Second, 10*time.Millisecond)
 
 	// Call this function times to test the idempotence.
@@ -258,6 +261,196 @@ func TestGetTableStatsToReleaseMemQuota(t *testing.T) {
 	}, 5*time.Second, 10*time.Millisecond)
 }
 
+func TestGetGlobalResolvedTs(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+	manager.getChangefeedTables = func(context.Context, model.Ts) ([]model.TableID, error) {
+		return []model.TableID{1, 2}, nil
+	}
+
+	_, ok := manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	manager.AddTable(span1, 1, 100)
+	require.Nil(t, manager.StartTable(span1, 1))
+	// table 2 is not replicated by this processor yet
+	_, ok = manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+
+	manager.AddTable(span2, 3, 100)
+	// table 2 is not replicating yet
+	_, ok = manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+	require.Nil(t, manager.StartTable(span2, 3))
+	resolvedTs, ok := manager.getGlobalResolvedTs(ctx)
+	require.True(t, ok)
+	require.Equal(t, model.Ts(1), resolvedTs)
+
+	value, _ := manager.tableSinks.Load(span1)
+	wrapper1 := value.(*tableSinkWrapper)
+	wrapper1.updateSinkResolvedMark(5)
+	resolvedTs, _ = manager.getGlobalResolvedTs(ctx)
+	require.Equal(t, model.Ts(3), resolvedTs)
+
+	// stopping tables block the global resolved ts
+	value, _ = manager.tableSinks.Load(span2)
+	value.(*tableSinkWrapper).state.Store(tablepb.TableStateStopping)
+	_, ok = manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+}
+
+func TestGlobalResolvedTsWhenTableIsMoved(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+	manager.getChangefeedTables = func(context.Context, model.Ts) ([]model.TableID, error) {
+		return []model.TableID{1, 2}, nil
+	}
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	manager.AddTable(span1, 1, 100)
+	require.Nil(t, manager.StartTable(span1, 1))
+	manager.AddTable(span2, 1, 100)
+	require.Nil(t, manager.StartTable(span2, 1))
+	value, _ := manager.tableSinks.Load(span1)
+	value.(*tableSinkWrapper).updateSinkResolvedMark(9)
+	value, _ = manager.tableSinks.Load(span2)
+	value.(*tableSinkWrapper).updateSinkResolvedMark(12)
+
+	// A transaction committed at 10 writes both tables. Table 2 has written
+	// its part but table 1 has not, so the transaction is not resolved yet.
+	resolvedTs, ok := manager.getGlobalResolvedTs(ctx)
+	require.True(t, ok)
+	require.Equal(t, model.Ts(9), resolvedTs)
+
+	// Table 1 is moved to another capture in the middle of the transaction,
+	// the part of table 2 must not be written alone.
+	value, _ = manager.tableSinks.Load(span1)
+	value.(*tableSinkWrapper).state.Store(tablepb.TableStateStopping)
+	_, ok = manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+	manager.RemoveTable(span1)
+	_, ok = manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+
+	// The table is moved back and replicated from its checkpoint again.
+	manager.AddTable(span1, 9, 100)
+	_, ok = manager.getGlobalResolvedTs(ctx)
+	require.False(t, ok)
+	require.Nil(t, manager.StartTable(span1, 9))
+	resolvedTs, ok = manager.getGlobalResolvedTs(ctx)
+	require.True(t, ok)
+	require.Equal(t, model.Ts(9), resolvedTs)
+}
+
+func TestGetChangefeedTablesFromSchema(t *testing.T) {
+	t.Parallel()
+
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.DDL2Job("create database test0")
+	t1 := helper.DDL2Job("create table test0.t1(id int primary key)")
+	// ineligible tables are not replicated
+	helper.DDL2Job("create table test0.t2(id int)")
+	t3 := helper.DDL2Job("create table test0.t3(id int primary key) " +
+		"partition by range(id) (partition p0 values less than (10), " +
+		"partition p1 values less than (20))")
+	ignored := helper.DDL2Job("create table test0.ignored(id int primary key)")
+	ts := ignored.BinlogInfo.FinishedTS
+
+	changefeedInfo := getChangefeedInfo()
+	changefeedInfo.Config.Filter.Rules = []string{"test0.*", "!test0.ignored"}
+	f, err := filter.NewFilter(changefeedInfo.Config, "")
+	require.Nil(t, err)
+	schemaStorage, err := entry.NewSchemaStorage(helper.GetCurrentMeta(), ts, false,
+		model.DefaultChangeFeedID("1"), util.RoleTester, f)
+	require.Nil(t, err)
+
+	manager := &SinkManager{
+		changefeedInfo: changefeedInfo,
+		schemaStorage:  schemaStorage,
+		filter:         f,
+	}
+	tables, err := manager.getChangefeedTablesFromSchema(context.Background(), ts)
+	require.Nil(t, err)
+	require.Len(t, tables, 3)
+	require.Contains(t, tables, t1.TableID)
+	for _, partition := range t3.BinlogInfo.TableInfo.GetPartitionInfo().Definitions {
+		require.Contains(t, tables, partition.ID)
+	}
+}
+
+func TestGlobalTxnReservedMem(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	changefeedInfo.Config.Sink.TxnAtomicity = config.AtomicityLevel("global")
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+	require.True(t, manager.globalTxnAtomicity)
+	require.Equal(t, changefeedInfo.Config.MemoryQuota/4, manager.globalTxnReservedMem)
+
+	// Other tables use up the quota except the reserved part.
+	for manager.tryAcquireSinkTaskMem(false) {
+	}
+	require.GreaterOrEqual(t, changefeedInfo.Config.MemoryQuota-manager.sinkMemQuota.GetUsedBytes(),
+		manager.globalTxnReservedMem)
+	// The slowest table can still move forward.
+	slowestTasks := 0
+	for manager.tryAcquireSinkTaskMem(true) {
+		slowestTasks++
+	}
+	require.GreaterOrEqual(t, uint64(slowestTasks), manager.globalTxnReservedMem/requestMemSize)
+	require.False(t, manager.tryAcquireSinkTaskMem(false))
+
+	// Memory released by the slowest table is not taken by other tables.
+	manager.sinkMemQuota.Refund(requestMemSize)
+	require.False(t, manager.tryAcquireSinkTaskMem(false))
+	require.True(t, manager.tryAcquireSinkTaskMem(true))
+}
+
+func TestTableTxnNoReservedMem(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedInfo := getChangefeedInfo()
+	manager, _, _ := CreateManagerWithMemEngine(t, ctx, model.DefaultChangeFeedID("1"),
+		changefeedInfo, make(chan error, 1))
+	defer func() {
+		cancel()
+		manager.Close()
+	}()
+	require.False(t, manager.globalTxnAtomicity)
+
+	// Without global level atomicity, the quota is not reserved.
+	for manager.tryAcquireSinkTaskMem(false) {
+	}
+	require.False(t, manager.tryAcquireSinkTaskMem(true))
+}
+
 func TestDoNotGenerateTableSinkTaskWhenTableIsNotReplicating(t *testing.T) {
 	t.Parallel()
 
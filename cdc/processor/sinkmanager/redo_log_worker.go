@@ -84,7 +84,7 @@ func (w *redoWorker) handleTask(ctx context.Context, task *redoTask) (finalErr e
 	// The task is finished and some required memory isn't used.
 	defer advancer.cleanup()
 
-	iter := w.sourceManager.FetchByTable(task.span, lowerBound, upperBound, w.memQuota)
+	iter := w.sourceManager.FetchByTable(ctx, task.span, lowerBound, upperBound, w.memQuota)
 	allEventCount := 0
 	cachedSize := uint64(0)
 
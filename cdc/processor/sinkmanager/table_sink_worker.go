@@ -138,12 +138,13 @@ func (w *sinkWorker) handleTask(ctx context.Context, task *sinkTask) (finalErr e
 	allEventSize := uint64(0)
 	allEventCount := 0
 	// lowerBound and upperBound are both closed intervals.
-	iter := w.sourceManager.FetchByTable(task.span, lowerBound, upperBound, w.sinkMemQuota)
+	iter := w.sourceManager.FetchByTable(ctx, task.span, lowerBound, upperBound, w.sinkMemQuota)
 
 	defer func() {
 		// Collect metrics.
 		w.metricRedoEventCacheMiss.Add(float64(allEventSize))
 		task.tableSink.receivedEventCount.Add(int64(allEventCount))
+		task.tableSink.receivedEventSize.Add(int64(allEventSize))
 		w.metricOutputEventCountKV.Add(float64(allEventCount))
 
 		// If eventCache is nil, update sorter commit ts and range event count.
@@ -240,6 +241,7 @@ func (w *sinkWorker) fetchFromCache(
 		newLowerBound = popRes.boundary.Next()
 		if len(popRes.events) > 0 {
 			task.tableSink.receivedEventCount.Add(int64(popRes.pushCount))
+			task.tableSink.receivedEventSize.Add(int64(popRes.size))
 			w.metricOutputEventCountKV.Add(float64(popRes.pushCount))
 			w.metricRedoEventCacheHit.Add(float64(popRes.size))
 			task.tableSink.appendRowChangedEvents(popRes.events...)
@@ -61,10 +61,23 @@ type tableSinkWrapper struct {
 	receivedSorterCommitTs atomic.Uint64
 	// receivedEventCount is the number of events received from the sorter.
 	receivedEventCount atomic.Int64
+	// receivedEventSize is the size of events received from the sorter.
+	receivedEventSize atomic.Int64
+	// lastRateSample is used to calculate the received rates of the table.
+	lastRateSample struct {
+		sync.Mutex
+		time  time.Time
+		count int64
+		size  int64
+	}
 	// lastCleanTime indicates the last time the table has been cleaned.
 	lastCleanTime time.Time
 	// checkpointTs is the checkpoint ts of the table sink.
 	checkpointTs atomic.Uint64
+	// sinkResolvedMark is the resolved mark of the last resolved ts sent to
+	// the table sink, all events not greater than it have been written to the
+	// backend sink. It's used to calculate the global resolved ts.
+	sinkResolvedMark atomic.Uint64
 
 	// rangeEventCounts is for clean the table engine.
 	// If rangeEventCounts[i].events is greater than 0, it means there must be
@@ -106,8 +119,10 @@ func newTableSinkWrapper(
 		targetTs:   targetTs,
 	}
 	res.checkpointTs.Store(startTs)
+	res.sinkResolvedMark.Store(startTs)
 	res.receivedSorterResolvedTs.Store(startTs)
 	res.barrierTs.Store(startTs)
+	res.lastRateSample.time = time.Now()
 	return res
 }
 
@@ -133,6 +148,7 @@ func (t *tableSinkWrapper) start(startTs model.Ts, replicateTs model.Ts) {
 	// Because in two phase scheduling, the table sink may be advanced to a later ts.
 	// And we can just continue to replicate the table sink from the new start ts.
 	t.checkpointTs.Store(startTs)
+	t.updateSinkResolvedMark(startTs)
 	for {
 		old := t.receivedSorterResolvedTs.Load()
 		if startTs <= old || t.receivedSorterResolvedTs.CompareAndSwap(old, startTs) {
@@ -172,9 +188,23 @@ func (t *tableSinkWrapper) updateResolvedTs(ts model.ResolvedTs) error {
 	if err := t.tableSink.UpdateResolvedTs(ts); err != nil {
 		return errors.Trace(err)
 	}
+	t.updateSinkResolvedMark(ts.ResolvedMark())
 	return nil
 }
 
+func (t *tableSinkWrapper) updateSinkResolvedMark(mark model.Ts) {
+	for {
+		old := t.sinkResolvedMark.Load()
+		if mark <= old || t.sinkResolvedMark.CompareAndSwap(old, mark) {
+			return
+		}
+	}
+}
+
+func (t *tableSinkWrapper) getSinkResolvedMark() model.Ts {
+	return t.sinkResolvedMark.Load()
+}
+
 func (t *tableSinkWrapper) getCheckpointTs() model.ResolvedTs {
 	currentCheckpointTs := t.checkpointTs.Load()
 	newCheckpointTs := t.tableSink.GetCheckpointTs()
@@ -196,6 +226,25 @@ func (t *tableSinkWrapper) getReceivedEventCount() int64 {
 	return t.receivedEventCount.Load()
 }
 
+// sampleReceivedRates returns the number of events and bytes received per
+// second since the last sample.
+func (t *tableSinkWrapper) sampleReceivedRates(now time.Time) (eventRate, byteRate uint64) {
+	t.lastRateSample.Lock()
+	defer t.lastRateSample.Unlock()
+
+	count := t.receivedEventCount.Load()
+	size := t.receivedEventSize.Load()
+	elapsed := now.Sub(t.lastRateSample.time).Seconds()
+	if elapsed > 0 {
+		eventRate = uint64(float64(count-t.lastRateSample.count) / elapsed)
+		byteRate = uint64(float64(size-t.lastRateSample.size) / elapsed)
+	}
+	t.lastRateSample.time = now
+	t.lastRateSample.count = count
+	t.lastRateSample.size = size
+	return
+}
+
 func (t *tableSinkWrapper) getState() tablepb.TableState {
 	return t.state.Load()
 }
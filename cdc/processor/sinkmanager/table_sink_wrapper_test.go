@@ -16,6 +16,7 @@ package sinkmanager
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -112,6 +113,26 @@ func TestUpdateReceivedSorterResolvedTs(t *testing.T) {
 	require.Equal(t, tablepb.TableStatePrepared, wrapper.getState())
 }
 
+func TestSampleReceivedRates(t *testing.T) {
+	t.Parallel()
+
+	wrapper, _ := createTableSinkWrapper(
+		model.DefaultChangeFeedID("1"), spanz.TableIDToComparableSpan(1))
+	now := wrapper.lastRateSample.time
+	wrapper.receivedEventCount.Add(100)
+	wrapper.receivedEventSize.Add(1000)
+	eventRate, byteRate := wrapper.sampleReceivedRates(now.Add(10 * time.Second))
+	require.Equal(t, uint64(10), eventRate)
+	require.Equal(t, uint64(100), byteRate)
+
+	// Rates are calculated since the last sample.
+	wrapper.receivedEventCount.Add(40)
+	wrapper.receivedEventSize.Add(400)
+	eventRate, byteRate = wrapper.sampleReceivedRates(now.Add(20 * time.Second))
+	require.Equal(t, uint64(4), eventRate)
+	require.Equal(t, uint64(40), byteRate)
+}
+
 func TestConvertNilRowChangedEvents(t *testing.T) {
 	t.Parallel()
 
@@ -196,7 +217,7 @@ func TestConvertRowChangedEventsWhenEnableOldValue(t *testing.T) {
 	result, size, err := convertRowChangedEvents(changefeedID, span, enableOldValue, events...)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(result))
-	require.Equal(t, uint64(240), size)
+	require.Equal(t, uint64(248), size)
 }
 
 func TestConvertRowChangedEventsWhenDisableOldValue(t *testing.T) {
@@ -249,7 +270,7 @@ func TestConvertRowChangedEventsWhenDisableOldValue(t *testing.T) {
 	result, size, err := convertRowChangedEvents(changefeedID, span, enableOldValue, events...)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(result))
-	require.Equal(t, uint64(240), size)
+	require.Equal(t, uint64(248), size)
 
 	// Update non-handle key.
 	columns = []*model.Column{
@@ -295,7 +316,7 @@ func TestConvertRowChangedEventsWhenDisableOldValue(t *testing.T) {
 	result, size, err = convertRowChangedEvents(changefeedID, span, enableOldValue, events...)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(result))
-	require.Equal(t, uint64(240), size)
+	require.Equal(t, uint64(248), size)
 }
 
 func TestGetUpperBoundTs(t *testing.T) {
@@ -0,0 +1,288 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/snapshot"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+var (
+	// snapshotRetryInterval is the interval to export the snapshot of a span
+	// again after the export fails.
+	snapshotRetryInterval = 10 * time.Second
+	// snapshotPersistInterval is the interval to record the progress of
+	// running exports in the task position.
+	snapshotPersistInterval = 10 * time.Second
+)
+
+// snapshotExporter exports the snapshot of a span, it is implemented by
+// snapshot.Exporter.
+type snapshotExporter interface {
+	Run(ctx context.Context) error
+	Progress() *model.SpanSnapshotProgress
+}
+
+// spanSnapshot is the export of the snapshot of a span. The span is added
+// after its snapshot is exported.
+type spanSnapshot struct {
+	cancel   context.CancelFunc
+	exporter snapshotExporter
+	// errCh receives the result of the export, it is nil if the export is
+	// failed and waits to be retried.
+	errCh    chan error
+	failedAt time.Time
+}
+
+// snapshotTableSpan exports the snapshot of the span if it is added at the
+// ts of the initial snapshot, or its table is being backfilled and it is
+// added at the snapshot ts of the backfill. It returns true if the span can
+// be added. The snapshot is exported before any changes of the span are
+// replicated, so only the progress of the span is held back until the export
+// is finished, other spans keep running.
+func (p *processor) snapshotTableSpan(span tablepb.Span, startTs model.Ts) (bool, error) {
+	snapshotTs, ok := p.spanSnapshotTs(span, startTs)
+	if !ok {
+		return false, nil
+	}
+	if snapshotTs == 0 {
+		return true, nil
+	}
+
+	task, ok := p.snapshots.Get(span)
+	if ok && task.errCh != nil {
+		select {
+		case err := <-task.errCh:
+			progress := task.exporter.Progress()
+			p.snapshotProgress.ReplaceOrInsert(span, progress)
+			if err == nil {
+				p.snapshots.Delete(span)
+				// Record the exported span at once, so that it is not
+				// exported again if it is moved to another capture.
+				p.persistSnapshotProgress()
+				log.Info("snapshot of the table span is exported",
+					zap.String("namespace", p.changefeedID.Namespace),
+					zap.String("changefeed", p.changefeedID.ID),
+					zap.Stringer("span", &span),
+					zap.Uint64("snapshotTs", snapshotTs),
+					zap.Uint64("rows", progress.ExportedRows))
+				return true, nil
+			}
+			log.Warn("failed to export the snapshot of the table span, retry later",
+				zap.String("namespace", p.changefeedID.Namespace),
+				zap.String("changefeed", p.changefeedID.ID),
+				zap.Stringer("span", &span),
+				zap.Uint64("snapshotTs", snapshotTs),
+				zap.Error(err))
+			task.errCh = nil
+			task.failedAt = time.Now()
+		default:
+		}
+		return false, nil
+	}
+	if ok && time.Since(task.failedAt) < snapshotRetryInterval {
+		return false, nil
+	}
+
+	progress := p.getSpanSnapshotProgress(span, snapshotTs)
+	if progress.Done {
+		p.snapshots.Delete(span)
+		return true, nil
+	}
+	_, concurrency, _ := snapshot.Limits(p.changefeed.Info.Config.Snapshot)
+	running := 0
+	p.snapshots.Range(func(_ tablepb.Span, task *spanSnapshot) bool {
+		if task.errCh != nil {
+			running++
+		}
+		return true
+	})
+	if running >= concurrency {
+		return false, nil
+	}
+
+	exporter, err := p.newSnapshotExporter(progress)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	task = &spanSnapshot{cancel: cancel, exporter: exporter, errCh: make(chan error, 1)}
+	p.snapshots.ReplaceOrInsert(span, task)
+	log.Info("start to export the snapshot of the table span",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Uint64("snapshotTs", snapshotTs),
+		zap.Binary("resumeKey", progress.ResumeKey))
+	go func(errCh chan<- error) {
+		errCh <- exporter.Run(ctx)
+	}(task.errCh)
+	return false, nil
+}
+
+// spanSnapshotTs returns the snapshot ts of the span added at startTs, it is
+// 0 if the snapshot of the span needn't be exported. It returns false if the
+// span must wait to be added.
+func (p *processor) spanSnapshotTs(span tablepb.Span, startTs model.Ts) (model.Ts, bool) {
+	if initial := p.changefeed.Status.Snapshot; initial != nil &&
+		!initial.Finished && initial.SnapshotTs == startTs {
+		return startTs, true
+	}
+	backfill := p.changefeed.Status.Backfill
+	if backfill == nil || backfill.IsFinished() || backfill.TableID != span.TableID {
+		return 0, true
+	}
+	if backfill.Snapshot == nil {
+		// The owner schedules the table again after the snapshot ts is
+		// persisted, wait until the processor sees it.
+		return 0, false
+	}
+	if backfill.Snapshot.SnapshotTs != startTs {
+		return 0, true
+	}
+	return startTs, true
+}
+
+// isSnapshotUnfinished returns true if the initial snapshot or the snapshot
+// of the backfilled table at snapshotTs is not finished.
+func (p *processor) isSnapshotUnfinished(snapshotTs model.Ts) bool {
+	status := p.changefeed.Status
+	if status.Snapshot != nil && !status.Snapshot.Finished &&
+		status.Snapshot.SnapshotTs == snapshotTs {
+		return true
+	}
+	return status.Backfill != nil && status.Backfill.Snapshot != nil &&
+		!status.Backfill.IsFinished() && status.Backfill.Snapshot.SnapshotTs == snapshotTs
+}
+
+// getSpanSnapshotProgress returns the furthest progress of the span recorded
+// by any capture, the span may be exported by another capture before it is
+// moved to this one.
+func (p *processor) getSpanSnapshotProgress(
+	span tablepb.Span, snapshotTs model.Ts,
+) *model.SpanSnapshotProgress {
+	res := model.NewSpanSnapshotProgress(span, snapshotTs)
+	update := func(progress *model.SpanSnapshotProgress) {
+		if progress.SnapshotTs != snapshotTs || res.Done {
+			return
+		}
+		progressSpan := progress.Span()
+		if !progressSpan.Eq(&span) {
+			return
+		}
+		if progress.Done || bytes.Compare(progress.ResumeKey, res.ResumeKey) > 0 {
+			res = progress.Clone()
+		}
+	}
+	for _, position := range p.changefeed.TaskPositions {
+		for _, progress := range position.Snapshot {
+			update(progress)
+		}
+	}
+	if progress, ok := p.snapshotProgress.Get(span); ok {
+		update(progress)
+	}
+	return res
+}
+
+// updateSnapshotProgress collects the progress of running exports, and
+// records it in the task position periodically.
+func (p *processor) updateSnapshotProgress() {
+	p.snapshots.Range(func(span tablepb.Span, task *spanSnapshot) bool {
+		if task.errCh != nil {
+			p.snapshotProgress.ReplaceOrInsert(span, task.exporter.Progress())
+		}
+		return true
+	})
+	if time.Since(p.snapshotPersistedAt) >= snapshotPersistInterval {
+		p.persistSnapshotProgress()
+	}
+}
+
+// persistSnapshotProgress records the collected progress in the task
+// position, and removes the progress of finished snapshots from it.
+func (p *processor) persistSnapshotProgress() {
+	p.snapshotPersistedAt = time.Now()
+	updates := make([]*model.SpanSnapshotProgress, 0, p.snapshotProgress.Len())
+	p.snapshotProgress.Range(func(_ tablepb.Span, progress *model.SpanSnapshotProgress) bool {
+		if p.isSnapshotUnfinished(progress.SnapshotTs) {
+			updates = append(updates, progress)
+		}
+		return true
+	})
+	p.snapshotProgress = spanz.NewHashMap[*model.SpanSnapshotProgress]()
+	p.changefeed.PatchTaskPosition(p.captureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			if position == nil {
+				return nil, false, nil
+			}
+			changed := len(updates) > 0
+			res := make([]*model.SpanSnapshotProgress, 0, len(position.Snapshot)+len(updates))
+			for _, progress := range position.Snapshot {
+				if !p.isSnapshotUnfinished(progress.SnapshotTs) {
+					changed = true
+					continue
+				}
+				span := progress.Span()
+				replaced := false
+				for _, update := range updates {
+					updateSpan := update.Span()
+					if update.SnapshotTs == progress.SnapshotTs && updateSpan.Eq(&span) {
+						replaced = true
+						break
+					}
+				}
+				if !replaced {
+					res = append(res, progress)
+				}
+			}
+			res = append(res, updates...)
+			if !changed {
+				return position, false, nil
+			}
+			if len(res) == 0 {
+				res = nil
+			}
+			position.Snapshot = res
+			return position, true, nil
+		})
+}
+
+// stopSnapshot stops the export of the snapshot of the span if there is one.
+func (p *processor) stopSnapshot(span tablepb.Span) {
+	if task, ok := p.snapshots.Get(span); ok {
+		task.cancel()
+		p.snapshots.Delete(span)
+	}
+}
+
+func (p *processor) newSnapshotExporterImpl(
+	progress *model.SpanSnapshotProgress,
+) (snapshotExporter, error) {
+	info, err := p.changefeed.Info.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return snapshot.NewExporter(p.changefeedID, info,
+		p.upstream.KVStorage, p.timezone, progress), nil
+}
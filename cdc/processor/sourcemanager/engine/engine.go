@@ -14,6 +14,8 @@
 package engine
 
 import (
+	"context"
+
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 )
@@ -38,6 +40,10 @@ type SortEngine interface {
 	// events are available for fetching, OnResolve is what you want.
 	Add(span tablepb.Span, events ...*model.PolymorphicEvent)
 
+	// WaitForQuota blocks until events of the given table can be added without
+	// exceeding the disk quota of the engine, or the context is done.
+	WaitForQuota(ctx context.Context, span tablepb.Span) error
+
 	// GetResolvedTs gets resolved timestamp of the given table.
 	GetResolvedTs(span tablepb.Span) model.Ts
 
@@ -47,9 +53,12 @@ type SortEngine interface {
 
 	// FetchByTable creates an iterator to fetch events from the given table.
 	// lowerBound is inclusive and only resolved events can be retrieved.
+	// ctx is used by the iterator to read events not stored locally.
 	//
 	// NOTE: FetchByTable is always available even if IsTableBased returns false.
-	FetchByTable(span tablepb.Span, lowerBound, upperBound Position) EventIterator
+	FetchByTable(
+		ctx context.Context, span tablepb.Span, lowerBound, upperBound Position,
+	) EventIterator
 
 	// FetchAllTables creates an iterator to fetch events from all tables.
 	// lowerBound is inclusive and only resolved events can be retrieved.
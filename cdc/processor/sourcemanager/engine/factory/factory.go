@@ -14,7 +14,9 @@
 package factory
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"sync"
 	stdatomic "sync/atomic"
@@ -22,12 +24,14 @@ import (
 
 	"github.com/cockroachdb/pebble"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	epebble "github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/pebble"
 	"github.com/pingcap/tiflow/pkg/config"
 	"go.uber.org/atomic"
 	"go.uber.org/multierr"
+	"go.uber.org/zap"
 )
 
 type sortEngineType int
@@ -37,6 +41,7 @@ const (
 	pebbleEngine sortEngineType = iota + 1
 
 	metricsCollectInterval = 15 * time.Second
+	spillCheckInterval     = 10 * time.Second
 )
 
 var (
@@ -51,17 +56,25 @@ type SortEngineFactory struct {
 	engineType      sortEngineType
 	dir             string
 	memQuotaInBytes uint64
+	// changefeedDiskQuota is the disk quota of each changefeed, 0 means
+	// unlimited.
+	changefeedDiskQuota uint64
 
 	mu      sync.Mutex
 	engines map[model.ChangeFeedID]engine.SortEngine
 
 	wg     sync.WaitGroup
 	closed chan struct{}
+	// cancel cancels background tasks, which may be blocked on I/O.
+	cancel context.CancelFunc
 
 	// Following fields are valid if engineType is pebbleEngine.
 	pebbleConfig *config.DBConfig
 	dbs          []*pebble.DB
 	writeStalls  []writeStall
+	// spillStorage is nil if spilling is disabled.
+	spillStorage storage.ExternalStorage
+	spillPrefix  string
 
 	// dbs is also readed in the background metrics collector.
 	dbInitialized *atomic.Bool
@@ -80,13 +93,24 @@ func (f *SortEngineFactory) Create(ID model.ChangeFeedID) (e engine.SortEngine,
 			return e, nil
 		}
 		if len(f.dbs) == 0 {
+			if f.pebbleConfig.SpillStorage != "" {
+				f.spillStorage, f.spillPrefix, err = createSpillStorage(
+					f.pebbleConfig.SpillStorage, f.dir)
+				if err != nil {
+					return
+				}
+			}
 			f.dbs, f.writeStalls, err = createPebbleDBs(f.dir, f.pebbleConfig, f.memQuotaInBytes)
 			if err != nil {
 				return
 			}
 			f.dbInitialized.Store(true)
 		}
-		e = epebble.New(ID, f.dbs)
+		e = epebble.New(ID, f.dbs, epebble.Options{
+			SpillStorage: f.spillStorage,
+			SpillPrefix:  f.spillPrefix,
+			DiskQuota:    f.changefeedDiskQuota,
+		})
 		f.engines[ID] = e
 	default:
 		log.Panic("not implemented")
@@ -99,12 +123,13 @@ func (f *SortEngineFactory) Drop(ID model.ChangeFeedID) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	engine, exists := f.engines[ID]
+	e, exists := f.engines[ID]
 	if !exists {
 		return nil
 	}
 	delete(f.engines, ID)
-	return engine.Close()
+	engine.ChangefeedDataSize().DeleteLabelValues(ID.Namespace, ID.ID)
+	return e.Close()
 }
 
 // Close will close all created engines and release all resources.
@@ -113,12 +138,14 @@ func (f *SortEngineFactory) Close() (err error) {
 	defer factoryMu.Unlock()
 	factory = nil
 
-	f.mu.Lock()
-	defer f.mu.Unlock()
-
+	// Background goroutines may require f.mu.
 	close(f.closed)
+	f.cancel()
 	f.wg.Wait()
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	for _, engine := range f.engines {
 		err = multierr.Append(err, engine.Close())
 	}
@@ -129,20 +156,29 @@ func (f *SortEngineFactory) Close() (err error) {
 }
 
 // NewForPebble will create a SortEngineFactory for the pebble implementation.
-func NewForPebble(dir string, memQuotaInBytes uint64, cfg *config.DBConfig) *SortEngineFactory {
+// changefeedDiskQuota is the disk quota of each changefeed, 0 means unlimited.
+func NewForPebble(
+	dir string, memQuotaInBytes, changefeedDiskQuota uint64, cfg *config.DBConfig,
+) *SortEngineFactory {
 	factoryMu.Lock()
 	defer factoryMu.Unlock()
 	if factory == nil {
+		ctx, cancel := context.WithCancel(context.Background())
 		factory = &SortEngineFactory{
-			engineType:      pebbleEngine,
-			dir:             dir,
-			memQuotaInBytes: memQuotaInBytes,
-			engines:         make(map[model.ChangeFeedID]engine.SortEngine),
-			closed:          make(chan struct{}),
-			pebbleConfig:    cfg,
-			dbInitialized:   atomic.NewBool(false),
+			engineType:          pebbleEngine,
+			dir:                 dir,
+			memQuotaInBytes:     memQuotaInBytes,
+			changefeedDiskQuota: changefeedDiskQuota,
+			engines:             make(map[model.ChangeFeedID]engine.SortEngine),
+			closed:              make(chan struct{}),
+			cancel:              cancel,
+			pebbleConfig:        cfg,
+			dbInitialized:       atomic.NewBool(false),
 		}
 		factory.startMetricsCollector()
+		if cfg.SpillDiskQuota > 0 {
+			factory.startSpiller(ctx)
+		}
 	}
 	return factory
 }
@@ -184,5 +220,111 @@ func (f *SortEngineFactory) collectMetrics() {
 			engine.BlockCacheAccess().WithLabelValues(id, "miss").
 				Set(float64(stats.BlockCache.Misses))
 		}
+		for _, usage := range f.DiskUsage() {
+			engine.ChangefeedDataSize().
+				WithLabelValues(usage.ChangefeedID.Namespace, usage.ChangefeedID.ID).
+				Set(float64(usage.Bytes))
+		}
+	}
+}
+
+// ChangefeedDiskUsage is bytes of events stored by a changefeed in the sort
+// engine, see epebble.EventSorter.DiskUsage.
+type ChangefeedDiskUsage struct {
+	ChangefeedID model.ChangeFeedID
+	Bytes        int64
+	// Quota is 0 if it's unlimited.
+	Quota  uint64
+	Tables map[model.TableID]int64
+}
+
+// DiskUsage returns disk usages of all changefeeds sorted by changefeed IDs.
+func (f *SortEngineFactory) DiskUsage() []ChangefeedDiskUsage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	usages := make([]ChangefeedDiskUsage, 0, len(f.engines))
+	for id, e := range f.engines {
+		sorter, ok := e.(*epebble.EventSorter)
+		if !ok {
+			continue
+		}
+		bytes, tables := sorter.DiskUsage()
+		usages = append(usages, ChangefeedDiskUsage{
+			ChangefeedID: id,
+			Bytes:        bytes,
+			Quota:        f.changefeedDiskQuota,
+			Tables:       tables,
+		})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].ChangefeedID.Namespace != usages[j].ChangefeedID.Namespace {
+			return usages[i].ChangefeedID.Namespace < usages[j].ChangefeedID.Namespace
+		}
+		return usages[i].ChangefeedID.ID < usages[j].ChangefeedID.ID
+	})
+	return usages
+}
+
+func (f *SortEngineFactory) startSpiller(ctx context.Context) {
+	f.wg.Add(1)
+	ticker := time.NewTicker(spillCheckInterval)
+	go func() {
+		defer f.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.closed:
+				return
+			case <-ticker.C:
+				if err := f.spill(ctx); err != nil {
+					log.Warn("spill sort engines fails", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// spill moves events to the spill storage if the disk usage of engines
+// exceeds the quota. To avoid spilling too frequently, events are spilled
+// until the usage is estimated to be less than 80% of the quota.
+//
+// The usage is the sum of estimated bytes of tables instead of the disk space
+// usage of dbs, which doesn't drop after events are deleted until they are
+// compacted.
+func (f *SortEngineFactory) spill(ctx context.Context) error {
+	if f.spillStorage == nil || !f.dbInitialized.Load() {
+		return nil
+	}
+	f.mu.Lock()
+	engines := make([]engine.SortEngine, 0, len(f.engines))
+	for _, e := range f.engines {
+		engines = append(engines, e)
+	}
+	f.mu.Unlock()
+
+	usage := uint64(0)
+	for _, e := range engines {
+		bytes, _ := e.(*epebble.EventSorter).DiskUsage()
+		usage += uint64(bytes)
+	}
+	quota := f.pebbleConfig.SpillDiskQuota
+	if usage <= quota {
+		return nil
+	}
+	target := int64(usage - quota/10*8)
+
+	log.Info("sort engine disk usage exceeds the quota, start to spill",
+		zap.Uint64("usage", usage), zap.Uint64("quota", quota))
+	for _, e := range engines {
+		if target <= 0 {
+			break
+		}
+		spilled, err := e.(*epebble.EventSorter).Spill(ctx, target)
+		if err != nil {
+			return err
+		}
+		target -= spilled
 	}
+	return nil
 }
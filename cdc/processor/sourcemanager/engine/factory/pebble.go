@@ -14,16 +14,24 @@
 package factory
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/pebble"
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	epebble "github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/pebble"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 )
 
@@ -73,6 +81,58 @@ func createPebbleDBs(
 	return dbs, writeStalls, nil
 }
 
+// spillIDFile is the file in sort-dir which stores the identity of the
+// sort-dir. It is kept across restarts of the capture.
+const spillIDFile = "spill-id"
+
+// createSpillStorage creates the storage which events are spilled to, and
+// returns the prefix of segments spilled by the capture. The prefix is the
+// identity of sort-dir, so that captures can share the storage. Like sort-dir,
+// segments left by the previous process are removed.
+func createSpillStorage(uri string, dir string) (storage.ExternalStorage, string, error) {
+	prefix, err := loadSpillID(dir)
+	if err != nil {
+		log.Error("load spill id fails", zap.String("dir", dir), zap.Error(err))
+		return nil, "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	s, err := util.GetExternalStorageFromURI(ctx, uri)
+	if err != nil {
+		log.Error("create spill storage fails", zap.Error(err))
+		return nil, "", err
+	}
+	err = util.RemoveFilesIf(ctx, s, func(path string) bool {
+		return strings.HasPrefix(path, prefix+"/") &&
+			strings.HasSuffix(path, epebble.SpillFileExt)
+	}, &storage.WalkOption{SubDir: prefix})
+	if err != nil {
+		return nil, "", err
+	}
+	log.Info("create spill storage success", zap.String("prefix", prefix))
+	return s, prefix, nil
+}
+
+// loadSpillID returns the identity of sort-dir, it's generated if absent.
+func loadSpillID(dir string) (string, error) {
+	path := filepath.Join(dir, spillIDFile)
+	data, err := os.ReadFile(path)
+	if err == nil && len(data) > 0 {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", errors.Trace(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", errors.Trace(err)
+	}
+	id := uuid.New().String()
+	if err := os.WriteFile(path, []byte(id), 0o644); err != nil {
+		return "", errors.Trace(err)
+	}
+	return id, nil
+}
+
 type pebbleLogger struct{ id int }
 
 var _ pebble.Logger = (*pebbleLogger)(nil)
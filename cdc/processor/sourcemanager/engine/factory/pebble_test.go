@@ -0,0 +1,50 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSpillStorageKeepsOtherCaptures(t *testing.T) {
+	ctx := context.Background()
+	uri := "file://" + t.TempDir()
+	dir1, dir2 := t.TempDir(), t.TempDir()
+
+	s1, prefix1, err := createSpillStorage(uri, dir1)
+	require.Nil(t, err)
+	_, prefix2, err := createSpillStorage(uri, dir2)
+	require.Nil(t, err)
+	require.NotEqual(t, prefix1, prefix2)
+
+	seg1 := prefix1 + "/default/test/1-1/00000000000000000001.spill"
+	seg2 := prefix2 + "/default/test/1-1/00000000000000000001.spill"
+	require.Nil(t, s1.WriteFile(ctx, seg1, []byte("1")))
+	require.Nil(t, s1.WriteFile(ctx, seg2, []byte("2")))
+
+	// The first capture restarts, the prefix is kept and only segments of
+	// it are removed.
+	s1, prefix, err := createSpillStorage(uri, dir1)
+	require.Nil(t, err)
+	require.Equal(t, prefix1, prefix)
+	exists, err := s1.FileExists(ctx, seg1)
+	require.Nil(t, err)
+	require.False(t, exists)
+	exists, err = s1.FileExists(ctx, seg2)
+	require.Nil(t, err)
+	require.True(t, exists)
+}
@@ -107,7 +107,9 @@ func (s *EventSorter) OnResolve(action func(tablepb.Span, model.Ts)) {
 }
 
 // FetchByTable implements engine.SortEngine.
-func (s *EventSorter) FetchByTable(span tablepb.Span, lowerBound, upperBound engine.Position) engine.EventIterator {
+func (s *EventSorter) FetchByTable(
+	_ context.Context, span tablepb.Span, lowerBound, upperBound engine.Position,
+) engine.EventIterator {
 	value, exists := s.tables.Load(span)
 	if !exists {
 		log.Panic("fetch events from an unexist table", zap.Stringer("span", &span))
@@ -145,6 +147,11 @@ func (s *EventSorter) GetStatsByTable(span tablepb.Span) engine.TableStats {
 	return engine.TableStats{}
 }
 
+// WaitForQuota implements engine.SortEngine.
+func (s *EventSorter) WaitForQuota(ctx context.Context, span tablepb.Span) error {
+	return nil
+}
+
 // ReceivedEvents implements engine.SortEngine.
 // Do not use this function, it is only used for testing.
 func (s *EventSorter) ReceivedEvents() int64 {
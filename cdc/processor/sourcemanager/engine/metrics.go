@@ -74,6 +74,15 @@ var (
 		Help:      "The amount of pending data stored on-disk by the sorter",
 	}, []string{"id"})
 
+	// changefeedDataSizeGauge is the metric that records bytes of events
+	// stored by each changefeed in the sorter.
+	changefeedDataSizeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "changefeed_data_size_gauge",
+		Help:      "The amount of pending data stored by each changefeed in the sorter",
+	}, []string{"namespace", "changefeed"})
+
 	dbIteratorGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "ticdc",
 		Subsystem: "db",
@@ -135,6 +144,11 @@ func OnDiskDataSize() *prometheus.GaugeVec {
 	return onDiskDataSizeGauge
 }
 
+// ChangefeedDataSize returns changefeedDataSizeGauge.
+func ChangefeedDataSize() *prometheus.GaugeVec {
+	return changefeedDataSizeGauge
+}
+
 // IteratorGauge returns dbIteratorGauge.
 func IteratorGauge() *prometheus.GaugeVec {
 	return dbIteratorGauge
@@ -164,6 +178,7 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterIterReadDurationHistogram)
 	registry.MustRegister(inMemoryDataSizeGauge)
 	registry.MustRegister(onDiskDataSizeGauge)
+	registry.MustRegister(changefeedDataSizeGauge)
 	registry.MustRegister(dbIteratorGauge)
 
 	// TODO: Seems these things belong to pebble instead of engine.
@@ -5,6 +5,7 @@
 package mock_engine
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -122,17 +123,17 @@ func (mr *MockSortEngineMockRecorder) FetchAllTables(lowerBound interface{}) *go
 }
 
 // FetchByTable mocks base method.
-func (m *MockSortEngine) FetchByTable(span tablepb.Span, lowerBound, upperBound engine.Position) engine.EventIterator {
+func (m *MockSortEngine) FetchByTable(ctx context.Context, span tablepb.Span, lowerBound, upperBound engine.Position) engine.EventIterator {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchByTable", span, lowerBound, upperBound)
+	ret := m.ctrl.Call(m, "FetchByTable", ctx, span, lowerBound, upperBound)
 	ret0, _ := ret[0].(engine.EventIterator)
 	return ret0
 }
 
 // FetchByTable indicates an expected call of FetchByTable.
-func (mr *MockSortEngineMockRecorder) FetchByTable(span, lowerBound, upperBound interface{}) *gomock.Call {
+func (mr *MockSortEngineMockRecorder) FetchByTable(ctx, span, lowerBound, upperBound interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByTable", reflect.TypeOf((*MockSortEngine)(nil).FetchByTable), span, lowerBound, upperBound)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchByTable", reflect.TypeOf((*MockSortEngine)(nil).FetchByTable), ctx, span, lowerBound, upperBound)
 }
 
 // GetResolvedTs mocks base method.
@@ -215,6 +216,20 @@ func (mr *MockSortEngineMockRecorder) RemoveTable(span interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTable", reflect.TypeOf((*MockSortEngine)(nil).RemoveTable), span)
 }
 
+// WaitForQuota mocks base method.
+func (m *MockSortEngine) WaitForQuota(ctx context.Context, span tablepb.Span) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForQuota", ctx, span)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForQuota indicates an expected call of WaitForQuota.
+func (mr *MockSortEngineMockRecorder) WaitForQuota(ctx, span interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForQuota", reflect.TypeOf((*MockSortEngine)(nil).WaitForQuota), ctx, span)
+}
+
 // MockEventIterator is a mock of EventIterator interface.
 type MockEventIterator struct {
 	ctrl     *gomock.Controller
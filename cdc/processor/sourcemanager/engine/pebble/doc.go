@@ -14,5 +14,7 @@
 // Package pebble is an pebble-based EventSortEngine implementation with such properties:
 //  1. all EventSortEngine instances shares several pebble.DB instances;
 //  2. keys are encoded with prefix TableID-CRTs-StartTs;
-//  3. keys are hashed into different pebble.DB instances based on table prefix.
+//  3. keys are hashed into different pebble.DB instances based on table prefix;
+//  4. resolved events can be spilled to an external storage if the disk usage
+//     of pebble.DB instances exceeds the quota, see EventSorter.Spill.
 package pebble
@@ -14,6 +14,7 @@
 package pebble
 
 import (
+	"context"
 	"encoding/binary"
 	"hash/fnv"
 	"math"
@@ -24,11 +25,13 @@ import (
 
 	"github.com/cockroachdb/pebble"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/pebble/encoding"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -46,6 +49,16 @@ type EventSorter struct {
 	dbs          []*pebble.DB
 	channs       []*chann.DrainableChann[eventWithTableID]
 	serde        encoding.MsgPackGenSerde
+	// spillStorage is nil if spilling is disabled.
+	spillStorage storage.ExternalStorage
+	spillPrefix  string
+	diskQuota    uint64
+
+	// diskUsage is the sum of diskUsage of all tables.
+	diskUsage atomic.Int64
+
+	// spillMu makes spilling exclusive.
+	spillMu sync.Mutex
 
 	// To manage background goroutines.
 	wg     sync.WaitGroup
@@ -60,8 +73,18 @@ type EventSorter struct {
 
 // EventIter implements sorter.EventIterator.
 type EventIter struct {
-	tableID  model.TableID
-	state    *tableState
+	// ctx is used to read spilled segments.
+	ctx     context.Context
+	tableID model.TableID
+	state   *tableState
+
+	// spilled segments are read before iter.
+	spilled       []*spilledSegment
+	spilledReader *spilledReader
+	spillStorage  storage.ExternalStorage
+	lowerBound    engine.Position
+	upperBound    engine.Position
+
 	iter     *pebble.Iterator
 	headItem *model.PolymorphicEvent
 	serde    encoding.MsgPackGenSerde
@@ -69,8 +92,20 @@ type EventIter struct {
 	nextDuration prometheus.Observer
 }
 
+// Options are options to create an EventSorter.
+type Options struct {
+	// SpillStorage is nil if spilling is disabled.
+	SpillStorage storage.ExternalStorage
+	// SpillPrefix is the directory in SpillStorage which segments are
+	// spilled to, it is exclusive to a capture.
+	SpillPrefix string
+	// DiskQuota is the maximum bytes of events stored in pebble, 0 means
+	// unlimited. See WaitForQuota.
+	DiskQuota uint64
+}
+
 // New creates an EventSorter instance.
-func New(ID model.ChangeFeedID, dbs []*pebble.DB) *EventSorter {
+func New(ID model.ChangeFeedID, dbs []*pebble.DB, opts Options) *EventSorter {
 	channs := make([]*chann.DrainableChann[eventWithTableID], 0, len(dbs))
 	for i := 0; i < len(dbs); i++ {
 		channs = append(channs, chann.NewAutoDrainChann[eventWithTableID](chann.Cap(128)))
@@ -80,6 +115,9 @@ func New(ID model.ChangeFeedID, dbs []*pebble.DB) *EventSorter {
 		changefeedID: ID,
 		dbs:          dbs,
 		channs:       channs,
+		spillStorage: opts.SpillStorage,
+		spillPrefix:  opts.SpillPrefix,
+		diskQuota:    opts.DiskQuota,
 		closed:       make(chan struct{}),
 		tables:       spanz.NewHashMap[*tableState](),
 	}
@@ -133,7 +171,8 @@ func (s *EventSorter) AddTable(span tablepb.Span) {
 // RemoveTable implements engine.SortEngine.
 func (s *EventSorter) RemoveTable(span tablepb.Span) {
 	s.mu.Lock()
-	if _, exists := s.tables.Get(span); !exists {
+	state, exists := s.tables.Get(span)
+	if !exists {
 		s.mu.Unlock()
 		log.Warn("remove an unexist table",
 			zap.String("namespace", s.changefeedID.Namespace),
@@ -143,6 +182,18 @@ func (s *EventSorter) RemoveTable(span tablepb.Span) {
 	}
 	s.tables.Delete(span)
 	s.mu.Unlock()
+
+	// Events of the table are not reachable after it's removed.
+	state.mu.Lock()
+	state.removed = true
+	state.mu.Unlock()
+	if err := s.cleanTable(state, span); err != nil {
+		log.Warn("clean removed table fails",
+			zap.String("namespace", s.changefeedID.Namespace),
+			zap.String("changefeed", s.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Error(err))
+	}
 }
 
 // Add implements engine.SortEngine.
@@ -206,7 +257,9 @@ func (s *EventSorter) OnResolve(action func(tablepb.Span, model.Ts)) {
 }
 
 // FetchByTable implements engine.SortEngine.
-func (s *EventSorter) FetchByTable(span tablepb.Span, lowerBound, upperBound engine.Position) engine.EventIterator {
+func (s *EventSorter) FetchByTable(
+	ctx context.Context, span tablepb.Span, lowerBound, upperBound engine.Position,
+) engine.EventIterator {
 	s.mu.RLock()
 	state, exists := s.tables.Get(span)
 	s.mu.RUnlock()
@@ -233,15 +286,25 @@ func (s *EventSorter) FetchByTable(span tablepb.Span, lowerBound, upperBound eng
 	iterReadDur := engine.SorterIterReadDuration()
 
 	seekStart := time.Now()
+	// Spilled segments and the pebble iterator must be got atomically, events
+	// may be moved from pebble to spilled segments concurrently.
+	state.mu.RLock()
+	spilled := state.fetchSpilled(lowerBound, upperBound)
 	iter := iterTable(db, state.uniqueID, span.TableID, lowerBound, upperBound)
+	state.mu.RUnlock()
 	iterReadDur.WithLabelValues(s.changefeedID.Namespace, s.changefeedID.ID, "first").
 		Observe(time.Since(seekStart).Seconds())
 
 	return &EventIter{
-		tableID: span.TableID,
-		state:   state,
-		iter:    iter,
-		serde:   s.serde,
+		ctx:          ctx,
+		tableID:      span.TableID,
+		state:        state,
+		spilled:      spilled,
+		spillStorage: s.spillStorage,
+		lowerBound:   lowerBound,
+		upperBound:   upperBound,
+		iter:         iter,
+		serde:        s.serde,
 
 		nextDuration: iterReadDur.WithLabelValues(s.changefeedID.Namespace, s.changefeedID.ID, "next"),
 	}
@@ -329,6 +392,9 @@ func (s *EventSorter) Close() error {
 
 	close(s.closed)
 	s.wg.Wait()
+	// Wait for the in-progress spilling, it won't start again after closed.
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
 	for _, ch := range s.channs {
 		ch.CloseAndDrain()
 	}
@@ -351,13 +417,12 @@ func (s *EventSorter) Close() error {
 
 // Next implements sorter.EventIterator.
 func (s *EventIter) Next() (event *model.PolymorphicEvent, pos engine.Position, err error) {
-	valid := s.iter != nil && s.iter.Valid()
 	var value []byte
-	for valid {
-		nextStart := time.Now()
-		value, valid = s.iter.Value(), s.iter.Next()
-		s.nextDuration.Observe(time.Since(nextStart).Seconds())
-
+	var valid bool
+	for {
+		if value, valid, err = s.nextValue(); err != nil || !valid {
+			break
+		}
 		event = &model.PolymorphicEvent{}
 		if _, err = s.serde.Unmarshal(event, value); err != nil {
 			return
@@ -367,6 +432,9 @@ func (s *EventIter) Next() (event *model.PolymorphicEvent, pos engine.Position,
 		}
 		s.headItem, event = event, nil
 	}
+	if err != nil {
+		return
+	}
 	if s.headItem != nil {
 		if event == nil || s.headItem.CRTs != event.CRTs || s.headItem.StartTs != event.StartTs {
 			pos.CommitTs = s.headItem.CRTs
@@ -377,12 +445,70 @@ func (s *EventIter) Next() (event *model.PolymorphicEvent, pos engine.Position,
 	return
 }
 
+// nextValue returns the next value in the iterator. Values in spilled
+// segments are returned before values in pebble.
+func (s *EventIter) nextValue() (value []byte, valid bool, err error) {
+	for s.spilledReader != nil || len(s.spilled) > 0 {
+		if s.spilledReader == nil {
+			if s.spilledReader, err = openSpilledSegment(s.ctx, s.spillStorage, s.spilled[0]); err != nil {
+				return nil, false, err
+			}
+			s.spilled = s.spilled[1:]
+		}
+		var key []byte
+		if key, value, valid, err = s.spilledReader.next(); err != nil {
+			return nil, false, err
+		}
+		if !valid {
+			if err = s.closeSpilledReader(); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		_, _, startTs, commitTs := encoding.DecodeKey(key)
+		pos := engine.Position{StartTs: startTs, CommitTs: commitTs}
+		if pos.Compare(s.upperBound) > 0 {
+			s.spilled = nil
+			if err = s.closeSpilledReader(); err != nil {
+				return nil, false, err
+			}
+			break
+		}
+		if pos.Compare(s.lowerBound) >= 0 {
+			return value, true, nil
+		}
+	}
+
+	if s.iter == nil || !s.iter.Valid() {
+		return nil, false, nil
+	}
+	nextStart := time.Now()
+	value = s.iter.Value()
+	s.iter.Next()
+	s.nextDuration.Observe(time.Since(nextStart).Seconds())
+	return value, true, nil
+}
+
+func (s *EventIter) closeSpilledReader() error {
+	err := s.spilledReader.close()
+	s.spilledReader = nil
+	if err != nil {
+		return errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return nil
+}
+
 // Close implements sorter.EventIterator.
-func (s *EventIter) Close() error {
+func (s *EventIter) Close() (err error) {
+	if s.spilledReader != nil {
+		err = s.closeSpilledReader()
+	}
 	if s.iter != nil {
-		return s.iter.Close()
+		if err1 := s.iter.Close(); err == nil {
+			err = err1
+		}
 	}
-	return nil
+	return err
 }
 
 type eventWithTableID struct {
@@ -399,10 +525,21 @@ type tableState struct {
 	maxReceivedCommitTs   atomic.Uint64
 	maxReceivedResolvedTs atomic.Uint64
 	receivedEvents        atomic.Int64
+	// diskUsage is the sum of bytes of buckets.
+	diskUsage atomic.Int64
+
+	// Only accessed by EventSorter.Spill, which is exclusive.
+	spillSeq uint64
 
 	// Following fields are protected by mu.
 	mu      sync.RWMutex
 	cleaned engine.Position
+	removed bool
+	// Events before or at spilledTo are either cleaned or moved to spilled
+	// segments, which are sorted by positions.
+	spilled   []*spilledSegment
+	spilledTo engine.Position
+	buckets   []usageBucket
 }
 
 func (s *EventSorter) handleEvents(
@@ -416,6 +553,7 @@ func (s *EventSorter) handleEvents(
 	batch := db.NewBatch()
 	writeOpts := &pebble.WriteOptions{Sync: false}
 	newResolved := spanz.NewHashMap[model.Ts]()
+	newUsage := spanz.NewHashMap[usageBucket]()
 
 	handleItem := func(item eventWithTableID) {
 		if item.event.IsResolved() {
@@ -434,6 +572,12 @@ func (s *EventSorter) handleEvents(
 				zap.String("namespace", s.changefeedID.Namespace),
 				zap.String("changefeed", s.changefeedID.ID))
 		}
+		usage, _ := newUsage.Get(item.span)
+		usage.bytes += int64(len(key) + len(value))
+		if item.event.CRTs > usage.maxCommitTs {
+			usage.maxCommitTs = item.event.CRTs
+		}
+		newUsage.ReplaceOrInsert(item.span, usage)
 	}
 
 	for {
@@ -503,6 +647,20 @@ func (s *EventSorter) handleEvents(
 			batch = db.NewBatch()
 		}
 
+		newUsage.Range(func(span tablepb.Span, usage usageBucket) bool {
+			s.mu.RLock()
+			state, ok := s.tables.Get(span)
+			s.mu.RUnlock()
+			if ok {
+				state.mu.Lock()
+				state.addUsage(usage)
+				state.mu.Unlock()
+				s.diskUsage.Add(usage.bytes)
+			}
+			return true
+		})
+		newUsage = spanz.NewHashMap[usageBucket]()
+
 		newResolved.Range(func(span tablepb.Span, resolved uint64) bool {
 			s.mu.RLock()
 			ts, ok := s.tables.Get(span)
@@ -540,10 +698,9 @@ func (s *EventSorter) cleanTable(
 		toClean = engine.Position{CommitTs: math.MaxUint64, StartTs: math.MaxUint64 - 1}
 	}
 
-	state.mu.RLock()
-	defer state.mu.RUnlock()
-
+	state.mu.Lock()
 	if state.cleaned.Compare(toClean) >= 0 {
+		state.mu.Unlock()
 		return nil
 	}
 
@@ -555,10 +712,15 @@ func (s *EventSorter) cleanTable(
 	db := s.dbs[getDB(span, len(s.dbs))]
 	err := db.DeleteRange(start, end, &pebble.WriteOptions{Sync: false})
 	if err != nil {
+		state.mu.Unlock()
 		return err
 	}
 
 	state.cleaned = toClean
+	s.diskUsage.Add(-state.releaseUsage(toClean))
+	spilled := state.cleanSpilled()
+	state.mu.Unlock()
+	s.deleteSpilled(spilled)
 	return nil
 }
 
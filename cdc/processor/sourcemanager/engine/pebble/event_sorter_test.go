@@ -14,6 +14,7 @@
 package pebble
 
 import (
+	"context"
 	"path/filepath"
 	"sort"
 	"testing"
@@ -35,7 +36,7 @@ func TestTableOperations(t *testing.T) {
 	defer func() { _ = db.Close() }()
 
 	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
-	s := New(cf, []*pebble.DB{db})
+	s := New(cf, []*pebble.DB{db}, Options{})
 	defer s.Close()
 
 	require.True(t, s.IsTableBased())
@@ -58,7 +59,7 @@ func TestNoResolvedTs(t *testing.T) {
 	defer func() { _ = db.Close() }()
 
 	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
-	s := New(cf, []*pebble.DB{db})
+	s := New(cf, []*pebble.DB{db}, Options{})
 	defer s.Close()
 
 	require.True(t, s.IsTableBased())
@@ -72,7 +73,7 @@ func TestNoResolvedTs(t *testing.T) {
 	timer := time.NewTimer(100 * time.Millisecond)
 	select {
 	case ts := <-resolvedTs:
-		iter := s.FetchByTable(span, engine.Position{}, engine.Position{CommitTs: ts})
+		iter := s.FetchByTable(context.Background(), span, engine.Position{}, engine.Position{CommitTs: ts})
 		event, _, err := iter.Next()
 		require.Nil(t, event)
 		require.Nil(t, err)
@@ -89,7 +90,7 @@ func TestEventFetch(t *testing.T) {
 	defer func() { _ = db.Close() }()
 
 	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
-	s := New(cf, []*pebble.DB{db})
+	s := New(cf, []*pebble.DB{db}, Options{})
 	defer s.Close()
 
 	require.True(t, s.IsTableBased())
@@ -135,7 +136,7 @@ func TestEventFetch(t *testing.T) {
 	timer := time.NewTimer(100 * time.Millisecond)
 	select {
 	case ts := <-resolvedTs:
-		iter := s.FetchByTable(span, engine.Position{}, engine.Position{CommitTs: ts, StartTs: ts - 1})
+		iter := s.FetchByTable(context.Background(), span, engine.Position{}, engine.Position{CommitTs: ts, StartTs: ts - 1})
 		for {
 			event, pos, err := iter.Next()
 			require.Nil(t, err)
@@ -171,7 +172,7 @@ func TestCleanData(t *testing.T) {
 	defer func() { _ = db.Close() }()
 
 	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
-	s := New(cf, []*pebble.DB{db})
+	s := New(cf, []*pebble.DB{db}, Options{})
 	defer s.Close()
 
 	require.True(t, s.IsTableBased())
@@ -0,0 +1,326 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/pebble/encoding"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// SpillFileExt is the extension of spilled segment files.
+	SpillFileExt = ".spill"
+
+	// spillSegmentSize is the size limit of a spilled segment. A segment is
+	// built in memory before it's written, segments are written one by one.
+	spillSegmentSize = 32 * 1024 * 1024
+	// spillReadBufferSize is the buffer size to read a spilled segment,
+	// segments are read in a streaming way so that iterators of many tables
+	// don't hold whole segments in memory.
+	spillReadBufferSize = 64 * 1024
+
+	spillDeleteTimeout = 30 * time.Second
+)
+
+// spilledSegment is a file in the spill storage, which contains resolved
+// events of a table moved out of pebble. Keys and values are kept as they
+// are in pebble, so events in segments are in the same order.
+type spilledSegment struct {
+	name string
+	// last is the position of the last event in the segment.
+	last engine.Position
+	size int64
+}
+
+// Spill moves resolved events of tables from pebble to the spill storage.
+// Tables whose events are cleaned least recently are spilled first, because
+// they are most likely blocked by a stalled sink. It stops after at least
+// target bytes are spilled, and returns the spilled bytes.
+func (s *EventSorter) Spill(ctx context.Context, target int64) (int64, error) {
+	if s.spillStorage == nil {
+		return 0, nil
+	}
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+
+	type candidate struct {
+		span    tablepb.Span
+		state   *tableState
+		cleaned engine.Position
+	}
+	var candidates []candidate
+	s.mu.RLock()
+	if s.isClosed {
+		s.mu.RUnlock()
+		return 0, nil
+	}
+	s.tables.Range(func(span tablepb.Span, state *tableState) bool {
+		state.mu.RLock()
+		candidates = append(candidates, candidate{span: span, state: state, cleaned: state.cleaned})
+		state.mu.RUnlock()
+		return true
+	})
+	s.mu.RUnlock()
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].cleaned.Compare(candidates[j].cleaned) < 0
+	})
+
+	spilled := int64(0)
+	for _, c := range candidates {
+		if spilled >= target {
+			break
+		}
+		n, err := s.spillTable(ctx, c.state, c.span)
+		if err != nil {
+			return spilled, err
+		}
+		spilled += n
+	}
+	return spilled, nil
+}
+
+// spillTable moves all resolved and uncleaned events of the table to the
+// spill storage, and then deletes them from pebble.
+func (s *EventSorter) spillTable(
+	ctx context.Context, state *tableState, span tablepb.Span,
+) (int64, error) {
+	resolved := state.sortedResolved.Load()
+	if resolved == 0 {
+		return 0, nil
+	}
+	to := engine.GenCommitFence(resolved)
+
+	state.mu.RLock()
+	from := state.cleaned
+	if state.spilledTo.Compare(from) > 0 {
+		from = state.spilledTo
+	}
+	state.mu.RUnlock()
+	if from.Compare(to) >= 0 {
+		return 0, nil
+	}
+	lower := engine.Position{}
+	if from.Valid() {
+		lower = from.Next()
+	}
+
+	db := s.dbs[getDB(span, len(s.dbs))]
+	iter := iterTable(db, state.uniqueID, span.TableID, lower, to)
+	defer iter.Close()
+
+	var segments []*spilledSegment
+	var buf []byte
+	var last engine.Position
+	flush := func() error {
+		if len(buf) == 0 {
+			return nil
+		}
+		state.spillSeq++
+		name := fmt.Sprintf("%s/%s/%s/%d-%d/%020d%s", s.spillPrefix,
+			s.changefeedID.Namespace, s.changefeedID.ID,
+			state.uniqueID, span.TableID, state.spillSeq, SpillFileExt)
+		if err := s.spillStorage.WriteFile(ctx, name, buf); err != nil {
+			return errors.WrapError(errors.ErrExternalStorageAPI, err)
+		}
+		segments = append(segments, &spilledSegment{name: name, last: last, size: int64(len(buf))})
+		buf = nil
+		return nil
+	}
+
+	var err error
+	for ; iter.Valid() && err == nil; iter.Next() {
+		buf = appendSpilledEvent(buf, iter.Key(), iter.Value())
+		_, _, startTs, commitTs := encoding.DecodeKey(iter.Key())
+		last = engine.Position{StartTs: startTs, CommitTs: commitTs}
+		if len(buf) >= spillSegmentSize {
+			err = flush()
+		}
+	}
+	if err == nil {
+		err = iter.Error()
+	}
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		s.deleteSpilled(segments)
+		return 0, err
+	}
+
+	// Segments are registered and events are deleted from pebble atomically
+	// for iterators, see FetchByTable.
+	state.mu.Lock()
+	if state.removed {
+		state.mu.Unlock()
+		s.deleteSpilled(segments)
+		return 0, nil
+	}
+	start := encoding.EncodeTsKey(state.uniqueID, uint64(span.TableID), 0)
+	toNext := to.Next()
+	end := encoding.EncodeTsKey(state.uniqueID, uint64(span.TableID), toNext.CommitTs, toNext.StartTs)
+	if err = db.DeleteRange(start, end, &pebble.WriteOptions{Sync: false}); err != nil {
+		state.mu.Unlock()
+		s.deleteSpilled(segments)
+		return 0, err
+	}
+	state.spilled = append(state.spilled, segments...)
+	state.spilledTo = to
+	s.diskUsage.Add(-state.releaseUsage(to))
+	// Segments may be cleaned during they are written.
+	cleaned := state.cleanSpilled()
+	state.mu.Unlock()
+	s.deleteSpilled(cleaned)
+
+	spilled := int64(0)
+	for _, segment := range segments {
+		spilled += segment.size
+	}
+	log.Info("events are spilled",
+		zap.String("namespace", s.changefeedID.Namespace),
+		zap.String("changefeed", s.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Any("from", from),
+		zap.Any("to", to),
+		zap.Int("segments", len(segments)),
+		zap.Int64("bytes", spilled))
+	return spilled, nil
+}
+
+// deleteSpilled deletes segment files. Errors are only logged, because a
+// leaked file doesn't affect correctness and is cleared at next start.
+func (s *EventSorter) deleteSpilled(segments []*spilledSegment) {
+	if len(segments) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), spillDeleteTimeout)
+	defer cancel()
+	for _, segment := range segments {
+		if err := s.spillStorage.DeleteFile(ctx, segment.name); err != nil {
+			log.Warn("delete spilled segment fails",
+				zap.String("namespace", s.changefeedID.Namespace),
+				zap.String("changefeed", s.changefeedID.ID),
+				zap.String("name", segment.name),
+				zap.Error(err))
+		}
+	}
+}
+
+// cleanSpilled removes segments whose events are all cleaned, and returns
+// them. It must be called with state.mu locked.
+func (state *tableState) cleanSpilled() []*spilledSegment {
+	i := 0
+	for i < len(state.spilled) && state.spilled[i].last.Compare(state.cleaned) <= 0 {
+		i++
+	}
+	cleaned := state.spilled[:i:i]
+	state.spilled = state.spilled[i:]
+	return cleaned
+}
+
+// fetchSpilled returns segments which may contain events in
+// [lowerBound, upperBound]. It must be called with state.mu locked.
+func (state *tableState) fetchSpilled(lowerBound, upperBound engine.Position) []*spilledSegment {
+	var segments []*spilledSegment
+	for _, segment := range state.spilled {
+		if segment.last.Compare(lowerBound) < 0 {
+			continue
+		}
+		segments = append(segments, segment)
+		if segment.last.Compare(upperBound) >= 0 {
+			break
+		}
+	}
+	return segments
+}
+
+// appendSpilledEvent encodes a key-value pair in the format of
+// [key length][key][value length][value], lengths are uint32.
+func appendSpilledEvent(buf, key, value []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(key)))
+	buf = append(buf, key...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(value)))
+	return append(buf, value...)
+}
+
+// spilledReader reads key-value pairs from a spilled segment sequentially.
+type spilledReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+}
+
+func newSpilledReader(r io.Reader) *spilledReader {
+	reader := &spilledReader{r: bufio.NewReaderSize(r, spillReadBufferSize)}
+	if closer, ok := r.(io.Closer); ok {
+		reader.closer = closer
+	}
+	return reader
+}
+
+// openSpilledSegment opens a spilled segment in the spill storage.
+func openSpilledSegment(
+	ctx context.Context, spillStorage storage.ExternalStorage, segment *spilledSegment,
+) (*spilledReader, error) {
+	file, err := spillStorage.Open(ctx, segment.name)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return newSpilledReader(file), nil
+}
+
+// next decodes the next key-value pair, valid is false if there is no more
+// pairs in the segment.
+func (r *spilledReader) next() (key, value []byte, valid bool, err error) {
+	var size [4]byte
+	for i, field := range []*[]byte{&key, &value} {
+		if _, err = io.ReadFull(r.r, size[:]); err != nil {
+			if i == 0 && err == io.EOF {
+				return nil, nil, false, nil
+			}
+			return nil, nil, false, spilledCorruptedError(err)
+		}
+		*field = make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err = io.ReadFull(r.r, *field); err != nil {
+			return nil, nil, false, spilledCorruptedError(err)
+		}
+	}
+	return key, value, true, nil
+}
+
+func (r *spilledReader) close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+func spilledCorruptedError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.ErrUnmarshalFailed.GenWithStack("spilled segment is corrupted")
+	}
+	return errors.WrapError(errors.ErrExternalStorageAPI, err)
+}
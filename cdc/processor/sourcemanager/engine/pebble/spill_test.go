@@ -0,0 +1,176 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpill(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, 1024*1024*10)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	spillDir := t.TempDir()
+	spillStorage, err := util.GetExternalStorageFromURI(ctx, "file://"+spillDir)
+	require.Nil(t, err)
+	countSpilled := func() int {
+		count := 0
+		err := spillStorage.WalkDir(ctx, &storage.WalkOption{}, func(string, int64) error {
+			count++
+			return nil
+		})
+		require.Nil(t, err)
+		return count
+	}
+
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := New(cf, []*pebble.DB{db}, Options{SpillStorage: spillStorage, SpillPrefix: "capture"})
+	defer s.Close()
+
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span)
+	resolvedTs := make(chan model.Ts)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs <- ts })
+	waitResolved := func(expected model.Ts) {
+		select {
+		case ts := <-resolvedTs:
+			require.Equal(t, expected, ts)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "must get a resolved timestamp instead of timeout")
+		}
+	}
+	fetch := func(lowerBound, upperBound engine.Position) ([]*model.PolymorphicEvent, []engine.Position) {
+		iter := s.FetchByTable(ctx, span, lowerBound, upperBound)
+		defer iter.Close()
+		var events []*model.PolymorphicEvent
+		var positions []engine.Position
+		for {
+			event, pos, err := iter.Next()
+			require.Nil(t, err)
+			if event == nil {
+				return events, positions
+			}
+			events = append(events, event)
+			positions = append(positions, pos)
+		}
+	}
+
+	inputEvents := []*model.PolymorphicEvent{
+		model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType: model.OpTypePut, Key: []byte{1}, StartTs: 1, CRTs: 2,
+		}),
+		model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType: model.OpTypePut, Key: []byte{2}, StartTs: 1, CRTs: 2,
+		}),
+		model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType: model.OpTypePut, Key: []byte{1}, StartTs: 3, CRTs: 4,
+		}),
+		model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType: model.OpTypePut, Key: []byte{1}, StartTs: 5, CRTs: 6,
+		}),
+	}
+	s.Add(span, inputEvents[:3]...)
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 4))
+	waitResolved(4)
+
+	// Events before or at the resolved ts are spilled.
+	spilled, err := s.Spill(ctx, 1)
+	require.Nil(t, err)
+	require.Greater(t, spilled, int64(0))
+	require.Equal(t, 1, countSpilled())
+	usage, _ := s.DiskUsage()
+	require.Equal(t, int64(0), usage)
+	spilled, err = s.Spill(ctx, 1)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), spilled)
+
+	s.Add(span, inputEvents[3])
+	s.Add(span, model.NewResolvedPolymorphicEvent(0, 6))
+	waitResolved(6)
+
+	// Spilled events are fetched before events in pebble.
+	events, positions := fetch(engine.Position{}, engine.GenCommitFence(6))
+	require.Equal(t, inputEvents, events)
+	require.Equal(t, []engine.Position{
+		{}, {CommitTs: 2, StartTs: 1}, {CommitTs: 4, StartTs: 3}, {CommitTs: 6, StartTs: 5},
+	}, positions)
+	events, _ = fetch(engine.Position{CommitTs: 4, StartTs: 3}, engine.GenCommitFence(4))
+	require.Equal(t, inputEvents[2:3], events)
+
+	// A segment is deleted after all events in it are cleaned.
+	require.Nil(t, s.CleanByTable(span, engine.GenCommitFence(2)))
+	require.Equal(t, 1, countSpilled())
+	events, _ = fetch(engine.Position{CommitTs: 2, StartTs: 2}, engine.GenCommitFence(6))
+	require.Equal(t, inputEvents[2:], events)
+	require.Nil(t, s.CleanByTable(span, engine.GenCommitFence(4)))
+	require.Equal(t, 0, countSpilled())
+	events, _ = fetch(engine.Position{CommitTs: 4, StartTs: 4}, engine.GenCommitFence(6))
+	require.Equal(t, inputEvents[3:], events)
+
+	// Segments are deleted after the table is removed.
+	spilled, err = s.Spill(ctx, 1)
+	require.Nil(t, err)
+	require.Greater(t, spilled, int64(0))
+	require.Equal(t, 1, countSpilled())
+	s.RemoveTable(span)
+	require.Equal(t, 0, countSpilled())
+}
+
+func TestSpilledReader(t *testing.T) {
+	data := appendSpilledEvent(nil, []byte("key1"), []byte("value1"))
+	data = appendSpilledEvent(data, []byte("key2"), []byte{})
+
+	r := newSpilledReader(bytes.NewReader(data))
+	key, value, valid, err := r.next()
+	require.Nil(t, err)
+	require.True(t, valid)
+	require.Equal(t, []byte("key1"), key)
+	require.Equal(t, []byte("value1"), value)
+	key, value, valid, err = r.next()
+	require.Nil(t, err)
+	require.True(t, valid)
+	require.Equal(t, []byte("key2"), key)
+	require.Empty(t, value)
+	_, _, valid, err = r.next()
+	require.Nil(t, err)
+	require.False(t, valid)
+	require.Nil(t, r.close())
+
+	// A truncated segment is reported as corrupted.
+	r = newSpilledReader(bytes.NewReader(data[:len(data)-1]))
+	_, _, valid, err = r.next()
+	require.Nil(t, err)
+	require.True(t, valid)
+	_, _, _, err = r.next()
+	require.Error(t, err)
+	_, _, _, err = newSpilledReader(bytes.NewReader(data[:6])).next()
+	require.Error(t, err)
+}
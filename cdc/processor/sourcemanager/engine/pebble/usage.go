@@ -0,0 +1,134 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+)
+
+const (
+	// maxUsageBuckets limits buckets of a table. New writes are merged into
+	// the last bucket after it's reached, which delays releasing them.
+	maxUsageBuckets = 4096
+
+	quotaCheckInterval = 50 * time.Millisecond
+)
+
+// usageBucket records bytes written into pebble for a table. Pebble can't
+// tell how many bytes are deleted by DeleteRange, so bytes are released
+// when all events in the bucket are cleaned or spilled.
+type usageBucket struct {
+	maxCommitTs model.Ts
+	bytes       int64
+}
+
+// addUsage records bytes of events written into pebble.
+// It must be called with state.mu locked.
+func (state *tableState) addUsage(bucket usageBucket) {
+	state.diskUsage.Add(bucket.bytes)
+	if n := len(state.buckets); n > 0 {
+		last := &state.buckets[n-1]
+		if bucket.maxCommitTs <= last.maxCommitTs || n >= maxUsageBuckets {
+			last.bytes += bucket.bytes
+			if bucket.maxCommitTs > last.maxCommitTs {
+				last.maxCommitTs = bucket.maxCommitTs
+			}
+			return
+		}
+	}
+	state.buckets = append(state.buckets, bucket)
+}
+
+// releaseUsage releases buckets whose events are all before or at the given
+// position, and returns the released bytes.
+// It must be called with state.mu locked.
+func (state *tableState) releaseUsage(upperBound engine.Position) int64 {
+	released := int64(0)
+	i := 0
+	for ; i < len(state.buckets); i++ {
+		maxCommitTs := state.buckets[i].maxCommitTs
+		if maxCommitTs > upperBound.CommitTs ||
+			(maxCommitTs == upperBound.CommitTs && !upperBound.IsCommitFence()) {
+			break
+		}
+		released += state.buckets[i].bytes
+	}
+	state.buckets = state.buckets[i:]
+	state.diskUsage.Add(-released)
+	return released
+}
+
+// resolvedUsage returns bytes of events which are resolved but not cleaned,
+// that is, bytes which can be released by consuming events of the table.
+func (state *tableState) resolvedUsage() int64 {
+	resolved := state.sortedResolved.Load()
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	usage := int64(0)
+	for _, bucket := range state.buckets {
+		if bucket.maxCommitTs > resolved {
+			break
+		}
+		usage += bucket.bytes
+	}
+	return usage
+}
+
+// WaitForQuota implements engine.SortEngine. When the disk usage of the
+// changefeed exceeds the quota, tables with resolved events are blocked until
+// all of them are consumed. Tables without resolved events are not blocked,
+// otherwise they could never make progress.
+func (s *EventSorter) WaitForQuota(ctx context.Context, span tablepb.Span) error {
+	if s.diskQuota == 0 || uint64(s.diskUsage.Load()) < s.diskQuota {
+		return nil
+	}
+	s.mu.RLock()
+	state, exists := s.tables.Get(span)
+	s.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	ticker := time.NewTicker(quotaCheckInterval)
+	defer ticker.Stop()
+	for uint64(s.diskUsage.Load()) >= s.diskQuota && state.resolvedUsage() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.closed:
+			return nil
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// DiskUsage returns bytes of events stored in pebble by the changefeed and
+// by each table. Bytes are estimated with sizes of uncompressed keys and
+// values, and spilled events are not included.
+func (s *EventSorter) DiskUsage() (int64, map[model.TableID]int64) {
+	tables := make(map[model.TableID]int64)
+	s.mu.RLock()
+	s.tables.Range(func(span tablepb.Span, state *tableState) bool {
+		tables[span.TableID] += state.diskUsage.Load()
+		return true
+	})
+	s.mu.RUnlock()
+	return s.diskUsage.Load(), tables
+}
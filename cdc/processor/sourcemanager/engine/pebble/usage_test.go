@@ -0,0 +1,112 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageBuckets(t *testing.T) {
+	state := &tableState{}
+	state.addUsage(usageBucket{maxCommitTs: 2, bytes: 10})
+	state.addUsage(usageBucket{maxCommitTs: 4, bytes: 20})
+	// Merged into the last bucket.
+	state.addUsage(usageBucket{maxCommitTs: 3, bytes: 30})
+	require.Len(t, state.buckets, 2)
+	require.Equal(t, int64(60), state.diskUsage.Load())
+
+	require.Equal(t, int64(0), state.releaseUsage(engine.Position{CommitTs: 2, StartTs: 0}))
+	require.Equal(t, int64(10), state.releaseUsage(engine.GenCommitFence(2)))
+	require.Equal(t, int64(0), state.releaseUsage(engine.GenCommitFence(3)))
+	require.Equal(t, int64(50), state.releaseUsage(engine.GenCommitFence(5)))
+	require.Empty(t, state.buckets)
+	require.Equal(t, int64(0), state.diskUsage.Load())
+}
+
+func TestDiskQuota(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, 1024*1024*10)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := New(cf, []*pebble.DB{db}, Options{DiskQuota: 1})
+	defer s.Close()
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	s.AddTable(span1)
+	s.AddTable(span2)
+	resolvedTs := make(chan model.Ts, 1)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs <- ts })
+
+	// No events, no limits.
+	require.Nil(t, s.WaitForQuota(context.Background(), span1))
+
+	s.Add(span1, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte{1}, StartTs: 1, CRTs: 2,
+	}))
+	s.Add(span1, model.NewResolvedPolymorphicEvent(0, 2))
+	select {
+	case <-resolvedTs:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "must get a resolved timestamp instead of timeout")
+	}
+	s.Add(span2, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte{1}, StartTs: 3, CRTs: 4,
+	}))
+	require.Eventually(t, func() bool {
+		_, tables := s.DiskUsage()
+		return tables[2] > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	usage, tables := s.DiskUsage()
+	require.Equal(t, tables[1]+tables[2], usage)
+	require.Greater(t, tables[1], int64(0))
+
+	// The table with resolved events is blocked until they are consumed.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.WaitForQuota(ctx, span1), context.DeadlineExceeded)
+	// The table without resolved events is never blocked.
+	require.Nil(t, s.WaitForQuota(context.Background(), span2))
+
+	done := make(chan error, 1)
+	go func() { done <- s.WaitForQuota(context.Background(), span1) }()
+	require.Nil(t, s.CleanByTable(span1, engine.GenCommitFence(2)))
+	select {
+	case err := <-done:
+		require.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "must be unblocked after events are cleaned")
+	}
+	usage, tables = s.DiskUsage()
+	require.Equal(t, int64(0), tables[1])
+	require.Equal(t, tables[2], usage)
+
+	// Usage of a removed table is released.
+	s.RemoveTable(span2)
+	usage, _ = s.DiskUsage()
+	require.Equal(t, int64(0), usage)
+}
@@ -128,10 +128,10 @@ func (m *SourceManager) OnResolve(action func(tablepb.Span, model.Ts)) {
 
 // FetchByTable just wrap the engine's FetchByTable method.
 func (m *SourceManager) FetchByTable(
-	span tablepb.Span, lowerBound, upperBound engine.Position,
+	ctx context.Context, span tablepb.Span, lowerBound, upperBound engine.Position,
 	quota *memquota.MemQuota,
 ) *engine.MountedEventIter {
-	iter := m.engine.FetchByTable(span, lowerBound, upperBound)
+	iter := m.engine.FetchByTable(ctx, span, lowerBound, upperBound)
 	return engine.NewMountedEventIter(m.changefeedID, iter, m.mg, defaultMaxBatchSize, quota)
 }
 
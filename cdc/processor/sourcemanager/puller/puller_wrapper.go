@@ -128,6 +128,13 @@ func (n *WrapperImpl) Start(
 					continue
 				}
 				pEvent := model.NewPolymorphicEvent(rawKV)
+				// Only data events are blocked, resolved events are required
+				// to consume events in the engine.
+				if !pEvent.IsResolved() {
+					if err := eventSortEngine.WaitForQuota(ctx, n.span); err != nil {
+						return nil
+					}
+				}
 				eventSortEngine.Add(n.span, pEvent)
 			}
 		}
@@ -23,6 +23,13 @@ import (
 type LogMeta struct {
 	CheckpointTs uint64 `msg:"checkpointTs"`
 	ResolvedTs   uint64 `msg:"resolvedTs"`
+	// RetainedTs is the lower bound of retained redo logs, all events
+	// whose commitTs is not less than it can be found in redo logs.
+	RetainedTs uint64 `msg:"retainedTs"`
+	// CompactedTs is the upper bound of compacted redo logs, other log files
+	// whose max commitTs is not greater than it are superseded. Superseded
+	// updates are only dropped before RetainedTs.
+	CompactedTs uint64 `msg:"compactedTs"`
 }
 
 // ParseMeta parses meta.
@@ -38,3 +45,17 @@ func ParseMeta(metas []*LogMeta, checkpointTs, resolvedTs *model.Ts) {
 		}
 	}
 }
+
+// ParseCompactionMeta parses the retained ts and compacted ts from metas.
+func ParseCompactionMeta(metas []*LogMeta, retainedTs, compactedTs *model.Ts) {
+	*retainedTs = 0
+	*compactedTs = 0
+	for _, meta := range metas {
+		if *retainedTs < meta.RetainedTs {
+			*retainedTs = meta.RetainedTs
+		}
+		if *compactedTs < meta.CompactedTs {
+			*compactedTs = meta.CompactedTs
+		}
+	}
+}
@@ -36,6 +36,18 @@ func (z *LogMeta) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "ResolvedTs")
 				return
 			}
+		case "retainedTs":
+			z.RetainedTs, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "RetainedTs")
+				return
+			}
+		case "compactedTs":
+			z.CompactedTs, err = dc.ReadUint64()
+			if err != nil {
+				err = msgp.WrapError(err, "CompactedTs")
+				return
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -49,9 +61,9 @@ func (z *LogMeta) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z LogMeta) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
+	// map header, size 4
 	// write "checkpointTs"
-	err = en.Append(0x82, 0xac, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x54, 0x73)
+	err = en.Append(0x84, 0xac, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x54, 0x73)
 	if err != nil {
 		return
 	}
@@ -70,19 +82,45 @@ func (z LogMeta) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "ResolvedTs")
 		return
 	}
+	// write "retainedTs"
+	err = en.Append(0xaa, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x64, 0x54, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.RetainedTs)
+	if err != nil {
+		err = msgp.WrapError(err, "RetainedTs")
+		return
+	}
+	// write "compactedTs"
+	err = en.Append(0xab, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x54, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.CompactedTs)
+	if err != nil {
+		err = msgp.WrapError(err, "CompactedTs")
+		return
+	}
 	return
 }
 
 // MarshalMsg implements msgp.Marshaler
 func (z LogMeta) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
+	// map header, size 4
 	// string "checkpointTs"
-	o = append(o, 0x82, 0xac, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x54, 0x73)
+	o = append(o, 0x84, 0xac, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x54, 0x73)
 	o = msgp.AppendUint64(o, z.CheckpointTs)
 	// string "resolvedTs"
 	o = append(o, 0xaa, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x54, 0x73)
 	o = msgp.AppendUint64(o, z.ResolvedTs)
+	// string "retainedTs"
+	o = append(o, 0xaa, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x64, 0x54, 0x73)
+	o = msgp.AppendUint64(o, z.RetainedTs)
+	// string "compactedTs"
+	o = append(o, 0xab, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x54, 0x73)
+	o = msgp.AppendUint64(o, z.CompactedTs)
 	return
 }
 
@@ -116,6 +154,18 @@ func (z *LogMeta) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "ResolvedTs")
 				return
 			}
+		case "retainedTs":
+			z.RetainedTs, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RetainedTs")
+				return
+			}
+		case "compactedTs":
+			z.CompactedTs, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "CompactedTs")
+				return
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -130,6 +180,6 @@ func (z *LogMeta) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z LogMeta) Msgsize() (s int) {
-	s = 1 + 13 + msgp.Uint64Size + 11 + msgp.Uint64Size
+	s = 1 + 13 + msgp.Uint64Size + 11 + msgp.Uint64Size + 11 + msgp.Uint64Size + 12 + msgp.Uint64Size
 	return
 }
@@ -0,0 +1,347 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/model/codec"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/pingcap/tiflow/pkg/uuid"
+	"go.uber.org/zap"
+)
+
+// compactor merges small row log files before the checkpoint into larger
+// ones. Superseded updates of a row are dropped only before the retained ts,
+// updates in the retention window are kept, so that retained logs can still
+// be applied up to any ts in the window.
+//
+// Files are compacted in the order of their max commitTs. A batch of files
+// whose max commitTs is not greater than ts T is compacted only if all other
+// files only contain events after T, so that all events in (compactedTs, T]
+// are merged together. The compacted ts is recorded in redo meta, log files
+// are then selected by the reader as follows:
+//   - compacted files whose max commitTs <= compactedTs are valid.
+//   - other files whose max commitTs <= compactedTs are superseded.
+type compactor struct {
+	changeFeedID  model.ChangeFeedID
+	extStorage    storage.ExternalStorage
+	uuidGenerator uuid.Generator
+	// targetSize is the size in bytes of compacted files.
+	targetSize int64
+}
+
+type compactLogFile struct {
+	path        string
+	size        int64
+	maxCommitTs model.Ts
+	minCommitTs model.Ts
+}
+
+func newCompactor(
+	changeFeedID model.ChangeFeedID, extStorage storage.ExternalStorage,
+	uuidGenerator uuid.Generator, targetSizeInMB int64,
+) *compactor {
+	return &compactor{
+		changeFeedID:  changeFeedID,
+		extStorage:    extStorage,
+		uuidGenerator: uuidGenerator,
+		targetSize:    targetSizeInMB * redo.Megabyte,
+	}
+}
+
+// removeStaleFiles removes log files superseded by the flushed compacted ts,
+// and compacted files not recorded by the compacted ts, which are left by
+// a failed compaction.
+func (c *compactor) removeStaleFiles(
+	ctx context.Context, flushedCompactedTs, compactedTs model.Ts,
+) error {
+	changefeedMatcher := getChangefeedMatcher(c.changeFeedID)
+	return util.RemoveFilesIf(ctx, c.extStorage, func(path string) bool {
+		maxCommitTs, ok := c.parseRowLogFile(path, changefeedMatcher)
+		if !ok {
+			return false
+		}
+		if redo.IsCompactedLogFile(path) {
+			return maxCommitTs > compactedTs
+		}
+		return maxCommitTs <= flushedCompactedTs
+	}, nil)
+}
+
+// compact compacts row log files after compactedTs and before checkpointTs,
+// updates before retainedTs are merged. It returns the new compacted ts.
+func (c *compactor) compact(
+	ctx context.Context, checkpointTs, compactedTs, retainedTs model.Ts,
+) (model.Ts, error) {
+	files, err := c.listFiles(ctx, compactedTs)
+	if err != nil {
+		return compactedTs, errors.Trace(err)
+	}
+	// suffixMinTs[i] is the min commitTs of events in files[i:].
+	suffixMinTs := make([]model.Ts, len(files)+1)
+	suffixMinTs[len(files)] = math.MaxUint64
+	for i := len(files) - 1; i >= 0; i-- {
+		suffixMinTs[i] = suffixMinTs[i+1]
+		if files[i].minCommitTs < suffixMinTs[i] {
+			suffixMinTs[i] = files[i].minCommitTs
+		}
+	}
+
+	start := 0
+	var size int64
+	for i, file := range files {
+		if file.maxCommitTs >= checkpointTs {
+			break
+		}
+		size += file.size
+		ts := file.maxCommitTs
+		if size < c.targetSize || suffixMinTs[i+1] <= ts {
+			continue
+		}
+		if err := c.writeCompactedFile(ctx, files[start:i+1], ts, retainedTs); err != nil {
+			return compactedTs, errors.Trace(err)
+		}
+		log.Info("redo log files compacted",
+			zap.String("namespace", c.changeFeedID.Namespace),
+			zap.String("changefeed", c.changeFeedID.ID),
+			zap.Int("fileCount", i+1-start),
+			zap.Int64("size", size),
+			zap.Uint64("compactedTs", ts))
+		compactedTs = ts
+		start = i + 1
+		size = 0
+	}
+	return compactedTs, nil
+}
+
+// listFiles lists all non-compacted row log files after compactedTs, files
+// are sorted by max commitTs. Files are read one by one to get their min
+// commitTs, logs are not kept in memory.
+func (c *compactor) listFiles(
+	ctx context.Context, compactedTs model.Ts,
+) ([]*compactLogFile, error) {
+	changefeedMatcher := getChangefeedMatcher(c.changeFeedID)
+	var files []*compactLogFile
+	err := c.extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+		path = strings.TrimPrefix(path, "/")
+		maxCommitTs, ok := c.parseRowLogFile(path, changefeedMatcher)
+		if !ok || maxCommitTs <= compactedTs || redo.IsCompactedLogFile(path) {
+			return nil
+		}
+		files = append(files, &compactLogFile{
+			path: path, size: size, maxCommitTs: maxCommitTs,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].maxCommitTs < files[j].maxCommitTs
+	})
+
+	for _, file := range files {
+		logs, err := c.readLogs(ctx, file.path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		file.minCommitTs = math.MaxUint64
+		for _, l := range logs {
+			if ts := l.GetCommitTs(); ts < file.minCommitTs {
+				file.minCommitTs = ts
+			}
+		}
+	}
+	return files, nil
+}
+
+func (c *compactor) readLogs(ctx context.Context, path string) ([]*model.RedoLog, error) {
+	data, err := c.extStorage.ReadFile(ctx, path)
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+	return reader.ReadAllLogs(data)
+}
+
+// writeCompactedFile merges logs of the files, logs of each table are
+// written to a separate compacted file, so that a compacted file only
+// contains events of one table like files written by the log writer.
+func (c *compactor) writeCompactedFile(
+	ctx context.Context, files []*compactLogFile, maxCommitTs, retainedTs model.Ts,
+) error {
+	// Only logs of the batch are kept in memory, whose size is about the
+	// target size.
+	tableLogs := make(map[model.TableID][]*model.RedoLog)
+	for _, file := range files {
+		fileLogs, err := c.readLogs(ctx, file.path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, l := range fileLogs {
+			tableID := l.RedoRow.Row.Table.TableID
+			tableLogs[tableID] = append(tableLogs[tableID], l)
+		}
+	}
+	tableIDs := make([]model.TableID, 0, len(tableLogs))
+	for tableID := range tableLogs {
+		tableIDs = append(tableIDs, tableID)
+	}
+	sort.Slice(tableIDs, func(i, j int) bool { return tableIDs[i] < tableIDs[j] })
+
+	for _, tableID := range tableIDs {
+		data, err := encodeRowLogs(mergeRowLogs(tableLogs[tableID], retainedTs))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		name := c.getCompactedLogFileName(maxCommitTs)
+		if err := c.extStorage.WriteFile(ctx, name, data); err != nil {
+			return errors.WrapError(errors.ErrExternalStorageAPI, err)
+		}
+	}
+	return nil
+}
+
+// encodeRowLogs encodes logs in the frame format of the log writer.
+func encodeRowLogs(logs []*model.RedoLog) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, l := range logs {
+		data, err := codec.MarshalRedoLog(l, nil)
+		if err != nil {
+			return nil, errors.WrapError(errors.ErrMarshalFailed, err)
+		}
+		lenField, padBytes := writer.EncodeFrameSize(len(data))
+		if err := binary.Write(&buf, binary.LittleEndian, lenField); err != nil {
+			return nil, errors.Trace(err)
+		}
+		buf.Write(data)
+		buf.Write(make([]byte, padBytes))
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *compactor) getCompactedLogFileName(maxCommitTs model.Ts) string {
+	uid := c.uuidGenerator.NewString()
+	if model.DefaultNamespace == c.changeFeedID.Namespace {
+		return fmt.Sprintf(redo.RedoLogFileFormatV1,
+			redo.RedoCompactedLogCaptureID, c.changeFeedID.ID,
+			redo.RedoRowLogFileType, maxCommitTs, uid, redo.LogEXT)
+	}
+	return fmt.Sprintf(redo.RedoLogFileFormatV2,
+		redo.RedoCompactedLogCaptureID, c.changeFeedID.Namespace, c.changeFeedID.ID,
+		redo.RedoRowLogFileType, maxCommitTs, uid, redo.LogEXT)
+}
+
+// parseRowLogFile returns the max commitTs of a row log file of the changefeed.
+func (c *compactor) parseRowLogFile(
+	path string, changefeedMatcher string,
+) (model.Ts, bool) {
+	if !strings.Contains(path, changefeedMatcher) || filepath.Ext(path) != redo.LogEXT {
+		return 0, false
+	}
+	commitTs, fileType, err := redo.ParseLogFileName(path)
+	if err != nil || fileType != redo.RedoRowLogFileType {
+		return 0, false
+	}
+	return commitTs, true
+}
+
+// mergeRowLogs merges changes of the same row, only the state before the
+// first change and the state after the last change are kept. Changes which
+// modify handle keys are kept as is, and they separate changes of related
+// rows before and after them.
+//
+// A merged change only has the commitTs of the last change, so only changes
+// before retainedTs are merged. Logs are applied from a start ts not less
+// than the retained ts, so merged changes are never applied partially, and
+// changes in the retention window can be applied up to any ts. Logs can be
+// applied from any ts in safe mode, so a row inserted and then deleted is
+// kept as a deletion, in case the insertion has been applied.
+func mergeRowLogs(logs []*model.RedoLog, retainedTs model.Ts) []*model.RedoLog {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].GetCommitTs() < logs[j].GetCommitTs()
+	})
+
+	merged := make([]*model.RedoLog, 0, len(logs))
+	pending := make(map[string]int)
+	for _, l := range logs {
+		// Logs are sorted, so changes after it are all kept as is.
+		if l.GetCommitTs() >= retainedTs {
+			merged = append(merged, l)
+			continue
+		}
+		row := l.RedoRow.Row
+		preKey := rowHandleKey(row, row.PreColumns)
+		key := rowHandleKey(row, row.Columns)
+		if row.IsDelete() {
+			key = preKey
+		} else if !row.IsInsert() && preKey != key {
+			key = ""
+		}
+		if key == "" {
+			delete(pending, preKey)
+			delete(pending, rowHandleKey(row, row.Columns))
+			merged = append(merged, l)
+			continue
+		}
+
+		idx, ok := pending[key]
+		if !ok {
+			pending[key] = len(merged)
+			merged = append(merged, l)
+			continue
+		}
+		first := merged[idx].RedoRow.Row
+		last := *row
+		if !first.IsInsert() || !last.IsDelete() {
+			last.PreColumns = first.PreColumns
+		}
+		merged[idx] = &model.RedoLog{
+			RedoRow: model.RedoRowChangedEvent{Row: &last},
+			Type:    model.RedoLogTypeRow,
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].GetCommitTs() < merged[j].GetCommitTs()
+	})
+	return merged
+}
+
+func rowHandleKey(row *model.RowChangedEvent, cols []*model.Column) string {
+	var b strings.Builder
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			fmt.Fprintf(&b, "%s=%s,", col.Name, model.ColumnValueString(col.Value))
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%s", row.Table.TableID, b.String())
+}
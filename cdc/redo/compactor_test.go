@@ -0,0 +1,284 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/model/codec"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/pkg/applier"
+	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/pingcap/tiflow/pkg/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRowLog(commitTs model.Ts, pre, post []int) *model.RedoLog {
+	cols := func(values []int) []*model.Column {
+		if len(values) == 0 {
+			return nil
+		}
+		return []*model.Column{
+			{Name: "id", Value: values[0], Flag: model.HandleKeyFlag},
+			{Name: "v", Value: values[1]},
+		}
+	}
+	return &model.RedoLog{
+		RedoRow: model.RedoRowChangedEvent{Row: &model.RowChangedEvent{
+			CommitTs:   commitTs,
+			Table:      &model.TableName{TableID: 1},
+			PreColumns: cols(pre),
+			Columns:    cols(post),
+		}},
+		Type: model.RedoLogTypeRow,
+	}
+}
+
+func TestMergeRowLogs(t *testing.T) {
+	t.Parallel()
+
+	logs := mergeRowLogs([]*model.RedoLog{
+		// row 1 is inserted, updated and then deleted.
+		newTestRowLog(1, nil, []int{1, 1}),
+		newTestRowLog(3, []int{1, 1}, []int{1, 2}),
+		newTestRowLog(5, []int{1, 2}, nil),
+		// row 2 is updated twice.
+		newTestRowLog(2, []int{2, 1}, []int{2, 2}),
+		newTestRowLog(4, []int{2, 2}, []int{2, 3}),
+		// row 3 is deleted, the handle key of row 4 is changed to 3.
+		newTestRowLog(6, []int{3, 1}, nil),
+		newTestRowLog(7, []int{4, 1}, []int{3, 1}),
+		newTestRowLog(8, []int{3, 1}, []int{3, 2}),
+	}, 9)
+	require.Len(t, logs, 5)
+	require.Equal(t, model.Ts(4), logs[0].GetCommitTs())
+	require.Equal(t, 1, logs[0].RedoRow.Row.PreColumns[1].Value)
+	require.Equal(t, 3, logs[0].RedoRow.Row.Columns[1].Value)
+	// The deletion is kept in case the insertion of row 1 has been applied.
+	require.Equal(t, model.Ts(5), logs[1].GetCommitTs())
+	require.True(t, logs[1].RedoRow.Row.IsDelete())
+	require.Equal(t, 2, logs[1].RedoRow.Row.PreColumns[1].Value)
+	require.Equal(t, model.Ts(6), logs[2].GetCommitTs())
+	require.Equal(t, model.Ts(7), logs[3].GetCommitTs())
+	require.Equal(t, model.Ts(8), logs[4].GetCommitTs())
+
+	// Changes in the retention window are kept.
+	logs = mergeRowLogs([]*model.RedoLog{
+		newTestRowLog(1, nil, []int{1, 1}),
+		newTestRowLog(2, []int{1, 1}, []int{1, 2}),
+		newTestRowLog(3, []int{1, 2}, []int{1, 3}),
+		newTestRowLog(4, []int{1, 3}, []int{1, 4}),
+	}, 3)
+	require.Len(t, logs, 3)
+	require.Equal(t, model.Ts(2), logs[0].GetCommitTs())
+	require.Empty(t, logs[0].RedoRow.Row.PreColumns)
+	require.Equal(t, model.Ts(3), logs[1].GetCommitTs())
+	require.Equal(t, model.Ts(4), logs[2].GetCommitTs())
+}
+
+func TestCompactor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	extStorage, _, err := util.GetTestExtStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+
+	writeLogFile := func(captureID string, maxCommitTs model.Ts, logs ...*model.RedoLog) {
+		var buf bytes.Buffer
+		for _, l := range logs {
+			data, err := codec.MarshalRedoLog(l, nil)
+			require.NoError(t, err)
+			lenField, padBytes := writer.EncodeFrameSize(len(data))
+			require.NoError(t, binary.Write(&buf, binary.LittleEndian, lenField))
+			buf.Write(data)
+			buf.Write(make([]byte, padBytes))
+		}
+		fileName := fmt.Sprintf(redo.RedoLogFileFormatV1, captureID, changefeedID.ID,
+			redo.RedoRowLogFileType, maxCommitTs, uuid.NewGenerator().NewString(), redo.LogEXT)
+		require.NoError(t, extStorage.WriteFile(ctx, fileName, buf.Bytes()))
+	}
+	writeLogFile("c1", 10,
+		newTestRowLog(2, nil, []int{1, 1}), newTestRowLog(10, []int{1, 1}, []int{1, 2}))
+	writeLogFile("c2", 20,
+		newTestRowLog(15, nil, []int{2, 1}), newTestRowLog(20, []int{1, 2}, []int{1, 3}))
+	// The file contains events before 20, so files before 20 can't be compacted
+	// without it.
+	writeLogFile("c1", 40, newTestRowLog(18, []int{2, 1}, []int{2, 2}))
+	writeLogFile("c2", 50, newTestRowLog(50, []int{2, 2}, []int{2, 3}))
+
+	c := newCompactor(changefeedID, extStorage, uuid.NewGenerator(), 0)
+	files, err := c.listFiles(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+	require.Equal(t, model.Ts(18), files[2].minCommitTs)
+	// Files are compacted until their size reaches the target size.
+	c.targetSize = files[0].size + 1
+	// Files after the checkpoint are not compacted.
+	compactedTs, err := c.compact(ctx, 40, 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(0), compactedTs)
+
+	compactedTs, err = c.compact(ctx, 45, 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(40), compactedTs)
+	files, err = c.listFiles(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	// Compacted files not recorded in meta are removed.
+	require.NoError(t, c.removeStaleFiles(ctx, 0, 0))
+	compactedTs, err = c.compact(ctx, 45, 0, math.MaxUint64)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(40), compactedTs)
+	// Superseded files are removed after the compacted ts is flushed.
+	require.NoError(t, c.removeStaleFiles(ctx, compactedTs, compactedTs))
+
+	var names []string
+	var logs []*model.RedoLog
+	err = extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+		names = append(names, path)
+		data, err := extStorage.ReadFile(ctx, path)
+		require.NoError(t, err)
+		fileLogs, err := reader.ReadAllLogs(data)
+		require.NoError(t, err)
+		logs = append(logs, fileLogs...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+	require.Len(t, logs, 3)
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].GetCommitTs() < logs[j].GetCommitTs()
+	})
+	// Row 2 is inserted at 15 and updated at 18.
+	require.Equal(t, model.Ts(18), logs[0].GetCommitTs())
+	require.Empty(t, logs[0].RedoRow.Row.PreColumns)
+	// Row 1 is inserted at 2 and updated twice.
+	require.Equal(t, model.Ts(20), logs[1].GetCommitTs())
+	require.Empty(t, logs[1].RedoRow.Row.PreColumns)
+	require.EqualValues(t, 3, logs[1].RedoRow.Row.Columns[1].Value)
+	require.Equal(t, model.Ts(50), logs[2].GetCommitTs())
+}
+
+func TestCompactorSeparatesTables(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	extStorage, _, err := util.GetTestExtStorage(ctx, t.TempDir())
+	require.NoError(t, err)
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+	c := newCompactor(changefeedID, extStorage, uuid.NewGenerator(), 0)
+
+	tableLog := func(tableID model.TableID, commitTs model.Ts, post []int) *model.RedoLog {
+		l := newTestRowLog(commitTs, nil, post)
+		l.RedoRow.Row.Table = &model.TableName{TableID: tableID}
+		return l
+	}
+	data, err := encodeRowLogs([]*model.RedoLog{
+		tableLog(1, 5, []int{1, 1}), tableLog(2, 6, []int{1, 2}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, extStorage.WriteFile(ctx, "c1.log", data))
+	data, err = encodeRowLogs([]*model.RedoLog{tableLog(2, 8, []int{2, 1})})
+	require.NoError(t, err)
+	require.NoError(t, extStorage.WriteFile(ctx, "c2.log", data))
+
+	files := []*compactLogFile{{path: "c1.log"}, {path: "c2.log"}}
+	require.NoError(t, c.writeCompactedFile(ctx, files, 10, 10))
+
+	tableRows := make(map[model.TableID]int)
+	err = extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+		if !redo.IsCompactedLogFile(path) {
+			return nil
+		}
+		data, err := extStorage.ReadFile(ctx, path)
+		require.NoError(t, err)
+		logs, err := reader.ReadAllLogs(data)
+		require.NoError(t, err)
+		require.NotEmpty(t, logs)
+		tableID := logs[0].RedoRow.Row.Table.TableID
+		for _, l := range logs {
+			require.Equal(t, tableID, l.RedoRow.Row.Table.TableID)
+		}
+		require.NotContains(t, tableRows, tableID)
+		tableRows[tableID] = len(logs)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[model.TableID]int{1: 1, 2: 2}, tableRows)
+}
+
+func TestApplyCompactedLogsInRetentionWindow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	extStorage, _, err := util.GetTestExtStorage(ctx, dir)
+	require.NoError(t, err)
+	changefeedID := model.DefaultChangeFeedID("test-changefeed")
+
+	// Row 1 is inserted at 10 and updated at 20 and 30.
+	for _, l := range []*model.RedoLog{
+		newTestRowLog(10, nil, []int{1, 1}),
+		newTestRowLog(20, []int{1, 1}, []int{1, 2}),
+		newTestRowLog(30, []int{1, 2}, []int{1, 3}),
+	} {
+		l.RedoRow.Row.Table = &model.TableName{Schema: "test", Table: "t", TableID: 1}
+		data, err := encodeRowLogs([]*model.RedoLog{l})
+		require.NoError(t, err)
+		fileName := fmt.Sprintf(redo.RedoLogFileFormatV1, "c1", changefeedID.ID,
+			redo.RedoRowLogFileType, l.GetCommitTs(), uuid.NewGenerator().NewString(), redo.LogEXT)
+		require.NoError(t, extStorage.WriteFile(ctx, fileName, data))
+	}
+
+	// All logs are retained since 5.
+	c := newCompactor(changefeedID, extStorage, uuid.NewGenerator(), 0)
+	compactedTs, err := c.compact(ctx, 40, 0, 5)
+	require.NoError(t, err)
+	require.Equal(t, model.Ts(30), compactedTs)
+	require.NoError(t, c.removeStaleFiles(ctx, compactedTs, compactedTs))
+	meta := &common.LogMeta{
+		CheckpointTs: 40, ResolvedTs: 40, RetainedTs: 5, CompactedTs: compactedTs,
+	}
+	data, err := meta.MarshalMsg(nil)
+	require.NoError(t, err)
+	metaFile := getMetafileName("c1", changefeedID, uuid.NewGenerator())
+	require.NoError(t, extStorage.WriteFile(ctx, metaFile, data))
+
+	// Logs can be applied up to a ts in the retention window before the
+	// compacted ts.
+	var out bytes.Buffer
+	err = applier.NewRedoApplier(&applier.RedoApplierConfig{
+		Storage:  "file://" + dir,
+		Dir:      t.TempDir(),
+		StartTs:  5,
+		TargetTs: 20,
+		DryRun:   true,
+		Output:   &out,
+	}).Apply(ctx)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "VALUES (1,2)")
+	require.NotContains(t, out.String(), "VALUES (1,3)")
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/uuid"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -56,12 +57,17 @@ type metaManager struct {
 
 	metaCheckpointTs statefulRts
 	metaResolvedTs   statefulRts
+	metaRetainedTs   statefulRts
+	metaCompactedTs  statefulRts
 
 	// This fields are used to process meta files and perform
 	// garbage collection of logs.
 	extStorage    storage.ExternalStorage
 	uuidGenerator uuid.Generator
 	preMetaFile   string
+	// retention is how long redo logs are kept before the checkpoint.
+	retention time.Duration
+	compactor *compactor
 
 	lastFlushTime          time.Time
 	flushIntervalInMs      int64
@@ -114,6 +120,7 @@ func NewMetaManager(ctx context.Context, cfg *config.ConsistentConfig) (*metaMan
 		uuidGenerator:     uuid.NewGenerator(),
 		enabled:           true,
 		flushIntervalInMs: cfg.FlushIntervalInMs,
+		retention:         time.Duration(cfg.RetentionHours) * time.Hour,
 	}
 
 	uri, err := storage.ParseRawURL(cfg.Storage)
@@ -131,6 +138,10 @@ func NewMetaManager(ctx context.Context, cfg *config.ConsistentConfig) (*metaMan
 		return nil, err
 	}
 	m.extStorage = extStorage
+	if cfg.CompactionFileSize > 0 {
+		m.compactor = newCompactor(
+			m.changeFeedID, extStorage, m.uuidGenerator, cfg.CompactionFileSize)
+	}
 	return m, nil
 }
 
@@ -139,7 +150,7 @@ func (m *metaManager) Enabled() bool {
 	return m.enabled
 }
 
-// Run runs bgFlushMeta and bgGC.
+// Run runs bgFlushMeta, bgGC and bgCompact.
 func (m *metaManager) Run(ctx context.Context) error {
 	if m.extStorage == nil {
 		log.Warn("extStorage of redo meta manager is nil, skip running")
@@ -153,6 +164,11 @@ func (m *metaManager) Run(ctx context.Context) error {
 	eg.Go(func() error {
 		return m.bgGC(egCtx)
 	})
+	if m.compactor != nil {
+		eg.Go(func() error {
+			return m.bgCompact(egCtx)
+		})
+	}
 	return eg.Wait()
 }
 
@@ -182,7 +198,12 @@ func (m *metaManager) UpdateMeta(checkpointTs, resolvedTs model.Ts) {
 func (m *metaManager) GetFlushedMeta() common.LogMeta {
 	checkpointTs := m.metaCheckpointTs.getFlushed()
 	resolvedTs := m.metaResolvedTs.getFlushed()
-	return common.LogMeta{CheckpointTs: checkpointTs, ResolvedTs: resolvedTs}
+	return common.LogMeta{
+		CheckpointTs: checkpointTs,
+		ResolvedTs:   resolvedTs,
+		RetainedTs:   m.metaRetainedTs.getFlushed(),
+		CompactedTs:  m.metaCompactedTs.getFlushed(),
+	}
 }
 
 // initMeta will read the meta file from external storage and initialize the meta
@@ -233,6 +254,7 @@ func (m *metaManager) initMeta(ctx context.Context, startTs model.Ts) error {
 	}
 	m.metaResolvedTs.unflushed = resolvedTs
 	m.metaCheckpointTs.unflushed = checkpointTs
+	common.ParseCompactionMeta(metas, &m.metaRetainedTs.unflushed, &m.metaCompactedTs.unflushed)
 	if err := m.maybeFlushMeta(ctx); err != nil {
 		return errors.WrapError(errors.ErrRedoMetaInitialize,
 			errors.Annotate(err, "flush meta file fail"))
@@ -347,14 +369,20 @@ func (m *metaManager) prepareForFlushMeta() (bool, common.LogMeta) {
 	flushed := common.LogMeta{}
 	flushed.CheckpointTs = m.metaCheckpointTs.getFlushed()
 	flushed.ResolvedTs = m.metaResolvedTs.getFlushed()
+	flushed.RetainedTs = m.metaRetainedTs.getFlushed()
+	flushed.CompactedTs = m.metaCompactedTs.getFlushed()
 
 	unflushed := common.LogMeta{}
 	unflushed.CheckpointTs = m.metaCheckpointTs.getUnflushed()
 	unflushed.ResolvedTs = m.metaResolvedTs.getUnflushed()
+	unflushed.RetainedTs = m.metaRetainedTs.getUnflushed()
+	unflushed.CompactedTs = m.metaCompactedTs.getUnflushed()
 
 	hasChange := false
 	if flushed.CheckpointTs < unflushed.CheckpointTs ||
-		flushed.ResolvedTs < unflushed.ResolvedTs {
+		flushed.ResolvedTs < unflushed.ResolvedTs ||
+		flushed.RetainedTs < unflushed.RetainedTs ||
+		flushed.CompactedTs < unflushed.CompactedTs {
 		hasChange = true
 	}
 	return hasChange, unflushed
@@ -363,6 +391,8 @@ func (m *metaManager) prepareForFlushMeta() (bool, common.LogMeta) {
 func (m *metaManager) postFlushMeta(meta common.LogMeta) {
 	m.metaResolvedTs.setFlushed(meta.ResolvedTs)
 	m.metaCheckpointTs.setFlushed(meta.CheckpointTs)
+	m.metaRetainedTs.setFlushed(meta.RetainedTs)
+	m.metaCompactedTs.setFlushed(meta.CompactedTs)
 }
 
 func (m *metaManager) flush(ctx context.Context, meta common.LogMeta) error {
@@ -432,12 +462,13 @@ func (m *metaManager) bgFlushMeta(egCtx context.Context, flushIntervalInMs int64
 	}
 }
 
-// bgGC cleans stale files before the flushed checkpoint in background.
+// bgGC cleans stale files before the flushed checkpoint in background,
+// files are kept for the retention period if it is set.
 func (m *metaManager) bgGC(egCtx context.Context) error {
 	ticker := time.NewTicker(time.Duration(redo.DefaultGCIntervalInMs) * time.Millisecond)
 	defer ticker.Stop()
 
-	preCkpt := uint64(0)
+	preGCTs := uint64(0)
 	for {
 		select {
 		case <-egCtx.Done():
@@ -446,17 +477,17 @@ func (m *metaManager) bgGC(egCtx context.Context) error {
 				zap.String("changefeed", m.changeFeedID.ID))
 			return errors.Trace(egCtx.Err())
 		case <-ticker.C:
-			ckpt := m.metaCheckpointTs.getFlushed()
-			if ckpt == preCkpt {
+			gcTs := m.getGCTs()
+			if gcTs <= preGCTs {
 				continue
 			}
-			preCkpt = ckpt
+			preGCTs = gcTs
 			log.Debug("redo manager GC is triggered",
-				zap.Uint64("checkpointTs", ckpt),
+				zap.Uint64("gcTs", gcTs),
 				zap.String("namespace", m.changeFeedID.Namespace),
 				zap.String("changefeed", m.changeFeedID.ID))
 			err := util.RemoveFilesIf(egCtx, m.extStorage, func(path string) bool {
-				return m.shouldRemoved(path, ckpt)
+				return m.shouldRemoved(path, gcTs)
 			}, nil)
 			if err != nil {
 				log.Warn("redo manager log GC fail",
@@ -464,10 +495,67 @@ func (m *metaManager) bgGC(egCtx context.Context) error {
 					zap.String("changefeed", m.changeFeedID.ID), zap.Error(err))
 				return errors.Trace(err)
 			}
+			m.metaRetainedTs.checkAndSetUnflushed(gcTs)
+		}
+	}
+}
+
+// getGCTs returns the ts before which redo logs can be removed.
+func (m *metaManager) getGCTs() model.Ts {
+	gcTs := m.metaCheckpointTs.getFlushed()
+	if m.retention > 0 {
+		retainedTs := oracle.GoTimeToTS(time.Now().Add(-m.retention))
+		if retainedTs < gcTs {
+			gcTs = retainedTs
+		}
+	}
+	return gcTs
+}
+
+// bgCompact compacts redo logs before the flushed checkpoint in background,
+// updates in the retention window are kept for point-in-time recovery.
+// Compaction is an optimization, so it is retried in the next round if it
+// fails, instead of failing the changefeed.
+func (m *metaManager) bgCompact(egCtx context.Context) error {
+	ticker := time.NewTicker(time.Duration(redo.DefaultCompactIntervalInMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-egCtx.Done():
+			log.Info("redo manager compaction exits as context cancelled",
+				zap.String("namespace", m.changeFeedID.Namespace),
+				zap.String("changefeed", m.changeFeedID.ID))
+			return errors.Trace(egCtx.Err())
+		case <-ticker.C:
+			if err := m.compactOnce(egCtx); err != nil {
+				log.Warn("redo manager log compaction fail",
+					zap.String("namespace", m.changeFeedID.Namespace),
+					zap.String("changefeed", m.changeFeedID.ID), zap.Error(err))
+			}
 		}
 	}
 }
 
+func (m *metaManager) compactOnce(ctx context.Context) error {
+	// Compacted files become valid after the compacted ts is flushed,
+	// so files superseded by them are removed in the next round.
+	compactedTs := m.metaCompactedTs.getUnflushed()
+	err := m.compactor.removeStaleFiles(ctx, m.metaCompactedTs.getFlushed(), compactedTs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Superseded updates are dropped only before the flushed retained ts,
+	// which is not greater than the start ts of applying logs.
+	compactedTs, err = m.compactor.compact(ctx, m.metaCheckpointTs.getFlushed(),
+		compactedTs, m.metaRetainedTs.getFlushed())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.metaCompactedTs.checkAndSetUnflushed(compactedTs)
+	return nil
+}
+
 func getMetafileName(
 	captureID model.CaptureID,
 	changeFeedID model.ChangeFeedID,
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -296,3 +297,22 @@ func TestGCAndCleanup(t *testing.T) {
 	})
 	require.Equal(t, 1, cnt)
 }
+
+func TestGCTsWithRetention(t *testing.T) {
+	t.Parallel()
+
+	m := &metaManager{}
+	m.metaCheckpointTs.setFlushed(oracle.GoTimeToTS(time.Now()))
+	require.Equal(t, m.metaCheckpointTs.getFlushed(), m.getGCTs())
+
+	m.retention = time.Hour
+	gcTs := m.getGCTs()
+	require.Less(t, gcTs, m.metaCheckpointTs.getFlushed())
+	require.InDelta(t, time.Hour.Milliseconds(),
+		oracle.GetTimeFromTS(m.metaCheckpointTs.getFlushed()).Sub(
+			oracle.GetTimeFromTS(gcTs)).Milliseconds(), float64(time.Minute.Milliseconds()))
+
+	// The checkpoint is the upper bound of gc ts.
+	m.metaCheckpointTs.setFlushed(100)
+	require.Equal(t, uint64(100), m.getGCTs())
+}
@@ -47,6 +47,11 @@ func (br *BlackHoleReader) ReadMeta(ctx context.Context) (checkpointTs, resolved
 	return 0, 1, nil
 }
 
+// ReadCompactionMeta implements LogReader.ReadCompactionMeta
+func (br *BlackHoleReader) ReadCompactionMeta(ctx context.Context) (retainedTs, compactedTs uint64, err error) {
+	return 0, 0, nil
+}
+
 // Close implement the Close interface
 func (br *BlackHoleReader) Close() error {
 	return nil
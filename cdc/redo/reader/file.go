@@ -59,10 +59,13 @@ type fileReader interface {
 }
 
 type readerConfig struct {
-	startTs  uint64
-	endTs    uint64
-	dir      string
-	fileType string
+	startTs uint64
+	endTs   uint64
+	// compactedTs is the compacted ts in redo meta, it is used to skip
+	// log files which are superseded by compacted ones.
+	compactedTs uint64
+	dir         string
+	fileType    string
 
 	uri                url.URL
 	useExternalStorage bool
@@ -126,7 +129,8 @@ func downLoadAndSortFiles(ctx context.Context, cfg *readerConfig) ([]io.ReadClos
 	if err != nil {
 		return nil, err
 	}
-	files, err := selectDownLoadFile(ctx, extStorage, cfg.fileType, cfg.startTs)
+	files, err := selectDownLoadFile(
+		ctx, extStorage, cfg.fileType, cfg.startTs, cfg.compactedTs)
 	if err != nil {
 		return nil, err
 	}
@@ -177,14 +181,14 @@ func getSortedFileName(name string) string {
 
 func selectDownLoadFile(
 	ctx context.Context, extStorage storage.ExternalStorage,
-	fixedType string, startTs uint64,
+	fixedType string, startTs, compactedTs uint64,
 ) ([]string, error) {
 	files := []string{}
 	// add changefeed filter and endTs filter
 	err := extStorage.WalkDir(ctx, &storage.WalkOption{},
 		func(path string, size int64) error {
 			fileName := filepath.Base(path)
-			ret, err := shouldOpen(startTs, fileName, fixedType)
+			ret, err := shouldOpen(startTs, compactedTs, fileName, fixedType)
 			if err != nil {
 				log.Warn("check selected log file fail",
 					zap.String("logFile", fileName),
@@ -203,6 +207,19 @@ func selectDownLoadFile(
 	return files, nil
 }
 
+// ReadAllLogs decodes all redo logs from the content of a log file.
+func ReadAllLogs(buf []byte) ([]*model.RedoLog, error) {
+	h, err := readAllFromBuffer(buf)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*model.RedoLog, 0, len(h))
+	for _, item := range h {
+		logs = append(logs, item.data)
+	}
+	return logs, nil
+}
+
 func readAllFromBuffer(buf []byte) (logHeap, error) {
 	r := &reader{
 		br: bytes.NewReader(buf),
@@ -285,7 +302,7 @@ func sortAndWriteFile(
 	return w.Close()
 }
 
-func shouldOpen(startTs uint64, name, fixedType string) (bool, error) {
+func shouldOpen(startTs, compactedTs uint64, name, fixedType string) (bool, error) {
 	// .sort.tmp will return error
 	commitTs, fileType, err := redo.ParseLogFileName(name)
 	if err != nil {
@@ -298,6 +315,15 @@ func shouldOpen(startTs uint64, name, fixedType string) (bool, error) {
 	if filepath.Ext(name) == redo.TmpEXT {
 		return true, nil
 	}
+	// Compacted log files are valid only if they are recorded in the meta,
+	// and they supersede other log files before the compacted ts.
+	if redo.IsCompactedLogFile(name) {
+		if commitTs > compactedTs {
+			return false, nil
+		}
+	} else if commitTs <= compactedTs {
+		return false, nil
+	}
 	// the commitTs=max(ts of log item in the file), if max > startTs then should open,
 	// filter out ts in (startTs, endTs] for consume
 	return commitTs > startTs, nil
@@ -77,3 +77,27 @@ func TestFileReaderRead(t *testing.T) {
 		require.NoError(t, r.Close())
 	}
 }
+
+func TestShouldOpenCompactedFile(t *testing.T) {
+	t.Parallel()
+
+	fileName := func(captureID string, maxCommitTs uint64) string {
+		return fmt.Sprintf(redo.RedoLogFileFormatV1, captureID, "test",
+			redo.RedoRowLogFileType, maxCommitTs, "uuid", redo.LogEXT)
+	}
+	for _, c := range []struct {
+		name     string
+		expected bool
+	}{
+		{fileName("capture", 10), false},
+		{fileName("capture", 20), false},
+		{fileName("capture", 30), true},
+		{fileName(redo.RedoCompactedLogCaptureID, 10), false},
+		{fileName(redo.RedoCompactedLogCaptureID, 20), true},
+		{fileName(redo.RedoCompactedLogCaptureID, 30), false},
+	} {
+		ret, err := shouldOpen(10, 20, c.name, redo.RedoRowLogFileType)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, ret, c.name)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"path/filepath"
+	"sort"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/model/codec"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/redo"
+)
+
+// Status of a redo log file in the inspect summary.
+const (
+	// LogFileValid means events in the file are read by the redo reader.
+	LogFileValid = "valid"
+	// LogFileSuperseded means the file is superseded by compacted files.
+	LogFileSuperseded = "superseded"
+	// LogFileStale means the file is a compacted file not recorded in meta.
+	LogFileStale = "stale"
+	// LogFileCorrupted means the file can't be decoded completely.
+	LogFileCorrupted = "corrupted"
+)
+
+// InspectConfig is the config used to inspect redo logs.
+type InspectConfig struct {
+	URI url.URL
+
+	// OnEvent is called with events matching Table, StartTs and EndTs if it
+	// is not nil. Events are passed file by file, events of a file are sorted
+	// by commit ts, so only events of one file are kept in memory.
+	OnEvent func(*model.RedoLog) error
+	// Table is in the form of "schema.table", empty means all tables.
+	Table string
+	// Events with commit ts in (StartTs, EndTs] are passed to OnEvent, 0 EndTs
+	// means no upper bound.
+	StartTs uint64
+	EndTs   uint64
+}
+
+// InspectSummary is the summary of redo logs in a storage.
+type InspectSummary struct {
+	// Meta is nil if there is no meta file.
+	Meta *common.LogMeta
+	// MinCommitTs and MaxCommitTs are the commit ts range of all events.
+	MinCommitTs uint64
+	MaxCommitTs uint64
+
+	Files  []*LogFileSummary
+	Tables []*TableSummary
+	DDLs   []*model.DDLEvent
+
+	tables map[model.TableName]*TableSummary
+}
+
+// LogFileSummary is the summary of a redo log file.
+type LogFileSummary struct {
+	Name        string
+	Size        int64
+	FileType    string
+	MaxCommitTs uint64
+	EventCount  int
+	Status      string
+	// Error is the reason why the file is corrupted.
+	Error string
+}
+
+// TableSummary is the summary of row changes of a table.
+type TableSummary struct {
+	model.TableName
+	RowCount    int
+	MinCommitTs uint64
+	MaxCommitTs uint64
+}
+
+// Inspect reads all redo log files in the storage and summarizes them. Files
+// are decoded as streams rather than sorted by the reader, so corrupted files
+// are reported instead of failing the inspection.
+func Inspect(ctx context.Context, cfg *InspectConfig) (*InspectSummary, error) {
+	extStorage, err := redo.InitExternalStorage(ctx, cfg.URI)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := readLogMeta(ctx, extStorage)
+	if err != nil {
+		return nil, err
+	}
+	var compactedTs uint64
+	if meta != nil {
+		compactedTs = meta.CompactedTs
+	}
+
+	summary := &InspectSummary{
+		Meta:        meta,
+		MinCommitTs: math.MaxUint64,
+		tables:      make(map[model.TableName]*TableSummary),
+	}
+	// onEventErr is returned as is rather than as a storage error.
+	var onEventErr error
+	err = extStorage.WalkDir(ctx, &storage.WalkOption{}, func(path string, size int64) error {
+		name := filepath.Base(path)
+		if filepath.Ext(name) != redo.LogEXT && filepath.Ext(name) != redo.TmpEXT {
+			return nil
+		}
+		commitTs, fileType, err := redo.ParseLogFileName(name)
+		if err != nil {
+			summary.Files = append(summary.Files, &LogFileSummary{
+				Name: path, Size: size, Status: LogFileCorrupted, Error: err.Error(),
+			})
+			return nil
+		}
+		file := &LogFileSummary{
+			Name: path, Size: size, FileType: fileType,
+			MaxCommitTs: commitTs, Status: LogFileValid,
+		}
+		summary.Files = append(summary.Files, file)
+		if ok, _ := shouldOpen(0, compactedTs, name, fileType); !ok {
+			file.Status = LogFileSuperseded
+			if redo.IsCompactedLogFile(name) {
+				file.Status = LogFileStale
+			}
+			return nil
+		}
+
+		r, err := extStorage.Open(ctx, path)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		var events []*model.RedoLog
+		file.EventCount, err = inspectFile(r, size, func(l *model.RedoLog) {
+			if summary.addLog(l, cfg) {
+				events = append(events, l)
+			}
+		})
+		if err != nil {
+			file.Status = LogFileCorrupted
+			file.Error = err.Error()
+		}
+
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].GetCommitTs() < events[j].GetCommitTs()
+		})
+		for _, l := range events {
+			if onEventErr = cfg.OnEvent(l); onEventErr != nil {
+				return onEventErr
+			}
+		}
+		return nil
+	})
+	if onEventErr != nil {
+		return nil, onEventErr
+	}
+	if err != nil {
+		return nil, errors.WrapError(errors.ErrExternalStorageAPI, err)
+	}
+
+	if summary.MinCommitTs == math.MaxUint64 {
+		summary.MinCommitTs = 0
+	}
+	for _, table := range summary.tables {
+		summary.Tables = append(summary.Tables, table)
+	}
+	sort.Slice(summary.Tables, func(i, j int) bool {
+		return summary.Tables[i].String() < summary.Tables[j].String()
+	})
+	sort.SliceStable(summary.DDLs, func(i, j int) bool {
+		return summary.DDLs[i].CommitTs < summary.DDLs[j].CommitTs
+	})
+	return summary, nil
+}
+
+// addLog adds the log to the summary, it returns whether the log should be
+// passed to OnEvent.
+func (s *InspectSummary) addLog(l *model.RedoLog, cfg *InspectConfig) bool {
+	var tableName model.TableName
+	switch l.Type {
+	case model.RedoLogTypeRow:
+		row := l.RedoRow.Row
+		if row == nil || row.Table == nil {
+			return false
+		}
+		tableName = *row.Table
+		s.addRow(row)
+	case model.RedoLogTypeDDL:
+		ddl := l.RedoDDL.DDL
+		if ddl == nil {
+			return false
+		}
+		// DDLs written by old versions of cdc may have no table info, they
+		// are counted under an empty table name.
+		if ddl.TableInfo != nil {
+			tableName = ddl.TableInfo.TableName
+		}
+		s.DDLs = append(s.DDLs, ddl)
+	default:
+		return false
+	}
+	commitTs := l.GetCommitTs()
+	if commitTs < s.MinCommitTs {
+		s.MinCommitTs = commitTs
+	}
+	if commitTs > s.MaxCommitTs {
+		s.MaxCommitTs = commitTs
+	}
+
+	if cfg.OnEvent == nil || commitTs <= cfg.StartTs || (cfg.EndTs != 0 && commitTs > cfg.EndTs) {
+		return false
+	}
+	return cfg.Table == "" || cfg.Table == fmt.Sprintf("%s.%s", tableName.Schema, tableName.Table)
+}
+
+func (s *InspectSummary) addRow(row *model.RowChangedEvent) {
+	table, ok := s.tables[*row.Table]
+	if !ok {
+		table = &TableSummary{TableName: *row.Table, MinCommitTs: row.CommitTs}
+		s.tables[*row.Table] = table
+	}
+	table.RowCount++
+	if row.CommitTs < table.MinCommitTs {
+		table.MinCommitTs = row.CommitTs
+	}
+	if row.CommitTs > table.MaxCommitTs {
+		table.MaxCommitTs = row.CommitTs
+	}
+}
+
+// inspectFile decodes logs of the file one by one and passes them to onLog,
+// it returns the count of logs decoded before the corrupted part if any.
+// Frame sizes are checked against the file size, since a corrupted size may
+// be arbitrarily large.
+func inspectFile(r io.Reader, size int64, onLog func(*model.RedoLog)) (int, error) {
+	br := bufio.NewReader(r)
+	lenBuf := make([]byte, frameSizeBytes)
+	var off int64
+	count := 0
+	for off+frameSizeBytes <= size {
+		if _, err := io.ReadFull(br, lenBuf); err != nil {
+			return count, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		lenField := int64(binary.LittleEndian.Uint64(lenBuf))
+		recBytes, padBytes := decodeFrameSize(lenField)
+		end := off + frameSizeBytes + recBytes + padBytes
+		if recBytes == 0 || end > size {
+			return count, checkTail(io.MultiReader(bytes.NewReader(lenBuf), br), off)
+		}
+		data := make([]byte, recBytes+padBytes)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return count, errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+		rl, _, err := codec.UnmarshalRedoLog(data[:recBytes])
+		if err != nil {
+			return count, errors.WrapError(errors.ErrUnmarshalFailed,
+				errors.Annotatef(err, "decode redo log at offset %d", off))
+		}
+		onLog(rl)
+		count++
+		off = end
+	}
+	return count, checkTail(br, off)
+}
+
+// checkTail checks the undecodable bytes after offset off. Trailing zeros are
+// left by preallocation and are not a corruption.
+func checkTail(r io.Reader, off int64) error {
+	buf := make([]byte, 4096)
+	var tailSize int64
+	corrupted := false
+	for {
+		n, err := r.Read(buf)
+		tailSize += int64(n)
+		if !corrupted && len(bytes.Trim(buf[:n], "\x00")) != 0 {
+			corrupted = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.WrapError(errors.ErrRedoFileOp, err)
+		}
+	}
+	if corrupted {
+		return errors.ErrRedoFileOp.GenWithStack(
+			"%d bytes after offset %d can't be decoded", tailSize, off)
+	}
+	return nil
+}
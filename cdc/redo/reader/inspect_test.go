@@ -0,0 +1,163 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/model/codec"
+	codecv1 "github.com/pingcap/tiflow/cdc/model/codec/v1"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/cdc/redo/writer"
+	"github.com/pingcap/tiflow/cdc/redo/writer/file"
+	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeRawLogFile := func(logType string, maxCommitTs uint64, records ...[]byte) string {
+		fileName := fmt.Sprintf(redo.RedoLogFileFormatV2, "capture", "default",
+			"changefeed", logType, maxCommitTs, uuid.NewString(), redo.LogEXT)
+		w, err := file.NewFileWriter(ctx, &writer.LogWriterConfig{
+			MaxLogSizeInBytes: 100000,
+			Dir:               dir,
+		}, writer.WithLogFileName(func() string {
+			return fileName
+		}))
+		require.NoError(t, err)
+		for _, data := range records {
+			_, err = w.Write(data)
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		return filepath.Join(dir, fileName)
+	}
+	writeLogFile := func(logType string, maxCommitTs uint64, logs ...*model.RedoLog) string {
+		records := make([][]byte, 0, len(logs))
+		for _, l := range logs {
+			data, err := codec.MarshalRedoLog(l, nil)
+			require.NoError(t, err)
+			records = append(records, data)
+		}
+		return writeRawLogFile(logType, maxCommitTs, records...)
+	}
+	newRow := func(table string, commitTs uint64) *model.RedoLog {
+		row := &model.RowChangedEvent{
+			CommitTs: commitTs,
+			Table:    &model.TableName{Schema: "test", Table: table},
+			Columns:  []*model.Column{{Name: "a", Value: int64(commitTs)}},
+		}
+		return row.ToRedoLog()
+	}
+
+	meta := &common.LogMeta{CheckpointTs: 10, ResolvedTs: 100}
+	data, err := meta.MarshalMsg(nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "capture_default_changefeed"+redo.MetaEXT), data, 0o644))
+
+	writeLogFile(redo.RedoRowLogFileType, 30, newRow("t1", 30), newRow("t2", 20))
+	writeLogFile(redo.RedoRowLogFileType, 50, newRow("t1", 40), newRow("t1", 50))
+	ddl := &model.DDLEvent{
+		CommitTs:  25,
+		Query:     "create table t2(a int)",
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t2"}},
+	}
+	// DDLs written by old versions of cdc have no table info.
+	noTableInfoDDL := &codecv1.RedoLog{
+		RedoDDL: &codecv1.RedoDDLEvent{DDL: &codecv1.DDLEvent{
+			CommitTs: 26, Query: "create database test",
+		}},
+		Type: codecv1.RedoLogType(model.RedoLogTypeDDL),
+	}
+	codecv1.PreMarshal(noTableInfoDDL)
+	noTableInfoData, err := noTableInfoDDL.MarshalMsg(nil)
+	require.NoError(t, err)
+	ddlData, err := codec.MarshalRedoLog(ddl.ToRedoLog(), nil)
+	require.NoError(t, err)
+	writeRawLogFile(redo.RedoDDLLogFileType, 26, ddlData, noTableInfoData)
+	corrupted := writeLogFile(redo.RedoRowLogFileType, 60, newRow("t2", 60))
+	f, err := os.OpenFile(corrupted, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("corrupted data"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	uri, err := url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+	var events []*model.RedoLog
+	summary, err := Inspect(ctx, &InspectConfig{
+		URI: *uri,
+		OnEvent: func(l *model.RedoLog) error {
+			events = append(events, l)
+			return nil
+		},
+		Table:   "test.t1",
+		StartTs: 30,
+		EndTs:   100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, meta.CheckpointTs, summary.Meta.CheckpointTs)
+	require.Equal(t, meta.ResolvedTs, summary.Meta.ResolvedTs)
+	require.Equal(t, uint64(20), summary.MinCommitTs)
+	require.Equal(t, uint64(60), summary.MaxCommitTs)
+
+	require.Len(t, summary.Files, 4)
+	var corruptedFiles []*LogFileSummary
+	for _, f := range summary.Files {
+		if f.Status == LogFileCorrupted {
+			corruptedFiles = append(corruptedFiles, f)
+		}
+	}
+	require.Len(t, corruptedFiles, 1)
+	require.Equal(t, uint64(60), corruptedFiles[0].MaxCommitTs)
+	require.Equal(t, 1, corruptedFiles[0].EventCount)
+
+	require.Len(t, summary.Tables, 2)
+	require.Equal(t, "t1", summary.Tables[0].Table)
+	require.Equal(t, 3, summary.Tables[0].RowCount)
+	require.Equal(t, uint64(30), summary.Tables[0].MinCommitTs)
+	require.Equal(t, uint64(50), summary.Tables[0].MaxCommitTs)
+	require.Equal(t, "t2", summary.Tables[1].Table)
+	require.Equal(t, 2, summary.Tables[1].RowCount)
+
+	require.Len(t, summary.DDLs, 2)
+	require.Equal(t, ddl.Query, summary.DDLs[0].Query)
+	require.Nil(t, summary.DDLs[1].TableInfo)
+	require.Equal(t, "create database test", summary.DDLs[1].Query)
+
+	require.Len(t, events, 2)
+	require.Equal(t, uint64(40), events[0].GetCommitTs())
+	require.Equal(t, uint64(50), events[1].GetCommitTs())
+
+	// Errors of OnEvent stop the inspection.
+	_, err = Inspect(ctx, &InspectConfig{
+		URI: *uri,
+		OnEvent: func(l *model.RedoLog) error {
+			return errors.New("on event error")
+		},
+	})
+	require.ErrorContains(t, err, "on event error")
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/common"
 	"github.com/pingcap/tiflow/pkg/errors"
@@ -51,6 +52,9 @@ type RedoLogReader interface {
 	ReadNextDDL(ctx context.Context) (*model.DDLEvent, error)
 	// ReadMeta reads meta from redo logs and returns the latest checkpointTs and resolvedTs
 	ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint64, err error)
+	// ReadCompactionMeta reads meta from redo logs and returns the retainedTs
+	// and compactedTs.
+	ReadCompactionMeta(ctx context.Context) (retainedTs, compactedTs uint64, err error)
 }
 
 // NewRedoLogReader creates a new redo log reader
@@ -81,6 +85,11 @@ type LogReaderConfig struct {
 	// will load the file to memory first then write the sorted file to disk
 	// the memory used is WorkerNums * defaultMaxLogSize (64 * megabyte) total
 	WorkerNums int
+
+	// StartTs is the ts redo logs are read from, only events whose commitTs
+	// is greater than it are read. It can be less than the checkpointTs to
+	// read retained logs, 0 means the checkpointTs recorded in redo meta.
+	StartTs uint64
 }
 
 // LogReader implement RedoLogReader interface
@@ -116,9 +125,33 @@ func newLogReader(ctx context.Context, cfg *LogReaderConfig) (*LogReader, error)
 	if err := logReader.initMeta(ctx); err != nil {
 		return nil, err
 	}
+	if cfg.StartTs != 0 {
+		minStartTs := getMinStartTs(logReader.meta)
+		if cfg.StartTs < minStartTs || cfg.StartTs > logReader.meta.ResolvedTs {
+			return nil, errors.ErrRedoApplyStartTsInvalid.GenWithStackByArgs(
+				cfg.StartTs, minStartTs, logReader.meta.ResolvedTs)
+		}
+	}
 	return logReader, nil
 }
 
+// getMinStartTs returns the min ts redo logs can be read from. Logs before
+// the checkpointTs are only kept since the retainedTs, and a zero retainedTs
+// means the meta is written by a version without retention.
+func getMinStartTs(meta *common.LogMeta) uint64 {
+	if meta.RetainedTs != 0 && meta.RetainedTs < meta.CheckpointTs {
+		return meta.RetainedTs
+	}
+	return meta.CheckpointTs
+}
+
+func (l *LogReader) getStartTs() uint64 {
+	if l.cfg.StartTs != 0 {
+		return l.cfg.StartTs
+	}
+	return l.meta.CheckpointTs
+}
+
 // Run implements the `RedoLogReader` interface.
 func (l *LogReader) Run(ctx context.Context) error {
 	select {
@@ -144,8 +177,9 @@ func (l *LogReader) Run(ctx context.Context) error {
 func (l *LogReader) runRowReader(egCtx context.Context) error {
 	defer close(l.rowCh)
 	rowCfg := &readerConfig{
-		startTs:            l.meta.CheckpointTs,
+		startTs:            l.getStartTs(),
 		endTs:              l.meta.ResolvedTs,
+		compactedTs:        l.meta.CompactedTs,
 		dir:                l.cfg.Dir,
 		fileType:           redo.RedoRowLogFileType,
 		uri:                l.cfg.URI,
@@ -158,7 +192,7 @@ func (l *LogReader) runRowReader(egCtx context.Context) error {
 func (l *LogReader) runDDLReader(egCtx context.Context) error {
 	defer close(l.ddlCh)
 	ddlCfg := &readerConfig{
-		startTs:            l.meta.CheckpointTs - 1,
+		startTs:            l.getStartTs() - 1,
 		endTs:              l.meta.ResolvedTs,
 		dir:                l.cfg.Dir,
 		fileType:           redo.RedoDDLLogFileType,
@@ -284,8 +318,24 @@ func (l *LogReader) initMeta(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	meta, err := readLogMeta(ctx, extStorage)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return errors.ErrRedoMetaFileNotFound.GenWithStackByArgs(l.cfg.Dir)
+	}
+	l.meta = meta
+	return nil
+}
+
+// readLogMeta reads and merges all meta files in the storage, nil is returned
+// if there is no meta file.
+func readLogMeta(
+	ctx context.Context, extStorage storage.ExternalStorage,
+) (*common.LogMeta, error) {
 	metas := make([]*common.LogMeta, 0, 64)
-	err = extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
+	err := extStorage.WalkDir(ctx, nil, func(path string, size int64) error {
 		if !strings.HasSuffix(path, redo.MetaEXT) {
 			return nil
 		}
@@ -305,22 +355,27 @@ func (l *LogReader) initMeta(ctx context.Context) error {
 		return nil
 	})
 	if err != nil {
-		return errors.WrapError(errors.ErrRedoMetaInitialize,
+		return nil, errors.WrapError(errors.ErrRedoMetaInitialize,
 			errors.Annotate(err, "read meta file fail"))
 	}
 	if len(metas) == 0 {
-		return errors.ErrRedoMetaFileNotFound.GenWithStackByArgs(l.cfg.Dir)
+		return nil, nil
 	}
 
-	var checkpointTs, resolvedTs uint64
+	var checkpointTs, resolvedTs, retainedTs, compactedTs uint64
 	common.ParseMeta(metas, &checkpointTs, &resolvedTs)
 	if resolvedTs < checkpointTs {
 		log.Panic("in all meta files, resolvedTs is less than checkpointTs",
 			zap.Uint64("resolvedTs", resolvedTs),
 			zap.Uint64("checkpointTs", checkpointTs))
 	}
-	l.meta = &common.LogMeta{CheckpointTs: checkpointTs, ResolvedTs: resolvedTs}
-	return nil
+	common.ParseCompactionMeta(metas, &retainedTs, &compactedTs)
+	return &common.LogMeta{
+		CheckpointTs: checkpointTs,
+		ResolvedTs:   resolvedTs,
+		RetainedTs:   retainedTs,
+		CompactedTs:  compactedTs,
+	}, nil
 }
 
 // ReadMeta implement ReadMeta interface
@@ -331,6 +386,14 @@ func (l *LogReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint
 	return l.meta.CheckpointTs, l.meta.ResolvedTs, nil
 }
 
+// ReadCompactionMeta implements the `RedoLogReader` interface.
+func (l *LogReader) ReadCompactionMeta(ctx context.Context) (retainedTs, compactedTs uint64, err error) {
+	if l.meta == nil {
+		return 0, 0, errors.Trace(errors.ErrRedoMetaFileNotFound.GenWithStackByArgs(l.cfg.Dir))
+	}
+	return l.meta.RetainedTs, l.meta.CompactedTs, nil
+}
+
 type logWithIdx struct {
 	idx  int
 	data *model.RedoLog
@@ -124,6 +124,107 @@ func TestReadLogs(t *testing.T) {
 	require.ErrorIs(t, eg.Wait(), nil)
 }
 
+func TestReadRetainedLogs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	meta := &common.LogMeta{
+		CheckpointTs: 50,
+		ResolvedTs:   100,
+		RetainedTs:   10,
+	}
+	for _, ts := range []uint64{10, 11, 12, 50, 100} {
+		genLogFile(ctx, t, dir, redo.RedoRowLogFileType, ts, ts)
+		genLogFile(ctx, t, dir, redo.RedoDDLLogFileType, ts, ts)
+	}
+	// DDLs at the startTs are read, since they may not be executed.
+	expectedRows := []uint64{12, 50, 100}
+	expectedDDLs := []uint64{11, 12, 50, 100}
+
+	uri, err := url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+	r := &LogReader{
+		cfg: &LogReaderConfig{
+			Dir:                t.TempDir(),
+			URI:                *uri,
+			UseExternalStorage: true,
+			StartTs:            11,
+		},
+		meta:  meta,
+		rowCh: make(chan *model.RowChangedEvent, defaultReaderChanSize),
+		ddlCh: make(chan *model.DDLEvent, defaultReaderChanSize),
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return r.Run(egCtx)
+	})
+
+	for _, ts := range expectedRows {
+		row, err := r.ReadNextRow(egCtx)
+		require.NoError(t, err)
+		require.Equal(t, ts, row.CommitTs)
+	}
+	for _, ts := range expectedDDLs {
+		ddl, err := r.ReadNextDDL(egCtx)
+		require.NoError(t, err)
+		require.Equal(t, ts, ddl.CommitTs)
+	}
+
+	cancel()
+	require.ErrorIs(t, eg.Wait(), nil)
+}
+
+func TestNewLogReaderWithStartTs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	genMetaFile(t, dir, &common.LogMeta{
+		CheckpointTs: 11,
+		ResolvedTs:   22,
+		RetainedTs:   5,
+		CompactedTs:  8,
+	})
+	uri, err := url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, startTs := range []uint64{5, 8, 11, 22} {
+		l, err := newLogReader(ctx, &LogReaderConfig{
+			Dir:     t.TempDir(),
+			URI:     *uri,
+			StartTs: startTs,
+		})
+		require.NoError(t, err)
+		require.Equal(t, startTs, l.getStartTs())
+		retainedTs, compactedTs, err := l.ReadCompactionMeta(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), retainedTs)
+		require.Equal(t, uint64(8), compactedTs)
+	}
+	for _, startTs := range []uint64{4, 23} {
+		_, err := newLogReader(ctx, &LogReaderConfig{
+			Dir:     t.TempDir(),
+			URI:     *uri,
+			StartTs: startTs,
+		})
+		require.Regexp(t, "ErrRedoApplyStartTsInvalid", err)
+	}
+
+	// Logs before the checkpointTs are not retained by old versions.
+	dir = t.TempDir()
+	genMetaFile(t, dir, &common.LogMeta{CheckpointTs: 11, ResolvedTs: 22})
+	uri, err = url.Parse(fmt.Sprintf("file://%s", dir))
+	require.NoError(t, err)
+	_, err = newLogReader(ctx, &LogReaderConfig{
+		Dir:     t.TempDir(),
+		URI:     *uri,
+		StartTs: 10,
+	})
+	require.Regexp(t, "ErrRedoApplyStartTsInvalid", err)
+}
+
 func TestLogReaderClose(t *testing.T) {
 	t.Parallel()
 
@@ -15,6 +15,7 @@ package internal
 
 import (
 	"context"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
@@ -57,9 +58,31 @@ type Scheduler interface {
 	// It is thread-safe
 	Rebalance()
 
-	// DrainCapture is used to drop all tables situated at the target capture
+	// DrainCapture is used to drop all tables situated at the target capture,
+	// remaining tables are force-moved after the deadline if it is not zero.
 	// It is thread-safe.
-	DrainCapture(target model.CaptureID) (int, error)
+	DrainCapture(target model.CaptureID, deadline time.Time) (int, error)
+
+	// DrainProgress returns the progress of tables situated at the target
+	// capture.
+	// It is thread-safe.
+	DrainProgress(target model.CaptureID) []model.DrainTableProgress
+
+	// IsCaptureDraining returns true if the target capture is being drained
+	// or has been drained. It's derived from the liveness reported by the
+	// capture, so that it's kept after the owner is changed.
+	// It is thread-safe.
+	IsCaptureDraining(target model.CaptureID) bool
+
+	// CancelDrainCapture cancels draining the target capture, it returns
+	// true if the capture is draining.
+	// It is thread-safe.
+	CancelDrainCapture(target model.CaptureID) bool
+
+	// IsTableRemoved returns true if no capture replicates the table, e.g.
+	// after it's left out of the current tables for backfilling.
+	// It is thread-safe.
+	IsTableRemoved(tableID model.TableID) bool
 
 	// Close scheduler and release resource.
 	// It is not thread-safe.
@@ -71,6 +94,23 @@ type Scheduler interface {
 // TODO: refactor `MoveTable` use Query to access the scheduler
 type Query struct {
 	CaptureID model.CaptureID
+	// Tp is the type of the drain capture query.
+	Tp DrainQueryType
+	// Deadline is the time after which remaining tables are force-moved
+	// when draining the capture, zero means no deadline.
+	Deadline time.Time
 
 	Resp interface{}
 }
+
+// DrainQueryType is the type of the drain capture query.
+type DrainQueryType int
+
+const (
+	// DrainQueryStart starts draining the capture.
+	DrainQueryStart DrainQueryType = iota
+	// DrainQueryProgress queries the progress of draining the capture.
+	DrainQueryProgress
+	// DrainQueryCancel cancels draining the capture.
+	DrainQueryCancel
+)
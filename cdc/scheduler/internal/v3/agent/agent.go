@@ -20,6 +20,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
@@ -50,7 +51,13 @@ type agent struct {
 	// It changes to LivenessCaptureStopping in following cases:
 	// 1. The capture receives a SIGTERM signal.
 	// 2. The agent receives a stopping heartbeat.
+	// It changes back to LivenessCaptureAlive if the agent receives a
+	// heartbeat which cancels stopping.
 	liveness *model.Liveness
+	// drainDeadline is the drain deadline in the last stopping heartbeat,
+	// it's reported while the capture is stopping, so that a new owner
+	// keeps force-moving tables after the deadline.
+	drainDeadline int64
 }
 
 type agentInfo struct {
@@ -274,7 +281,7 @@ func (a *agent) handleMessageHeartbeat(request *schedulepb.Heartbeat) (
 ) {
 	allTables := a.tableM.getAllTableSpans()
 	result := make([]tablepb.TableStatus, 0, allTables.Len())
-	allTables.Ascend(func(span tablepb.Span, table *tableSpan) bool {
+	allTables.Range(func(span tablepb.Span, table *tableSpan) bool {
 		status := table.getTableSpanStatus(request.CollectStats)
 		if table.task != nil && table.task.IsRemove {
 			status.State = tablepb.TableStateStopping
@@ -291,11 +298,38 @@ func (a *agent) handleMessageHeartbeat(request *schedulepb.Heartbeat) (
 
 	if request.IsStopping {
 		a.handleLivenessUpdate(model.LivenessCaptureStopping)
+		if request.DrainDeadline != 0 {
+			a.drainDeadline = request.DrainDeadline
+		}
+	} else if request.CancelStopping {
+		a.drainDeadline = 0
+		if a.liveness.Resume() {
+			log.Info("schedulerv3: agent resumes liveness, since draining is cancelled",
+				zap.String("namespace", a.ChangeFeedID.Namespace),
+				zap.String("changefeed", a.ChangeFeedID.ID))
+		}
 	}
 	response := &schedulepb.HeartbeatResponse{
 		Tables:   result,
 		Liveness: a.liveness.Load(),
 	}
+	if response.Liveness == model.LivenessCaptureStopping {
+		response.DrainDeadline = a.drainDeadline
+	}
+	if request.CollectStats {
+		// The memory usage is used by the owner to balance tables, it is
+		// fine to omit it if it is unavailable. It's the heap usage of the
+		// process instead of the host, which may run other processes.
+		if used, err := memory.InstanceMemUsed(); err == nil {
+			response.MemoryUsage = used
+		} else {
+			log.Warn("schedulerv3: agent fails to get memory usage",
+				zap.String("capture", a.CaptureID),
+				zap.String("namespace", a.ChangeFeedID.Namespace),
+				zap.String("changefeed", a.ChangeFeedID.ID),
+				zap.Error(err))
+		}
+	}
 
 	message := &schedulepb.Message{
 		MsgType:           schedulepb.MsgHeartbeatResponse,
@@ -390,9 +390,28 @@ func TestAgentHandleMessageHeartbeat(t *testing.T) {
 
 	a.handleLivenessUpdate(model.LivenessCaptureAlive)
 	heartbeat.Heartbeat.IsStopping = true
+	heartbeat.Heartbeat.DrainDeadline = 1000
 	response, _ = a.handleMessage([]*schedulepb.Message{heartbeat})
 	require.Equal(t, model.LivenessCaptureStopping, response[0].GetHeartbeatResponse().Liveness)
 	require.Equal(t, model.LivenessCaptureStopping, a.liveness.Load())
+	require.EqualValues(t, 1000, response[0].GetHeartbeatResponse().DrainDeadline)
+
+	// The owner is changed, the capture keeps stopping and reports the
+	// drain deadline to the new owner.
+	heartbeat.Header.OwnerRevision = schedulepb.OwnerRevision{Revision: 2}
+	heartbeat.From = "owner-2"
+	heartbeat.Heartbeat.IsStopping = false
+	heartbeat.Heartbeat.DrainDeadline = 0
+	response, _ = a.handleMessage([]*schedulepb.Message{heartbeat})
+	require.Equal(t, model.LivenessCaptureStopping, response[0].GetHeartbeatResponse().Liveness)
+	require.EqualValues(t, 1000, response[0].GetHeartbeatResponse().DrainDeadline)
+
+	// Draining is cancelled.
+	heartbeat.Heartbeat.CancelStopping = true
+	response, _ = a.handleMessage([]*schedulepb.Message{heartbeat})
+	require.Equal(t, model.LivenessCaptureAlive, response[0].GetHeartbeatResponse().Liveness)
+	require.Equal(t, model.LivenessCaptureAlive, a.liveness.Load())
+	require.Zero(t, response[0].GetHeartbeatResponse().DrainDeadline)
 }
 
 func TestAgentPermuteMessages(t *testing.T) {
@@ -276,7 +276,9 @@ func (t *tableSpan) poll(ctx context.Context) (*schedulepb.Message, error) {
 }
 
 type tableSpanManager struct {
-	tables   *spanz.BtreeMap[*tableSpan]
+	// tables is indexed by exact spans, spans being replaced and their new
+	// spans may overlap on the same capture.
+	tables   *spanz.HashMap[*tableSpan]
 	executor internal.TableExecutor
 
 	changefeedID model.ChangeFeedID
@@ -286,7 +288,7 @@ func newTableSpanManager(
 	changefeed model.ChangeFeedID, executor internal.TableExecutor,
 ) *tableSpanManager {
 	return &tableSpanManager{
-		tables:       spanz.NewBtreeMap[*tableSpan](),
+		tables:       spanz.NewHashMap[*tableSpan](),
 		executor:     executor,
 		changefeedID: changefeed,
 	}
@@ -296,7 +298,7 @@ func (tm *tableSpanManager) poll(ctx context.Context) ([]*schedulepb.Message, er
 	result := make([]*schedulepb.Message, 0)
 	var err error
 	toBeDropped := []tablepb.Span{}
-	tm.tables.Ascend(func(span tablepb.Span, table *tableSpan) bool {
+	tm.tables.Range(func(span tablepb.Span, table *tableSpan) bool {
 		message, err1 := table.poll(ctx)
 		if err != nil {
 			err = errors.Trace(err1)
@@ -320,7 +322,7 @@ func (tm *tableSpanManager) poll(ctx context.Context) ([]*schedulepb.Message, er
 	return result, err
 }
 
-func (tm *tableSpanManager) getAllTableSpans() *spanz.BtreeMap[*tableSpan] {
+func (tm *tableSpanManager) getAllTableSpans() *spanz.HashMap[*tableSpan] {
 	return tm.tables
 }
 
@@ -173,7 +173,9 @@ func (c *coordinator) Rebalance() {
 
 // DrainCapture implement the scheduler interface
 // return the count of table replicating on the target capture, and true if the request processed.
-func (c *coordinator) DrainCapture(target model.CaptureID) (int, error) {
+func (c *coordinator) DrainCapture(
+	target model.CaptureID, deadline time.Time,
+) (int, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -197,15 +199,6 @@ func (c *coordinator) DrainCapture(target model.CaptureID) (int, error) {
 			return true
 		})
 
-	if count == 0 {
-		log.Info("schedulerv3: drain capture request ignored, "+
-			"the target capture has no replicating table",
-			zap.String("namespace", c.changefeedID.Namespace),
-			zap.String("changefeed", c.changefeedID.ID),
-			zap.String("target", target))
-		return count, nil
-	}
-
 	// when draining the capture, tables need to be dispatched to other
 	// capture except the draining one, so at least should have 2 captures alive.
 	if len(c.captureM.Captures) <= 1 {
@@ -229,7 +222,9 @@ func (c *coordinator) DrainCapture(target model.CaptureID) (int, error) {
 		return count, nil
 	}
 
-	if !c.schedulerM.DrainCapture(target) {
+	// The target is drained even if it has no replicating table, so that
+	// it becomes stopping and no table is scheduled to it.
+	if !c.schedulerM.DrainCapture(target, deadline) {
 		log.Info("schedulerv3: drain capture request ignored, "+
 			"since there is capture draining",
 			zap.String("namespace", c.changefeedID.Namespace),
@@ -241,6 +236,85 @@ func (c *coordinator) DrainCapture(target model.CaptureID) (int, error) {
 	return count, nil
 }
 
+// DrainProgress implement the scheduler interface
+func (c *coordinator) DrainProgress(target model.CaptureID) []model.DrainTableProgress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	progress := make([]model.DrainTableProgress, 0)
+	c.replicationM.ReplicationSets().Ascend(
+		func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+			if rep.Primary != target {
+				return true
+			}
+			p := model.DrainTableProgress{
+				Namespace:    c.changefeedID.Namespace,
+				ChangefeedID: c.changefeedID.ID,
+				TableID:      span.TableID,
+				Span:         span.String(),
+				State:        rep.State.String(),
+			}
+			for captureID, role := range rep.Captures {
+				if role == replication.RoleSecondary {
+					p.DestCapture = captureID
+				}
+			}
+			progress = append(progress, p)
+			return true
+		})
+	return progress
+}
+
+// IsCaptureDraining implement the scheduler interface
+func (c *coordinator) IsCaptureDraining(target model.CaptureID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.schedulerM.DrainingTarget() == target {
+		return true
+	}
+	capture, ok := c.captureM.Captures[target]
+	return ok && capture.State == member.CaptureStateStopping
+}
+
+// CancelDrainCapture implement the scheduler interface
+func (c *coordinator) CancelDrainCapture(target model.CaptureID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cancelled := c.schedulerM.CancelDrainCapture(target)
+	// The capture may be stopping because of draining in other changefeeds.
+	if c.captureM.CancelStopping(target) {
+		cancelled = true
+	}
+	if cancelled {
+		log.Info("schedulerv3: drain capture cancelled",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.String("target", target))
+	}
+	return cancelled
+}
+
+// IsTableRemoved implement the scheduler interface
+func (c *coordinator) IsTableRemoved(tableID model.TableID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Tables replicated by captures are unknown before they are initialized.
+	if !c.captureM.CheckAllCaptureInitialized() {
+		return false
+	}
+	removed := true
+	start, end := spanz.TableIDToComparableRange(tableID)
+	c.replicationM.ReplicationSets().AscendRange(start, end,
+		func(_ tablepb.Span, _ *replication.ReplicationSet) bool {
+			removed = false
+			return false
+		})
+	return removed
+}
+
 func (c *coordinator) Close(ctx context.Context) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -277,7 +351,7 @@ func (c *coordinator) poll(
 	var msgBuf []*schedulepb.Message
 	c.captureM.HandleMessage(recvMsgs)
 	msgs := c.captureM.Tick(c.replicationM.ReplicationSets(),
-		c.schedulerM.DrainingTarget(), barrier)
+		c.schedulerM.DrainingTarget(), c.schedulerM.DrainDeadline(), barrier)
 	msgBuf = append(msgBuf, msgs...)
 	msgs = c.captureM.HandleAliveCaptureUpdate(aliveCaptures)
 	msgBuf = append(msgBuf, msgs...)
@@ -316,6 +390,12 @@ func (c *coordinator) poll(
 		msgBuf = append(msgBuf, msgs...)
 	}
 
+	// Report the error to the changefeed instead of leaving tables
+	// unscheduled silently.
+	if err := c.schedulerM.CheckPlacement(c.captureM.Captures); err != nil {
+		return checkpointCannotProceed, checkpointCannotProceed, errors.Trace(err)
+	}
+
 	// Generate schedule tasks based on the current status.
 	replications := c.replicationM.ReplicationSets()
 	runningTasks := c.replicationM.RunningTasks()
@@ -323,6 +403,8 @@ func (c *coordinator) poll(
 		ctx, &c.tableRanges, replications, c.captureM.Captures, c.compat)
 	allTasks := c.schedulerM.Schedule(
 		checkpointTs, currentSpans, c.captureM.Captures, replications, runningTasks)
+	allTasks = append(allTasks,
+		c.reconciler.RebalanceTasks(replications, c.captureM.Captures)...)
 
 	// Handle generated schedule tasks.
 	msgs, err = c.replicationM.HandleTasks(allTasks)
@@ -17,6 +17,7 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -323,6 +324,33 @@ func TestCoordinatorAddCapture(t *testing.T) {
 	require.True(t, msgs[0].DispatchTableRequest.GetAddTable().IsSecondary)
 }
 
+func TestCoordinatorNoCaptureMatchesLabels(t *testing.T) {
+	t.Parallel()
+
+	settings := config.GetDefaultReplicaConfig().Scheduler
+	settings.CaptureLabels = map[string]string{"zone": "a"}
+	coord, _ := newTestCoordinator(&config.SchedulerConfig{
+		HeartbeatTick:      math.MaxInt,
+		CollectStatsTick:   math.MaxInt,
+		MaxTaskConcurrency: 1,
+		ChangefeedSettings: settings,
+	})
+	coord.captureM.Captures["a"] = &member.CaptureStatus{
+		State: member.CaptureStateInitialized, Labels: map[string]string{"zone": "b"},
+	}
+	coord.captureM.SetInitializedForTests(true)
+
+	// Tables can not be scheduled, the error is reported to the changefeed.
+	ctx := context.Background()
+	aliveCaptures := map[model.CaptureID]*model.CaptureInfo{"a": {}}
+	_, _, err := coord.poll(ctx, 0, []model.TableID{1}, aliveCaptures, nil)
+	require.True(t, cerror.ErrNoCaptureMatchesLabels.Equal(err), err)
+
+	coord.captureM.Captures["a"].Labels = map[string]string{"zone": "a"}
+	_, _, err = coord.poll(ctx, 0, []model.TableID{1}, aliveCaptures, nil)
+	require.Nil(t, err)
+}
+
 func TestCoordinatorRemoveCapture(t *testing.T) {
 	t.Parallel()
 
@@ -377,13 +405,13 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 
 	coord.captureM.SetInitializedForTests(true)
 	coord.captureM.Captures["a"] = &member.CaptureStatus{State: member.CaptureStateUninitialized}
-	count, err := coord.DrainCapture("a")
+	count, err := coord.DrainCapture("a", time.Time{})
 	require.ErrorIs(t, err, cerror.ErrSchedulerRequestFailed)
 	require.Equal(t, 0, count)
 
 	coord.captureM.Captures["a"] = &member.CaptureStatus{State: member.CaptureStateInitialized}
 	coord.replicationM = replication.NewReplicationManager(10, model.ChangeFeedID{})
-	count, err = coord.DrainCapture("a")
+	count, err = coord.DrainCapture("a", time.Time{})
 	require.NoError(t, err)
 	require.Equal(t, 0, count)
 
@@ -393,7 +421,7 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 		Primary: "a",
 	})
 
-	count, err = coord.DrainCapture("a")
+	count, err = coord.DrainCapture("a", time.Time{})
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 
@@ -404,15 +432,116 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 		Primary: "b",
 	})
 
-	count, err = coord.DrainCapture("a")
+	count, err = coord.DrainCapture("a", time.Time{})
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 
 	coord.schedulerM = scheduler.NewSchedulerManager(
 		model.ChangeFeedID{}, config.NewDefaultSchedulerConfig())
-	count, err = coord.DrainCapture("b")
+	count, err = coord.DrainCapture("b", time.Time{})
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
+
+	progress := coord.DrainProgress("b")
+	require.Len(t, progress, 1)
+	require.EqualValues(t, 2, progress[0].TableID)
+	require.Equal(t, "Replicating", progress[0].State)
+	require.Empty(t, coord.DrainProgress("c"))
+
+	coord.captureM.Captures["b"].State = member.CaptureStateStopping
+	require.True(t, coord.CancelDrainCapture("b"))
+	require.Equal(t, member.CaptureStateInitialized, coord.captureM.Captures["b"].State)
+	require.Equal(t, "", coord.schedulerM.DrainingTarget())
+	require.False(t, coord.CancelDrainCapture("b"))
+}
+
+func TestCoordinatorDrainCaptureOwnerChanged(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.SchedulerConfig{
+		HeartbeatTick:      1,
+		CollectStatsTick:   math.MaxInt,
+		MaxTaskConcurrency: 1,
+		AddTableBatchSize:  50,
+		ChangefeedSettings: config.GetDefaultReplicaConfig().Scheduler,
+	}
+	ctx := context.Background()
+	currentTables := []model.TableID{1, 2}
+	aliveCaptures := map[model.CaptureID]*model.CaptureInfo{"a": {}, "b": {}}
+	heartbeatResponse := func(
+		from model.CaptureID, resp *schedulepb.HeartbeatResponse,
+	) *schedulepb.Message {
+		return &schedulepb.Message{
+			Header: &schedulepb.Message_Header{
+				OwnerRevision: schedulepb.OwnerRevision{Revision: 1},
+			},
+			To:                "a",
+			From:              from,
+			MsgType:           schedulepb.MsgHeartbeatResponse,
+			HeartbeatResponse: resp,
+		}
+	}
+	tableStatus := func(tableID model.TableID) []tablepb.TableStatus {
+		return []tablepb.TableStatus{{
+			Span:  spanz.TableIDToComparableSpan(tableID),
+			State: tablepb.TableStateReplicating,
+		}}
+	}
+	initCoordinator := func(b *schedulepb.HeartbeatResponse) (*coordinator, *transport.MockTrans) {
+		coord, trans := newTestCoordinator(cfg)
+		_, _, err := coord.poll(ctx, 0, currentTables, aliveCaptures, nil)
+		require.Nil(t, err)
+		trans.RecvBuffer = append(trans.RecvBuffer,
+			heartbeatResponse("a", &schedulepb.HeartbeatResponse{Tables: tableStatus(2)}),
+			heartbeatResponse("b", b))
+		trans.SendBuffer = nil
+		_, _, err = coord.poll(ctx, 0, currentTables, aliveCaptures, nil)
+		require.Nil(t, err)
+		require.True(t, coord.captureM.CheckAllCaptureInitialized())
+		return coord, trans
+	}
+
+	// Drain capture "b" with a deadline.
+	coord, trans := initCoordinator(&schedulepb.HeartbeatResponse{Tables: tableStatus(1)})
+	require.False(t, coord.IsCaptureDraining("b"))
+	deadline := time.Now().Add(-time.Second).Truncate(time.Millisecond)
+	count, err := coord.DrainCapture("b", deadline)
+	require.Nil(t, err)
+	require.Equal(t, 1, count)
+	require.True(t, coord.IsCaptureDraining("b"))
+	trans.SendBuffer = nil
+	_, _, err = coord.poll(ctx, 0, currentTables, aliveCaptures, nil)
+	require.Nil(t, err)
+	var heartbeat *schedulepb.Heartbeat
+	for _, msg := range trans.SendBuffer {
+		if msg.To == "b" && msg.MsgType == schedulepb.MsgHeartbeat {
+			heartbeat = msg.Heartbeat
+		}
+	}
+	require.NotNil(t, heartbeat)
+	require.True(t, heartbeat.IsStopping)
+	require.Equal(t, deadline.UnixMilli(), heartbeat.DrainDeadline)
+
+	// The owner is changed before table 1 is moved out, capture "b" reports
+	// it is stopping with the deadline.
+	coord, trans = initCoordinator(&schedulepb.HeartbeatResponse{
+		Tables:        tableStatus(1),
+		Liveness:      model.LivenessCaptureStopping,
+		DrainDeadline: heartbeat.DrainDeadline,
+	})
+	require.True(t, coord.IsCaptureDraining("b"))
+	require.Equal(t, "b", coord.schedulerM.DrainingTarget())
+	require.True(t, deadline.Equal(coord.schedulerM.DrainDeadline()))
+	// Table 1 is moved out by the new owner.
+	var addTable *schedulepb.AddTableRequest
+	for _, msg := range trans.SendBuffer {
+		if msg.MsgType == schedulepb.MsgDispatchTableRequest {
+			addTable = msg.DispatchTableRequest.GetAddTable()
+			require.Equal(t, "a", msg.To)
+		}
+	}
+	require.NotNil(t, addTable)
+	require.EqualValues(t, 1, addTable.Span.TableID)
 }
 
 func TestCoordinatorAdvanceCheckpoint(t *testing.T) {
@@ -580,3 +709,34 @@ func TestCoordinatorDropMsgIfChangefeedEpochMismatch(t *testing.T) {
 	require.EqualValues(t, "1", msgs[0].From)
 	require.EqualValues(t, "3", msgs[1].From)
 }
+
+func TestCoordinatorIsTableRemoved(t *testing.T) {
+	t.Parallel()
+
+	coord := coordinator{
+		version:   "6.2.0",
+		revision:  schedulepb.OwnerRevision{Revision: 3},
+		captureID: "a",
+	}
+	cfg := config.NewDefaultSchedulerConfig()
+	coord.captureM = member.NewCaptureManager("", model.ChangeFeedID{}, coord.revision, cfg)
+	coord.replicationM = replication.NewReplicationManager(10, model.ChangeFeedID{})
+
+	// Captures are not initialized yet.
+	coord.captureM.Captures["a"] = &member.CaptureStatus{State: member.CaptureStateUninitialized}
+	require.False(t, coord.IsTableRemoved(1))
+
+	coord.captureM.SetInitializedForTests(true)
+	coord.captureM.Captures["a"] = &member.CaptureStatus{State: member.CaptureStateInitialized}
+	require.True(t, coord.IsTableRemoved(1))
+
+	span := spanz.TableIDToComparableSpan(1)
+	span.StartKey = append(span.StartKey, 'a')
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:    span,
+		State:   replication.ReplicationSetStateRemoving,
+		Primary: "a",
+	})
+	require.False(t, coord.IsTableRemoved(1))
+	require.True(t, coord.IsTableRemoved(2))
+}
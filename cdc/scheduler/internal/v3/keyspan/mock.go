@@ -79,8 +79,9 @@ func NewReconcilerForTests(
 	cache RegionCache, config *config.ChangefeedSchedulerConfig,
 ) *Reconciler {
 	return &Reconciler{
-		tableSpans: make(map[int64]splittedSpans),
-		config:     config,
-		splitter:   []splitter{newRegionCountSplitter(model.ChangeFeedID{}, cache)},
+		tableSpans:   make(map[int64]splittedSpans),
+		config:       config,
+		splitter:     []splitter{newRegionCountSplitter(model.ChangeFeedID{}, cache)},
+		spanSplitter: newRegionCountSplitter(model.ChangeFeedID{}, cache),
 	}
 }
@@ -14,7 +14,10 @@
 package keyspan
 
 import (
+	"bytes"
 	"context"
+	"sort"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -28,6 +31,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 )
 
 type splitter interface {
@@ -39,7 +43,27 @@ type splitter interface {
 
 type splittedSpans struct {
 	byAddTable bool
-	spans      []tablepb.Span
+	// rebalance is the ongoing splitting or merging of spans.
+	rebalance *spanRebalance
+	// updatedAt is the time when replications of spans are all found.
+	updatedAt time.Time
+	spans     []tablepb.Span
+}
+
+// spanRebalance replaces some spans of a table by splitting or merging.
+// Spans are replaced by a replace spans task, new spans are prepared while
+// replaced spans keep replicating, and replaced spans are removed only
+// after all new spans are prepared, like moving a table.
+type spanRebalance struct {
+	// kept is the spans which are not changed.
+	kept []tablepb.Span
+	// replaced is the spans which are removed.
+	replaced []tablepb.Span
+	// captures is the primary captures of replaced spans, new spans are
+	// added to them if they are still alive.
+	captures []model.CaptureID
+	// spans is the new spans.
+	spans []tablepb.Span
 }
 
 // Reconciler reconciles span and table mapping, make sure spans are in
@@ -52,6 +76,9 @@ type Reconciler struct {
 	config       *config.ChangefeedSchedulerConfig
 
 	splitter []splitter
+	// spanSplitter splits hot spans of replicating tables.
+	spanSplitter      splitter
+	lastRebalanceTime time.Time
 }
 
 // NewReconciler returns a Reconciler.
@@ -72,6 +99,7 @@ func NewReconciler(
 			newWriteSplitter(changefeedID, pdapi),
 			newRegionCountSplitter(changefeedID, up.RegionCache),
 		},
+		spanSplitter: newRegionCountSplitter(changefeedID, up.RegionCache),
 	}, nil
 }
 
@@ -83,6 +111,7 @@ func NewReconciler(
 // 4. Add table by DDL.
 // 5. Drop table by DDL.
 // 6. Some captures fail, does NOT affect spans.
+// 7. Hot spans are split and cold spans are merged.
 func (m *Reconciler) Reconcile(
 	ctx context.Context,
 	currentTables *replication.TableRanges,
@@ -102,6 +131,14 @@ func (m *Reconciler) Reconcile(
 
 		// Reconcile spans from current replications.
 		coveredSpans, holes := replications.FindHoles(tableStart, tableEnd)
+		if ss, ok := m.tableSpans[tableID]; ok && ss.rebalance != nil {
+			// 7. Spans are split or merged, replaced spans are replaced by
+			// new spans by tasks from RebalanceTasks.
+			m.reconcileRebalance(&ss, replications)
+			m.tableSpans[tableID] = ss
+			updateCache = true
+			return true
+		}
 		if len(coveredSpans) == 0 {
 			// No such spans in replications.
 			if _, ok := m.tableSpans[tableID]; ok {
@@ -165,6 +202,9 @@ func (m *Reconciler) Reconcile(
 			// Found and no hole, maybe:
 			// 2. owner switch and no capture fails.
 			ss := m.tableSpans[tableID]
+			if ss.byAddTable || ss.updatedAt.IsZero() {
+				ss.updatedAt = time.Now()
+			}
 			ss.byAddTable = false
 			ss.spans = ss.spans[:0]
 			ss.spans = append(ss.spans, coveredSpans...)
@@ -197,6 +237,10 @@ func (m *Reconciler) Reconcile(
 		}
 	}
 
+	if m.rebalanceSpans(ctx, replications, compat) {
+		updateCache = true
+	}
+
 	if updateCache {
 		m.spanCache = make([]tablepb.Span, 0)
 		for _, ss := range m.tableSpans {
@@ -205,3 +249,274 @@ func (m *Reconciler) Reconcile(
 	}
 	return m.spanCache
 }
+
+// rebalanceSpans splits hot spans and merges adjacent cold spans of
+// replicating tables. It returns true if spans are changed.
+//
+// It only records the rebalance, spans are replaced by tasks returned from
+// RebalanceTasks.
+func (m *Reconciler) rebalanceSpans(
+	ctx context.Context,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	compat *compat.Compat,
+) bool {
+	if m.config == nil || !compat.CheckSpanReplicationEnabled() ||
+		(m.config.SplitWriteThreshold == 0 && m.config.MergeWriteThreshold == 0) {
+		return false
+	}
+	interval := m.config.GetSpanRebalanceInterval()
+	now := time.Now()
+	if now.Sub(m.lastRebalanceTime) < interval {
+		return false
+	}
+	m.lastRebalanceTime = now
+
+	changed := false
+	for tableID, ss := range m.tableSpans {
+		if ss.byAddTable || ss.rebalance != nil ||
+			now.Sub(ss.updatedAt) < interval {
+			continue
+		}
+		reps := make([]*replication.ReplicationSet, 0, len(ss.spans))
+		rates := make([]uint64, 0, len(ss.spans))
+		for _, span := range ss.spans {
+			rep, ok := replications.Get(span)
+			if !ok || rep.State != replication.ReplicationSetStateReplicating {
+				break
+			}
+			reps = append(reps, rep)
+			rates = append(rates, rep.Stats.ByteRate)
+		}
+		if len(rates) != len(ss.spans) {
+			continue
+		}
+		spans := m.splitHotSpans(ctx, ss.spans, rates)
+		if spans == nil {
+			spans = m.mergeColdSpans(ss.spans, rates)
+		}
+		if spans == nil {
+			continue
+		}
+		log.Info("schedulerv3: rebalance spans by write rate",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Int64("tableID", tableID),
+			zap.Int("oldSpans", len(ss.spans)),
+			zap.Int("newSpans", len(spans)),
+			zap.Int("splitWriteThreshold", m.config.SplitWriteThreshold),
+			zap.Int("mergeWriteThreshold", m.config.MergeWriteThreshold))
+		// Keep spans which are not changed, the others are replaced.
+		rb := &spanRebalance{}
+		for i := range ss.spans {
+			kept := false
+			for j := range spans {
+				if ss.spans[i].Eq(&spans[j]) {
+					kept = true
+					break
+				}
+			}
+			if kept {
+				rb.kept = append(rb.kept, ss.spans[i])
+				continue
+			}
+			rb.replaced = append(rb.replaced, ss.spans[i])
+			rb.captures = append(rb.captures, reps[i].Primary)
+		}
+		for i := range spans {
+			if !spansContain(rb.kept, &spans[i]) {
+				rb.spans = append(rb.spans, spans[i])
+			}
+		}
+		ss.rebalance = rb
+		m.reconcileRebalance(&ss, replications)
+		m.tableSpans[tableID] = ss
+		changed = true
+	}
+	return changed
+}
+
+// reconcileRebalance updates desired spans of a table during a rebalance.
+// Desired spans are the kept spans, the replaced spans which are not
+// removed yet and the new spans which are promoted already, so that the
+// basic scheduler does not add or remove these spans by itself.
+func (m *Reconciler) reconcileRebalance(
+	ss *splittedSpans,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) {
+	rb := ss.rebalance
+	spans := make([]tablepb.Span, 0, len(rb.kept)+len(rb.replaced)+len(rb.spans))
+	spans = append(spans, rb.kept...)
+	removed := true
+	for i := range rb.replaced {
+		if _, ok := getReplicationSet(replications, &rb.replaced[i]); !ok {
+			continue
+		}
+		removed = false
+		spans = append(spans, rb.replaced[i])
+	}
+	added := 0
+	for i := range rb.spans {
+		if _, ok := getReplicationSet(replications, &rb.spans[i]); ok {
+			spans = append(spans, rb.spans[i])
+			added++
+		}
+	}
+	ss.spans = spans
+	if removed && added == len(rb.spans) {
+		// All new spans are added, wait until they are replicating like
+		// adding a table.
+		ss.byAddTable = true
+		ss.rebalance = nil
+	}
+}
+
+// RebalanceTasks returns tasks of ongoing rebalances of spans. A task is
+// returned while all replaced spans of the table are replicating, the
+// replication manager ignores it if the spans are being replaced already.
+func (m *Reconciler) RebalanceTasks(
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	aliveCaptures map[model.CaptureID]*member.CaptureStatus,
+) []*replication.ScheduleTask {
+	var captureIDs []model.CaptureID
+	var tasks []*replication.ScheduleTask
+	for _, ss := range m.tableSpans {
+		rb := ss.rebalance
+		if rb == nil {
+			continue
+		}
+		replicating := true
+		for i := range rb.replaced {
+			rep, ok := getReplicationSet(replications, &rb.replaced[i])
+			if !ok || rep.State != replication.ReplicationSetStateReplicating {
+				replicating = false
+				break
+			}
+		}
+		if !replicating {
+			continue
+		}
+		if captureIDs == nil {
+			captureIDs = schedulableCaptures(aliveCaptures)
+		}
+		// Prefer primary captures of replaced spans, so that a split span
+		// is spread from its former capture to others.
+		targets := make([]model.CaptureID, 0, len(captureIDs))
+		for _, captureID := range rb.captures {
+			if status, ok := aliveCaptures[captureID]; ok &&
+				status.State != member.CaptureStateStopping &&
+				!slices.Contains(targets, captureID) {
+				targets = append(targets, captureID)
+			}
+		}
+		for _, captureID := range captureIDs {
+			if !slices.Contains(targets, captureID) {
+				targets = append(targets, captureID)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		task := &replication.ReplaceSpans{
+			Spans:      rb.replaced,
+			NewSpans:   rb.spans,
+			CaptureIDs: make([]model.CaptureID, 0, len(rb.spans)),
+		}
+		for i := range rb.spans {
+			task.CaptureIDs = append(task.CaptureIDs, targets[i%len(targets)])
+		}
+		tasks = append(tasks, &replication.ScheduleTask{ReplaceSpans: task})
+	}
+	return tasks
+}
+
+// splitHotSpans splits spans whose write rate exceeds the split threshold
+// into two spans. It returns nil if no span is split.
+func (m *Reconciler) splitHotSpans(
+	ctx context.Context, spans []tablepb.Span, rates []uint64,
+) []tablepb.Span {
+	if m.config.SplitWriteThreshold == 0 || m.spanSplitter == nil {
+		return nil
+	}
+	// Split a span into two spans by region count.
+	cfg := &config.ChangefeedSchedulerConfig{RegionThreshold: 1}
+	res := make([]tablepb.Span, 0, len(spans)+1)
+	split := false
+	for i, span := range spans {
+		if rates[i] > uint64(m.config.SplitWriteThreshold) {
+			if subSpans := m.spanSplitter.split(ctx, span, 2, cfg); len(subSpans) > 1 {
+				res = append(res, subSpans...)
+				split = true
+				continue
+			}
+		}
+		res = append(res, span)
+	}
+	if !split {
+		return nil
+	}
+	return res
+}
+
+// mergeColdSpans merges adjacent spans whose total write rate is below
+// the merge threshold. It returns nil if no span is merged.
+func (m *Reconciler) mergeColdSpans(
+	spans []tablepb.Span, rates []uint64,
+) []tablepb.Span {
+	if m.config.MergeWriteThreshold == 0 || len(spans) <= 1 {
+		return nil
+	}
+	res := make([]tablepb.Span, 0, len(spans))
+	res = append(res, spans[0])
+	lastRate := rates[0]
+	for i := 1; i < len(spans); i++ {
+		last := &res[len(res)-1]
+		if bytes.Equal(last.EndKey, spans[i].StartKey) &&
+			lastRate+rates[i] < uint64(m.config.MergeWriteThreshold) {
+			last.EndKey = spans[i].EndKey
+			lastRate += rates[i]
+			continue
+		}
+		res = append(res, spans[i])
+		lastRate = rates[i]
+	}
+	if len(res) == len(spans) {
+		return nil
+	}
+	return res
+}
+
+func spansContain(spans []tablepb.Span, span *tablepb.Span) bool {
+	for i := range spans {
+		if spans[i].Eq(span) {
+			return true
+		}
+	}
+	return false
+}
+
+// getReplicationSet returns the replication set of exactly the span.
+// Replication sets are indexed by start keys, a replaced span and a new
+// span may share the same start key.
+func getReplicationSet(
+	replications *spanz.BtreeMap[*replication.ReplicationSet], span *tablepb.Span,
+) (*replication.ReplicationSet, bool) {
+	rep, ok := replications.Get(*span)
+	if !ok || !rep.Span.Eq(span) {
+		return nil, false
+	}
+	return rep, true
+}
+
+// schedulableCaptures returns sorted IDs of captures which are not stopping.
+func schedulableCaptures(
+	aliveCaptures map[model.CaptureID]*member.CaptureStatus,
+) []model.CaptureID {
+	captureIDs := make([]model.CaptureID, 0, len(aliveCaptures))
+	for captureID, status := range aliveCaptures {
+		if status.State != member.CaptureStateStopping {
+			captureIDs = append(captureIDs, captureID)
+		}
+	}
+	sort.Strings(captureIDs)
+	return captureIDs
+}
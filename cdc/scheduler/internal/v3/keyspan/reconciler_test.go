@@ -16,6 +16,7 @@ package keyspan
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -259,3 +260,141 @@ func TestBatchAddRateLimit(t *testing.T) {
 	require.Equal(t, allSpan, reconciler.tableSpans[2].spans)
 	require.Equal(t, 1, len(reconciler.tableSpans))
 }
+
+func TestRebalanceSpans(t *testing.T) {
+	t.Parallel()
+
+	allSpan, cache := prepareSpanCache(t, [][3]uint8{
+		{1, 0, 1}, // table ID, start key suffix, end key suffix.
+		{1, 1, 2},
+		{1, 2, 3},
+		{1, 3, 4},
+	})
+
+	cfg := &config.SchedulerConfig{
+		ChangefeedSettings: &config.ChangefeedSchedulerConfig{
+			EnableTableAcrossNodes: true,
+			RegionThreshold:        100,
+			SplitWriteThreshold:    100,
+			MergeWriteThreshold:    10,
+			SpanRebalanceInterval:  time.Hour,
+		},
+	}
+	compat := compat.New(cfg, map[string]*model.CaptureInfo{})
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"1": {ID: "1", State: member.CaptureStateInitialized},
+		"2": {ID: "2", State: member.CaptureStateInitialized},
+	}
+	ctx := context.Background()
+	tableSpan := spanz.TableIDToComparableSpan(1)
+	replicating := func(
+		span tablepb.Span, captureID model.CaptureID, checkpointTs, rate uint64,
+	) *replication.ReplicationSet {
+		return &replication.ReplicationSet{
+			Span:       span,
+			State:      replication.ReplicationSetStateReplicating,
+			Primary:    captureID,
+			Checkpoint: tablepb.Checkpoint{CheckpointTs: checkpointTs},
+			Stats:      tablepb.Stats{ByteRate: rate},
+		}
+	}
+
+	reps := spanz.NewBtreeMap[*replication.ReplicationSet]()
+	reconciler := NewReconcilerForTests(cache, cfg.ChangefeedSettings)
+	expire := func() {
+		reconciler.lastRebalanceTime = time.Time{}
+		for tableID, ss := range reconciler.tableSpans {
+			ss.updatedAt = time.Now().Add(-time.Hour)
+			reconciler.tableSpans[tableID] = ss
+		}
+	}
+	currentTables := &replication.TableRanges{}
+	currentTables.UpdateTables([]model.TableID{1})
+	spans := reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, []tablepb.Span{tableSpan}, spans)
+
+	// Do not split spans which are just added.
+	reps.ReplaceOrInsert(tableSpan, replicating(tableSpan, "1", 10, 1000))
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, []tablepb.Span{tableSpan}, spans)
+	require.Nil(t, reconciler.RebalanceTasks(reps, captures))
+
+	// Do not split spans before the rebalance interval.
+	reconciler.lastRebalanceTime = time.Time{}
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, []tablepb.Span{tableSpan}, spans)
+	require.Nil(t, reconciler.tableSpans[1].rebalance)
+
+	// Split the hot span, the hot span is kept in desired spans and replaced
+	// by a replace spans task.
+	expire()
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	splitSpans := []tablepb.Span{
+		{TableID: 1, StartKey: tableSpan.StartKey, EndKey: allSpan[1].EndKey},
+		{TableID: 1, StartKey: allSpan[2].StartKey, EndKey: tableSpan.EndKey},
+	}
+	require.Equal(t, []tablepb.Span{tableSpan}, spans)
+	require.Equal(t, splitSpans, reconciler.tableSpans[1].rebalance.spans)
+	tasks := reconciler.RebalanceTasks(reps, captures)
+	require.Equal(t, []*replication.ScheduleTask{{
+		ReplaceSpans: &replication.ReplaceSpans{
+			Spans:      []tablepb.Span{tableSpan},
+			NewSpans:   splitSpans,
+			CaptureIDs: []model.CaptureID{"1", "2"},
+		},
+	}}, tasks)
+	// The task is returned again until the hot span is removed.
+	require.Equal(t, tasks, reconciler.RebalanceTasks(reps, captures))
+
+	// Split spans are prepared and the hot span is being removed.
+	reps.GetV(tableSpan).State = replication.ReplicationSetStateRemoving
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, []tablepb.Span{tableSpan}, spans)
+	require.Nil(t, reconciler.RebalanceTasks(reps, captures))
+
+	// Split spans are promoted after the hot span is removed.
+	reps.Delete(tableSpan)
+	reps.ReplaceOrInsert(splitSpans[0], replicating(splitSpans[0], "1", 20, 1))
+	reps.ReplaceOrInsert(splitSpans[1], replicating(splitSpans[1], "2", 20, 2))
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, splitSpans, spans)
+	require.Nil(t, reconciler.tableSpans[1].rebalance)
+	require.True(t, reconciler.tableSpans[1].byAddTable)
+	require.Nil(t, reconciler.RebalanceTasks(reps, captures))
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, splitSpans, spans)
+	require.False(t, reconciler.tableSpans[1].byAddTable)
+
+	// Do not merge spans which are not replicating.
+	expire()
+	reps.GetV(splitSpans[0]).State = replication.ReplicationSetStatePrepare
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, splitSpans, spans)
+	require.Nil(t, reconciler.tableSpans[1].rebalance)
+
+	// Merge cold spans.
+	expire()
+	reps.GetV(splitSpans[0]).State = replication.ReplicationSetStateReplicating
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, splitSpans, spans)
+	require.Equal(t, []tablepb.Span{tableSpan}, reconciler.tableSpans[1].rebalance.spans)
+	tasks = reconciler.RebalanceTasks(reps, captures)
+	require.Equal(t, []*replication.ScheduleTask{{
+		ReplaceSpans: &replication.ReplaceSpans{
+			Spans:      splitSpans,
+			NewSpans:   []tablepb.Span{tableSpan},
+			CaptureIDs: []model.CaptureID{"1"},
+		},
+	}}, tasks)
+	reps.GetV(splitSpans[0]).State = replication.ReplicationSetStateRemoving
+	reps.GetV(splitSpans[1]).State = replication.ReplicationSetStateRemoving
+	require.Nil(t, reconciler.RebalanceTasks(reps, captures))
+	reps.Delete(splitSpans[0])
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, splitSpans[1:], spans)
+	reps.Delete(splitSpans[1])
+	reps.ReplaceOrInsert(tableSpan, replicating(tableSpan, "1", 30, 1))
+	spans = reconciler.Reconcile(ctx, currentTables, reps, captures, compat)
+	require.Equal(t, []tablepb.Span{tableSpan}, spans)
+	require.Nil(t, reconciler.tableSpans[1].rebalance)
+}
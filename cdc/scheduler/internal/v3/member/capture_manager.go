@@ -14,6 +14,8 @@
 package member
 
 import (
+	"time"
+
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -64,10 +66,23 @@ type CaptureStatus struct {
 	ID       model.CaptureID
 	Addr     string
 	IsOwner  bool
+	// MemoryUsage is the memory usage in bytes reported by the capture
+	// when stats are collected.
+	MemoryUsage uint64
+	// Labels are user-defined labels of the capture.
+	Labels map[string]string
+	// DrainDeadline is the drain deadline reported by a stopping capture,
+	// zero means no deadline.
+	DrainDeadline time.Time
+
+	// cancelStopping is true if draining the capture is cancelled and the
+	// capture is not alive yet.
+	cancelStopping bool
 }
 
 func newCaptureStatus(
 	rev schedulepb.OwnerRevision, id model.CaptureID, addr string, isOwner bool,
+	labels map[string]string,
 ) *CaptureStatus {
 	return &CaptureStatus{
 		OwnerRev: rev,
@@ -75,6 +90,7 @@ func newCaptureStatus(
 		ID:       id,
 		Addr:     addr,
 		IsOwner:  isOwner,
+		Labels:   labels,
 	}
 }
 
@@ -100,12 +116,26 @@ func (c *CaptureStatus) handleHeartbeatResponse(
 			zap.String("captureAddr", c.Addr))
 	}
 	if resp.Liveness == model.LivenessCaptureStopping {
-		c.State = CaptureStateStopping
-		log.Info("schedulerv3: capture stopping",
+		// Ignore the stale liveness if draining the capture is cancelled.
+		if !c.cancelStopping {
+			if resp.DrainDeadline != 0 {
+				c.DrainDeadline = time.UnixMilli(resp.DrainDeadline)
+			}
+			c.State = CaptureStateStopping
+			log.Info("schedulerv3: capture stopping",
+				zap.String("capture", c.ID),
+				zap.String("captureAddr", c.Addr))
+		}
+	} else if c.cancelStopping {
+		c.cancelStopping = false
+		log.Info("schedulerv3: capture resumed from stopping",
 			zap.String("capture", c.ID),
 			zap.String("captureAddr", c.Addr))
 	}
 	c.Tables = resp.Tables
+	if resp.MemoryUsage != 0 {
+		c.MemoryUsage = resp.MemoryUsage
+	}
 }
 
 // CaptureChanges wraps changes of captures.
@@ -169,7 +199,7 @@ func (c *CaptureManager) checkAllCaptureInitialized() bool {
 // necessary.
 func (c *CaptureManager) Tick(
 	reps *spanz.BtreeMap[*replication.ReplicationSet],
-	drainingCapture model.CaptureID,
+	drainingCapture model.CaptureID, drainDeadline time.Time,
 	barrier *schedulepb.Barrier,
 ) []*schedulepb.Message {
 	c.tickCounter++
@@ -188,6 +218,10 @@ func (c *CaptureManager) Tick(
 	})
 	msgs := make([]*schedulepb.Message, 0, len(c.Captures))
 	for to := range c.Captures {
+		var deadline int64
+		if drainingCapture == to && !drainDeadline.IsZero() {
+			deadline = drainDeadline.UnixMilli()
+		}
 		msgs = append(msgs, &schedulepb.Message{
 			To:      to,
 			MsgType: schedulepb.MsgHeartbeat,
@@ -198,6 +232,10 @@ func (c *CaptureManager) Tick(
 				IsStopping:   drainingCapture == to,
 				CollectStats: c.pendingCollect,
 				Barrier:      barrier,
+				// CancelStopping let the receiver capture know that draining
+				// is cancelled and it should be alive again.
+				CancelStopping: c.Captures[to].cancelStopping,
+				DrainDeadline:  deadline,
 			},
 		})
 	}
@@ -205,6 +243,23 @@ func (c *CaptureManager) Tick(
 	return msgs
 }
 
+// CancelStopping cancels draining the capture, the capture becomes alive
+// again. It returns false if the capture is not found or not stopping.
+func (c *CaptureManager) CancelStopping(id model.CaptureID) bool {
+	captureStatus, ok := c.Captures[id]
+	if !ok || captureStatus.State != CaptureStateStopping {
+		return false
+	}
+	captureStatus.State = CaptureStateInitialized
+	captureStatus.DrainDeadline = time.Time{}
+	captureStatus.cancelStopping = true
+	log.Info("schedulerv3: cancel stopping capture",
+		zap.String("namespace", c.changefeedID.Namespace),
+		zap.String("changefeed", c.changefeedID.ID),
+		zap.String("capture", id))
+	return true
+}
+
 // HandleMessage handles messages sent from other captures.
 func (c *CaptureManager) HandleMessage(
 	msgs []*schedulepb.Message,
@@ -232,10 +287,11 @@ func (c *CaptureManager) HandleAliveCaptureUpdate(
 		if _, ok := c.Captures[id]; !ok {
 			// A new capture.
 			c.Captures[id] = newCaptureStatus(
-				c.OwnerRev, id, info.AdvertiseAddr, c.ownerID == id)
+				c.OwnerRev, id, info.AdvertiseAddr, c.ownerID == id, info.Labels)
 			log.Info("schedulerv3: find a new capture",
 				zap.String("captureAddr", info.AdvertiseAddr),
-				zap.String("capture", id))
+				zap.String("capture", id),
+				zap.Any("labels", info.Labels))
 			msgs = append(msgs, &schedulepb.Message{
 				To:        id,
 				MsgType:   schedulepb.MsgHeartbeat,
@@ -15,6 +15,7 @@ package member
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -34,7 +35,7 @@ func TestCaptureStatusHandleHeartbeatResponse(t *testing.T) {
 
 	rev := schedulepb.OwnerRevision{Revision: 1}
 	epoch := schedulepb.ProcessorEpoch{Epoch: "test"}
-	c := newCaptureStatus(rev, "", "", true)
+	c := newCaptureStatus(rev, "", "", true, nil)
 	require.Equal(t, CaptureStateUninitialized, c.State)
 	require.True(t, c.IsOwner)
 
@@ -43,6 +44,12 @@ func TestCaptureStatusHandleHeartbeatResponse(t *testing.T) {
 	require.Equal(t, CaptureStateInitialized, c.State)
 	require.Equal(t, epoch, c.Epoch)
 
+	// Memory usage is kept if it is not reported.
+	c.handleHeartbeatResponse(&schedulepb.HeartbeatResponse{MemoryUsage: 1024}, epoch)
+	require.Equal(t, uint64(1024), c.MemoryUsage)
+	c.handleHeartbeatResponse(&schedulepb.HeartbeatResponse{}, epoch)
+	require.Equal(t, uint64(1024), c.MemoryUsage)
+
 	// Processor epoch mismatch
 	c.handleHeartbeatResponse(&schedulepb.HeartbeatResponse{
 		Liveness: model.LivenessCaptureStopping,
@@ -54,6 +61,29 @@ func TestCaptureStatusHandleHeartbeatResponse(t *testing.T) {
 		&schedulepb.HeartbeatResponse{Liveness: model.LivenessCaptureStopping}, epoch)
 	require.Equal(t, CaptureStateStopping, c.State)
 	require.Equal(t, epoch, c.Epoch)
+
+	// Stopping -> Initialized, the stale liveness is ignored until the
+	// capture reports alive.
+	cm := NewCaptureManager("", model.ChangeFeedID{}, rev, config.NewDefaultSchedulerConfig())
+	cm.Captures["a"] = c
+	require.False(t, cm.CancelStopping("b"))
+	require.True(t, cm.CancelStopping("a"))
+	require.False(t, cm.CancelStopping("a"))
+	require.Equal(t, CaptureStateInitialized, c.State)
+	reps := spanz.NewBtreeMap[*replication.ReplicationSet]()
+	require.Empty(t, cm.Tick(reps, captureIDNotDraining, time.Time{}, nil))
+	msgs := cm.Tick(reps, captureIDNotDraining, time.Time{}, nil)
+	require.Len(t, msgs, 1)
+	require.True(t, msgs[0].Heartbeat.CancelStopping)
+	c.handleHeartbeatResponse(
+		&schedulepb.HeartbeatResponse{Liveness: model.LivenessCaptureStopping}, epoch)
+	require.Equal(t, CaptureStateInitialized, c.State)
+	c.handleHeartbeatResponse(
+		&schedulepb.HeartbeatResponse{Liveness: model.LivenessCaptureAlive}, epoch)
+	require.False(t, c.cancelStopping)
+	c.handleHeartbeatResponse(
+		&schedulepb.HeartbeatResponse{Liveness: model.LivenessCaptureStopping}, epoch)
+	require.Equal(t, CaptureStateStopping, c.State)
 }
 
 func TestCaptureManagerHandleAliveCaptureUpdate(t *testing.T) {
@@ -62,7 +92,7 @@ func TestCaptureManagerHandleAliveCaptureUpdate(t *testing.T) {
 	rev := schedulepb.OwnerRevision{}
 	cm := NewCaptureManager("1", model.ChangeFeedID{}, rev, config.NewDefaultSchedulerConfig())
 	ms := map[model.CaptureID]*model.CaptureInfo{
-		"1": {}, "2": {}, "3": {},
+		"1": {}, "2": {}, "3": {Labels: map[string]string{"zone": "a"}},
 	}
 
 	// Initial handle alive captures.
@@ -79,6 +109,7 @@ func TestCaptureManagerHandleAliveCaptureUpdate(t *testing.T) {
 	require.Contains(t, cm.Captures, "2")
 	require.False(t, cm.Captures["2"].IsOwner)
 	require.Contains(t, cm.Captures, "3")
+	require.Equal(t, map[string]string{"zone": "a"}, cm.Captures["3"].Labels)
 
 	// Remove one capture before init.
 	delete(ms, "1")
@@ -186,9 +217,9 @@ func TestCaptureManagerTick(t *testing.T) {
 	cm := NewCaptureManager("", model.ChangeFeedID{}, rev, config.NewDefaultSchedulerConfig())
 
 	// No heartbeat if there is no capture.
-	msgs := cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+	msgs := cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 	require.Empty(t, msgs)
-	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 	require.Empty(t, msgs)
 
 	ms := map[model.CaptureID]*model.CaptureInfo{
@@ -198,9 +229,9 @@ func TestCaptureManagerTick(t *testing.T) {
 	cm.HandleAliveCaptureUpdate(ms)
 
 	// Heartbeat even if capture is uninitialized.
-	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 	require.Empty(t, msgs)
-	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 	require.ElementsMatch(t, []*schedulepb.Message{
 		{To: "1", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
 		{To: "2", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
@@ -210,9 +241,9 @@ func TestCaptureManagerTick(t *testing.T) {
 	for _, s := range []CaptureState{CaptureStateInitialized, CaptureStateStopping} {
 		cm.Captures["1"].State = s
 		cm.Captures["2"].State = s
-		msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+		msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 		require.Empty(t, msgs)
-		msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+		msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 		require.ElementsMatch(t, []*schedulepb.Message{
 			{To: "1", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
 			{To: "2", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
@@ -220,7 +251,7 @@ func TestCaptureManagerTick(t *testing.T) {
 	}
 
 	// TableID in heartbeat.
-	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+	msgs = cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 	require.Empty(t, msgs)
 
 	tables := spanz.NewBtreeMap[*replication.ReplicationSet]()
@@ -241,7 +272,7 @@ func TestCaptureManagerTick(t *testing.T) {
 		}})
 	tables.ReplaceOrInsert(tablepb.Span{TableID: 4}, &replication.ReplicationSet{})
 
-	msgs = cm.Tick(tables, captureIDNotDraining, nil)
+	msgs = cm.Tick(tables, captureIDNotDraining, time.Time{}, nil)
 	require.Len(t, msgs, 2)
 	if msgs[0].To == "1" {
 		require.ElementsMatch(t,
@@ -276,7 +307,7 @@ func TestCaptureManagerCollectStatsTick(t *testing.T) {
 	// heartbeat :   x   x   x   x
 	// collect   :     x     x
 	for i := 1; i <= 8; i++ {
-		msgs := cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, nil)
+		msgs := cm.Tick(spanz.NewBtreeMap[*replication.ReplicationSet](), captureIDNotDraining, time.Time{}, nil)
 		if i%2 == 0 {
 			require.Len(t, msgs, 2)
 			collect := i == 4 || i == 6
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"container/heap"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -67,6 +68,20 @@ type AddTable struct {
 type RemoveTable struct {
 	Span      tablepb.Span
 	CaptureID model.CaptureID
+	// Force removes the table from the capture whatever the state of the
+	// table is, as if the capture is shutdown. It is used to force drain
+	// the capture.
+	Force bool
+}
+
+// ReplaceSpans is a schedule task for replacing replicating spans of a
+// table by new spans, it splits or merges spans.
+type ReplaceSpans struct {
+	Spans []tablepb.Span
+	// NewSpans cover the same ranges as Spans.
+	NewSpans []tablepb.Span
+	// CaptureIDs are captures of new spans.
+	CaptureIDs []model.CaptureID
 }
 
 // ScheduleTask is a schedule task that wraps add/move/remove table tasks.
@@ -75,6 +90,7 @@ type ScheduleTask struct { //nolint:revive
 	AddTable     *AddTable
 	RemoveTable  *RemoveTable
 	BurstBalance *BurstBalance
+	ReplaceSpans *ReplaceSpans
 
 	Accept Callback
 }
@@ -89,6 +105,8 @@ func (s *ScheduleTask) Name() string {
 		return "removeTable"
 	} else if s.BurstBalance != nil {
 		return "burstBalance"
+	} else if s.ReplaceSpans != nil {
+		return "replaceSpans"
 	}
 	return "unknown"
 }
@@ -96,6 +114,8 @@ func (s *ScheduleTask) Name() string {
 // Manager manages replications and running scheduling tasks.
 type Manager struct { //nolint:revive
 	spans *spanz.BtreeMap[*ReplicationSet]
+	// replacements are ongoing replacements of spans of tables.
+	replacements map[model.TableID]*spanReplacement
 
 	runningTasks       *spanz.BtreeMap[*ScheduleTask]
 	maxTaskConcurrency int
@@ -106,6 +126,7 @@ type Manager struct { //nolint:revive
 	acceptRemoveTableTask  int
 	acceptMoveTableTask    int
 	acceptBurstBalanceTask int
+	acceptReplaceSpansTask int
 
 	slowTableHeap         SetHeap
 	lastLogSlowTablesTime time.Time
@@ -122,6 +143,7 @@ func NewReplicationManager(
 	const degreeReadHeavy = 256
 	return &Manager{
 		spans:              spanz.NewBtreeMapWithDegree[*ReplicationSet](degreeReadHeavy),
+		replacements:       make(map[model.TableID]*spanReplacement),
 		runningTasks:       spanz.NewBtreeMap[*ScheduleTask](),
 		maxTaskConcurrency: maxTaskConcurrency,
 		changefeedID:       changefeedID,
@@ -134,6 +156,7 @@ func (r *Manager) HandleCaptureChanges(
 	removed map[model.CaptureID][]tablepb.TableStatus,
 	checkpointTs model.Ts,
 ) ([]*schedulepb.Message, error) {
+	sentMsgs := make([]*schedulepb.Message, 0)
 	if init != nil {
 		if r.spans.Len() != 0 {
 			log.Panic("schedulerv3: init again",
@@ -141,7 +164,7 @@ func (r *Manager) HandleCaptureChanges(
 				zap.String("changefeed", r.changefeedID.ID),
 				zap.Any("init", init), zap.Any("tablesCount", r.spans.Len()))
 		}
-		spanStatusMap := spanz.NewBtreeMap[map[model.CaptureID]*tablepb.TableStatus]()
+		spanStatusMap := spanz.NewHashMap[map[model.CaptureID]*tablepb.TableStatus]()
 		for captureID, spans := range init {
 			for i := range spans {
 				table := spans[i]
@@ -153,20 +176,25 @@ func (r *Manager) HandleCaptureChanges(
 			}
 		}
 		var err error
-		spanStatusMap.Ascend(func(span tablepb.Span, status map[string]*tablepb.TableStatus) bool {
+		tables := make([]*ReplicationSet, 0, spanStatusMap.Len())
+		spanStatusMap.Range(func(span tablepb.Span, status map[string]*tablepb.TableStatus) bool {
 			table, err1 := NewReplicationSet(span, checkpointTs, status, r.changefeedID)
-			if err != nil {
+			if err1 != nil {
 				err = errors.Trace(err1)
 				return false
 			}
-			r.spans.ReplaceOrInsert(table.Span, table)
+			tables = append(tables, table)
 			return true
 		})
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		msgs, err := r.initReplicationSets(tables)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
 	}
-	sentMsgs := make([]*schedulepb.Message, 0)
 	if removed != nil {
 		var err error
 		r.spans.Ascend(func(span tablepb.Span, table *ReplicationSet) bool {
@@ -187,10 +215,88 @@ func (r *Manager) HandleCaptureChanges(
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		for _, rs := range r.replacements {
+			for _, table := range rs.spans {
+				for captureID := range removed {
+					msgs, _, err := table.handleCaptureShutdown(captureID)
+					if err != nil {
+						return nil, errors.Trace(err)
+					}
+					sentMsgs = append(sentMsgs, msgs...)
+				}
+			}
+		}
+		msgs, err := r.pollReplacements()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+	}
+	return sentMsgs, nil
+}
+
+// initReplicationSets adds replication sets built from table statuses
+// reported by captures. Spans may overlap if the owner is switched while
+// spans of a table are being replaced, new spans which are not replicating
+// yet are removed, spans are replaced again by the reconciler.
+func (r *Manager) initReplicationSets(
+	tables []*ReplicationSet,
+) ([]*schedulepb.Message, error) {
+	priority := func(table *ReplicationSet) int {
+		if table.Primary != "" {
+			return 0
+		}
+		if table.State == ReplicationSetStateRemoving {
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		return priority(tables[i]) < priority(tables[j])
+	})
+	sentMsgs := make([]*schedulepb.Message, 0)
+	for _, table := range tables {
+		if table.Primary == "" && r.overlaps(table.Span) {
+			log.Info("schedulerv3: remove span overlapping other spans",
+				zap.String("namespace", r.changefeedID.Namespace),
+				zap.String("changefeed", r.changefeedID.ID),
+				zap.Any("replicationSet", table))
+			msgs, err := forceRemoveReplicationSet(table)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			sentMsgs = append(sentMsgs, msgs...)
+			continue
+		}
+		r.spans.ReplaceOrInsert(table.Span, table)
 	}
 	return sentMsgs, nil
 }
 
+// overlaps returns true if the span overlaps any span of its table.
+func (r *Manager) overlaps(span tablepb.Span) bool {
+	overlapped := false
+	start := tablepb.Span{TableID: span.TableID}
+	end := tablepb.Span{TableID: span.TableID, StartKey: span.EndKey}
+	r.spans.AscendRange(start, end, func(s tablepb.Span, _ *ReplicationSet) bool {
+		if bytes.Compare(s.EndKey, span.StartKey) > 0 {
+			overlapped = true
+			return false
+		}
+		return true
+	})
+	return overlapped
+}
+
+// getReplicationSet returns the replication set of exactly the span.
+func (r *Manager) getReplicationSet(span tablepb.Span) (*ReplicationSet, bool) {
+	table, ok := r.spans.Get(span)
+	if !ok || !table.Span.Eq(&span) {
+		return nil, false
+	}
+	return table, true
+}
+
 // HandleMessage handles messages sent by other captures.
 func (r *Manager) HandleMessage(
 	msgs []*schedulepb.Message,
@@ -218,6 +324,11 @@ func (r *Manager) HandleMessage(
 				zap.Stringer("type", msg.MsgType), zap.Any("message", msg))
 		}
 	}
+	msgs, err := r.pollReplacements()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sentMsgs = append(sentMsgs, msgs...)
 	return sentMsgs, nil
 }
 
@@ -225,26 +336,11 @@ func (r *Manager) handleMessageHeartbeatResponse(
 	from model.CaptureID, msg *schedulepb.HeartbeatResponse,
 ) ([]*schedulepb.Message, error) {
 	sentMsgs := make([]*schedulepb.Message, 0)
-	for _, status := range msg.Tables {
-		table, ok := r.spans.Get(status.Span)
-		if !ok {
-			log.Info("schedulerv3: ignore table status no table found",
-				zap.String("namespace", r.changefeedID.Namespace),
-				zap.String("changefeed", r.changefeedID.ID),
-				zap.Any("message", status))
-			continue
-		}
-		msgs, err := table.handleTableStatus(from, &status)
+	for i := range msg.Tables {
+		msgs, err := r.handleTableStatus(from, &msg.Tables[i])
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		if table.hasRemoved() {
-			log.Info("schedulerv3: table has removed",
-				zap.String("namespace", r.changefeedID.Namespace),
-				zap.String("changefeed", r.changefeedID.ID),
-				zap.Int64("tableID", status.Span.TableID))
-			r.spans.Delete(status.Span)
-		}
 		sentMsgs = append(sentMsgs, msgs...)
 	}
 	return sentMsgs, nil
@@ -266,8 +362,17 @@ func (r *Manager) handleMessageDispatchTableResponse(
 			zap.Any("message", msg))
 		return nil, nil
 	}
+	return r.handleTableStatus(from, status)
+}
 
-	table, ok := r.spans.Get(status.Span)
+func (r *Manager) handleTableStatus(
+	from model.CaptureID, status *tablepb.TableStatus,
+) ([]*schedulepb.Message, error) {
+	msgs, ok, err := r.handleReplacementStatus(from, status)
+	if ok || err != nil {
+		return msgs, errors.Trace(err)
+	}
+	table, ok := r.getReplicationSet(status.Span)
 	if !ok {
 		log.Info("schedulerv3: ignore table status no table found",
 			zap.String("namespace", r.changefeedID.Namespace),
@@ -275,10 +380,14 @@ func (r *Manager) handleMessageDispatchTableResponse(
 			zap.Any("message", status))
 		return nil, nil
 	}
-	msgs, err := table.handleTableStatus(from, status)
+	msgs, err = table.handleTableStatus(from, status)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if rs, ok := r.replacements[status.Span.TableID]; ok {
+		rs.updateCheckpoint(&status.Span, table.Checkpoint.CheckpointTs)
+		rs.updateCheckpoint(&status.Span, status.Checkpoint.CheckpointTs)
+	}
 	if table.hasRemoved() {
 		log.Info("schedulerv3: table has removed",
 			zap.String("namespace", r.changefeedID.Namespace),
@@ -296,6 +405,10 @@ func (r *Manager) HandleTasks(
 	// Check if a running task is finished.
 	toBeDeleted := []tablepb.Span{}
 	r.runningTasks.Ascend(func(span tablepb.Span, task *ScheduleTask) bool {
+		if r.isReplaced(span) {
+			// The task is finished when the replacement is finished.
+			return true
+		}
 		if table, ok := r.spans.Get(span); ok {
 			// If table is back to Replicating or Removed,
 			// the running task is finished.
@@ -326,6 +439,19 @@ func (r *Manager) HandleTasks(
 			}
 			continue
 		}
+		// Replacing spans does not affect by maxTaskConcurrency either, new
+		// spans are not replicating until replaced spans are removed.
+		if task.ReplaceSpans != nil {
+			msgs, err := r.handleReplaceSpansTask(task.ReplaceSpans)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			sentMsgs = append(sentMsgs, msgs...)
+			if task.Accept != nil {
+				task.Accept()
+			}
+			continue
+		}
 
 		// Check if accepting one more task exceeds maxTaskConcurrency.
 		if r.runningTasks.Len() == r.maxTaskConcurrency {
@@ -413,6 +539,16 @@ func (r *Manager) handleRemoveTableTask(
 		r.spans.Delete(task.Span)
 		return nil, nil
 	}
+	if task.Force {
+		msgs, err := table.handleForceRemoveTable(task.CaptureID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if table.hasRemoved() {
+			r.spans.Delete(task.Span)
+		}
+		return msgs, nil
+	}
 	return table.handleRemoveTable()
 }
 
@@ -463,6 +599,20 @@ func (r *Manager) handleBurstBalanceTasks(
 	}
 	for i := range task.RemoveTables {
 		removeTable := task.RemoveTables[i]
+		if removeTable.Force {
+			if _, ok := r.spans.Get(removeTable.Span); !ok {
+				continue
+			}
+			// Force remove overrides the running task, the table is not
+			// tracked by the task anymore.
+			msgs, err := r.handleRemoveTableTask(&removeTable)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			sentMsgs = append(sentMsgs, msgs...)
+			r.runningTasks.Delete(removeTable.Span)
+			continue
+		}
 		if _, ok := r.runningTasks.Get(removeTable.Span); ok {
 			// Skip add table if the table is already running a task.
 			continue
@@ -680,6 +830,8 @@ func (r *Manager) CollectMetrics() {
 	r.acceptMoveTableTask = 0
 	metricAcceptScheduleTask.WithLabelValues("burstBalance").Add(float64(r.acceptBurstBalanceTask))
 	r.acceptBurstBalanceTask = 0
+	metricAcceptScheduleTask.WithLabelValues("replaceSpans").Add(float64(r.acceptReplaceSpansTask))
+	r.acceptReplaceSpansTask = 0
 	runningScheduleTaskGauge.
 		WithLabelValues(cf.Namespace, cf.ID).Set(float64(r.runningTasks.Len()))
 	var stateCounters [6]int
@@ -723,6 +875,7 @@ func (r *Manager) CleanMetrics() {
 	metricAcceptScheduleTask.DeleteLabelValues("removeTable")
 	metricAcceptScheduleTask.DeleteLabelValues("moveTable")
 	metricAcceptScheduleTask.DeleteLabelValues("burstBalance")
+	metricAcceptScheduleTask.DeleteLabelValues("replaceSpans")
 	var stateCounters [6]int
 	for s := range stateCounters {
 		tableStateGauge.
@@ -531,6 +531,63 @@ func TestReplicationManagerBurstBalanceMoveTables(t *testing.T) {
 	require.True(t, r.runningTasks.Has(spanz.TableIDToComparableSpan(2)))
 }
 
+func TestReplicationManagerBurstBalanceForceRemoveTables(t *testing.T) {
+	t.Parallel()
+
+	r := NewReplicationManager(10, model.ChangeFeedID{})
+	// Table 1 is added to "1", but "1" never prepares it.
+	span1 := spanz.TableIDToComparableSpan(1)
+	_, err := r.HandleTasks([]*ScheduleTask{{
+		AddTable: &AddTable{Span: span1, CaptureID: "1", CheckpointTs: 1},
+	}})
+	require.Nil(t, err)
+	require.Equal(t, ReplicationSetStatePrepare, r.spans.GetV(span1).State)
+	require.True(t, r.runningTasks.Has(span1))
+	// Table 2 is moving from "1" to "2".
+	span2 := spanz.TableIDToComparableSpan(2)
+	table2, err := NewReplicationSet(span2, 0, map[string]*tablepb.TableStatus{
+		"1": {Span: span2, State: tablepb.TableStateReplicating},
+	}, model.ChangeFeedID{})
+	require.Nil(t, err)
+	r.spans.ReplaceOrInsert(span2, table2)
+	_, err = r.HandleTasks([]*ScheduleTask{{
+		MoveTable: &MoveTable{Span: span2, DestCapture: "2"},
+	}})
+	require.Nil(t, err)
+	require.Equal(t, ReplicationSetStatePrepare, table2.State)
+
+	// Tables are removed from "1" whatever the state is, even if they are
+	// running tasks.
+	msgs, err := r.HandleTasks([]*ScheduleTask{{
+		BurstBalance: &BurstBalance{
+			RemoveTables: []RemoveTable{
+				{Span: span1, CaptureID: "1", Force: true},
+				{Span: span2, CaptureID: "1", Force: true},
+				{Span: spanz.TableIDToComparableSpan(3), CaptureID: "1", Force: true},
+			},
+		},
+	}})
+	require.Nil(t, err)
+	require.Len(t, msgs, 2)
+	for _, span := range []tablepb.Span{span1, span2} {
+		require.Contains(t, msgs, &schedulepb.Message{
+			To:      "1",
+			MsgType: schedulepb.MsgDispatchTableRequest,
+			DispatchTableRequest: &schedulepb.DispatchTableRequest{
+				Request: &schedulepb.DispatchTableRequest_RemoveTable{
+					RemoveTable: &schedulepb.RemoveTableRequest{Span: span},
+				},
+			},
+		})
+		require.False(t, r.runningTasks.Has(span))
+		require.NotContains(t, r.spans.GetV(span).Captures, "1")
+	}
+	// Table 1 can be added again, and table 2 is still moving to "2".
+	require.Equal(t, ReplicationSetStateAbsent, r.spans.GetV(span1).State)
+	require.Equal(t, ReplicationSetStatePrepare, table2.State)
+	require.True(t, table2.isInRole("2", RoleSecondary))
+}
+
 func TestReplicationManagerMaxTaskConcurrency(t *testing.T) {
 	t.Parallel()
 
@@ -886,6 +886,40 @@ func (r *ReplicationSet) handleRemoveTable() ([]*schedulepb.Message, error) {
 	return r.poll(&status, r.Primary)
 }
 
+// handleForceRemoveTable removes the table from the capture whatever the
+// state of the replication set. The capture is asked to remove the table,
+// and the replication set transits as if the capture is shutdown.
+func (r *ReplicationSet) handleForceRemoveTable(
+	captureID model.CaptureID,
+) ([]*schedulepb.Message, error) {
+	if _, ok := r.Captures[captureID]; !ok {
+		log.Warn("schedulerv3: force remove table is ignored",
+			zap.Any("replicationSet", r), zap.Int64("tableID", r.Span.TableID),
+			zap.String("captureID", captureID))
+		return nil, nil
+	}
+	oldState := r.State
+	msgs, _, err := r.handleCaptureShutdown(captureID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	log.Info("schedulerv3: replication state transition, force remove table",
+		zap.Any("replicationSet", r), zap.String("captureID", captureID),
+		zap.Stringer("old", oldState), zap.Stringer("new", r.State))
+	msgs = append(msgs, &schedulepb.Message{
+		To:      captureID,
+		MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: &schedulepb.DispatchTableRequest{
+			Request: &schedulepb.DispatchTableRequest_RemoveTable{
+				RemoveTable: &schedulepb.RemoveTableRequest{
+					Span: r.Span,
+				},
+			},
+		},
+	})
+	return msgs, nil
+}
+
 func (r *ReplicationSet) hasRemoved() bool {
 	// It has been removed successfully if it's state is Removing,
 	// and there is no capture has it.
@@ -921,7 +955,11 @@ func (r *ReplicationSet) updateCheckpointAndStats(
 	if r.Checkpoint.ResolvedTs < checkpoint.ResolvedTs {
 		r.Checkpoint.ResolvedTs = checkpoint.ResolvedTs
 	}
-	r.Stats = stats
+	// Stats are only reported when they are collected, keep the last
+	// collected stats so that they can be used by schedulers.
+	if stats.CurrentTs != 0 {
+		r.Stats = stats
+	}
 }
 
 // SetHeap is a max-heap, it implements heap.Interface.
@@ -1354,6 +1354,20 @@ func TestReplicationSetRemoveRestart(t *testing.T) {
 	require.True(t, r.hasRemoved())
 }
 
+func TestReplicationSetUpdateStats(t *testing.T) {
+	t.Parallel()
+
+	r := &ReplicationSet{}
+	stats := tablepb.Stats{CurrentTs: 1, EventRate: 10, ByteRate: 100}
+	r.updateCheckpointAndStats(tablepb.Checkpoint{CheckpointTs: 1}, stats)
+	require.Equal(t, stats, r.Stats)
+
+	// Stats are not collected, keep the last collected stats.
+	r.updateCheckpointAndStats(tablepb.Checkpoint{CheckpointTs: 2}, tablepb.Stats{})
+	require.Equal(t, stats, r.Stats)
+	require.Equal(t, uint64(2), r.Checkpoint.CheckpointTs)
+}
+
 func TestReplicationSetHeap_Len(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,334 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"math"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"go.uber.org/zap"
+)
+
+// spanReplacement replaces replicating spans of a table by new spans. It
+// works like moving a table, new spans are prepared as secondaries while
+// replaced spans keep replicating. Replaced spans are removed after all new
+// spans are prepared, then new spans are promoted and replicate from the
+// checkpoint where replaced spans are stopped.
+//
+// New spans may overlap replaced spans, so they are not added to the
+// replication sets of the manager until replaced spans are removed.
+type spanReplacement struct {
+	replaced []tablepb.Span
+	// checkpoints is the last checkpoint of each replaced span.
+	checkpoints []model.Ts
+	spans       []*ReplicationSet
+	// prepared is true if the secondary of the new span is prepared.
+	prepared []bool
+	// committed is true after replaced spans are asked to be removed.
+	committed bool
+	// placeholder is the running task of replaced spans, it keeps other
+	// tasks from scheduling replaced spans.
+	placeholder *ScheduleTask
+}
+
+func (s *spanReplacement) replacedIndex(span *tablepb.Span) int {
+	for i := range s.replaced {
+		if s.replaced[i].Eq(span) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *spanReplacement) updateCheckpoint(span *tablepb.Span, checkpointTs model.Ts) {
+	if i := s.replacedIndex(span); i >= 0 && s.checkpoints[i] < checkpointTs {
+		s.checkpoints[i] = checkpointTs
+	}
+}
+
+func (s *spanReplacement) checkpointTs() model.Ts {
+	checkpointTs := uint64(math.MaxUint64)
+	for _, ts := range s.checkpoints {
+		if ts < checkpointTs {
+			checkpointTs = ts
+		}
+	}
+	return checkpointTs
+}
+
+// isReplaced returns true if the span is being replaced.
+func (r *Manager) isReplaced(span tablepb.Span) bool {
+	rs, ok := r.replacements[span.TableID]
+	return ok && rs.replacedIndex(&span) >= 0
+}
+
+func (r *Manager) handleReplaceSpansTask(
+	task *ReplaceSpans,
+) ([]*schedulepb.Message, error) {
+	r.acceptReplaceSpansTask++
+	if len(task.Spans) == 0 || len(task.NewSpans) != len(task.CaptureIDs) {
+		log.Warn("schedulerv3: ignore invalid replace spans task",
+			zap.String("namespace", r.changefeedID.Namespace),
+			zap.String("changefeed", r.changefeedID.ID),
+			zap.Any("task", task))
+		return nil, nil
+	}
+	tableID := task.Spans[0].TableID
+	if _, ok := r.replacements[tableID]; ok {
+		log.Debug("schedulerv3: ignore replace spans task, already exists",
+			zap.String("namespace", r.changefeedID.Namespace),
+			zap.String("changefeed", r.changefeedID.ID),
+			zap.Int64("tableID", tableID))
+		return nil, nil
+	}
+	rs := &spanReplacement{
+		replaced:    task.Spans,
+		checkpoints: make([]model.Ts, len(task.Spans)),
+		prepared:    make([]bool, len(task.NewSpans)),
+		placeholder: &ScheduleTask{},
+	}
+	for i := range task.Spans {
+		table, ok := r.getReplicationSet(task.Spans[i])
+		_, running := r.runningTasks.Get(task.Spans[i])
+		if !ok || running || table.State != ReplicationSetStateReplicating {
+			log.Info("schedulerv3: ignore replace spans task, span is not replicating",
+				zap.String("namespace", r.changefeedID.Namespace),
+				zap.String("changefeed", r.changefeedID.ID),
+				zap.String("span", task.Spans[i].String()))
+			return nil, nil
+		}
+		rs.checkpoints[i] = table.Checkpoint.CheckpointTs
+	}
+	// New spans are prepared from the checkpoint of replaced spans, and
+	// they start replicating from where replaced spans are stopped.
+	checkpointTs := rs.checkpointTs()
+	sentMsgs := make([]*schedulepb.Message, 0, len(task.NewSpans))
+	for i := range task.NewSpans {
+		table, err := NewReplicationSet(task.NewSpans[i], checkpointTs, nil, r.changefeedID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		msgs, err := table.handleAddTable(task.CaptureIDs[i])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+		rs.spans = append(rs.spans, table)
+	}
+	for i := range task.Spans {
+		r.runningTasks.ReplaceOrInsert(task.Spans[i], rs.placeholder)
+	}
+	r.replacements[tableID] = rs
+	log.Info("schedulerv3: replace spans, prepare new spans",
+		zap.String("namespace", r.changefeedID.Namespace),
+		zap.String("changefeed", r.changefeedID.ID),
+		zap.Int64("tableID", tableID),
+		zap.Int("replaced", len(task.Spans)),
+		zap.Int("new", len(task.NewSpans)),
+		zap.Uint64("checkpointTs", checkpointTs))
+	return sentMsgs, nil
+}
+
+// handleReplacementStatus handles the status of a new span of an ongoing
+// replacement. It returns false if the span is not a new span.
+func (r *Manager) handleReplacementStatus(
+	from model.CaptureID, status *tablepb.TableStatus,
+) ([]*schedulepb.Message, bool, error) {
+	rs, ok := r.replacements[status.Span.TableID]
+	if !ok {
+		return nil, false, nil
+	}
+	for i, table := range rs.spans {
+		if !table.Span.Eq(&status.Span) {
+			continue
+		}
+		if status.State == tablepb.TableStatePrepared &&
+			table.State == ReplicationSetStatePrepare &&
+			table.isInRole(from, RoleSecondary) {
+			// Hold the prepared span in Prepare state, it is committed
+			// after replaced spans are removed.
+			rs.prepared[i] = true
+			return nil, true, nil
+		}
+		if table.isInRole(from, RoleSecondary) {
+			rs.prepared[i] = false
+		}
+		msgs, err := table.handleTableStatus(from, status)
+		return msgs, true, errors.Trace(err)
+	}
+	return nil, false, nil
+}
+
+// pollReplacements advances ongoing replacements of spans.
+func (r *Manager) pollReplacements() ([]*schedulepb.Message, error) {
+	sentMsgs := make([]*schedulepb.Message, 0)
+	for tableID, rs := range r.replacements {
+		var msgs []*schedulepb.Message
+		var err error
+		if !rs.committed {
+			msgs, err = r.pollReplacementOnPrepare(tableID, rs)
+		} else {
+			msgs, err = r.pollReplacementOnCommit(tableID, rs)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+	}
+	return sentMsgs, nil
+}
+
+// pollReplacementOnPrepare removes replaced spans after all new spans are
+// prepared. The replacement is aborted if a new span or a replaced span
+// fails, spans are replaced again by the reconciler later.
+func (r *Manager) pollReplacementOnPrepare(
+	tableID model.TableID, rs *spanReplacement,
+) ([]*schedulepb.Message, error) {
+	allPrepared := true
+	for i, table := range rs.spans {
+		if table.State == ReplicationSetStateAbsent {
+			return r.abortReplacement(tableID, rs)
+		}
+		allPrepared = allPrepared && rs.prepared[i]
+	}
+	for i := range rs.replaced {
+		table, ok := r.getReplicationSet(rs.replaced[i])
+		if !ok || table.State != ReplicationSetStateReplicating {
+			return r.abortReplacement(tableID, rs)
+		}
+	}
+	if !allPrepared {
+		return nil, nil
+	}
+
+	sentMsgs := make([]*schedulepb.Message, 0, len(rs.replaced))
+	for i := range rs.replaced {
+		table, _ := r.getReplicationSet(rs.replaced[i])
+		rs.updateCheckpoint(&table.Span, table.Checkpoint.CheckpointTs)
+		msgs, err := table.handleRemoveTable()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+	}
+	rs.committed = true
+	log.Info("schedulerv3: replace spans, new spans are prepared, remove replaced spans",
+		zap.String("namespace", r.changefeedID.Namespace),
+		zap.String("changefeed", r.changefeedID.ID),
+		zap.Int64("tableID", tableID))
+	return sentMsgs, nil
+}
+
+// pollReplacementOnCommit promotes new spans after replaced spans are
+// removed.
+func (r *Manager) pollReplacementOnCommit(
+	tableID model.TableID, rs *spanReplacement,
+) ([]*schedulepb.Message, error) {
+	for i := range rs.replaced {
+		table, ok := r.getReplicationSet(rs.replaced[i])
+		if !ok {
+			continue
+		}
+		if !table.hasRemoved() {
+			return nil, nil
+		}
+		// The capture of the span is shutdown during removing.
+		r.spans.Delete(table.Span)
+	}
+	r.deleteReplacement(tableID, rs)
+
+	checkpointTs := rs.checkpointTs()
+	sentMsgs := make([]*schedulepb.Message, 0, len(rs.spans))
+	for i, table := range rs.spans {
+		// Changes before the checkpoint are replicated by replaced spans.
+		if table.Checkpoint.CheckpointTs < checkpointTs {
+			table.Checkpoint.CheckpointTs = checkpointTs
+		}
+		if table.Checkpoint.ResolvedTs < checkpointTs {
+			table.Checkpoint.ResolvedTs = checkpointTs
+		}
+		r.spans.ReplaceOrInsert(table.Span, table)
+		secondary, ok := table.getRole(RoleSecondary)
+		if !ok || !rs.prepared[i] || table.State != ReplicationSetStatePrepare {
+			// The secondary is lost, the span is added by other schedulers.
+			continue
+		}
+		msgs, err := table.handleTableStatus(secondary, &tablepb.TableStatus{
+			Span:       table.Span,
+			State:      tablepb.TableStatePrepared,
+			Checkpoint: table.Checkpoint,
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+		// Just for place holding.
+		r.runningTasks.ReplaceOrInsert(table.Span, &ScheduleTask{})
+	}
+	log.Info("schedulerv3: replace spans, replaced spans are removed, promote new spans",
+		zap.String("namespace", r.changefeedID.Namespace),
+		zap.String("changefeed", r.changefeedID.ID),
+		zap.Int64("tableID", tableID),
+		zap.Uint64("checkpointTs", checkpointTs))
+	return sentMsgs, nil
+}
+
+func (r *Manager) abortReplacement(
+	tableID model.TableID, rs *spanReplacement,
+) ([]*schedulepb.Message, error) {
+	sentMsgs := make([]*schedulepb.Message, 0, len(rs.spans))
+	for _, table := range rs.spans {
+		msgs, err := forceRemoveReplicationSet(table)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+	}
+	r.deleteReplacement(tableID, rs)
+	log.Info("schedulerv3: replace spans, abort the replacement",
+		zap.String("namespace", r.changefeedID.Namespace),
+		zap.String("changefeed", r.changefeedID.ID),
+		zap.Int64("tableID", tableID))
+	return sentMsgs, nil
+}
+
+func (r *Manager) deleteReplacement(tableID model.TableID, rs *spanReplacement) {
+	for i := range rs.replaced {
+		if task, ok := r.runningTasks.Get(rs.replaced[i]); ok && task == rs.placeholder {
+			r.runningTasks.Delete(rs.replaced[i])
+		}
+	}
+	delete(r.replacements, tableID)
+}
+
+// forceRemoveReplicationSet removes the table from all its captures.
+func forceRemoveReplicationSet(table *ReplicationSet) ([]*schedulepb.Message, error) {
+	captureIDs := make([]model.CaptureID, 0, len(table.Captures))
+	for captureID := range table.Captures {
+		captureIDs = append(captureIDs, captureID)
+	}
+	sentMsgs := make([]*schedulepb.Message, 0, len(captureIDs))
+	for _, captureID := range captureIDs {
+		msgs, err := table.handleForceRemoveTable(captureID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sentMsgs = append(sentMsgs, msgs...)
+	}
+	return sentMsgs, nil
+}
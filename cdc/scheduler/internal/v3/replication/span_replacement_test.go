@@ -0,0 +1,235 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareReplaceSpans(t *testing.T) (*Manager, tablepb.Span, []tablepb.Span) {
+	r := NewReplicationManager(10, model.ChangeFeedID{})
+	span := spanz.TableIDToComparableSpan(1)
+	tbl, err := NewReplicationSet(span, 10, map[string]*tablepb.TableStatus{
+		"1": {Span: span, State: tablepb.TableStateReplicating},
+	}, model.ChangeFeedID{})
+	require.Nil(t, err)
+	r.spans.ReplaceOrInsert(span, tbl)
+	// The first new span shares the start key with the replaced span.
+	mid := append(append([]byte{}, span.StartKey...), 1)
+	newSpans := []tablepb.Span{
+		{TableID: 1, StartKey: span.StartKey, EndKey: mid},
+		{TableID: 1, StartKey: mid, EndKey: span.EndKey},
+	}
+	return r, span, newSpans
+}
+
+func tableStatusMessage(
+	from model.CaptureID, status ...tablepb.TableStatus,
+) *schedulepb.Message {
+	return &schedulepb.Message{
+		From:              from,
+		MsgType:           schedulepb.MsgHeartbeatResponse,
+		HeartbeatResponse: &schedulepb.HeartbeatResponse{Tables: status},
+	}
+}
+
+func TestReplicationManagerReplaceSpans(t *testing.T) {
+	t.Parallel()
+
+	r, span, newSpans := prepareReplaceSpans(t)
+	msgs, err := r.HandleTasks([]*ScheduleTask{{
+		ReplaceSpans: &ReplaceSpans{
+			Spans:      []tablepb.Span{span},
+			NewSpans:   newSpans,
+			CaptureIDs: []model.CaptureID{"1", "2"},
+		},
+	}})
+	require.Nil(t, err)
+	require.Len(t, msgs, 2)
+	for i, captureID := range []model.CaptureID{"1", "2"} {
+		require.Equal(t, captureID, msgs[i].To)
+		addTable := msgs[i].DispatchTableRequest.GetAddTable()
+		require.Equal(t, newSpans[i], addTable.Span)
+		require.True(t, addTable.IsSecondary)
+		require.Equal(t, uint64(10), addTable.Checkpoint.CheckpointTs)
+	}
+	// New spans are not added to replication sets until they are promoted.
+	require.Equal(t, 1, r.spans.Len())
+	require.True(t, r.runningTasks.Has(span))
+
+	// Ignore the task if spans are being replaced.
+	msgs, err = r.HandleTasks([]*ScheduleTask{{
+		ReplaceSpans: &ReplaceSpans{
+			Spans:      []tablepb.Span{span},
+			NewSpans:   newSpans,
+			CaptureIDs: []model.CaptureID{"2", "1"},
+		},
+	}})
+	require.Nil(t, err)
+	require.Len(t, msgs, 0)
+
+	// The replaced span keeps replicating while new spans are preparing.
+	msgs, err = r.HandleMessage([]*schedulepb.Message{tableStatusMessage("1",
+		tablepb.TableStatus{
+			Span: span, State: tablepb.TableStateReplicating,
+			Checkpoint: tablepb.Checkpoint{CheckpointTs: 15, ResolvedTs: 15},
+		},
+		tablepb.TableStatus{Span: newSpans[0], State: tablepb.TableStatePrepared},
+	)})
+	require.Nil(t, err)
+	require.Len(t, msgs, 0)
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(span).State)
+	require.Equal(t, uint64(15), r.spans.GetV(span).Checkpoint.CheckpointTs)
+	msgs, err = r.HandleTasks(nil)
+	require.Nil(t, err)
+	require.Len(t, msgs, 0)
+	require.True(t, r.runningTasks.Has(span))
+
+	// Remove the replaced span after all new spans are prepared.
+	msgs, err = r.HandleMessage([]*schedulepb.Message{tableStatusMessage("2",
+		tablepb.TableStatus{Span: newSpans[1], State: tablepb.TableStatePrepared},
+	)})
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.EqualValues(t, &schedulepb.Message{
+		To:      "1",
+		MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: &schedulepb.DispatchTableRequest{
+			Request: &schedulepb.DispatchTableRequest_RemoveTable{
+				RemoveTable: &schedulepb.RemoveTableRequest{Span: span},
+			},
+		},
+	}, msgs[0])
+	require.Equal(t, ReplicationSetStateRemoving, r.spans.GetV(span).State)
+
+	// Promote new spans from the checkpoint where the replaced span stops.
+	msgs, err = r.HandleMessage([]*schedulepb.Message{tableStatusMessage("1",
+		tablepb.TableStatus{
+			Span: span, State: tablepb.TableStateStopped,
+			Checkpoint: tablepb.Checkpoint{CheckpointTs: 20, ResolvedTs: 20},
+		},
+		tablepb.TableStatus{Span: newSpans[0], State: tablepb.TableStatePrepared},
+	)})
+	require.Nil(t, err)
+	require.Len(t, msgs, 2)
+	for i, captureID := range []model.CaptureID{"1", "2"} {
+		require.Equal(t, captureID, msgs[i].To)
+		addTable := msgs[i].DispatchTableRequest.GetAddTable()
+		require.Equal(t, newSpans[i], addTable.Span)
+		require.False(t, addTable.IsSecondary)
+		require.Equal(t, tablepb.Checkpoint{CheckpointTs: 20, ResolvedTs: 20},
+			addTable.Checkpoint)
+	}
+	require.Len(t, r.replacements, 0)
+	require.Equal(t, 2, r.spans.Len())
+	for i := range newSpans {
+		tbl, ok := r.getReplicationSet(newSpans[i])
+		require.True(t, ok)
+		require.Equal(t, ReplicationSetStateCommit, tbl.State)
+	}
+
+	// Commit -> Replicating.
+	msgs, err = r.HandleMessage([]*schedulepb.Message{
+		tableStatusMessage("1", tablepb.TableStatus{
+			Span: newSpans[0], State: tablepb.TableStateReplicating,
+		}),
+		tableStatusMessage("2", tablepb.TableStatus{
+			Span: newSpans[1], State: tablepb.TableStateReplicating,
+		}),
+	})
+	require.Nil(t, err)
+	require.Len(t, msgs, 0)
+	for i, captureID := range []model.CaptureID{"1", "2"} {
+		tbl, _ := r.getReplicationSet(newSpans[i])
+		require.Equal(t, ReplicationSetStateReplicating, tbl.State)
+		require.Equal(t, captureID, tbl.Primary)
+	}
+	msgs, err = r.HandleTasks(nil)
+	require.Nil(t, err)
+	require.Len(t, msgs, 0)
+	require.Equal(t, 0, r.runningTasks.Len())
+}
+
+func TestReplicationManagerAbortReplaceSpans(t *testing.T) {
+	t.Parallel()
+
+	r, span, newSpans := prepareReplaceSpans(t)
+	_, err := r.HandleTasks([]*ScheduleTask{{
+		ReplaceSpans: &ReplaceSpans{
+			Spans:      []tablepb.Span{span},
+			NewSpans:   newSpans,
+			CaptureIDs: []model.CaptureID{"1", "2"},
+		},
+	}})
+	require.Nil(t, err)
+	_, err = r.HandleMessage([]*schedulepb.Message{tableStatusMessage("1",
+		tablepb.TableStatus{Span: newSpans[0], State: tablepb.TableStatePrepared},
+	)})
+	require.Nil(t, err)
+
+	// The capture of a new span is shutdown, other new spans are removed
+	// and the replaced span keeps replicating.
+	msgs, err := r.HandleCaptureChanges(nil, map[model.CaptureID][]tablepb.TableStatus{
+		"2": {},
+	}, 0)
+	require.Nil(t, err)
+	require.Len(t, msgs, 1)
+	require.EqualValues(t, &schedulepb.Message{
+		To:      "1",
+		MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: &schedulepb.DispatchTableRequest{
+			Request: &schedulepb.DispatchTableRequest_RemoveTable{
+				RemoveTable: &schedulepb.RemoveTableRequest{Span: newSpans[0]},
+			},
+		},
+	}, msgs[0])
+	require.Len(t, r.replacements, 0)
+	require.False(t, r.runningTasks.Has(span))
+	require.Equal(t, 1, r.spans.Len())
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(span).State)
+}
+
+func TestReplicationManagerInitOverlappedSpans(t *testing.T) {
+	t.Parallel()
+
+	_, span, newSpans := prepareReplaceSpans(t)
+	r := NewReplicationManager(10, model.ChangeFeedID{})
+	// The owner is switched while new spans are prepared.
+	msgs, err := r.HandleCaptureChanges(map[model.CaptureID][]tablepb.TableStatus{
+		"1": {
+			{Span: newSpans[0], State: tablepb.TableStatePrepared},
+			{Span: span, State: tablepb.TableStateReplicating},
+		},
+		"2": {{Span: newSpans[1], State: tablepb.TableStatePreparing}},
+	}, nil, 10)
+	require.Nil(t, err)
+	require.Len(t, msgs, 2)
+	removed := map[model.CaptureID]tablepb.Span{}
+	for _, msg := range msgs {
+		removed[msg.To] = msg.DispatchTableRequest.GetRemoveTable().Span
+	}
+	require.Equal(t, map[model.CaptureID]tablepb.Span{
+		"1": newSpans[0], "2": newSpans[1],
+	}, removed)
+	require.Equal(t, 1, r.spans.Len())
+	tbl, ok := r.getReplicationSet(span)
+	require.True(t, ok)
+	require.Equal(t, ReplicationSetStateReplicating, tbl.State)
+}
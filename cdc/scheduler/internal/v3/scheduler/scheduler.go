@@ -40,6 +40,8 @@ const (
 	schedulerPriorityBasic schedulerPriority = iota
 	// schedulerPriorityDrainCapture has higher priority than other schedulers.
 	schedulerPriorityDrainCapture
+	// schedulerPriorityPlacement moves tables to satisfy placement constraints.
+	schedulerPriorityPlacement
 	schedulerPriorityMoveTable
 	schedulerPriorityRebalance
 	schedulerPriorityBalance
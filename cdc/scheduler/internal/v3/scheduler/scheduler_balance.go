@@ -15,6 +15,7 @@ package scheduler
 
 import (
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/pingcap/log"
@@ -22,7 +23,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
 )
 
 var _ scheduler = &balanceScheduler{}
@@ -41,13 +44,22 @@ type balanceScheduler struct {
 	forceBalance bool
 
 	maxTaskConcurrency int
+
+	// strategy is the balance strategy, see config.BalanceStrategyXXX.
+	strategy string
+	// threshold is the tolerance ratio of the throughput strategy.
+	threshold float64
 }
 
-func newBalanceScheduler(interval time.Duration, concurrency int) *balanceScheduler {
+func newBalanceScheduler(
+	interval time.Duration, concurrency int, strategy string, threshold float64,
+) *balanceScheduler {
 	return &balanceScheduler{
 		random:               rand.New(rand.NewSource(time.Now().UnixNano())),
 		checkBalanceInterval: interval,
 		maxTaskConcurrency:   concurrency,
+		strategy:             strategy,
+		threshold:            threshold,
 	}
 }
 
@@ -77,6 +89,13 @@ func (b *balanceScheduler) Schedule(
 		}
 	}
 
+	if b.strategy == config.BalanceStrategyThroughput {
+		// Stats are collected periodically, do not force balance so that
+		// the next round is planned with the stats after the moves.
+		return buildThroughputBalanceMoveTables(
+			captures, replications, b.maxTaskConcurrency, b.threshold)
+	}
+
 	tasks := buildBalanceMoveTables(
 		b.random, captures, replications, b.maxTaskConcurrency)
 	b.forceBalance = len(tasks) != 0
@@ -98,3 +117,161 @@ func buildBalanceMoveTables(
 	}
 	return tasks
 }
+
+// spanLoad is the load of a span, it is the average of the span's shares of
+// the total event rate and the total byte rate of the changefeed.
+type spanLoad struct {
+	span tablepb.Span
+	load float64
+}
+
+// captureLoad is the load of a capture.
+type captureLoad struct {
+	id          model.CaptureID
+	load        float64
+	memoryUsage uint64
+	spans       []spanLoad
+}
+
+// buildThroughputBalanceMoveTables plans moves by the throughput of spans.
+//
+// A capture is overloaded if its load exceeds the average load by the
+// threshold. Spans are moved from overloaded captures to the least loaded
+// captures, as long as the target captures do not become overloaded and
+// their memory usage does not exceed the average memory usage by the
+// threshold. It falls back to table count balancing if no throughput is
+// reported yet.
+func buildThroughputBalanceMoveTables(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+	maxTaskConcurrency int,
+	threshold float64,
+) []*replication.ScheduleTask {
+	if len(captures) == 0 {
+		return nil
+	}
+	loads := make(map[model.CaptureID]*captureLoad, len(captures))
+	var totalMemory uint64
+	for id, capture := range captures {
+		loads[id] = &captureLoad{id: id, memoryUsage: capture.MemoryUsage}
+		totalMemory += capture.MemoryUsage
+	}
+
+	var totalEventRate, totalByteRate uint64
+	allReplicating := true
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if rep.State != replication.ReplicationSetStateReplicating {
+			allReplicating = false
+			return false
+		}
+		totalEventRate += rep.Stats.EventRate
+		totalByteRate += rep.Stats.ByteRate
+		return true
+	})
+	// Tables are being moved, the loads of captures are not stable yet.
+	if !allReplicating {
+		return nil
+	}
+	if totalEventRate == 0 && totalByteRate == 0 {
+		return buildBalanceMoveTables(nil, captures, replications, maxTaskConcurrency)
+	}
+
+	// Only rates which are reported take part in the loads.
+	rates := 0
+	if totalEventRate != 0 {
+		rates++
+	}
+	if totalByteRate != 0 {
+		rates++
+	}
+	share := func(rate, total uint64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(rate) / float64(total) / float64(rates)
+	}
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		capture, ok := loads[rep.Primary]
+		if !ok {
+			return true
+		}
+		load := share(rep.Stats.EventRate, totalEventRate) +
+			share(rep.Stats.ByteRate, totalByteRate)
+		capture.load += load
+		capture.spans = append(capture.spans, spanLoad{span: span, load: load})
+		return true
+	})
+
+	upperLoad := (1 + threshold) / float64(len(captures))
+	upperMemory := (1 + threshold) * float64(totalMemory) / float64(len(captures))
+	sorted := make([]*captureLoad, 0, len(loads))
+	for _, capture := range loads {
+		sorted = append(sorted, capture)
+		sort.Slice(capture.spans, func(i, j int) bool {
+			if capture.spans[i].load != capture.spans[j].load {
+				return capture.spans[i].load > capture.spans[j].load
+			}
+			return capture.spans[i].span.Less(&capture.spans[j].span)
+		})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].load != sorted[j].load {
+			return sorted[i].load > sorted[j].load
+		}
+		return sorted[i].id < sorted[j].id
+	})
+
+	tasks := make([]*replication.ScheduleTask, 0)
+	for _, source := range sorted {
+		if source.load <= upperLoad {
+			break
+		}
+		for _, s := range source.spans {
+			if len(tasks) >= maxTaskConcurrency {
+				return tasks
+			}
+			if source.load <= upperLoad {
+				break
+			}
+			var target *captureLoad
+			for _, candidate := range sorted {
+				if candidate == source ||
+					captures[candidate.id].State == member.CaptureStateStopping {
+					continue
+				}
+				if totalMemory != 0 &&
+					float64(candidate.memoryUsage) > upperMemory {
+					continue
+				}
+				// Moving the span must reduce the load of the busier capture
+				// and must not overload the target capture.
+				if candidate.load+s.load >= source.load ||
+					candidate.load+s.load > upperLoad {
+					continue
+				}
+				if target == nil || candidate.load < target.load ||
+					(candidate.load == target.load &&
+						candidate.memoryUsage < target.memoryUsage) {
+					target = candidate
+				}
+			}
+			if target == nil {
+				continue
+			}
+			source.load -= s.load
+			target.load += s.load
+			tasks = append(tasks, &replication.ScheduleTask{
+				MoveTable: &replication.MoveTable{
+					Span:        s.span,
+					DestCapture: target.id,
+				},
+			})
+			log.Info("schedulerv3: plan to move table by throughput",
+				zap.String("source", source.id),
+				zap.String("target", target.id),
+				zap.Stringer("span", &s.span),
+				zap.Float64("load", s.load))
+		}
+	}
+	return tasks
+}
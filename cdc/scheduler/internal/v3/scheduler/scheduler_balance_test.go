@@ -18,8 +18,10 @@ import (
 	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
@@ -27,7 +29,7 @@ import (
 func TestSchedulerBalanceCaptureOnline(t *testing.T) {
 	t.Parallel()
 
-	sched := newBalanceScheduler(time.Duration(0), 3)
+	sched := newBalanceScheduler(time.Duration(0), 3, config.BalanceStrategyTableCount, 0)
 	sched.random = nil
 
 	// New capture "b" online
@@ -75,7 +77,7 @@ func TestSchedulerBalanceCaptureOnline(t *testing.T) {
 func TestSchedulerBalanceTaskLimit(t *testing.T) {
 	t.Parallel()
 
-	sched := newBalanceScheduler(time.Duration(0), 2)
+	sched := newBalanceScheduler(time.Duration(0), 2, config.BalanceStrategyTableCount, 0)
 	sched.random = nil
 
 	// New capture "b" online
@@ -90,7 +92,94 @@ func TestSchedulerBalanceTaskLimit(t *testing.T) {
 	tasks := sched.Schedule(0, currentTables, captures, replications)
 	require.Len(t, tasks, 2)
 
-	sched = newBalanceScheduler(time.Duration(0), 1)
+	sched = newBalanceScheduler(time.Duration(0), 1, config.BalanceStrategyTableCount, 0)
 	tasks = sched.Schedule(0, currentTables, captures, replications)
 	require.Len(t, tasks, 1)
 }
+
+func TestSchedulerBalanceThroughput(t *testing.T) {
+	t.Parallel()
+
+	sched := newBalanceScheduler(
+		time.Duration(0), 3, config.BalanceStrategyThroughput, 0.2)
+	newRep := func(primary model.CaptureID, eventRate, byteRate uint64) *replication.ReplicationSet {
+		return &replication.ReplicationSet{
+			State:   replication.ReplicationSetStateReplicating,
+			Primary: primary,
+			Stats:   tablepb.Stats{EventRate: eventRate, ByteRate: byteRate},
+		}
+	}
+
+	// Two hot tables are replicated by capture "a", count-based balancing
+	// considers the captures balanced.
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentTables := spanz.ArrayToSpan([]model.TableID{1, 2, 3, 4})
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: newRep("a", 450, 4500),
+		2: newRep("a", 450, 4500),
+		3: newRep("b", 50, 500),
+		4: newRep("b", 50, 500),
+	})
+	tasks := sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, model.TableID(1), tasks[0].MoveTable.Span.TableID)
+	require.Equal(t, "b", tasks[0].MoveTable.DestCapture)
+	require.False(t, sched.forceBalance)
+
+	// The loads are within the threshold, no table is moved.
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: newRep("a", 450, 4500),
+		2: newRep("a", 100, 1000),
+		3: newRep("b", 400, 4000),
+		4: newRep("b", 50, 500),
+	})
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+
+	// A table which is too hot is not moved, other tables are moved away.
+	captures = map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}, "c": {}}
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: newRep("a", 900, 9000),
+		2: newRep("a", 50, 500),
+		3: newRep("b", 25, 250),
+		4: newRep("c", 25, 250),
+	})
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, model.TableID(2), tasks[0].MoveTable.Span.TableID)
+	require.Equal(t, "b", tasks[0].MoveTable.DestCapture)
+
+	// Capture "c" uses too much memory, it is not chosen as the target
+	// even though it has the lowest load.
+	sched.threshold = 0.3
+	captures = map[model.CaptureID]*member.CaptureStatus{
+		"a": {MemoryUsage: 1}, "b": {MemoryUsage: 1}, "c": {MemoryUsage: 10},
+	}
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: newRep("a", 500, 0),
+		2: newRep("a", 300, 0),
+		3: newRep("b", 120, 0),
+		4: newRep("c", 80, 0),
+	})
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, model.TableID(2), tasks[0].MoveTable.Span.TableID)
+	require.Equal(t, "b", tasks[0].MoveTable.DestCapture)
+
+	// Some tables are being moved, wait for them.
+	replications.GetV(tablepb.Span{TableID: 3}).State =
+		replication.ReplicationSetStatePrepare
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+
+	// Fall back to count-based balancing if there is no throughput.
+	captures = map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: newRep("a", 0, 0),
+		2: newRep("a", 0, 0),
+		3: newRep("a", 0, 0),
+		4: newRep("a", 0, 0),
+	})
+	tasks = sched.Schedule(0, currentTables, captures, replications)
+	require.Len(t, tasks, 2)
+}
@@ -16,6 +16,7 @@ package scheduler
 import (
 	"math"
 	"sync"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -34,6 +35,9 @@ var _ scheduler = &drainCaptureScheduler{}
 type drainCaptureScheduler struct {
 	mu     sync.Mutex
 	target model.CaptureID
+	// deadline is the time after which remaining tables of the target
+	// capture are force-moved, zero means no deadline.
+	deadline time.Time
 
 	changefeedID       model.ChangeFeedID
 	maxTaskConcurrency int
@@ -59,17 +63,63 @@ func (d *drainCaptureScheduler) getTarget() model.CaptureID {
 	return d.target
 }
 
-func (d *drainCaptureScheduler) setTarget(target model.CaptureID) bool {
+func (d *drainCaptureScheduler) getDeadline() time.Time {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	return d.deadline
+}
+
+func (d *drainCaptureScheduler) setTarget(
+	target model.CaptureID, deadline time.Time,
+) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.target == target {
+		// Allow updating the deadline of a draining capture.
+		if !deadline.IsZero() {
+			d.deadline = deadline
+		}
+		return true
+	}
 	if d.target != captureIDNotDraining {
 		return false
 	}
 
 	d.target = target
+	d.deadline = deadline
+	return true
+}
+
+// cancel cancels draining the target capture, it returns false if the
+// capture is not draining.
+func (d *drainCaptureScheduler) cancel(target model.CaptureID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.target != target {
+		return false
+	}
+	d.target = captureIDNotDraining
+	d.deadline = time.Time{}
 	return true
 }
 
+// forcing returns true if the deadline of draining has passed.
+func (d *drainCaptureScheduler) forcing() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.forcingLocked()
+}
+
+func (d *drainCaptureScheduler) forcingLocked() bool {
+	return d.target != captureIDNotDraining &&
+		!d.deadline.IsZero() && time.Now().After(d.deadline)
+}
+
+func (d *drainCaptureScheduler) reset() {
+	d.target = captureIDNotDraining
+	d.deadline = time.Time{}
+}
+
 func (d *drainCaptureScheduler) Schedule(
 	_ model.Ts,
 	_ []tablepb.Span,
@@ -89,7 +139,10 @@ func (d *drainCaptureScheduler) Schedule(
 				continue
 			}
 			if capture.State == member.CaptureStateStopping {
+				// The deadline is reported by the capture, so that it's
+				// kept after the owner is changed.
 				d.target = id
+				d.deadline = capture.DrainDeadline
 				break
 			}
 		}
@@ -101,7 +154,8 @@ func (d *drainCaptureScheduler) Schedule(
 		log.Info("schedulerv3: drain a stopping capture",
 			zap.String("namespace", d.changefeedID.Namespace),
 			zap.String("changefeed", d.changefeedID.ID),
-			zap.String("captureID", d.target))
+			zap.String("captureID", d.target),
+			zap.Time("deadline", d.deadline))
 	}
 
 	// Currently, the workload is the number of tables in a capture.
@@ -120,16 +174,31 @@ func (d *drainCaptureScheduler) Schedule(
 			zap.String("namespace", d.changefeedID.Namespace),
 			zap.String("changefeed", d.changefeedID.ID),
 			zap.String("target", d.target), zap.Any("captures", captures))
-		d.target = captureIDNotDraining
+		d.reset()
 		return nil
 	}
 
 	maxTaskConcurrency := d.maxTaskConcurrency
+	// After the deadline, all remaining tables are moved at once, and
+	// tables which are not replicating do not block draining.
+	force := d.forcingLocked()
 	// victimSpans record tables should be moved out from the target capture
 	victimSpans := make([]tablepb.Span, 0, maxTaskConcurrency)
+	// removedSpans record tables which are not replicating, they are
+	// force-removed from the target capture after the deadline.
+	removedSpans := make([]tablepb.Span, 0)
 	skipDrain := false
 	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
 		if rep.State != replication.ReplicationSetStateReplicating {
+			if force {
+				// They may be stuck, e.g. the target capture never prepares
+				// the table, so they are removed from the target capture
+				// whatever the state is.
+				if _, ok := rep.Captures[d.target]; ok {
+					removedSpans = append(removedSpans, span)
+				}
+				return true
+			}
 			// only drain the target capture if all tables is replicating,
 			log.Debug("schedulerv3: drain capture scheduler skip this tick,"+
 				"not all table is replicating",
@@ -142,13 +211,13 @@ func (d *drainCaptureScheduler) Schedule(
 		}
 
 		if rep.Primary == d.target {
-			if len(victimSpans) < maxTaskConcurrency {
+			if len(victimSpans) < maxTaskConcurrency || force {
 				victimSpans = append(victimSpans, span)
 			}
 		}
 
 		// only calculate workload of other captures not the drain target.
-		if rep.Primary != d.target {
+		if _, ok := captureWorkload[rep.Primary]; ok {
 			captureWorkload[rep.Primary]++
 		}
 		return true
@@ -161,17 +230,23 @@ func (d *drainCaptureScheduler) Schedule(
 	// 1. the target capture has no table at the beginning
 	// 2. all tables moved from the target capture
 	// 3. the target capture cannot be found in the latest captures
-	if len(victimSpans) == 0 {
+	if len(victimSpans) == 0 && len(removedSpans) == 0 {
+		// Keep the target until the capture knows it's stopping, so that
+		// it's still drained after the owner is changed.
+		if capture, ok := captures[d.target]; ok &&
+			capture.State != member.CaptureStateStopping {
+			return nil
+		}
 		log.Info("schedulerv3: drain capture scheduler finished, since no table",
 			zap.String("namespace", d.changefeedID.Namespace),
 			zap.String("changefeed", d.changefeedID.ID),
 			zap.String("target", d.target))
-		d.target = captureIDNotDraining
+		d.reset()
 		return nil
 	}
 
 	// For each victim table, find the target for it
-	result := make([]*replication.ScheduleTask, 0, maxTaskConcurrency)
+	moves := make([]replication.MoveTable, 0, len(victimSpans))
 	for _, span := range victimSpans {
 		target := ""
 		minWorkload := math.MaxInt64
@@ -189,17 +264,47 @@ func (d *drainCaptureScheduler) Schedule(
 				zap.Any("workload", captureWorkload))
 		}
 
-		result = append(result, &replication.ScheduleTask{
-			MoveTable: &replication.MoveTable{
-				Span:        span,
-				DestCapture: target,
-			},
-			Accept: (replication.Callback)(nil), // No need for accept callback here.
+		moves = append(moves, replication.MoveTable{
+			Span:        span,
+			DestCapture: target,
 		})
 
 		// Increase target workload to make sure tables are evenly distributed.
 		captureWorkload[target]++
 	}
 
+	if force {
+		removes := make([]replication.RemoveTable, 0, len(removedSpans))
+		for _, span := range removedSpans {
+			removes = append(removes, replication.RemoveTable{
+				Span:      span,
+				CaptureID: d.target,
+				Force:     true,
+			})
+		}
+		// Burst balance is not limited by the max task concurrency.
+		log.Warn("schedulerv3: drain capture deadline exceeded, "+
+			"force move remaining tables",
+			zap.String("namespace", d.changefeedID.Namespace),
+			zap.String("changefeed", d.changefeedID.ID),
+			zap.String("target", d.target),
+			zap.Time("deadline", d.deadline),
+			zap.Int("tableCount", len(moves)),
+			zap.Int("removedTableCount", len(removes)))
+		return []*replication.ScheduleTask{{
+			BurstBalance: &replication.BurstBalance{
+				MoveTables:   moves,
+				RemoveTables: removes,
+			},
+		}}
+	}
+
+	result := make([]*replication.ScheduleTask, 0, len(moves))
+	for i := range moves {
+		result = append(result, &replication.ScheduleTask{
+			MoveTable: &moves[i],
+			Accept:    (replication.Callback)(nil), // No need for accept callback here.
+		})
+	}
 	return result
 }
@@ -15,6 +15,7 @@ package scheduler
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -38,7 +39,7 @@ func TestDrainCapture(t *testing.T) {
 	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
 	require.Len(t, tasks, 0)
 
-	ok := scheduler.setTarget("a")
+	ok := scheduler.setTarget("a", time.Time{})
 	require.True(t, ok)
 
 	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
@@ -47,7 +48,7 @@ func TestDrainCapture(t *testing.T) {
 	require.Equal(t, captureIDNotDraining, scheduler.target)
 
 	captures["a"] = &member.CaptureStatus{}
-	ok = scheduler.setTarget("b")
+	ok = scheduler.setTarget("b", time.Time{})
 	require.True(t, ok)
 
 	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
@@ -102,7 +103,7 @@ func TestDrainCapture(t *testing.T) {
 		},
 	})
 
-	ok = scheduler.setTarget("a")
+	ok = scheduler.setTarget("a", time.Time{})
 	require.True(t, ok)
 	// not all table is replicating, skip this tick.
 	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
@@ -123,7 +124,7 @@ func TestDrainCapture(t *testing.T) {
 	require.Len(t, tasks, 3)
 
 	scheduler = newDrainCaptureScheduler(1, model.ChangeFeedID{})
-	require.True(t, scheduler.setTarget("a"))
+	require.True(t, scheduler.setTarget("a", time.Time{}))
 	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
 	require.Equal(t, "a", scheduler.target)
 	require.Len(t, tasks, 1)
@@ -187,7 +188,7 @@ func TestDrainImbalanceCluster(t *testing.T) {
 		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
 	})
 	scheduler := newDrainCaptureScheduler(10, model.ChangeFeedID{})
-	scheduler.setTarget("a")
+	scheduler.setTarget("a", time.Time{})
 	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
 	require.Len(t, tasks, 2)
 	require.EqualValues(t, "a", scheduler.getTarget())
@@ -210,7 +211,7 @@ func TestDrainEvenlyDistributedTables(t *testing.T) {
 		6: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
 	})
 	scheduler := newDrainCaptureScheduler(10, model.ChangeFeedID{})
-	scheduler.setTarget("a")
+	scheduler.setTarget("a", time.Time{})
 	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
 	require.Len(t, tasks, 3)
 	taskMap := make(map[model.CaptureID]int)
@@ -220,3 +221,105 @@ func TestDrainEvenlyDistributedTables(t *testing.T) {
 	require.Equal(t, 1, taskMap["b"])
 	require.Equal(t, 2, taskMap["c"])
 }
+
+func TestDrainCaptureDeadlineAndCancel(t *testing.T) {
+	t.Parallel()
+
+	var checkpointTs model.Ts
+	currentTables := make([]tablepb.Span, 0)
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateStopping},
+		"b": {IsOwner: true, State: member.CaptureStateInitialized},
+	}
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {
+			State: replication.ReplicationSetStatePrepare, Primary: "a",
+			Captures: map[model.CaptureID]replication.Role{
+				"a": replication.RolePrimary,
+			},
+		},
+		4: {
+			State: replication.ReplicationSetStatePrepare, Primary: "b",
+			Captures: map[model.CaptureID]replication.Role{
+				"b": replication.RolePrimary,
+			},
+		},
+	})
+	scheduler := newDrainCaptureScheduler(1, model.ChangeFeedID{})
+	require.True(t, scheduler.setTarget("a", time.Time{}))
+	require.False(t, scheduler.forcing())
+	// Not all tables are replicating, skip this tick.
+	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+
+	// Setting the same target again updates the deadline.
+	require.True(t, scheduler.setTarget("a", time.Now().Add(-time.Second)))
+	require.False(t, scheduler.setTarget("b", time.Time{}))
+	require.True(t, scheduler.forcing())
+	// After the deadline, all replicating tables are moved at once, and
+	// other tables are removed from the target capture.
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.MoveTables, 2)
+	for _, move := range tasks[0].BurstBalance.MoveTables {
+		require.Equal(t, "b", move.DestCapture)
+	}
+	require.Equal(t, []replication.RemoveTable{{
+		Span: tablepb.Span{TableID: 3}, CaptureID: "a", Force: true,
+	}}, tasks[0].BurstBalance.RemoveTables)
+
+	// Only removing tables which are not replicating does not finish draining.
+	replications.Delete(tablepb.Span{TableID: 1})
+	replications.Delete(tablepb.Span{TableID: 2})
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.MoveTables, 0)
+	require.Len(t, tasks[0].BurstBalance.RemoveTables, 1)
+	require.Equal(t, "a", scheduler.getTarget())
+
+	// Cancel draining.
+	require.False(t, scheduler.cancel("b"))
+	require.True(t, scheduler.cancel("a"))
+	require.Equal(t, captureIDNotDraining, scheduler.getTarget())
+	require.False(t, scheduler.forcing())
+	captures["a"].State = member.CaptureStateInitialized
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.Equal(t, captureIDNotDraining, scheduler.getTarget())
+}
+
+func TestDrainCaptureKeptAfterOwnerChanged(t *testing.T) {
+	t.Parallel()
+
+	var checkpointTs model.Ts
+	currentTables := make([]tablepb.Span, 0)
+	deadline := time.Now().Add(time.Hour)
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized},
+		"b": {State: member.CaptureStateStopping, DrainDeadline: deadline},
+	}
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	})
+	// A stopping capture is drained with the deadline it reports.
+	scheduler := newDrainCaptureScheduler(10, model.ChangeFeedID{})
+	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.Equal(t, "b", scheduler.getTarget())
+	require.Equal(t, deadline, scheduler.getDeadline())
+
+	// A capture without tables is drained until it knows it's stopping.
+	replications = mapToSpanMap(map[model.TableID]*replication.ReplicationSet{})
+	captures["b"] = &member.CaptureStatus{State: member.CaptureStateInitialized}
+	scheduler = newDrainCaptureScheduler(10, model.ChangeFeedID{})
+	require.True(t, scheduler.setTarget("b", time.Time{}))
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.Equal(t, "b", scheduler.getTarget())
+	captures["b"].State = member.CaptureStateStopping
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.Equal(t, captureIDNotDraining, scheduler.getTarget())
+}
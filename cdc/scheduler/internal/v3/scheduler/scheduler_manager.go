@@ -54,8 +54,11 @@ func NewSchedulerManager(
 		cfg.AddTableBatchSize, changefeedID)
 	sm.schedulers[schedulerPriorityDrainCapture] = newDrainCaptureScheduler(
 		cfg.MaxTaskConcurrency, changefeedID)
+	sm.schedulers[schedulerPriorityPlacement] = newPlacementScheduler(
+		cfg.ChangefeedSettings, cfg.MaxTaskConcurrency, changefeedID)
 	sm.schedulers[schedulerPriorityBalance] = newBalanceScheduler(
-		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency)
+		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency,
+		cfg.BalanceStrategy, cfg.BalanceThreshold)
 	sm.schedulers[schedulerPriorityMoveTable] = newMoveTableScheduler(changefeedID)
 	sm.schedulers[schedulerPriorityRebalance] = newRebalanceScheduler(changefeedID)
 
@@ -70,17 +73,33 @@ func (sm *Manager) Schedule(
 	replications *spanz.BtreeMap[*replication.ReplicationSet],
 	runTasking *spanz.BtreeMap[*replication.ScheduleTask],
 ) []*replication.ScheduleTask {
+	placement := sm.schedulers[schedulerPriorityPlacement].(*placementScheduler)
+	// Only captures which match the placement constraints of the changefeed
+	// can replicate tables, the placement scheduler moves tables off others.
+	captures := placement.filterCaptures(aliveCaptures, replications)
+	drain := sm.schedulers[schedulerPriorityDrainCapture].(*drainCaptureScheduler)
 	for sid, scheduler := range sm.schedulers {
 		// Basic scheduler bypasses max task check, because it handles the most
 		// critical scheduling, e.g. add table via CREATE TABLE DDL.
-		if sid != int(schedulerPriorityBasic) {
+		// Drain capture scheduler bypasses it after the drain deadline.
+		if sid != int(schedulerPriorityBasic) &&
+			!(sid == int(schedulerPriorityDrainCapture) && drain.forcing()) {
 			if runTasking.Len() >= sm.maxTaskConcurrency {
 				// Do not generate more scheduling tasks if there are too many
 				// running tasks.
 				return nil
 			}
 		}
-		tasks := scheduler.Schedule(checkpointTs, currentSpans, aliveCaptures, replications)
+		var tasks []*replication.ScheduleTask
+		switch schedulerPriority(sid) {
+		case schedulerPriorityPlacement:
+			tasks = scheduler.Schedule(checkpointTs, currentSpans, aliveCaptures, replications)
+		case schedulerPriorityBalance, schedulerPriorityRebalance:
+			tasks = scheduler.Schedule(checkpointTs, currentSpans, captures, replications)
+			tasks = placement.filterTasks(tasks, captures, replications)
+		default:
+			tasks = scheduler.Schedule(checkpointTs, currentSpans, captures, replications)
+		}
 		for _, t := range tasks {
 			name := struct {
 				scheduler, task string
@@ -100,6 +119,15 @@ func (sm *Manager) Schedule(
 	return nil
 }
 
+// CheckPlacement returns an error if tables can not be scheduled due to the
+// placement constraints of the changefeed.
+func (sm *Manager) CheckPlacement(
+	aliveCaptures map[model.CaptureID]*member.CaptureStatus,
+) error {
+	placement := sm.schedulers[schedulerPriorityPlacement].(*placementScheduler)
+	return placement.checkCaptures(aliveCaptures)
+}
+
 // MoveTable moves a table to the target capture.
 func (sm *Manager) MoveTable(span tablepb.Span, target model.CaptureID) {
 	scheduler := sm.schedulers[schedulerPriorityMoveTable]
@@ -132,8 +160,9 @@ func (sm *Manager) Rebalance() {
 	atomic.StoreInt32(&rebalanceScheduler.rebalance, 1)
 }
 
-// DrainCapture drains all tables in the target capture.
-func (sm *Manager) DrainCapture(target model.CaptureID) bool {
+// DrainCapture drains all tables in the target capture, remaining tables
+// are force-moved after the deadline if it is not zero.
+func (sm *Manager) DrainCapture(target model.CaptureID, deadline time.Time) bool {
 	scheduler := sm.schedulers[schedulerPriorityDrainCapture]
 	drainCaptureScheduler, ok := scheduler.(*drainCaptureScheduler)
 	if !ok {
@@ -142,7 +171,12 @@ func (sm *Manager) DrainCapture(target model.CaptureID) bool {
 			zap.String("changefeed", sm.changefeedID.ID))
 	}
 
-	return drainCaptureScheduler.setTarget(target)
+	return drainCaptureScheduler.setTarget(target, deadline)
+}
+
+// CancelDrainCapture cancels draining the target capture.
+func (sm *Manager) CancelDrainCapture(target model.CaptureID) bool {
+	return sm.schedulers[schedulerPriorityDrainCapture].(*drainCaptureScheduler).cancel(target)
 }
 
 // DrainingTarget returns a capture id that is currently been draining.
@@ -150,6 +184,11 @@ func (sm *Manager) DrainingTarget() model.CaptureID {
 	return sm.schedulers[schedulerPriorityDrainCapture].(*drainCaptureScheduler).getTarget()
 }
 
+// DrainDeadline returns the deadline of draining the target capture.
+func (sm *Manager) DrainDeadline() time.Time {
+	return sm.schedulers[schedulerPriorityDrainCapture].(*drainCaptureScheduler).getDeadline()
+}
+
 // CollectMetrics collects metrics.
 func (sm *Manager) CollectMetrics() {
 	cf := sm.changefeedID
@@ -0,0 +1,450 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+var _ scheduler = &placementScheduler{}
+
+// placementScheduler moves tables to satisfy the placement constraints of
+// the changefeed:
+//  1. tables are moved off captures which do not match the capture labels,
+//  2. all spans are moved to one capture if the changefeed is pinned to a
+//     single capture,
+//  3. spans of a table are spread across captures with different values of
+//     the spread label.
+type placementScheduler struct {
+	cfg                *config.ChangefeedSchedulerConfig
+	maxTaskConcurrency int
+	changefeedID       model.ChangeFeedID
+}
+
+func newPlacementScheduler(
+	cfg *config.ChangefeedSchedulerConfig, concurrency int,
+	changefeed model.ChangeFeedID,
+) *placementScheduler {
+	if cfg == nil {
+		cfg = &config.ChangefeedSchedulerConfig{}
+	}
+	return &placementScheduler{
+		cfg:                cfg,
+		maxTaskConcurrency: concurrency,
+		changefeedID:       changefeed,
+	}
+}
+
+func (p *placementScheduler) Name() string {
+	return "placement-scheduler"
+}
+
+// enabled returns true if the changefeed has placement constraints.
+func (p *placementScheduler) enabled() bool {
+	return len(p.cfg.CaptureLabels) != 0 || p.cfg.SpreadLabel != "" ||
+		p.cfg.SingleCapture
+}
+
+// filterCaptures returns captures which match the capture labels. If the
+// changefeed is pinned to a single capture, only the pinned capture and
+// stopping captures are returned, so that tables of stopping captures are
+// still drained.
+func (p *placementScheduler) filterCaptures(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) map[model.CaptureID]*member.CaptureStatus {
+	if len(p.cfg.CaptureLabels) == 0 && !p.cfg.SingleCapture {
+		return captures
+	}
+	res := make(map[model.CaptureID]*member.CaptureStatus, len(captures))
+	for id, capture := range captures {
+		if p.cfg.MatchCaptureLabels(capture.Labels) {
+			res[id] = capture
+		}
+	}
+	if !p.cfg.SingleCapture {
+		return res
+	}
+	pinned, ok := pinnedCapture(res, replications)
+	if !ok {
+		return res
+	}
+	for id, capture := range res {
+		if id != pinned && capture.State != member.CaptureStateStopping {
+			delete(res, id)
+		}
+	}
+	return res
+}
+
+// pinnedCapture returns the capture which replicates all spans of a
+// changefeed pinned to a single capture. It's the capture which is not
+// stopping and has the most spans, so that a new capture does not take
+// over tables, ties are broken by the capture ID.
+func pinnedCapture(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) (model.CaptureID, bool) {
+	spans := make(map[model.CaptureID]int, len(captures))
+	for id, capture := range captures {
+		if capture.State != member.CaptureStateStopping {
+			spans[id] = 0
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+	replications.Ascend(func(_ tablepb.Span, rep *replication.ReplicationSet) bool {
+		if _, ok := spans[rep.Primary]; ok {
+			spans[rep.Primary]++
+		}
+		return true
+	})
+	res, most := "", -1
+	for id, n := range spans {
+		if n > most || (n == most && id < res) {
+			res, most = id, n
+		}
+	}
+	return res, true
+}
+
+// checkCaptures returns an error if no alive capture matches the capture
+// labels, tables of the changefeed can not be scheduled in this case.
+func (p *placementScheduler) checkCaptures(
+	captures map[model.CaptureID]*member.CaptureStatus,
+) error {
+	if len(p.cfg.CaptureLabels) == 0 {
+		return nil
+	}
+	for _, capture := range captures {
+		if capture.State != member.CaptureStateStopping &&
+			p.cfg.MatchCaptureLabels(capture.Labels) {
+			return nil
+		}
+	}
+	log.Warn("schedulerv3: no capture matches the capture labels",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Any("captureLabels", p.cfg.CaptureLabels))
+	return errors.ErrNoCaptureMatchesLabels.GenWithStackByArgs(p.cfg.CaptureLabels)
+}
+
+func (p *placementScheduler) Schedule(
+	_ model.Ts,
+	_ []tablepb.Span,
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) []*replication.ScheduleTask {
+	if !p.enabled() {
+		return nil
+	}
+	targets := p.filterCaptures(captures, replications)
+	state := newPlacementState(p.cfg.SpreadLabel, captures,
+		func(id model.CaptureID, capture *member.CaptureStatus) bool {
+			_, ok := targets[id]
+			return ok && capture.State != member.CaptureStateStopping
+		})
+	if len(state.workload) == 0 {
+		// It's reported by checkCaptures.
+		return nil
+	}
+	// Only schedule when all tables are replicating, so that the placement
+	// is computed from a stable state.
+	if !state.build(replications) {
+		return nil
+	}
+
+	tasks := make([]*replication.ScheduleTask, 0)
+	// A span is moved at most once in a round, the second move would be
+	// rejected by the replication manager.
+	moved := spanz.NewHashMap[struct{}]()
+	move := func(span tablepb.Span, target model.CaptureID) {
+		moved.ReplaceOrInsert(span, struct{}{})
+		state.move(span, target)
+		tasks = append(tasks, &replication.ScheduleTask{
+			MoveTable: &replication.MoveTable{Span: span, DestCapture: target},
+		})
+	}
+
+	// Move tables off captures which do not match the capture labels.
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if len(tasks) >= p.maxTaskConcurrency {
+			return false
+		}
+		if state.isTarget(rep.Primary) {
+			return true
+		}
+		value := ""
+		if p.cfg.SpreadLabel != "" {
+			value = state.minValue(span.TableID)
+		}
+		move(span, state.leastLoaded(value))
+		return true
+	})
+	if p.cfg.SpreadLabel == "" {
+		return tasks
+	}
+
+	// Spread spans of each table across values of the spread label.
+	for _, tableID := range state.tableIDs() {
+		for len(tasks) < p.maxTaskConcurrency {
+			maxValue, minValue := state.maxValue(tableID), state.minValue(tableID)
+			counts := state.counts(tableID)
+			if counts[maxValue]-counts[minValue] <= 1 {
+				break
+			}
+			span, ok := state.spanOf(tableID, maxValue, moved)
+			if !ok {
+				break
+			}
+			move(span, state.leastLoaded(minValue))
+		}
+	}
+	return tasks
+}
+
+// filterTasks drops moves of other schedulers which make spans of a table
+// less spread, so that schedulers do not move tables back and forth.
+func (p *placementScheduler) filterTasks(
+	tasks []*replication.ScheduleTask,
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) []*replication.ScheduleTask {
+	if p.cfg.SpreadLabel == "" || len(tasks) == 0 {
+		return tasks
+	}
+	// Captures are filtered by the capture labels already.
+	state := newPlacementState(p.cfg.SpreadLabel, captures,
+		func(model.CaptureID, *member.CaptureStatus) bool { return true })
+	state.build(replications)
+	allow := func(m *replication.MoveTable) bool {
+		rep, ok := replications.Get(m.Span)
+		if !ok || !state.isTarget(rep.Primary) {
+			return true
+		}
+		source, target := state.value(rep.Primary), state.value(m.DestCapture)
+		counts := state.counts(m.Span.TableID)
+		if source != target && counts[target] >= counts[source] {
+			log.Info("schedulerv3: ignore the move which makes the table less spread",
+				zap.String("namespace", p.changefeedID.Namespace),
+				zap.String("changefeed", p.changefeedID.ID),
+				zap.Stringer("span", &m.Span),
+				zap.String("target", m.DestCapture))
+			return false
+		}
+		state.move(m.Span, m.DestCapture)
+		return true
+	}
+
+	res := make([]*replication.ScheduleTask, 0, len(tasks))
+	for _, task := range tasks {
+		switch {
+		case task.MoveTable != nil:
+			if allow(task.MoveTable) {
+				res = append(res, task)
+			}
+		case task.BurstBalance != nil:
+			moves := make([]replication.MoveTable, 0, len(task.BurstBalance.MoveTables))
+			for i := range task.BurstBalance.MoveTables {
+				if allow(&task.BurstBalance.MoveTables[i]) {
+					moves = append(moves, task.BurstBalance.MoveTables[i])
+				}
+			}
+			// Keep the task even if all moves are dropped, so that it is
+			// still accepted.
+			task.BurstBalance.MoveTables = moves
+			res = append(res, task)
+		default:
+			res = append(res, task)
+		}
+	}
+	return res
+}
+
+// placedSpan is a span and the capture which replicates it.
+type placedSpan struct {
+	span    tablepb.Span
+	capture model.CaptureID
+}
+
+// placementState tracks where spans are placed.
+type placementState struct {
+	spreadLabel string
+	captures    map[model.CaptureID]*member.CaptureStatus
+	// workload is the number of spans of each target capture.
+	workload map[model.CaptureID]int
+	// tables is the placed spans of each table.
+	tables map[model.TableID][]*placedSpan
+}
+
+func newPlacementState(
+	spreadLabel string, captures map[model.CaptureID]*member.CaptureStatus,
+	isTarget func(model.CaptureID, *member.CaptureStatus) bool,
+) *placementState {
+	s := &placementState{
+		spreadLabel: spreadLabel,
+		captures:    captures,
+		workload:    make(map[model.CaptureID]int),
+		tables:      make(map[model.TableID][]*placedSpan),
+	}
+	for id, capture := range captures {
+		if isTarget(id, capture) {
+			s.workload[id] = 0
+		}
+	}
+	return s
+}
+
+// build records the captures of spans, it returns false if some tables
+// are not replicating.
+func (s *placementState) build(
+	replications *spanz.BtreeMap[*replication.ReplicationSet],
+) bool {
+	allReplicating := true
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if rep.State != replication.ReplicationSetStateReplicating {
+			allReplicating = false
+			return false
+		}
+		if _, ok := s.workload[rep.Primary]; ok {
+			s.workload[rep.Primary]++
+		}
+		s.tables[span.TableID] = append(s.tables[span.TableID],
+			&placedSpan{span: span, capture: rep.Primary})
+		return true
+	})
+	return allReplicating
+}
+
+func (s *placementState) isTarget(id model.CaptureID) bool {
+	_, ok := s.workload[id]
+	return ok
+}
+
+func (s *placementState) move(span tablepb.Span, target model.CaptureID) {
+	for _, placed := range s.tables[span.TableID] {
+		if placed.span.Eq(&span) {
+			if s.isTarget(placed.capture) {
+				s.workload[placed.capture]--
+			}
+			placed.capture = target
+			break
+		}
+	}
+	if s.isTarget(target) {
+		s.workload[target]++
+	}
+}
+
+// value returns the value of the spread label of the capture.
+func (s *placementState) value(id model.CaptureID) string {
+	if capture, ok := s.captures[id]; ok {
+		return capture.Labels[s.spreadLabel]
+	}
+	return ""
+}
+
+// counts returns the number of spans of the table in each value of the
+// spread label, values of all target captures are included.
+func (s *placementState) counts(tableID model.TableID) map[string]int {
+	counts := make(map[string]int)
+	for id := range s.workload {
+		counts[s.value(id)] = 0
+	}
+	for _, placed := range s.tables[tableID] {
+		if s.isTarget(placed.capture) {
+			counts[s.value(placed.capture)]++
+		}
+	}
+	return counts
+}
+
+func (s *placementState) minValue(tableID model.TableID) string {
+	return s.pickValue(tableID, func(a, b int) bool { return a < b })
+}
+
+func (s *placementState) maxValue(tableID model.TableID) string {
+	return s.pickValue(tableID, func(a, b int) bool { return a > b })
+}
+
+func (s *placementState) pickValue(
+	tableID model.TableID, better func(a, b int) bool,
+) string {
+	counts := s.counts(tableID)
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	res := values[0]
+	for _, v := range values[1:] {
+		if better(counts[v], counts[res]) {
+			res = v
+		}
+	}
+	return res
+}
+
+// leastLoaded returns the target capture with the least spans, only
+// captures with the value of the spread label are considered if the
+// spread label is set.
+func (s *placementState) leastLoaded(value string) model.CaptureID {
+	ids := make([]model.CaptureID, 0, len(s.workload))
+	for id := range s.workload {
+		if s.spreadLabel == "" || s.value(id) == value {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if s.workload[ids[i]] != s.workload[ids[j]] {
+			return s.workload[ids[i]] < s.workload[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids[0]
+}
+
+// spanOf returns the first span of the table which is placed on captures
+// with the value of the spread label and is not moved in this round.
+func (s *placementState) spanOf(
+	tableID model.TableID, value string, moved *spanz.HashMap[struct{}],
+) (tablepb.Span, bool) {
+	for _, placed := range s.tables[tableID] {
+		if s.isTarget(placed.capture) && s.value(placed.capture) == value &&
+			!moved.Has(placed.span) {
+			return placed.span, true
+		}
+	}
+	return tablepb.Span{}, false
+}
+
+func (s *placementState) tableIDs() []model.TableID {
+	ids := make([]model.TableID, 0, len(s.tables))
+	for id := range s.tables {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
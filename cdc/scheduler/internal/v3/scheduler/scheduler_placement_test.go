@@ -0,0 +1,267 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func zoneCapture(zone string) *member.CaptureStatus {
+	return &member.CaptureStatus{
+		State:  member.CaptureStateInitialized,
+		Labels: map[string]string{"zone": zone},
+	}
+}
+
+func TestSchedulerPlacementCaptureLabels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ChangefeedSchedulerConfig{
+		CaptureLabels: map[string]string{"zone": "a"},
+	}
+	sched := newPlacementScheduler(cfg, 10, model.ChangeFeedID{})
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a1": zoneCapture("a"), "a2": zoneCapture("a"), "b1": zoneCapture("b"),
+	}
+	require.Len(t, sched.filterCaptures(captures, spanz.NewBtreeMap[*replication.ReplicationSet]()), 2)
+	require.NotContains(t, sched.filterCaptures(captures, spanz.NewBtreeMap[*replication.ReplicationSet]()), "b1")
+
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a1"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "b1"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "b1"},
+	})
+	tasks := sched.Schedule(0, nil, captures, replications)
+	require.Len(t, tasks, 2)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: tablepb.Span{TableID: 2}, DestCapture: "a2",
+	}, tasks[0].MoveTable)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: tablepb.Span{TableID: 3}, DestCapture: "a1",
+	}, tasks[1].MoveTable)
+
+	// Tasks are limited by the max task concurrency.
+	sched.maxTaskConcurrency = 1
+	tasks = sched.Schedule(0, nil, captures, replications)
+	require.Len(t, tasks, 1)
+	sched.maxTaskConcurrency = 10
+
+	// No task if some tables are not replicating.
+	replications.GetV(tablepb.Span{TableID: 1}).State = replication.ReplicationSetStatePrepare
+	require.Len(t, sched.Schedule(0, nil, captures, replications), 0)
+
+	require.Nil(t, sched.checkCaptures(captures))
+
+	// No task if no capture matches the labels, an error is reported.
+	cfg.CaptureLabels = map[string]string{"zone": "c"}
+	replications.GetV(tablepb.Span{TableID: 1}).State = replication.ReplicationSetStateReplicating
+	require.Len(t, sched.Schedule(0, nil, captures, replications), 0)
+	require.True(t, errors.ErrNoCaptureMatchesLabels.Equal(sched.checkCaptures(captures)))
+
+	// No task without placement constraints.
+	sched = newPlacementScheduler(nil, 10, model.ChangeFeedID{})
+	require.Len(t, sched.Schedule(0, nil, captures, replications), 0)
+}
+
+func TestSchedulerPlacementSpread(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ChangefeedSchedulerConfig{SpreadLabel: "zone"}
+	sched := newPlacementScheduler(cfg, 10, model.ChangeFeedID{})
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a1": zoneCapture("a"), "a2": zoneCapture("a"), "b1": zoneCapture("b"),
+	}
+
+	// All spans of table 1 are in zone a.
+	replications := spanz.NewBtreeMap[*replication.ReplicationSet]()
+	for i, primary := range []model.CaptureID{"a1", "a1", "a2", "a2"} {
+		span := tablepb.Span{
+			TableID:  1,
+			StartKey: []byte{byte(i)},
+			EndKey:   []byte{byte(i + 1)},
+		}
+		replications.ReplaceOrInsert(span, &replication.ReplicationSet{
+			State: replication.ReplicationSetStateReplicating, Primary: primary,
+		})
+	}
+	tasks := sched.Schedule(0, nil, captures, replications)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, "b1", task.MoveTable.DestCapture)
+	}
+	for _, task := range tasks {
+		replications.GetV(task.MoveTable.Span).Primary = "b1"
+	}
+	require.Len(t, sched.Schedule(0, nil, captures, replications), 0)
+
+	// Moves which make the table less spread are dropped, other tasks
+	// are kept.
+	span0 := tablepb.Span{TableID: 1, StartKey: []byte{0}, EndKey: []byte{1}}
+	span2 := tablepb.Span{TableID: 1, StartKey: []byte{2}, EndKey: []byte{3}}
+	require.Equal(t, "b1", replications.GetV(span0).Primary)
+	require.Equal(t, "a2", replications.GetV(span2).Primary)
+	tasks = sched.filterTasks([]*replication.ScheduleTask{
+		{MoveTable: &replication.MoveTable{Span: span2, DestCapture: "b1"}},
+		{MoveTable: &replication.MoveTable{Span: span0, DestCapture: "a1"}},
+		{BurstBalance: &replication.BurstBalance{MoveTables: []replication.MoveTable{
+			{Span: span0, DestCapture: "a2"},
+			{Span: span2, DestCapture: "a1"},
+		}}},
+	}, captures, replications)
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, []replication.MoveTable{
+		{Span: span2, DestCapture: "a1"},
+	}, tasks[0].BurstBalance.MoveTables)
+}
+
+func TestSchedulerPlacementMoveSpanOnce(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ChangefeedSchedulerConfig{SpreadLabel: "zone"}
+	sched := newPlacementScheduler(cfg, 10, model.ChangeFeedID{})
+	stopping := zoneCapture("a")
+	stopping.State = member.CaptureStateStopping
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a1": zoneCapture("a"), "a2": stopping, "b1": zoneCapture("b"),
+	}
+
+	// Span 0 is on a stopping capture, it is moved off the capture, and
+	// its table is not spread either.
+	replications := spanz.NewBtreeMap[*replication.ReplicationSet]()
+	spans := make([]tablepb.Span, 0, 4)
+	for i, primary := range []model.CaptureID{"a2", "a1", "a1", "a1"} {
+		span := tablepb.Span{
+			TableID:  1,
+			StartKey: []byte{byte(i)},
+			EndKey:   []byte{byte(i + 1)},
+		}
+		spans = append(spans, span)
+		replications.ReplaceOrInsert(span, &replication.ReplicationSet{
+			State: replication.ReplicationSetStateReplicating, Primary: primary,
+		})
+	}
+	tasks := sched.Schedule(0, nil, captures, replications)
+	require.Len(t, tasks, 2)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spans[0], DestCapture: "b1",
+	}, tasks[0].MoveTable)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spans[1], DestCapture: "b1",
+	}, tasks[1].MoveTable)
+
+	// The span moved off the stopping capture is not picked again.
+	moved := spanz.NewHashMap[struct{}]()
+	state := newPlacementState("zone", captures,
+		func(_ model.CaptureID, capture *member.CaptureStatus) bool {
+			return capture.State != member.CaptureStateStopping
+		})
+	require.True(t, state.build(replications))
+	for _, span := range spans[1:] {
+		moved.ReplaceOrInsert(span, struct{}{})
+	}
+	_, ok := state.spanOf(1, "a", moved)
+	require.False(t, ok)
+	moved.Delete(spans[2])
+	span, ok := state.spanOf(1, "a", moved)
+	require.True(t, ok)
+	require.Equal(t, spans[2], span)
+}
+
+func TestSchedulerPlacementSingleCapture(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ChangefeedSchedulerConfig{SingleCapture: true}
+	sched := newPlacementScheduler(cfg, 10, model.ChangeFeedID{})
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized},
+		"b": {State: member.CaptureStateInitialized},
+	}
+
+	// The capture with the most spans is pinned.
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+	})
+	require.Equal(t, []model.CaptureID{"b"},
+		sortedCaptureIDs(sched.filterCaptures(captures, replications)))
+	tasks := sched.Schedule(0, nil, captures, replications)
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: tablepb.Span{TableID: 3}, DestCapture: "b",
+	}, tasks[0].MoveTable)
+
+	// A stopping capture is not pinned, but it is kept for draining.
+	captures["b"].State = member.CaptureStateStopping
+	require.Equal(t, []model.CaptureID{"a", "b"},
+		sortedCaptureIDs(sched.filterCaptures(captures, replications)))
+	tasks = sched.Schedule(0, nil, captures, replications)
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		require.Equal(t, "a", task.MoveTable.DestCapture)
+	}
+}
+
+func TestSchedulerManagerSingleCaptureJoin(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.NewDefaultSchedulerConfig()
+	cfg.ChangefeedSettings = &config.ChangefeedSchedulerConfig{SingleCapture: true}
+	m := NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"), cfg)
+	runningTasks := spanz.NewBtreeMap[*replication.ScheduleTask]()
+
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized},
+	}
+	currentSpans := []tablepb.Span{{TableID: 1}, {TableID: 2}}
+	replications := mapToSpanMap(map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+	})
+	require.Len(t, m.Schedule(0, currentSpans, captures, replications, runningTasks), 0)
+
+	// Tables keep replicating on the pinned capture after a capture joins.
+	captures["b"] = &member.CaptureStatus{State: member.CaptureStateInitialized}
+	for i := 0; i < 3; i++ {
+		require.Len(t, m.Schedule(0, currentSpans, captures, replications, runningTasks), 0)
+	}
+	m.Rebalance()
+	require.Len(t, m.Schedule(0, currentSpans, captures, replications, runningTasks), 0)
+
+	// New tables are added to the pinned capture.
+	currentSpans = append(currentSpans, tablepb.Span{TableID: 3})
+	tasks := m.Schedule(0, currentSpans, captures, replications, runningTasks)
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.AddTables, 1)
+	require.Equal(t, "a", tasks[0].BurstBalance.AddTables[0].CaptureID)
+}
+
+func sortedCaptureIDs(captures map[model.CaptureID]*member.CaptureStatus) []model.CaptureID {
+	res := make([]model.CaptureID, 0, len(captures))
+	for id := range captures {
+		res = append(res, id)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -121,7 +121,10 @@ func newBalanceMoveTables(
 
 	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
 		if rep.State == replication.ReplicationSetStateReplicating {
-			tablesPerCapture[rep.Primary].Add(span)
+			// The capture may be filtered out by the placement constraints.
+			if ts, ok := tablesPerCapture[rep.Primary]; ok {
+				ts.Add(span)
+			}
 		}
 		return true
 	})
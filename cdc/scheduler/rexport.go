@@ -48,6 +48,18 @@ type InfoProvider internal.InfoProvider
 // Query is for open api can access the scheduler
 type Query internal.Query
 
+// DrainQueryType is the type of the drain capture query.
+type DrainQueryType = internal.DrainQueryType
+
+const (
+	// DrainQueryStart starts draining the capture.
+	DrainQueryStart = internal.DrainQueryStart
+	// DrainQueryProgress queries the progress of draining the capture.
+	DrainQueryProgress = internal.DrainQueryProgress
+	// DrainQueryCancel cancels draining the capture.
+	DrainQueryCancel = internal.DrainQueryCancel
+)
+
 // Agent is an interface for an object inside Processor that is responsible
 // for receiving commands from the Owner.
 // Ideally the processor should drive the Agent by Tick.
@@ -564,11 +564,13 @@ func (m *Barrier) GetGlobalBarrierTs() github_com_pingcap_tiflow_cdc_processor_t
 }
 
 type Heartbeat struct {
-	TableIDs     []github_com_pingcap_tiflow_cdc_model.TableID `protobuf:"varint,1,rep,packed,name=table_ids,json=tableIds,proto3,casttype=github.com/pingcap/tiflow/cdc/model.TableID" json:"table_ids,omitempty"`
-	IsStopping   bool                                          `protobuf:"varint,2,opt,name=is_stopping,json=isStopping,proto3" json:"is_stopping,omitempty"`
-	Spans        []tablepb.Span                                `protobuf:"bytes,3,rep,name=spans,proto3" json:"spans"`
-	CollectStats bool                                          `protobuf:"varint,4,opt,name=collect_stats,json=collectStats,proto3" json:"collect_stats,omitempty"`
-	Barrier      *Barrier                                      `protobuf:"bytes,5,opt,name=barrier,proto3" json:"barrier,omitempty"`
+	TableIDs       []github_com_pingcap_tiflow_cdc_model.TableID `protobuf:"varint,1,rep,packed,name=table_ids,json=tableIds,proto3,casttype=github.com/pingcap/tiflow/cdc/model.TableID" json:"table_ids,omitempty"`
+	IsStopping     bool                                          `protobuf:"varint,2,opt,name=is_stopping,json=isStopping,proto3" json:"is_stopping,omitempty"`
+	Spans          []tablepb.Span                                `protobuf:"bytes,3,rep,name=spans,proto3" json:"spans"`
+	CollectStats   bool                                          `protobuf:"varint,4,opt,name=collect_stats,json=collectStats,proto3" json:"collect_stats,omitempty"`
+	Barrier        *Barrier                                      `protobuf:"bytes,5,opt,name=barrier,proto3" json:"barrier,omitempty"`
+	CancelStopping bool                                          `protobuf:"varint,6,opt,name=cancel_stopping,json=cancelStopping,proto3" json:"cancel_stopping,omitempty"`
+	DrainDeadline  int64                                         `protobuf:"varint,7,opt,name=drain_deadline,json=drainDeadline,proto3" json:"drain_deadline,omitempty"`
 }
 
 func (m *Heartbeat) Reset()         { *m = Heartbeat{} }
@@ -639,9 +641,29 @@ func (m *Heartbeat) GetBarrier() *Barrier {
 	return nil
 }
 
+func (m *Heartbeat) GetCancelStopping() bool {
+	if m != nil {
+		return m.CancelStopping
+	}
+	return false
+}
+
+func (m *Heartbeat) GetDrainDeadline() int64 {
+	if m != nil {
+		return m.DrainDeadline
+	}
+	return 0
+}
+
 type HeartbeatResponse struct {
 	Tables   []tablepb.TableStatus                        `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables"`
 	Liveness github_com_pingcap_tiflow_cdc_model.Liveness `protobuf:"varint,2,opt,name=liveness,proto3,casttype=github.com/pingcap/tiflow/cdc/model.Liveness" json:"liveness,omitempty"`
+	// Memory usage in bytes of the capture, it is only set when stats
+	// are collected.
+	MemoryUsage uint64 `protobuf:"varint,3,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
+	// drain_deadline is the drain deadline received by a stopping capture,
+	// it's reported so that a new owner keeps the deadline.
+	DrainDeadline int64 `protobuf:"varint,4,opt,name=drain_deadline,json=drainDeadline,proto3" json:"drain_deadline,omitempty"`
 }
 
 func (m *HeartbeatResponse) Reset()         { *m = HeartbeatResponse{} }
@@ -691,6 +713,20 @@ func (m *HeartbeatResponse) GetLiveness() github_com_pingcap_tiflow_cdc_model.Li
 	return 0
 }
 
+func (m *HeartbeatResponse) GetMemoryUsage() uint64 {
+	if m != nil {
+		return m.MemoryUsage
+	}
+	return 0
+}
+
+func (m *HeartbeatResponse) GetDrainDeadline() int64 {
+	if m != nil {
+		return m.DrainDeadline
+	}
+	return 0
+}
+
 type OwnerRevision struct {
 	Revision int64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
 }
@@ -1016,81 +1052,85 @@ func init() {
 }
 
 var fileDescriptor_86eeacbf6ca5b996 = []byte{
-	// 1175 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x57, 0x5f, 0x6f, 0xdb, 0x54,
-	0x14, 0x8f, 0x93, 0xb4, 0x71, 0x4e, 0xda, 0x34, 0xbb, 0x74, 0xcc, 0x0a, 0x90, 0x04, 0x23, 0xb1,
-	0xb2, 0x81, 0xb3, 0x05, 0x18, 0x63, 0x03, 0xa4, 0xa5, 0x1d, 0x6a, 0xd1, 0xaa, 0x56, 0x6e, 0x0b,
-	0x08, 0x21, 0x05, 0xc7, 0xbe, 0x75, 0xac, 0x25, 0xbe, 0xc6, 0xd7, 0x6d, 0xd5, 0xaf, 0xd0, 0x27,
-	0xbe, 0x40, 0x3f, 0x00, 0x8f, 0x20, 0x21, 0xf1, 0x30, 0x89, 0xd7, 0x49, 0xbc, 0xf4, 0x11, 0x21,
-	0x14, 0x8d, 0xf6, 0x5b, 0x94, 0x17, 0xe4, 0x7b, 0xaf, 0x9d, 0xa4, 0x4d, 0xc1, 0x29, 0x03, 0x89,
-	0x37, 0xdf, 0x73, 0x7d, 0x7e, 0xe7, 0xcf, 0xfd, 0xfd, 0xce, 0xb5, 0xe1, 0x0d, 0x6a, 0x76, 0xb0,
-	0xb5, 0xd3, 0xc5, 0x7e, 0x3d, 0x7a, 0xf2, 0xda, 0xf5, 0xc0, 0x68, 0x77, 0x71, 0x2b, 0x32, 0x68,
-	0x9e, 0x4f, 0x02, 0x82, 0xae, 0x7b, 0x8e, 0x6b, 0x9b, 0x86, 0xa7, 0x05, 0xce, 0x76, 0x97, 0xec,
-	0x69, 0xa6, 0x65, 0x6a, 0xb1, 0xb7, 0x36, 0xf0, 0x2e, 0xcf, 0xdb, 0xc4, 0x26, 0xcc, 0xa7, 0x1e,
-	0x3e, 0x71, 0xf7, 0xf2, 0x2b, 0x9e, 0x4f, 0x4c, 0x4c, 0x29, 0xf1, 0x39, 0x7c, 0x14, 0x86, 0x6f,
-	0xab, 0xdf, 0xa6, 0x61, 0xee, 0x81, 0x65, 0x6d, 0x86, 0x26, 0x1d, 0x7f, 0xbd, 0x83, 0x69, 0x80,
-	0xb6, 0x40, 0xe6, 0x99, 0x38, 0x96, 0x22, 0xd5, 0xa4, 0x85, 0x4c, 0xf3, 0xde, 0x71, 0xbf, 0x9a,
-	0x63, 0xef, 0xac, 0x2c, 0x9d, 0xf6, 0xab, 0x37, 0x6d, 0x27, 0xe8, 0xec, 0xb4, 0x35, 0x93, 0xf4,
-	0xea, 0x22, 0xbb, 0x3a, 0xcf, 0xae, 0x6e, 0x5a, 0x66, 0xbd, 0x47, 0x2c, 0xdc, 0xd5, 0xc4, 0xeb,
-	0x7a, 0x8e, 0x61, 0xad, 0x58, 0x68, 0x09, 0xb2, 0xd4, 0x33, 0x5c, 0x25, 0x5b, 0x93, 0x16, 0x0a,
-	0x8d, 0x1b, 0xda, 0x98, 0xba, 0xe2, 0x5c, 0x35, 0x91, 0xab, 0xb6, 0xe1, 0x19, 0x6e, 0x33, 0xfb,
-	0xb4, 0x5f, 0x4d, 0xe9, 0xcc, 0x1b, 0xbd, 0x0a, 0x33, 0x0e, 0x6d, 0x51, 0x6c, 0x12, 0xd7, 0x32,
-	0xfc, 0x7d, 0x25, 0x5d, 0x93, 0x16, 0x64, 0xbd, 0xe0, 0xd0, 0x8d, 0xc8, 0x84, 0x3e, 0x05, 0x30,
-	0x3b, 0xd8, 0x7c, 0xec, 0x11, 0xc7, 0x0d, 0x94, 0x0c, 0x0b, 0x77, 0x2b, 0x59, 0xb8, 0xc5, 0xd8,
-	0x4f, 0x04, 0x1d, 0x42, 0x52, 0xbf, 0x93, 0x00, 0xe9, 0xb8, 0x47, 0x76, 0xf1, 0x7f, 0xd9, 0xae,
-	0xf4, 0x3f, 0x69, 0x97, 0xfa, 0x9b, 0x04, 0xf3, 0x4b, 0x0e, 0xf5, 0x8c, 0xc0, 0xec, 0x8c, 0x64,
-	0xfd, 0x19, 0xe4, 0x0d, 0xcb, 0x6a, 0x31, 0x47, 0x96, 0x76, 0xa1, 0x71, 0x57, 0x4b, 0x48, 0x35,
-	0xed, 0x0c, 0x63, 0x96, 0x53, 0xba, 0x6c, 0x08, 0x13, 0xfa, 0x0a, 0x66, 0x7c, 0xd6, 0x24, 0x81,
-	0xcd, 0xf3, 0xbf, 0x9f, 0x18, 0xfb, 0x7c, 0x87, 0x97, 0x53, 0x7a, 0xc1, 0x1f, 0x58, 0x9b, 0x79,
-	0xc8, 0xf9, 0x7c, 0x47, 0xfd, 0x41, 0x82, 0xd2, 0x20, 0x19, 0xea, 0x11, 0x97, 0x62, 0xb4, 0x02,
-	0xd3, 0x34, 0x30, 0x82, 0x1d, 0x2a, 0xea, 0xba, 0x9d, 0xac, 0x77, 0x0c, 0x64, 0x83, 0x39, 0xea,
-	0x02, 0xe0, 0x0c, 0x95, 0xd2, 0xcf, 0x8d, 0x4a, 0x3f, 0x4a, 0xf0, 0xc2, 0x48, 0xa1, 0xff, 0x9f,
-	0xd4, 0x9f, 0x49, 0x70, 0xf5, 0x0c, 0xa3, 0x44, 0xf2, 0x9f, 0x9f, 0xa7, 0xd4, 0xfb, 0x97, 0xa0,
-	0x14, 0x47, 0x1b, 0xe1, 0x94, 0x31, 0x96, 0x53, 0x1f, 0x5c, 0x8e, 0x53, 0x31, 0xfe, 0x08, 0xa9,
-	0x00, 0x64, 0x5f, 0x6c, 0xa9, 0x4f, 0x24, 0x98, 0xe1, 0x56, 0xc3, 0xf7, 0x1d, 0xec, 0xff, 0x5b,
-	0x12, 0xdf, 0x02, 0x68, 0xf3, 0x08, 0xad, 0x80, 0xb2, 0xa2, 0xb2, 0xcd, 0x3b, 0xa7, 0xfd, 0x6a,
-	0xe3, 0xaf, 0xd1, 0xce, 0x4d, 0x74, 0x6d, 0x93, 0xea, 0x79, 0x81, 0xb4, 0x49, 0xd5, 0x9f, 0x25,
-	0xc8, 0x45, 0x99, 0x7f, 0x09, 0x45, 0x9e, 0xb9, 0xd8, 0x0e, 0x89, 0x95, 0x59, 0x28, 0x34, 0xde,
-	0x4d, 0xdc, 0xbb, 0xe1, 0x46, 0xe8, 0xb3, 0xc1, 0xd0, 0x8a, 0xa2, 0x36, 0x5c, 0xb1, 0xbb, 0xa4,
-	0x6d, 0x74, 0x5b, 0xcf, 0xad, 0x8e, 0x39, 0x0e, 0xd8, 0x8c, 0xab, 0xf9, 0x29, 0x0d, 0xf9, 0x65,
-	0x6c, 0xf8, 0x41, 0x1b, 0x1b, 0x41, 0xc8, 0xb1, 0xe8, 0x24, 0x78, 0x29, 0x99, 0xe6, 0xfd, 0xe3,
-	0x7e, 0x55, 0x16, 0xbd, 0xa5, 0x93, 0x9e, 0x85, 0x2c, 0xce, 0x82, 0xa2, 0x2a, 0x14, 0xc2, 0x8b,
-	0x25, 0x20, 0x5e, 0xe8, 0x24, 0xee, 0x15, 0x70, 0xe8, 0x86, 0xb0, 0xa0, 0x8f, 0x61, 0x2a, 0x1c,
-	0xa9, 0x54, 0xc9, 0xb0, 0x0e, 0x4e, 0x3e, 0x91, 0xb9, 0x3b, 0x7a, 0x0d, 0x66, 0x4d, 0xd2, 0xed,
-	0x62, 0x33, 0x68, 0x85, 0x52, 0xa5, 0xec, 0x42, 0x94, 0xf5, 0x19, 0x61, 0x0c, 0x65, 0x4c, 0xd1,
-	0x27, 0x90, 0x13, 0x2d, 0x55, 0xa6, 0x2e, 0x96, 0xee, 0xd8, 0x03, 0x8b, 0xce, 0x2a, 0x02, 0x50,
-	0xbf, 0x97, 0xe0, 0x4a, 0xdc, 0xc1, 0x58, 0xad, 0x6b, 0x30, 0xcd, 0x72, 0x8c, 0x18, 0x31, 0xf9,
-	0xa8, 0x11, 0x65, 0x09, 0x18, 0xf4, 0x08, 0xe4, 0xae, 0xb3, 0x8b, 0x5d, 0x4c, 0x39, 0x07, 0xa6,
-	0x9a, 0xb7, 0x4e, 0xfb, 0xd5, 0x37, 0x93, 0x9c, 0xc6, 0x23, 0xe1, 0xa7, 0xc7, 0x08, 0xea, 0x4d,
-	0x98, 0x5d, 0xdb, 0x73, 0xb1, 0xaf, 0xe3, 0x5d, 0x87, 0x3a, 0xc4, 0x45, 0xe5, 0x50, 0xa0, 0xfc,
-	0x99, 0x6b, 0x50, 0x8f, 0xd7, 0xea, 0xeb, 0x50, 0x5c, 0x8f, 0x32, 0x7d, 0xe8, 0x11, 0xb3, 0x83,
-	0xe6, 0x61, 0x0a, 0x87, 0x0f, 0xec, 0xd5, 0xbc, 0xce, 0x17, 0xea, 0x75, 0x98, 0x5b, 0xec, 0x18,
-	0xae, 0x8d, 0xb7, 0x31, 0xb6, 0xc6, 0xbc, 0x98, 0x8d, 0x5e, 0x7c, 0x22, 0x43, 0x6e, 0x15, 0x53,
-	0x6a, 0xd8, 0xac, 0x51, 0x1d, 0x6c, 0x58, 0xd8, 0x17, 0x33, 0xed, 0xbd, 0xc4, 0x27, 0x21, 0x10,
-	0xb4, 0x65, 0xe6, 0xae, 0x0b, 0x18, 0xb4, 0x06, 0x72, 0x8f, 0xda, 0xad, 0x60, 0xdf, 0xe3, 0x93,
-	0xac, 0xd8, 0x78, 0x67, 0x52, 0xc8, 0xcd, 0x7d, 0x0f, 0xeb, 0xb9, 0x1e, 0xb5, 0xc3, 0x07, 0xf4,
-	0x10, 0xb2, 0xdb, 0x3e, 0xe9, 0xb1, 0x4f, 0x9d, 0x7c, 0xf3, 0xf6, 0x69, 0xbf, 0xfa, 0x56, 0x92,
-	0xae, 0x2f, 0x1a, 0x5e, 0xb0, 0xe3, 0x87, 0x2a, 0x60, 0xee, 0xe8, 0x01, 0xa4, 0x03, 0xc2, 0xd8,
-	0x78, 0x29, 0x90, 0x74, 0x40, 0x10, 0x85, 0x17, 0x2d, 0x71, 0x37, 0xf0, 0x51, 0xdd, 0x12, 0x37,
-	0xb5, 0x60, 0xf1, 0x87, 0x89, 0x0b, 0x1d, 0xf7, 0xd1, 0xa2, 0xcf, 0x5b, 0xe3, 0x3e, 0x65, 0x76,
-	0xe1, 0xda, 0xb9, 0xa0, 0x9c, 0xe4, 0xca, 0x34, 0x8b, 0xfa, 0xd1, 0x65, 0xa3, 0x72, 0x14, 0xfd,
-	0xaa, 0x35, 0xf6, 0xbe, 0x5b, 0x87, 0x7c, 0x27, 0x92, 0x95, 0x92, 0x63, 0x91, 0x1a, 0x89, 0x23,
-	0x0d, 0x04, 0x39, 0x00, 0x41, 0x0e, 0xa0, 0x78, 0x31, 0x28, 0x42, 0x66, 0xd0, 0xf7, 0x2e, 0x01,
-	0x1d, 0x15, 0x70, 0xa5, 0x73, 0xd6, 0x54, 0xfe, 0x35, 0x0d, 0xd3, 0x9c, 0x97, 0x48, 0x81, 0xdc,
-	0x2e, 0xf6, 0x63, 0x61, 0xe5, 0xf5, 0x68, 0x89, 0x4c, 0x28, 0x92, 0x50, 0x84, 0xad, 0x58, 0x79,
-	0xfc, 0xe6, 0xbd, 0x93, 0x38, 0x97, 0x11, 0x0d, 0x8b, 0x81, 0x31, 0x4b, 0x46, 0x84, 0xbd, 0x0d,
-	0x73, 0xf1, 0x98, 0x69, 0x71, 0x2d, 0x66, 0x26, 0x14, 0xda, 0xa8, 0xf8, 0x45, 0x98, 0xa2, 0x37,
-	0x3a, 0x12, 0x1c, 0x28, 0x99, 0xb1, 0xf8, 0x45, 0xa0, 0xec, 0x84, 0x1f, 0xbe, 0x67, 0xa6, 0x87,
-	0x88, 0x34, 0x67, 0x8e, 0x9a, 0x6f, 0xfc, 0x21, 0x41, 0x61, 0x48, 0xa9, 0xa8, 0x02, 0xb0, 0x4a,
-	0xed, 0x2d, 0xf7, 0xb1, 0x4b, 0xf6, 0xdc, 0x52, 0xaa, 0x5c, 0x3c, 0x38, 0xac, 0x0d, 0x59, 0xd0,
-	0x5d, 0xb8, 0xb6, 0x4a, 0xed, 0x71, 0x94, 0x2f, 0x49, 0xe5, 0x97, 0x0e, 0x0e, 0x6b, 0x17, 0x6d,
-	0xa3, 0x7b, 0xa0, 0x9c, 0xdf, 0xe2, 0x47, 0x5c, 0x4a, 0x97, 0x5f, 0x3e, 0x38, 0xac, 0x5d, 0xb8,
-	0x8f, 0x54, 0x98, 0x59, 0xa5, 0x76, 0xcc, 0x96, 0x52, 0xa6, 0x5c, 0x3a, 0x38, 0xac, 0x8d, 0xd8,
-	0x50, 0x03, 0xe6, 0x87, 0xd7, 0x31, 0x76, 0xb6, 0xac, 0x1c, 0x1c, 0xd6, 0xc6, 0xee, 0x35, 0xd7,
-	0x8f, 0x7e, 0xaf, 0xa4, 0x9e, 0x1e, 0x57, 0xa4, 0xa3, 0xe3, 0x8a, 0xf4, 0xec, 0xb8, 0x22, 0x7d,
-	0x73, 0x52, 0x49, 0x1d, 0x9d, 0x54, 0x52, 0xbf, 0x9c, 0x54, 0x52, 0x5f, 0xfc, 0xcd, 0x47, 0xc1,
-	0xb8, 0x1f, 0xe3, 0xf6, 0x34, 0xfb, 0x59, 0x7d, 0xfb, 0xcf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xba,
-	0x96, 0xe2, 0x0e, 0x37, 0x0f, 0x00, 0x00,
+	// 1238 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x57, 0xdf, 0x6e, 0xe3, 0xc4,
+	0x17, 0x8e, 0x93, 0x34, 0x7f, 0x4e, 0xda, 0x24, 0x3b, 0xbf, 0xee, 0x6f, 0xa3, 0x00, 0x49, 0x08,
+	0x82, 0x96, 0x5d, 0x70, 0x76, 0x03, 0x2c, 0x4b, 0x17, 0x90, 0x36, 0xed, 0xa2, 0x16, 0x6d, 0xd5,
+	0xca, 0x6d, 0x01, 0x21, 0x24, 0xe3, 0xd8, 0x53, 0xc7, 0xda, 0xc4, 0x63, 0x3c, 0x4e, 0xab, 0xbe,
+	0x42, 0x6f, 0xe0, 0x05, 0xfa, 0x00, 0x5c, 0x72, 0x81, 0xc4, 0xc5, 0x3e, 0xc0, 0x4a, 0xdc, 0xf4,
+	0x12, 0x21, 0x14, 0x2d, 0xed, 0x5b, 0x14, 0x2e, 0x90, 0x67, 0xc6, 0x4e, 0xd2, 0xba, 0x90, 0x86,
+	0x05, 0x89, 0x3b, 0xcf, 0x19, 0x9f, 0xef, 0xfc, 0x99, 0xef, 0x3b, 0x1e, 0xc3, 0xeb, 0x54, 0xef,
+	0x60, 0xa3, 0xdf, 0xc5, 0x6e, 0x23, 0x78, 0x72, 0xda, 0x0d, 0x4f, 0x6b, 0x77, 0xb1, 0x1a, 0x18,
+	0x64, 0xc7, 0x25, 0x1e, 0x41, 0x0b, 0x8e, 0x65, 0x9b, 0xba, 0xe6, 0xc8, 0x9e, 0xb5, 0xdb, 0x25,
+	0xfb, 0xb2, 0x6e, 0xe8, 0x72, 0xe8, 0x2d, 0x0f, 0xbd, 0xcb, 0xf3, 0x26, 0x31, 0x09, 0xf3, 0x69,
+	0xf8, 0x4f, 0xdc, 0xbd, 0xfc, 0x92, 0xe3, 0x12, 0x1d, 0x53, 0x4a, 0x5c, 0x0e, 0x1f, 0x84, 0xe1,
+	0xdb, 0xf5, 0x6f, 0xe3, 0x50, 0x78, 0x60, 0x18, 0xdb, 0xbe, 0x49, 0xc1, 0x5f, 0xf5, 0x31, 0xf5,
+	0xd0, 0x0e, 0x64, 0x78, 0x26, 0x96, 0x51, 0x92, 0x6a, 0xd2, 0x62, 0xa2, 0xb5, 0x74, 0x32, 0xa8,
+	0xa6, 0xd9, 0x3b, 0x6b, 0x2b, 0x67, 0x83, 0xea, 0x2d, 0xd3, 0xf2, 0x3a, 0xfd, 0xb6, 0xac, 0x93,
+	0x5e, 0x43, 0x64, 0xd7, 0xe0, 0xd9, 0x35, 0x74, 0x43, 0x6f, 0xf4, 0x88, 0x81, 0xbb, 0xb2, 0x78,
+	0x5d, 0x49, 0x33, 0xac, 0x35, 0x03, 0xad, 0x40, 0x92, 0x3a, 0x9a, 0x5d, 0x4a, 0xd6, 0xa4, 0xc5,
+	0x5c, 0xf3, 0xa6, 0x1c, 0x51, 0x57, 0x98, 0xab, 0x2c, 0x72, 0x95, 0xb7, 0x1c, 0xcd, 0x6e, 0x25,
+	0x9f, 0x0e, 0xaa, 0x31, 0x85, 0x79, 0xa3, 0x97, 0x61, 0xd6, 0xa2, 0x2a, 0xc5, 0x3a, 0xb1, 0x0d,
+	0xcd, 0x3d, 0x28, 0xc5, 0x6b, 0xd2, 0x62, 0x46, 0xc9, 0x59, 0x74, 0x2b, 0x30, 0xa1, 0x4f, 0x00,
+	0xf4, 0x0e, 0xd6, 0x1f, 0x3b, 0xc4, 0xb2, 0xbd, 0x52, 0x82, 0x85, 0xbb, 0x3d, 0x59, 0xb8, 0xe5,
+	0xd0, 0x4f, 0x04, 0x1d, 0x41, 0xaa, 0x7f, 0x27, 0x01, 0x52, 0x70, 0x8f, 0xec, 0xe1, 0x7f, 0xb3,
+	0x5d, 0xf1, 0xbf, 0xd3, 0xae, 0xfa, 0x2f, 0x12, 0xcc, 0xaf, 0x58, 0xd4, 0xd1, 0x3c, 0xbd, 0x33,
+	0x96, 0xf5, 0xa7, 0x90, 0xd5, 0x0c, 0x43, 0x65, 0x8e, 0x2c, 0xed, 0x5c, 0xf3, 0x9e, 0x3c, 0x21,
+	0xd5, 0xe4, 0x73, 0x8c, 0x59, 0x8d, 0x29, 0x19, 0x4d, 0x98, 0xd0, 0x97, 0x30, 0xeb, 0xb2, 0x26,
+	0x09, 0x6c, 0x9e, 0xff, 0xfd, 0x89, 0xb1, 0x2f, 0x76, 0x78, 0x35, 0xa6, 0xe4, 0xdc, 0xa1, 0xb5,
+	0x95, 0x85, 0xb4, 0xcb, 0x77, 0xea, 0xdf, 0x4b, 0x50, 0x1c, 0x26, 0x43, 0x1d, 0x62, 0x53, 0x8c,
+	0xd6, 0x20, 0x45, 0x3d, 0xcd, 0xeb, 0x53, 0x51, 0xd7, 0x9d, 0xc9, 0x7a, 0xc7, 0x40, 0xb6, 0x98,
+	0xa3, 0x22, 0x00, 0xce, 0x51, 0x29, 0xfe, 0xdc, 0xa8, 0xf4, 0x83, 0x04, 0xff, 0x1b, 0x2b, 0xf4,
+	0xbf, 0x93, 0xfa, 0x33, 0x09, 0xae, 0x9f, 0x63, 0x94, 0x48, 0xfe, 0xb3, 0x8b, 0x94, 0x7a, 0x6f,
+	0x0a, 0x4a, 0x71, 0xb4, 0x31, 0x4e, 0x69, 0x91, 0x9c, 0x7a, 0x7f, 0x3a, 0x4e, 0x85, 0xf8, 0x63,
+	0xa4, 0x02, 0xc8, 0xb8, 0x62, 0xab, 0xfe, 0x44, 0x82, 0x59, 0x6e, 0xd5, 0x5c, 0xd7, 0xc2, 0xee,
+	0x3f, 0x25, 0xf1, 0x1d, 0x80, 0x36, 0x8f, 0xa0, 0x7a, 0x94, 0x15, 0x95, 0x6c, 0xdd, 0x3d, 0x1b,
+	0x54, 0x9b, 0x7f, 0x8e, 0x76, 0x61, 0xa2, 0xcb, 0xdb, 0x54, 0xc9, 0x0a, 0xa4, 0x6d, 0x5a, 0xff,
+	0x51, 0x82, 0x74, 0x90, 0xf9, 0x17, 0x90, 0xe7, 0x99, 0x8b, 0x6d, 0x9f, 0x58, 0x89, 0xc5, 0x5c,
+	0xf3, 0x9d, 0x89, 0x7b, 0x37, 0xda, 0x08, 0x65, 0xce, 0x1b, 0x59, 0x51, 0xd4, 0x86, 0x6b, 0x66,
+	0x97, 0xb4, 0xb5, 0xae, 0xfa, 0xdc, 0xea, 0x28, 0x70, 0xc0, 0x56, 0x58, 0xcd, 0xd7, 0x09, 0xc8,
+	0xae, 0x62, 0xcd, 0xf5, 0xda, 0x58, 0xf3, 0x7c, 0x8e, 0x05, 0x27, 0xc1, 0x4b, 0x49, 0xb4, 0xee,
+	0x9f, 0x0c, 0xaa, 0x19, 0xd1, 0x5b, 0x7a, 0xd5, 0xb3, 0xc8, 0x88, 0xb3, 0xa0, 0xa8, 0x0a, 0x39,
+	0xff, 0xc3, 0xe2, 0x11, 0xc7, 0x77, 0x12, 0xdf, 0x15, 0xb0, 0xe8, 0x96, 0xb0, 0xa0, 0x8f, 0x60,
+	0xc6, 0x1f, 0xa9, 0xb4, 0x94, 0xa8, 0x25, 0xa6, 0x9a, 0xc8, 0xdc, 0x1d, 0xbd, 0x02, 0x73, 0x3a,
+	0xe9, 0x76, 0xb1, 0xee, 0xa9, 0xbe, 0x54, 0x29, 0xfb, 0x20, 0x66, 0x94, 0x59, 0x61, 0xf4, 0x65,
+	0x4c, 0xd1, 0xc7, 0x90, 0x16, 0x2d, 0x2d, 0xcd, 0x5c, 0x2e, 0xdd, 0xc8, 0x03, 0x0b, 0xce, 0x2a,
+	0x00, 0x40, 0x0b, 0x50, 0xd0, 0x35, 0x5b, 0xc7, 0xdd, 0x61, 0x75, 0x29, 0x16, 0x32, 0xcf, 0xcd,
+	0x61, 0x85, 0xaf, 0x42, 0xde, 0x70, 0x35, 0xcb, 0x56, 0x0d, 0xac, 0x19, 0x5d, 0xcb, 0xc6, 0xa5,
+	0xb4, 0x4f, 0x76, 0x65, 0x8e, 0x59, 0x57, 0x84, 0xb1, 0xfe, 0xbb, 0x04, 0xd7, 0xc2, 0x13, 0x09,
+	0xd5, 0xbf, 0x01, 0x29, 0x56, 0x73, 0xc0, 0xb0, 0xab, 0x8f, 0x2e, 0xd1, 0x26, 0x01, 0x83, 0x1e,
+	0x41, 0xa6, 0x6b, 0xed, 0x61, 0x1b, 0x53, 0xce, 0xa9, 0x99, 0xd6, 0xed, 0xb3, 0x41, 0xf5, 0x8d,
+	0x49, 0x4e, 0xf7, 0x91, 0xf0, 0x53, 0x42, 0x04, 0xff, 0xde, 0xd0, 0xc3, 0x3d, 0xe2, 0x1e, 0xa8,
+	0x7d, 0xaa, 0x99, 0x98, 0x5d, 0x0b, 0x92, 0x4a, 0x8e, 0xdb, 0x76, 0x7c, 0x53, 0x44, 0xf9, 0xc9,
+	0xa8, 0xf2, 0x6f, 0xc1, 0xdc, 0xc6, 0xbe, 0x8d, 0x5d, 0x05, 0xef, 0x59, 0xd4, 0x22, 0x36, 0x2a,
+	0xfb, 0xa3, 0x83, 0x3f, 0xf3, 0xe9, 0xa0, 0x84, 0xeb, 0xfa, 0x6b, 0x90, 0xdf, 0x0c, 0x6a, 0x7e,
+	0xe8, 0x10, 0xbd, 0x83, 0xe6, 0x61, 0x06, 0xfb, 0x0f, 0xec, 0xd5, 0xac, 0xc2, 0x17, 0xf5, 0x05,
+	0x28, 0x2c, 0x77, 0x34, 0xdb, 0xc4, 0xbb, 0x18, 0x1b, 0x11, 0x2f, 0x26, 0x83, 0x17, 0x9f, 0x64,
+	0x20, 0xbd, 0x8e, 0x29, 0x4b, 0x78, 0x03, 0x52, 0x1d, 0xac, 0x19, 0xd8, 0x15, 0xd3, 0xf6, 0xdd,
+	0x89, 0x39, 0x22, 0x10, 0xe4, 0x55, 0xe6, 0xae, 0x08, 0x18, 0xb4, 0x01, 0x99, 0x1e, 0x35, 0x55,
+	0xef, 0xc0, 0xe1, 0x33, 0x36, 0xdf, 0x7c, 0xfb, 0xaa, 0x90, 0xdb, 0x07, 0x0e, 0x56, 0xd2, 0x3d,
+	0x6a, 0xfa, 0x0f, 0xe8, 0x21, 0x24, 0x77, 0x5d, 0xd2, 0x63, 0xdd, 0xce, 0xb6, 0xee, 0x9c, 0x0d,
+	0xaa, 0x6f, 0x4e, 0x72, 0x7e, 0xcb, 0x9a, 0xe3, 0xf5, 0x5d, 0x5f, 0x9f, 0xcc, 0x1d, 0x3d, 0x80,
+	0xb8, 0x47, 0x4a, 0xc9, 0x69, 0x41, 0xe2, 0x1e, 0x41, 0x14, 0xfe, 0x6f, 0x88, 0xaf, 0x16, 0xff,
+	0x88, 0xa8, 0xe2, 0x0e, 0x21, 0xf4, 0xf5, 0xc1, 0xc4, 0x85, 0x46, 0x5d, 0xa7, 0x94, 0x79, 0x23,
+	0xc2, 0x8a, 0xf6, 0xe0, 0xc6, 0x85, 0xa0, 0x5c, 0x2e, 0x4c, 0x81, 0xb9, 0xe6, 0x87, 0xd3, 0x46,
+	0xe5, 0x28, 0xca, 0x75, 0x23, 0xca, 0x8c, 0x36, 0x21, 0xdb, 0x09, 0x04, 0xca, 0x34, 0x9c, 0x6b,
+	0x36, 0x27, 0x8e, 0x34, 0x94, 0xf6, 0x10, 0x04, 0x59, 0x80, 0xc2, 0xc5, 0xb0, 0x88, 0x0c, 0x83,
+	0x5e, 0x9a, 0x02, 0x3a, 0x28, 0xe0, 0x5a, 0xe7, 0xbc, 0xa9, 0xfc, 0x73, 0x1c, 0x52, 0x9c, 0x97,
+	0xa8, 0x04, 0xe9, 0x3d, 0xec, 0x86, 0xc2, 0xca, 0x2a, 0xc1, 0x12, 0xe9, 0x90, 0x27, 0xbe, 0x08,
+	0xd5, 0x50, 0x79, 0xfc, 0x4e, 0x70, 0x77, 0xe2, 0x5c, 0xc6, 0x34, 0x2c, 0x46, 0xcf, 0x1c, 0x19,
+	0x13, 0xf6, 0x2e, 0x14, 0xc2, 0x81, 0xa5, 0x72, 0x2d, 0x26, 0xae, 0x28, 0xb4, 0x71, 0xf1, 0x8b,
+	0x30, 0x79, 0x67, 0xcc, 0x8a, 0x2c, 0x28, 0xea, 0xa1, 0xf8, 0x45, 0xa0, 0xe4, 0x15, 0xaf, 0xe4,
+	0xe7, 0xa6, 0x87, 0x88, 0x54, 0xd0, 0xc7, 0xcd, 0x37, 0x7f, 0x93, 0x20, 0x37, 0xa2, 0x54, 0x54,
+	0x01, 0x58, 0xa7, 0xe6, 0x8e, 0xfd, 0xd8, 0x26, 0xfb, 0x76, 0x31, 0x56, 0xce, 0x1f, 0x1e, 0xd5,
+	0x46, 0x2c, 0xe8, 0x1e, 0xdc, 0x58, 0xa7, 0x66, 0x14, 0xe5, 0x8b, 0x52, 0xf9, 0x85, 0xc3, 0xa3,
+	0xda, 0x65, 0xdb, 0x68, 0x09, 0x4a, 0x17, 0xb7, 0xf8, 0x11, 0x17, 0xe3, 0xe5, 0x17, 0x0f, 0x8f,
+	0x6a, 0x97, 0xee, 0xa3, 0x3a, 0xcc, 0xae, 0x53, 0x33, 0x64, 0x4b, 0x31, 0x51, 0x2e, 0x1e, 0x1e,
+	0xd5, 0xc6, 0x6c, 0xa8, 0x09, 0xf3, 0xa3, 0xeb, 0x10, 0x3b, 0x59, 0x2e, 0x1d, 0x1e, 0xd5, 0x22,
+	0xf7, 0x5a, 0x9b, 0xc7, 0xbf, 0x56, 0x62, 0x4f, 0x4f, 0x2a, 0xd2, 0xf1, 0x49, 0x45, 0x7a, 0x76,
+	0x52, 0x91, 0xbe, 0x39, 0xad, 0xc4, 0x8e, 0x4f, 0x2b, 0xb1, 0x9f, 0x4e, 0x2b, 0xb1, 0xcf, 0xff,
+	0xe2, 0xba, 0x12, 0xf5, 0xcb, 0xde, 0x4e, 0xb1, 0xdf, 0xe8, 0xb7, 0xfe, 0x18, 0x00, 0xde, 0xee,
+	0x33, 0x69, 0xd1, 0x0f, 0x00, 0x00,
 }
 
 func (m *AddTableRequest) Marshal() (dAtA []byte, err error) {
@@ -1522,6 +1562,21 @@ func (m *Heartbeat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DrainDeadline != 0 {
+		i = encodeVarintTableSchedule(dAtA, i, uint64(m.DrainDeadline))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.CancelStopping {
+		i--
+		if m.CancelStopping {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Barrier != nil {
 		{
 			size, err := m.Barrier.MarshalToSizedBuffer(dAtA[:i])
@@ -1610,6 +1665,16 @@ func (m *HeartbeatResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.DrainDeadline != 0 {
+		i = encodeVarintTableSchedule(dAtA, i, uint64(m.DrainDeadline))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.MemoryUsage != 0 {
+		i = encodeVarintTableSchedule(dAtA, i, uint64(m.MemoryUsage))
+		i--
+		dAtA[i] = 0x18
+	}
 	if m.Liveness != 0 {
 		i = encodeVarintTableSchedule(dAtA, i, uint64(m.Liveness))
 		i--
@@ -2088,6 +2153,12 @@ func (m *Heartbeat) Size() (n int) {
 		l = m.Barrier.Size()
 		n += 1 + l + sovTableSchedule(uint64(l))
 	}
+	if m.CancelStopping {
+		n += 2
+	}
+	if m.DrainDeadline != 0 {
+		n += 1 + sovTableSchedule(uint64(m.DrainDeadline))
+	}
 	return n
 }
 
@@ -2106,6 +2177,12 @@ func (m *HeartbeatResponse) Size() (n int) {
 	if m.Liveness != 0 {
 		n += 1 + sovTableSchedule(uint64(m.Liveness))
 	}
+	if m.MemoryUsage != 0 {
+		n += 1 + sovTableSchedule(uint64(m.MemoryUsage))
+	}
+	if m.DrainDeadline != 0 {
+		n += 1 + sovTableSchedule(uint64(m.DrainDeadline))
+	}
 	return n
 }
 
@@ -3352,6 +3429,45 @@ func (m *Heartbeat) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CancelStopping", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTableSchedule
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CancelStopping = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DrainDeadline", wireType)
+			}
+			m.DrainDeadline = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTableSchedule
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DrainDeadline |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTableSchedule(dAtA[iNdEx:])
@@ -3455,6 +3571,44 @@ func (m *HeartbeatResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryUsage", wireType)
+			}
+			m.MemoryUsage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTableSchedule
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryUsage |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DrainDeadline", wireType)
+			}
+			m.DrainDeadline = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTableSchedule
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DrainDeadline |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTableSchedule(dAtA[iNdEx:])
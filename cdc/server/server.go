@@ -231,7 +231,8 @@ func (s *server) createSortEngineFactory() error {
 	}
 	memPercentage := float64(conf.Sorter.MaxMemoryPercentage) / 100
 	memInBytes := uint64(float64(totalMemory) * memPercentage)
-	s.sortEngineFactory = factory.NewForPebble(sortDir, memInBytes, conf.Debug.DB)
+	s.sortEngineFactory = factory.NewForPebble(
+		sortDir, memInBytes, conf.Sorter.MaxChangefeedDiskUsage, conf.Debug.DB)
 	log.Info("sorter engine memory limit",
 		zap.Uint64("bytes", memInBytes),
 		zap.String("memory", humanize.IBytes(memInBytes)),
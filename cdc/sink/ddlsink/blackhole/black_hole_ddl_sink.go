@@ -49,5 +49,13 @@ func (d *DDLSink) WriteCheckpointTs(ctx context.Context,
 	return nil
 }
 
+// WriteSyncPoint do nothing.
+func (d *DDLSink) WriteSyncPoint(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	log.Debug("BlackHoleSink: SyncPoint Event", zap.Uint64("ts", ts), zap.Any("tables", tables))
+	return nil
+}
+
 // Close do nothing.
 func (d *DDLSink) Close() {}
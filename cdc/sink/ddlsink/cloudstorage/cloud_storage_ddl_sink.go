@@ -102,6 +102,23 @@ func (d *DDLSink) WriteCheckpointTs(ctx context.Context,
 	return errors.Trace(err)
 }
 
+// WriteSyncPoint writes the manifest of the syncpoint to the cloud storage,
+// which lists the last data file of each table.
+func (d *DDLSink) WriteSyncPoint(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	manifest, err := cloudstorage.NewSyncPointManifest(ctx, d.storage, ts, tables)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = d.storage.WriteFile(ctx, cloudstorage.GenerateSyncPointManifestPath(ts), data)
+	return errors.Trace(err)
+}
+
 // Close closes the sink.
 func (d *DDLSink) Close() {
 	if d.statistics != nil {
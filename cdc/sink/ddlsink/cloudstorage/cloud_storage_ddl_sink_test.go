@@ -21,6 +21,8 @@ import (
 	"path"
 	"testing"
 
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
@@ -133,3 +135,88 @@ func TestWriteCheckpointTs(t *testing.T) {
 	require.Nil(t, err)
 	require.JSONEq(t, `{"checkpoint-ts":100}`, string(metadata))
 }
+
+// noWalkStorage fails if the whole storage is listed.
+type noWalkStorage struct {
+	storage.ExternalStorage
+}
+
+func (s *noWalkStorage) WalkDir(
+	_ context.Context, _ *storage.WalkOption, _ func(path string, size int64) error,
+) error {
+	return errors.New("the storage should not be listed")
+}
+
+func TestWriteSyncPoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	parentDir := t.TempDir()
+	uri := fmt.Sprintf("file:///%s", parentDir)
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+	sink, err := NewDDLSink(ctx, sinkURI)
+	require.Nil(t, err)
+	sink.storage = &noWalkStorage{ExternalStorage: sink.storage}
+
+	// table1 is written to two date dirs, only the last one is reported
+	for dir, fileName := range map[string]string{
+		"test/table1/100/2023-03-09": "CDC000005.csv",
+		"test/table1/100/2023-03-10": "CDC000002.csv",
+		"test/table2/200/66":         "CDC000001.csv",
+		"test/table2/200/67":         "CDC000003.csv",
+	} {
+		dataDir := path.Join(parentDir, dir)
+		require.Nil(t, os.MkdirAll(dataDir, 0o755))
+		require.Nil(t, os.WriteFile(path.Join(dataDir, "CDC.index"), []byte(fileName+"\n"), 0o644))
+		require.Nil(t, os.WriteFile(path.Join(dataDir, fileName), []byte("data"), 0o644))
+	}
+	require.Nil(t, os.MkdirAll(path.Join(parentDir, "syncpoint/tables"), 0o755))
+	for tableID, tableDir := range map[int64]string{
+		1:  `{"schema":"test","table":"table1","dir":"test/table1/100/2023-03-10"}`,
+		66: `{"schema":"test","table":"table2","dir":"test/table2/200/66"}`,
+		67: `{"schema":"test","table":"table2","dir":"test/table2/200/67"}`,
+		// table3 is not replicated any more
+		3: `{"schema":"test","table":"table3","dir":"test/table3/300"}`,
+	} {
+		require.Nil(t, os.WriteFile(path.Join(parentDir,
+			fmt.Sprintf("syncpoint/tables/%d.dir", tableID)), []byte(tableDir), 0o644))
+	}
+
+	tables := []*model.TableInfo{
+		{TableInfo: &timodel.TableInfo{ID: 1}},
+		{TableInfo: &timodel.TableInfo{ID: 2, Partition: &timodel.PartitionInfo{
+			Enable:      true,
+			Definitions: []timodel.PartitionDefinition{{ID: 66}, {ID: 67}, {ID: 68}},
+		}}},
+	}
+	err = sink.WriteSyncPoint(ctx, 100, tables)
+	require.Nil(t, err)
+	manifest, err := os.ReadFile(path.Join(parentDir, "syncpoint/100.manifest"))
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"primary-ts": 100,
+		"files": [
+			{
+				"schema": "test",
+				"table": "table1",
+				"dir": "test/table1/100/2023-03-10",
+				"file-name": "CDC000002.csv",
+				"index": 2
+			},
+			{
+				"schema": "test",
+				"table": "table2",
+				"dir": "test/table2/200/66",
+				"file-name": "CDC000001.csv",
+				"index": 1
+			},
+			{
+				"schema": "test",
+				"table": "table2",
+				"dir": "test/table2/200/67",
+				"file-name": "CDC000003.csv",
+				"index": 3
+			}
+		]
+	}`, string(manifest))
+}
@@ -28,6 +28,12 @@ type Sink interface {
 	// Note: This is a synchronous and thread-safe method.
 	// This only for MQSink for now.
 	WriteCheckpointTs(ctx context.Context, ts uint64, tables []*model.TableInfo) error
+	// WriteSyncPoint writes a syncpoint marker to the sink, all events before
+	// or at the ts are written to the sink when it's called.
+	// Note: This is a synchronous and thread-safe method.
+	// This only for MQSink and cloud storage sink, syncpoints of MySQL
+	// compatible sinks are written by the syncpoint store.
+	WriteSyncPoint(ctx context.Context, ts uint64, tables []*model.TableInfo) error
 	// Close closes the sink.
 	Close()
 }
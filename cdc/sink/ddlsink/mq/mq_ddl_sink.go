@@ -147,8 +147,39 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 	if msg == nil {
 		return nil
 	}
+	return k.broadcast(ctx, msg, tables)
+}
+
+// WriteSyncPoint sends a syncpoint marker to all partitions of the MQ system.
+func (k *DDLSink) WriteSyncPoint(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	encoder := k.encoderBuilder.Build()
+	msg, err := encoder.EncodeSyncPointEvent(ts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if msg == nil {
+		log.Warn("Skip syncpoint event, it's not supported by the protocol",
+			zap.Uint64("primaryTs", ts),
+			zap.String("protocol", k.protocol.String()),
+			zap.String("namespace", k.id.Namespace),
+			zap.String("changefeed", k.id.ID))
+		return nil
+	}
+	log.Info("Emit syncpoint event",
+		zap.Uint64("primaryTs", ts),
+		zap.String("namespace", k.id.Namespace),
+		zap.String("changefeed", k.id.ID))
+	return k.broadcast(ctx, msg, tables)
+}
+
+// broadcast sends the message to all partitions of topics of the tables.
+func (k *DDLSink) broadcast(ctx context.Context,
+	msg *common.Message, tables []*model.TableInfo,
+) error {
 	// NOTICE: When there are no tables to replicate,
-	// we need to send checkpoint ts to the default topic.
+	// we need to send the message to the default topic.
 	// This will be compatible with the old behavior.
 	if len(tables) == 0 {
 		topic := k.eventRouter.GetDefaultTopic()
@@ -156,8 +187,8 @@ func (k *DDLSink) WriteCheckpointTs(ctx context.Context,
 		if err != nil {
 			return errors.Trace(err)
 		}
-		log.Debug("Emit checkpointTs to default topic",
-			zap.String("topic", topic), zap.Uint64("checkpointTs", ts))
+		log.Debug("Emit message to default topic",
+			zap.String("topic", topic), zap.Uint64("ts", msg.Ts))
 		err = k.producer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
 		return errors.Trace(err)
 	}
@@ -297,3 +297,41 @@ func TestWriteCheckpointTsWhenCanalJsonTiDBExtensionIsDisable(t *testing.T) {
 	require.Len(t, s.producer.(*ddlproducer.MockDDLProducer).GetAllEvents(),
 		0, "No topic and partition should be broadcast")
 }
+
+func TestWriteSyncPoint(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leader, topic := initBroker(t, kafka.DefaultMockPartitionNum)
+	defer leader.Close()
+	uriTemplate := "kafka://%s/%s?kafka-version=0.9.0.0&max-batch-size=1" +
+		"&max-message-bytes=1048576&partition-num=1" +
+		"&kafka-client-id=unit-test&auto-create-topic=false&compression=gzip" +
+		"&protocol=open-protocol"
+	uri := fmt.Sprintf(uriTemplate, leader.Addr(), topic)
+
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.Nil(t, replicaConfig.ValidateAndAdjust(sinkURI))
+
+	s, err := NewKafkaDDLSink(ctx, sinkURI, replicaConfig,
+		kafka.NewMockFactory,
+		ddlproducer.NewMockDDLProducer)
+	require.Nil(t, err)
+	require.NotNil(t, s)
+
+	primaryTs := uint64(417318403368288260)
+	var tables []*model.TableInfo
+	err = s.WriteSyncPoint(ctx, primaryTs, tables)
+	require.Nil(t, err)
+
+	events := s.producer.(*ddlproducer.MockDDLProducer).GetAllEvents()
+	require.Len(t, events, 3, "All partitions should be broadcast")
+	for _, event := range events {
+		require.Equal(t, model.MessageTypeSyncPoint, event.Type)
+		require.Equal(t, primaryTs, event.Ts)
+	}
+}
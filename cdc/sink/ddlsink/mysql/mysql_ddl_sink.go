@@ -213,6 +213,12 @@ func (m *DDLSink) WriteCheckpointTs(_ context.Context, _ uint64, _ []*model.Tabl
 	return nil
 }
 
+// WriteSyncPoint does nothing.
+func (m *DDLSink) WriteSyncPoint(_ context.Context, _ uint64, _ []*model.TableInfo) error {
+	// Syncpoints are written by the syncpoint store.
+	return nil
+}
+
 // Close closes the database connection.
 func (m *DDLSink) Close() {
 	if m.statistics != nil {
@@ -48,7 +48,10 @@ type dmlWorker struct {
 	// tableEvents maintains a mapping of <table, []eventFragment>.
 	tableEvents *tableEventsMap
 	// fileSize maintains a mapping of <table, file size>.
-	fileSize          map[cloudstorage.VersionedTableName]uint64
+	fileSize map[cloudstorage.VersionedTableName]uint64
+	// tableDirs maintains a mapping of <physical table id, data dir> which
+	// are reported for syncpoints.
+	tableDirs         map[int64]string
 	isClosed          uint64
 	statistics        *metrics.Statistics
 	filePathGenerator *cloudstorage.FilePathGenerator
@@ -97,6 +100,7 @@ func newDMLWorker(
 		tableEvents:       newTableEventsMap(),
 		flushNotifyCh:     make(chan flushTask, 1),
 		fileSize:          make(map[cloudstorage.VersionedTableName]uint64),
+		tableDirs:         make(map[int64]string),
 		statistics:        statistics,
 		filePathGenerator: cloudstorage.NewFilePathGenerator(config, storage, extension, clock),
 		bufferPool: sync.Pool{
@@ -178,6 +182,19 @@ func (d *dmlWorker) flushMessages(ctx context.Context) error {
 				}
 				indexFilePath := d.filePathGenerator.GenerateIndexFilePath(table, date)
 
+				// report the data dir before writing the index file, so the
+				// last data file of the table can be found by syncpoints.
+				err = d.reportTableDir(ctx, table, path.Dir(dataFilePath))
+				if err != nil {
+					log.Error("failed to report table dir to external storage",
+						zap.Int("workerID", d.id),
+						zap.String("namespace", d.changeFeedID.Namespace),
+						zap.String("changefeed", d.changeFeedID.ID),
+						zap.String("path", dataFilePath),
+						zap.Error(err))
+					return errors.Trace(err)
+				}
+
 				// first write the index file to external storage.
 				// the file content is simply the last element of the data file path
 				err = d.writeIndexFile(ctx, indexFilePath, path.Base(dataFilePath)+"\n")
@@ -254,6 +271,31 @@ func (d *dmlWorker) writeSchemaFile(
 	return nil
 }
 
+// reportTableDir writes the data dir of the table to the external storage if
+// the table is written to a new data dir.
+func (d *dmlWorker) reportTableDir(
+	ctx context.Context, table cloudstorage.VersionedTableName, dir string,
+) error {
+	tableID := table.TableNameWithPhysicTableID.TableID
+	if d.tableDirs[tableID] == dir {
+		return nil
+	}
+	data, err := json.Marshal(cloudstorage.SyncPointTableDir{
+		Schema: table.TableNameWithPhysicTableID.Schema,
+		Table:  table.TableNameWithPhysicTableID.Table,
+		Dir:    dir,
+	})
+	if err != nil {
+		return err
+	}
+	err = d.storage.WriteFile(ctx, cloudstorage.GenerateSyncPointTableDirPath(tableID), data)
+	if err != nil {
+		return err
+	}
+	d.tableDirs[tableID] = dir
+	return nil
+}
+
 func (d *dmlWorker) writeIndexFile(ctx context.Context, path, content string) error {
 	err := d.storage.WriteFile(ctx, path, []byte(content))
 	return err
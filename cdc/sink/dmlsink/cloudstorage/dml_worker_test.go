@@ -134,6 +134,10 @@ func TestDMLWorkerRun(t *testing.T) {
 		fileNames = append(fileNames, f.Name())
 	}
 	require.ElementsMatch(t, []string{"CDC000001.json", "schema.json", "CDC.index"}, fileNames)
+	// the data dir of table1 is reported for syncpoints
+	tableDir, err := os.ReadFile(path.Join(parentDir, "syncpoint/tables/100.dir"))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"schema":"test","table":"table1","dir":"test/table1/99"}`, string(tableDir))
 	cancel()
 	d.close()
 	wg.Wait()
@@ -23,3 +23,11 @@ type EventSink[E TableEvent] interface {
 	// The EventSink meets internal errors and has been dead already.
 	Dead() <-chan struct{}
 }
+
+// GlobalResolvedTsUpdater is implemented by sinks which assemble upstream
+// transactions spanning multiple tables before writing them downstream.
+type GlobalResolvedTsUpdater interface {
+	// UpdateGlobalResolvedTs tells the sink that every table sink has written
+	// all events whose commit ts is less than or equal to ts.
+	UpdateGlobalResolvedTs(ts uint64)
+}
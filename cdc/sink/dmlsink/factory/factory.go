@@ -131,6 +131,14 @@ func (s *SinkFactory) CreateTableSinkForConsumer(
 		&dmlsink.RowChangeEventAppender{}, totalRowsCounter)
 }
 
+// UpdateGlobalResolvedTs forwards the minimal resolved ts of all table sinks
+// to the underlying sink if it assembles cross-table transactions.
+func (s *SinkFactory) UpdateGlobalResolvedTs(ts model.Ts) {
+	if updater, ok := s.txnSink.(dmlsink.GlobalResolvedTsUpdater); ok {
+		updater.UpdateGlobalResolvedTs(ts)
+	}
+}
+
 // Close closes the sink.
 func (s *SinkFactory) Close() {
 	if s.rowSink != nil && s.txnSink != nil {
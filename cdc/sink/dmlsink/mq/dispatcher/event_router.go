@@ -102,7 +102,8 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 			f = filter.CaseInsensitive(f)
 		}
 
-		d := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		d := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue,
+			cfg.Sink.EnableTxnBoundary)
 		t, err := getTopicDispatcher(ruleConfig, defaultTopic, cfg.Sink.Protocol)
 		if err != nil {
 			return nil, err
@@ -222,8 +223,10 @@ func (s *EventRouter) matchDispatcher(
 }
 
 // getPartitionDispatcher returns the partition dispatcher for a specific partition rule.
+// If transaction boundaries are enabled, the default rule dispatches rows by table,
+// so that rows of a transaction are sent to the same partition.
 func getPartitionDispatcher(
-	ruleConfig *config.DispatchRule, enableOldValue bool,
+	ruleConfig *config.DispatchRule, enableOldValue bool, enableTxnBoundary bool,
 ) partition.Dispatcher {
 	var (
 		d    partition.Dispatcher
@@ -243,7 +246,11 @@ func getPartitionDispatcher(
 	case partitionDispatchRuleTable:
 		d = partition.NewTableDispatcher()
 	case partitionDispatchRuleDefault:
-		d = partition.NewDefaultDispatcher(enableOldValue)
+		if enableTxnBoundary {
+			d = partition.NewTableDispatcher()
+		} else {
+			d = partition.NewDefaultDispatcher(enableOldValue)
+		}
 	}
 
 	return d
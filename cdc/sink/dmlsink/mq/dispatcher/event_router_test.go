@@ -34,6 +34,14 @@ func TestEventRouter(t *testing.T) {
 	require.IsType(t, &topic.StaticTopicDispatcher{}, topicDispatcher)
 	require.IsType(t, &partition.DefaultDispatcher{}, partitionDispatcher)
 
+	// rows of a transaction are dispatched by table if transaction boundaries are enabled.
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.EnableTxnBoundary = true
+	d, err = NewEventRouter(replicaConfig, "test")
+	require.Nil(t, err)
+	_, partitionDispatcher = d.matchDispatcher("test", "test")
+	require.IsType(t, &partition.TableDispatcher{}, partitionDispatcher)
+
 	d, err = NewEventRouter(&config.ReplicaConfig{
 		Sink: &config.SinkConfig{
 			DispatchRules: []*config.DispatchRule{
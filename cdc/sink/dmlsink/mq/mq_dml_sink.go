@@ -44,6 +44,9 @@ type dmlSink struct {
 	id model.ChangeFeedID
 	// protocol indicates the protocol used by this sink.
 	protocol config.Protocol
+	// enableTxnBoundary indicates whether to attach the transaction boundary
+	// to each row.
+	enableTxnBoundary bool
 
 	worker *worker
 	// eventRouter used to route events to the right topic and partition.
@@ -84,15 +87,16 @@ func newDMLSink(
 	worker := newWorker(changefeedID, encoderConfig.Protocol,
 		encoderBuilder, encoderConcurrency, producer, statistics)
 	s := &dmlSink{
-		id:           changefeedID,
-		protocol:     encoderConfig.Protocol,
-		worker:       worker,
-		eventRouter:  eventRouter,
-		topicManager: topicManager,
-		adminClient:  adminClient,
-		ctx:          ctx,
-		cancel:       cancel,
-		dead:         make(chan struct{}),
+		id:                changefeedID,
+		protocol:          encoderConfig.Protocol,
+		enableTxnBoundary: encoderConfig.EnableTxnBoundary,
+		worker:            worker,
+		eventRouter:       eventRouter,
+		topicManager:      topicManager,
+		adminClient:       adminClient,
+		ctx:               ctx,
+		cancel:            cancel,
+		dead:              make(chan struct{}),
 	}
 
 	// Spawn a goroutine to send messages by the worker.
@@ -118,6 +122,9 @@ func (s *dmlSink) WriteEvents(rows ...*dmlsink.RowChangeCallbackableEvent) error
 		return errors.Trace(errors.New("dead dmlSink"))
 	}
 
+	if s.enableTxnBoundary {
+		setTxnBoundaries(rows)
+	}
 	for _, row := range rows {
 		if row.GetTableSinkState() != state.TableSinkSinking {
 			// The table where the event comes from is in stopping, so it's safe
@@ -143,6 +150,28 @@ func (s *dmlSink) WriteEvents(rows ...*dmlsink.RowChangeCallbackableEvent) error
 	return nil
 }
 
+// setTxnBoundaries attaches the transaction boundary to each row. Rows are
+// from the same table, and with the table level atomicity, all rows of a
+// transaction are written in the same batch, so a transaction is a run of
+// rows with the same StartTs and CommitTs.
+func setTxnBoundaries(rows []*dmlsink.RowChangeCallbackableEvent) {
+	for begin := 0; begin < len(rows); {
+		end := begin + 1
+		for end < len(rows) &&
+			rows[end].Event.StartTs == rows[begin].Event.StartTs &&
+			rows[end].Event.CommitTs == rows[begin].Event.CommitTs {
+			end++
+		}
+		for i := begin; i < end; i++ {
+			rows[i].Event.TxnBoundary = &model.TxnBoundary{
+				TableRowIndex: i - begin + 1,
+				TableRowCount: end - begin,
+			}
+		}
+		begin = end
+	}
+}
+
 // Close closes the sink.
 func (s *dmlSink) Close() {
 	if s.cancel != nil {
@@ -123,3 +123,39 @@ func TestWriteEvents(t *testing.T) {
 	require.Len(t, s.worker.producer.(*dmlproducer.MockDMLProducer).GetAllEvents(), 3000)
 	s.Close()
 }
+
+func TestSetTxnBoundaries(t *testing.T) {
+	t.Parallel()
+
+	tableStatus := state.TableSinkSinking
+	txns := []struct{ startTs, commitTs uint64 }{
+		{1, 10}, {1, 10}, {2, 10}, {3, 11}, {3, 11}, {3, 11},
+	}
+	events := make([]*dmlsink.RowChangeCallbackableEvent, 0, len(txns))
+	for _, txn := range txns {
+		events = append(events, &dmlsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				StartTs:  txn.startTs,
+				CommitTs: txn.commitTs,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+			},
+			Callback:  func() {},
+			SinkState: &tableStatus,
+		})
+	}
+	setTxnBoundaries(events)
+
+	expected := []model.TxnBoundary{
+		{TableRowIndex: 1, TableRowCount: 2}, {TableRowIndex: 2, TableRowCount: 2},
+		{TableRowIndex: 1, TableRowCount: 1},
+		{TableRowIndex: 1, TableRowCount: 3}, {TableRowIndex: 2, TableRowCount: 3}, {TableRowIndex: 3, TableRowCount: 3},
+	}
+	for i, event := range events {
+		require.Equal(t, expected[i], *event.Event.TxnBoundary)
+	}
+	require.True(t, events[0].Event.TxnBoundary.IsBegin())
+	require.False(t, events[0].Event.TxnBoundary.IsCommit())
+	require.True(t, events[2].Event.TxnBoundary.IsBegin())
+	require.True(t, events[2].Event.TxnBoundary.IsCommit())
+	require.True(t, events[5].Event.TxnBoundary.IsCommit())
+}
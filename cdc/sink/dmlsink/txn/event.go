@@ -27,6 +27,9 @@ import (
 
 type txnEvent struct {
 	*dmlsink.TxnCallbackableEvent
+	// others holds the SingleTableTxns of other tables which belong to the
+	// same upstream transaction. It's only used with global level atomicity.
+	others           []*dmlsink.TxnCallbackableEvent
 	start            time.Time
 	conflictResolved time.Time
 }
@@ -35,6 +38,20 @@ func newTxnEvent(event *dmlsink.TxnCallbackableEvent) *txnEvent {
 	return &txnEvent{TxnCallbackableEvent: event, start: time.Now()}
 }
 
+// newGlobalTxnEvent creates a txnEvent from all SingleTableTxns of one
+// upstream transaction, which will be flushed in one downstream transaction.
+func newGlobalTxnEvent(events []*dmlsink.TxnCallbackableEvent) *txnEvent {
+	return &txnEvent{TxnCallbackableEvent: events[0], others: events[1:], start: time.Now()}
+}
+
+// parts returns all SingleTableTxns carried by the txnEvent.
+func (e *txnEvent) parts() []*dmlsink.TxnCallbackableEvent {
+	if len(e.others) == 0 {
+		return []*dmlsink.TxnCallbackableEvent{e.TxnCallbackableEvent}
+	}
+	return append([]*dmlsink.TxnCallbackableEvent{e.TxnCallbackableEvent}, e.others...)
+}
+
 func (e *txnEvent) OnConflictResolved() {
 	e.conflictResolved = time.Now()
 }
@@ -42,6 +59,9 @@ func (e *txnEvent) OnConflictResolved() {
 // ConflictKeys implements causality.txnEvent interface.
 func (e *txnEvent) ConflictKeys(numSlots uint64) []uint64 {
 	keys := genTxnKeys(e.TxnCallbackableEvent.Event)
+	if len(e.others) > 0 {
+		keys = mergeTxnKeys(keys, e.others)
+	}
 	sort.Slice(keys, func(i, j int) bool { return keys[i]%numSlots < keys[j]%numSlots })
 	return keys
 }
@@ -69,6 +89,23 @@ func genTxnKeys(txn *model.SingleTableTxn) []uint64 {
 	return keys
 }
 
+// mergeTxnKeys appends keys of `others` to `keys` and removes duplicates.
+func mergeTxnKeys(keys []uint64, others []*dmlsink.TxnCallbackableEvent) []uint64 {
+	hashRes := make(map[uint64]struct{}, len(keys))
+	for _, key := range keys {
+		hashRes[key] = struct{}{}
+	}
+	for _, other := range others {
+		for _, key := range genTxnKeys(other.Event) {
+			if _, ok := hashRes[key]; !ok {
+				hashRes[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
 func genRowKeys(row *model.RowChangedEvent) [][]byte {
 	var keys [][]byte
 	if len(row.Columns) != 0 {
@@ -0,0 +1,86 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+)
+
+// globalTxnKey identifies an upstream transaction. All SingleTableTxns split
+// from one upstream transaction share the same start ts and commit ts.
+type globalTxnKey struct {
+	startTs  model.Ts
+	commitTs model.Ts
+}
+
+// globalTxnAssembler buffers SingleTableTxns until all tables have written
+// the events of an upstream transaction, and then reassembles them into one
+// transaction so that they can be applied downstream atomically.
+//
+// Buffered transactions are not called back, so table sinks don't release
+// their memory. It's bounded by the sink memory quota of the processor.
+type globalTxnAssembler struct {
+	mu   sync.Mutex
+	txns map[globalTxnKey][]*dmlsink.TxnCallbackableEvent
+	// resolvedTs is the last global resolved ts.
+	resolvedTs model.Ts
+}
+
+func newGlobalTxnAssembler() *globalTxnAssembler {
+	return &globalTxnAssembler{
+		txns: make(map[globalTxnKey][]*dmlsink.TxnCallbackableEvent),
+	}
+}
+
+// add buffers a SingleTableTxn.
+func (a *globalTxnAssembler) add(txn *dmlsink.TxnCallbackableEvent) {
+	key := globalTxnKey{startTs: txn.Event.StartTs, commitTs: txn.Event.CommitTs}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.txns[key] = append(a.txns[key], txn)
+}
+
+// resolve advances the global resolved ts and returns all complete upstream
+// transactions, ordered by commit ts.
+func (a *globalTxnAssembler) resolve(ts model.Ts) [][]*dmlsink.TxnCallbackableEvent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ts > a.resolvedTs {
+		a.resolvedTs = ts
+	}
+
+	keys := make([]globalTxnKey, 0)
+	for key := range a.txns {
+		if key.commitTs <= a.resolvedTs {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].commitTs != keys[j].commitTs {
+			return keys[i].commitTs < keys[j].commitTs
+		}
+		return keys[i].startTs < keys[j].startTs
+	})
+
+	resolved := make([][]*dmlsink.TxnCallbackableEvent, 0, len(keys))
+	for _, key := range keys {
+		resolved = append(resolved, a.txns[key])
+		delete(a.txns, key)
+	}
+	return resolved
+}
@@ -0,0 +1,485 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+const (
+	bdrConflictApplied = "applied"
+	bdrConflictSkipped = "skipped"
+)
+
+// bdrConflict is a row changed in both clusters in BDR mode.
+type bdrConflict struct {
+	row *model.RowChangedEvent
+	// key is the readable handle key of the row.
+	key             string
+	incomingVersion uint64
+	// downstreamVersion is nil if the row does not exist in the downstream.
+	downstreamVersion *uint64
+	applied           bool
+}
+
+func (c *bdrConflict) eventType() string {
+	switch {
+	case c.row.IsInsert():
+		return "insert"
+	case c.row.IsDelete():
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+func (c *bdrConflict) resolution() string {
+	if c.applied {
+		return bdrConflictApplied
+	}
+	return bdrConflictSkipped
+}
+
+// createBDRConflictTable creates the table which records bdr conflicts.
+func createBDRConflictTable(ctx context.Context, db *sql.DB, conflictTable string) error {
+	schema, table, _ := strings.Cut(conflictTable, ".")
+	if _, err := db.ExecContext(ctx,
+		"CREATE DATABASE IF NOT EXISTS "+quotes.QuoteName(schema)); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	query := `CREATE TABLE IF NOT EXISTS %s
+	(
+		id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		ticdc_cluster_id VARCHAR(255) NOT NULL,
+		changefeed VARCHAR(255) NOT NULL,
+		source_id BIGINT UNSIGNED NOT NULL,
+		schema_name VARCHAR(255) NOT NULL,
+		table_name VARCHAR(255) NOT NULL,
+		row_key TEXT NOT NULL,
+		event_type VARCHAR(16) NOT NULL,
+		commit_ts BIGINT UNSIGNED NOT NULL,
+		incoming_version BIGINT UNSIGNED NOT NULL,
+		downstream_version BIGINT UNSIGNED NULL,
+		resolution VARCHAR(16) NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		INDEX (created_at)
+	);`
+	if _, err := db.ExecContext(ctx,
+		fmt.Sprintf(query, quotes.QuoteSchema(schema, table))); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	return nil
+}
+
+// resolveBDRConflicts checks rows of the buffered events against the
+// downstream in the transaction, records conflicts and returns DMLs of rows
+// which are not skipped by the conflict policy, along with the conflicts.
+// It must be called in the transaction which executes the returned DMLs, so
+// that rows read here are not changed before the transaction is committed.
+func (s *mysqlBackend) resolveBDRConflicts(
+	ctx context.Context, tx *sql.Tx,
+) (*preparedDMLs, []*bdrConflict, error) {
+	// versions holds versions of rows in the downstream, which are replaced
+	// by versions written by previous rows in the batch. A nil version means
+	// the row does not exist.
+	versions, err := s.fetchBDRVersions(ctx, tx)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	events := make([]*dmlsink.TxnCallbackableEvent, 0, len(s.events))
+	var conflicts []*bdrConflict
+	for _, event := range s.events {
+		rows := make([]*model.RowChangedEvent, 0, len(event.Event.Rows))
+		for _, row := range event.Event.Rows {
+			conflict := s.detectBDRConflict(row, versions)
+			if conflict != nil {
+				conflicts = append(conflicts, conflict)
+				if !conflict.applied {
+					continue
+				}
+			}
+			rows = append(rows, row)
+		}
+		txn := *event.Event
+		txn.Rows = rows
+		events = append(events, &dmlsink.TxnCallbackableEvent{Event: &txn})
+	}
+	if err := s.recordBDRConflicts(ctx, tx, conflicts); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	// A row which wins a conflict may exist in the downstream, so that it
+	// must be written in safe mode.
+	return s.prepareDMLsOf(events, s.cfg.SafeMode || len(conflicts) > 0), conflicts, nil
+}
+
+// bdrConflictKeys returns the handle key of the row to check against the
+// downstream, it returns nil if the row is not checked.
+func (s *mysqlBackend) bdrConflictKeys(
+	row *model.RowChangedEvent,
+) (keys []string, args []interface{}) {
+	cols := row.PreColumns
+	if row.IsInsert() {
+		cols = row.Columns
+	}
+	if !hasColumn(cols, s.cfg.BDRConflict.VersionColumn) {
+		return nil, nil
+	}
+	return whereSlice(cols, false)
+}
+
+// detectBDRConflict returns a conflict if the downstream row is changed
+// after the old value of the row, or nil if there is no conflict. Rows of
+// tables without the version column or the handle key are not checked.
+// versions must contain the version of the row, see fetchBDRVersions.
+func (s *mysqlBackend) detectBDRConflict(
+	row *model.RowChangedEvent, versions map[string]*uint64,
+) *bdrConflict {
+	cfg := s.cfg.BDRConflict
+	keys, args := s.bdrConflictKeys(row)
+	if len(keys) == 0 {
+		return nil
+	}
+	key := formatHandleKey(keys, args)
+
+	downstreamVersion := versions[bdrRowID(row.Table, keys, args)]
+	exists := downstreamVersion != nil
+	incomingVersion := row.CommitTs
+	if !row.IsDelete() {
+		if version, ok := getBDRVersion(row.Columns, cfg.VersionColumn); ok {
+			incomingVersion = version
+		}
+	}
+	var inConflict bool
+	switch {
+	case row.IsInsert():
+		// An inserted row may be written again, for example, after the
+		// changefeed restarts, it is not in conflict if the version is
+		// the same.
+		inConflict = exists && *downstreamVersion != incomingVersion
+	case !exists:
+		// The row is deleted in the downstream, which is not in conflict
+		// with an incoming delete.
+		inConflict = !row.IsDelete()
+	default:
+		oldVersion, _ := getBDRVersion(row.PreColumns, cfg.VersionColumn)
+		inConflict = *downstreamVersion != oldVersion
+	}
+	var conflict *bdrConflict
+	if inConflict {
+		conflict = &bdrConflict{
+			row: row, key: key, incomingVersion: incomingVersion,
+			downstreamVersion: downstreamVersion,
+		}
+		if exists && !row.IsDelete() {
+			conflict.applied = cfg.IncomingWins(
+				incomingVersion, *downstreamVersion, s.cfg.SourceID)
+		} else {
+			// A deleted row has no version to compare with, so a conflict
+			// between a delete and an update is resolved as a tie by the
+			// policy, which makes the same decision in both clusters.
+			conflict.applied = cfg.IncomingWins(
+				incomingVersion, incomingVersion, s.cfg.SourceID)
+		}
+		log.Warn("bdr conflict is detected",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Stringer("table", row.Table),
+			zap.String("key", key),
+			zap.String("type", conflict.eventType()),
+			zap.Uint64("commitTs", row.CommitTs),
+			zap.Uint64("incomingVersion", incomingVersion),
+			zap.Uint64p("downstreamVersion", conflict.downstreamVersion),
+			zap.String("policy", string(cfg.Policy)),
+			zap.String("resolution", conflict.resolution()))
+		if !conflict.applied {
+			return conflict
+		}
+	}
+
+	// The row is written, following rows of it in the batch must be compared
+	// with the written version instead of the one in the downstream.
+	versions[bdrRowID(row.Table, keys, args)] = nil
+	if !row.IsDelete() {
+		newKeys, newArgs := whereSlice(row.Columns, false)
+		versions[bdrRowID(row.Table, newKeys, newArgs)] = &incomingVersion
+	}
+	return conflict
+}
+
+// bdrLookup is a batch of rows of a table to read from the downstream.
+type bdrLookup struct {
+	table *model.TableName
+	keys  []string
+	// args holds values of keys of each row.
+	args [][]interface{}
+}
+
+// fetchBDRVersions reads versions of rows of the buffered events from the
+// downstream in the transaction, rows of a table are read in batches. It
+// returns versions by bdrRowID, which is nil if the row does not exist.
+func (s *mysqlBackend) fetchBDRVersions(
+	ctx context.Context, tx *sql.Tx,
+) (map[string]*uint64, error) {
+	versions := make(map[string]*uint64)
+	lookups := make(map[string]*bdrLookup)
+	var order []string
+	for _, event := range s.events {
+		for _, row := range event.Event.Rows {
+			keys, args := s.bdrConflictKeys(row)
+			if len(keys) == 0 {
+				continue
+			}
+			id := bdrRowID(row.Table, keys, args)
+			if _, ok := versions[id]; ok {
+				continue
+			}
+			versions[id] = nil
+			table := row.Table.String() + ":" + buildColumnList(keys)
+			lookup, ok := lookups[table]
+			if !ok {
+				lookup = &bdrLookup{table: row.Table, keys: keys}
+				lookups[table] = lookup
+				order = append(order, table)
+			}
+			lookup.args = append(lookup.args, args)
+		}
+	}
+
+	batchSize := s.cfg.MaxTxnRow
+	if batchSize <= 0 {
+		batchSize = pmysql.DefaultMaxTxnRow
+	}
+	for _, table := range order {
+		lookup := lookups[table]
+		for start := 0; start < len(lookup.args); start += batchSize {
+			end := start + batchSize
+			if end > len(lookup.args) {
+				end = len(lookup.args)
+			}
+			if err := s.queryBDRVersions(ctx, tx, lookup.table, lookup.keys,
+				lookup.args[start:end], versions); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	return versions, nil
+}
+
+// queryBDRVersions reads versions of the rows of a table in one query, and
+// stores them in versions.
+func (s *mysqlBackend) queryBDRVersions(
+	ctx context.Context, tx *sql.Tx, table *model.TableName,
+	keys []string, rows [][]interface{}, versions map[string]*uint64,
+) error {
+	var builder strings.Builder
+	builder.WriteString("SELECT " + buildColumnList(keys) + "," +
+		quotes.QuoteName(s.cfg.BDRConflict.VersionColumn) +
+		" FROM " + table.QuoteString() + " WHERE ")
+	args := make([]interface{}, 0, len(rows)*len(keys))
+	if len(keys) == 1 {
+		builder.WriteString(quotes.QuoteName(keys[0]) + " IN (" + placeHolder(len(rows)) + ")")
+	} else {
+		builder.WriteString("(" + buildColumnList(keys) + ") IN (")
+		for i := range rows {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			builder.WriteString("(" + placeHolder(len(keys)) + ")")
+		}
+		builder.WriteString(")")
+	}
+	for _, row := range rows {
+		args = append(args, row...)
+	}
+	builder.WriteString(" FOR UPDATE")
+
+	result, err := tx.QueryContext(ctx, builder.String(), args...)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	defer result.Close()
+	matched, returned := 0, 0
+	for result.Next() {
+		values := make([]sql.NullString, len(keys)+1)
+		dest := make([]interface{}, 0, len(values))
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := result.Scan(dest...); err != nil {
+			return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+		}
+		returned++
+		// The only returned row is the one queried, whatever its keys are.
+		id := bdrRowID(table, keys, rows[0])
+		if len(rows) > 1 {
+			keyValues := make([]interface{}, 0, len(keys))
+			for _, value := range values[:len(keys)] {
+				keyValues = append(keyValues, value.String)
+			}
+			id = bdrRowID(table, keys, keyValues)
+			if _, ok := versions[id]; !ok {
+				continue
+			}
+		}
+		var version uint64
+		if value := values[len(keys)]; value.Valid {
+			version, err = strconv.ParseUint(value.String, 10, 64)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+			}
+		}
+		versions[id] = &version
+		matched++
+	}
+	if err := result.Err(); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	if matched == returned {
+		return nil
+	}
+	// Keys returned by the downstream may be different from the incoming
+	// ones, for example, in case-insensitive collations, rows which are not
+	// matched are read one by one.
+	for _, row := range rows {
+		id := bdrRowID(table, keys, row)
+		if versions[id] != nil {
+			continue
+		}
+		if err := s.queryBDRVersions(ctx, tx, table, keys,
+			[][]interface{}{row}, versions); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// recordBDRConflicts writes conflicts to the conflict table in the transaction.
+func (s *mysqlBackend) recordBDRConflicts(
+	ctx context.Context, tx *sql.Tx, conflicts []*bdrConflict,
+) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	schema, table, _ := strings.Cut(s.cfg.BDRConflict.ConflictTable, ".")
+	query := "INSERT INTO " + quotes.QuoteSchema(schema, table) +
+		" (ticdc_cluster_id, changefeed, source_id, schema_name, table_name, row_key," +
+		" event_type, commit_ts, incoming_version, downstream_version, resolution) VALUES "
+	args := make([]interface{}, 0, len(conflicts)*11)
+	for i, c := range conflicts {
+		if i > 0 {
+			query += ","
+		}
+		query += "(" + placeHolder(11) + ")"
+		var downstreamVersion interface{}
+		if c.downstreamVersion != nil {
+			downstreamVersion = *c.downstreamVersion
+		}
+		args = append(args, config.GetGlobalServerConfig().ClusterID, s.changefeed,
+			s.cfg.SourceID, c.row.Table.Schema, c.row.Table.Table, c.key, c.eventType(),
+			c.row.CommitTs, c.incomingVersion, downstreamVersion, c.resolution())
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+	return nil
+}
+
+// countBDRConflicts updates conflict metrics, it must be called after the
+// transaction recording the conflicts is committed, since conflicts are
+// detected again if the transaction is retried.
+func (s *mysqlBackend) countBDRConflicts(conflicts []*bdrConflict) {
+	for _, c := range conflicts {
+		if c.applied {
+			s.metricBDRConflictApplied.Inc()
+		} else {
+			s.metricBDRConflictSkipped.Inc()
+		}
+	}
+}
+
+// getBDRVersion returns the version in the column, it returns false if the
+// column does not exist or its value is NULL.
+func getBDRVersion(cols []*model.Column, name string) (uint64, bool) {
+	for _, col := range cols {
+		if col == nil || !strings.EqualFold(col.Name, name) {
+			continue
+		}
+		switch v := col.Value.(type) {
+		case uint64:
+			return v, true
+		case int64:
+			return uint64(v), v >= 0
+		case []byte:
+			version, err := strconv.ParseUint(string(v), 10, 64)
+			return version, err == nil
+		case string:
+			version, err := strconv.ParseUint(v, 10, 64)
+			return version, err == nil
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+func hasColumn(cols []*model.Column, name string) bool {
+	for _, col := range cols {
+		if col != nil && strings.EqualFold(col.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// bdrRowID identifies a row of the table by its handle key, values of keys
+// are compared in their text format.
+func bdrRowID(table *model.TableName, keys []string, values []interface{}) string {
+	var b strings.Builder
+	b.WriteString(table.String())
+	for i, key := range keys {
+		b.WriteString(":" + key + "=")
+		switch v := values[i].(type) {
+		case []byte:
+			b.Write(v)
+		default:
+			fmt.Fprint(&b, v)
+		}
+	}
+	return b.String()
+}
+
+// formatHandleKey formats the handle key like "`a` = 1, `b` = x".
+func formatHandleKey(keys []string, values []interface{}) string {
+	var b strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s = %v", quotes.QuoteName(key), values[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,314 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newBDRRow(id int64, oldVersion, newVersion uint64) *model.RowChangedEvent {
+	columns := func(version uint64) []*model.Column {
+		return []*model.Column{
+			{
+				Name:  "id",
+				Type:  mysql.TypeLonglong,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+				Value: id,
+			},
+			{
+				Name:  "version",
+				Type:  mysql.TypeLonglong,
+				Flag:  model.UnsignedFlag,
+				Value: version,
+			},
+		}
+	}
+	row := &model.RowChangedEvent{
+		StartTs:  1,
+		CommitTs: 100,
+		Table:    &model.TableName{Schema: "test", Table: "t", TableID: 1},
+	}
+	if oldVersion != 0 {
+		row.PreColumns = columns(oldVersion)
+	}
+	if newVersion != 0 {
+		row.Columns = columns(newVersion)
+	}
+	return row
+}
+
+func TestBDRConflict(t *testing.T) {
+	selectVersion := regexp.QuoteMeta(
+		"SELECT `id`,`version` FROM `test`.`t` WHERE `id` IN (?,?,?,?) FOR UPDATE")
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock, err := sqlmock.New()
+		require.Nil(t, err)
+		mock.ExpectQuery("select tidb_version()").WillReturnError(&dmysql.MySQLError{
+			Number:  1305,
+			Message: "FUNCTION test.tidb_version does not exist",
+		})
+		mock.ExpectExec(regexp.QuoteMeta("CREATE DATABASE IF NOT EXISTS `tidb_cdc`")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS `tidb_cdc`.`bdr_conflict`")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		// The first commit fails, conflicts are detected again in the retry.
+		for i := 0; i < 2; i++ {
+			mock.ExpectBegin()
+			// Rows are read in one query. The row 1 is updated in the downstream,
+			// and the incoming version is larger. The row 2 is updated in the
+			// downstream, and the downstream version is larger, the second
+			// update of it is compared with the downstream row too. The row 3
+			// is deleted in the downstream. The row 4 is not in conflict.
+			mock.ExpectQuery(selectVersion).WithArgs(int64(1), int64(2), int64(3), int64(4)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).
+					AddRow("1", "15").AddRow("2", "30").AddRow("4", "10"))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `tidb_cdc`.`bdr_conflict`")).
+				WithArgs(
+					sqlmock.AnyArg(), "default.test-changefeed", uint64(1), "test", "t", "`id` = 1",
+					"update", uint64(100), uint64(20), uint64(15), bdrConflictApplied,
+					sqlmock.AnyArg(), "default.test-changefeed", uint64(1), "test", "t", "`id` = 2",
+					"update", uint64(100), uint64(12), uint64(30), bdrConflictSkipped,
+					sqlmock.AnyArg(), "default.test-changefeed", uint64(1), "test", "t", "`id` = 2",
+					"update", uint64(100), uint64(13), uint64(30), bdrConflictSkipped,
+					sqlmock.AnyArg(), "default.test-changefeed", uint64(1), "test", "t", "`id` = 3",
+					"update", uint64(100), uint64(25), nil, bdrConflictSkipped,
+				).
+				WillReturnResult(sqlmock.NewResult(4, 4))
+			// Only rows 1 and 4 are written, in safe mode.
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `test`.`t`")).
+				WithArgs(int64(1), uint64(10), int64(4), uint64(10)).
+				WillReturnResult(sqlmock.NewResult(2, 2))
+			mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO `test`.`t`")).
+				WithArgs(int64(1), uint64(20), int64(4), uint64(11)).
+				WillReturnResult(sqlmock.NewResult(2, 2))
+			if i == 0 {
+				mock.ExpectCommit().WillReturnError(&dmysql.MySQLError{
+					Number:  mysql.ErrLockDeadlock,
+					Message: "Deadlock found when trying to get lock",
+				})
+				continue
+			}
+			mock.ExpectCommit()
+		}
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = contextutil.PutChangefeedIDInCtx(ctx, model.DefaultChangeFeedID("test-changefeed"))
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1" +
+		"&cache-prep-stmts=false&multi-stmt-enable=false")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.BDRMode = true
+	replicaConfig.Sink.TiDBSourceID = 1
+	replicaConfig.BDRConflict = &config.BDRConflictConfig{
+		Policy:             config.BDRConflictPolicyLastWriterWins,
+		VersionColumn:      "version",
+		DownstreamSourceID: 2,
+	}
+	require.Nil(t, replicaConfig.BDRConflict.ValidateAndAdjust())
+	sink, err := newMySQLBackend(ctx, sinkURI, replicaConfig, mockGetDBConn)
+	require.Nil(t, err)
+
+	flushed := false
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{
+			newBDRRow(1, 10, 20),
+			newBDRRow(2, 10, 12),
+			newBDRRow(2, 12, 13),
+			newBDRRow(3, 10, 25),
+			newBDRRow(4, 10, 11),
+		}},
+		Callback: func() { flushed = true },
+	})
+	require.Nil(t, sink.Flush(ctx))
+	require.True(t, flushed)
+	// Conflicts are counted once after the transaction is committed.
+	require.Equal(t, float64(1), testutil.ToFloat64(sink.metricBDRConflictApplied))
+	require.Equal(t, float64(3), testutil.ToFloat64(sink.metricBDRConflictSkipped))
+	require.Nil(t, sink.Close())
+}
+
+func TestGetBDRVersion(t *testing.T) {
+	t.Parallel()
+
+	cols := []*model.Column{
+		{Name: "id", Value: int64(1)},
+		{Name: "Version", Value: []byte("42")},
+		{Name: "v2", Value: int64(-1)},
+		{Name: "v3", Value: nil},
+	}
+	version, ok := getBDRVersion(cols, "version")
+	require.True(t, ok)
+	require.Equal(t, uint64(42), version)
+	_, ok = getBDRVersion(cols, "v2")
+	require.False(t, ok)
+	_, ok = getBDRVersion(cols, "v3")
+	require.False(t, ok)
+	require.True(t, hasColumn(cols, "v3"))
+	_, ok = getBDRVersion(cols, "v4")
+	require.False(t, ok)
+}
+
+func TestBDRConflictOfMissingRow(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		policy         config.BDRConflictPolicy
+		sourcePriority []uint64
+		// applied is the resolution of an update of a row which is deleted
+		// in the downstream.
+		applied bool
+	}{
+		// The source id of the incoming write is smaller.
+		{policy: config.BDRConflictPolicyLastWriterWins, applied: false},
+		{policy: config.BDRConflictPolicySourcePriority, sourcePriority: []uint64{1, 2}, applied: true},
+		{policy: config.BDRConflictPolicySourcePriority, sourcePriority: []uint64{2, 1}, applied: false},
+		{policy: config.BDRConflictPolicyLogAndSkip, applied: false},
+	}
+	for _, c := range cases {
+		cfg := pmysql.NewConfig()
+		cfg.SourceID = 1
+		cfg.BDRConflict = &config.BDRConflictConfig{
+			Policy:             c.policy,
+			VersionColumn:      "version",
+			DownstreamSourceID: 2,
+			SourcePriority:     c.sourcePriority,
+		}
+		require.Nil(t, cfg.BDRConflict.ValidateAndAdjust())
+		s := &mysqlBackend{cfg: cfg}
+		table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+		missing := func() map[string]*uint64 {
+			return map[string]*uint64{
+				bdrRowID(table, []string{"id"}, []interface{}{int64(1)}): nil,
+			}
+		}
+
+		// An update of the missing row is resolved by the policy, the row is
+		// written again if the update is applied.
+		versions := missing()
+		conflict := s.detectBDRConflict(newBDRRow(1, 10, 20), versions)
+		require.NotNil(t, conflict, c.policy)
+		require.Nil(t, conflict.downstreamVersion)
+		require.Equal(t, c.applied, conflict.applied, c.policy)
+		version := versions[bdrRowID(table, []string{"id"}, []interface{}{int64(1)})]
+		if c.applied {
+			require.Equal(t, uint64(20), *version)
+		} else {
+			require.Nil(t, version)
+		}
+
+		// A delete of the missing row is not in conflict.
+		require.Nil(t, s.detectBDRConflict(newBDRRow(1, 10, 0), missing()), c.policy)
+		// An insert of the missing row is not in conflict.
+		require.Nil(t, s.detectBDRConflict(newBDRRow(1, 0, 10), missing()), c.policy)
+	}
+}
+
+func TestBDRConflictOfDelete(t *testing.T) {
+	t.Parallel()
+
+	cfg := pmysql.NewConfig()
+	cfg.SourceID = 1
+	cfg.BDRConflict = &config.BDRConflictConfig{
+		Policy:             config.BDRConflictPolicyLastWriterWins,
+		VersionColumn:      "version",
+		DownstreamSourceID: 2,
+	}
+	require.Nil(t, cfg.BDRConflict.ValidateAndAdjust())
+	s := &mysqlBackend{cfg: cfg}
+	table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+	downstreamVersion := uint64(20)
+	versions := map[string]*uint64{
+		bdrRowID(table, []string{"id"}, []interface{}{int64(1)}): &downstreamVersion,
+	}
+
+	// A delete of a row updated in the downstream is resolved as a tie,
+	// the same as an update of a row deleted in the downstream.
+	conflict := s.detectBDRConflict(newBDRRow(1, 10, 0), versions)
+	require.NotNil(t, conflict)
+	require.Equal(t, downstreamVersion, *conflict.downstreamVersion)
+	require.False(t, conflict.applied)
+}
+
+func TestQueryBDRVersions(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer db.Close()
+	cfg := pmysql.NewConfig()
+	cfg.BDRConflict = &config.BDRConflictConfig{VersionColumn: "version"}
+	s := &mysqlBackend{cfg: cfg}
+	table := &model.TableName{Schema: "test", Table: "t", TableID: 1}
+	keys := []string{"a", "b"}
+	rows := [][]interface{}{{int64(1), "x"}, {int64(2), "y"}, {int64(3), "z"}}
+	versions := make(map[string]*uint64)
+	for _, row := range rows {
+		versions[bdrRowID(table, keys, row)] = nil
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `a`,`b`,`version` FROM `test`.`t` "+
+		"WHERE (`a`,`b`) IN ((?,?),(?,?),(?,?)) FOR UPDATE")).
+		WithArgs(int64(1), "x", int64(2), "y", int64(3), "z").
+		WillReturnRows(sqlmock.NewRows([]string{"a", "b", "version"}).
+			AddRow("1", "x", "10").AddRow("2", "Y", "20"))
+	// The key returned in another case is read again.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `a`,`b`,`version` FROM `test`.`t` "+
+		"WHERE (`a`,`b`) IN ((?,?)) FOR UPDATE")).
+		WithArgs(int64(2), "y").
+		WillReturnRows(sqlmock.NewRows([]string{"a", "b", "version"}).AddRow("2", "Y", "20"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `a`,`b`,`version` FROM `test`.`t` "+
+		"WHERE (`a`,`b`) IN ((?,?)) FOR UPDATE")).
+		WithArgs(int64(3), "z").
+		WillReturnRows(sqlmock.NewRows([]string{"a", "b", "version"}))
+	mock.ExpectRollback()
+	tx, err := db.Begin()
+	require.Nil(t, err)
+	require.Nil(t, s.queryBDRVersions(context.Background(), tx, table, keys, rows, versions))
+	require.Equal(t, uint64(10), *versions[bdrRowID(table, keys, rows[0])])
+	require.Equal(t, uint64(20), *versions[bdrRowID(table, keys, rows[1])])
+	require.Nil(t, versions[bdrRowID(table, keys, rows[2])])
+	require.Nil(t, tx.Rollback())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
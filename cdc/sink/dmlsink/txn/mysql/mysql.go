@@ -73,6 +73,8 @@ type mysqlBackend struct {
 	metricTxnSinkDMLBatchCommit     prometheus.Observer
 	metricTxnSinkDMLBatchCallback   prometheus.Observer
 	metricTxnPrepareStatementErrors prometheus.Counter
+	metricBDRConflictApplied        prometheus.Counter
+	metricBDRConflictSkipped        prometheus.Counter
 
 	// implement stmtCache to improve performance, especially when the downstream is TiDB
 	stmtCache *lru.Cache
@@ -113,6 +115,12 @@ func NewMySQLBackends(
 		return nil, err
 	}
 
+	if cfg.BDRConflict != nil {
+		if err := createBDRConflictTable(ctx, db, cfg.BDRConflict.ConflictTable); err != nil {
+			return nil, err
+		}
+	}
+
 	// By default, cache-prep-stmts=true, an LRU cache is used for prepared statements,
 	// two connections are required to process a transaction.
 	// The first connection is held in the tx variable, which is used to manage the transaction.
@@ -182,6 +190,8 @@ func NewMySQLBackends(
 			metricTxnSinkDMLBatchCommit:     txn.SinkDMLBatchCommit.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 			metricTxnSinkDMLBatchCallback:   txn.SinkDMLBatchCallback.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 			metricTxnPrepareStatementErrors: txn.PrepareStatementErrors.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+			metricBDRConflictApplied:        txn.BDRConflictCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID, bdrConflictApplied),
+			metricBDRConflictSkipped:        txn.BDRConflictCount.WithLabelValues(changefeedID.Namespace, changefeedID.ID, bdrConflictSkipped),
 			stmtCache:                       stmtCache,
 			cachePrepStmts:                  cachePrepStmts,
 			maxAllowedPacket:                maxAllowedPacket,
@@ -520,19 +530,26 @@ func hasHandleKey(cols []*model.Column) bool {
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+	return s.prepareDMLsOf(s.events, s.cfg.SafeMode)
+}
+
+// prepareDMLsOf converts rows of the events to DMLs.
+func (s *mysqlBackend) prepareDMLsOf(
+	events []*dmlsink.TxnCallbackableEvent, safeMode bool,
+) *preparedDMLs {
 	// TODO: use a sync.Pool to reduce allocations.
 	startTs := make([]uint64, 0, s.rows)
 	sqls := make([]string, 0, s.rows)
 	values := make([][]interface{}, 0, s.rows)
-	callbacks := make([]dmlsink.CallbackFunc, 0, len(s.events))
+	callbacks := make([]dmlsink.CallbackFunc, 0, len(events))
 
 	// translateToInsert control the update and insert behavior
 	// we only translate into insert when old value is enabled and safe mode is disabled
-	translateToInsert := s.cfg.EnableOldValue && !s.cfg.SafeMode
+	translateToInsert := s.cfg.EnableOldValue && !safeMode
 
 	rowCount := 0
 	approximateSize := int64(0)
-	for _, event := range s.events {
+	for _, event := range events {
 		if len(event.Event.Rows) == 0 {
 			continue
 		}
@@ -552,7 +569,7 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			zap.Uint64("firstRowCommitTs", firstRow.CommitTs),
 			zap.Uint64("firstRowReplicatingTs", firstRow.ReplicatingTs),
 			zap.Bool("enableOldValue", s.cfg.EnableOldValue),
-			zap.Bool("safeMode", s.cfg.SafeMode))
+			zap.Bool("safeMode", safeMode))
 
 		if event.Callback != nil {
 			callbacks = append(callbacks, event.Callback)
@@ -759,19 +776,40 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 					start, s.changefeed, "BEGIN", dmls.rowCount, dmls.startTs)
 			}
 
+			// Rows skipped by the bdr conflict policy are not executed, but
+			// callbacks of all rows are still called after the transaction.
+			execDMLs := dmls
+			var conflicts []*bdrConflict
+			if s.cfg.BDRConflict != nil {
+				execDMLs, conflicts, err = s.resolveBDRConflicts(pctx, tx)
+				if err != nil {
+					err := logDMLTxnErr(err, start, s.changefeed,
+						"resolve bdr conflicts", dmls.rowCount, dmls.startTs)
+					if rbErr := tx.Rollback(); rbErr != nil {
+						if errors.Cause(rbErr) != context.Canceled {
+							log.Warn("failed to rollback txn", zap.Error(rbErr))
+						}
+					}
+					return 0, err
+				}
+			}
+
 			// If interplated SQL size exceeds maxAllowedPacket, mysql driver will
 			// fall back to the sequantial way.
 			// error can be ErrPrepareMulti, ErrBadConn etc.
 			// TODO: add a quick path to check whether we should fallback to
 			// the sequence way.
-			if s.cfg.MultiStmtEnable && !fallbackToSeqWay {
-				err = s.multiStmtExecute(pctx, dmls, tx, writeTimeout)
+			switch {
+			case len(execDMLs.sqls) == 0:
+				// All rows are skipped by the bdr conflict policy.
+			case s.cfg.MultiStmtEnable && !fallbackToSeqWay:
+				err = s.multiStmtExecute(pctx, execDMLs, tx, writeTimeout)
 				if err != nil {
 					fallbackToSeqWay = true
 					return 0, err
 				}
-			} else {
-				err = s.sequenceExecute(pctx, dmls, tx, writeTimeout)
+			default:
+				err = s.sequenceExecute(pctx, execDMLs, tx, writeTimeout)
 				if err != nil {
 					return 0, err
 				}
@@ -799,6 +837,7 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 					cerror.WrapError(cerror.ErrMySQLTxnError, err),
 					start, s.changefeed, "COMMIT", dmls.rowCount, dmls.startTs)
 			}
+			s.countBDRConflicts(conflicts)
 			return dmls.rowCount, nil
 		})
 		if err != nil {
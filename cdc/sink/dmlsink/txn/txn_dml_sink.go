@@ -46,6 +46,10 @@ type dmlSink struct {
 	workers          []*worker
 	cancel           func()
 
+	// globalTxns is only used with global level atomicity. It reassembles
+	// upstream transactions spanning multiple tables.
+	globalTxns *globalTxnAssembler
+
 	wg     sync.WaitGroup
 	dead   chan struct{}
 	isDead atomic.Bool
@@ -83,6 +87,9 @@ func NewMySQLSink(
 	sink := newSink(ctx, backends, errCh, conflictDetectorSlots)
 	sink.statistics = statistics
 	sink.cancel = cancel
+	if replicaConfig.Sink.TxnAtomicity.IsGlobal() {
+		sink.globalTxns = newGlobalTxnAssembler()
+	}
 
 	return sink, nil
 }
@@ -136,11 +143,28 @@ func (s *dmlSink) WriteEvents(txnEvents ...*dmlsink.TxnCallbackableEvent) error
 			continue
 		}
 
+		if s.globalTxns != nil {
+			// Wait until all parts of the upstream transaction arrive.
+			s.globalTxns.add(txn)
+			continue
+		}
 		s.conflictDetector.Add(newTxnEvent(txn))
 	}
 	return nil
 }
 
+// UpdateGlobalResolvedTs implements dmlsink.GlobalResolvedTsUpdater.
+// It must not be called concurrently, otherwise transactions could be
+// added into the conflict detector out of order.
+func (s *dmlSink) UpdateGlobalResolvedTs(ts model.Ts) {
+	if s.globalTxns == nil || s.isDead.Load() {
+		return
+	}
+	for _, parts := range s.globalTxns.resolve(ts) {
+		s.conflictDetector.Add(newGlobalTxnEvent(parts))
+	}
+}
+
 // Close closes the dmlSink. It won't wait for all pending items backend handled.
 func (s *dmlSink) Close() {
 	if s.cancel != nil {
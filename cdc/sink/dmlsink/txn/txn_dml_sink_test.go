@@ -16,6 +16,7 @@ package txn
 import (
 	"context"
 	"sort"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -105,6 +106,103 @@ func TestTxnSinkNolocking(t *testing.T) {
 	sink.Close()
 }
 
+// recorder records events of every flush.
+type recorder struct {
+	sync.Mutex
+	pending []*dmlsink.TxnCallbackableEvent
+	flushed [][]*dmlsink.TxnCallbackableEvent
+}
+
+func (r *recorder) OnTxnEvent(e *dmlsink.TxnCallbackableEvent) bool {
+	r.Lock()
+	defer r.Unlock()
+	r.pending = append(r.pending, e)
+	// Ask for flushing after each event to check parts of a global
+	// transaction are still flushed together.
+	return true
+}
+
+func (r *recorder) Flush(ctx context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.pending) > 0 {
+		for _, e := range r.pending {
+			e.Callback()
+		}
+		r.flushed = append(r.flushed, r.pending)
+		r.pending = nil
+	}
+	return nil
+}
+
+func (r *recorder) MaxFlushInterval() time.Duration {
+	return 100 * time.Millisecond
+}
+
+func (r *recorder) Close() error {
+	return nil
+}
+
+func TestTxnSinkGlobalAtomicity(t *testing.T) {
+	t.Parallel()
+
+	be := &recorder{}
+	errCh := make(chan error, 1)
+	sink := newSink(context.Background(), []backend{be}, errCh, DefaultConflictDetectorSlots)
+	sink.globalTxns = newGlobalTxnAssembler()
+	defer sink.Close()
+
+	var handled uint32 = 0
+	newEvent := func(table string, startTs, commitTs model.Ts) *dmlsink.TxnCallbackableEvent {
+		sinkState := new(state.TableSinkState)
+		*sinkState = state.TableSinkSinking
+		return &dmlsink.CallbackableEvent[*model.SingleTableTxn]{
+			Event: &model.SingleTableTxn{
+				StartTs:  startTs,
+				CommitTs: commitTs,
+				Table:    &model.TableName{Schema: "test", Table: table},
+				Rows: []*model.RowChangedEvent{
+					{
+						StartTs:  startTs,
+						CommitTs: commitTs,
+						Table:    &model.TableName{Schema: "test", Table: table},
+						Columns:  []*model.Column{{Name: "a", Value: 1}},
+					},
+				},
+			},
+			Callback:  func() { atomic.AddUint32(&handled, 1) },
+			SinkState: sinkState,
+		}
+	}
+
+	require.Nil(t, sink.WriteEvents(newEvent("t1", 1, 2), newEvent("t1", 3, 4)))
+	require.Nil(t, sink.WriteEvents(newEvent("t2", 1, 2), newEvent("t3", 1, 2)))
+
+	// Nothing is written before the global resolved ts is updated.
+	time.Sleep(200 * time.Millisecond)
+	require.Equal(t, uint32(0), atomic.LoadUint32(&handled))
+
+	sink.UpdateGlobalResolvedTs(3)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint32(&handled) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	be.Lock()
+	require.Len(t, be.flushed, 1)
+	tables := make([]string, 0, 3)
+	for _, e := range be.flushed[0] {
+		require.Equal(t, model.Ts(2), e.Event.CommitTs)
+		tables = append(tables, e.Event.Table.Table)
+	}
+	be.Unlock()
+	sort.Strings(tables)
+	require.Equal(t, []string{"t1", "t2", "t3"}, tables)
+
+	sink.UpdateGlobalResolvedTs(4)
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint32(&handled) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestGenKeys(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -148,21 +148,29 @@ func (w *worker) runLoop() error {
 // It returns true if the event is sent to backend.
 func (w *worker) onEvent(txn txnWithNotifier) bool {
 	w.hasPending = true
-
-	if txn.txnEvent.GetTableSinkState() != state.TableSinkSinking {
-		// The table where the event comes from is in stopping, so it's safe
-		// to drop the event directly.
-		txn.txnEvent.Callback()
-		// Still necessary to append the wantMore callback into the pending list.
-		w.wantMoreCallbacks = append(w.wantMoreCallbacks, txn.wantMore)
-		return false
-	}
+	// Still necessary to append the wantMore callback into the pending list
+	// even if all events are dropped.
+	w.wantMoreCallbacks = append(w.wantMoreCallbacks, txn.wantMore)
 
 	w.metricConflictDetectDuration.Observe(txn.conflictResolved.Sub(txn.start).Seconds())
 	w.metricQueueDuration.Observe(time.Since(txn.start).Seconds())
-	w.metricTxnWorkerHandledRows.Add(float64(len(txn.Event.Rows)))
-	w.wantMoreCallbacks = append(w.wantMoreCallbacks, txn.wantMore)
-	return w.backend.OnTxnEvent(txn.txnEvent.TxnCallbackableEvent)
+
+	// All parts of a global transaction are handed to the backend before
+	// flushing, so that they are written in one downstream transaction.
+	needFlush := false
+	for _, part := range txn.parts() {
+		if part.GetTableSinkState() != state.TableSinkSinking {
+			// The table where the event comes from is in stopping, so it's safe
+			// to drop the event directly.
+			part.Callback()
+			continue
+		}
+		w.metricTxnWorkerHandledRows.Add(float64(len(part.Event.Rows)))
+		if w.backend.OnTxnEvent(part) {
+			needFlush = true
+		}
+	}
+	return needFlush
 }
 
 // doFlush flushes the backend.
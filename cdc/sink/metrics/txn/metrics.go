@@ -87,6 +87,14 @@ var (
 			Name:      "txn_prepare_statement_errors",
 			Help:      "Prepare statement errors",
 		}, []string{"namespace", "changefeed"})
+
+	BDRConflictCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_bdr_conflict_count",
+			Help:      "The number of rows in conflict in BDR mode",
+		}, []string{"namespace", "changefeed", "resolution"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -99,4 +107,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(SinkDMLBatchCommit)
 	registry.MustRegister(SinkDMLBatchCallback)
 	registry.MustRegister(PrepareStatementErrors)
+	registry.MustRegister(BDRConflictCount)
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/util"
 )
@@ -40,6 +41,10 @@ func Validate(ctx context.Context, sinkURI string, cfg *config.ReplicaConfig) er
 		return err
 	}
 
+	if err := checkLargeMessageHandle(uri, cfg); err != nil {
+		return err
+	}
+
 	if cfg.BDRMode {
 		err := checkBDRMode(ctx, uri, cfg)
 		if err != nil {
@@ -59,20 +64,100 @@ func Validate(ctx context.Context, sinkURI string, cfg *config.ReplicaConfig) er
 	return nil
 }
 
+// ValidateFinishMarker checks if the sink supports the on-finish marker, it
+// is used when the on-finish config of a running changefeed is updated.
+func ValidateFinishMarker(sinkURI string, cfg *config.ReplicaConfig) error {
+	uri, err := preCheckSinkURI(sinkURI)
+	if err != nil {
+		return err
+	}
+	return checkSyncPointSchemeCompatibility(uri, cfg)
+}
+
 // checkSyncPointSchemeCompatibility checks if the sink scheme is compatible
-// with the syncpoint feature.
+// with the syncpoint feature. The on-finish marker is written as a syncpoint,
+// so it is checked as well.
 func checkSyncPointSchemeCompatibility(
 	uri *url.URL,
 	cfg *config.ReplicaConfig,
 ) error {
-	if cfg.EnableSyncPoint && !sink.IsMySQLCompatibleScheme(uri.Scheme) {
+	feature := "syncpoint enabled"
+	if !cfg.EnableSyncPoint {
+		if !cfg.OnFinish.NeedWriteMarker() {
+			return nil
+		}
+		feature = "on-finish write-marker enabled"
+	}
+	switch {
+	case sink.IsMySQLCompatibleScheme(uri.Scheme), sink.IsStorageScheme(uri.Scheme):
+		return nil
+	case sink.IsMQScheme(uri.Scheme):
+		// Syncpoint markers are only sent by protocols which can encode them.
+		protocolStr := uri.Query().Get(config.ProtocolKey)
+		if protocolStr == "" {
+			protocolStr = cfg.Sink.Protocol
+		}
+		protocol, err := config.ParseSinkProtocolFromString(protocolStr)
+		if err != nil {
+			return err
+		}
+		encoderConfig := common.NewConfig(protocol)
+		if err := encoderConfig.Apply(uri, cfg); err != nil {
+			return err
+		}
+		if protocol == config.ProtocolOpen ||
+			(protocol == config.ProtocolCanalJSON && encoderConfig.EnableTiDBExtension) {
+			return nil
+		}
+		return cerror.ErrSinkURIInvalid.
+			GenWithStack(
+				"protocol %s is not supported with %s, "+
+					"only open-protocol and canal-json with tidb extension are supported, "+
+					"sink uri: %s", protocol, feature, uri,
+			)
+	default:
 		return cerror.ErrSinkURIInvalid.
 			GenWithStack(
-				"sink uri scheme is not supported with syncpoint enabled"+
-					"sink uri: %s", uri,
+				"sink uri scheme is not supported with %s"+
+					"sink uri: %s", feature, uri,
 			)
 	}
-	return nil
+}
+
+// checkLargeMessageHandle checks if messages sent by claim check can be
+// decoded by consumers. Only decoders of open-protocol and canal-json with
+// tidb extension read messages from the claim check storage, the other
+// decoders, such as craft, can not decode reference messages.
+func checkLargeMessageHandle(uri *url.URL, cfg *config.ReplicaConfig) error {
+	if !sink.IsMQScheme(uri.Scheme) {
+		return nil
+	}
+	protocolStr := uri.Query().Get(config.ProtocolKey)
+	if protocolStr == "" {
+		protocolStr = cfg.Sink.Protocol
+	}
+	protocol, err := config.ParseSinkProtocolFromString(protocolStr)
+	if err != nil {
+		return err
+	}
+	encoderConfig := common.NewConfig(protocol)
+	if err := encoderConfig.Apply(uri, cfg); err != nil {
+		return err
+	}
+	if !encoderConfig.ClaimCheckEnabled() {
+		return nil
+	}
+	if protocol == config.ProtocolOpen || protocol == config.ProtocolDefault ||
+		(protocol == config.ProtocolCanalJSON && encoderConfig.EnableTiDBExtension) {
+		return nil
+	}
+	return cerror.ErrSinkURIInvalid.
+		GenWithStack(
+			"protocol %s is not supported with large-message-handle-option claim-check, "+
+				"messages in the claim check storage can only be decoded by "+
+				"open-protocol and canal-json with tidb extension, "+
+				"sink uri: %s", protocol, uri,
+		)
 }
 
 // preCheckSinkURI do some pre-check for sink URI.
@@ -15,6 +15,7 @@ package validator
 
 import (
 	"context"
+	"net/url"
 	"testing"
 
 	"github.com/pingcap/tiflow/pkg/config"
@@ -117,7 +118,7 @@ func TestValidateSink(t *testing.T) {
 
 	// test sink-scheme/syncpoint error
 	replicateConfig.EnableSyncPoint = true
-	sinkURI = "kafka://"
+	sinkURI = "blackhole://"
 	err = Validate(ctx, sinkURI, replicateConfig)
 	require.NotNil(t, err)
 	require.Contains(
@@ -125,3 +126,81 @@ func TestValidateSink(t *testing.T) {
 		"sink uri scheme is not supported with syncpoint enabled",
 	)
 }
+
+func TestCheckSyncPointSchemeCompatibility(t *testing.T) {
+	t.Parallel()
+
+	replicateConfig := config.GetDefaultReplicaConfig()
+	replicateConfig.EnableSyncPoint = true
+	for _, tc := range []struct {
+		sinkURI string
+		err     string
+	}{
+		{sinkURI: "mysql://127.0.0.1:3306/"},
+		{sinkURI: "s3://bucket/prefix?protocol=csv"},
+		{sinkURI: "kafka://127.0.0.1:9092/test?protocol=open-protocol"},
+		{sinkURI: "kafka://127.0.0.1:9092/test?protocol=canal-json&enable-tidb-extension=true"},
+		{
+			sinkURI: "kafka://127.0.0.1:9092/test?protocol=canal-json",
+			err:     "protocol canal-json is not supported with syncpoint enabled",
+		},
+		{
+			sinkURI: "kafka://127.0.0.1:9092/test?protocol=maxwell",
+			err:     "protocol maxwell is not supported with syncpoint enabled",
+		},
+		{
+			sinkURI: "blackhole://",
+			err:     "sink uri scheme is not supported with syncpoint enabled",
+		},
+	} {
+		uri, err := url.Parse(tc.sinkURI)
+		require.NoError(t, err)
+		err = checkSyncPointSchemeCompatibility(uri, replicateConfig)
+		if tc.err == "" {
+			require.NoError(t, err, tc.sinkURI)
+		} else {
+			require.ErrorContains(t, err, tc.err, tc.sinkURI)
+		}
+	}
+}
+
+func TestValidateFinishMarker(t *testing.T) {
+	t.Parallel()
+
+	replicateConfig := config.GetDefaultReplicaConfig()
+	require.NoError(t, ValidateFinishMarker("blackhole://", replicateConfig))
+
+	// The finish marker is written as a syncpoint.
+	replicateConfig.OnFinish.WriteMarker = true
+	require.NoError(t, ValidateFinishMarker("mysql://127.0.0.1:3306/", replicateConfig))
+	require.NoError(t, ValidateFinishMarker("s3://bucket/prefix?protocol=csv", replicateConfig))
+	require.NoError(t, ValidateFinishMarker(
+		"kafka://127.0.0.1:9092/test?protocol=open-protocol", replicateConfig))
+	require.ErrorContains(t, ValidateFinishMarker(
+		"kafka://127.0.0.1:9092/test?protocol=maxwell", replicateConfig),
+		"protocol maxwell is not supported with on-finish write-marker enabled")
+	require.ErrorContains(t, ValidateFinishMarker("blackhole://", replicateConfig),
+		"sink uri scheme is not supported with on-finish write-marker enabled")
+}
+
+func TestCheckLargeMessageHandle(t *testing.T) {
+	t.Parallel()
+
+	replicateConfig := config.GetDefaultReplicaConfig()
+	check := func(sinkURI string) error {
+		uri, err := url.Parse(sinkURI)
+		require.NoError(t, err)
+		return checkLargeMessageHandle(uri, replicateConfig)
+	}
+	require.NoError(t, check("kafka://127.0.0.1:9092/test?protocol=craft"))
+	require.NoError(t, check("mysql://127.0.0.1:3306/?large-message-handle-option=claim-check"))
+
+	claimCheck := "&large-message-handle-option=claim-check&claim-check-storage-uri=file:///tmp/claim-check"
+	require.NoError(t, check("kafka://127.0.0.1:9092/test?protocol=open-protocol"+claimCheck))
+	require.NoError(t, check(
+		"kafka://127.0.0.1:9092/test?protocol=canal-json&enable-tidb-extension=true"+claimCheck))
+	require.ErrorContains(t, check("kafka://127.0.0.1:9092/test?protocol=craft"+claimCheck),
+		"protocol craft is not supported with large-message-handle-option claim-check")
+	require.ErrorContains(t, check("kafka://127.0.0.1:9092/test?protocol=canal-json"+claimCheck),
+		"protocol canal-json is not supported with large-message-handle-option claim-check")
+}
@@ -0,0 +1,320 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/factory"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/tablesink"
+	tsink "github.com/pingcap/tiflow/cdc/sink/tablesink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// flushCheckInterval is the interval to check whether a chunk is flushed.
+	flushCheckInterval = 10 * time.Millisecond
+	// defaultChunkRows, defaultConcurrency and defaultMemoryQuota are used if
+	// the snapshot config is removed from the changefeed after the export is
+	// started.
+	defaultChunkRows   = 10000
+	defaultConcurrency = 4
+	defaultMemoryQuota = 256 * 1024 * 1024
+)
+
+// Exporter exports a consistent snapshot of a span at the snapshot ts through
+// the sink of a changefeed. It is used by processors to export the initial
+// snapshot of a changefeed and the snapshot of a backfilled table, before the
+// span is replicated. The span is exported in chunks, and the progress is
+// recorded after each chunk is flushed, so that the export can be resumed from
+// the last flushed chunk.
+//
+// Rows are written as inserts with commit ts equal to the snapshot ts, so the
+// incremental replication can start from the snapshot ts seamlessly.
+type Exporter struct {
+	changefeedID model.ChangeFeedID
+	info         *model.ChangeFeedInfo
+	kvStorage    tidbkv.Storage
+	tz           *time.Location
+	// chunkBytes is the max approximate bytes of rows in a chunk.
+	chunkBytes int
+
+	mu       sync.Mutex
+	progress *model.SpanSnapshotProgress
+}
+
+// NewExporter creates an Exporter which resumes from the given progress of
+// a span.
+func NewExporter(
+	changefeedID model.ChangeFeedID,
+	info *model.ChangeFeedInfo,
+	kvStorage tidbkv.Storage,
+	tz *time.Location,
+	progress *model.SpanSnapshotProgress,
+) *Exporter {
+	e := &Exporter{
+		changefeedID: changefeedID,
+		info:         info,
+		kvStorage:    kvStorage,
+		tz:           tz,
+		progress:     progress.Clone(),
+	}
+	_, concurrency, memoryQuota := Limits(info.Config.Snapshot)
+	// Each span being exported holds at most one chunk in memory.
+	e.chunkBytes = int(memoryQuota / uint64(concurrency))
+	return e
+}
+
+// Limits returns the max rows of a chunk, the max number of spans exported at
+// the same time by a processor and the memory quota of them.
+func Limits(cfg *config.SnapshotConfig) (chunkRows, concurrency int, memoryQuota uint64) {
+	chunkRows, concurrency, memoryQuota = defaultChunkRows, defaultConcurrency, defaultMemoryQuota
+	if cfg == nil {
+		return
+	}
+	if cfg.ChunkRows > 0 {
+		chunkRows = cfg.ChunkRows
+	}
+	if cfg.Concurrency > 0 {
+		concurrency = cfg.Concurrency
+	}
+	if cfg.MemoryQuota > 0 {
+		memoryQuota = cfg.MemoryQuota
+	}
+	return
+}
+
+// Progress returns a copy of the current progress.
+func (e *Exporter) Progress() *model.SpanSnapshotProgress {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.progress.Clone()
+}
+
+// Run exports the rows of the span which are not exported yet.
+// It returns nil after the span is exported.
+func (e *Exporter) Run(ctx context.Context) error {
+	progress := e.Progress()
+	if progress.Done {
+		return nil
+	}
+	span := progress.Span()
+	snapshotTs := progress.SnapshotTs
+	log.Info("start to export snapshot of span",
+		zap.String("namespace", e.changefeedID.Namespace),
+		zap.String("changefeed", e.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Uint64("snapshotTs", snapshotTs),
+		zap.Bool("resumed", len(progress.ResumeKey) != 0))
+
+	cfg := e.info.Config
+	f, err := filter.NewFilter(cfg, util.GetTimeZoneName(e.tz))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Rows committed at ts are decoded with the schema at ts-1 by the mounter,
+	// and rows in the snapshot are treated as committed at the snapshot ts.
+	schemaTs := snapshotTs - 1
+	meta, err := kv.GetSnapshotMeta(e.kvStorage, schemaTs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	schemaStorage, err := entry.NewSchemaStorage(
+		meta, schemaTs, cfg.ForceReplicate, e.changefeedID, util.RoleProcessor, f)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	sinkErrCh := make(chan error, 16)
+	sinkFactory, err := factory.New(ctx, e.info.SinkURI, cfg, sinkErrCh)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer sinkFactory.Close()
+	tableSink := sinkFactory.CreateTableSink(e.changefeedID, span, snapshotTs,
+		tablesink.TotalRowsCountCounter.WithLabelValues(e.changefeedID.Namespace, e.changefeedID.ID))
+	defer tableSink.Close()
+
+	mounter := entry.NewMounter(schemaStorage, e.changefeedID, e.tz, f,
+		cfg.EnableOldValue, cfg.Integrity)
+	snap := e.kvStorage.GetSnapshot(tidbkv.NewVersion(snapshotTs))
+	snap.SetOption(tidbkv.Priority, tidbkv.PriorityLow)
+	start, end, err := decodeSpan(span)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	chunkRows, _, _ := Limits(cfg.Snapshot)
+	w := &spanExporter{
+		snapshotTs: snapshotTs,
+		start:      start,
+		end:        end,
+		chunkRows:  chunkRows,
+		chunkBytes: e.chunkBytes,
+		snap:       snap,
+		mounter:    mounter,
+	}
+
+	// Chunks share the same commit ts, so batch resolved ts is used to flush
+	// them one by one.
+	resolvedTs := model.ResolvedTs{Mode: model.BatchResolvedMode, Ts: snapshotTs, BatchID: 1}
+	resumeKey := progress.ResumeKey
+	for {
+		rows, lastKey, done, err := w.scanChunk(ctx, resumeKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(rows) > 0 {
+			tableSink.AppendRowChangedEvents(rows...)
+			if err := tableSink.UpdateResolvedTs(resolvedTs); err != nil {
+				return errors.Trace(err)
+			}
+			sinkFactory.UpdateGlobalResolvedTs(snapshotTs)
+			if err := waitFlushed(ctx, tableSink, resolvedTs, sinkErrCh); err != nil {
+				return errors.Trace(err)
+			}
+			resolvedTs = resolvedTs.AdvanceBatch()
+		}
+		e.updateProgress(lastKey, len(rows), done)
+		if done {
+			break
+		}
+		resumeKey = lastKey
+	}
+	log.Info("snapshot of span is exported",
+		zap.String("namespace", e.changefeedID.Namespace),
+		zap.String("changefeed", e.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Uint64("snapshotTs", snapshotTs),
+		zap.Uint64("rows", e.Progress().ExportedRows))
+	return nil
+}
+
+// updateProgress records a flushed chunk of the span.
+func (e *Exporter) updateProgress(lastKey []byte, rows int, done bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if lastKey != nil {
+		e.progress.ResumeKey = lastKey
+	}
+	e.progress.ExportedRows += uint64(rows)
+	e.progress.Done = done
+}
+
+// spanExporter scans the snapshot of a span chunk by chunk.
+type spanExporter struct {
+	snapshotTs model.Ts
+	// start and end are the raw keys of the span.
+	start     tidbkv.Key
+	end       tidbkv.Key
+	chunkRows int
+	// chunkBytes is the max approximate bytes of rows in a chunk.
+	chunkBytes int
+	snap       tidbkv.Snapshot
+	mounter    entry.Mounter
+}
+
+// scanChunk scans at most chunkRows keys after resumeKey, and stops once rows
+// of the chunk exceed chunkBytes. It returns the decoded rows, the last
+// scanned key and whether the table is finished.
+func (w *spanExporter) scanChunk(
+	ctx context.Context, resumeKey []byte,
+) ([]*model.RowChangedEvent, []byte, bool, error) {
+	start := w.start
+	if len(resumeKey) != 0 {
+		start = tidbkv.Key(resumeKey).Next()
+	}
+	iter, err := w.snap.Iter(start, w.end)
+	if err != nil {
+		return nil, nil, false, errors.Trace(err)
+	}
+	defer iter.Close()
+
+	var lastKey []byte
+	rows := make([]*model.RowChangedEvent, 0, w.chunkRows)
+	bytes := 0
+	// At least one key is scanned, so that the export always makes progress.
+	for scanned := 0; iter.Valid() && scanned < w.chunkRows &&
+		(scanned == 0 || bytes < w.chunkBytes); scanned++ {
+		lastKey = append([]byte(nil), iter.Key()...)
+		event := model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     lastKey,
+			Value:   append([]byte(nil), iter.Value()...),
+			StartTs: w.snapshotTs,
+			CRTs:    w.snapshotTs,
+		})
+		if err := w.mounter.DecodeEvent(ctx, event); err != nil {
+			return nil, nil, false, errors.Trace(err)
+		}
+		// The row is nil if it's filtered out.
+		if event.Row != nil {
+			// Rows are written in the safe mode, so that a chunk can be
+			// exported again if the export is interrupted.
+			event.Row.ReplicatingTs = w.snapshotTs + 1
+			rows = append(rows, event.Row)
+			bytes += event.Row.ApproximateBytes()
+		}
+		if err := iter.Next(); err != nil {
+			return nil, nil, false, errors.Trace(err)
+		}
+	}
+	return rows, lastKey, !iter.Valid(), nil
+}
+
+// waitFlushed waits until all rows before resolvedTs are written to the sink.
+func waitFlushed(
+	ctx context.Context, tableSink tsink.TableSink, resolvedTs model.ResolvedTs,
+	sinkErrCh <-chan error,
+) error {
+	ticker := time.NewTicker(flushCheckInterval)
+	defer ticker.Stop()
+	for {
+		if tableSink.GetCheckpointTs().EqualOrGreater(resolvedTs) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case err := <-sinkErrCh:
+			return errors.Trace(err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// decodeSpan returns the raw keys of a span in the comparable format.
+func decodeSpan(span tablepb.Span) (tidbkv.Key, tidbkv.Key, error) {
+	_, start, err := codec.DecodeBytes(span.StartKey, nil)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	_, end, err := codec.DecodeBytes(span.EndKey, nil)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestExporter(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+
+	job1 := helper.DDL2Job("create table test.t1(id int primary key, v int)")
+	job2 := helper.DDL2Job("create table test.t2(id int primary key, v int)")
+	helper.Tk().MustExec("insert into test.t1 values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	helper.Tk().MustExec("insert into test.t2 values (1, 1), (2, 2), (3, 3)")
+	snapshotTs, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.NoError(t, err)
+	// Rows committed after the snapshot ts are not exported.
+	helper.Tk().MustExec("insert into test.t1 values (6, 6)")
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Snapshot = &config.SnapshotConfig{Enable: true, ChunkRows: 2}
+	info := &model.ChangeFeedInfo{SinkURI: "blackhole://", Config: cfg}
+	changefeedID := model.DefaultChangeFeedID("test")
+
+	span := spanz.TableIDToComparableSpan(job1.TableID)
+	exporter := NewExporter(changefeedID, info, helper.Storage(), time.UTC,
+		model.NewSpanSnapshotProgress(span, snapshotTs.Ver))
+	require.NoError(t, exporter.Run(context.Background()))
+	progress := exporter.Progress()
+	require.True(t, progress.Done)
+	require.Equal(t, uint64(5), progress.ExportedRows)
+	resumeKey := tablecodec.EncodeRowKeyWithHandle(job1.TableID, tidbkv.IntHandle(5))
+	require.Equal(t, []byte(resumeKey), progress.ResumeKey)
+
+	// Resume from the second row of t1.
+	progress = model.NewSpanSnapshotProgress(span, snapshotTs.Ver)
+	progress.ResumeKey = tablecodec.EncodeRowKeyWithHandle(job1.TableID, tidbkv.IntHandle(2))
+	progress.ExportedRows = 2
+	exporter = NewExporter(changefeedID, info, helper.Storage(), time.UTC, progress)
+	require.NoError(t, exporter.Run(context.Background()))
+	progress = exporter.Progress()
+	require.True(t, progress.Done)
+	require.Equal(t, uint64(5), progress.ExportedRows)
+
+	// An exported span is not exported again.
+	exporter = NewExporter(changefeedID, info, helper.Storage(), time.UTC, progress)
+	require.NoError(t, exporter.Run(context.Background()))
+	require.Equal(t, uint64(5), exporter.Progress().ExportedRows)
+
+	// Chunks are split by the memory quota even if they are smaller than
+	// chunk rows.
+	cfg.Snapshot = &config.SnapshotConfig{
+		Enable: true, ChunkRows: 100, Concurrency: 2, MemoryQuota: 2,
+	}
+	exporter = NewExporter(changefeedID, info, helper.Storage(), time.UTC,
+		model.NewSpanSnapshotProgress(spanz.TableIDToComparableSpan(job2.TableID), snapshotTs.Ver))
+	require.NoError(t, exporter.Run(context.Background()))
+	progress = exporter.Progress()
+	require.True(t, progress.Done)
+	require.Equal(t, uint64(3), progress.ExportedRows)
+
+	// Only rows in the span are exported.
+	span.StartKey = spanz.ToComparableKey(
+		tablecodec.EncodeRowKeyWithHandle(job1.TableID, tidbkv.IntHandle(3)))
+	exporter = NewExporter(changefeedID, info, helper.Storage(), time.UTC,
+		model.NewSpanSnapshotProgress(span, snapshotTs.Ver))
+	require.NoError(t, exporter.Run(context.Background()))
+	progress = exporter.Progress()
+	require.True(t, progress.Done)
+	require.Equal(t, uint64(3), progress.ExportedRows)
+}
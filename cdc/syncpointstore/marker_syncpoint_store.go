@@ -0,0 +1,60 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncpointstore
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+// markerSyncPointStore records syncpoints as markers in the downstream, a
+// marker message broadcast to all partitions for MQ sinks, and a manifest
+// file listing the last data file of each table for cloud storage sinks.
+// Consumers can build a consistent view of all tables at the marker.
+type markerSyncPointStore struct {
+	writeSyncPoint WriteSyncPointFunc
+}
+
+func newMarkerSyncPointStore(writeSyncPoint WriteSyncPointFunc) SyncPointStore {
+	log.Info("Start marker syncpoint sink")
+	return &markerSyncPointStore{writeSyncPoint: writeSyncPoint}
+}
+
+// CreateSyncTable does nothing, there is no table to record markers.
+func (s *markerSyncPointStore) CreateSyncTable(_ context.Context) error {
+	return nil
+}
+
+func (s *markerSyncPointStore) SinkSyncPoint(ctx context.Context,
+	id model.ChangeFeedID,
+	checkpointTs uint64,
+) error {
+	if err := s.writeSyncPoint(ctx, checkpointTs); err != nil {
+		return errors.Trace(err)
+	}
+	log.Info("syncpoint marker is written to downstream",
+		zap.String("namespace", id.Namespace),
+		zap.String("changefeed", id.ID),
+		zap.Uint64("primaryTs", checkpointTs))
+	return nil
+}
+
+// Close does nothing, the sink of markers is closed by its owner.
+func (s *markerSyncPointStore) Close() error {
+	return nil
+}
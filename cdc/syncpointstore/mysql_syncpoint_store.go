@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -206,6 +207,38 @@ func (s *mysqlSyncPointStore) SinkSyncPoint(ctx context.Context,
 	return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 }
 
+// SyncPoint is a pair of timestamps recorded in the downstream, the upstream
+// snapshot at PrimaryTs is consistent with the downstream snapshot at SecondaryTs.
+type SyncPoint struct {
+	PrimaryTs   uint64
+	SecondaryTs uint64
+}
+
+// GetSyncPoint reads a syncpoint of the changefeed from the downstream db,
+// the latest one is returned if primaryTs is 0.
+func GetSyncPoint(
+	ctx context.Context, db *sql.DB, id model.ChangeFeedID, primaryTs uint64,
+) (*SyncPoint, error) {
+	query := "SELECT primary_ts, secondary_ts FROM " + schemaName + "." + syncPointTableName +
+		" WHERE ticdc_cluster_id = ? AND changefeed = ?"
+	args := []interface{}{config.GetGlobalServerConfig().ClusterID, id.ID}
+	if primaryTs != 0 {
+		query += " AND primary_ts = ?"
+		args = append(args, strconv.FormatUint(primaryTs, 10))
+	}
+	query += " ORDER BY CAST(primary_ts AS UNSIGNED) DESC LIMIT 1"
+
+	syncPoint := &SyncPoint{}
+	err := db.QueryRowContext(ctx, query, args...).Scan(&syncPoint.PrimaryTs, &syncPoint.SecondaryTs)
+	if err == sql.ErrNoRows {
+		return nil, cerror.ErrSyncPointNotFound.GenWithStackByArgs(id.ID, primaryTs)
+	}
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return syncPoint, nil
+}
+
 func (s *mysqlSyncPointStore) Close() error {
 	err := s.db.Close()
 	return cerror.WrapError(cerror.ErrMySQLConnectionError, err)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
 )
 
 // SyncPointStore is an abstraction for anything that a changefeed may emit into.
@@ -35,21 +36,31 @@ type SyncPointStore interface {
 	Close() error
 }
 
-// NewSyncPointStore creates a new SyncPoint sink with the sink-uri
+// WriteSyncPointFunc writes a syncpoint marker to a sink which is not
+// MySQL-compatible, for example, the ddl sink of the changefeed.
+type WriteSyncPointFunc func(ctx context.Context, ts uint64) error
+
+// NewSyncPointStore creates a new SyncPoint sink with the sink-uri.
+// writeSyncPoint is used by MQ and cloud storage sinks, syncpoints of
+// MySQL-compatible sinks are recorded in the downstream db.
 func NewSyncPointStore(
 	ctx context.Context,
 	changefeedID model.ChangeFeedID,
 	sinkURIStr string,
 	syncPointRetention time.Duration,
+	writeSyncPoint WriteSyncPointFunc,
 ) (SyncPointStore, error) {
 	// parse sinkURI as a URI
 	sinkURI, err := url.Parse(sinkURIStr)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
 	}
-	switch strings.ToLower(sinkURI.Scheme) {
-	case "mysql", "tidb", "mysql+ssl", "tidb+ssl":
+	scheme := strings.ToLower(sinkURI.Scheme)
+	switch {
+	case sink.IsMySQLCompatibleScheme(scheme):
 		return newMySQLSyncPointStore(ctx, changefeedID, sinkURI, syncPointRetention)
+	case sink.IsMQScheme(scheme), sink.IsStorageScheme(scheme):
+		return newMarkerSyncPointStore(writeSyncPoint), nil
 	default:
 		return nil, cerror.ErrSinkURIInvalid.
 			GenWithStack("the sink scheme (%s) is not supported", sinkURI.Scheme)
@@ -21,11 +21,9 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -44,11 +42,9 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/logutil"
-	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/security"
-	"github.com/pingcap/tiflow/pkg/sink/codec"
-	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
-	"github.com/pingcap/tiflow/pkg/sink/codec/open"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/consumer"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -70,6 +66,10 @@ var (
 	protocol            config.Protocol
 	enableTiDBExtension bool
 
+	// claimCheckStorageURI is the storage of messages sent by claim check,
+	// objects are not deleted after they are consumed.
+	claimCheckStorageURI string
+
 	// eventRouterReplicaConfig only used to initialize the consumer's eventRouter
 	// which then can be used to check RowChangedEvent dispatched correctness
 	eventRouterReplicaConfig *config.ReplicaConfig
@@ -192,6 +192,16 @@ func init() {
 		enableTiDBExtension = b
 	}
 
+	s = upstreamURI.Query().Get("claim-check-storage-uri")
+	if s != "" {
+		if protocol != config.ProtocolOpen && protocol != config.ProtocolDefault &&
+			!(protocol == config.ProtocolCanalJSON && enableTiDBExtension) {
+			log.Panic("claim-check-storage-uri only work with open-protocol, " +
+				"or canal-json with enable-tidb-extension")
+		}
+		claimCheckStorageURI = s
+	}
+
 	if configFile != "" {
 		eventRouterReplicaConfig = config.GetDefaultReplicaConfig()
 		eventRouterReplicaConfig.Sink.Protocol = protocol.String()
@@ -353,35 +363,12 @@ func main() {
 	}
 }
 
-type partitionSinks struct {
-	tablesCommitTsMap sync.Map
-	tableSinksMap     sync.Map
-	resolvedTs        uint64
-	partitionNo       int
-}
-
 // Consumer represents a Sarama consumer group consumer
 type Consumer struct {
 	ready chan bool
 
-	ddlList              []*model.DDLEvent
-	ddlListMu            sync.Mutex
-	ddlWithMaxCommitTs   *model.DDLEvent
-	ddlSink              ddlsink.Sink
-	fakeTableIDGenerator *fakeTableIDGenerator
-
-	// sinkFactory is used to create table sink for each table.
-	sinkFactory *eventsinkfactory.SinkFactory
-	sinks       []*partitionSinks
-	sinksMu     sync.Mutex
-
-	// initialize to 0 by default
-	globalResolvedTs uint64
-
-	protocol            config.Protocol
-	enableTiDBExtension bool
-
-	eventRouter *dispatcher.EventRouter
+	*consumer.Consumer
+	sarama.ConsumerGroupHandler
 }
 
 // NewConsumer creates a new cdc kafka consumer
@@ -393,12 +380,23 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 	}
 	ctx = contextutil.PutTimezoneInCtx(ctx, tz)
 
-	c := new(Consumer)
-	c.fakeTableIDGenerator = &fakeTableIDGenerator{
-		tableIDs: make(map[string]int64),
+	var claimCheck *common.ClaimCheck
+	if claimCheckStorageURI != "" {
+		claimCheck, err = common.NewClaimCheck(ctx, claimCheckStorageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	newDecoder, err := consumer.NewDecoderFunc(protocol, enableTiDBExtension, claimCheck)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg := &consumer.Config{
+		PartitionNum:    kafkaPartitionNum,
+		NewDecoder:      newDecoder,
+		MaxMessageBytes: kafkaMaxMessageBytes,
+		MaxBatchSize:    kafkaMaxBatchSize,
 	}
-	c.protocol = protocol
-	c.enableTiDBExtension = enableTiDBExtension
 
 	// this means user has input config file to enable dispatcher check
 	// some protocol does not provide enough information to check the
@@ -413,19 +411,12 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		c.eventRouter = eventRouter
-
+		cfg.EventRouter = eventRouter
 	}
 
-	c.sinks = make([]*partitionSinks, kafkaPartitionNum)
 	ctx, cancel := context.WithCancel(ctx)
 	ctx = contextutil.PutRoleInCtx(ctx, util.RoleKafkaConsumer)
 	errChan := make(chan error, 1)
-	for i := 0; i < int(kafkaPartitionNum); i++ {
-		c.sinks[i] = &partitionSinks{
-			partitionNo: i,
-		}
-	}
 	f, err := eventsinkfactory.New(
 		ctx,
 		downstreamURIStr,
@@ -436,7 +427,6 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 		cancel()
 		return nil, errors.Trace(err)
 	}
-	c.sinkFactory = f
 
 	go func() {
 		err := <-errChan
@@ -457,390 +447,86 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 		cancel()
 		return nil, errors.Trace(err)
 	}
-	c.ddlSink = ddlSink
+
+	c := new(Consumer)
+	c.Consumer, err = consumer.New(cfg, &sinkHandler{sinkFactory: f, ddlSink: ddlSink})
+	if err != nil {
+		cancel()
+		return nil, errors.Trace(err)
+	}
+	c.ConsumerGroupHandler = c.Consumer.ConsumerGroupHandler()
 	c.ready = make(chan bool)
 	return c, nil
 }
 
 // Setup is run at the beginning of a new session, before ConsumeClaim
-func (c *Consumer) Setup(sarama.ConsumerGroupSession) error {
+func (c *Consumer) Setup(session sarama.ConsumerGroupSession) error {
 	// Mark the c as ready
 	close(c.ready)
-	return nil
-}
-
-// Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited
-func (c *Consumer) Cleanup(sarama.ConsumerGroupSession) error {
-	return nil
-}
-
-type eventsGroup struct {
-	events []*model.RowChangedEvent
-}
-
-func newEventsGroup() *eventsGroup {
-	return &eventsGroup{
-		events: make([]*model.RowChangedEvent, 0),
-	}
-}
-
-func (g *eventsGroup) Append(e *model.RowChangedEvent) {
-	g.events = append(g.events, e)
-}
-
-func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
-	sort.Slice(g.events, func(i, j int) bool {
-		return g.events[i].CommitTs < g.events[j].CommitTs
-	})
-
-	i := sort.Search(len(g.events), func(i int) bool {
-		return g.events[i].CommitTs > resolveTs
-	})
-	result := g.events[:i]
-	g.events = g.events[i:]
-
-	return result
-}
-
-// ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages().
-func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	partition := claim.Partition()
-	c.sinksMu.Lock()
-	sink := c.sinks[partition]
-	c.sinksMu.Unlock()
-	if sink == nil {
-		panic("sink should initialized")
-	}
-
-	var (
-		decoder codec.RowEventDecoder
-		err     error
-	)
-	switch c.protocol {
-	case config.ProtocolOpen, config.ProtocolDefault:
-		decoder = open.NewBatchDecoder()
-	case config.ProtocolCanalJSON:
-		decoder = canal.NewBatchDecoder(c.enableTiDBExtension, "")
-	default:
-		log.Panic("Protocol not supported", zap.Any("Protocol", c.protocol))
-	}
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	eventGroups := make(map[int64]*eventsGroup)
-	for message := range claim.Messages() {
-		if err := decoder.AddKeyValue(message.Key, message.Value); err != nil {
-			log.Error("add key value to the decoder failed", zap.Error(err))
-			return errors.Trace(err)
-		}
-
-		counter := 0
-		for {
-			tp, hasNext, err := decoder.HasNext()
-			if err != nil {
-				log.Panic("decode message key failed", zap.Error(err))
-			}
-			if !hasNext {
-				break
-			}
-
-			counter++
-			// If the message containing only one event exceeds the length limit, CDC will allow it and issue a warning.
-			if len(message.Key)+len(message.Value) > kafkaMaxMessageBytes && counter > 1 {
-				log.Panic("kafka max-messages-bytes exceeded", zap.Int("max-message-bytes", kafkaMaxMessageBytes),
-					zap.Int("receivedBytes", len(message.Key)+len(message.Value)))
-			}
-
-			switch tp {
-			case model.MessageTypeDDL:
-				// for some protocol, DDL would be dispatched to all partitions,
-				// Consider that DDL a, b, c received from partition-0, the latest DDL is c,
-				// if we receive `a` from partition-1, which would be seemed as DDL regression,
-				// then cause the consumer panic, but it was a duplicate one.
-				// so we only handle DDL received from partition-0 should be enough.
-				// but all DDL event messages should be consumed.
-				ddl, err := decoder.NextDDLEvent()
-				if err != nil {
-					log.Panic("decode message value failed", zap.ByteString("value", message.Value))
-				}
-				if partition == 0 {
-					c.appendDDL(ddl)
-				}
-			case model.MessageTypeRow:
-				row, err := decoder.NextRowChangedEvent()
-				if err != nil {
-					log.Panic("decode message value failed", zap.ByteString("value", message.Value))
-				}
-
-				if c.eventRouter != nil {
-					target := c.eventRouter.GetPartitionForRowChange(row, kafkaPartitionNum)
-					if partition != target {
-						log.Panic("RowChangedEvent dispatched to wrong partition",
-							zap.Int32("obtained", partition),
-							zap.Int32("expected", target),
-							zap.Int32("partitionNum", kafkaPartitionNum),
-							zap.Any("row", row),
-						)
-					}
-				}
-
-				globalResolvedTs := atomic.LoadUint64(&c.globalResolvedTs)
-				if row.CommitTs <= globalResolvedTs || row.CommitTs <= sink.resolvedTs {
-					log.Warn("RowChangedEvent fallback row, ignore it",
-						zap.Uint64("commitTs", row.CommitTs),
-						zap.Uint64("globalResolvedTs", globalResolvedTs),
-						zap.Uint64("sinkResolvedTs", sink.resolvedTs),
-						zap.Int32("partition", partition),
-						zap.Any("row", row))
-				}
-				var partitionID int64
-				if row.Table.IsPartition {
-					partitionID = row.Table.TableID
-				}
-				tableID := c.fakeTableIDGenerator.
-					generateFakeTableID(row.Table.Schema, row.Table.Table, partitionID)
-				row.Table.TableID = tableID
-
-				group, ok := eventGroups[tableID]
-				if !ok {
-					group = newEventsGroup()
-					eventGroups[tableID] = group
-				}
-				group.Append(row)
-			case model.MessageTypeResolved:
-				ts, err := decoder.NextResolvedEvent()
-				if err != nil {
-					log.Panic("decode message value failed", zap.ByteString("value", message.Value))
-				}
-				resolvedTs := atomic.LoadUint64(&sink.resolvedTs)
-				// `resolvedTs` should be monotonically increasing, it's allowed to receive redundant one.
-				if ts < resolvedTs {
-					log.Panic("partition resolved ts fallback",
-						zap.Uint64("ts", ts),
-						zap.Uint64("resolvedTs", resolvedTs),
-						zap.Int32("partition", partition))
-				}
-				if ts > resolvedTs {
-					for tableID, group := range eventGroups {
-						events := group.Resolve(ts)
-						if len(events) == 0 {
-							continue
-						}
-						if _, ok := sink.tableSinksMap.Load(tableID); !ok {
-							sink.tableSinksMap.Store(tableID, c.sinkFactory.CreateTableSinkForConsumer(
-								model.DefaultChangeFeedID("kafka-consumer"),
-								spanz.TableIDToComparableSpan(tableID),
-								events[0].CommitTs,
-								prometheus.NewCounter(prometheus.CounterOpts{}),
-							))
-						}
-						s, _ := sink.tableSinksMap.Load(tableID)
-						s.(tablesink.TableSink).AppendRowChangedEvents(events...)
-						commitTs := events[len(events)-1].CommitTs
-						lastCommitTs, ok := sink.tablesCommitTsMap.Load(tableID)
-						if !ok || lastCommitTs.(uint64) < commitTs {
-							sink.tablesCommitTsMap.Store(tableID, commitTs)
-						}
-					}
-					log.Debug("update sink resolved ts",
-						zap.Uint64("ts", ts),
-						zap.Int32("partition", partition))
-					atomic.StoreUint64(&sink.resolvedTs, ts)
-				} else {
-					log.Info("redundant sink resolved ts", zap.Uint64("ts", ts), zap.Int32("partition", partition))
-				}
-			}
-			session.MarkMessage(message, "")
-		}
-
-		if counter > kafkaMaxBatchSize {
-			log.Panic("Open Protocol max-batch-size exceeded", zap.Int("max-batch-size", kafkaMaxBatchSize),
-				zap.Int("actual-batch-size", counter))
-		}
-	}
-
-	return nil
-}
-
-// append DDL wait to be handled, only consider the constraint among DDLs.
-// for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
-func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
-	c.ddlListMu.Lock()
-	defer c.ddlListMu.Unlock()
-	// DDL CommitTs fallback, just crash it to indicate the bug.
-	if c.ddlWithMaxCommitTs != nil && ddl.CommitTs < c.ddlWithMaxCommitTs.CommitTs {
-		log.Panic("DDL CommitTs < maxCommitTsDDL.CommitTs",
-			zap.Uint64("commitTs", ddl.CommitTs),
-			zap.Uint64("maxCommitTs", c.ddlWithMaxCommitTs.CommitTs),
-			zap.Any("DDL", ddl))
-	}
-
-	// A rename tables DDL job contains multiple DDL events with same CommitTs.
-	// So to tell if a DDL is redundant or not, we must check the equivalence of
-	// the current DDL and the DDL with max CommitTs.
-	if ddl == c.ddlWithMaxCommitTs {
-		log.Info("ignore redundant DDL, the DDL is equal to ddlWithMaxCommitTs",
-			zap.Any("DDL", ddl))
-		return
-	}
-
-	c.ddlList = append(c.ddlList, ddl)
-	log.Info("DDL event received", zap.Any("DDL", ddl))
-	c.ddlWithMaxCommitTs = ddl
+	return c.ConsumerGroupHandler.Setup(session)
 }
 
-func (c *Consumer) getFrontDDL() *model.DDLEvent {
-	c.ddlListMu.Lock()
-	defer c.ddlListMu.Unlock()
-	if len(c.ddlList) > 0 {
-		return c.ddlList[0]
-	}
-	return nil
-}
+// sinkHandler writes events to table sinks and the DDL sink.
+type sinkHandler struct {
+	// sinkFactory is used to create table sink for each table.
+	sinkFactory *eventsinkfactory.SinkFactory
+	ddlSink     ddlsink.Sink
 
-func (c *Consumer) popDDL() *model.DDLEvent {
-	c.ddlListMu.Lock()
-	defer c.ddlListMu.Unlock()
-	if len(c.ddlList) > 0 {
-		ddl := c.ddlList[0]
-		c.ddlList = c.ddlList[1:]
-		return ddl
-	}
-	return nil
+	tableSinksMu sync.Mutex
+	tableSinks   map[int64]tablesink.TableSink
 }
 
-func (c *Consumer) forEachSink(fn func(sink *partitionSinks) error) error {
-	c.sinksMu.Lock()
-	defer c.sinksMu.Unlock()
-	for _, sink := range c.sinks {
-		if err := fn(sink); err != nil {
-			return errors.Trace(err)
-		}
-	}
+// AppendRowChangedEvents implements consumer.Handler.
+func (h *sinkHandler) AppendRowChangedEvents(
+	_ context.Context, tableID int64, events []*model.RowChangedEvent,
+) error {
+	h.tableSinksMu.Lock()
+	defer h.tableSinksMu.Unlock()
+	if h.tableSinks == nil {
+		h.tableSinks = make(map[int64]tablesink.TableSink)
+	}
+	tableSink, ok := h.tableSinks[tableID]
+	if !ok {
+		tableSink = h.sinkFactory.CreateTableSinkForConsumer(
+			model.DefaultChangeFeedID("kafka-consumer"),
+			spanz.TableIDToComparableSpan(tableID),
+			events[0].CommitTs,
+			prometheus.NewCounter(prometheus.CounterOpts{}),
+		)
+		h.tableSinks[tableID] = tableSink
+	}
+	tableSink.AppendRowChangedEvents(events...)
 	return nil
 }
 
-func (c *Consumer) getMinPartitionResolvedTs() (result uint64, err error) {
-	result = uint64(math.MaxUint64)
-	err = c.forEachSink(func(sink *partitionSinks) error {
-		a := atomic.LoadUint64(&sink.resolvedTs)
-		if a < result {
-			result = a
-		}
-		return nil
-	})
-	return result, err
-}
-
-// Run the Consumer
-func (c *Consumer) Run(ctx context.Context) error {
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
+// FlushRowChangedEvents implements consumer.Handler.
+func (h *sinkHandler) FlushRowChangedEvents(ctx context.Context, ts uint64) error {
+	resolvedTs := model.NewResolvedTs(ts)
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-ticker.C:
-		}
-
-		minPartitionResolvedTs, err := c.getMinPartitionResolvedTs()
-		if err != nil {
-			return errors.Trace(err)
+		default:
 		}
-
-		// handle DDL
-		todoDDL := c.getFrontDDL()
-		if todoDDL != nil && todoDDL.CommitTs <= minPartitionResolvedTs {
-			// flush DMLs
-			if err := c.forEachSink(func(sink *partitionSinks) error {
-				return syncFlushRowChangedEvents(ctx, sink, todoDDL.CommitTs)
-			}); err != nil {
-				return errors.Trace(err)
-			}
-
-			// DDL can be executed, do it first.
-			if err := c.ddlSink.WriteDDLEvent(ctx, todoDDL); err != nil {
+		flushed := true
+		h.tableSinksMu.Lock()
+		for _, tableSink := range h.tableSinks {
+			if err := tableSink.UpdateResolvedTs(resolvedTs); err != nil {
+				h.tableSinksMu.Unlock()
 				return errors.Trace(err)
 			}
-			c.popDDL()
-
-			if todoDDL.CommitTs < minPartitionResolvedTs {
-				log.Info("update minPartitionResolvedTs by DDL",
-					zap.Uint64("minPartitionResolvedTs", minPartitionResolvedTs),
-					zap.Any("DDL", todoDDL))
+			if !tableSink.GetCheckpointTs().EqualOrGreater(resolvedTs) {
+				flushed = false
 			}
-			minPartitionResolvedTs = todoDDL.CommitTs
-		}
-
-		// update global resolved ts
-		if c.globalResolvedTs > minPartitionResolvedTs {
-			log.Panic("global ResolvedTs fallback",
-				zap.Uint64("globalResolvedTs", c.globalResolvedTs),
-				zap.Uint64("minPartitionResolvedTs", minPartitionResolvedTs))
-		}
-
-		if c.globalResolvedTs == minPartitionResolvedTs {
-			continue
-		}
-
-		c.globalResolvedTs = minPartitionResolvedTs
-
-		if err := c.forEachSink(func(sink *partitionSinks) error {
-			return syncFlushRowChangedEvents(ctx, sink, c.globalResolvedTs)
-		}); err != nil {
-			return errors.Trace(err)
 		}
-	}
-}
-
-func syncFlushRowChangedEvents(ctx context.Context, sink *partitionSinks, resolvedTs uint64) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-		flushedResolvedTs := true
-		sink.tablesCommitTsMap.Range(func(key, value interface{}) bool {
-			tableID := key.(int64)
-			resolvedTs := model.NewResolvedTs(resolvedTs)
-			tableSink, ok := sink.tableSinksMap.Load(tableID)
-			if !ok {
-				log.Panic("Table sink not found", zap.Int64("tableID", tableID))
-			}
-			if err := tableSink.(tablesink.TableSink).UpdateResolvedTs(resolvedTs); err != nil {
-				log.Error("Failed to update resolved ts", zap.Error(err))
-				return false
-			}
-			if !tableSink.(tablesink.TableSink).GetCheckpointTs().EqualOrGreater(resolvedTs) {
-				flushedResolvedTs = false
-			}
-			return true
-		})
-		if flushedResolvedTs {
+		h.tableSinksMu.Unlock()
+		if flushed {
 			return nil
 		}
 	}
 }
 
-type fakeTableIDGenerator struct {
-	tableIDs       map[string]int64
-	currentTableID int64
-	mu             sync.Mutex
-}
-
-func (g *fakeTableIDGenerator) generateFakeTableID(schema, table string, partition int64) int64 {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	key := quotes.QuoteSchema(schema, table)
-	if partition != 0 {
-		key = fmt.Sprintf("%s.`%d`", key, partition)
-	}
-	if tableID, ok := g.tableIDs[key]; ok {
-		return tableID
-	}
-	g.currentTableID++
-	g.tableIDs[key] = g.currentTableID
-	return g.currentTableID
+// WriteDDLEvent implements consumer.Handler.
+func (h *sinkHandler) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	return h.ddlSink.WriteDDLEvent(ctx, ddl)
 }
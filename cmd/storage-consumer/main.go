@@ -444,7 +444,7 @@ func (c *consumer) emitDMLEvents(
 	case config.ProtocolCanalJSON:
 		// Always enable tidb extension for canal-json protocol
 		// because we need to get the commit ts from the extension field.
-		decoder = canal.NewBatchDecoder(true, c.codecCfg.Terminator)
+		decoder = canal.NewBatchDecoder(true, c.codecCfg.Terminator, nil)
 		err := decoder.AddKeyValue(nil, content)
 		if err != nil {
 			return errors.Trace(err)
@@ -29,6 +29,8 @@ type APIV2Interface interface {
 	StatusGetter
 	CapturesGetter
 	ProcessorsGetter
+	UpstreamsGetter
+	NamespacesGetter
 }
 
 // APIV2Client implements APIV1Interface and it is used to interact with cdc owner http api.
@@ -90,6 +92,22 @@ func (c *APIV2Client) Processors() ProcessorInterface {
 	return newProcessors(c)
 }
 
+// Upstreams returns a UpstreamInterface abstracting upstream operations.
+func (c *APIV2Client) Upstreams() UpstreamInterface {
+	if c == nil {
+		return nil
+	}
+	return newUpstreams(c)
+}
+
+// Namespaces returns a NamespaceInterface abstracting namespace operations.
+func (c *APIV2Client) Namespaces() NamespaceInterface {
+	if c == nil {
+		return nil
+	}
+	return newNamespaces(c)
+}
+
 // NewAPIClient creates a new APIV1Client.
 func NewAPIClient(serverAddr string, credential *security.Credential) (*APIV2Client, error) {
 	c := &rest.Config{}
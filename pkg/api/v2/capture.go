@@ -30,6 +30,10 @@ type CapturesGetter interface {
 // We can also mock the capture operations by implement this interface.
 type CaptureInterface interface {
 	List(ctx context.Context) ([]model.Capture, error)
+	Drain(ctx context.Context, captureID string,
+		cfg *v2.DrainCaptureConfig) (*model.DrainCaptureResp, error)
+	DrainStatus(ctx context.Context, captureID string) (*model.DrainCaptureResp, error)
+	CancelDrain(ctx context.Context, captureID string) (*model.DrainCaptureResp, error)
 }
 
 // captures implements CaptureInterface
@@ -53,3 +57,40 @@ func (c *captures) List(ctx context.Context) ([]model.Capture, error) {
 		Into(result)
 	return result.Items, err
 }
+
+// Drain starts draining the given capture
+func (c *captures) Drain(ctx context.Context, captureID string,
+	cfg *v2.DrainCaptureConfig,
+) (*model.DrainCaptureResp, error) {
+	result := &model.DrainCaptureResp{}
+	err := c.client.Post().
+		WithURI("captures/" + captureID + "/drain").
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// DrainStatus returns the draining progress of the given capture
+func (c *captures) DrainStatus(ctx context.Context,
+	captureID string,
+) (*model.DrainCaptureResp, error) {
+	result := &model.DrainCaptureResp{}
+	err := c.client.Get().
+		WithURI("captures/" + captureID + "/drain").
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// CancelDrain cancels draining the given capture
+func (c *captures) CancelDrain(ctx context.Context,
+	captureID string,
+) (*model.DrainCaptureResp, error) {
+	result := &model.DrainCaptureResp{}
+	err := c.client.Delete().
+		WithURI("captures/" + captureID + "/drain").
+		Do(ctx).
+		Into(result)
+	return result, err
+}
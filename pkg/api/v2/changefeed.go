@@ -20,6 +20,7 @@ import (
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+	"github.com/pingcap/tiflow/pkg/verification"
 )
 
 // ChangefeedsGetter has a method to return a ChangefeedInterface.
@@ -36,17 +37,33 @@ type ChangefeedInterface interface {
 	VerifyTable(ctx context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error)
 	// Update updates a changefeed
 	Update(ctx context.Context, cfg *v2.ChangefeedConfig,
-		name string) (*v2.ChangeFeedInfo, error)
+		namespace string, name string) (*v2.ChangeFeedInfo, error)
 	// Resume resumes a changefeed with given config
-	Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error
+	Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, namespace string, name string) error
 	// Delete deletes a changefeed by name
-	Delete(ctx context.Context, name string) error
+	Delete(ctx context.Context, namespace string, name string) error
 	// Pause pauses a changefeed with given name
-	Pause(ctx context.Context, name string) error
+	Pause(ctx context.Context, namespace string, name string) error
 	// Get gets a changefeed detaail info
-	Get(ctx context.Context, name string) (*v2.ChangeFeedInfo, error)
-	// List lists all changefeeds
-	List(ctx context.Context, state string) ([]v2.ChangefeedCommonInfo, error)
+	Get(ctx context.Context, namespace string, name string) (*v2.ChangeFeedInfo, error)
+	// List lists all changefeeds, changefeeds of all namespaces are listed
+	// if namespace is empty
+	List(ctx context.Context, namespace string, state string) ([]v2.ChangefeedCommonInfo, error)
+	// ListEvents lists the history events of a changefeed
+	ListEvents(ctx context.Context, namespace string, name string) ([]v2.ChangefeedEvent, error)
+	// GetHeldDDL gets the DDL held for approval of a changefeed
+	GetHeldDDL(ctx context.Context, namespace string, name string) (*v2.HeldDDL, error)
+	// HandleHeldDDL approves, skips or replaces the held DDL of a changefeed
+	HandleHeldDDL(ctx context.Context, cfg *v2.HandleDDLConfig, namespace string, name string) error
+	// BackfillTable re-syncs a table of a running changefeed
+	BackfillTable(ctx context.Context, cfg *v2.BackfillTableConfig, namespace string, name string) error
+	// StartVerification starts a consistency check of a changefeed
+	StartVerification(ctx context.Context, cfg *v2.VerifyConfig,
+		namespace string, name string) (*verification.Status, error)
+	// GetVerification gets the last consistency check of a changefeed
+	GetVerification(ctx context.Context, namespace string, name string) (*verification.Status, error)
+	// CancelVerification cancels the running consistency check of a changefeed
+	CancelVerification(ctx context.Context, namespace string, name string) error
 }
 
 // changefeeds implements ChangefeedInterface
@@ -85,12 +102,13 @@ func (c *changefeeds) VerifyTable(ctx context.Context,
 }
 
 func (c *changefeeds) Update(ctx context.Context,
-	cfg *v2.ChangefeedConfig, name string,
+	cfg *v2.ChangefeedConfig, namespace string, name string,
 ) (*v2.ChangeFeedInfo, error) {
 	result := &v2.ChangeFeedInfo{}
 	u := fmt.Sprintf("changefeeds/%s", name)
 	err := c.client.Put().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		WithBody(cfg).
 		Do(ctx).
 		Into(result)
@@ -99,38 +117,41 @@ func (c *changefeeds) Update(ctx context.Context,
 
 // Resume a changefeed
 func (c *changefeeds) Resume(ctx context.Context,
-	cfg *v2.ResumeChangefeedConfig, name string,
+	cfg *v2.ResumeChangefeedConfig, namespace string, name string,
 ) error {
 	u := fmt.Sprintf("changefeeds/%s/resume", name)
 	return c.client.Post().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		WithBody(cfg).
 		Do(ctx).Error()
 }
 
 // Delete a changefeed
 func (c *changefeeds) Delete(ctx context.Context,
-	name string,
+	namespace string, name string,
 ) error {
 	u := fmt.Sprintf("changefeeds/%s", name)
 	return c.client.Delete().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).Error()
 }
 
 // Pause a changefeed
 func (c *changefeeds) Pause(ctx context.Context,
-	name string,
+	namespace string, name string,
 ) error {
 	u := fmt.Sprintf("changefeeds/%s/pause", name)
 	return c.client.Post().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).Error()
 }
 
 // Get gets a changefeed detaail info
 func (c *changefeeds) Get(ctx context.Context,
-	name string,
+	namespace string, name string,
 ) (*v2.ChangeFeedInfo, error) {
 	err := model.ValidateChangefeedID(name)
 	if err != nil {
@@ -140,6 +161,7 @@ func (c *changefeeds) Get(ctx context.Context,
 	u := fmt.Sprintf("changefeeds/%s", name)
 	err = c.client.Get().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).
 		Into(result)
 	return result, err
@@ -147,13 +169,118 @@ func (c *changefeeds) Get(ctx context.Context,
 
 // List lists all changefeeds
 func (c *changefeeds) List(ctx context.Context,
-	state string,
+	namespace string, state string,
 ) ([]v2.ChangefeedCommonInfo, error) {
 	result := &v2.ListResponse[v2.ChangefeedCommonInfo]{}
 	err := c.client.Get().
 		WithURI("changefeeds").
+		WithParam("namespace", namespace).
 		WithParam("state", state).
 		Do(ctx).
 		Into(result)
 	return result.Items, err
 }
+
+// ListEvents lists the history events of a changefeed
+func (c *changefeeds) ListEvents(ctx context.Context,
+	namespace string, name string,
+) ([]v2.ChangefeedEvent, error) {
+	err := model.ValidateChangefeedID(name)
+	if err != nil {
+		return nil, err
+	}
+	result := &v2.ListResponse[v2.ChangefeedEvent]{}
+	u := fmt.Sprintf("changefeeds/%s/events", name)
+	err = c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result.Items, err
+}
+
+// GetHeldDDL gets the DDL held for approval of a changefeed
+func (c *changefeeds) GetHeldDDL(ctx context.Context,
+	namespace string, name string,
+) (*v2.HeldDDL, error) {
+	err := model.ValidateChangefeedID(name)
+	if err != nil {
+		return nil, err
+	}
+	result := new(v2.HeldDDL)
+	u := fmt.Sprintf("changefeeds/%s/held_ddl", name)
+	err = c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// HandleHeldDDL approves, skips or replaces the held DDL of a changefeed
+func (c *changefeeds) HandleHeldDDL(ctx context.Context,
+	cfg *v2.HandleDDLConfig, namespace string, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/held_ddl", name)
+	return c.client.Post().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		WithBody(cfg).
+		Do(ctx).Error()
+}
+
+// StartVerification starts a consistency check of a changefeed
+func (c *changefeeds) StartVerification(ctx context.Context,
+	cfg *v2.VerifyConfig, namespace string, name string,
+) (*verification.Status, error) {
+	result := new(verification.Status)
+	u := fmt.Sprintf("changefeeds/%s/verification", name)
+	err := c.client.Post().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// GetVerification gets the last consistency check of a changefeed
+func (c *changefeeds) GetVerification(ctx context.Context,
+	namespace string, name string,
+) (*verification.Status, error) {
+	err := model.ValidateChangefeedID(name)
+	if err != nil {
+		return nil, err
+	}
+	result := new(verification.Status)
+	u := fmt.Sprintf("changefeeds/%s/verification", name)
+	err = c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// CancelVerification cancels the running consistency check of a changefeed
+func (c *changefeeds) CancelVerification(ctx context.Context,
+	namespace string, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/verification", name)
+	return c.client.Delete().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).Error()
+}
+
+// BackfillTable re-syncs a table of a running changefeed
+func (c *changefeeds) BackfillTable(ctx context.Context,
+	cfg *v2.BackfillTableConfig, namespace string, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/backfill", name)
+	return c.client.Post().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		WithBody(cfg).
+		Do(ctx).Error()
+}
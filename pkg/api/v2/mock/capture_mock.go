@@ -9,8 +9,9 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	model "github.com/pingcap/tiflow/cdc/model"
-	v2 "github.com/pingcap/tiflow/pkg/api/v2"
+	v20 "github.com/pingcap/tiflow/pkg/api/v2"
 )
 
 // MockCapturesGetter is a mock of CapturesGetter interface.
@@ -37,10 +38,10 @@ func (m *MockCapturesGetter) EXPECT() *MockCapturesGetterMockRecorder {
 }
 
 // Captures mocks base method.
-func (m *MockCapturesGetter) Captures() v2.CaptureInterface {
+func (m *MockCapturesGetter) Captures() v20.CaptureInterface {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Captures")
-	ret0, _ := ret[0].(v2.CaptureInterface)
+	ret0, _ := ret[0].(v20.CaptureInterface)
 	return ret0
 }
 
@@ -73,6 +74,51 @@ func (m *MockCaptureInterface) EXPECT() *MockCaptureInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CancelDrain mocks base method.
+func (m *MockCaptureInterface) CancelDrain(ctx context.Context, captureID string) (*model.DrainCaptureResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelDrain", ctx, captureID)
+	ret0, _ := ret[0].(*model.DrainCaptureResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelDrain indicates an expected call of CancelDrain.
+func (mr *MockCaptureInterfaceMockRecorder) CancelDrain(ctx, captureID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelDrain", reflect.TypeOf((*MockCaptureInterface)(nil).CancelDrain), ctx, captureID)
+}
+
+// Drain mocks base method.
+func (m *MockCaptureInterface) Drain(ctx context.Context, captureID string, cfg *v2.DrainCaptureConfig) (*model.DrainCaptureResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx, captureID, cfg)
+	ret0, _ := ret[0].(*model.DrainCaptureResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockCaptureInterfaceMockRecorder) Drain(ctx, captureID, cfg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockCaptureInterface)(nil).Drain), ctx, captureID, cfg)
+}
+
+// DrainStatus mocks base method.
+func (m *MockCaptureInterface) DrainStatus(ctx context.Context, captureID string) (*model.DrainCaptureResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainStatus", ctx, captureID)
+	ret0, _ := ret[0].(*model.DrainCaptureResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainStatus indicates an expected call of DrainStatus.
+func (mr *MockCaptureInterfaceMockRecorder) DrainStatus(ctx, captureID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainStatus", reflect.TypeOf((*MockCaptureInterface)(nil).DrainStatus), ctx, captureID)
+}
+
 // List mocks base method.
 func (m *MockCaptureInterface) List(ctx context.Context) ([]model.Capture, error) {
 	m.ctrl.T.Helper()
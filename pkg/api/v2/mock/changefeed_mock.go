@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	v20 "github.com/pingcap/tiflow/pkg/api/v2"
+	verification "github.com/pingcap/tiflow/pkg/verification"
 )
 
 // MockChangefeedsGetter is a mock of ChangefeedsGetter interface.
@@ -73,6 +74,34 @@ func (m *MockChangefeedInterface) EXPECT() *MockChangefeedInterfaceMockRecorder
 	return m.recorder
 }
 
+// BackfillTable mocks base method.
+func (m *MockChangefeedInterface) BackfillTable(ctx context.Context, cfg *v2.BackfillTableConfig, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackfillTable", ctx, cfg, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BackfillTable indicates an expected call of BackfillTable.
+func (mr *MockChangefeedInterfaceMockRecorder) BackfillTable(ctx, cfg, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackfillTable", reflect.TypeOf((*MockChangefeedInterface)(nil).BackfillTable), ctx, cfg, namespace, name)
+}
+
+// CancelVerification mocks base method.
+func (m *MockChangefeedInterface) CancelVerification(ctx context.Context, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelVerification", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelVerification indicates an expected call of CancelVerification.
+func (mr *MockChangefeedInterfaceMockRecorder) CancelVerification(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelVerification", reflect.TypeOf((*MockChangefeedInterface)(nil).CancelVerification), ctx, namespace, name)
+}
+
 // Create mocks base method.
 func (m *MockChangefeedInterface) Create(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -89,90 +118,164 @@ func (mr *MockChangefeedInterfaceMockRecorder) Create(ctx, cfg interface{}) *gom
 }
 
 // Delete mocks base method.
-func (m *MockChangefeedInterface) Delete(ctx context.Context, name string) error {
+func (m *MockChangefeedInterface) Delete(ctx context.Context, namespace, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret := m.ctrl.Call(m, "Delete", ctx, namespace, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockChangefeedInterfaceMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Delete(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChangefeedInterface)(nil).Delete), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChangefeedInterface)(nil).Delete), ctx, namespace, name)
 }
 
 // Get mocks base method.
-func (m *MockChangefeedInterface) Get(ctx context.Context, name string) (*v2.ChangeFeedInfo, error) {
+func (m *MockChangefeedInterface) Get(ctx context.Context, namespace, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret := m.ctrl.Call(m, "Get", ctx, namespace, name)
 	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockChangefeedInterfaceMockRecorder) Get(ctx, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Get(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChangefeedInterface)(nil).Get), ctx, namespace, name)
+}
+
+// GetHeldDDL mocks base method.
+func (m *MockChangefeedInterface) GetHeldDDL(ctx context.Context, namespace, name string) (*v2.HeldDDL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeldDDL", ctx, namespace, name)
+	ret0, _ := ret[0].(*v2.HeldDDL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeldDDL indicates an expected call of GetHeldDDL.
+func (mr *MockChangefeedInterfaceMockRecorder) GetHeldDDL(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeldDDL", reflect.TypeOf((*MockChangefeedInterface)(nil).GetHeldDDL), ctx, namespace, name)
+}
+
+// GetVerification mocks base method.
+func (m *MockChangefeedInterface) GetVerification(ctx context.Context, namespace, name string) (*verification.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerification", ctx, namespace, name)
+	ret0, _ := ret[0].(*verification.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVerification indicates an expected call of GetVerification.
+func (mr *MockChangefeedInterfaceMockRecorder) GetVerification(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerification", reflect.TypeOf((*MockChangefeedInterface)(nil).GetVerification), ctx, namespace, name)
+}
+
+// HandleHeldDDL mocks base method.
+func (m *MockChangefeedInterface) HandleHeldDDL(ctx context.Context, cfg *v2.HandleDDLConfig, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleHeldDDL", ctx, cfg, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleHeldDDL indicates an expected call of HandleHeldDDL.
+func (mr *MockChangefeedInterfaceMockRecorder) HandleHeldDDL(ctx, cfg, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChangefeedInterface)(nil).Get), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleHeldDDL", reflect.TypeOf((*MockChangefeedInterface)(nil).HandleHeldDDL), ctx, cfg, namespace, name)
 }
 
 // List mocks base method.
-func (m *MockChangefeedInterface) List(ctx context.Context, state string) ([]v2.ChangefeedCommonInfo, error) {
+func (m *MockChangefeedInterface) List(ctx context.Context, namespace, state string) ([]v2.ChangefeedCommonInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, state)
+	ret := m.ctrl.Call(m, "List", ctx, namespace, state)
 	ret0, _ := ret[0].([]v2.ChangefeedCommonInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockChangefeedInterfaceMockRecorder) List(ctx, state interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) List(ctx, namespace, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockChangefeedInterface)(nil).List), ctx, namespace, state)
+}
+
+// ListEvents mocks base method.
+func (m *MockChangefeedInterface) ListEvents(ctx context.Context, namespace, name string) ([]v2.ChangefeedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, namespace, name)
+	ret0, _ := ret[0].([]v2.ChangefeedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockChangefeedInterfaceMockRecorder) ListEvents(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockChangefeedInterface)(nil).List), ctx, state)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockChangefeedInterface)(nil).ListEvents), ctx, namespace, name)
 }
 
 // Pause mocks base method.
-func (m *MockChangefeedInterface) Pause(ctx context.Context, name string) error {
+func (m *MockChangefeedInterface) Pause(ctx context.Context, namespace, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Pause", ctx, name)
+	ret := m.ctrl.Call(m, "Pause", ctx, namespace, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Pause indicates an expected call of Pause.
-func (mr *MockChangefeedInterfaceMockRecorder) Pause(ctx, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Pause(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockChangefeedInterface)(nil).Pause), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockChangefeedInterface)(nil).Pause), ctx, namespace, name)
 }
 
 // Resume mocks base method.
-func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error {
+func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, namespace, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Resume", ctx, cfg, name)
+	ret := m.ctrl.Call(m, "Resume", ctx, cfg, namespace, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Resume indicates an expected call of Resume.
-func (mr *MockChangefeedInterfaceMockRecorder) Resume(ctx, cfg, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Resume(ctx, cfg, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockChangefeedInterface)(nil).Resume), ctx, cfg, namespace, name)
+}
+
+// StartVerification mocks base method.
+func (m *MockChangefeedInterface) StartVerification(ctx context.Context, cfg *v2.VerifyConfig, namespace, name string) (*verification.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVerification", ctx, cfg, namespace, name)
+	ret0, _ := ret[0].(*verification.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartVerification indicates an expected call of StartVerification.
+func (mr *MockChangefeedInterfaceMockRecorder) StartVerification(ctx, cfg, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockChangefeedInterface)(nil).Resume), ctx, cfg, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVerification", reflect.TypeOf((*MockChangefeedInterface)(nil).StartVerification), ctx, cfg, namespace, name)
 }
 
 // Update mocks base method.
-func (m *MockChangefeedInterface) Update(ctx context.Context, cfg *v2.ChangefeedConfig, name string) (*v2.ChangeFeedInfo, error) {
+func (m *MockChangefeedInterface) Update(ctx context.Context, cfg *v2.ChangefeedConfig, namespace, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, cfg, name)
+	ret := m.ctrl.Call(m, "Update", ctx, cfg, namespace, name)
 	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockChangefeedInterfaceMockRecorder) Update(ctx, cfg, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Update(ctx, cfg, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChangefeedInterface)(nil).Update), ctx, cfg, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChangefeedInterface)(nil).Update), ctx, cfg, namespace, name)
 }
 
 // VerifyTable mocks base method.
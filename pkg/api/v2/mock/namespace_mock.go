@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/api/v2/namespace.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	v20 "github.com/pingcap/tiflow/pkg/api/v2"
+)
+
+// MockNamespacesGetter is a mock of NamespacesGetter interface.
+type MockNamespacesGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockNamespacesGetterMockRecorder
+}
+
+// MockNamespacesGetterMockRecorder is the mock recorder for MockNamespacesGetter.
+type MockNamespacesGetterMockRecorder struct {
+	mock *MockNamespacesGetter
+}
+
+// NewMockNamespacesGetter creates a new mock instance.
+func NewMockNamespacesGetter(ctrl *gomock.Controller) *MockNamespacesGetter {
+	mock := &MockNamespacesGetter{ctrl: ctrl}
+	mock.recorder = &MockNamespacesGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNamespacesGetter) EXPECT() *MockNamespacesGetterMockRecorder {
+	return m.recorder
+}
+
+// Namespaces mocks base method.
+func (m *MockNamespacesGetter) Namespaces() v20.NamespaceInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Namespaces")
+	ret0, _ := ret[0].(v20.NamespaceInterface)
+	return ret0
+}
+
+// Namespaces indicates an expected call of Namespaces.
+func (mr *MockNamespacesGetterMockRecorder) Namespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Namespaces", reflect.TypeOf((*MockNamespacesGetter)(nil).Namespaces))
+}
+
+// MockNamespaceInterface is a mock of NamespaceInterface interface.
+type MockNamespaceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockNamespaceInterfaceMockRecorder
+}
+
+// MockNamespaceInterfaceMockRecorder is the mock recorder for MockNamespaceInterface.
+type MockNamespaceInterfaceMockRecorder struct {
+	mock *MockNamespaceInterface
+}
+
+// NewMockNamespaceInterface creates a new mock instance.
+func NewMockNamespaceInterface(ctrl *gomock.Controller) *MockNamespaceInterface {
+	mock := &MockNamespaceInterface{ctrl: ctrl}
+	mock.recorder = &MockNamespaceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNamespaceInterface) EXPECT() *MockNamespaceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockNamespaceInterface) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockNamespaceInterfaceMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNamespaceInterface)(nil).Delete), ctx, name)
+}
+
+// Get mocks base method.
+func (m *MockNamespaceInterface) Get(ctx context.Context, name string) (*v2.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret0, _ := ret[0].(*v2.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNamespaceInterfaceMockRecorder) Get(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNamespaceInterface)(nil).Get), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockNamespaceInterface) List(ctx context.Context) ([]v2.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]v2.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockNamespaceInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNamespaceInterface)(nil).List), ctx)
+}
+
+// Put mocks base method.
+func (m *MockNamespaceInterface) Put(ctx context.Context, info *v2.NamespaceInfo) (*v2.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, info)
+	ret0, _ := ret[0].(*v2.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockNamespaceInterfaceMockRecorder) Put(ctx, info interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockNamespaceInterface)(nil).Put), ctx, info)
+}
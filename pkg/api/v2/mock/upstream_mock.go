@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/api/v2/upstream.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	v20 "github.com/pingcap/tiflow/pkg/api/v2"
+)
+
+// MockUpstreamsGetter is a mock of UpstreamsGetter interface.
+type MockUpstreamsGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpstreamsGetterMockRecorder
+}
+
+// MockUpstreamsGetterMockRecorder is the mock recorder for MockUpstreamsGetter.
+type MockUpstreamsGetterMockRecorder struct {
+	mock *MockUpstreamsGetter
+}
+
+// NewMockUpstreamsGetter creates a new mock instance.
+func NewMockUpstreamsGetter(ctrl *gomock.Controller) *MockUpstreamsGetter {
+	mock := &MockUpstreamsGetter{ctrl: ctrl}
+	mock.recorder = &MockUpstreamsGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpstreamsGetter) EXPECT() *MockUpstreamsGetterMockRecorder {
+	return m.recorder
+}
+
+// Upstreams mocks base method.
+func (m *MockUpstreamsGetter) Upstreams() v20.UpstreamInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upstreams")
+	ret0, _ := ret[0].(v20.UpstreamInterface)
+	return ret0
+}
+
+// Upstreams indicates an expected call of Upstreams.
+func (mr *MockUpstreamsGetterMockRecorder) Upstreams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upstreams", reflect.TypeOf((*MockUpstreamsGetter)(nil).Upstreams))
+}
+
+// MockUpstreamInterface is a mock of UpstreamInterface interface.
+type MockUpstreamInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpstreamInterfaceMockRecorder
+}
+
+// MockUpstreamInterfaceMockRecorder is the mock recorder for MockUpstreamInterface.
+type MockUpstreamInterfaceMockRecorder struct {
+	mock *MockUpstreamInterface
+}
+
+// NewMockUpstreamInterface creates a new mock instance.
+func NewMockUpstreamInterface(ctrl *gomock.Controller) *MockUpstreamInterface {
+	mock := &MockUpstreamInterface{ctrl: ctrl}
+	mock.recorder = &MockUpstreamInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpstreamInterface) EXPECT() *MockUpstreamInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockUpstreamInterface) Delete(ctx context.Context, upstreamID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, upstreamID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUpstreamInterfaceMockRecorder) Delete(ctx, upstreamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUpstreamInterface)(nil).Delete), ctx, upstreamID)
+}
+
+// List mocks base method.
+func (m *MockUpstreamInterface) List(ctx context.Context) ([]v2.UpstreamStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]v2.UpstreamStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUpstreamInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUpstreamInterface)(nil).List), ctx)
+}
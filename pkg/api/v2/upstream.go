@@ -0,0 +1,64 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// UpstreamsGetter has a method to return a UpstreamInterface.
+type UpstreamsGetter interface {
+	Upstreams() UpstreamInterface
+}
+
+// UpstreamInterface has methods to work with Upstream items.
+// We can also mock the upstream operations by implement this interface.
+type UpstreamInterface interface {
+	List(ctx context.Context) ([]v2.UpstreamStatus, error)
+	Delete(ctx context.Context, upstreamID uint64) error
+}
+
+// upstreams implements UpstreamInterface
+type upstreams struct {
+	client rest.CDCRESTInterface
+}
+
+// newUpstreams returns upstreams
+func newUpstreams(c *APIV2Client) *upstreams {
+	return &upstreams{
+		client: c.RESTClient(),
+	}
+}
+
+// List returns the list of upstreams
+func (c *upstreams) List(ctx context.Context) ([]v2.UpstreamStatus, error) {
+	result := &v2.ListResponse[v2.UpstreamStatus]{}
+	err := c.client.Get().
+		WithURI("upstreams").
+		Do(ctx).
+		Into(result)
+	return result.Items, err
+}
+
+// Delete removes an idle upstream
+func (c *upstreams) Delete(ctx context.Context, upstreamID uint64) error {
+	u := fmt.Sprintf("upstreams/%d", upstreamID)
+	return c.client.Delete().
+		WithURI(u).
+		Do(ctx).Error()
+}
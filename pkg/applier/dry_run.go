@@ -0,0 +1,112 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+)
+
+var sqlStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// printDDL prints the DDL in the same way as it is executed by the DDL sink.
+func (ra *RedoApplier) printDDL(ddl *model.DDLEvent) error {
+	var builder strings.Builder
+	if schema := ddl.TableInfo.TableName.Schema; schema != "" {
+		builder.WriteString("USE " + quotes.QuoteName(schema) + ";\n")
+	}
+	builder.WriteString(strings.TrimSuffix(strings.TrimSpace(ddl.Query), ";") + ";\n")
+	return ra.write(builder.String())
+}
+
+// printRow prints the row change in the same way as it is executed by the
+// sink in safe mode, that is, an update is split into a delete and a replace.
+func (ra *RedoApplier) printRow(row *model.RowChangedEvent) error {
+	quoteTable := quotes.QuoteSchema(row.Table.Schema, row.Table.Table)
+	var builder strings.Builder
+	if len(row.PreColumns) != 0 {
+		builder.WriteString(formatDelete(quoteTable, row.PreColumns) + "\n")
+	}
+	if len(row.Columns) != 0 {
+		builder.WriteString(formatReplace(quoteTable, row.Columns) + "\n")
+	}
+	return ra.write(builder.String())
+}
+
+func (ra *RedoApplier) write(s string) error {
+	if _, err := fmt.Fprint(ra.cfg.Output, s); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func formatReplace(quoteTable string, cols []*model.Column) string {
+	names := make([]string, 0, len(cols))
+	values := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col == nil || col.Flag.IsGeneratedColumn() {
+			continue
+		}
+		names = append(names, quotes.QuoteName(col.Name))
+		values = append(values, formatValue(col))
+	}
+	return fmt.Sprintf("REPLACE INTO %s (%s) VALUES (%s);", quoteTable,
+		strings.Join(names, ","), strings.Join(values, ","))
+}
+
+func formatDelete(quoteTable string, cols []*model.Column) string {
+	hasHandleKey := false
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			hasHandleKey = true
+			break
+		}
+	}
+	conds := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col == nil || col.Flag.IsGeneratedColumn() ||
+			(hasHandleKey && !col.Flag.IsHandleKey()) {
+			continue
+		}
+		if col.Value == nil {
+			conds = append(conds, quotes.QuoteName(col.Name)+" IS NULL")
+		} else {
+			conds = append(conds, quotes.QuoteName(col.Name)+" = "+formatValue(col))
+		}
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s LIMIT 1;",
+		quoteTable, strings.Join(conds, " AND "))
+}
+
+func formatValue(col *model.Column) string {
+	switch v := col.Value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + sqlStringEscaper.Replace(v) + "'"
+	case []byte:
+		if col.Charset == "" || col.Charset == charset.CharsetBin {
+			return "x'" + hex.EncodeToString(v) + "'"
+		}
+		return "'" + sqlStringEscaper.Replace(string(v)) + "'"
+	default:
+		return model.ColumnValueString(v)
+	}
+}
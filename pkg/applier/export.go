@@ -0,0 +1,251 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// applyProgress is persisted in RedoApplierConfig.ProgressFile, so that an
+// interrupted apply can be resumed after the applied ts.
+type applyProgress struct {
+	// AppliedTs is the ts all events before or at which have been flushed.
+	AppliedTs model.Ts `json:"applied-ts"`
+}
+
+func (ra *RedoApplier) loadProgress() (model.Ts, error) {
+	if ra.cfg.ProgressFile == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(ra.cfg.ProgressFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	var progress applyProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return 0, errors.WrapError(errors.ErrUnmarshalFailed, err)
+	}
+	return progress.AppliedTs, nil
+}
+
+func (ra *RedoApplier) saveProgress(appliedTs model.Ts) error {
+	if ra.cfg.ProgressFile == "" || ra.cfg.DryRun {
+		return nil
+	}
+	if ra.tableInfos != nil {
+		appliedTs = ra.tableInfos.maxProgress(appliedTs)
+	}
+	data, err := json.Marshal(&applyProgress{AppliedTs: appliedTs})
+	if err != nil {
+		return errors.WrapError(errors.ErrMarshalFailed, err)
+	}
+	// Write to a temporary file first to avoid leaving a broken progress file.
+	tmpFile := ra.cfg.ProgressFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	if err := os.Rename(tmpFile, ra.cfg.ProgressFile); err != nil {
+		return errors.WrapError(errors.ErrRedoFileOp, err)
+	}
+	return nil
+}
+
+// tableInfoCache rebuilds table infos from columns of redo logs. Table infos
+// are not recorded in redo logs, but they are required by encoders of MQ and
+// storage sinks.
+type tableInfoCache struct {
+	// startTs is the version of tables which are not changed by any DDL.
+	startTs model.Ts
+	// versions records commit ts of the last DDL of each table.
+	versions map[model.TableName]model.Ts
+	infos    map[model.TableID]*model.TableInfo
+	// pendingDDLs are table DDLs waiting for columns of the table, it is nil
+	// if DDLs are not deferred.
+	pendingDDLs map[model.TableName]*model.DDLEvent
+}
+
+// newTableInfoCache creates a tableInfoCache. If deferDDLs is true, table
+// DDLs are deferred until columns of the table are known, it is required by
+// storage sinks which write columns of the table into schema files.
+func newTableInfoCache(startTs model.Ts, deferDDLs bool) *tableInfoCache {
+	c := &tableInfoCache{
+		startTs:  startTs,
+		versions: make(map[model.TableName]model.Ts),
+		infos:    make(map[model.TableID]*model.TableInfo),
+	}
+	if deferDDLs {
+		c.pendingDDLs = make(map[model.TableName]*model.DDLEvent)
+	}
+	return c
+}
+
+// fillDDL sets the table info of the DDL. Columns of the table are unknown
+// until rows after the DDL are read, so only the table name is recorded.
+// It returns true if the DDL is deferred until the next row of the table,
+// see takeDDL.
+func (c *tableInfoCache) fillDDL(ddl *model.DDLEvent) bool {
+	name := ddl.TableInfo.TableName
+	ddl.TableInfo = &model.TableInfo{
+		TableInfo: &timodel.TableInfo{Name: timodel.NewCIStr(name.Table)},
+		TableName: name,
+		Version:   ddl.CommitTs,
+	}
+	if name.Table == "" {
+		return false
+	}
+	key := model.TableName{Schema: name.Schema, Table: name.Table}
+	c.versions[key] = ddl.CommitTs
+	if c.pendingDDLs == nil {
+		return false
+	}
+	if prev, ok := c.pendingDDLs[key]; ok {
+		// No rows are written in the version of the previous DDL.
+		logSkippedDDL(prev)
+	}
+	c.pendingDDLs[key] = ddl
+	return true
+}
+
+// skipPendingDDLs drops DDLs which are not followed by any rows, columns of
+// their tables are unknown, and there is no data in their versions.
+func (c *tableInfoCache) skipPendingDDLs() {
+	for key, ddl := range c.pendingDDLs {
+		logSkippedDDL(ddl)
+		delete(c.pendingDDLs, key)
+	}
+}
+
+// maxProgress returns the largest progress not after ts which can be saved.
+// Deferred DDLs are not written yet, so the progress must be saved before
+// them to read them again after the apply is resumed.
+func (c *tableInfoCache) maxProgress(ts model.Ts) model.Ts {
+	for _, ddl := range c.pendingDDLs {
+		if ddl.CommitTs <= ts {
+			ts = ddl.CommitTs - 1
+		}
+	}
+	return ts
+}
+
+func logSkippedDDL(ddl *model.DDLEvent) {
+	log.Warn("skip the schema file of the DDL without following rows",
+		zap.String("query", ddl.Query), zap.Uint64("commitTs", ddl.CommitTs))
+}
+
+// takeDDL returns the deferred DDL of the table of the row, with the table
+// info built from the row. It returns nil if there is no deferred DDL.
+func (c *tableInfoCache) takeDDL(row *model.RowChangedEvent) *model.DDLEvent {
+	key := model.TableName{Schema: row.Table.Schema, Table: row.Table.Table}
+	ddl, ok := c.pendingDDLs[key]
+	if !ok || row.TableInfo.Version != ddl.CommitTs {
+		return nil
+	}
+	delete(c.pendingDDLs, key)
+	ddl.TableInfo = row.TableInfo
+	return ddl
+}
+
+// fillRow sets the table info and column infos of the row.
+func (c *tableInfoCache) fillRow(row *model.RowChangedEvent) {
+	version, ok := c.versions[model.TableName{Schema: row.Table.Schema, Table: row.Table.Table}]
+	if !ok {
+		version = c.startTs
+	}
+	cols := row.Columns
+	if len(cols) == 0 {
+		cols = row.PreColumns
+	}
+	info, ok := c.infos[row.Table.TableID]
+	if !ok || info.Version != version || !columnsMatch(info, cols) {
+		info = newTableInfo(row.Table, cols, row.ColInfos, version)
+		c.infos[row.Table.TableID] = info
+	}
+	row.TableInfo = info
+	_, _, row.ColInfos = info.GetRowColInfos()
+}
+
+func columnsMatch(info *model.TableInfo, cols []*model.Column) bool {
+	if len(info.Columns) != len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		if col != nil && (info.Columns[i].Name.O != col.Name ||
+			info.Columns[i].GetType() != col.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// newTableInfo builds a table info from columns of a row. Field types of the
+// columns are taken from colInfos read from redo logs if they are recorded.
+func newTableInfo(
+	name *model.TableName, cols []*model.Column,
+	colInfos []rowcodec.ColInfo, version model.Ts,
+) *model.TableInfo {
+	info := &timodel.TableInfo{
+		ID:      name.TableID,
+		Name:    timodel.NewCIStr(name.Table),
+		Columns: make([]*timodel.ColumnInfo, 0, len(cols)),
+	}
+	for i, col := range cols {
+		colInfo := &timodel.ColumnInfo{
+			ID:     int64(i + 1),
+			Offset: i,
+			State:  timodel.StatePublic,
+		}
+		if col == nil {
+			colInfo.FieldType = *types.NewFieldType(mysql.TypeNull)
+			info.Columns = append(info.Columns, colInfo)
+			continue
+		}
+		colInfo.Name = timodel.NewCIStr(col.Name)
+		colInfo.FieldType = *types.NewFieldType(col.Type)
+		if i < len(colInfos) && colInfos[i].Ft != nil {
+			colInfo.SetFlen(colInfos[i].Ft.GetFlen())
+			colInfo.SetDecimal(colInfos[i].Ft.GetDecimal())
+		}
+		colInfo.SetCharset(col.Charset)
+		if col.Flag.IsPrimaryKey() {
+			colInfo.AddFlag(mysql.PriKeyFlag)
+		}
+		if col.Flag.IsUniqueKey() {
+			colInfo.AddFlag(mysql.UniqueKeyFlag)
+		}
+		if !col.Flag.IsNullable() {
+			colInfo.AddFlag(mysql.NotNullFlag)
+		}
+		if col.Flag.IsUnsigned() {
+			colInfo.AddFlag(mysql.UnsignedFlag)
+		}
+		if col.Flag.IsBinary() {
+			colInfo.AddFlag(mysql.BinaryFlag)
+		}
+		info.Columns = append(info.Columns, colInfo)
+	}
+	return model.WrapTableInfo(0, name.Schema, version, info)
+}
@@ -0,0 +1,224 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportToStorage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	newRow := func(commitTs uint64, id int, cols ...*model.Column) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			CommitTs: commitTs,
+			Table:    &model.TableName{Schema: "test", Table: "t1", TableID: 100},
+			Columns: append([]*model.Column{{
+				Name: "a", Type: mysql.TypeLong, Value: id,
+				Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+			}}, cols...),
+		}
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		redoLogCh := make(chan *model.RowChangedEvent, 1024)
+		ddlEventCh := make(chan *model.DDLEvent, 1024)
+		redoLogCh <- newRow(1100, 1)
+		redoLogCh <- newRow(1300, 2, &model.Column{Name: "b", Type: mysql.TypeLong, Value: 2})
+		ddlEventCh <- &model.DDLEvent{
+			CommitTs: 1200,
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: "t1"},
+			},
+			Query: "alter table t1 add column b int",
+			Type:  timodel.ActionAddColumn,
+		}
+		close(redoLogCh)
+		close(ddlEventCh)
+		return NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh), nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	dir := t.TempDir()
+	cfg := &RedoApplierConfig{
+		SinkURI:      fmt.Sprintf("file:///%s/output?flush-interval=2s", dir),
+		Protocol:     "csv",
+		ProgressFile: filepath.Join(dir, "progress"),
+	}
+	require.NoError(t, NewRedoApplier(cfg).Apply(ctx))
+
+	readDataFiles := func() string {
+		var data []string
+		err := filepath.Walk(filepath.Join(dir, "output"), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || filepath.Ext(path) != ".csv" {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			data = append(data, strings.TrimPrefix(path, dir)+":"+string(content))
+			return err
+		})
+		require.NoError(t, err)
+		return strings.Join(data, "")
+	}
+	// Rows before and after the DDL are written to different versions.
+	exported := readDataFiles()
+	require.Contains(t, exported, "/output/test/t1/1000/")
+	require.Contains(t, exported, "\"I\",\"t1\",\"test\",1\r\n")
+	require.Contains(t, exported, "/output/test/t1/1200/")
+	require.Contains(t, exported, "\"I\",\"t1\",\"test\",2,2\r\n")
+	schema, err := os.ReadFile(filepath.Join(dir, "output/test/t1/1200/schema.json"))
+	require.NoError(t, err)
+	// The schema file of the DDL is written with columns of following rows.
+	var def cloudstorage.TableDefinition
+	require.NoError(t, json.Unmarshal(schema, &def))
+	require.Equal(t, "alter table t1 add column b int", def.Query)
+	require.Equal(t, uint64(1200), def.TableVersion)
+	require.Len(t, def.Columns, 2)
+	require.Equal(t, "a", def.Columns[0].Name)
+	require.Equal(t, "b", def.Columns[1].Name)
+
+	progress, err := NewRedoApplier(cfg).loadProgress()
+	require.NoError(t, err)
+	require.Equal(t, resolvedTs, progress)
+
+	// Events applied before are skipped after the export is resumed.
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "output")))
+	require.NoError(t, NewRedoApplier(cfg).saveProgress(1200))
+	require.NoError(t, NewRedoApplier(cfg).Apply(ctx))
+	exported = readDataFiles()
+	require.NotContains(t, exported, "/output/test/t1/1000/")
+	require.Contains(t, exported, "\"I\",\"t1\",\"test\",2,2\r\n")
+}
+
+func TestExportResumeAcrossDeferredDDL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	progressIntervalBak := progressInterval
+	progressInterval = 0
+	defer func() {
+		progressInterval = progressIntervalBak
+	}()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	newRow := func(commitTs uint64, table string, tableID int64, cols int) *model.RowChangedEvent {
+		row := &model.RowChangedEvent{
+			CommitTs: commitTs,
+			Table:    &model.TableName{Schema: "test", Table: table, TableID: tableID},
+		}
+		for i := 0; i < cols; i++ {
+			row.Columns = append(row.Columns, &model.Column{
+				Name: fmt.Sprintf("c%d", i), Type: mysql.TypeLong, Value: i,
+			})
+		}
+		return row
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		redoLogCh := make(chan *model.RowChangedEvent, 1024)
+		ddlEventCh := make(chan *model.DDLEvent, 1024)
+		redoLogCh <- newRow(1100, "t1", 100, 1)
+		redoLogCh <- newRow(1300, "t2", 101, 1)
+		redoLogCh <- newRow(1400, "t2", 101, 1)
+		redoLogCh <- newRow(1600, "t1", 100, 2)
+		ddlEventCh <- &model.DDLEvent{
+			CommitTs: 1200,
+			TableInfo: &model.TableInfo{
+				TableName: model.TableName{Schema: "test", Table: "t1"},
+			},
+			Query: "alter table t1 add column c1 int",
+			Type:  timodel.ActionAddColumn,
+		}
+		close(redoLogCh)
+		close(ddlEventCh)
+		return NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh), nil
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	dir := t.TempDir()
+	cfg := &RedoApplierConfig{
+		SinkURI:      fmt.Sprintf("file:///%s/output?flush-interval=2s", dir),
+		Protocol:     "csv",
+		ProgressFile: filepath.Join(dir, "progress"),
+		TargetTs:     1500,
+	}
+	schemaFile := filepath.Join(dir, "output/test/t1/1200/schema.json")
+
+	// The DDL is still deferred at the target ts, so the progress is saved
+	// before it even if rows of other tables after it are flushed.
+	require.NoError(t, NewRedoApplier(cfg).Apply(ctx))
+	_, err := os.Stat(schemaFile)
+	require.True(t, os.IsNotExist(err))
+	progress, err := NewRedoApplier(cfg).loadProgress()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1199), progress)
+
+	// The DDL is read again and written with rows of the table after resuming.
+	cfg.TargetTs = 0
+	require.NoError(t, NewRedoApplier(cfg).Apply(ctx))
+	schema, err := os.ReadFile(schemaFile)
+	require.NoError(t, err)
+	var def cloudstorage.TableDefinition
+	require.NoError(t, json.Unmarshal(schema, &def))
+	require.Equal(t, "alter table t1 add column c1 int", def.Query)
+	require.Len(t, def.Columns, 2)
+	progress, err = NewRedoApplier(cfg).loadProgress()
+	require.NoError(t, err)
+	require.Equal(t, resolvedTs, progress)
+}
+
+func TestNewTableInfoWithFieldTypes(t *testing.T) {
+	decimalType := types.NewFieldType(mysql.TypeNewDecimal)
+	decimalType.SetFlen(10)
+	decimalType.SetDecimal(2)
+	cols := []*model.Column{
+		{Name: "a", Type: mysql.TypeNewDecimal, Flag: model.UnsignedFlag | model.NullableFlag},
+		{Name: "b", Type: mysql.TypeLong},
+	}
+	name := &model.TableName{Schema: "test", Table: "t1", TableID: 100}
+
+	info := newTableInfo(name, cols, []rowcodec.ColInfo{{Ft: decimalType}, {}}, 1000)
+	require.Equal(t, 10, info.Columns[0].GetFlen())
+	require.Equal(t, 2, info.Columns[0].GetDecimal())
+	require.True(t, mysql.HasUnsignedFlag(info.Columns[0].GetFlag()))
+	require.False(t, mysql.HasNotNullFlag(info.Columns[0].GetFlag()))
+	require.True(t, mysql.HasNotNullFlag(info.Columns[1].GetFlag()))
+
+	// The default length is used if field types are not recorded.
+	info = newTableInfo(name, cols, nil, 1000)
+	require.Equal(t, types.UnspecifiedLength, info.Columns[0].GetFlen())
+}
@@ -15,7 +15,9 @@ package applier
 
 import (
 	"context"
+	"io"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/pingcap/log"
@@ -30,7 +32,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/tablesink"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -45,6 +49,9 @@ const (
 	flushWaitDuration = 200 * time.Millisecond
 )
 
+// progressInterval is the interval to flush all tables and save progress.
+var progressInterval = 10 * time.Second
+
 var (
 	// In the boundary case, non-idempotent DDLs will not be executed.
 	// TODO(CharlesCheung96): fix this
@@ -59,12 +66,34 @@ type RedoApplierConfig struct {
 	SinkURI string
 	Storage string
 	Dir     string
+	// StartTs is the ts redo logs are applied from, 0 means the checkpoint ts
+	// recorded in redo meta. It can be less than the checkpoint ts to apply
+	// logs retained before the checkpoint.
+	StartTs uint64
+	// TargetTs is the ts redo logs are applied up to, 0 means the resolved ts
+	// recorded in redo meta.
+	TargetTs uint64
+	// FilterRules are table filter rules with the same syntax as the filter
+	// rules of a changefeed, only events of matched tables are applied.
+	FilterRules []string
+	// DryRun prints SQL statements to Output instead of executing them in sink.
+	DryRun bool
+	Output io.Writer
+	// Protocol is the protocol used to encode events if the sink is a MQ or
+	// storage sink.
+	Protocol string
+	// ProgressFile records the applied ts, so that an interrupted apply can be
+	// resumed from it.
+	ProgressFile string
 }
 
 // RedoApplier implements a redo log applier
 type RedoApplier struct {
-	cfg *RedoApplierConfig
-	rd  reader.RedoLogReader
+	cfg    *RedoApplierConfig
+	rd     reader.RedoLogReader
+	filter filter.Filter
+	// tableInfos is only used by sinks which are not MySQL compatible.
+	tableInfos *tableInfoCache
 
 	ddlSink         ddlsink.Sink
 	appliedDDLCount uint64
@@ -85,6 +114,9 @@ type RedoApplier struct {
 
 // NewRedoApplier creates a new RedoApplier instance
 func NewRedoApplier(cfg *RedoApplierConfig) *RedoApplier {
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
 	return &RedoApplier{
 		cfg:   cfg,
 		errCh: make(chan error, 1024),
@@ -105,6 +137,7 @@ func (rac *RedoApplierConfig) toLogReaderConfig() (string, *reader.LogReaderConf
 		URI:                *uri,
 		Dir:                rac.Dir,
 		UseExternalStorage: redo.IsExternalStorage(uri.Scheme),
+		StartTs:            rac.StartTs,
 	}
 	return uri.Scheme, cfg, nil
 }
@@ -120,8 +153,28 @@ func (ra *RedoApplier) catchError(ctx context.Context) error {
 	}
 }
 
-func (ra *RedoApplier) initSink(ctx context.Context) (err error) {
+func (ra *RedoApplier) initFilter() (err error) {
 	replicaConfig := config.GetDefaultReplicaConfig()
+	if len(ra.cfg.FilterRules) != 0 {
+		replicaConfig.Filter.Rules = ra.cfg.FilterRules
+	}
+	ra.filter, err = filter.NewFilter(replicaConfig, "")
+	return err
+}
+
+func (ra *RedoApplier) initSink(ctx context.Context, startTs model.Ts) (err error) {
+	replicaConfig := config.GetDefaultReplicaConfig()
+	sinkURI, err := url.Parse(ra.cfg.SinkURI)
+	if err != nil {
+		return errors.WrapError(errors.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		replicaConfig.Sink.Protocol = ra.cfg.Protocol
+		if err := replicaConfig.ValidateAndAdjust(sinkURI); err != nil {
+			return err
+		}
+		ra.tableInfos = newTableInfoCache(startTs, sink.IsStorageScheme(sinkURI.Scheme))
+	}
 	ra.sinkFactory, err = dmlfactory.New(ctx, ra.cfg.SinkURI, replicaConfig, ra.errCh)
 	if err != nil {
 		return err
@@ -157,13 +210,49 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	_, compactedTs, err := ra.rd.ReadCompactionMeta(ctx)
+	if err != nil {
+		return err
+	}
+	// The reader only reads events after the start ts.
+	startTs := checkpointTs
+	if ra.cfg.StartTs != 0 {
+		startTs = ra.cfg.StartTs
+	}
+	logResolvedTs := resolvedTs
+	if ra.cfg.TargetTs != 0 {
+		// Compaction keeps all updates after the retained ts, so logs can be
+		// applied up to any ts after the start ts.
+		if ra.cfg.TargetTs < startTs || ra.cfg.TargetTs > resolvedTs {
+			return errors.ErrRedoApplyTargetTsInvalid.GenWithStackByArgs(
+				ra.cfg.TargetTs, startTs, resolvedTs)
+		}
+		resolvedTs = ra.cfg.TargetTs
+	}
+	appliedTs, err := ra.loadProgress()
+	if err != nil {
+		return err
+	}
+	if appliedTs > startTs {
+		startTs = appliedTs
+	}
 	log.Info("apply redo log starts",
 		zap.Uint64("checkpointTs", checkpointTs),
-		zap.Uint64("resolvedTs", resolvedTs))
-	if err := ra.initSink(ctx); err != nil {
+		zap.Uint64("startTs", startTs),
+		zap.Uint64("compactedTs", compactedTs),
+		zap.Uint64("resolvedTs", resolvedTs),
+		zap.Uint64("appliedTs", appliedTs),
+		zap.Strings("filterRules", ra.cfg.FilterRules),
+		zap.Bool("dryRun", ra.cfg.DryRun))
+	if err := ra.initFilter(); err != nil {
 		return err
 	}
-	defer ra.sinkFactory.Close()
+	if !ra.cfg.DryRun {
+		if err := ra.initSink(ctx, startTs); err != nil {
+			return err
+		}
+		defer ra.sinkFactory.Close()
+	}
 
 	shouldApplyDDL := func(row *model.RowChangedEvent, ddl *model.DDLEvent) bool {
 		if ddl == nil {
@@ -177,11 +266,54 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 		return row.CommitTs > ddl.CommitTs
 	}
 
-	row, err := ra.rd.ReadNextRow(ctx)
+	// Logs are read in the order of commit ts, so events applied before are
+	// skipped, and reading stops at the first event after resolvedTs.
+	readNextRow := func() (*model.RowChangedEvent, error) {
+		for {
+			row, err := ra.rd.ReadNextRow(ctx)
+			if err != nil || row == nil || row.CommitTs > resolvedTs {
+				return nil, err
+			}
+			if row.CommitTs > appliedTs {
+				return row, nil
+			}
+		}
+	}
+	readNextDDL := func() (*model.DDLEvent, error) {
+		for {
+			ddl, err := ra.rd.ReadNextDDL(ctx)
+			if err != nil || ddl == nil || ddl.CommitTs > resolvedTs {
+				return nil, err
+			}
+			if ddl.CommitTs > appliedTs {
+				return ddl, nil
+			}
+		}
+	}
+
+	// Progress is saved periodically at transaction boundaries, after all
+	// rows before the boundary are flushed.
+	lastCommitTs := startTs
+	lastSaveTime := time.Now()
+	maybeSaveProgress := func(nextCommitTs model.Ts) error {
+		if ra.cfg.ProgressFile == "" || ra.cfg.DryRun ||
+			nextCommitTs <= lastCommitTs || time.Since(lastSaveTime) < progressInterval {
+			return nil
+		}
+		for tableID := range ra.tableSinks {
+			if err := ra.waitTableFlush(ctx, tableID, lastCommitTs); err != nil {
+				return err
+			}
+		}
+		lastSaveTime = time.Now()
+		return ra.saveProgress(lastCommitTs)
+	}
+
+	row, err := readNextRow()
 	if err != nil {
 		return err
 	}
-	ddl, err := ra.rd.ReadNextDDL(ctx)
+	ddl, err := readNextDDL()
 	if err != nil {
 		return err
 	}
@@ -193,18 +325,34 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 			if err := ra.applyDDL(ctx, ddl, checkpointTs); err != nil {
 				return err
 			}
-			if ddl, err = ra.rd.ReadNextDDL(ctx); err != nil {
+			if ddl.CommitTs > lastCommitTs {
+				lastCommitTs = ddl.CommitTs
+			}
+			if ddl, err = readNextDDL(); err != nil {
 				return err
 			}
 		} else {
-			if err := ra.applyRow(row, checkpointTs); err != nil {
-				return err
+			if !ra.filter.ShouldIgnoreTable(row.Table.Schema, row.Table.Table) {
+				if err := maybeSaveProgress(row.CommitTs); err != nil {
+					return err
+				}
+				if err := ra.applyRow(ctx, row, startTs); err != nil {
+					return err
+				}
+				lastCommitTs = row.CommitTs
 			}
-			if row, err = ra.rd.ReadNextRow(ctx); err != nil {
+			if row, err = readNextRow(); err != nil {
 				return err
 			}
 		}
 	}
+	// DDLs deferred at the end of logs are not followed by any rows. If the
+	// apply stops at a target ts before the end, they are kept pending, so
+	// that the progress is saved before them and they are read again after
+	// the apply is resumed.
+	if ra.tableInfos != nil && resolvedTs == logResolvedTs {
+		ra.tableInfos.skipPendingDDLs()
+	}
 	// wait all tables to flush data
 	for tableID := range ra.tableResolvedTsMap {
 		if err := ra.waitTableFlush(ctx, tableID, resolvedTs); err != nil {
@@ -212,6 +360,9 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 		}
 		ra.tableSinks[tableID].Close()
 	}
+	if err := ra.saveProgress(resolvedTs); err != nil {
+		return err
+	}
 
 	log.Info("apply redo log finishes",
 		zap.Uint64("appliedLogCount", ra.appliedLogCount),
@@ -272,11 +423,19 @@ func (ra *RedoApplier) applyDDL(
 			log.Warn("ignore DDL without table info", zap.Any("ddl", ddl))
 			return true
 		}
-		return false
+		tableName := ddl.TableInfo.TableName
+		if tableName.Table == "" {
+			return ra.filter.ShouldIgnoreSchema(tableName.Schema)
+		}
+		return ra.filter.ShouldIgnoreTable(tableName.Schema, tableName.Table)
 	}
 	if shouldSkip() {
 		return nil
 	}
+	if ra.cfg.DryRun {
+		ra.appliedDDLCount++
+		return ra.printDDL(ddl)
+	}
 	log.Warn("apply DDL", zap.Any("ddl", ddl))
 	// Wait all tables to flush data before applying DDL.
 	// TODO: only block tables that are affected by this DDL.
@@ -285,16 +444,25 @@ func (ra *RedoApplier) applyDDL(
 			return err
 		}
 	}
+	if ra.tableInfos != nil && ra.tableInfos.fillDDL(ddl) {
+		// The DDL is written with the next row of the table.
+		return nil
+	}
 	if err := ra.ddlSink.WriteDDLEvent(ctx, ddl); err != nil {
 		return err
 	}
 	ra.appliedDDLCount++
-	return nil
+	return ra.saveProgress(ddl.CommitTs)
 }
 
 func (ra *RedoApplier) applyRow(
-	row *model.RowChangedEvent, checkpointTs model.Ts,
+	ctx context.Context, row *model.RowChangedEvent, checkpointTs model.Ts,
 ) error {
+	if ra.cfg.DryRun {
+		ra.appliedLogCount++
+		return ra.printRow(row)
+	}
+
 	rowSize := uint64(row.ApproximateBytes())
 	if rowSize > ra.pendingQuota {
 		if err := ra.resetQuota(uint64(row.ApproximateBytes())); err != nil {
@@ -325,6 +493,15 @@ func (ra *RedoApplier) applyRow(
 		}
 	}
 
+	if ra.tableInfos != nil {
+		ra.tableInfos.fillRow(row)
+		if ddl := ra.tableInfos.takeDDL(row); ddl != nil {
+			if err := ra.ddlSink.WriteDDLEvent(ctx, ddl); err != nil {
+				return err
+			}
+			ra.appliedDDLCount++
+		}
+	}
 	ra.tableSinks[tableID].AppendRowChangedEvents(row)
 	record := ra.tableResolvedTsMap[tableID]
 	record.Size += rowSize
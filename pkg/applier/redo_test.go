@@ -14,6 +14,7 @@
 package applier
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
@@ -37,6 +38,7 @@ var _ reader.RedoLogReader = &MockReader{}
 type MockReader struct {
 	checkpointTs uint64
 	resolvedTs   uint64
+	compactedTs  uint64
 	redoLogCh    chan *model.RowChangedEvent
 	ddlEventCh   chan *model.DDLEvent
 }
@@ -86,6 +88,11 @@ func (br *MockReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs ui
 	return br.checkpointTs, br.resolvedTs, nil
 }
 
+// ReadCompactionMeta implements LogReader.ReadCompactionMeta
+func (br *MockReader) ReadCompactionMeta(ctx context.Context) (retainedTs, compactedTs uint64, err error) {
+	return 0, br.compactedTs, nil
+}
+
 func TestApply(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -211,6 +218,118 @@ func TestApply(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestApplyDryRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	compactedTs := uint64(0)
+	newReader := func() (reader.RedoLogReader, error) {
+		redoLogCh := make(chan *model.RowChangedEvent, 1024)
+		ddlEventCh := make(chan *model.DDLEvent, 1024)
+		for _, dml := range []*model.RowChangedEvent{
+			{
+				CommitTs: 1100,
+				Table:    &model.TableName{Schema: "test", Table: "t1"},
+				Columns: []*model.Column{
+					{Name: "a", Value: 1, Flag: model.HandleKeyFlag},
+					{Name: "b", Value: "it's", Charset: "utf8mb4"},
+				},
+			},
+			{
+				CommitTs: 1200,
+				Table:    &model.TableName{Schema: "test", Table: "t2"},
+				Columns: []*model.Column{
+					{Name: "a", Value: 1, Flag: model.HandleKeyFlag},
+				},
+			},
+			{
+				CommitTs: 1300,
+				Table:    &model.TableName{Schema: "test", Table: "t1"},
+				PreColumns: []*model.Column{
+					{Name: "a", Value: 1, Flag: model.HandleKeyFlag},
+					{Name: "b", Value: []byte("it's"), Charset: "utf8mb4"},
+				},
+				Columns: []*model.Column{
+					{Name: "a", Value: 2, Flag: model.HandleKeyFlag},
+					{Name: "b", Value: nil},
+				},
+			},
+			{
+				CommitTs: 1600,
+				Table:    &model.TableName{Schema: "test", Table: "t1"},
+				PreColumns: []*model.Column{
+					{Name: "a", Value: 2, Flag: model.HandleKeyFlag},
+					{Name: "b", Value: nil},
+				},
+			},
+		} {
+			redoLogCh <- dml
+		}
+		for _, ddl := range []*model.DDLEvent{
+			{
+				CommitTs: 1200,
+				TableInfo: &model.TableInfo{
+					TableName: model.TableName{Schema: "test", Table: "t1"},
+				},
+				Query: "alter table t1 add column c int",
+				Type:  timodel.ActionAddColumn,
+			},
+			{
+				CommitTs: 1200,
+				TableInfo: &model.TableInfo{
+					TableName: model.TableName{Schema: "test", Table: "t2"},
+				},
+				Query: "alter table t2 add column c int",
+				Type:  timodel.ActionAddColumn,
+			},
+		} {
+			ddlEventCh <- ddl
+		}
+		close(redoLogCh)
+		close(ddlEventCh)
+		rd := NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh)
+		rd.compactedTs = compactedTs
+		return rd, nil
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return newReader()
+	}
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	var out bytes.Buffer
+	cfg := &RedoApplierConfig{
+		TargetTs:    1500,
+		FilterRules: []string{"test.t1"},
+		DryRun:      true,
+		Output:      &out,
+	}
+	require.NoError(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Equal(t, "REPLACE INTO `test`.`t1` (`a`,`b`) VALUES (1,'it\\'s');\n"+
+		"USE `test`;\nalter table t1 add column c int;\n"+
+		"DELETE FROM `test`.`t1` WHERE `a` = 1 LIMIT 1;\n"+
+		"REPLACE INTO `test`.`t1` (`a`,`b`) VALUES (2,NULL);\n", out.String())
+
+	// The target ts must be in the range of redo logs.
+	cfg.TargetTs = 2500
+	require.Regexp(t, "ErrRedoApplyTargetTsInvalid", NewRedoApplier(cfg).Apply(ctx))
+
+	// Retained logs can be applied from a ts before the checkpoint ts, and
+	// up to a ts before the compacted ts, but not before the start ts.
+	compactedTs = 1550
+	cfg.StartTs = 900
+	cfg.TargetTs = 800
+	require.Regexp(t, "ErrRedoApplyTargetTsInvalid", NewRedoApplier(cfg).Apply(ctx))
+	cfg.TargetTs = 1600
+	out.Reset()
+	require.NoError(t, NewRedoApplier(cfg).Apply(ctx))
+	require.Contains(t, out.String(), "DELETE FROM `test`.`t1` WHERE `a` = 2 LIMIT 1;\n")
+}
+
 func TestApplyMeetSinkError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
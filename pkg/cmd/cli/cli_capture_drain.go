@@ -0,0 +1,107 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"time"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// drainCaptureOptions defines flags for the `cli capture drain` command.
+type drainCaptureOptions struct {
+	apiv2Client apiv2client.APIV2Interface
+
+	captureID string
+	timeout   time.Duration
+	cancel    bool
+	status    bool
+}
+
+// newDrainCaptureOptions creates new options for the `cli capture drain` command.
+func newDrainCaptureOptions() *drainCaptureOptions {
+	return &drainCaptureOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *drainCaptureOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.captureID, "capture-id", "", "Capture ID")
+	cmd.PersistentFlags().DurationVar(&o.timeout, "timeout", 0,
+		"Force-move remaining tables after the timeout, 0 means no timeout")
+	cmd.PersistentFlags().BoolVar(&o.cancel, "cancel", false,
+		"Cancel draining the capture")
+	cmd.PersistentFlags().BoolVar(&o.status, "status", false,
+		"Show the draining progress of the capture")
+	_ = cmd.MarkPersistentFlagRequired("capture-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *drainCaptureOptions) complete(f factory.Factory) error {
+	apiv2Client, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiv2Client = apiv2Client
+	return nil
+}
+
+// run runs the `cli capture drain` command.
+func (o *drainCaptureOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	var (
+		resp *model.DrainCaptureResp
+		err  error
+	)
+	switch {
+	case o.cancel:
+		resp, err = o.apiv2Client.Captures().CancelDrain(ctx, o.captureID)
+	case o.status:
+		resp, err = o.apiv2Client.Captures().DrainStatus(ctx, o.captureID)
+	default:
+		resp, err = o.apiv2Client.Captures().Drain(ctx, o.captureID,
+			&v2.DrainCaptureConfig{Timeout: uint64(o.timeout.Seconds())})
+	}
+	if err != nil {
+		return err
+	}
+
+	return util.JSONPrint(cmd, resp)
+}
+
+// newCmdDrainCapture creates the `cli capture drain` command.
+func newCmdDrainCapture(f factory.Factory) *cobra.Command {
+	o := newDrainCaptureOptions()
+
+	command := &cobra.Command{
+		Use:   "drain",
+		Short: "Move all tables out of a capture, or show and cancel the draining",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureDrainCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cf := mock.NewMockCaptureInterface(ctrl)
+	f := &mockFactory{captures: cf}
+
+	cmd := newCmdDrainCapture(f)
+	cf.EXPECT().Drain(gomock.Any(), "c1", &v2.DrainCaptureConfig{Timeout: 60}).
+		Return(&model.DrainCaptureResp{CurrentTableCount: 1}, nil)
+	os.Args = []string{"drain", "--capture-id=c1", "--timeout=1m"}
+	require.Nil(t, cmd.Execute())
+
+	o := newDrainCaptureOptions()
+	require.Nil(t, o.complete(f))
+	o.captureID = "c1"
+	o.status = true
+	cf.EXPECT().DrainStatus(gomock.Any(), "c1").
+		Return(&model.DrainCaptureResp{}, nil)
+	require.Nil(t, o.run(cmd))
+
+	o.cancel = true
+	cf.EXPECT().CancelDrain(gomock.Any(), "c1").Return(nil, errors.New("test"))
+	require.NotNil(t, o.run(cmd))
+}
@@ -33,6 +33,8 @@ type mockAPIV2Client struct {
 	unsafes     apiv2client.UnsafeInterface
 	captures    apiv2client.CaptureInterface
 	processors  apiv2client.ProcessorInterface
+	upstreams   apiv2client.UpstreamInterface
+	namespaces  apiv2client.NamespaceInterface
 }
 
 func (f *mockAPIV2Client) Changefeeds() apiv2client.ChangefeedInterface {
@@ -55,6 +57,14 @@ func (f *mockAPIV2Client) Processors() apiv2client.ProcessorInterface {
 	return f.processors
 }
 
+func (f *mockAPIV2Client) Upstreams() apiv2client.UpstreamInterface {
+	return f.upstreams
+}
+
+func (f *mockAPIV2Client) Namespaces() apiv2client.NamespaceInterface {
+	return f.namespaces
+}
+
 type mockFactory struct {
 	factory.Factory
 	captures    *mock.MockCaptureInterface
@@ -63,6 +73,8 @@ type mockFactory struct {
 	status      *mock.MockStatusInterface
 	tso         *mock.MockTsoInterface
 	unsafes     *mock.MockUnsafeInterface
+	upstreams   *mock.MockUpstreamInterface
+	namespaces  *mock.MockNamespaceInterface
 }
 
 func newMockFactory(ctrl *gomock.Controller) *mockFactory {
@@ -72,6 +84,8 @@ func newMockFactory(ctrl *gomock.Controller) *mockFactory {
 	statuses := mock.NewMockStatusInterface(ctrl)
 	unsafes := mock.NewMockUnsafeInterface(ctrl)
 	tso := mock.NewMockTsoInterface(ctrl)
+	upstreams := mock.NewMockUpstreamInterface(ctrl)
+	namespaces := mock.NewMockNamespaceInterface(ctrl)
 	return &mockFactory{
 		captures:    cps,
 		changefeeds: cf,
@@ -79,6 +93,8 @@ func newMockFactory(ctrl *gomock.Controller) *mockFactory {
 		status:      statuses,
 		tso:         tso,
 		unsafes:     unsafes,
+		upstreams:   upstreams,
+		namespaces:  namespaces,
 	}
 }
 
@@ -89,6 +105,8 @@ func (f *mockFactory) APIV2Client() (apiv2client.APIV2Interface, error) {
 		tso:         f.tso,
 		unsafes:     f.unsafes,
 		processors:  f.processors,
+		upstreams:   f.upstreams,
+		namespaces:  f.namespaces,
 	}, nil
 }
 
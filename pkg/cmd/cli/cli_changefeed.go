@@ -32,6 +32,10 @@ func newCmdChangefeed(f factory.Factory) *cobra.Command {
 	cmds.AddCommand(newCmdListChangefeed(f))
 	cmds.AddCommand(newCmdPauseChangefeed(f))
 	cmds.AddCommand(newCmdQueryChangefeed(f))
+	cmds.AddCommand(newCmdEventsChangefeed(f))
+	cmds.AddCommand(newCmdDDLChangefeed(f))
+	cmds.AddCommand(newCmdBackfillChangefeed(f))
+	cmds.AddCommand(newCmdVerifyChangefeed(f))
 	cmds.AddCommand(newCmdRemoveChangefeed(f))
 	cmds.AddCommand(newCmdResumeChangefeed(f))
 
@@ -0,0 +1,90 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// backfillChangefeedOptions defines flags for the `cli changefeed backfill` command.
+type backfillChangefeedOptions struct {
+	apiClientV2  apiv2client.APIV2Interface
+	changefeedID string
+	namespace    string
+	tableID      int64
+}
+
+// newBackfillChangefeedOptions creates new options for the `cli changefeed backfill` command.
+func newBackfillChangefeedOptions() *backfillChangefeedOptions {
+	return &backfillChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *backfillChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeed, the default namespace is used if it is empty")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	cmd.PersistentFlags().Int64Var(&o.tableID, "table-id", 0,
+		"Physical ID of the table to backfill, use the partition ID for a partitioned table")
+	_ = cmd.MarkPersistentFlagRequired("table-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *backfillChangefeedOptions) complete(f factory.Factory) error {
+	clientV2, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClientV2 = clientV2
+	return nil
+}
+
+// run the `cli changefeed backfill` command.
+func (o *backfillChangefeedOptions) run(cmd *cobra.Command) error {
+	err := o.apiClientV2.Changefeeds().BackfillTable(context.Background(),
+		&v2.BackfillTableConfig{TableID: o.tableID}, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Table %d is going to be backfilled, "+
+		"the progress can be found by `cli changefeed query`\n", o.tableID)
+	return nil
+}
+
+// newCmdBackfillChangefeed creates the `cli changefeed backfill` command.
+func newCmdBackfillChangefeed(f factory.Factory) *cobra.Command {
+	o := newBackfillChangefeedOptions()
+
+	command := &cobra.Command{
+		Use: "backfill",
+		Short: "Export the snapshot of a table to the sink again and " +
+			"replicate it from the snapshot ts, other tables keep being replicated",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
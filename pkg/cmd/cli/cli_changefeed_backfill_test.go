@@ -0,0 +1,60 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedBackfillCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock.NewMockChangefeedInterface(ctrl)
+
+	f := &mockFactory{changefeeds: cfV2}
+
+	o := newBackfillChangefeedOptions()
+	require.Nil(t, o.complete(f))
+	cmd := newCmdBackfillChangefeed(f)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+
+	o.changefeedID = "abc"
+	o.tableID = 100
+	cfV2.EXPECT().BackfillTable(gomock.Any(),
+		&v2.BackfillTableConfig{TableID: 100}, "", "abc").Return(nil)
+	require.Nil(t, o.run(cmd))
+	require.Contains(t, b.String(), "Table 100 is going to be backfilled")
+
+	cfV2.EXPECT().BackfillTable(gomock.Any(), gomock.Any(), "", "abc").
+		Return(errors.New("test"))
+	require.NotNil(t, o.run(cmd))
+
+	// the table id is required
+	cmd.SetArgs([]string{"-c", "abc"})
+	require.ErrorContains(t, cmd.Execute(), "table-id")
+
+	// backfill a table of a changefeed in a non-default namespace
+	cfV2.EXPECT().BackfillTable(gomock.Any(),
+		&v2.BackfillTableConfig{TableID: 100}, "ns1", "abc").Return(nil)
+	cmd.SetArgs([]string{"-c", "abc", "--namespace", "ns1", "--table-id", "100"})
+	require.Nil(t, cmd.Execute())
+}
@@ -40,6 +40,7 @@ import (
 type changefeedCommonOptions struct {
 	noConfirm      bool
 	targetTs       uint64
+	targetTime     string
 	sinkURI        string
 	schemaRegistry string
 	configFile     string
@@ -62,6 +63,8 @@ func newChangefeedCommonOptions() *changefeedCommonOptions {
 func (o *changefeedCommonOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&o.noConfirm, "no-confirm", false, "Don't ask user whether to ignore ineligible table")
 	cmd.PersistentFlags().Uint64Var(&o.targetTs, "target-ts", 0, "Target ts of changefeed")
+	cmd.PersistentFlags().StringVar(&o.targetTime, "target-time", "",
+		"Target time of changefeed in RFC3339 format, ignored if target-ts is set")
 	cmd.PersistentFlags().StringVar(&o.sinkURI, "sink-uri", "", "sink uri")
 	cmd.PersistentFlags().StringVar(&o.configFile, "config", "", "Path of the configuration file")
 	cmd.PersistentFlags().StringVar(&o.sortEngine, "sort-engine", model.SortUnified, "sort engine used for data sort")
@@ -79,11 +82,6 @@ func (o *changefeedCommonOptions) addFlags(cmd *cobra.Command) {
 	_ = cmd.PersistentFlags().MarkHidden("sort-dir")
 	// we don't support specify these flags below when cdc version >= 6.2.0
 	_ = cmd.PersistentFlags().MarkHidden("sort-engine")
-	// we don't support specify there flags below when cdc version <= 6.3.0
-	_ = cmd.PersistentFlags().MarkHidden("upstream-pd")
-	_ = cmd.PersistentFlags().MarkHidden("upstream-ca")
-	_ = cmd.PersistentFlags().MarkHidden("upstream-cert")
-	_ = cmd.PersistentFlags().MarkHidden("upstream-key")
 }
 
 // strictDecodeConfig do strictDecodeFile check and only verify the rules for now.
@@ -104,6 +102,7 @@ type createChangefeedOptions struct {
 	apiClient               apiv2client.APIV2Interface
 
 	changefeedID            string
+	namespace               string
 	disableGCSafePointCheck bool
 	startTs                 uint64
 	timezone                string
@@ -123,6 +122,7 @@ func newCreateChangefeedOptions(commonChangefeedOptions *changefeedCommonOptions
 func (o *createChangefeedOptions) addFlags(cmd *cobra.Command) {
 	o.commonChangefeedOptions.addFlags(cmd)
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeed, the default namespace is used if it is empty")
 	cmd.PersistentFlags().BoolVarP(&o.disableGCSafePointCheck, "disable-gc-check", "", false, "Disable GC safe point check")
 	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "Start ts of changefeed")
 	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
@@ -240,9 +240,11 @@ func (o *createChangefeedOptions) getChangefeedConfig() *v2.ChangefeedConfig {
 	replicaConfig := v2.ToAPIReplicaConfig(o.cfg)
 	upstreamConfig := o.getUpstreamConfig()
 	return &v2.ChangefeedConfig{
+		Namespace:     o.namespace,
 		ID:            o.changefeedID,
 		StartTs:       o.startTs,
 		TargetTs:      o.commonChangefeedOptions.targetTs,
+		TargetTime:    o.commonChangefeedOptions.targetTime,
 		SinkURI:       o.commonChangefeedOptions.sinkURI,
 		ReplicaConfig: replicaConfig,
 		PDConfig:      upstreamConfig.PDConfig,
@@ -0,0 +1,152 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// ddlChangefeedOptions defines flags for the `cli changefeed ddl` commands.
+type ddlChangefeedOptions struct {
+	apiClientV2  apiv2client.APIV2Interface
+	changefeedID string
+	namespace    string
+	commitTs     uint64
+	query        string
+}
+
+// newDDLChangefeedOptions creates new options for the `cli changefeed ddl` commands.
+func newDDLChangefeedOptions() *ddlChangefeedOptions {
+	return &ddlChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *ddlChangefeedOptions) addFlags(cmd *cobra.Command, action model.DDLDecisionAction) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeed, the default namespace is used if it is empty")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	if action == "" {
+		return
+	}
+	cmd.PersistentFlags().Uint64Var(&o.commitTs, "commit-ts", 0,
+		"Commit ts of the held DDL, which can be found by `cli changefeed ddl query`")
+	_ = cmd.MarkPersistentFlagRequired("commit-ts")
+	if action == model.DDLDecisionReplace {
+		cmd.PersistentFlags().StringVar(&o.query, "query", "",
+			"The statement executed to the downstream instead of the held DDL")
+		_ = cmd.MarkPersistentFlagRequired("query")
+	}
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *ddlChangefeedOptions) complete(f factory.Factory) error {
+	clientV2, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClientV2 = clientV2
+	return nil
+}
+
+// runQuery runs the `cli changefeed ddl query` command.
+func (o *ddlChangefeedOptions) runQuery(cmd *cobra.Command) error {
+	heldDDL, err := o.apiClientV2.Changefeeds().GetHeldDDL(context.Background(), o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, heldDDL)
+}
+
+// runDecision runs the `cli changefeed ddl approve|skip|replace` commands.
+func (o *ddlChangefeedOptions) runDecision(cmd *cobra.Command, action model.DDLDecisionAction) error {
+	err := o.apiClientV2.Changefeeds().HandleHeldDDL(context.Background(), &v2.HandleDDLConfig{
+		CommitTs: o.commitTs,
+		DDLDecision: v2.DDLDecision{
+			Action: string(action),
+			Query:  o.query,
+		},
+	}, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("The held DDL with commit ts %d is handled, action: %s\n", o.commitTs, action)
+	return nil
+}
+
+// newCmdDDLChangefeed creates the `cli changefeed ddl` command.
+func newCmdDDLChangefeed(f factory.Factory) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "ddl",
+		Short: "Manage the DDL held for approval of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+	}
+
+	cmds.AddCommand(newCmdQueryHeldDDL(f))
+	cmds.AddCommand(newCmdHandleHeldDDL(f, model.DDLDecisionApprove,
+		"Approve the held DDL, it is executed to the downstream as it is"))
+	cmds.AddCommand(newCmdHandleHeldDDL(f, model.DDLDecisionSkip,
+		"Skip the held DDL, it is not executed to the downstream"))
+	cmds.AddCommand(newCmdHandleHeldDDL(f, model.DDLDecisionReplace,
+		"Replace the held DDL with another statement executed to the downstream"))
+
+	return cmds
+}
+
+// newCmdQueryHeldDDL creates the `cli changefeed ddl query` command.
+func newCmdQueryHeldDDL(f factory.Factory) *cobra.Command {
+	o := newDDLChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "query",
+		Short: "Query the DDL held for approval",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.runQuery(cmd))
+		},
+	}
+
+	o.addFlags(command, "")
+
+	return command
+}
+
+// newCmdHandleHeldDDL creates the `cli changefeed ddl approve|skip|replace` commands.
+func newCmdHandleHeldDDL(
+	f factory.Factory, action model.DDLDecisionAction, short string,
+) *cobra.Command {
+	o := newDDLChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   string(action),
+		Short: short,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.runDecision(cmd, action))
+		},
+	}
+
+	o.addFlags(command, action)
+
+	return command
+}
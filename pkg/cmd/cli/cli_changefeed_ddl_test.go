@@ -0,0 +1,90 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedDDLCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock.NewMockChangefeedInterface(ctrl)
+
+	f := &mockFactory{changefeeds: cfV2}
+
+	o := newDDLChangefeedOptions()
+	require.Nil(t, o.complete(f))
+	cmd := newCmdDDLChangefeed(f)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+
+	// query the held ddl
+	heldDDL := &v2.HeldDDL{
+		CommitTs: 100,
+		Type:     "drop table",
+		Schema:   "test",
+		Table:    "t",
+		Query:    "DROP TABLE `test`.`t`",
+	}
+	cfV2.EXPECT().GetHeldDDL(gomock.Any(), "", "abc").Return(heldDDL, nil)
+	o.changefeedID = "abc"
+	require.Nil(t, o.runQuery(cmd))
+	result := &v2.HeldDDL{}
+	require.Nil(t, json.Unmarshal(b.Bytes(), result))
+	require.Equal(t, heldDDL, result)
+
+	cfV2.EXPECT().GetHeldDDL(gomock.Any(), "", "abc").Return(nil, errors.New("test"))
+	require.NotNil(t, o.runQuery(cmd))
+
+	// replace the held ddl
+	o.commitTs = 100
+	o.query = "RENAME TABLE `test`.`t` TO `test`.`t_dropped`"
+	cfV2.EXPECT().HandleHeldDDL(gomock.Any(), &v2.HandleDDLConfig{
+		CommitTs: 100,
+		DDLDecision: v2.DDLDecision{
+			Action: string(model.DDLDecisionReplace),
+			Query:  "RENAME TABLE `test`.`t` TO `test`.`t_dropped`",
+		},
+	}, "", "abc").Return(nil)
+	b.Reset()
+	require.Nil(t, o.runDecision(cmd, model.DDLDecisionReplace))
+	require.Contains(t, b.String(), "action: replace")
+
+	o.query = ""
+	cfV2.EXPECT().HandleHeldDDL(gomock.Any(), gomock.Any(), "", "abc").
+		Return(errors.New("test"))
+	require.NotNil(t, o.runDecision(cmd, model.DDLDecisionSkip))
+
+	// the commit ts is required to make a decision
+	cmd.SetArgs([]string{"approve", "-c", "abc"})
+	require.ErrorContains(t, cmd.Execute(), "commit-ts")
+
+	// query and skip the held ddl of a changefeed in a non-default namespace
+	cfV2.EXPECT().GetHeldDDL(gomock.Any(), "ns1", "abc").Return(heldDDL, nil)
+	cmd.SetArgs([]string{"query", "-c", "abc", "--namespace", "ns1"})
+	require.Nil(t, cmd.Execute())
+	cfV2.EXPECT().HandleHeldDDL(gomock.Any(), gomock.Any(), "ns1", "abc").Return(nil)
+	cmd.SetArgs([]string{"skip", "-c", "abc", "--namespace", "ns1", "--commit-ts", "100"})
+	require.Nil(t, cmd.Execute())
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// eventsChangefeedOptions defines flags for the `cli changefeed events` command.
+type eventsChangefeedOptions struct {
+	apiClientV2  apiv2client.APIV2Interface
+	changefeedID string
+	namespace    string
+	eventType    string
+}
+
+// newEventsChangefeedOptions creates new options for the `cli changefeed events` command.
+func newEventsChangefeedOptions() *eventsChangefeedOptions {
+	return &eventsChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *eventsChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeed, the default namespace is used if it is empty")
+	cmd.PersistentFlags().StringVar(&o.eventType, "type", "",
+		"Only output events of the given type, "+
+			"can be state-changed, admin-job, error, warning or ddl")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *eventsChangefeedOptions) complete(f factory.Factory) error {
+	clientV2, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClientV2 = clientV2
+	return nil
+}
+
+// run the `cli changefeed events` command.
+func (o *eventsChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.Background()
+	events, err := o.apiClientV2.Changefeeds().ListEvents(ctx, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	if o.eventType == "" {
+		return util.JSONPrint(cmd, events)
+	}
+	filtered := make([]v2.ChangefeedEvent, 0, len(events))
+	for _, e := range events {
+		if e.Type == o.eventType {
+			filtered = append(filtered, e)
+		}
+	}
+	return util.JSONPrint(cmd, filtered)
+}
+
+// newCmdEventsChangefeed creates the `cli changefeed events` command.
+func newCmdEventsChangefeed(f factory.Factory) *cobra.Command {
+	o := newEventsChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "events",
+		Short: "List the history events of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
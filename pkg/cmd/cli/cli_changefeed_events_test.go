@@ -0,0 +1,77 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedEventsCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock.NewMockChangefeedInterface(ctrl)
+
+	f := &mockFactory{changefeeds: cfV2}
+
+	o := newEventsChangefeedOptions()
+	require.Nil(t, o.complete(f))
+	cmd := newCmdEventsChangefeed(f)
+
+	events := []v2.ChangefeedEvent{
+		{
+			Type:    string(model.ChangefeedEventAdminJob),
+			Message: model.AdminStop.String(),
+		},
+		{
+			Type:     string(model.ChangefeedEventDDL),
+			Message:  "create table t (id int primary key)",
+			CommitTs: 100,
+		},
+	}
+	cfV2.EXPECT().ListEvents(gomock.Any(), "", "abc").Return(events, nil).Times(2)
+
+	o.changefeedID = "abc"
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, o.run(cmd))
+	var result []v2.ChangefeedEvent
+	require.Nil(t, json.Unmarshal(b.Bytes(), &result))
+	require.Equal(t, events, result)
+
+	// filter by the event type
+	o.eventType = string(model.ChangefeedEventDDL)
+	b.Reset()
+	require.Nil(t, o.run(cmd))
+	result = nil
+	require.Nil(t, json.Unmarshal(b.Bytes(), &result))
+	require.Len(t, result, 1)
+	require.Equal(t, uint64(100), result[0].CommitTs)
+
+	cfV2.EXPECT().ListEvents(gomock.Any(), "", "abc").Return(nil, errors.New("test"))
+	require.NotNil(t, o.run(cmd))
+
+	// list the events of a changefeed in a non-default namespace
+	cfV2.EXPECT().ListEvents(gomock.Any(), "ns1", "abc").Return(events, nil)
+	cmd.SetArgs([]string{"-c", "abc", "--namespace", "ns1"})
+	require.Nil(t, cmd.Execute())
+}
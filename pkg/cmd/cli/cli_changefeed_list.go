@@ -38,7 +38,8 @@ type changefeedCommonInfo struct {
 type listChangefeedOptions struct {
 	apiClient v2.APIV2Interface
 
-	listAll bool
+	listAll   bool
+	namespace string
 }
 
 // newListChangefeedOptions creates new options for the `cli changefeed list` command.
@@ -50,6 +51,7 @@ func newListChangefeedOptions() *listChangefeedOptions {
 // flags related to template printing to it.
 func (o *listChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.listAll, "all", "a", false, "List all replication tasks(including removed and finished)")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeeds, changefeeds of all namespaces are listed if it is empty")
 }
 
 // complete adapts from the command line args to the data and client required.
@@ -66,7 +68,7 @@ func (o *listChangefeedOptions) complete(f factory.Factory) error {
 func (o *listChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := context.GetDefaultContext()
 
-	raw, err := o.apiClient.Changefeeds().List(ctx, "all")
+	raw, err := o.apiClient.Changefeeds().List(ctx, o.namespace, "all")
 	if err != nil {
 		return err
 	}
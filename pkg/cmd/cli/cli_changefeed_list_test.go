@@ -36,7 +36,7 @@ func TestChangefeedListCli(t *testing.T) {
 	b := bytes.NewBufferString("")
 	cmd.SetOut(b)
 
-	cf.EXPECT().List(gomock.Any(), gomock.Any()).Return([]v2.ChangefeedCommonInfo{
+	cf.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return([]v2.ChangefeedCommonInfo{
 		{
 			UpstreamID:     1,
 			Namespace:      "default",
@@ -108,7 +108,23 @@ func TestChangefeedListCli(t *testing.T) {
 	require.Contains(t, string(out), "finished-5")
 	require.Contains(t, string(out), "stopped-6")
 
-	cf.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("changefeed list test error"))
+	// only list changefeeds in the given namespace
+	cf.EXPECT().List(gomock.Any(), "ns1", "all").Return([]v2.ChangefeedCommonInfo{
+		{
+			UpstreamID: 1,
+			Namespace:  "ns1",
+			ID:         "normal-7",
+			FeedState:  model.StateNormal,
+		},
+	}, nil)
+	os.Args = []string{"list", "--namespace=ns1"}
+	require.Nil(t, cmd.Execute())
+	out, err = io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), "normal-7")
+	require.NotContains(t, string(out), "normal-2")
+
+	cf.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("changefeed list test error"))
 	o := newListChangefeedOptions()
 	require.NoError(t, o.complete(f))
 	require.Contains(t, o.run(cmd).Error(), "changefeed list test error")
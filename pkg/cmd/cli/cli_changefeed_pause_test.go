@@ -29,11 +29,16 @@ func TestChangefeedPauseCli(t *testing.T) {
 	cf := mock.NewMockChangefeedInterface(ctrl)
 	f := &mockFactory{changefeeds: cf}
 	cmd := newCmdPauseChangefeed(f)
-	cf.EXPECT().Pause(gomock.Any(), "abc").Return(nil)
+	cf.EXPECT().Pause(gomock.Any(), "", "abc").Return(nil)
 	os.Args = []string{"pause", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
 
-	cf.EXPECT().Pause(gomock.Any(), "abc").Return(errors.New("test"))
+	// pause a changefeed in a non-default namespace
+	cf.EXPECT().Pause(gomock.Any(), "ns1", "abc").Return(nil)
+	os.Args = []string{"pause", "--namespace=ns1", "--changefeed-id=abc"}
+	require.Nil(t, cmd.Execute())
+
+	cf.EXPECT().Pause(gomock.Any(), "", "abc").Return(errors.New("test"))
 	o := newPauseChangefeedOptions()
 	o.changefeedID = "abc"
 	require.Nil(t, o.complete(f))
@@ -42,15 +42,19 @@ type cfMeta struct {
 	Engine         model.SortEngine          `json:"sort_engine,omitempty"`
 	FeedState      model.FeedState           `json:"state"`
 	RunningError   *v2.RunningError          `json:"error"`
+	Warning        *v2.ChangefeedWarning     `json:"warning,omitempty"`
 	ErrorHis       []int64                   `json:"error_history"`
 	CreatorVersion string                    `json:"creator_version"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+	Snapshot       *v2.SnapshotProgress      `json:"snapshot_progress,omitempty"`
+	Backfill       *v2.TableBackfill         `json:"backfill,omitempty"`
 }
 
 // queryChangefeedOptions defines flags for the `cli changefeed query` command.
 type queryChangefeedOptions struct {
 	apiClientV2  apiv2client.APIV2Interface
 	changefeedID string
+	namespace    string
 	simplified   bool
 }
 
@@ -64,6 +68,7 @@ func newQueryChangefeedOptions() *queryChangefeedOptions {
 func (o *queryChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.simplified, "simple", "s", false, "Output simplified replication status")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeed, the default namespace is used if it is empty")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
 
@@ -81,7 +86,11 @@ func (o *queryChangefeedOptions) complete(f factory.Factory) error {
 func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := context.Background()
 	if o.simplified {
-		infos, err := o.apiClientV2.Changefeeds().List(ctx, "all")
+		namespace := o.namespace
+		if namespace == "" {
+			namespace = model.DefaultNamespace
+		}
+		infos, err := o.apiClientV2.Changefeeds().List(ctx, namespace, "all")
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -93,7 +102,7 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(o.changefeedID)
 	}
 
-	detail, err := o.apiClientV2.Changefeeds().Get(ctx, o.changefeedID)
+	detail, err := o.apiClientV2.Changefeeds().Get(ctx, o.namespace, o.changefeedID)
 	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
 		return err
 	}
@@ -111,8 +120,11 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		CheckpointTime: detail.CheckpointTime,
 		FeedState:      detail.State,
 		RunningError:   detail.Error,
+		Warning:        detail.Warning,
 		CreatorVersion: detail.CreatorVersion,
 		TaskStatus:     detail.TaskStatus,
+		Snapshot:       detail.SnapshotProgress,
+		Backfill:       detail.Backfill,
 	}
 	return util.JSONPrint(cmd, meta)
 }
@@ -38,7 +38,7 @@ func TestChangefeedQueryCli(t *testing.T) {
 	o.complete(f)
 	cmd := newCmdQueryChangefeed(f)
 
-	cfV2.EXPECT().List(gomock.Any(), "all").Return([]v2.ChangefeedCommonInfo{
+	cfV2.EXPECT().List(gomock.Any(), "default", "all").Return([]v2.ChangefeedCommonInfo{
 		{
 			UpstreamID:     1,
 			Namespace:      "default",
@@ -51,7 +51,7 @@ func TestChangefeedQueryCli(t *testing.T) {
 	o.simplified = true
 	o.changefeedID = "abc"
 	require.Nil(t, o.run(cmd))
-	cfV2.EXPECT().List(gomock.Any(), "all").Return([]v2.ChangefeedCommonInfo{
+	cfV2.EXPECT().List(gomock.Any(), "default", "all").Return([]v2.ChangefeedCommonInfo{
 		{
 			UpstreamID:     1,
 			Namespace:      "default",
@@ -65,13 +65,13 @@ func TestChangefeedQueryCli(t *testing.T) {
 	o.changefeedID = "abcd"
 	require.NotNil(t, o.run(cmd))
 
-	cfV2.EXPECT().List(gomock.Any(), "all").Return(nil, errors.New("test"))
+	cfV2.EXPECT().List(gomock.Any(), "default", "all").Return(nil, errors.New("test"))
 	o.simplified = true
 	o.changefeedID = "abcd"
 	require.NotNil(t, o.run(cmd))
 
 	// query success
-	cfV2.EXPECT().Get(gomock.Any(), "bcd").Return(&v2.ChangeFeedInfo{}, nil)
+	cfV2.EXPECT().Get(gomock.Any(), "", "bcd").Return(&v2.ChangeFeedInfo{}, nil)
 
 	o.simplified = false
 	o.changefeedID = "bcd"
@@ -83,8 +83,40 @@ func TestChangefeedQueryCli(t *testing.T) {
 	// make sure config is printed
 	require.Contains(t, string(out), "config")
 
+	// the warning of a retrying changefeed is printed
+	cfV2.EXPECT().Get(gomock.Any(), "", "bcd").Return(&v2.ChangeFeedInfo{
+		State: model.StateWarning,
+		Warning: &v2.ChangefeedWarning{
+			RunningError: v2.RunningError{Code: "CDC:ErrMySQLTxnError"},
+			RetryCount:   2,
+		},
+	}, nil)
+	b.Reset()
+	require.Nil(t, o.run(cmd))
+	require.Contains(t, b.String(), `"state": "warning"`)
+	require.Contains(t, b.String(), `"retry_count": 2`)
+
+	// query a changefeed in a non-default namespace
+	cfV2.EXPECT().Get(gomock.Any(), "ns1", "bcd").Return(&v2.ChangeFeedInfo{
+		Namespace: "ns1",
+		ID:        "bcd",
+	}, nil)
+	o.namespace = "ns1"
+	b.Reset()
+	require.Nil(t, o.run(cmd))
+	require.Contains(t, b.String(), `"namespace": "ns1"`)
+	cfV2.EXPECT().List(gomock.Any(), "ns1", "all").Return([]v2.ChangefeedCommonInfo{
+		{Namespace: "ns1", ID: "bcd"},
+	}, nil)
+	o.simplified = true
+	b.Reset()
+	require.Nil(t, o.run(cmd))
+	require.Contains(t, b.String(), `"namespace": "ns1"`)
+	o.simplified = false
+	o.namespace = ""
+
 	// query failed
-	cfV2.EXPECT().Get(gomock.Any(), "bcd").Return(nil, errors.New("test"))
+	cfV2.EXPECT().Get(gomock.Any(), "", "bcd").Return(nil, errors.New("test"))
 	os.Args = []string{"query", "--simple=false", "--changefeed-id=bcd"}
 	require.NotNil(t, o.run(cmd))
 }
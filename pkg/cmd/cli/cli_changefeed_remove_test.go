@@ -33,20 +33,28 @@ func TestChangefeedRemoveCli(t *testing.T) {
 
 	cmd := newCmdRemoveChangefeed(f)
 
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{}, nil)
-	cf.EXPECT().Delete(gomock.Any(), "abc").Return(nil)
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(nil,
+	cf.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{}, nil)
+	cf.EXPECT().Delete(gomock.Any(), "", "abc").Return(nil)
+	cf.EXPECT().Get(gomock.Any(), "", "abc").Return(nil,
 		cerror.ErrChangeFeedNotExists.GenWithStackByArgs("abc"))
 	os.Args = []string{"remove", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(nil,
+	cf.EXPECT().Get(gomock.Any(), "", "abc").Return(nil,
 		cerror.ErrChangeFeedNotExists.GenWithStackByArgs("abc"))
 	os.Args = []string{"remove", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
 
+	// remove a changefeed in a non-default namespace
+	cf.EXPECT().Get(gomock.Any(), "ns1", "abc").Return(&v2.ChangeFeedInfo{}, nil)
+	cf.EXPECT().Delete(gomock.Any(), "ns1", "abc").Return(nil)
+	cf.EXPECT().Get(gomock.Any(), "ns1", "abc").Return(nil,
+		cerror.ErrChangeFeedNotExists.GenWithStackByArgs("abc"))
+	os.Args = []string{"remove", "--namespace=ns1", "--changefeed-id=abc"}
+	require.Nil(t, cmd.Execute())
+
 	o := newRemoveChangefeedOptions()
 	o.complete(f)
 	o.changefeedID = "abc"
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(nil, errors.New("abc"))
+	cf.EXPECT().Get(gomock.Any(), "", "abc").Return(nil, errors.New("abc"))
 	require.NotNil(t, o.run(cmd))
 }
@@ -37,7 +37,7 @@ func TestChangefeedResumeCli(t *testing.T) {
 	cmd := newCmdResumeChangefeed(f)
 
 	// 1. test changefeed resume with non-nil changefeed get result, non-nil tso get result
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -49,19 +49,32 @@ func TestChangefeedResumeCli(t *testing.T) {
 	}, nil).AnyTimes()
 	f.changefeeds.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
 		OverwriteCheckpointTs: 0,
-	}, "abc").Return(nil)
+	}, "", "abc").Return(nil)
 	os.Args = []string{"resume", "--no-confirm=true", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
 
+	// resume a changefeed in a non-default namespace
+	f.changefeeds.EXPECT().Get(gomock.Any(), "ns1", "abc").Return(&v2.ChangeFeedInfo{
+		UpstreamID: 1,
+		Namespace:  "ns1",
+		ID:         "abc",
+	}, nil)
+	f.changefeeds.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
+		OverwriteCheckpointTs: 0,
+	}, "ns1", "abc").Return(nil)
+	cmd = newCmdResumeChangefeed(f)
+	os.Args = []string{"resume", "--no-confirm=true", "--namespace=ns1", "--changefeed-id=abc"}
+	require.Nil(t, cmd.Execute())
+
 	// 2. test changefeed resume with nil changfeed get result
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{}, nil)
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{}, nil)
 	os.Args = []string{"resume", "--no-confirm=false", "--changefeed-id=abc"}
 	o.noConfirm = false
 	o.changefeedID = "abc"
 	require.NotNil(t, o.run(cmd))
 
 	// 3. test changefeed resume with nil tso get result
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -73,7 +86,7 @@ func TestChangefeedResumeCli(t *testing.T) {
 
 	// 4. test changefeed resume with non-nil changefeed result, non-nil tso get result,
 	// and confirmation checking
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -108,7 +121,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	cmd := newCmdResumeChangefeed(f)
 
 	// 1. test changefeed resume with valid overwritten checkpointTs
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -121,7 +134,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(tso, nil).AnyTimes()
 	f.changefeeds.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
 		OverwriteCheckpointTs: oracle.ComposeTS(tso.Timestamp, tso.LogicTime),
-	}, "abc").Return(nil)
+	}, "", "abc").Return(nil)
 	os.Args = []string{
 		"resume", "--no-confirm=true", "--changefeed-id=abc",
 		"--overwrite-checkpoint-ts=now",
@@ -129,7 +142,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	require.Nil(t, cmd.Execute())
 
 	// 2. test changefeed resume with invalid overwritten checkpointTs
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -143,7 +156,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	require.NotNil(t, o.run(cmd))
 
 	// 3. test changefeed resume with checkpointTs larger than current tso
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -155,7 +168,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	require.NotNil(t, o.run(cmd))
 
 	// 4. test changefeed resume with checkpointTs smaller than gcSafePoint
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&v2.ChangeFeedInfo{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "", "abc").Return(&v2.ChangeFeedInfo{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -168,7 +181,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(tso, nil).AnyTimes()
 	f.changefeeds.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
 		OverwriteCheckpointTs: 262144,
-	}, "abc").
+	}, "", "abc").
 		Return(cerror.ErrStartTsBeforeGC)
 	o.overwriteCheckpointTs = "262144"
 	require.NotNil(t, o.run(cmd))
@@ -0,0 +1,59 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestChangefeedStatisticsCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	o := newStatisticsChangefeedOptions()
+	require.Nil(t, o.complete(f))
+	cmd := newCmdStatisticsChangefeed(f)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+
+	// statistics of a changefeed in a non-default namespace
+	o.namespace = "ns1"
+	o.changefeedID = "abc"
+	f.changefeeds.EXPECT().Get(gomock.Any(), "ns1", "abc").Return(&v2.ChangeFeedInfo{
+		UpstreamID:   1,
+		Namespace:    "ns1",
+		ID:           "abc",
+		CheckpointTs: oracle.ComposeTS(1000, 0),
+		ResolvedTs:   oracle.ComposeTS(3000, 0),
+	}, nil)
+	f.tso.EXPECT().Query(gomock.Any(), &v2.UpstreamConfig{ID: 1}).
+		Return(&v2.Tso{Timestamp: 6000}, nil)
+	var lastCount uint64
+	var lastTime time.Time
+	require.Nil(t, o.runCliWithAPIClient(context.Background(), cmd, &lastCount, &lastTime))
+	require.Contains(t, b.String(), `"sink_gap": "2000ms"`)
+	require.Contains(t, b.String(), `"replication_gap": "5000ms"`)
+
+	f.changefeeds.EXPECT().Get(gomock.Any(), "ns1", "abc").Return(nil, errors.New("test"))
+	require.NotNil(t, o.runCliWithAPIClient(context.Background(), cmd, &lastCount, &lastTime))
+}
@@ -16,7 +16,9 @@ package cli
 import (
 	"encoding/json"
 	"strings"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
@@ -26,6 +28,7 @@ import (
 	"github.com/r3labs/diff"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -35,6 +38,7 @@ type updateChangefeedOptions struct {
 
 	commonChangefeedOptions *changefeedCommonOptions
 	changefeedID            string
+	namespace               string
 }
 
 // newUpdateChangefeedOptions creates new options for the `cli changefeed update` command.
@@ -49,6 +53,7 @@ func newUpdateChangefeedOptions(commonChangefeedOptions *changefeedCommonOptions
 func (o *updateChangefeedOptions) addFlags(cmd *cobra.Command) {
 	o.commonChangefeedOptions.addFlags(cmd)
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.namespace, "namespace", "", "Namespace of the changefeed, the default namespace is used if it is empty")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
 
@@ -90,7 +95,7 @@ func (o *updateChangefeedOptions) complete(f factory.Factory) error {
 func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
-	old, err := o.apiV2Client.Changefeeds().Get(ctx, o.changefeedID)
+	old, err := o.apiV2Client.Changefeeds().Get(ctx, o.namespace, o.changefeedID)
 	if err != nil {
 		return err
 	}
@@ -126,7 +131,7 @@ func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 	}
 
 	changefeedConfig := o.getChangefeedConfig(cmd, newInfo)
-	info, err := o.apiV2Client.Changefeeds().Update(ctx, changefeedConfig, o.changefeedID)
+	info, err := o.apiV2Client.Changefeeds().Update(ctx, changefeedConfig, o.namespace, o.changefeedID)
 	if err != nil {
 		return err
 	}
@@ -153,6 +158,19 @@ func (o *updateChangefeedOptions) applyChanges(oldInfo *v2.ChangeFeedInfo,
 		switch flag.Name {
 		case "target-ts":
 			newInfo.TargetTs = o.commonChangefeedOptions.targetTs
+		case "target-time":
+			// target-time is ignored if target-ts is set.
+			if o.commonChangefeedOptions.targetTs != 0 {
+				break
+			}
+			var targetTime time.Time
+			targetTime, err = time.Parse(time.RFC3339, o.commonChangefeedOptions.targetTime)
+			if err != nil {
+				err = errors.Errorf("invalid target-time %s, it must be in RFC3339 format",
+					o.commonChangefeedOptions.targetTime)
+				break
+			}
+			newInfo.TargetTs = oracle.GoTimeToTS(targetTime)
 		case "sink-uri":
 			newInfo.SinkURI = o.commonChangefeedOptions.sinkURI
 		case "config":
@@ -0,0 +1,90 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/pingcap/tiflow/pkg/verification"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedVerifyCli(t *testing.T) {
+	verifyPollInterval = time.Millisecond
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock.NewMockChangefeedInterface(ctrl)
+
+	f := &mockFactory{changefeeds: cfV2}
+
+	o := newVerifyChangefeedOptions()
+	require.Nil(t, o.complete(f))
+	cmd := newCmdVerifyChangefeed(f)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+
+	// start a verification and wait for the report
+	o.changefeedID = "abc"
+	o.upstreamURI = "mysql://root@127.0.0.1:4000/"
+	o.qps = 100
+	cfV2.EXPECT().StartVerification(gomock.Any(), &v2.VerifyConfig{
+		UpstreamURI: "mysql://root@127.0.0.1:4000/",
+		QPS:         100,
+	}, "", "abc").Return(&verification.Status{State: verification.StateRunning}, nil)
+	cfV2.EXPECT().GetVerification(gomock.Any(), "", "abc").
+		Return(&verification.Status{State: verification.StateRunning}, nil)
+	report := &verification.Report{PrimaryTs: 10, SecondaryTs: 20, Consistent: true}
+	cfV2.EXPECT().GetVerification(gomock.Any(), "", "abc").
+		Return(&verification.Status{State: verification.StateFinished, Report: report}, nil)
+	require.Nil(t, o.runStart(cmd))
+	result := &verification.Status{}
+	require.Nil(t, json.Unmarshal(b.Bytes(), result))
+	require.Equal(t, report, result.Report)
+
+	// a failed verification returns an error
+	cfV2.EXPECT().StartVerification(gomock.Any(), gomock.Any(), "", "abc").
+		Return(&verification.Status{State: verification.StateFailed, Error: "test"}, nil)
+	b.Reset()
+	require.ErrorContains(t, o.runStart(cmd), "failed")
+
+	// query the verification
+	cfV2.EXPECT().GetVerification(gomock.Any(), "", "abc").Return(nil, errors.New("test"))
+	require.NotNil(t, o.runQuery(cmd))
+
+	// cancel the verification
+	cfV2.EXPECT().CancelVerification(gomock.Any(), "", "abc").Return(nil)
+	b.Reset()
+	require.Nil(t, o.runCancel(cmd))
+	require.Contains(t, b.String(), "canceled")
+
+	// the upstream uri is required to start a verification
+	cmd.SetArgs([]string{"-c", "abc"})
+	require.ErrorContains(t, cmd.Execute(), "upstream-uri")
+
+	// query and cancel the verification of a changefeed in a non-default namespace
+	cfV2.EXPECT().GetVerification(gomock.Any(), "ns1", "abc").
+		Return(&verification.Status{State: verification.StateRunning}, nil)
+	cmd.SetArgs([]string{"query", "-c", "abc", "--namespace", "ns1"})
+	require.Nil(t, cmd.Execute())
+	cfV2.EXPECT().CancelVerification(gomock.Any(), "ns1", "abc").Return(nil)
+	cmd.SetArgs([]string{"cancel", "-c", "abc", "--namespace", "ns1"})
+	require.Nil(t, cmd.Execute())
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// namespace holds the quotas of a namespace.
+type namespace struct {
+	Name                   string            `json:"name"`
+	MaxDeclaredMemoryQuota uint64            `json:"max-declared-memory-quota"`
+	MaxChangefeeds         int               `json:"max-changefeeds"`
+	AllowedSinkSchemes     []string          `json:"allowed-sink-schemes"`
+	CaptureLabels          map[string]string `json:"capture-labels"`
+	Changefeeds            []string          `json:"changefeeds"`
+}
+
+func toCLINamespace(info *v2.NamespaceInfo) *namespace {
+	return &namespace{
+		Name:                   info.Name,
+		MaxDeclaredMemoryQuota: info.MaxDeclaredMemoryQuota,
+		MaxChangefeeds:         info.MaxChangefeeds,
+		AllowedSinkSchemes:     info.AllowedSinkSchemes,
+		CaptureLabels:          info.CaptureLabels,
+		Changefeeds:            info.Changefeeds,
+	}
+}
+
+// listNamespaceOptions defines flags for the `cli namespace list` command.
+type listNamespaceOptions struct {
+	apiv2Client apiv2client.APIV2Interface
+}
+
+// newListNamespaceOptions creates new listNamespaceOptions for the `cli namespace list` command.
+func newListNamespaceOptions() *listNamespaceOptions {
+	return &listNamespaceOptions{}
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *listNamespaceOptions) complete(f factory.Factory) error {
+	apiv2Client, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiv2Client = apiv2Client
+	return nil
+}
+
+// run runs the `cli namespace list` command.
+func (o *listNamespaceOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	raw, err := o.apiv2Client.Namespaces().List(ctx)
+	if err != nil {
+		return err
+	}
+	namespaces := make([]*namespace, 0, len(raw))
+	for i := range raw {
+		namespaces = append(namespaces, toCLINamespace(&raw[i]))
+	}
+
+	return util.JSONPrint(cmd, namespaces)
+}
+
+// newCmdListNamespace creates the `cli namespace list` command.
+func newCmdListNamespace(f factory.Factory) *cobra.Command {
+	o := newListNamespaceOptions()
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List all namespaces which have quotas",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	return command
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceListCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ns := mock.NewMockNamespaceInterface(ctrl)
+	f := &mockFactory{namespaces: ns}
+	cmd := newCmdListNamespace(f)
+	ns.EXPECT().List(gomock.Any()).Return([]v2.NamespaceInfo{
+		{
+			Name:           "team-a",
+			MaxChangefeeds: 2,
+			Changefeeds:    []string{"abc"},
+		},
+		{
+			Name:                   "team-b",
+			MaxDeclaredMemoryQuota: 1024,
+			AllowedSinkSchemes:     []string{"kafka"},
+		},
+	}, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{"list"}
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), `"max-changefeeds": 2`)
+	require.Contains(t, string(out), `"max-declared-memory-quota": 1024`)
+
+	ns.EXPECT().List(gomock.Any()).Return(nil, errors.New("test"))
+	o := newListNamespaceOptions()
+	o.complete(f)
+	require.NotNil(t, o.run(cmd))
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// removeNamespaceOptions defines flags for the `cli namespace remove` command.
+type removeNamespaceOptions struct {
+	apiClient apiv2client.APIV2Interface
+	name      string
+}
+
+// newRemoveNamespaceOptions creates new options for the `cli namespace remove` command.
+func newRemoveNamespaceOptions() *removeNamespaceOptions {
+	return &removeNamespaceOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *removeNamespaceOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.name, "namespace", "n", "", "Namespace name")
+	_ = cmd.MarkPersistentFlagRequired("namespace")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *removeNamespaceOptions) complete(f factory.Factory) error {
+	client, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = client
+	return nil
+}
+
+// run the `cli namespace remove` command.
+func (o *removeNamespaceOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	err := o.apiClient.Namespaces().Delete(ctx, o.name)
+	if err != nil {
+		cmd.Printf("Namespace remove failed.\nName: %s\nError: %s\n", o.name,
+			err.Error())
+		return err
+	}
+	cmd.Printf("Namespace remove successfully.\nName: %s\n", o.name)
+	return nil
+}
+
+// newCmdRemoveNamespace creates the `cli namespace remove` command.
+func newCmdRemoveNamespace(f factory.Factory) *cobra.Command {
+	o := newRemoveNamespaceOptions()
+
+	command := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove the quotas of a namespace, changefeeds in it are not limited anymore",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
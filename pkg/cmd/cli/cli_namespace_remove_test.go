@@ -0,0 +1,43 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceRemoveCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ns := mock.NewMockNamespaceInterface(ctrl)
+	f := &mockFactory{namespaces: ns}
+
+	cmd := newCmdRemoveNamespace(f)
+	ns.EXPECT().Delete(gomock.Any(), "team-a").Return(nil)
+	os.Args = []string{"remove", "--namespace=team-a"}
+	require.Nil(t, cmd.Execute())
+
+	o := newRemoveNamespaceOptions()
+	o.complete(f)
+	o.name = "team-b"
+	ns.EXPECT().Delete(gomock.Any(), "team-b").
+		Return(cerror.ErrNamespaceNotExists.GenWithStackByArgs("team-b"))
+	require.NotNil(t, o.run(cmd))
+}
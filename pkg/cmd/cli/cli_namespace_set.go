@@ -0,0 +1,101 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// setNamespaceOptions defines flags for the `cli namespace set` command.
+type setNamespaceOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	name                   string
+	maxDeclaredMemoryQuota uint64
+	maxChangefeeds         int
+	allowedSinkSchemes     []string
+	captureLabels          map[string]string
+}
+
+// newSetNamespaceOptions creates new options for the `cli namespace set` command.
+func newSetNamespaceOptions() *setNamespaceOptions {
+	return &setNamespaceOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *setNamespaceOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.name, "namespace", "n", "", "Namespace name")
+	cmd.PersistentFlags().Uint64Var(&o.maxDeclaredMemoryQuota, "max-declared-memory-quota", 0,
+		"Max sum in bytes of memory quotas declared by running changefeeds in the namespace, "+
+			"it is checked on admission only, 0 means no limit")
+	cmd.PersistentFlags().IntVar(&o.maxChangefeeds, "max-changefeeds", 0,
+		"Max number of changefeeds in the namespace, 0 means no limit")
+	cmd.PersistentFlags().StringSliceVar(&o.allowedSinkSchemes, "allowed-sink-schemes", nil,
+		"Sink schemes that changefeeds in the namespace can use, e.g. kafka,mysql")
+	cmd.PersistentFlags().StringToStringVar(&o.captureLabels, "capture-labels", nil,
+		"Labels of captures that changefeeds in the namespace are restricted to, e.g. zone=z1")
+	_ = cmd.MarkPersistentFlagRequired("namespace")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *setNamespaceOptions) complete(f factory.Factory) error {
+	client, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = client
+	return nil
+}
+
+// run the `cli namespace set` command.
+func (o *setNamespaceOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	info, err := o.apiClient.Namespaces().Put(ctx, &v2.NamespaceInfo{
+		Name:                   o.name,
+		MaxDeclaredMemoryQuota: o.maxDeclaredMemoryQuota,
+		MaxChangefeeds:         o.maxChangefeeds,
+		AllowedSinkSchemes:     o.allowedSinkSchemes,
+		CaptureLabels:          o.captureLabels,
+	})
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, toCLINamespace(info))
+}
+
+// newCmdSetNamespace creates the `cli namespace set` command.
+func newCmdSetNamespace(f factory.Factory) *cobra.Command {
+	o := newSetNamespaceOptions()
+
+	command := &cobra.Command{
+		Use:   "set",
+		Short: "Create or update the quotas of a namespace",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
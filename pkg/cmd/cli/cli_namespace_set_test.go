@@ -0,0 +1,61 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceSetCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ns := mock.NewMockNamespaceInterface(ctrl)
+	f := &mockFactory{namespaces: ns}
+
+	cmd := newCmdSetNamespace(f)
+	info := &v2.NamespaceInfo{
+		Name:                   "team-a",
+		MaxDeclaredMemoryQuota: 1024,
+		MaxChangefeeds:         2,
+		AllowedSinkSchemes:     []string{"kafka", "mysql"},
+		CaptureLabels:          map[string]string{"zone": "z1"},
+	}
+	ns.EXPECT().Put(gomock.Any(), info).Return(info, nil)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	os.Args = []string{
+		"set", "-n=team-a", "--max-declared-memory-quota=1024", "--max-changefeeds=2",
+		"--allowed-sink-schemes=kafka,mysql", "--capture-labels=zone=z1",
+	}
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), `"name": "team-a"`)
+
+	o := newSetNamespaceOptions()
+	o.complete(f)
+	o.name = "team_a"
+	ns.EXPECT().Put(gomock.Any(), gomock.Any()).
+		Return(nil, cerror.ErrInvalidNamespace.GenWithStackByArgs(128))
+	require.NotNil(t, o.run(cmd))
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// upstream holds upstream information.
+type upstream struct {
+	ID          uint64   `json:"id"`
+	PDAddrs     []string `json:"pd-addrs"`
+	IsDefault   bool     `json:"is-default"`
+	IsIdle      bool     `json:"is-idle"`
+	Changefeeds []string `json:"changefeeds"`
+	GCSafepoint uint64   `json:"gc-safepoint"`
+}
+
+// listUpstreamOptions defines flags for the `cli upstream list` command.
+type listUpstreamOptions struct {
+	apiv2Client apiv2client.APIV2Interface
+}
+
+// newListUpstreamOptions creates new listUpstreamOptions for the `cli upstream list` command.
+func newListUpstreamOptions() *listUpstreamOptions {
+	return &listUpstreamOptions{}
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *listUpstreamOptions) complete(f factory.Factory) error {
+	apiv2Client, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiv2Client = apiv2Client
+	return nil
+}
+
+// run runs the `cli upstream list` command.
+func (o *listUpstreamOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	raw, err := o.apiv2Client.Upstreams().List(ctx)
+	if err != nil {
+		return err
+	}
+	upstreams := make([]*upstream, 0, len(raw))
+	for _, up := range raw {
+		upstreams = append(upstreams,
+			&upstream{
+				ID:          up.ID,
+				PDAddrs:     up.PDAddrs,
+				IsDefault:   up.IsDefault,
+				IsIdle:      up.IsIdle,
+				Changefeeds: up.Changefeeds,
+				GCSafepoint: up.GCSafepoint,
+			})
+	}
+
+	return util.JSONPrint(cmd, upstreams)
+}
+
+// newCmdListUpstream creates the `cli upstream list` command.
+func newCmdListUpstream(f factory.Factory) *cobra.Command {
+	o := newListUpstreamOptions()
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List all upstream TiDB clusters in TiCDC cluster",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.run(cmd))
+		},
+	}
+
+	return command
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiflow/pkg/api/v2/mock"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamRemoveCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	up := mock.NewMockUpstreamInterface(ctrl)
+	f := &mockFactory{upstreams: up}
+
+	cmd := newCmdRemoveUpstream(f)
+	up.EXPECT().Delete(gomock.Any(), uint64(2)).Return(nil)
+	os.Args = []string{"remove", "--upstream-id=2"}
+	require.Nil(t, cmd.Execute())
+
+	o := newRemoveUpstreamOptions()
+	o.complete(f)
+	o.upstreamID = 2
+	up.EXPECT().Delete(gomock.Any(), uint64(2)).
+		Return(cerror.ErrUpstreamInUse.GenWithStackByArgs(2, []string{"abc"}))
+	require.NotNil(t, o.run(cmd))
+}
@@ -16,6 +16,7 @@ package redo
 import (
 	"net/url"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/pkg/applier"
 	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -25,7 +26,11 @@ import (
 // applyRedoOptions defines flags for the `redo apply` command.
 type applyRedoOptions struct {
 	options
-	sinkURI string
+	sinkURI     string
+	startTs     uint64
+	targetTs    uint64
+	filterRules []string
+	dryRun      bool
 }
 
 // newapplyRedoOptions creates new applyRedoOptions for the `redo apply` command.
@@ -36,13 +41,20 @@ func newapplyRedoOptions() *applyRedoOptions {
 // addFlags receives a *cobra.Command reference and binds
 // flags related to template printing to it.
 func (o *applyRedoOptions) addFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "target database sink-uri")
-	// the possible error returned from MarkFlagRequired is `no such flag`
-	cmd.MarkFlagRequired("sink-uri") //nolint:errcheck
+	cmd.Flags().StringVar(&o.sinkURI, "sink-uri", "", "target database sink-uri, required unless --dry-run is set")
+	cmd.Flags().Uint64Var(&o.startTs, "start-ts", 0, "apply redo logs from the ts, it can be the retained ts printed by \"redo inspect\" to apply logs retained before the checkpoint ts, the checkpoint ts of redo logs is used if not set")
+	cmd.Flags().Uint64Var(&o.targetTs, "target-ts", 0, "apply redo logs up to the ts, the resolved ts of redo logs is used if not set")
+	cmd.Flags().StringSliceVar(&o.filterRules, "filter", nil, "table filter rules, with the same syntax as the filter rules of changefeed, eg, \"test.t1\"")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "print SQL statements instead of executing them in sink")
 }
 
-//nolint:unparam
 func (o *applyRedoOptions) complete(cmd *cobra.Command) error {
+	if o.dryRun {
+		return nil
+	}
+	if o.sinkURI == "" {
+		return errors.New("sink-uri is required unless --dry-run is set")
+	}
 	// parse sinkURI as a URI
 	sinkURI, err := url.Parse(o.sinkURI)
 	if err != nil {
@@ -63,16 +75,23 @@ func (o *applyRedoOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
 	cfg := &applier.RedoApplierConfig{
-		Storage: o.storage,
-		SinkURI: o.sinkURI,
-		Dir:     o.dir,
+		Storage:     o.storage,
+		SinkURI:     o.sinkURI,
+		Dir:         o.dir,
+		StartTs:     o.startTs,
+		TargetTs:    o.targetTs,
+		FilterRules: o.filterRules,
+		DryRun:      o.dryRun,
+		Output:      cmd.OutOrStdout(),
 	}
 	ap := applier.NewRedoApplier(cfg)
 	err := ap.Apply(ctx)
 	if err != nil {
 		return err
 	}
-	cmd.Println("Apply redo log successfully")
+	if !o.dryRun {
+		cmd.Println("Apply redo log successfully")
+	}
 	return nil
 }
 
@@ -39,4 +39,12 @@ func TestComplete(t *testing.T) {
 	err = o.complete(cmd)
 	require.NoError(t, err)
 	require.Equal(t, "mysql://root@127.0.0.1:3306?time-zone=UTC&safe-mode=true", o.sinkURI)
+
+	o.sinkURI = ""
+	err = o.complete(cmd)
+	require.Error(t, err)
+
+	o.dryRun = true
+	err = o.complete(cmd)
+	require.NoError(t, err)
 }
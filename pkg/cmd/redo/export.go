@@ -0,0 +1,110 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"net/url"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/applier"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+	"github.com/spf13/cobra"
+)
+
+// exportRedoOptions defines flags for the `redo export` command.
+type exportRedoOptions struct {
+	options
+	format       string
+	output       string
+	progressFile string
+}
+
+// newExportRedoOptions creates new exportRedoOptions for the `redo export` command.
+func newExportRedoOptions() *exportRedoOptions {
+	return &exportRedoOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *exportRedoOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.format, "format", "", "format of exported events (canal-json|csv|avro)")
+	cmd.Flags().StringVar(&o.output, "output", "", "URI of the storage or MQ which events are exported to, eg, \"s3://bucket/prefix\", \"kafka://127.0.0.1:9092/topic\"")
+	cmd.Flags().StringVar(&o.progressFile, "progress-file", "", "local file to record the export progress, an interrupted export is resumed from it")
+	// the possible error returned from MarkFlagRequired is `no such flag`
+	cmd.MarkFlagRequired("format") //nolint:errcheck
+	cmd.MarkFlagRequired("output") //nolint:errcheck
+}
+
+func (o *exportRedoOptions) complete(cmd *cobra.Command) error {
+	protocol, err := config.ParseSinkProtocolFromString(o.format)
+	if err != nil {
+		return err
+	}
+	switch protocol {
+	case config.ProtocolCanalJSON, config.ProtocolCsv, config.ProtocolAvro:
+	default:
+		return errors.Errorf("format %s is not supported by redo export", o.format)
+	}
+
+	outputURI, err := url.Parse(o.output)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMQScheme(outputURI.Scheme) && !sink.IsStorageScheme(outputURI.Scheme) {
+		return cerror.ErrSinkURIInvalid.GenWithStackByArgs(o.output)
+	}
+	return nil
+}
+
+// run runs the `redo export` command.
+func (o *exportRedoOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	cfg := &applier.RedoApplierConfig{
+		Storage:      o.storage,
+		SinkURI:      o.output,
+		Dir:          o.dir,
+		Protocol:     o.format,
+		ProgressFile: o.progressFile,
+	}
+	ap := applier.NewRedoApplier(cfg)
+	err := ap.Apply(ctx)
+	if err != nil {
+		return err
+	}
+	cmd.Println("Export redo log successfully")
+	return nil
+}
+
+// newCmdExport creates the `redo export` command.
+func newCmdExport(opt *options) *cobra.Command {
+	o := newExportRedoOptions()
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Export redo logs to storage or MQ in the given format",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			if err := o.complete(cmd); err != nil {
+				return err
+			}
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteExport(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "test",
+	}
+	o := newExportRedoOptions()
+	o.format = "csv"
+	o.output = "s3://bucket/prefix"
+	require.NoError(t, o.complete(cmd))
+
+	o.format = "canal-json"
+	o.output = "kafka://127.0.0.1:9092/topic"
+	require.NoError(t, o.complete(cmd))
+
+	o.format = "open-protocol"
+	require.Error(t, o.complete(cmd))
+
+	o.format = "avro"
+	o.output = "mysql://root@127.0.0.1:3306"
+	require.Regexp(t, "ErrSinkURIInvalid", o.complete(cmd))
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/redo"
+	"github.com/spf13/cobra"
+)
+
+// inspectOptions defines flags for the `redo inspect` command.
+type inspectOptions struct {
+	options
+	table   string
+	tsRange string
+
+	startTs uint64
+	endTs   uint64
+}
+
+// newInspectOptions creates new inspectOptions for the `redo inspect` command.
+func newInspectOptions() *inspectOptions {
+	return &inspectOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *inspectOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.table, "table", "", "dump events of the table in JSON, eg, \"test.t1\"")
+	cmd.Flags().StringVar(&o.tsRange, "ts-range", "", "dump events with commit ts in the range (start,end] in JSON, eg, \"400000000,410000000\"")
+}
+
+func (o *inspectOptions) complete(cmd *cobra.Command) error {
+	if o.tsRange == "" {
+		return nil
+	}
+	parts := strings.Split(o.tsRange, ",")
+	if len(parts) != 2 {
+		return errors.Errorf("invalid ts range %s, it should be in the form of start,end", o.tsRange)
+	}
+	var err error
+	if o.startTs, err = strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 64); err != nil {
+		return errors.Annotatef(err, "invalid ts range %s", o.tsRange)
+	}
+	if o.endTs, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64); err != nil {
+		return errors.Annotatef(err, "invalid ts range %s", o.tsRange)
+	}
+	if o.startTs >= o.endTs {
+		return errors.Errorf("invalid ts range %s, start should be less than end", o.tsRange)
+	}
+	return nil
+}
+
+// run runs the `redo inspect` command.
+func (o *inspectOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	uri, err := url.Parse(o.storage)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrConsistentStorage, err)
+	}
+	if redo.IsLocalStorage(uri.Scheme) {
+		uri.Scheme = "file"
+	}
+	cfg := &reader.InspectConfig{
+		URI:     *uri,
+		Table:   o.table,
+		StartTs: o.startTs,
+		EndTs:   o.endTs,
+	}
+	// Events are printed while files are inspected, before the summary.
+	if o.table != "" || o.tsRange != "" {
+		cfg.OnEvent = func(e *model.RedoLog) error {
+			return printEvent(cmd, e)
+		}
+	}
+	summary, err := reader.Inspect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	printSummary(cmd, summary)
+	return nil
+}
+
+func printSummary(cmd *cobra.Command, summary *reader.InspectSummary) {
+	if summary.Meta == nil {
+		cmd.Println("meta: not found")
+	} else {
+		cmd.Printf("meta: checkpoint-ts:%d, resolved-ts:%d, retained-ts:%d, compacted-ts:%d\n",
+			summary.Meta.CheckpointTs, summary.Meta.ResolvedTs,
+			summary.Meta.RetainedTs, summary.Meta.CompactedTs)
+	}
+	cmd.Printf("commit-ts range: [%d, %d]\n", summary.MinCommitTs, summary.MaxCommitTs)
+
+	var corrupted []*reader.LogFileSummary
+	cmd.Printf("files (%d):\n", len(summary.Files))
+	for _, f := range summary.Files {
+		cmd.Printf("  %s type:%s, size:%d, max-commit-ts:%d, events:%d, status:%s\n",
+			f.Name, f.FileType, f.Size, f.MaxCommitTs, f.EventCount, f.Status)
+		if f.Status == reader.LogFileCorrupted {
+			corrupted = append(corrupted, f)
+		}
+	}
+	cmd.Printf("tables (%d):\n", len(summary.Tables))
+	for _, t := range summary.Tables {
+		cmd.Printf("  %s.%s table-id:%d, rows:%d, commit-ts range: [%d, %d]\n",
+			t.Schema, t.Table, t.TableID, t.RowCount, t.MinCommitTs, t.MaxCommitTs)
+	}
+	cmd.Printf("ddls (%d):\n", len(summary.DDLs))
+	for _, ddl := range summary.DDLs {
+		cmd.Printf("  commit-ts:%d, query:%s\n", ddl.CommitTs, ddl.Query)
+	}
+	cmd.Printf("corrupted files (%d):\n", len(corrupted))
+	for _, f := range corrupted {
+		cmd.Printf("  %s error:%s\n", f.Name, f.Error)
+	}
+}
+
+// inspectEvent is the JSON format of a dumped event.
+type inspectEvent struct {
+	CommitTs uint64                 `json:"commit-ts"`
+	Row      *model.RowChangedEvent `json:"row,omitempty"`
+	DDL      *model.DDLEvent        `json:"ddl,omitempty"`
+}
+
+func printEvent(cmd *cobra.Command, e *model.RedoLog) error {
+	event := &inspectEvent{CommitTs: e.GetCommitTs()}
+	if e.Type == model.RedoLogTypeRow {
+		event.Row = e.RedoRow.Row
+	} else {
+		event.DDL = e.RedoDDL.DDL
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	cmd.Println(string(data))
+	return nil
+}
+
+// newCmdInspect creates the `redo inspect` command.
+func newCmdInspect(opt *options) *cobra.Command {
+	o := newInspectOptions()
+	command := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect redo logs, print a summary and dump events",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			if err := o.complete(cmd); err != nil {
+				return err
+			}
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteInspect(t *testing.T) {
+	cmd := &cobra.Command{
+		Use: "test",
+	}
+	o := newInspectOptions()
+	require.NoError(t, o.complete(cmd))
+
+	o.tsRange = "100, 200"
+	require.NoError(t, o.complete(cmd))
+	require.Equal(t, uint64(100), o.startTs)
+	require.Equal(t, uint64(200), o.endTs)
+
+	for _, tsRange := range []string{"100", "a,200", "100,b", "200,100"} {
+		o.tsRange = tsRange
+		require.Error(t, o.complete(cmd), tsRange)
+	}
+}
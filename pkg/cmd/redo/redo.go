@@ -69,6 +69,8 @@ func NewCmdRedo() *cobra.Command {
 
 	// Add subcommands.
 	cmds.AddCommand(newCmdApply(o))
+	cmds.AddCommand(newCmdExport(o))
+	cmds.AddCommand(newCmdInspect(o))
 	cmds.AddCommand(newCmdMeta(o))
 
 	return cmds
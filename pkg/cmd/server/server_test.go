@@ -198,6 +198,8 @@ func TestParseCfg(t *testing.T) {
 				MaxTaskConcurrency:   10,
 				CheckBalanceInterval: 60000000000,
 				AddTableBatchSize:    50,
+				BalanceStrategy:      config.BalanceStrategyTableCount,
+				BalanceThreshold:     0.2,
 			},
 		},
 		ClusterID:           "default",
@@ -341,6 +343,8 @@ check-balance-interval = "10s"
 				MaxTaskConcurrency:   11,
 				CheckBalanceInterval: config.TomlDuration(10 * time.Second),
 				AddTableBatchSize:    50,
+				BalanceStrategy:      config.BalanceStrategyTableCount,
+				BalanceThreshold:     0.2,
 			},
 		},
 		ClusterID:           "default",
@@ -473,6 +477,8 @@ cert-allowed-cn = ["dd","ee"]
 				MaxTaskConcurrency:   10,
 				CheckBalanceInterval: 60000000000,
 				AddTableBatchSize:    50,
+				BalanceStrategy:      config.BalanceStrategyTableCount,
+				BalanceThreshold:     0.2,
 			},
 		},
 		ClusterID:           "default",
@@ -534,6 +540,8 @@ unknown3 = 3
 			MaxTaskConcurrency:   10,
 			CheckBalanceInterval: 60000000000,
 			AddTableBatchSize:    50,
+			BalanceStrategy:      config.BalanceStrategyTableCount,
+			BalanceThreshold:     0.2,
 		},
 	}, o.serverConfig.Debug)
 }
@@ -0,0 +1,127 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// BDRConflictPolicy is the policy to resolve a conflict in BDR mode.
+type BDRConflictPolicy string
+
+const (
+	// BDRConflictPolicyLastWriterWins keeps the write with the larger version,
+	// the write from the source with the larger source id wins a tie.
+	BDRConflictPolicyLastWriterWins BDRConflictPolicy = "last-writer-wins"
+	// BDRConflictPolicySourcePriority keeps the write from the source which
+	// is listed earlier in source-priority.
+	BDRConflictPolicySourcePriority BDRConflictPolicy = "source-priority"
+	// BDRConflictPolicyLogAndSkip keeps the downstream row, and only records
+	// the conflict.
+	BDRConflictPolicyLogAndSkip BDRConflictPolicy = "log-and-skip"
+
+	// DefaultBDRConflictTable is the default table which records conflicts.
+	DefaultBDRConflictTable = "tidb_cdc.bdr_conflict"
+)
+
+// BDRConflictConfig represents how the MySQL sink detects and resolves
+// conflicts in BDR mode. A conflict happens when a row is changed in both
+// clusters before the change of one cluster is replicated to the other one.
+type BDRConflictConfig struct {
+	Policy BDRConflictPolicy `toml:"policy" json:"policy"`
+	// VersionColumn is the column which holds the version of the last write
+	// to a row, like its commit ts, it must be kept up to date by writers of
+	// both clusters. A row is in conflict if its version in the downstream is
+	// different from the version in the old value of the incoming event.
+	// Tables without this column are not checked.
+	VersionColumn string `toml:"version-column" json:"version-column"`
+	// DownstreamSourceID is the source id of writes made in the downstream
+	// cluster, which is the tidb-source-id of the changefeed in the opposite
+	// direction.
+	DownstreamSourceID uint64 `toml:"downstream-source-id" json:"downstream-source-id"`
+	// SourcePriority lists source ids from the highest priority to the lowest,
+	// it is used by the source-priority policy.
+	SourcePriority []uint64 `toml:"source-priority" json:"source-priority,omitempty"`
+	// ConflictTable is the downstream table which records all conflicts
+	// and how they are resolved, in the format of "schema.table".
+	ConflictTable string `toml:"conflict-table" json:"conflict-table"`
+}
+
+// ValidateAndAdjust validates the bdr conflict config and adjusts it if necessary.
+func (c *BDRConflictConfig) ValidateAndAdjust() error {
+	switch c.Policy {
+	case BDRConflictPolicyLastWriterWins, BDRConflictPolicyLogAndSkip:
+	case BDRConflictPolicySourcePriority:
+		if len(c.SourcePriority) == 0 {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"source-priority must be set for the source-priority bdr conflict policy")
+		}
+	default:
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid bdr conflict policy: %s", c.Policy))
+	}
+	if c.VersionColumn == "" {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"version-column must be set to detect bdr conflicts")
+	}
+	if c.DownstreamSourceID == 0 && c.Policy != BDRConflictPolicyLogAndSkip {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("downstream-source-id must be set for the %s bdr conflict policy",
+				c.Policy))
+	}
+	if c.ConflictTable == "" {
+		c.ConflictTable = DefaultBDRConflictTable
+	}
+	if parts := strings.Split(c.ConflictTable, "."); len(parts) != 2 ||
+		parts[0] == "" || parts[1] == "" {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid conflict-table %s, it must be schema.table", c.ConflictTable))
+	}
+	return nil
+}
+
+// IncomingWins returns true if the incoming write replaces the downstream row.
+// incomingVersion and downstreamVersion are the versions of the incoming write
+// and the downstream row, incomingSourceID is the source id of the incoming write.
+// A conflict with a deleted row is resolved as a tie of versions, since the
+// deleted row has no version.
+func (c *BDRConflictConfig) IncomingWins(
+	incomingVersion, downstreamVersion, incomingSourceID uint64,
+) bool {
+	switch c.Policy {
+	case BDRConflictPolicyLastWriterWins:
+		if incomingVersion != downstreamVersion {
+			return incomingVersion > downstreamVersion
+		}
+		return incomingSourceID > c.DownstreamSourceID
+	case BDRConflictPolicySourcePriority:
+		return c.sourceRank(incomingSourceID) < c.sourceRank(c.DownstreamSourceID)
+	default:
+		return false
+	}
+}
+
+// sourceRank returns the index of the source id in SourcePriority,
+// sources which are not listed have the lowest priority.
+func (c *BDRConflictConfig) sourceRank(sourceID uint64) int {
+	for i, id := range c.SourcePriority {
+		if id == sourceID {
+			return i
+		}
+	}
+	return len(c.SourcePriority)
+}
@@ -69,7 +69,11 @@ const (
   "integrity": {
     "integrity-check-level": "none",
     "corruption-handle-level": "warn"
- }
+  },
+  "on-finish": {
+    "action": "finish",
+    "write-marker": false
+  }
 }`
 
 	testCfgTestServerConfigMarshal = `{
@@ -94,6 +98,7 @@ const (
   "sorter": {
     "max-memory-percentage": 10,
     "sort-dir": "/tmp/sorter",
+    "max-changefeed-disk-usage": 0,
     "max-memory-consumption": 0,
     "num-workerpool-goroutine": 0,
     "num-concurrent-worker": 0,
@@ -125,7 +130,9 @@ const (
       "compaction-deletion-threshold": 10485760,
       "compaction-period": 1800,
       "iterator-max-alive-duration": 10000,
-      "iterator-slow-read-duration": 256
+      "iterator-slow-read-duration": 256,
+      "spill-disk-quota": 0,
+      "spill-storage": ""
     },
     "messages": {
       "client-max-batch-interval": 10000000,
@@ -142,7 +149,9 @@ const (
       "collect-stats-tick": 200,
       "max-task-concurrency": 10,
       "check-balance-interval": 60000000000,
-      "add-table-batch-size": 50
+      "add-table-batch-size": 50,
+      "balance-strategy": "table-count",
+      "balance-threshold": 0.2
     }
   },
   "cluster-id": "default",
@@ -196,6 +205,7 @@ const (
     "date-separator": "month",
     "enable-partition-separator": true,
     "only-output-updated-columns": false,
+    "enable-txn-boundary": false,
     "enable-kafka-sink-v2": true
   },
   "consistent": {
@@ -215,6 +225,10 @@ const (
   "integrity": {
     "integrity-check-level": "none",
     "corruption-handle-level": "warn"
+  },
+  "on-finish": {
+    "action": "finish",
+    "write-marker": false
   }
 }`
 
@@ -262,6 +276,7 @@ const (
     "date-separator": "month",
     "enable-partition-separator": true,
     "only-output-updated-columns": false,
+    "enable-txn-boundary": false,
 	"enable-kafka-sink-v2": true
   },
   "consistent": {
@@ -279,6 +294,10 @@ const (
   "integrity": {
     "integrity-check-level": "none",
     "corruption-handle-level": "warn"
+  },
+  "on-finish": {
+    "action": "finish",
+    "write-marker": false
   }
 }`
 )
@@ -28,6 +28,14 @@ type ConsistentConfig struct {
 	FlushIntervalInMs int64  `toml:"flush-interval" json:"flush-interval"`
 	Storage           string `toml:"storage" json:"storage"`
 	UseFileBackend    bool   `toml:"use-file-backend" json:"use-file-backend"`
+	// RetentionHours is the hours of redo logs kept before the checkpoint,
+	// 0 means redo logs are removed once they are before the checkpoint.
+	// Retained logs are applied by `redo apply --start-ts`.
+	RetentionHours int64 `toml:"retention-hours" json:"retention-hours,omitempty"`
+	// CompactionFileSize is the size in MB that small redo log files are
+	// compacted into, 0 means compaction is disabled. Superseded updates are
+	// dropped only before the retention window.
+	CompactionFileSize int64 `toml:"compaction-file-size" json:"compaction-file-size,omitempty"`
 }
 
 // ValidateAndAdjust validates the consistency config and adjusts it if necessary.
@@ -49,6 +57,22 @@ func (c *ConsistentConfig) ValidateAndAdjust() error {
 				c.FlushIntervalInMs, redo.MinFlushIntervalInMs))
 	}
 
+	if c.RetentionHours < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.retention-hours:%d must be equal or greater than 0",
+				c.RetentionHours))
+	}
+	if c.CompactionFileSize < 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			fmt.Sprintf("The consistent.compaction-file-size:%d must be equal or greater than 0",
+				c.CompactionFileSize))
+	}
+	// Only retained redo logs are compacted.
+	if c.CompactionFileSize > 0 && c.RetentionHours == 0 {
+		return cerror.ErrInvalidReplicaConfig.FastGenByArgs(
+			"The consistent.compaction-file-size requires consistent.retention-hours")
+	}
+
 	uri, err := storage.ParseRawURL(c.Storage)
 	if err != nil {
 		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
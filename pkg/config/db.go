@@ -75,6 +75,18 @@ type DBConfig struct {
 	//
 	// The default value is 256, 256ms.
 	IteratorSlowReadDuration int `toml:"iterator-slow-read-duration" json:"iterator-slow-read-duration"`
+
+	// SpillDiskQuota is the maximum disk usage of db sorter in bytes. Resolved
+	// events are spilled to SpillStorage after the quota is exceeded, so that
+	// a stalled changefeed can't fill up the disk of sort-dir.
+	//
+	// The default value is 0, which means spilling is disabled.
+	SpillDiskQuota uint64 `toml:"spill-disk-quota" json:"spill-disk-quota"`
+	// SpillStorage is the URI of the storage that events are spilled to, eg,
+	// "file:///data/spill" or "s3://bucket/prefix". It can be shared by
+	// captures, segments of a capture are put under the identity of its
+	// sort-dir, and only they are cleared when the capture starts.
+	SpillStorage string `toml:"spill-storage" json:"spill-storage"`
 }
 
 // ValidateAndAdjust validates and adjusts the db configuration
@@ -83,6 +95,10 @@ func (c *DBConfig) ValidateAndAdjust() error {
 		return errors.ErrIllegalSorterParameter.GenWithStackByArgs(
 			"sorter.leveldb.compression must be \"none\" or \"snappy\"")
 	}
+	if c.SpillDiskQuota > 0 && c.SpillStorage == "" {
+		return errors.ErrIllegalSorterParameter.GenWithStackByArgs(
+			"sorter spill-storage must be set if spill-disk-quota is set")
+	}
 
 	return nil
 }
@@ -0,0 +1,73 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// ddlActionTypes maps the names of DDL types to the action types,
+// the names are the same as the ones shown in `ADMIN SHOW DDL JOBS`.
+var ddlActionTypes = func() map[string]timodel.ActionType {
+	res := make(map[string]timodel.ActionType)
+	for i := 1; i <= math.MaxUint8; i++ {
+		action := timodel.ActionType(i)
+		if name := action.String(); name != "none" {
+			res[name] = action
+		}
+	}
+	return res
+}()
+
+// DDLApprovalConfig represents the DDL types which are held before they are
+// executed to the downstream, until an operator approves, skips or replaces
+// them. It is used to prevent risky DDLs executed upstream by accident from
+// being replicated.
+type DDLApprovalConfig struct {
+	// DDLTypes are the names of DDL types to hold, for example
+	// "drop table", "truncate table" and "drop column".
+	DDLTypes []string `toml:"ddl-types" json:"ddl-types"`
+}
+
+// ValidateAndAdjust validates the ddl approval config and adjusts it if necessary.
+func (c *DDLApprovalConfig) ValidateAndAdjust() error {
+	for i, name := range c.DDLTypes {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := ddlActionTypes[name]; !ok {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				fmt.Sprintf("invalid ddl type in ddl-approval: %s", c.DDLTypes[i]))
+		}
+		c.DDLTypes[i] = name
+	}
+	return nil
+}
+
+// NeedApproval returns true if DDLs of the given type must be approved
+// before they are executed, a nil config means no DDL needs approval.
+func (c *DDLApprovalConfig) NeedApproval(action timodel.ActionType) bool {
+	if c == nil {
+		return false
+	}
+	for _, name := range c.DDLTypes {
+		if t, ok := ddlActionTypes[strings.ToLower(strings.TrimSpace(name))]; ok && t == action {
+			return true
+		}
+	}
+	return false
+}
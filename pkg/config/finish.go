@@ -0,0 +1,70 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// FinishActionFinish marks the changefeed as finished, the default value.
+	FinishActionFinish string = "finish"
+	// FinishActionPause pauses the changefeed, so that it can be resumed
+	// after the target ts is moved forward.
+	FinishActionPause string = "pause"
+	// FinishActionRemove removes the changefeed.
+	FinishActionRemove string = "remove"
+)
+
+// OnFinishConfig represents the actions to take once a changefeed
+// reaches its target ts.
+type OnFinishConfig struct {
+	// Action is the state the changefeed is moved to, it can be
+	// "finish", "pause" or "remove".
+	Action string `toml:"action" json:"action"`
+	// WriteMarker set true to write a marker to the downstream at the
+	// target ts before taking the action. It is written as a syncpoint, a
+	// syncpoint row for MySQL-compatible sinks, and a syncpoint marker for
+	// MQ and storage sinks, other sinks are rejected.
+	WriteMarker bool `toml:"write-marker" json:"write-marker"`
+}
+
+// ValidateAndAdjust validates the on-finish config and adjusts it if necessary.
+func (c *OnFinishConfig) ValidateAndAdjust() error {
+	switch c.Action {
+	case "":
+		c.Action = FinishActionFinish
+	case FinishActionFinish, FinishActionPause, FinishActionRemove:
+	default:
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid on-finish action: %s, must be one of %s, %s and %s",
+				c.Action, FinishActionFinish, FinishActionPause, FinishActionRemove))
+	}
+	return nil
+}
+
+// GetAction returns the on-finish action, a nil config means FinishActionFinish.
+func (c *OnFinishConfig) GetAction() string {
+	if c == nil || c.Action == "" {
+		return FinishActionFinish
+	}
+	return c.Action
+}
+
+// NeedWriteMarker returns true if a marker should be written to the downstream.
+func (c *OnFinishConfig) NeedWriteMarker() bool {
+	return c != nil && c.WriteMarker
+}
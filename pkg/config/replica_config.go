@@ -79,6 +79,10 @@ var defaultReplicaConfig = &ReplicaConfig{
 		IntegrityCheckLevel:   IntegrityCheckLevelNone,
 		CorruptionHandleLevel: CorruptionHandleLevelWarn,
 	},
+	OnFinish: &OnFinishConfig{
+		Action:      FinishActionFinish,
+		WriteMarker: false,
+	},
 }
 
 // GetDefaultReplicaConfig returns the default replica config.
@@ -121,6 +125,18 @@ type replicaConfig struct {
 	// Scheduler is the configuration for scheduler.
 	Scheduler *ChangefeedSchedulerConfig `toml:"scheduler" json:"scheduler"`
 	Integrity *IntegrityConfig           `toml:"integrity" json:"integrity"`
+	// OnFinish is the configuration for actions taken when the changefeed
+	// reaches its target ts.
+	OnFinish *OnFinishConfig `toml:"on-finish" json:"on-finish"`
+	// DDLApproval is the configuration for DDLs held for manual approval
+	// before they are executed to the downstream.
+	DDLApproval *DDLApprovalConfig `toml:"ddl-approval" json:"ddl-approval,omitempty"`
+	// BDRConflict is the configuration for detecting and resolving conflicts
+	// in BDR mode, conflicts are not detected if it is nil.
+	BDRConflict *BDRConflictConfig `toml:"bdr-conflict" json:"bdr-conflict,omitempty"`
+	// Snapshot is the configuration for exporting the initial snapshot of
+	// tables before the incremental replication.
+	Snapshot *SnapshotConfig `toml:"snapshot" json:"snapshot,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -217,6 +233,18 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error {
 	if c.Scheduler == nil {
 		c.FixScheduler(false)
 	}
+	if err := c.Scheduler.ValidateAndAdjust(); err != nil {
+		return err
+	}
+	// Transactions spanning multiple tables are assembled by one processor,
+	// tables must not be placed on several captures.
+	if c.Sink != nil && c.Sink.TxnAtomicity.IsGlobal() {
+		if c.Scheduler.EnableTableAcrossNodes || c.Scheduler.SpreadLabel != "" {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"global level transaction atomicity can not be used with " +
+					"enable-table-across-nodes or spread-label")
+		}
+	}
 	// TODO: Remove the hack once span replication is compatible with all sinks.
 	if !isSinkCompatibleWithSpanReplication(sinkURI) {
 		c.Scheduler.EnableTableAcrossNodes = false
@@ -237,6 +265,34 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error {
 		}
 	}
 
+	if c.OnFinish != nil {
+		if err := c.OnFinish.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+
+	if c.DDLApproval != nil {
+		if err := c.DDLApproval.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+
+	if c.Snapshot != nil {
+		if err := c.Snapshot.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+
+	if c.BDRConflict != nil {
+		if !c.BDRMode {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				"bdr-conflict can only be set in bdr mode")
+		}
+		if err := c.BDRConflict.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"testing"
 	"time"
 
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -185,6 +186,185 @@ func TestValidateAndAdjust(t *testing.T) {
 	cfg.Integrity.IntegrityCheckLevel = IntegrityCheckLevelCorrectness
 	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
 	require.Equal(t, IntegrityCheckLevelNone, cfg.Integrity.IntegrityCheckLevel)
+
+	// capture labels must not be empty.
+	cfg = GetDefaultReplicaConfig()
+	cfg.Scheduler.CaptureLabels = map[string]string{"zone": ""}
+	require.Regexp(t, ".*can not be empty.*", cfg.ValidateAndAdjust(sinkURL))
+	cfg.Scheduler.CaptureLabels = map[string]string{"zone": "a"}
+	cfg.Scheduler.SpreadLabel = " host "
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, "host", cfg.Scheduler.SpreadLabel)
+
+	// merge threshold must be less than split threshold.
+	cfg = GetDefaultReplicaConfig()
+	cfg.Scheduler.SplitWriteThreshold = -1
+	require.Regexp(t, ".*must not be negative.*", cfg.ValidateAndAdjust(sinkURL))
+	cfg.Scheduler.SplitWriteThreshold = 100
+	cfg.Scheduler.MergeWriteThreshold = 100
+	require.Regexp(t, ".*must be less than.*", cfg.ValidateAndAdjust(sinkURL))
+	cfg.Scheduler.MergeWriteThreshold = 10
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, DefaultSpanRebalanceInterval, cfg.Scheduler.GetSpanRebalanceInterval())
+	cfg.Scheduler.SpanRebalanceInterval = -time.Second
+	require.Regexp(t, ".*span-rebalance-interval.*", cfg.ValidateAndAdjust(sinkURL))
+	cfg.Scheduler.SpanRebalanceInterval = 5 * time.Minute
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+	require.Equal(t, 5*time.Minute, cfg.Scheduler.GetSpanRebalanceInterval())
+
+	// redo log compaction requires retention.
+	cfg = GetDefaultReplicaConfig()
+	cfg.Consistent.Level = "eventual"
+	cfg.Consistent.Storage = "blackhole://"
+	cfg.Consistent.RetentionHours = -1
+	require.Regexp(t, ".*retention-hours.*", cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.RetentionHours = 0
+	cfg.Consistent.CompactionFileSize = 64
+	require.Regexp(t, ".*requires consistent.retention-hours.*", cfg.ValidateAndAdjust(sinkURL))
+	cfg.Consistent.RetentionHours = 24
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+}
+
+func TestDDLApprovalConfig(t *testing.T) {
+	t.Parallel()
+
+	var nilConfig *DDLApprovalConfig
+	require.False(t, nilConfig.NeedApproval(timodel.ActionDropTable))
+
+	sinkURI, err := url.Parse("blackhole://")
+	require.NoError(t, err)
+	conf := GetDefaultReplicaConfig()
+	conf.DDLApproval = &DDLApprovalConfig{
+		DDLTypes: []string{"DROP TABLE", " truncate table", "drop column"},
+	}
+	require.NoError(t, conf.ValidateAndAdjust(sinkURI))
+	require.Equal(t, []string{"drop table", "truncate table", "drop column"},
+		conf.DDLApproval.DDLTypes)
+	require.True(t, conf.DDLApproval.NeedApproval(timodel.ActionDropTable))
+	require.True(t, conf.DDLApproval.NeedApproval(timodel.ActionTruncateTable))
+	require.True(t, conf.DDLApproval.NeedApproval(timodel.ActionDropColumn))
+	require.False(t, conf.DDLApproval.NeedApproval(timodel.ActionAddColumn))
+
+	conf.DDLApproval.DDLTypes = []string{"drop everything"}
+	require.ErrorContains(t, conf.ValidateAndAdjust(sinkURI), "drop everything")
+}
+
+func TestBDRConflictConfig(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("mysql://127.0.0.1:3306/")
+	require.NoError(t, err)
+	conf := GetDefaultReplicaConfig()
+	conf.BDRConflict = &BDRConflictConfig{
+		Policy:             BDRConflictPolicyLastWriterWins,
+		VersionColumn:      "version",
+		DownstreamSourceID: 2,
+	}
+	require.ErrorContains(t, conf.ValidateAndAdjust(sinkURI), "bdr mode")
+
+	conf.BDRMode = true
+	require.NoError(t, conf.ValidateAndAdjust(sinkURI))
+	require.Equal(t, DefaultBDRConflictTable, conf.BDRConflict.ConflictTable)
+
+	// last-writer-wins
+	c := conf.BDRConflict
+	require.True(t, c.IncomingWins(20, 10, 1))
+	require.False(t, c.IncomingWins(10, 20, 3))
+	require.True(t, c.IncomingWins(10, 10, 3))
+	require.False(t, c.IncomingWins(10, 10, 1))
+
+	// source-priority
+	c.Policy = BDRConflictPolicySourcePriority
+	require.ErrorContains(t, c.ValidateAndAdjust(), "source-priority must be set")
+	c.SourcePriority = []uint64{1, 2}
+	require.NoError(t, c.ValidateAndAdjust())
+	require.True(t, c.IncomingWins(10, 20, 1))
+	require.False(t, c.IncomingWins(20, 10, 3))
+
+	// log-and-skip
+	c.Policy = BDRConflictPolicyLogAndSkip
+	c.DownstreamSourceID = 0
+	require.NoError(t, c.ValidateAndAdjust())
+	require.False(t, c.IncomingWins(20, 10, 1))
+
+	c.Policy = "first-writer-wins"
+	require.ErrorContains(t, c.ValidateAndAdjust(), "first-writer-wins")
+	c.Policy = BDRConflictPolicyLastWriterWins
+	require.ErrorContains(t, c.ValidateAndAdjust(), "downstream-source-id")
+	c.DownstreamSourceID = 2
+	c.VersionColumn = ""
+	require.ErrorContains(t, c.ValidateAndAdjust(), "version-column")
+	c.VersionColumn = "version"
+	c.ConflictTable = "bdr_conflict"
+	require.ErrorContains(t, c.ValidateAndAdjust(), "bdr_conflict")
+}
+
+func TestSnapshotConfig(t *testing.T) {
+	t.Parallel()
+
+	var nilConfig *SnapshotConfig
+	require.False(t, nilConfig.IsEnabled())
+
+	sinkURI, err := url.Parse("blackhole://")
+	require.NoError(t, err)
+	conf := GetDefaultReplicaConfig()
+	conf.Snapshot = &SnapshotConfig{Enable: true}
+	require.NoError(t, conf.ValidateAndAdjust(sinkURI))
+	require.True(t, conf.Snapshot.IsEnabled())
+	require.Equal(t, defaultSnapshotChunkRows, conf.Snapshot.ChunkRows)
+	require.Equal(t, defaultSnapshotConcurrency, conf.Snapshot.Concurrency)
+	require.Equal(t, uint64(defaultSnapshotMemoryQuota), conf.Snapshot.MemoryQuota)
+
+	conf.Snapshot.ChunkRows = -1
+	require.ErrorContains(t, conf.ValidateAndAdjust(sinkURI), "chunk-rows")
+	conf.Snapshot.ChunkRows = 1
+	conf.Snapshot.Concurrency = -1
+	require.ErrorContains(t, conf.ValidateAndAdjust(sinkURI), "concurrency")
+}
+
+func TestGlobalTxnAtomicitySchedulerConfig(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("mysql://127.0.0.1:3306/?transaction-atomicity=global")
+	require.NoError(t, err)
+	conf := GetDefaultReplicaConfig()
+	require.NoError(t, conf.ValidateAndAdjust(sinkURI))
+	require.True(t, conf.Sink.TxnAtomicity.IsGlobal())
+
+	// Tables can not be placed on several captures.
+	conf.Scheduler.EnableTableAcrossNodes = true
+	require.ErrorContains(t, conf.ValidateAndAdjust(sinkURI), "enable-table-across-nodes")
+
+	conf = GetDefaultReplicaConfig()
+	conf.Scheduler.SpreadLabel = "zone"
+	require.ErrorContains(t, conf.ValidateAndAdjust(sinkURI), "spread-label")
+
+	// Table level atomicity is not affected.
+	sinkURI, err = url.Parse("mysql://127.0.0.1:3306/?transaction-atomicity=table")
+	require.NoError(t, err)
+	require.NoError(t, conf.ValidateAndAdjust(sinkURI))
+}
+
+func TestChangefeedSchedulerConfigCaptureLabels(t *testing.T) {
+	t.Parallel()
+
+	var nilCfg *ChangefeedSchedulerConfig
+	require.True(t, nilCfg.MatchCaptureLabels(nil))
+
+	cfg := &ChangefeedSchedulerConfig{
+		CaptureLabels: map[string]string{"zone": "a"},
+	}
+	require.True(t, cfg.MatchCaptureLabels(map[string]string{"zone": "a", "host": "h1"}))
+	require.False(t, cfg.MatchCaptureLabels(map[string]string{"zone": "b"}))
+	require.False(t, cfg.MatchCaptureLabels(nil))
+
+	// Labels of the namespace take precedence.
+	merged := cfg.WithCaptureLabels(map[string]string{"zone": "b", "rack": "r1"})
+	require.Equal(t, map[string]string{"zone": "b", "rack": "r1"}, merged.CaptureLabels)
+	require.Equal(t, map[string]string{"zone": "a"}, cfg.CaptureLabels)
+
+	merged = nilCfg.WithCaptureLabels(map[string]string{"zone": "b"})
+	require.Equal(t, map[string]string{"zone": "b"}, merged.CaptureLabels)
 }
 
 func TestIsSinkCompatibleWithSpanReplication(t *testing.T) {
@@ -14,11 +14,24 @@
 package config
 
 import (
+	"strings"
 	"time"
 
+	"github.com/pingcap/errors"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
+const (
+	// BalanceStrategyTableCount balances tables by the number of tables.
+	BalanceStrategyTableCount = "table-count"
+	// BalanceStrategyThroughput balances tables by the throughput of tables.
+	BalanceStrategyThroughput = "throughput"
+
+	// DefaultSpanRebalanceInterval is the default interval of splitting hot
+	// spans and merging cold spans.
+	DefaultSpanRebalanceInterval = time.Minute
+)
+
 // ChangefeedSchedulerConfig is per changefeed scheduler settings.
 type ChangefeedSchedulerConfig struct {
 	// EnableTableAcrossNodes set true to split one table to multiple spans and
@@ -30,6 +43,103 @@ type ChangefeedSchedulerConfig struct {
 	WriteKeyThreshold int `toml:"write-key-threshold" json:"write-key-threshold"`
 	// Deprecated.
 	RegionPerSpan int `toml:"region-per-span" json:"region-per-span"`
+	// SplitWriteThreshold is the write bytes per second threshold of
+	// splitting a replicating span, 0 disables splitting hot spans.
+	SplitWriteThreshold int `toml:"split-write-threshold" json:"split-write-threshold,omitempty"`
+	// MergeWriteThreshold is the write bytes per second threshold of merging
+	// adjacent spans of a table, 0 disables merging cold spans.
+	MergeWriteThreshold int `toml:"merge-write-threshold" json:"merge-write-threshold,omitempty"`
+	// SpanRebalanceInterval is the interval of splitting hot spans and
+	// merging cold spans, it is also the minimal interval between two changes
+	// of spans of a table. 0 means DefaultSpanRebalanceInterval.
+	SpanRebalanceInterval time.Duration `toml:"span-rebalance-interval" json:"span-rebalance-interval,omitempty"`
+	// CaptureLabels restricts the changefeed to run only on captures which
+	// have all the labels, e.g. {"zone": "a"}.
+	CaptureLabels map[string]string `toml:"capture-labels" json:"capture-labels,omitempty"`
+	// SpreadLabel is the key of a capture label, spans of a table are spread
+	// across captures with different values of the label, e.g. "zone".
+	SpreadLabel string `toml:"spread-label" json:"spread-label,omitempty"`
+	// SingleCapture pins all spans of the changefeed to one capture. It is
+	// not configurable, the owner sets it for changefeeds which require
+	// global level transaction atomicity.
+	SingleCapture bool `toml:"-" json:"-"`
+}
+
+// GetSpanRebalanceInterval returns the interval of rebalancing spans.
+func (c *ChangefeedSchedulerConfig) GetSpanRebalanceInterval() time.Duration {
+	if c == nil || c.SpanRebalanceInterval == 0 {
+		return DefaultSpanRebalanceInterval
+	}
+	return c.SpanRebalanceInterval
+}
+
+// ValidateAndAdjust verifies that each parameter is valid.
+func (c *ChangefeedSchedulerConfig) ValidateAndAdjust() error {
+	if err := ValidateCaptureLabels(c.CaptureLabels); err != nil {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(err.Error())
+	}
+	c.SpreadLabel = strings.TrimSpace(c.SpreadLabel)
+	if c.SplitWriteThreshold < 0 || c.MergeWriteThreshold < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"split-write-threshold and merge-write-threshold must not be negative")
+	}
+	if c.SpanRebalanceInterval < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"span-rebalance-interval must not be negative")
+	}
+	// Otherwise merged spans may be split again immediately.
+	if c.SplitWriteThreshold > 0 && c.MergeWriteThreshold >= c.SplitWriteThreshold {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"merge-write-threshold must be less than split-write-threshold")
+	}
+	return nil
+}
+
+// MatchCaptureLabels returns true if a capture with the labels satisfies
+// the placement constraints of the changefeed.
+func (c *ChangefeedSchedulerConfig) MatchCaptureLabels(labels map[string]string) bool {
+	if c == nil {
+		return true
+	}
+	for k, v := range c.CaptureLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// WithCaptureLabels returns a copy of the config, the labels are added to
+// CaptureLabels and override the ones with the same keys set by the
+// changefeed, so that a changefeed can not escape the labels of its namespace.
+func (c *ChangefeedSchedulerConfig) WithCaptureLabels(
+	labels map[string]string,
+) *ChangefeedSchedulerConfig {
+	res := &ChangefeedSchedulerConfig{}
+	if c != nil {
+		*res = *c
+	}
+	res.CaptureLabels = make(map[string]string, len(labels)+len(res.CaptureLabels))
+	if c != nil {
+		for k, v := range c.CaptureLabels {
+			res.CaptureLabels[k] = v
+		}
+	}
+	for k, v := range labels {
+		res.CaptureLabels[k] = v
+	}
+	return res
+}
+
+// ValidateCaptureLabels checks that keys and values of the labels are
+// not empty.
+func ValidateCaptureLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if strings.TrimSpace(k) == "" || strings.TrimSpace(v) == "" {
+			return errors.Errorf("capture label %q=%q can not be empty", k, v)
+		}
+	}
+	return nil
 }
 
 // SchedulerConfig configs TiCDC scheduler.
@@ -50,6 +160,16 @@ type SchedulerConfig struct {
 	// When there are only 2 captures, and a large number of tables, this can be helpful to prevent
 	// oom caused by all tables dispatched to only one capture.
 	AddTableBatchSize int `toml:"add-table-batch-size" json:"add-table-batch-size"`
+	// BalanceStrategy is the strategy of balancing tables among captures,
+	// it can be "table-count" or "throughput".
+	// The "throughput" strategy balances tables by their event rates and
+	// the memory usage of captures, it helps when a few tables produce most
+	// of the traffic.
+	BalanceStrategy string `toml:"balance-strategy" json:"balance-strategy"`
+	// BalanceThreshold is the tolerance ratio of the "throughput" strategy.
+	// Tables are moved only if the load of a capture exceeds the average
+	// load by the ratio, it prevents tables from being moved back and forth.
+	BalanceThreshold float64 `toml:"balance-threshold" json:"balance-threshold"`
 
 	// ChangefeedSettings is setting by changefeed.
 	ChangefeedSettings *ChangefeedSchedulerConfig `toml:"-" json:"-"`
@@ -66,6 +186,8 @@ func NewDefaultSchedulerConfig() *SchedulerConfig {
 		// TODO: no need to check balance each minute, relax the interval.
 		CheckBalanceInterval: TomlDuration(time.Minute),
 		AddTableBatchSize:    50,
+		BalanceStrategy:      BalanceStrategyTableCount,
+		BalanceThreshold:     0.2,
 	}
 }
 
@@ -91,6 +213,18 @@ func (c *SchedulerConfig) ValidateAndAdjust() error {
 		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
 			"add-table-batch-size must be large than 0")
 	}
+	switch c.BalanceStrategy {
+	case BalanceStrategyTableCount, BalanceStrategyThroughput:
+	case "":
+		c.BalanceStrategy = BalanceStrategyTableCount
+	default:
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"balance-strategy must be table-count or throughput")
+	}
+	if c.BalanceThreshold < 0 {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"balance-threshold can not be negative")
+	}
 
 	return nil
 }
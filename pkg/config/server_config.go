@@ -171,6 +171,9 @@ type ServerConfig struct {
 	Debug               *DebugConfig    `toml:"debug" json:"debug"`
 	ClusterID           string          `toml:"cluster-id" json:"cluster-id"`
 	MaxMemoryPercentage int             `toml:"max-memory-percentage" json:"max-memory-percentage"`
+	// Labels are user-defined labels of the capture, e.g. zone, rack and
+	// host class, they are used to place changefeeds and tables.
+	Labels map[string]string `toml:"labels" json:"labels,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ServerConfig
@@ -285,6 +288,9 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		log.Warn("server max-memory-percentage must be less than 100, set to default value")
 		c.MaxMemoryPercentage = DefaultMaxMemoryPercentage
 	}
+	if err = ValidateCaptureLabels(c.Labels); err != nil {
+		return cerror.WrapError(cerror.ErrInvalidServerOption, err)
+	}
 
 	return nil
 }
@@ -75,6 +75,10 @@ func TestServerConfigValidateAndAdjust(t *testing.T) {
 	conf.Debug.Messages.ServerWorkerPoolSize = 0
 	require.Nil(t, conf.ValidateAndAdjust())
 	require.EqualValues(t, GetDefaultServerConfig().Debug.Messages.ServerWorkerPoolSize, conf.Debug.Messages.ServerWorkerPoolSize)
+	conf.Labels = map[string]string{"": "a"}
+	require.Regexp(t, ".*can not be empty.*", conf.ValidateAndAdjust())
+	conf.Labels = map[string]string{"zone": "a"}
+	require.Nil(t, conf.ValidateAndAdjust())
 }
 
 func TestDBConfigValidateAndAdjust(t *testing.T) {
@@ -133,6 +137,17 @@ func TestSchedulerConfigValidateAndAdjust(t *testing.T) {
 	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
 	conf.AddTableBatchSize = 0
 	require.Error(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
+	conf.BalanceStrategy = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, BalanceStrategyTableCount, conf.BalanceStrategy)
+	conf.BalanceStrategy = "unknown"
+	require.Error(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
+	conf.BalanceThreshold = -1
+	require.Error(t, conf.ValidateAndAdjust())
 }
 
 func TestIsValidClusterID(t *testing.T) {
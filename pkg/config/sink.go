@@ -41,6 +41,13 @@ const (
 	noneTxnAtomicity AtomicityLevel = "none"
 	// tableTxnAtomicity means atomicity of single table transactions is guaranteed.
 	tableTxnAtomicity AtomicityLevel = "table"
+	// globalTxnAtomicity means atomicity of upstream transactions spanning
+	// multiple tables is guaranteed. Transactions are assembled by the sink of
+	// one processor, so all tables of the changefeed are replicated by one
+	// capture.
+	// Transactions are buffered until all tables resolve them, the buffer is
+	// bounded by the memory quota of the changefeed.
+	globalTxnAtomicity AtomicityLevel = "global"
 )
 
 const (
@@ -71,14 +78,28 @@ func (l AtomicityLevel) ShouldSplitTxn() bool {
 	return l == noneTxnAtomicity
 }
 
-func (l AtomicityLevel) validate(scheme string) error {
+// IsGlobal returns true if transactions spanning multiple tables should be
+// applied atomically.
+func (l AtomicityLevel) IsGlobal() bool {
+	return l == globalTxnAtomicity
+}
+
+func (l AtomicityLevel) validate(scheme string, enableTxnBoundary bool) error {
 	switch l {
 	case unknownTxnAtomicity:
 	case noneTxnAtomicity:
 		// Do nothing here to avoid modifying the persistence parameters.
 	case tableTxnAtomicity:
-		// MqSink only support `noneTxnAtomicity`.
-		if sink.IsMQScheme(scheme) {
+		// MqSink only support `noneTxnAtomicity`, unless transaction boundaries
+		// are enabled, which requires transactions not to be split.
+		if sink.IsMQScheme(scheme) && !enableTxnBoundary {
+			errMsg := fmt.Sprintf("%s level atomicity is not supported by %s scheme", l, scheme)
+			return cerror.ErrSinkURIInvalid.GenWithStackByArgs(errMsg)
+		}
+	case globalTxnAtomicity:
+		// Only the MySQL-compatible sinks can apply several tables in
+		// one downstream transaction.
+		if !sink.IsMySQLCompatibleScheme(scheme) {
 			errMsg := fmt.Sprintf("%s level atomicity is not supported by %s scheme", l, scheme)
 			return cerror.ErrSinkURIInvalid.GenWithStackByArgs(errMsg)
 		}
@@ -98,6 +119,14 @@ var ForceEnableOldValueProtocols = []string{
 
 // SinkConfig represents sink config for a changefeed
 type SinkConfig struct {
+	// TxnAtomicity is the atomicity level of upstream transactions.
+	// The global level is only guaranteed while all tables are replicated by
+	// one capture. The owner pins such changefeeds to one capture, but tables
+	// are moved to another capture one by one when the pinned capture is
+	// drained or fails, and transactions spanning tables on different
+	// captures may be split during the move. So it's recommended to run
+	// such changefeeds in a cluster with one capture. Spreading or splitting
+	// tables across captures is rejected.
 	TxnAtomicity AtomicityLevel `toml:"transaction-atomicity" json:"transaction-atomicity"`
 	Protocol     string         `toml:"protocol" json:"protocol"`
 
@@ -115,6 +144,13 @@ type SinkConfig struct {
 
 	OnlyOutputUpdatedColumns bool `toml:"only-output-updated-columns" json:"only-output-updated-columns"`
 
+	// EnableTxnBoundary enabled then the transaction id, and the index and the
+	// count of rows in the transaction in the same table are attached to each
+	// row by MQ sinks.
+	// It requires the `table` level transaction atomicity, and the default
+	// or table partition rule, which dispatches rows by table.
+	EnableTxnBoundary bool `toml:"enable-txn-boundary" json:"enable-txn-boundary"`
+
 	// TiDBSourceID is the source ID of the upstream TiDB,
 	// which is used to set the `tidb_cdc_write_source` session variable.
 	// Note: This field is only used internally and only used in the MySQL sink.
@@ -223,6 +259,17 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) er
 			rule.PartitionRule = rule.DispatcherRule
 			rule.DispatcherRule = ""
 		}
+		// Rows of a transaction must be sent to the same partition, so that
+		// consumers can collect them by the transaction boundary.
+		if s.EnableTxnBoundary {
+			switch strings.ToLower(rule.PartitionRule) {
+			case "ts", "index-value", "rowid":
+				return cerror.ErrSinkInvalidConfig.GenWithStack(
+					"enable-txn-boundary is not supported by partition rule %s, "+
+						"only default and table are supported, matcher: %v",
+					rule.PartitionRule, rule.Matcher)
+			}
+		}
 	}
 
 	if s.EncoderConcurrency < 0 {
@@ -299,8 +346,12 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 			"the configuration in sink URI will be used", zap.Error(err))
 	}
 
+	if err := s.validateAndAdjustTxnBoundary(sinkURI.Scheme); err != nil {
+		return err
+	}
+
 	// validate that TxnAtomicity is valid and compatible with the scheme.
-	if err := s.TxnAtomicity.validate(sinkURI.Scheme); err != nil {
+	if err := s.TxnAtomicity.validate(sinkURI.Scheme, s.EnableTxnBoundary); err != nil {
 		return err
 	}
 
@@ -322,6 +373,27 @@ func (s *SinkConfig) validateAndAdjustSinkURI(sinkURI *url.URL) error {
 	return nil
 }
 
+// validateAndAdjustTxnBoundary checks that transaction boundaries are only
+// enabled for MQ sinks, and uses the `table` level atomicity for them by default.
+func (s *SinkConfig) validateAndAdjustTxnBoundary(scheme string) error {
+	if !s.EnableTxnBoundary {
+		return nil
+	}
+	if !sink.IsMQScheme(scheme) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"enable-txn-boundary is not supported by %s scheme", scheme)
+	}
+	switch s.TxnAtomicity {
+	case unknownTxnAtomicity:
+		s.TxnAtomicity = tableTxnAtomicity
+	case tableTxnAtomicity:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"enable-txn-boundary requires table level atomicity, but got %s", s.TxnAtomicity)
+	}
+	return nil
+}
+
 // applyParameterBySinkURI parse sinkURI and set `Protocol` and `TxnAtomicity` to `SinkConfig`.
 // Return:
 // - ErrIncompatibleSinkConfig to terminate `updated` changefeed operation.
@@ -0,0 +1,80 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// defaultSnapshotChunkRows is the default number of rows of a snapshot chunk.
+	defaultSnapshotChunkRows = 10000
+	// defaultSnapshotConcurrency is the default number of spans exported
+	// at the same time by a capture.
+	defaultSnapshotConcurrency = 4
+	// defaultSnapshotMemoryQuota is the default memory quota of chunks being
+	// exported, 256 MiB.
+	defaultSnapshotMemoryQuota = 256 * 1024 * 1024
+)
+
+// SnapshotConfig represents the initial snapshot of a changefeed. If it is
+// enabled, all matched tables are exported at the start ts through the sink
+// before the incremental replication starts. Each span is exported by the
+// capture it is scheduled to, and the progress of a span is lost if its
+// capture goes away, the span is exported again from the beginning then.
+//
+// NOTICE: tables are not created in MySQL-compatible downstreams, they must
+// exist before the changefeed is created.
+type SnapshotConfig struct {
+	Enable bool `toml:"enable" json:"enable"`
+	// ChunkRows is the max number of rows written to the sink at a time,
+	// the progress is persisted after each chunk so the export is resumable.
+	ChunkRows int `toml:"chunk-rows" json:"chunk-rows"`
+	// Concurrency is the max number of spans exported at the same time by
+	// a capture.
+	Concurrency int `toml:"concurrency" json:"concurrency"`
+	// MemoryQuota is the max bytes of chunks being exported at the same
+	// time by a capture, it is shared by spans exported concurrently.
+	MemoryQuota uint64 `toml:"memory-quota" json:"memory-quota"`
+}
+
+// ValidateAndAdjust validates the snapshot config and adjusts it if necessary.
+func (c *SnapshotConfig) ValidateAndAdjust() error {
+	if c.ChunkRows < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("chunk-rows in snapshot must be positive, got %d", c.ChunkRows))
+	}
+	if c.ChunkRows == 0 {
+		c.ChunkRows = defaultSnapshotChunkRows
+	}
+	if c.Concurrency < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("concurrency in snapshot must be positive, got %d", c.Concurrency))
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = defaultSnapshotConcurrency
+	}
+	if c.MemoryQuota == 0 {
+		c.MemoryQuota = defaultSnapshotMemoryQuota
+	}
+	return nil
+}
+
+// IsEnabled returns true if the initial snapshot is enabled,
+// a nil config means it is disabled.
+func (c *SnapshotConfig) IsEnabled() bool {
+	return c != nil && c.Enable
+}
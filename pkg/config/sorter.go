@@ -21,6 +21,10 @@ type SorterConfig struct {
 	MaxMemoryPercentage int `toml:"max-memory-percentage" json:"max-memory-percentage"`
 	// the directory used to store the temporary files generated by the sorter
 	SortDir string `toml:"sort-dir" json:"sort-dir"`
+	// the maximum bytes of events of a changefeed stored in the sorter, pullers
+	// of tables are paused after it's exceeded until their sorted events are
+	// consumed. 0 means unlimited.
+	MaxChangefeedDiskUsage uint64 `toml:"max-changefeed-disk-usage" json:"max-changefeed-disk-usage"`
 
 	// the maximum memory consumption allowed for in-memory sorting
 	// Deprecated: we don't use this field anymore after introducing pull based sink.
@@ -42,6 +42,10 @@ var (
 		"scheduler request failed, %s",
 		errors.RFCCodeText("CDC:ErrSchedulerRequestFailed"),
 	)
+	ErrNoCaptureMatchesLabels = errors.Normalize(
+		"no capture matches the capture labels %v of the changefeed",
+		errors.RFCCodeText("CDC:ErrNoCaptureMatchesLabels"),
+	)
 	ErrGetAllStoresFailed = errors.Normalize(
 		"get stores from pd failed",
 		errors.RFCCodeText("CDC:ErrGetAllStoresFailed"),
@@ -203,6 +207,10 @@ var (
 		"kafka broker config item not found",
 		errors.RFCCodeText("CDC:ErrKafkaBrokerConfigNotFound"),
 	)
+	ErrMQConsumerInvalidEvent = errors.Normalize(
+		"invalid event consumed from MQ, %s",
+		errors.RFCCodeText("CDC:ErrMQConsumerInvalidEvent"),
+	)
 	ErrRedoConfigInvalid = errors.Normalize(
 		"redo log config invalid",
 		errors.RFCCodeText("CDC:ErrRedoConfigInvalid"),
@@ -227,6 +235,14 @@ var (
 		"initialize meta for redo log",
 		errors.RFCCodeText("CDC:ErrRedoMetaInitialize"),
 	)
+	ErrRedoApplyTargetTsInvalid = errors.Normalize(
+		"target ts %d is out of the range of redo logs [%d, %d]",
+		errors.RFCCodeText("CDC:ErrRedoApplyTargetTsInvalid"),
+	)
+	ErrRedoApplyStartTsInvalid = errors.Normalize(
+		"start ts %d is out of the range of retained redo logs [%d, %d]",
+		errors.RFCCodeText("CDC:ErrRedoApplyStartTsInvalid"),
+	)
 	ErrFileSizeExceed = errors.Normalize(
 		"rawData size %d exceeds maximum file size %d",
 		errors.RFCCodeText("CDC:ErrFileSizeExceed"),
@@ -264,6 +280,10 @@ var (
 		"MySQL query error",
 		errors.RFCCodeText("CDC:ErrMySQLQueryError"),
 	)
+	ErrSyncPointNotFound = errors.Normalize(
+		"syncpoint not found in the downstream, changefeed: %s, primaryTs: %d",
+		errors.RFCCodeText("CDC:ErrSyncPointNotFound"),
+	)
 	ErrMySQLConnectionError = errors.Normalize(
 		"MySQL connection error",
 		errors.RFCCodeText("CDC:ErrMySQLConnectionError"),
@@ -447,6 +467,18 @@ var (
 			`eg, "simple-namespace-test"`),
 		errors.RFCCodeText("CDC:ErrInvalidNamespace"),
 	)
+	ErrInvalidNamespaceInfo = errors.Normalize(
+		"invalid namespace info: %s",
+		errors.RFCCodeText("CDC:ErrInvalidNamespaceInfo"),
+	)
+	ErrNamespaceNotExists = errors.Normalize(
+		"namespace %s does not exist",
+		errors.RFCCodeText("CDC:ErrNamespaceNotExists"),
+	)
+	ErrNamespaceQuotaExceeded = errors.Normalize(
+		"changefeed %s exceeds the quota of namespace %s, %s",
+		errors.RFCCodeText("CDC:ErrNamespaceQuotaExceeded"),
+	)
 	ErrInvalidEtcdKey = errors.Normalize(
 		"invalid key: %s",
 		errors.RFCCodeText("CDC:ErrInvalidEtcdKey"),
@@ -564,6 +596,22 @@ var (
 		"changefeed update error: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateRefused"),
 	)
+	ErrVerificationNotFound = errors.Normalize(
+		"there is no verification of changefeed %s",
+		errors.RFCCodeText("CDC:ErrVerificationNotFound"),
+	)
+	ErrVerificationIsRunning = errors.Normalize(
+		"a verification of changefeed %s is already running",
+		errors.RFCCodeText("CDC:ErrVerificationIsRunning"),
+	)
+	ErrHeldDDLNotFound = errors.Normalize(
+		"there is no held ddl in changefeed %s",
+		errors.RFCCodeText("CDC:ErrHeldDDLNotFound"),
+	)
+	ErrBackfillInProgress = errors.Normalize(
+		"table %d of changefeed %s is being backfilled",
+		errors.RFCCodeText("CDC:ErrBackfillInProgress"),
+	)
 	ErrChangefeedUpdateFailedTransaction = errors.Normalize(
 		"changefeed update failed due to unexpected etcd transaction failure: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateFailed"),
@@ -791,6 +839,14 @@ var (
 		"upstream has running import tasks, upstream-id: %d",
 		errors.RFCCodeText("CDC:ErrUpstreamHasRunningImport"),
 	)
+	ErrUpstreamInUse = errors.Normalize(
+		"upstream is used by changefeeds, upstream-id: %d, changefeeds: %v",
+		errors.RFCCodeText("CDC:ErrUpstreamInUse"),
+	)
+	ErrUpstreamIsDefault = errors.Normalize(
+		"the default upstream can not be removed, upstream-id: %d",
+		errors.RFCCodeText("CDC:ErrUpstreamIsDefault"),
+	)
 
 	// ReplicationSet error
 	ErrReplicationSetInconsistent = errors.Normalize(
@@ -77,6 +77,7 @@ var changefeedUnRetryableErrors = []*errors.Error{
 	ErrSyncRenameTableFailed,
 	ErrChangefeedUnretryable,
 	ErrCorruptedDataMutation,
+	ErrNamespaceQuotaExceeded,
 }
 
 // IsChangefeedUnRetryableError returns true if an error is a changefeed not retry error.
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/pkg/utils/tempurl"
 	"go.etcd.io/etcd/api/v3/mvccpb"
@@ -39,6 +41,15 @@ import (
 // DefaultCDCClusterID is the default value of cdc cluster id
 const DefaultCDCClusterID = "default"
 
+const (
+	// Ref: https://etcd.io/docs/v3.3/op-guide/configuration/#--max-txn-ops
+	etcdTxnMaxOps = 128
+
+	deleteUpstreamBackoffBaseDelayInMs = 100
+	deleteUpstreamBackoffMaxDelayInMs  = 2 * 1000
+	deleteUpstreamMaxTries             = 8
+)
+
 // CaptureOwnerKey is the capture owner path that is saved to etcd
 func CaptureOwnerKey(clusterID string) string {
 	return BaseKey(clusterID) + metaPrefix + "/owner"
@@ -59,6 +70,12 @@ func ChangefeedStatusKeyPrefix(clusterID, namespace string) string {
 	return NamespacedPrefix(clusterID, namespace) + ChangefeedStatusKey
 }
 
+// GetEtcdKeyChangefeedHistory returns the key of a changefeed history
+func GetEtcdKeyChangefeedHistory(clusterID string, changefeedID model.ChangeFeedID) string {
+	return NamespacedPrefix(clusterID, changefeedID.Namespace) +
+		ChangefeedHistoryKey + "/" + changefeedID.ID
+}
+
 // GetEtcdKeyChangeFeedList returns the prefix key of all changefeed config
 func GetEtcdKeyChangeFeedList(clusterID, namespace string) string {
 	return fmt.Sprintf("%s/changefeed/info", NamespacedPrefix(clusterID, namespace))
@@ -123,11 +140,33 @@ type CDCEtcdClient interface {
 		id model.ChangeFeedID,
 	) (*model.ChangeFeedStatus, int64, error)
 
+	GetChangefeedHistory(ctx context.Context,
+		id model.ChangeFeedID,
+	) (*model.ChangefeedHistory, error)
+
 	GetUpstreamInfo(ctx context.Context,
 		upstreamID model.UpstreamID,
 		namespace string,
 	) (*model.UpstreamInfo, error)
 
+	GetUpstreamInfos(ctx context.Context) (
+		map[model.UpstreamID]*model.UpstreamInfo, error,
+	)
+
+	DeleteUpstreamInfo(ctx context.Context,
+		upstreamID model.UpstreamID,
+	) error
+
+	GetNamespaceInfo(ctx context.Context,
+		namespace string,
+	) (*model.NamespaceInfo, error)
+
+	GetNamespaceInfos(ctx context.Context) (map[string]*model.NamespaceInfo, error)
+
+	PutNamespaceInfo(ctx context.Context, info *model.NamespaceInfo) error
+
+	DeleteNamespaceInfo(ctx context.Context, namespace string) error
+
 	GetGCServiceID() string
 
 	GetEnsureGCServiceID(tag string) string
@@ -337,6 +376,24 @@ func (c *CDCEtcdClientImpl) GetChangeFeedStatus(ctx context.Context,
 	return info, resp.Kvs[0].ModRevision, errors.Trace(err)
 }
 
+// GetChangefeedHistory queries the history of a changefeed, an empty
+// history is returned if no event has been recorded.
+func (c *CDCEtcdClientImpl) GetChangefeedHistory(ctx context.Context,
+	id model.ChangeFeedID,
+) (*model.ChangefeedHistory, error) {
+	key := GetEtcdKeyChangefeedHistory(c.ClusterID, id)
+	resp, err := c.Client.Get(ctx, key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	history := &model.ChangefeedHistory{}
+	if resp.Count == 0 {
+		return history, nil
+	}
+	err = history.Unmarshal(resp.Kvs[0].Value)
+	return history, errors.Trace(err)
+}
+
 // GetCaptures returns kv revision and CaptureInfo list
 func (c *CDCEtcdClientImpl) GetCaptures(ctx context.Context) (int64, []*model.CaptureInfo, error) {
 	key := CaptureInfoKeyPrefix(c.ClusterID)
@@ -634,6 +691,242 @@ func (c *CDCEtcdClientImpl) GetUpstreamInfo(ctx context.Context,
 	return info, errors.Trace(err)
 }
 
+// GetUpstreamInfos queries upstream infos of all namespaces, an upstream
+// used in several namespaces is returned once.
+func (c *CDCEtcdClientImpl) GetUpstreamInfos(ctx context.Context) (
+	map[model.UpstreamID]*model.UpstreamInfo, error,
+) {
+	namespaces, _, err := c.getNamespaces(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos := make(map[model.UpstreamID]*model.UpstreamInfo)
+	for _, namespace := range namespaces {
+		prefix := NamespacedPrefix(c.ClusterID, namespace) + upstreamKey + "/"
+		resp, err := c.Client.Get(ctx, prefix, clientv3.WithPrefix())
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		for _, kv := range resp.Kvs {
+			k := new(CDCKey)
+			if err := k.Parse(c.ClusterID, string(kv.Key)); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, ok := infos[k.UpstreamID]; ok {
+				continue
+			}
+			info := &model.UpstreamInfo{}
+			if err := info.Unmarshal(kv.Value); err != nil {
+				return nil, errors.Trace(err)
+			}
+			infos[k.UpstreamID] = info
+		}
+	}
+	return infos, nil
+}
+
+// getNamespaces returns all namespaces having keys in etcd, and the revision
+// they are read at. Only the first key of each namespace is read, the keys
+// of the namespace are skipped by seeking past its prefix, so the cost does
+// not grow with the number of changefeeds and captures.
+func (c *CDCEtcdClientImpl) getNamespaces(ctx context.Context) ([]string, int64, error) {
+	prefix := BaseKey(c.ClusterID) + "/"
+	end := clientv3.GetPrefixRangeEnd(prefix)
+	var namespaces []string
+	var rev int64
+	for key := prefix; ; {
+		opts := []clientv3.OpOption{
+			clientv3.WithRange(end), clientv3.WithLimit(1), clientv3.WithKeysOnly(),
+		}
+		if rev != 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := c.Client.Get(ctx, key, opts...)
+		if err != nil {
+			return nil, 0, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+		if len(resp.Kvs) == 0 {
+			return namespaces, rev, nil
+		}
+		namespace, _, _ := strings.Cut(strings.TrimPrefix(string(resp.Kvs[0].Key), prefix), "/")
+		if "/"+namespace != metaPrefix {
+			namespaces = append(namespaces, namespace)
+		}
+		// "0" is the byte next to "/", all keys of the namespace are skipped.
+		key = prefix + namespace + "0"
+	}
+}
+
+// DeleteUpstreamInfo deletes an upstream info from all namespaces in etcd if
+// it is not used by any changefeed. The GC safepoint and the upstream client
+// are shared by all namespaces, so changefeeds of all namespaces are checked.
+// The check and the deletion are done in etcd transactions, so that no
+// changefeed is created with the upstream between them. The deletion is
+// retried with backoff if changefeeds are changed concurrently.
+func (c *CDCEtcdClientImpl) DeleteUpstreamInfo(ctx context.Context,
+	upstreamID model.UpstreamID,
+) error {
+	return retry.Do(ctx, func() error {
+		return c.deleteUpstreamInfo(ctx, upstreamID)
+	}, retry.WithBackoffBaseDelay(deleteUpstreamBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(deleteUpstreamBackoffMaxDelayInMs),
+		retry.WithMaxTries(deleteUpstreamMaxTries),
+		retry.WithIsRetryableErr(cerror.ErrEtcdTryAgain.Equal))
+}
+
+func (c *CDCEtcdClientImpl) deleteUpstreamInfo(ctx context.Context,
+	upstreamID model.UpstreamID,
+) error {
+	namespaces, rev, err := c.getNamespaces(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var cmps []clientv3.Cmp
+	var opsThen []clientv3.Op
+	var changefeeds []string
+	for _, namespace := range namespaces {
+		prefix := GetEtcdKeyChangeFeedList(c.ClusterID, namespace)
+		resp, err := c.Client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithRev(rev))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		for _, kv := range resp.Kvs {
+			id, err := extractKeySuffix(string(kv.Key))
+			if err != nil {
+				return err
+			}
+			info := &model.ChangeFeedInfo{}
+			if err := info.Unmarshal(kv.Value); err != nil {
+				return errors.Trace(err)
+			}
+			if info.UpstreamID == upstreamID {
+				changefeeds = append(changefeeds, namespace+"/"+id)
+			}
+		}
+
+		key := CDCKey{
+			Tp:         CDCKeyTypeUpStream,
+			ClusterID:  c.ClusterID,
+			UpstreamID: upstreamID,
+			Namespace:  namespace,
+		}
+		resp, err = c.Client.Get(ctx, key.String(), clientv3.WithRev(rev))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if len(resp.Kvs) == 0 {
+			// A changefeed created with the upstream in this namespace puts
+			// the upstream info back, so there is nothing to guard here.
+			continue
+		}
+		// No changefeed of the namespace is created or updated after they
+		// are read, and the upstream info is not changed by a new changefeed.
+		cmps = append(cmps,
+			clientv3.Compare(clientv3.ModRevision(prefix), "<", rev+1).WithPrefix(),
+			clientv3.Compare(clientv3.ModRevision(key.String()),
+				"=", resp.Kvs[0].ModRevision))
+		opsThen = append(opsThen, clientv3.OpDelete(key.String()))
+	}
+	if len(changefeeds) > 0 {
+		sort.Strings(changefeeds)
+		return cerror.ErrUpstreamInUse.GenWithStackByArgs(upstreamID, changefeeds)
+	}
+
+	// Each namespace takes two comparisons and one operation, split them
+	// into transactions that do not exceed the etcd max-txn-ops limit.
+	for len(opsThen) > 0 {
+		n := len(opsThen)
+		if n > etcdTxnMaxOps/2 {
+			n = etcdTxnMaxOps / 2
+		}
+		txnResp, err := c.Client.Txn(ctx, cmps[:2*n], opsThen[:n], TxnEmptyOpsElse)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+		}
+		if !txnResp.Succeeded {
+			log.Info("changefeeds are changed while deleting the upstream, retry",
+				zap.Uint64("upstreamID", upstreamID))
+			return cerror.ErrEtcdTryAgain.GenWithStackByArgs()
+		}
+		cmps, opsThen = cmps[2*n:], opsThen[n:]
+	}
+	return nil
+}
+
+// GetNamespaceInfo gets a namespace info from etcd
+func (c *CDCEtcdClientImpl) GetNamespaceInfo(ctx context.Context,
+	namespace string,
+) (*model.NamespaceInfo, error) {
+	key := CDCKey{
+		Tp:        CDCKeyTypeNamespace,
+		ClusterID: c.ClusterID,
+		Namespace: namespace,
+	}
+	resp, err := c.Client.Get(ctx, key.String())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Count == 0 {
+		return nil, cerror.ErrNamespaceNotExists.GenWithStackByArgs(namespace)
+	}
+	info := &model.NamespaceInfo{}
+	err = info.Unmarshal(resp.Kvs[0].Value)
+	return info, errors.Trace(err)
+}
+
+// GetNamespaceInfos queries all namespace infos of the cluster
+func (c *CDCEtcdClientImpl) GetNamespaceInfos(ctx context.Context) (
+	map[string]*model.NamespaceInfo, error,
+) {
+	prefix := BaseKey(c.ClusterID) + metaPrefix + namespaceKey + "/"
+	resp, err := c.Client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	infos := make(map[string]*model.NamespaceInfo, resp.Count)
+	for _, kv := range resp.Kvs {
+		info := &model.NamespaceInfo{}
+		if err := info.Unmarshal(kv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		infos[info.Name] = info
+	}
+	return infos, nil
+}
+
+// PutNamespaceInfo creates or updates a namespace info in etcd
+func (c *CDCEtcdClientImpl) PutNamespaceInfo(ctx context.Context,
+	info *model.NamespaceInfo,
+) error {
+	key := CDCKey{
+		Tp:        CDCKeyTypeNamespace,
+		ClusterID: c.ClusterID,
+		Namespace: info.Name,
+	}
+	value, err := info.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = c.Client.Put(ctx, key.String(), string(value))
+	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+}
+
+// DeleteNamespaceInfo deletes a namespace info from etcd
+func (c *CDCEtcdClientImpl) DeleteNamespaceInfo(ctx context.Context,
+	namespace string,
+) error {
+	key := CDCKey{
+		Tp:        CDCKeyTypeNamespace,
+		ClusterID: c.ClusterID,
+		Namespace: namespace,
+	}
+	_, err := c.Client.Delete(ctx, key.String())
+	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+}
+
 // GcServiceIDForTest returns the gc service ID for tests
 func GcServiceIDForTest() string {
 	return fmt.Sprintf("ticdc-%s-%d", "default", 0)
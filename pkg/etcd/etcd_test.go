@@ -128,6 +128,33 @@ func TestOpChangeFeedDetail(t *testing.T) {
 	require.True(t, cerror.ErrChangeFeedNotExists.Equal(err))
 }
 
+func TestGetChangefeedHistory(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+	ctx := context.Background()
+	cfID := model.DefaultChangeFeedID("test-history")
+
+	history, err := s.client.GetChangefeedHistory(ctx, cfID)
+	require.NoError(t, err)
+	require.Empty(t, history.Events)
+
+	history.Append(&model.ChangefeedEvent{
+		Type:    model.ChangefeedEventAdminJob,
+		Message: model.AdminStop.String(),
+	})
+	value, err := history.Marshal()
+	require.NoError(t, err)
+	_, err = s.client.Client.Put(ctx,
+		GetEtcdKeyChangefeedHistory(s.client.ClusterID, cfID), value)
+	require.NoError(t, err)
+
+	history, err = s.client.GetChangefeedHistory(ctx, cfID)
+	require.NoError(t, err)
+	require.Len(t, history.Events, 1)
+	require.Equal(t, model.ChangefeedEventAdminJob, history.Events[0].Type)
+}
+
 func TestGetAllChangeFeedInfo(t *testing.T) {
 	s := &Tester{}
 	s.SetUpTest(t)
@@ -254,6 +281,180 @@ func TestUpdateChangefeedAndUpstream(t *testing.T) {
 	require.Equal(t, changeFeedInfo.SinkURI, changefeedResult.SinkURI)
 }
 
+func TestGetAndDeleteUpstreamInfos(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	for i := 1; i <= 2; i++ {
+		upstreamInfo := &model.UpstreamInfo{
+			ID:          uint64(i),
+			PDEndpoints: fmt.Sprintf("http://127.0.0.%d:2379", i),
+		}
+		changeFeedID := model.DefaultChangeFeedID(fmt.Sprintf("test-upstream-%d", i))
+		changeFeedInfo := &model.ChangeFeedInfo{
+			ID:         changeFeedID.ID,
+			Namespace:  changeFeedID.Namespace,
+			SinkURI:    "blackhole://",
+			UpstreamID: uint64(i),
+		}
+		err := s.client.CreateChangefeedInfo(ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+		require.NoError(t, err)
+	}
+
+	// The upstream 1 is used by a changefeed of another namespace as well.
+	otherID := model.ChangeFeedID{Namespace: "team-a", ID: "test-upstream-3"}
+	err := s.client.CreateChangefeedInfo(ctx,
+		&model.UpstreamInfo{ID: 1, PDEndpoints: "http://127.0.0.1:2379"},
+		&model.ChangeFeedInfo{
+			ID:         otherID.ID,
+			Namespace:  otherID.Namespace,
+			SinkURI:    "blackhole://",
+			UpstreamID: 1,
+		}, otherID)
+	require.NoError(t, err)
+
+	infos, err := s.client.GetUpstreamInfos(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, "http://127.0.0.2:2379", infos[2].PDEndpoints)
+
+	// an upstream used by a changefeed can not be deleted
+	err = s.client.DeleteUpstreamInfo(ctx, 1)
+	require.True(t, cerror.ErrUpstreamInUse.Equal(err))
+	require.Contains(t, err.Error(), "default/test-upstream-1")
+	require.Contains(t, err.Error(), "team-a/test-upstream-3")
+
+	// changefeeds of all namespaces are checked
+	err = s.client.DeleteChangeFeedInfo(ctx, model.DefaultChangeFeedID("test-upstream-1"))
+	require.NoError(t, err)
+	err = s.client.DeleteUpstreamInfo(ctx, 1)
+	require.True(t, cerror.ErrUpstreamInUse.Equal(err))
+	require.NotContains(t, err.Error(), "default/test-upstream-1")
+
+	err = s.client.DeleteChangeFeedInfo(ctx, otherID)
+	require.NoError(t, err)
+	err = s.client.DeleteUpstreamInfo(ctx, 1)
+	require.NoError(t, err)
+	infos, err = s.client.GetUpstreamInfos(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	for _, namespace := range []string{model.DefaultNamespace, otherID.Namespace} {
+		_, err = s.client.GetUpstreamInfo(ctx, 1, namespace)
+		require.True(t, cerror.ErrUpstreamNotFound.Equal(err))
+	}
+
+	// the upstream is deleted from more namespaces than one txn can hold
+	var namespaces []string
+	for i := 0; i < etcdTxnMaxOps; i++ {
+		id := model.ChangeFeedID{Namespace: fmt.Sprintf("ns-%d", i), ID: "test"}
+		err = s.client.CreateChangefeedInfo(ctx,
+			&model.UpstreamInfo{ID: 1, PDEndpoints: "http://127.0.0.1:2379"},
+			&model.ChangeFeedInfo{
+				ID:         id.ID,
+				Namespace:  id.Namespace,
+				SinkURI:    "blackhole://",
+				UpstreamID: 1,
+			}, id)
+		require.NoError(t, err)
+		err = s.client.DeleteChangeFeedInfo(ctx, id)
+		require.NoError(t, err)
+		namespaces = append(namespaces, id.Namespace)
+	}
+	err = s.client.DeleteUpstreamInfo(ctx, 1)
+	require.NoError(t, err)
+	for _, namespace := range namespaces {
+		_, err = s.client.GetUpstreamInfo(ctx, 1, namespace)
+		require.True(t, cerror.ErrUpstreamNotFound.Equal(err))
+	}
+
+	// the deletion respects the context
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = s.client.DeleteUpstreamInfo(cctx, 2)
+	require.Error(t, err)
+	_, err = s.client.GetUpstreamInfo(ctx, 2, model.DefaultNamespace)
+	require.NoError(t, err)
+}
+
+func TestGetNamespaces(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	// namespaces sorted around the skipped "/" are all found
+	for _, namespace := range []string{"ns", "ns-1", "ns0", "ns.a", model.DefaultNamespace} {
+		for i := 0; i < 3; i++ {
+			id := model.ChangeFeedID{Namespace: namespace, ID: fmt.Sprintf("test-%d", i)}
+			err := s.client.CreateChangefeedInfo(ctx,
+				&model.UpstreamInfo{ID: 1, PDEndpoints: "http://127.0.0.1:2379"},
+				&model.ChangeFeedInfo{
+					ID:         id.ID,
+					Namespace:  id.Namespace,
+					SinkURI:    "blackhole://",
+					UpstreamID: 1,
+				}, id)
+			require.NoError(t, err)
+		}
+	}
+	err := s.client.PutNamespaceInfo(ctx, &model.NamespaceInfo{Name: "team-a"})
+	require.NoError(t, err)
+
+	namespaces, rev, err := s.client.getNamespaces(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{model.DefaultNamespace, "ns-1", "ns.a", "ns", "ns0"}, namespaces)
+
+	// a namespace created later is found at a newer revision
+	id := model.ChangeFeedID{Namespace: "ns1", ID: "test"}
+	err = s.client.CreateChangefeedInfo(ctx,
+		&model.UpstreamInfo{ID: 1, PDEndpoints: "http://127.0.0.1:2379"},
+		&model.ChangeFeedInfo{ID: id.ID, Namespace: id.Namespace, UpstreamID: 1}, id)
+	require.NoError(t, err)
+	namespaces, rev2, err := s.client.getNamespaces(ctx)
+	require.NoError(t, err)
+	require.Greater(t, rev2, rev)
+	require.Equal(t, []string{model.DefaultNamespace, "ns-1", "ns.a", "ns", "ns0", "ns1"}, namespaces)
+}
+
+func TestNamespaceInfos(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	_, err := s.client.GetNamespaceInfo(ctx, "team-a")
+	require.True(t, cerror.ErrNamespaceNotExists.Equal(err))
+
+	for _, name := range []string{"team-a", "team-b"} {
+		err := s.client.PutNamespaceInfo(ctx, &model.NamespaceInfo{
+			Name:           name,
+			MaxChangefeeds: 2,
+		})
+		require.NoError(t, err)
+	}
+	err = s.client.PutNamespaceInfo(ctx, &model.NamespaceInfo{
+		Name:           "team-a",
+		MaxChangefeeds: 3,
+	})
+	require.NoError(t, err)
+	info, err := s.client.GetNamespaceInfo(ctx, "team-a")
+	require.NoError(t, err)
+	require.Equal(t, 3, info.MaxChangefeeds)
+
+	infos, err := s.client.GetNamespaceInfos(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, 2, infos["team-b"].MaxChangefeeds)
+
+	err = s.client.DeleteNamespaceInfo(ctx, "team-b")
+	require.NoError(t, err)
+	infos, err = s.client.GetNamespaceInfos(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+}
+
 func TestGetAllCaptureLeases(t *testing.T) {
 	s := &Tester{}
 	s.SetUpTest(t)
@@ -34,9 +34,12 @@ const (
 	ChangefeedInfoKey = "/changefeed/info"
 	// ChangefeedStatusKey is the key path for changefeed status
 	ChangefeedStatusKey = "/changefeed/status"
+	// ChangefeedHistoryKey is the key path for changefeed history
+	ChangefeedHistoryKey = "/changefeed/history"
 	// metaVersionKey is the key path for metadata version
 	metaVersionKey = "/meta/meta-version"
 	upstreamKey    = "/upstream"
+	namespaceKey   = "/namespace"
 
 	// DeletionCounterKey is the key path for the counter of deleted keys
 	DeletionCounterKey = metaPrefix + "/meta/ticdc-delete-etcd-key-count"
@@ -63,6 +66,8 @@ const (
 	CDCKeyTypeTaskPosition
 	CDCKeyTypeMetaVersion
 	CDCKeyTypeUpStream
+	CDCKeyTypeChangefeedHistory
+	CDCKeyTypeNamespace
 )
 
 // CDCKey represents an etcd key which is defined by TiCDC
@@ -135,6 +140,9 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 			k.OwnerLeaseID = ""
 		case strings.HasPrefix(key, metaVersionKey):
 			k.Tp = CDCKeyTypeMetaVersion
+		case strings.HasPrefix(key, namespaceKey+"/"):
+			k.Tp = CDCKeyTypeNamespace
+			k.Namespace = key[len(namespaceKey)+1:]
 		default:
 			return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
 		}
@@ -167,6 +175,14 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 				ID:        key[len(ChangefeedStatusKey)+1:],
 			}
 			k.OwnerLeaseID = ""
+		case strings.HasPrefix(key, ChangefeedHistoryKey):
+			k.Tp = CDCKeyTypeChangefeedHistory
+			k.CaptureID = ""
+			k.ChangefeedID = model.ChangeFeedID{
+				Namespace: namespace,
+				ID:        key[len(ChangefeedHistoryKey)+1:],
+			}
+			k.OwnerLeaseID = ""
 		case strings.HasPrefix(key, taskPositionKey):
 			splitKey := strings.SplitN(key[len(taskPositionKey)+1:], "/", 2)
 			if len(splitKey) != 2 {
@@ -201,11 +217,16 @@ func (k *CDCKey) String() string {
 	case CDCKeyTypeChangeFeedStatus:
 		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedStatusKey +
 			"/" + k.ChangefeedID.ID
+	case CDCKeyTypeChangefeedHistory:
+		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedHistoryKey +
+			"/" + k.ChangefeedID.ID
 	case CDCKeyTypeTaskPosition:
 		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + taskPositionKey +
 			"/" + k.CaptureID + "/" + k.ChangefeedID.ID
 	case CDCKeyTypeMetaVersion:
 		return BaseKey(k.ClusterID) + metaPrefix + metaVersionKey
+	case CDCKeyTypeNamespace:
+		return BaseKey(k.ClusterID) + metaPrefix + namespaceKey + "/" + k.Namespace
 	case CDCKeyTypeUpStream:
 		return fmt.Sprintf("%s%s/%d",
 			NamespacedPrefix(k.ClusterID, k.Namespace),
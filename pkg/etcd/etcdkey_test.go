@@ -74,6 +74,15 @@ func TestEtcdKey(t *testing.T) {
 			ClusterID:    DefaultCDCClusterID,
 			Namespace:    model.DefaultNamespace,
 		},
+	}, {
+		key: DefaultClusterAndNamespacePrefix +
+			"/changefeed/history/test-changefeed",
+		expected: &CDCKey{
+			Tp:           CDCKeyTypeChangefeedHistory,
+			ChangefeedID: model.DefaultChangeFeedID("test-changefeed"),
+			ClusterID:    DefaultCDCClusterID,
+			Namespace:    model.DefaultNamespace,
+		},
 	}, {
 		key: "/tidb/cdc/default/name/task" +
 			"/position/6bbc01c8-0605-4f86-a0f9-b3119109b225/test-changefeed",
@@ -111,6 +120,13 @@ func TestEtcdKey(t *testing.T) {
 			Tp:        CDCKeyTypeMetaVersion,
 			ClusterID: DefaultCDCClusterID,
 		},
+	}, {
+		key: fmt.Sprintf("%s%s/team-a", DefaultClusterAndMetaPrefix, namespaceKey),
+		expected: &CDCKey{
+			Tp:        CDCKeyTypeNamespace,
+			ClusterID: DefaultCDCClusterID,
+			Namespace: "team-a",
+		},
 	}}
 	for _, tc := range testcases {
 		k := new(CDCKey)
@@ -160,7 +176,7 @@ func TestEtcdKeyParseError(t *testing.T) {
 		}
 	}
 	k := new(CDCKey)
-	k.Tp = CDCKeyTypeUpStream + 1
+	k.Tp = CDCKeyTypeNamespace + 1
 	require.Panics(t, func() {
 		_ = k.String()
 	})
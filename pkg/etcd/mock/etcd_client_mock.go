@@ -80,6 +80,34 @@ func (mr *MockCDCEtcdClientMockRecorder) DeleteCaptureInfo(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCaptureInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).DeleteCaptureInfo), arg0, arg1)
 }
 
+// DeleteNamespaceInfo mocks base method.
+func (m *MockCDCEtcdClient) DeleteNamespaceInfo(ctx context.Context, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespaceInfo", ctx, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNamespaceInfo indicates an expected call of DeleteNamespaceInfo.
+func (mr *MockCDCEtcdClientMockRecorder) DeleteNamespaceInfo(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespaceInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).DeleteNamespaceInfo), ctx, namespace)
+}
+
+// DeleteUpstreamInfo mocks base method.
+func (m *MockCDCEtcdClient) DeleteUpstreamInfo(ctx context.Context, upstreamID model.UpstreamID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUpstreamInfo", ctx, upstreamID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUpstreamInfo indicates an expected call of DeleteUpstreamInfo.
+func (mr *MockCDCEtcdClientMockRecorder) DeleteUpstreamInfo(ctx, upstreamID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUpstreamInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).DeleteUpstreamInfo), ctx, upstreamID)
+}
+
 // GetAllCDCInfo mocks base method.
 func (m *MockCDCEtcdClient) GetAllCDCInfo(ctx context.Context) ([]*mvccpb.KeyValue, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +169,21 @@ func (mr *MockCDCEtcdClientMockRecorder) GetChangeFeedInfo(ctx, id interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetChangeFeedInfo), ctx, id)
 }
 
+// GetChangefeedHistory mocks base method.
+func (m *MockCDCEtcdClient) GetChangefeedHistory(ctx context.Context, id model.ChangeFeedID) (*model.ChangefeedHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangefeedHistory", ctx, id)
+	ret0, _ := ret[0].(*model.ChangefeedHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangefeedHistory indicates an expected call of GetChangefeedHistory.
+func (mr *MockCDCEtcdClientMockRecorder) GetChangefeedHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangefeedHistory", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetChangefeedHistory), ctx, id)
+}
+
 // GetChangeFeedStatus mocks base method.
 func (m *MockCDCEtcdClient) GetChangeFeedStatus(ctx context.Context, id model.ChangeFeedID) (*model.ChangeFeedStatus, int64, error) {
 	m.ctrl.T.Helper()
@@ -213,6 +256,36 @@ func (mr *MockCDCEtcdClientMockRecorder) GetGCServiceID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGCServiceID", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetGCServiceID))
 }
 
+// GetNamespaceInfo mocks base method.
+func (m *MockCDCEtcdClient) GetNamespaceInfo(ctx context.Context, namespace string) (*model.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceInfo", ctx, namespace)
+	ret0, _ := ret[0].(*model.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceInfo indicates an expected call of GetNamespaceInfo.
+func (mr *MockCDCEtcdClientMockRecorder) GetNamespaceInfo(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetNamespaceInfo), ctx, namespace)
+}
+
+// GetNamespaceInfos mocks base method.
+func (m *MockCDCEtcdClient) GetNamespaceInfos(ctx context.Context) (map[string]*model.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceInfos", ctx)
+	ret0, _ := ret[0].(map[string]*model.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceInfos indicates an expected call of GetNamespaceInfos.
+func (mr *MockCDCEtcdClientMockRecorder) GetNamespaceInfos(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceInfos", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetNamespaceInfos), ctx)
+}
+
 // GetOwnerID mocks base method.
 func (m *MockCDCEtcdClient) GetOwnerID(arg0 context.Context) (model.CaptureID, error) {
 	m.ctrl.T.Helper()
@@ -258,6 +331,21 @@ func (mr *MockCDCEtcdClientMockRecorder) GetUpstreamInfo(ctx, upstreamID, namesp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetUpstreamInfo), ctx, upstreamID, namespace)
 }
 
+// GetUpstreamInfos mocks base method.
+func (m *MockCDCEtcdClient) GetUpstreamInfos(ctx context.Context) (map[model.UpstreamID]*model.UpstreamInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpstreamInfos", ctx)
+	ret0, _ := ret[0].(map[model.UpstreamID]*model.UpstreamInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpstreamInfos indicates an expected call of GetUpstreamInfos.
+func (mr *MockCDCEtcdClientMockRecorder) GetUpstreamInfos(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpstreamInfos", reflect.TypeOf((*MockCDCEtcdClient)(nil).GetUpstreamInfos), ctx)
+}
+
 // PutCaptureInfo mocks base method.
 func (m *MockCDCEtcdClient) PutCaptureInfo(arg0 context.Context, arg1 *model.CaptureInfo, arg2 clientv3.LeaseID) error {
 	m.ctrl.T.Helper()
@@ -272,6 +360,20 @@ func (mr *MockCDCEtcdClientMockRecorder) PutCaptureInfo(arg0, arg1, arg2 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutCaptureInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).PutCaptureInfo), arg0, arg1, arg2)
 }
 
+// PutNamespaceInfo mocks base method.
+func (m *MockCDCEtcdClient) PutNamespaceInfo(ctx context.Context, info *model.NamespaceInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutNamespaceInfo", ctx, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutNamespaceInfo indicates an expected call of PutNamespaceInfo.
+func (mr *MockCDCEtcdClientMockRecorder) PutNamespaceInfo(ctx, info interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutNamespaceInfo", reflect.TypeOf((*MockCDCEtcdClient)(nil).PutNamespaceInfo), ctx, info)
+}
+
 // SaveChangeFeedInfo mocks base method.
 func (m *MockCDCEtcdClient) SaveChangeFeedInfo(ctx context.Context, info *model.ChangeFeedInfo, changeFeedID model.ChangeFeedID) error {
 	m.ctrl.T.Helper()
@@ -32,6 +32,7 @@ type GlobalReactorState struct {
 	Owner          map[string]struct{}
 	Captures       map[model.CaptureID]*model.CaptureInfo
 	Upstreams      map[model.UpstreamID]*model.UpstreamInfo
+	Namespaces     map[string]*model.NamespaceInfo
 	Changefeeds    map[model.ChangeFeedID]*ChangefeedReactorState
 	pendingPatches [][]DataPatch
 
@@ -48,6 +49,7 @@ func NewGlobalState(clusterID string) *GlobalReactorState {
 		Owner:       map[string]struct{}{},
 		Captures:    make(map[model.CaptureID]*model.CaptureInfo),
 		Upstreams:   make(map[model.UpstreamID]*model.UpstreamInfo),
+		Namespaces:  make(map[string]*model.NamespaceInfo),
 		Changefeeds: make(map[model.ChangeFeedID]*ChangefeedReactorState),
 	}
 }
@@ -90,6 +92,7 @@ func (s *GlobalReactorState) Update(key util.EtcdKey, value []byte, _ bool) erro
 		s.Captures[k.CaptureID] = &newCaptureInfo
 	case etcd.CDCKeyTypeChangefeedInfo,
 		etcd.CDCKeyTypeChangeFeedStatus,
+		etcd.CDCKeyTypeChangefeedHistory,
 		etcd.CDCKeyTypeTaskPosition:
 		changefeedState, exist := s.Changefeeds[k.ChangefeedID]
 		if !exist {
@@ -123,6 +126,21 @@ func (s *GlobalReactorState) Update(key util.EtcdKey, value []byte, _ bool) erro
 			zap.Uint64("upstream", k.UpstreamID),
 			zap.Any("info", newUpstreamInfo))
 		s.Upstreams[k.UpstreamID] = &newUpstreamInfo
+	case etcd.CDCKeyTypeNamespace:
+		if value == nil {
+			log.Info("namespace is removed", zap.String("namespace", k.Namespace))
+			delete(s.Namespaces, k.Namespace)
+			return nil
+		}
+		var newNamespaceInfo model.NamespaceInfo
+		err := newNamespaceInfo.Unmarshal(value)
+		if err != nil {
+			return cerrors.ErrUnmarshalFailed.Wrap(err).GenWithStackByArgs()
+		}
+		log.Info("namespace is updated",
+			zap.String("namespace", k.Namespace),
+			zap.Any("info", newNamespaceInfo))
+		s.Namespaces[k.Namespace] = &newNamespaceInfo
 	case etcd.CDCKeyTypeMetaVersion:
 	default:
 		log.Warn("receive an unexpected etcd event", zap.String("key", key.String()), zap.ByteString("value", value))
@@ -157,6 +175,7 @@ type ChangefeedReactorState struct {
 	ID            model.ChangeFeedID
 	Info          *model.ChangeFeedInfo
 	Status        *model.ChangeFeedStatus
+	History       *model.ChangefeedHistory
 	TaskPositions map[model.CaptureID]*model.TaskPosition
 
 	pendingPatches        []DataPatch
@@ -211,6 +230,16 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 		}
 		s.Status = new(model.ChangeFeedStatus)
 		e = s.Status
+	case etcd.CDCKeyTypeChangefeedHistory:
+		if key.ChangefeedID != s.ID {
+			return nil
+		}
+		if value == nil {
+			s.History = nil
+			return nil
+		}
+		s.History = new(model.ChangefeedHistory)
+		e = s.History
 	case etcd.CDCKeyTypeTaskPosition:
 		if key.ChangefeedID != s.ID {
 			return nil
@@ -238,7 +267,7 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 
 // Exist returns false if all keys of this changefeed in ETCD is not exist
 func (s *ChangefeedReactorState) Exist() bool {
-	return s.Info != nil || s.Status != nil || len(s.TaskPositions) != 0
+	return s.Info != nil || s.Status != nil || s.History != nil || len(s.TaskPositions) != 0
 }
 
 // Active return true if the changefeed is ready to be processed
@@ -337,6 +366,22 @@ func (s *ChangefeedReactorState) PatchStatus(fn func(*model.ChangeFeedStatus) (*
 	})
 }
 
+// PatchHistory appends a DataPatch which can modify the ChangefeedHistory
+func (s *ChangefeedReactorState) PatchHistory(fn func(*model.ChangefeedHistory) (*model.ChangefeedHistory, bool, error)) {
+	key := &etcd.CDCKey{
+		ClusterID:    s.ClusterID,
+		Tp:           etcd.CDCKeyTypeChangefeedHistory,
+		ChangefeedID: s.ID,
+	}
+	s.patchAny(key.String(), changefeedHistoryTPI, func(e interface{}) (interface{}, bool, error) {
+		// e == nil means that the key is not exist before this patch
+		if e == nil {
+			return fn(nil)
+		}
+		return fn(e.(*model.ChangefeedHistory))
+	})
+}
+
 // PatchTaskPosition appends a DataPatch which can modify the TaskPosition of a specified capture
 func (s *ChangefeedReactorState) PatchTaskPosition(captureID model.CaptureID, fn func(*model.TaskPosition) (*model.TaskPosition, bool, error)) {
 	key := &etcd.CDCKey{
@@ -355,9 +400,10 @@ func (s *ChangefeedReactorState) PatchTaskPosition(captureID model.CaptureID, fn
 }
 
 var (
-	taskPositionTPI     *model.TaskPosition
-	changefeedStatusTPI *model.ChangeFeedStatus
-	changefeedInfoTPI   *model.ChangeFeedInfo
+	taskPositionTPI      *model.TaskPosition
+	changefeedStatusTPI  *model.ChangeFeedStatus
+	changefeedInfoTPI    *model.ChangeFeedInfo
+	changefeedHistoryTPI *model.ChangefeedHistory
 )
 
 func (s *ChangefeedReactorState) patchAny(key string, tpi interface{}, fn func(interface{}) (interface{}, bool, error)) {
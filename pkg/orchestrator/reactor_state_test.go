@@ -127,6 +127,7 @@ func TestChangefeedStateUpdate(t *testing.T) {
 						Consistent:       &config.ConsistentConfig{Level: "normal", Storage: "local"},
 						Scheduler:        config.GetDefaultReplicaConfig().Scheduler,
 						Integrity:        config.GetDefaultReplicaConfig().Integrity,
+						OnFinish:         config.GetDefaultReplicaConfig().OnFinish,
 					},
 				},
 				Status: &model.ChangeFeedStatus{CheckpointTs: 421980719742451713, ResolvedTs: 421980720003809281},
@@ -177,6 +178,7 @@ func TestChangefeedStateUpdate(t *testing.T) {
 						Consistent:       &config.ConsistentConfig{Level: "normal", Storage: "local"},
 						Scheduler:        config.GetDefaultReplicaConfig().Scheduler,
 						Integrity:        config.GetDefaultReplicaConfig().Integrity,
+						OnFinish:         config.GetDefaultReplicaConfig().OnFinish,
 					},
 				},
 				Status: &model.ChangeFeedStatus{CheckpointTs: 421980719742451713, ResolvedTs: 421980720003809281},
@@ -232,6 +234,7 @@ func TestChangefeedStateUpdate(t *testing.T) {
 						Consistent:       &config.ConsistentConfig{Level: "normal", Storage: "local"},
 						Scheduler:        config.GetDefaultReplicaConfig().Scheduler,
 						Integrity:        config.GetDefaultReplicaConfig().Integrity,
+						OnFinish:         config.GetDefaultReplicaConfig().OnFinish,
 					},
 				},
 				Status: &model.ChangeFeedStatus{CheckpointTs: 421980719742451713, ResolvedTs: 421980720003809281},
@@ -322,6 +325,7 @@ func TestPatchInfo(t *testing.T) {
 			Consistent: defaultConfig.Consistent,
 			Scheduler:  defaultConfig.Scheduler,
 			Integrity:  defaultConfig.Integrity,
+			OnFinish:   defaultConfig.OnFinish,
 		},
 	})
 	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
@@ -340,6 +344,7 @@ func TestPatchInfo(t *testing.T) {
 			Consistent: defaultConfig.Consistent,
 			Scheduler:  defaultConfig.Scheduler,
 			Integrity:  defaultConfig.Integrity,
+			OnFinish:   defaultConfig.OnFinish,
 		},
 	})
 	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
@@ -372,6 +377,31 @@ func TestPatchStatus(t *testing.T) {
 	require.Nil(t, state.Status)
 }
 
+func TestPatchHistory(t *testing.T) {
+	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID("test1"))
+	stateTester := NewReactorStateTester(t, state, nil)
+	event := &model.ChangefeedEvent{
+		Type:    model.ChangefeedEventAdminJob,
+		Message: model.AdminStop.String(),
+	}
+	state.PatchHistory(func(history *model.ChangefeedHistory) (*model.ChangefeedHistory, bool, error) {
+		require.Nil(t, history)
+		history = &model.ChangefeedHistory{}
+		return history, history.Append(event), nil
+	})
+	stateTester.MustApplyPatches()
+	require.True(t, state.Exist())
+	require.Len(t, state.History.Events, 1)
+	require.Equal(t, event.Message, state.History.Events[0].Message)
+	state.PatchHistory(func(history *model.ChangefeedHistory) (*model.ChangefeedHistory, bool, error) {
+		return nil, true, nil
+	})
+	stateTester.MustApplyPatches()
+	require.Nil(t, state.History)
+	require.False(t, state.Exist())
+}
+
 func TestPatchTaskPosition(t *testing.T) {
 	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
 		model.DefaultChangeFeedID("test1"))
@@ -456,6 +486,8 @@ func TestGlobalStateUpdate(t *testing.T) {
 					"/task/position/6bbc01c8-0605-4f86-a0f9-b3119109b225/test2",
 				etcd.DefaultClusterAndNamespacePrefix +
 					"/upstream/12345",
+				etcd.DefaultClusterAndMetaPrefix +
+					"/namespace/team-a",
 			},
 			updateValue: []string{
 				`6bbc01c8-0605-4f86-a0f9-b3119109b225`,
@@ -466,6 +498,7 @@ func TestGlobalStateUpdate(t *testing.T) {
 				`{"resolved-ts":421980720003809281,"checkpoint-ts":421980719742451713,
 "admin-job-type":0}`,
 				`{}`,
+				`{"name":"team-a","max-changefeeds":2}`,
 			},
 			expected: GlobalReactorState{
 				ClusterID: etcd.DefaultCDCClusterID,
@@ -477,6 +510,9 @@ func TestGlobalStateUpdate(t *testing.T) {
 				Upstreams: map[model.UpstreamID]*model.UpstreamInfo{
 					model.UpstreamID(12345): {},
 				},
+				Namespaces: map[string]*model.NamespaceInfo{
+					"team-a": {Name: "team-a", MaxChangefeeds: 2},
+				},
 				Changefeeds: map[model.ChangeFeedID]*ChangefeedReactorState{
 					model.DefaultChangeFeedID("test1"): {
 						ClusterID: etcd.DefaultCDCClusterID,
@@ -516,6 +552,10 @@ func TestGlobalStateUpdate(t *testing.T) {
 					"/task/position/6bbc01c8-0605-4f86-a0f9-b3119109b225/test1",
 				etcd.DefaultClusterAndMetaPrefix +
 					"/capture/6bbc01c8-0605-4f86-a0f9-b3119109b225",
+				etcd.DefaultClusterAndMetaPrefix +
+					"/namespace/team-a",
+				etcd.DefaultClusterAndMetaPrefix +
+					"/namespace/team-a",
 			},
 			updateValue: []string{
 				`6bbc01c8-0605-4f86-a0f9-b3119109b225`,
@@ -528,12 +568,15 @@ func TestGlobalStateUpdate(t *testing.T) {
 				``,
 				``,
 				``,
+				`{"name":"team-a"}`,
+				``,
 			},
 			expected: GlobalReactorState{
-				ClusterID: etcd.DefaultCDCClusterID,
-				Owner:     map[string]struct{}{"22317526c4fc9a38": {}},
-				Captures:  map[model.CaptureID]*model.CaptureInfo{},
-				Upstreams: map[model.UpstreamID]*model.UpstreamInfo{},
+				ClusterID:  etcd.DefaultCDCClusterID,
+				Owner:      map[string]struct{}{"22317526c4fc9a38": {}},
+				Captures:   map[model.CaptureID]*model.CaptureInfo{},
+				Upstreams:  map[model.UpstreamID]*model.UpstreamInfo{},
+				Namespaces: map[string]*model.NamespaceInfo{},
 				Changefeeds: map[model.ChangeFeedID]*ChangefeedReactorState{
 					model.DefaultChangeFeedID("test2"): {
 						ClusterID: etcd.DefaultCDCClusterID,
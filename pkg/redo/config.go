@@ -30,6 +30,9 @@ import (
 var (
 	// DefaultGCIntervalInMs defines GC interval in meta manager, which can be changed in tests.
 	DefaultGCIntervalInMs = 5000 // 5 seconds
+	// DefaultCompactIntervalInMs defines compaction interval in meta manager,
+	// which can be changed in tests.
+	DefaultCompactIntervalInMs = 60000 // 1 minute
 	// DefaultMaxLogSize is the default max size of log file
 	DefaultMaxLogSize = int64(64)
 )
@@ -233,8 +236,16 @@ const (
 	// RedoMetaFileFormat is the format of redo meta file, which contains namespace information.
 	// layout: captureID_namespace_changefeedID_fileType_uuid.fileExtName
 	RedoMetaFileFormat = "%s_%s_%s_%s_%s%s"
+	// RedoCompactedLogCaptureID is used as the capture ID in the name of
+	// log files written by the redo log compactor.
+	RedoCompactedLogCaptureID = "compacted"
 )
 
+// IsCompactedLogFile returns true if the log file is written by the compactor.
+func IsCompactedLogFile(name string) bool {
+	return strings.HasPrefix(filepath.Base(name), RedoCompactedLogCaptureID+"_")
+}
+
 // logFormat2ParseFormat converts redo log file name format to the space separated
 // format, which can be read and parsed by sscanf. Besides remove the suffix `%s`
 // which is used as file name extension, since we will parse extension first.
@@ -0,0 +1,169 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// syncPointDir is the directory where syncpoint manifests sit.
+const syncPointDir = "syncpoint"
+
+// SyncPointManifest records the data files which are written when a syncpoint
+// is reached. Data files listed in the manifest contain all changes committed
+// before or at the primary ts, and no change committed after it, so they can
+// be used to build a consistent view of all tables.
+type SyncPointManifest struct {
+	PrimaryTs uint64               `json:"primary-ts"`
+	Files     []SyncPointFileIndex `json:"files"`
+}
+
+// SyncPointFileIndex is the last data file written for a physical table.
+// Data files in the data directories the table was written to before are all
+// written before the syncpoint.
+type SyncPointFileIndex struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	// Dir is the data directory, like "schema/table/version/date".
+	Dir string `json:"dir"`
+	// FileName is the name of the last data file, like "CDC000005.csv".
+	FileName string `json:"file-name"`
+	// Index is the index of the last data file, data files in the directory
+	// whose indexes are not greater than it are written before the syncpoint.
+	Index uint64 `json:"index"`
+}
+
+// SyncPointTableDir is the data directory a physical table is written to.
+// It is reported by the DML writer when the table is written to a new data
+// directory, so that the manifest of a syncpoint is built without listing the
+// whole storage.
+type SyncPointTableDir struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	// Dir is the data directory, like "schema/table/version/date".
+	Dir string `json:"dir"`
+}
+
+// GenerateSyncPointManifestPath generates the manifest file path of a syncpoint.
+func GenerateSyncPointManifestPath(primaryTs uint64) string {
+	return fmt.Sprintf("%s/%d.manifest", syncPointDir, primaryTs)
+}
+
+// GenerateSyncPointTableDirPath generates the file path where the data
+// directory of a physical table is reported.
+func GenerateSyncPointTableDirPath(physicalTableID int64) string {
+	return fmt.Sprintf("%s/tables/%d.dir", syncPointDir, physicalTableID)
+}
+
+// NewSyncPointManifest reads the reported data directories of the tables and
+// their index files, and creates the manifest of the syncpoint. Tables which
+// have no data files yet are not in the manifest.
+func NewSyncPointManifest(
+	ctx context.Context, extStorage storage.ExternalStorage,
+	primaryTs uint64, tables []*model.TableInfo,
+) (*SyncPointManifest, error) {
+	manifest := &SyncPointManifest{PrimaryTs: primaryTs, Files: []SyncPointFileIndex{}}
+	for _, table := range tables {
+		physicalTableIDs := []int64{table.ID}
+		if pi := table.GetPartitionInfo(); pi != nil {
+			physicalTableIDs = physicalTableIDs[:0]
+			for _, partition := range pi.Definitions {
+				physicalTableIDs = append(physicalTableIDs, partition.ID)
+			}
+		}
+		for _, tableID := range physicalTableIDs {
+			file, ok, err := readSyncPointFileIndex(ctx, extStorage, tableID)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				manifest.Files = append(manifest.Files, file)
+			}
+		}
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Dir < manifest.Files[j].Dir
+	})
+	return manifest, nil
+}
+
+// readSyncPointFileIndex reads the last data file of a physical table, it
+// returns false if no data file of the table is written.
+func readSyncPointFileIndex(
+	ctx context.Context, extStorage storage.ExternalStorage, physicalTableID int64,
+) (SyncPointFileIndex, bool, error) {
+	dirPath := GenerateSyncPointTableDirPath(physicalTableID)
+	exist, err := extStorage.FileExists(ctx, dirPath)
+	if err != nil || !exist {
+		return SyncPointFileIndex{}, false, err
+	}
+	data, err := extStorage.ReadFile(ctx, dirPath)
+	if err != nil {
+		return SyncPointFileIndex{}, false, err
+	}
+	var tableDir SyncPointTableDir
+	if err := json.Unmarshal(data, &tableDir); err != nil {
+		return SyncPointFileIndex{}, false, cerror.WrapError(cerror.ErrStorageSinkInvalidFileName, err)
+	}
+	// The directory is reported before the index file is written.
+	indexPath := path.Join(tableDir.Dir, defaultIndexFileName)
+	exist, err = extStorage.FileExists(ctx, indexPath)
+	if err != nil || !exist {
+		return SyncPointFileIndex{}, false, err
+	}
+	data, err = extStorage.ReadFile(ctx, indexPath)
+	if err != nil {
+		return SyncPointFileIndex{}, false, err
+	}
+	fileName := strings.TrimSuffix(string(data), "\n")
+	index, err := fetchIndexFromDataFileName(fileName)
+	if err != nil {
+		return SyncPointFileIndex{}, false, err
+	}
+	return SyncPointFileIndex{
+		Schema:   tableDir.Schema,
+		Table:    tableDir.Table,
+		Dir:      tableDir.Dir,
+		FileName: fileName,
+		Index:    index,
+	}, true, nil
+}
+
+// fetchIndexFromDataFileName parses the index of a data file name like
+// "CDC000005.csv", regardless of the extension.
+func fetchIndexFromDataFileName(fileName string) (uint64, error) {
+	if !strings.HasPrefix(fileName, "CDC") {
+		return 0, cerror.WrapError(cerror.ErrStorageSinkInvalidFileName,
+			fmt.Errorf("'%s' is a invalid file name", fileName))
+	}
+	name := fileName[len("CDC"):]
+	if dot := strings.Index(name, "."); dot >= 0 {
+		name = name[:dot]
+	}
+	index, err := strconv.ParseUint(name, 10, 64)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrStorageSinkInvalidFileName, err)
+	}
+	return index, nil
+}
@@ -53,6 +53,9 @@ type BatchEncoder struct {
 type Options struct {
 	EnableTiDBExtension bool
 	EnableRowChecksum   bool
+	// EnableTxnBoundary outputs the transaction boundary of each row,
+	// it only works if the tidb extension is enabled.
+	EnableTxnBoundary bool
 
 	// EnableWatermarkEvent set to true, avro encode DDL and checkpoint event
 	// and send to the downstream kafka, they cannot be consumed by the confluent official consumer
@@ -166,6 +169,12 @@ func (a *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 	return nil, nil
 }
 
+// EncodeSyncPointEvent implements the RowEventEncoder interface, there is
+// no syncpoint event in avro so far, the event is ignored.
+func (a *BatchEncoder) EncodeSyncPointEvent(ts uint64) (*common.Message, error) {
+	return nil, nil
+}
+
 // EncodeDDLEvent only encode DDL event if the watermark event is enabled
 // it's only used for the testing purpose.
 func (a *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error) {
@@ -211,6 +220,7 @@ func (a *BatchEncoder) avroEncode(
 		colInfos               []rowcodec.ColInfo
 		enableTiDBExtension    bool
 		enableRowLevelChecksum bool
+		enableTxnBoundary      bool
 		schemaManager          *schemaManager
 		operation              string
 	)
@@ -222,6 +232,7 @@ func (a *BatchEncoder) avroEncode(
 		}
 		enableTiDBExtension = false
 		enableRowLevelChecksum = false
+		enableTxnBoundary = false
 		schemaManager = a.keySchemaManager
 	} else {
 		input = &avroEncodeInput{
@@ -231,6 +242,7 @@ func (a *BatchEncoder) avroEncode(
 
 		enableTiDBExtension = a.EnableTiDBExtension
 		enableRowLevelChecksum = a.EnableRowChecksum
+		enableTxnBoundary = a.EnableTxnBoundary
 		schemaManager = a.valueSchemaManager
 		if e.IsInsert() {
 			operation = insertOperation
@@ -255,6 +267,7 @@ func (a *BatchEncoder) avroEncode(
 			input,
 			enableTiDBExtension,
 			enableRowLevelChecksum,
+			enableTxnBoundary,
 			a.DecimalHandlingMode,
 			a.BigintUnsignedHandlingMode,
 		)
@@ -294,6 +307,17 @@ func (a *BatchEncoder) avroEncode(
 		native[tidbChecksumVersion] = e.ChecksumVersion
 	}
 
+	if enableTxnBoundary && enableTiDBExtension {
+		native[tidbTxnID] = int64(e.StartTs)
+		if e.TxnBoundary != nil {
+			native[tidbTxnTableRowIndex] = e.TxnBoundary.TableRowIndex
+			native[tidbTxnTableRowCount] = e.TxnBoundary.TableRowCount
+		} else {
+			native[tidbTxnTableRowIndex] = 0
+			native[tidbTxnTableRowCount] = 0
+		}
+	}
+
 	bin, err := avroCodec.BinaryFromNative(nil, native)
 	if err != nil {
 		log.Error("AvroEventBatchEncoder: converting to Avro binary failed", zap.Error(err))
@@ -323,6 +347,11 @@ const (
 	tidbRowLevelChecksum = "_tidb_row_level_checksum"
 	tidbChecksumVersion  = "_tidb_checksum_version"
 	tidbCorrupted        = "_tidb_corrupted"
+
+	// transaction boundary related fields
+	tidbTxnID            = "_tidb_txn_id"
+	tidbTxnTableRowIndex = "_tidb_txn_table_row_index"
+	tidbTxnTableRowCount = "_tidb_txn_table_row_count"
 )
 
 var type2TiDBType = map[byte]string{
@@ -439,6 +468,7 @@ func rowToAvroSchema(
 	input *avroEncodeInput,
 	enableTiDBExtension bool,
 	enableRowLevelChecksum bool,
+	enableTxnBoundary bool,
 	decimalHandlingMode string,
 	bigintUnsignedHandlingMode string,
 ) (string, error) {
@@ -545,6 +575,25 @@ func rowToAvroSchema(
 				})
 		}
 
+		if enableTxnBoundary {
+			top.Fields = append(top.Fields,
+				map[string]interface{}{
+					"name":    tidbTxnID,
+					"type":    "long",
+					"default": 0,
+				},
+				map[string]interface{}{
+					"name":    tidbTxnTableRowIndex,
+					"type":    "int",
+					"default": 0,
+				},
+				map[string]interface{}{
+					"name":    tidbTxnTableRowCount,
+					"type":    "int",
+					"default": 0,
+				})
+		}
+
 	}
 
 	str, err := json.Marshal(&top)
@@ -554,7 +603,8 @@ func rowToAvroSchema(
 	log.Info("rowToAvroSchema",
 		zap.ByteString("schema", str),
 		zap.Bool("enableTiDBExtension", enableTiDBExtension),
-		zap.Bool("enableRowLevelChecksum", enableRowLevelChecksum))
+		zap.Bool("enableRowLevelChecksum", enableRowLevelChecksum),
+		zap.Bool("enableTxnBoundary", enableTxnBoundary))
 	return string(str), nil
 }
 
@@ -970,6 +1020,7 @@ func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
 		Options: &Options{
 			EnableTiDBExtension:        b.config.EnableTiDBExtension,
 			EnableRowChecksum:          b.config.EnableRowChecksum,
+			EnableTxnBoundary:          b.config.EnableTxnBoundary,
 			EnableWatermarkEvent:       b.config.AvroEnableWatermark,
 			DecimalHandlingMode:        b.config.AvroDecimalHandlingMode,
 			BigintUnsignedHandlingMode: b.config.AvroBigintUnsignedHandlingMode,
@@ -677,6 +677,7 @@ func TestRowToAvroSchemaEnableChecksum(t *testing.T) {
 		input,
 		true,
 		true,
+		false,
 		"string",
 		"string",
 	)
@@ -719,6 +720,7 @@ func TestRowToAvroSchema(t *testing.T) {
 		},
 		false,
 		false,
+		false,
 		"precise",
 		"long",
 	)
@@ -736,6 +738,7 @@ func TestRowToAvroSchema(t *testing.T) {
 		},
 		true,
 		false,
+		false,
 		"precise",
 		"long",
 	)
@@ -853,6 +856,7 @@ func TestAvroEncode(t *testing.T) {
 		},
 		false,
 		false,
+		false,
 		"precise",
 		"long",
 	)
@@ -879,6 +883,7 @@ func TestAvroEncode(t *testing.T) {
 		},
 		true,
 		false,
+		false,
 		"precise",
 		"long",
 	)
@@ -901,6 +906,59 @@ func TestAvroEncode(t *testing.T) {
 	}
 }
 
+func TestAvroEncodeTxnBoundary(t *testing.T) {
+	encoder, err := setupEncoderAndSchemaRegistry(true, "precise", "long")
+	require.NoError(t, err)
+	defer teardownEncoderAndSchemaRegistry()
+	encoder.EnableTxnBoundary = true
+
+	cols := []*model.Column{{
+		Name:  "id",
+		Value: int64(1),
+		Type:  mysql.TypeLong,
+		Flag:  model.HandleKeyFlag,
+	}}
+	colInfos := []rowcodec.ColInfo{{
+		ID:         1000,
+		IsPKHandle: true,
+		Ft:         types.NewFieldType(mysql.TypeLong),
+	}}
+	event := &model.RowChangedEvent{
+		StartTs:  417318403368288250,
+		CommitTs: 417318403368288260,
+		Table:    &model.TableName{Schema: "testdb", Table: "txnboundary"},
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "testdb", Table: "txnboundary"},
+		},
+		Columns:     cols,
+		ColInfos:    colInfos,
+		TxnBoundary: &model.TxnBoundary{TableRowIndex: 2, TableRowCount: 3},
+	}
+
+	valueSchema, err := rowToAvroSchema(
+		getAvroNamespace(encoder.namespace, event.Table),
+		event.Table.Table,
+		&avroEncodeInput{cols, colInfos},
+		true,
+		false,
+		true,
+		"precise",
+		"long",
+	)
+	require.NoError(t, err)
+	avroValueCodec, err := goavro.NewCodec(valueSchema)
+	require.NoError(t, err)
+
+	r, err := encoder.avroEncode(context.Background(), event, "default", false)
+	require.NoError(t, err)
+	res, _, err := avroValueCodec.NativeFromBinary(r.data)
+	require.NoError(t, err)
+	native := res.(map[string]interface{})
+	require.Equal(t, int64(event.StartTs), native[tidbTxnID])
+	require.Equal(t, int32(2), native[tidbTxnTableRowIndex])
+	require.Equal(t, int32(3), native[tidbTxnTableRowCount])
+}
+
 func TestAvroEnvelope(t *testing.T) {
 	t.Parallel()
 
@@ -299,7 +299,7 @@ func BenchmarkCraftDecoding(b *testing.B) {
 func BenchmarkJsonDecoding(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for _, message := range codecJSONEncodedRowChanges {
-			decoder := open.NewBatchDecoder()
+			decoder := open.NewBatchDecoder(nil)
 			if err := decoder.AddKeyValue(message.Key, message.Value); err != nil {
 				panic(err)
 			} else {
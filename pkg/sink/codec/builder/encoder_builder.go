@@ -35,7 +35,7 @@ func NewRowEventEncoderBuilder(
 ) (codec.RowEventEncoderBuilder, error) {
 	switch c.Protocol {
 	case config.ProtocolDefault, config.ProtocolOpen:
-		return open.NewBatchEncoderBuilder(c), nil
+		return open.NewBatchEncoderBuilder(ctx, c)
 	case config.ProtocolCanal:
 		return canal.NewBatchEncoderBuilder(), nil
 	case config.ProtocolAvro:
@@ -43,7 +43,7 @@ func NewRowEventEncoderBuilder(
 	case config.ProtocolMaxwell:
 		return maxwell.NewBatchEncoderBuilder(), nil
 	case config.ProtocolCanalJSON:
-		return canal.NewJSONRowEventEncoderBuilder(c), nil
+		return canal.NewJSONRowEventEncoderBuilder(ctx, c)
 	case config.ProtocolCraft:
 		return craft.NewBatchEncoderBuilder(c), nil
 
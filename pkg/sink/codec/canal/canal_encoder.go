@@ -43,6 +43,13 @@ func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 	return nil, nil
 }
 
+// EncodeSyncPointEvent implements the RowEventEncoder interface
+func (d *BatchEncoder) EncodeSyncPointEvent(ts uint64) (*common.Message, error) {
+	// For canal now, there is no such a corresponding type to SyncPointEvent so far.
+	// Therefore, the event is ignored.
+	return nil, nil
+}
+
 // AppendRowChangedEvent implements the RowEventEncoder interface
 func (d *BatchEncoder) AppendRowChangedEvent(
 	_ context.Context,
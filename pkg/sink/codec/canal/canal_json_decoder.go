@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"go.uber.org/zap"
 )
 
@@ -30,16 +31,21 @@ type batchDecoder struct {
 	msg                 canalJSONMessageInterface
 	enableTiDBExtension bool
 	terminator          string
+
+	claimCheck *common.ClaimCheck
 }
 
-// NewBatchDecoder return a decoder for canal-json
+// NewBatchDecoder return a decoder for canal-json. claimCheck is used to fetch
+// messages sent by claim check, it can be nil if claim check is not enabled.
 func NewBatchDecoder(
 	enableTiDBExtension bool,
 	terminator string,
+	claimCheck *common.ClaimCheck,
 ) codec.RowEventDecoder {
 	return &batchDecoder{
 		enableTiDBExtension: enableTiDBExtension,
 		terminator:          terminator,
+		claimCheck:          claimCheck,
 	}
 }
 
@@ -85,11 +91,38 @@ func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
 			zap.Error(err), zap.ByteString("data", encodedData))
 		return model.MessageTypeUnknown, false, err
 	}
+	if withExtension, ok := msg.(*canalJSONMessageWithTiDBExtension); ok &&
+		withExtension.Extensions != nil && withExtension.Extensions.ClaimCheckLocation != "" {
+		var err error
+		if msg, err = b.fetchClaimCheckMessage(withExtension.Extensions.ClaimCheckLocation); err != nil {
+			return model.MessageTypeUnknown, false, err
+		}
+	}
 	b.msg = msg
 
 	return b.msg.messageType(), true, nil
 }
 
+// fetchClaimCheckMessage reads the message which was sent by claim check.
+func (b *batchDecoder) fetchClaimCheckMessage(location string) (canalJSONMessageInterface, error) {
+	if b.claimCheck == nil {
+		return nil, cerror.ErrCanalDecodeFailed.
+			GenWithStack("claim check message found, but claim check storage is not set")
+	}
+	claimCheckMsg, err := b.claimCheck.ReadMessage(location)
+	if err != nil {
+		return nil, err
+	}
+	msg := &canalJSONMessageWithTiDBExtension{
+		JSONMessage: &JSONMessage{},
+		Extensions:  &tidbExtension{},
+	}
+	if err := json.Unmarshal(claimCheckMsg.Value, msg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+	}
+	return msg, nil
+}
+
 // NextRowChangedEvent implements the RowEventDecoder interface
 // `HasNext` should be called before this.
 func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
@@ -121,7 +154,8 @@ func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
 // NextResolvedEvent implements the RowEventDecoder interface
 // `HasNext` should be called before this.
 func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
-	if b.msg == nil || b.msg.messageType() != model.MessageTypeResolved {
+	if b.msg == nil || (b.msg.messageType() != model.MessageTypeResolved &&
+		b.msg.messageType() != model.MessageTypeSyncPoint) {
 		return 0, cerror.ErrCanalDecodeFailed.
 			GenWithStack("not found resolved event message")
 	}
@@ -134,5 +168,8 @@ func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
 			GenWithStack("MessageTypeResolved tidb extension not found")
 	}
 	b.msg = nil
+	if withExtensionEvent.messageType() == model.MessageTypeSyncPoint {
+		return withExtensionEvent.Extensions.SyncPointTs, nil
+	}
 	return withExtensionEvent.Extensions.WatermarkTs, nil
 }
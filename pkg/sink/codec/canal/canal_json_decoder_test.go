@@ -31,7 +31,7 @@ func TestNewCanalJSONBatchDecoder4RowMessage(t *testing.T) {
 			EnableTiDBExtension: encodeEnable,
 			Terminator:          config.CRLF,
 			MaxMessageBytes:     config.DefaultMaxMessageBytes,
-		})
+		}, nil)
 		require.NotNil(t, encoder)
 
 		err := encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, nil)
@@ -42,7 +42,7 @@ func TestNewCanalJSONBatchDecoder4RowMessage(t *testing.T) {
 		msg := messages[0]
 
 		for _, decodeEnable := range []bool{false, true} {
-			decoder := NewBatchDecoder(decodeEnable, "")
+			decoder := NewBatchDecoder(decodeEnable, "", nil)
 			err := decoder.AddKeyValue(msg.Key, msg.Value)
 			require.NoError(t, err)
 
@@ -97,7 +97,7 @@ func TestNewCanalJSONBatchDecoder4DDLMessage(t *testing.T) {
 		require.NotNil(t, result)
 
 		for _, decodeEnable := range []bool{false, true} {
-			decoder := NewBatchDecoder(decodeEnable, "")
+			decoder := NewBatchDecoder(decodeEnable, "", nil)
 			err := decoder.AddKeyValue(nil, result.Value)
 			require.NoError(t, err)
 
@@ -134,7 +134,7 @@ func TestCanalJSONBatchDecoderWithTerminator(t *testing.T) {
 	encodedValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"FirstName":12,"HireDate":91,"LastName":12,"OfficeLocation":12,"id":4},"mysqlType":{"FirstName":"varchar","HireDate":"date","LastName":"varchar","OfficeLocation":"varchar","id":"int"},"data":[{"FirstName":"Bob","HireDate":"2014-06-04","LastName":"Smith","OfficeLocation":"New York","id":"101"}],"old":null}
 {"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"UPDATE","es":1668067229137,"ts":1668067230720,"sql":"","sqlType":{"FirstName":12,"HireDate":91,"LastName":12,"OfficeLocation":12,"id":4},"mysqlType":{"FirstName":"varchar","HireDate":"date","LastName":"varchar","OfficeLocation":"varchar","id":"int"},"data":[{"FirstName":"Bob","HireDate":"2015-10-08","LastName":"Smith","OfficeLocation":"Los Angeles","id":"101"}],"old":[{"FirstName":"Bob","HireDate":"2014-06-04","LastName":"Smith","OfficeLocation":"New York","id":"101"}]}
 {"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"DELETE","es":1668067230388,"ts":1668067231725,"sql":"","sqlType":{"FirstName":12,"HireDate":91,"LastName":12,"OfficeLocation":12,"id":4},"mysqlType":{"FirstName":"varchar","HireDate":"date","LastName":"varchar","OfficeLocation":"varchar","id":"int"},"data":[{"FirstName":"Bob","HireDate":"2015-10-08","LastName":"Smith","OfficeLocation":"Los Angeles","id":"101"}],"old":null}`
-	decoder := NewBatchDecoder(false, "\n")
+	decoder := NewBatchDecoder(false, "\n", nil)
 	err := decoder.AddKeyValue(nil, []byte(encodedValue))
 	require.NoError(t, err)
 
@@ -25,7 +25,10 @@ import (
 	canal "github.com/pingcap/tiflow/proto/canal"
 )
 
-const tidbWaterMarkType = "TIDB_WATERMARK"
+const (
+	tidbWaterMarkType = "TIDB_WATERMARK"
+	tidbSyncPointType = "TIDB_SYNCPOINT"
+)
 
 // The TiCDC Canal-JSON implementation extend the official format with a TiDB extension field.
 // canalJSONMessageInterface is used to support this without affect the original format.
@@ -33,6 +36,7 @@ type canalJSONMessageInterface interface {
 	getSchema() *string
 	getTable() *string
 	getCommitTs() uint64
+	getTxnBoundary() (uint64, *model.TxnBoundary)
 	getQuery() string
 	getOld() map[string]interface{}
 	getData() map[string]interface{}
@@ -80,6 +84,11 @@ func (c *JSONMessage) getCommitTs() uint64 {
 	return 0
 }
 
+// for JSONMessage, we lost the transaction boundary.
+func (c *JSONMessage) getTxnBoundary() (uint64, *model.TxnBoundary) {
+	return 0, nil
+}
+
 func (c *JSONMessage) getQuery() string {
 	return c.Query
 }
@@ -115,6 +124,10 @@ func (c *JSONMessage) messageType() model.MessageType {
 		return model.MessageTypeResolved
 	}
 
+	if c.EventType == tidbSyncPointType {
+		return model.MessageTypeSyncPoint
+	}
+
 	return model.MessageTypeRow
 }
 
@@ -133,6 +146,17 @@ func (c *JSONMessage) pkNameSet() map[string]struct{} {
 type tidbExtension struct {
 	CommitTs    uint64 `json:"commitTs,omitempty"`
 	WatermarkTs uint64 `json:"watermarkTs,omitempty"`
+	// SyncPointTs is the primary ts of a syncpoint event.
+	SyncPointTs uint64 `json:"syncPointTs,omitempty"`
+	// ClaimCheckLocation is set if the message is a reference to a message
+	// written to the claim check storage.
+	ClaimCheckLocation string `json:"claimCheckLocation,omitempty"`
+	// TxnID, TxnTableRowIndex and TxnTableRowCount are the transaction
+	// boundary of the row in its table, they are set if transaction
+	// boundaries are enabled.
+	TxnID            uint64 `json:"txnId,omitempty"`
+	TxnTableRowIndex int    `json:"txnTableRowIndex,omitempty"`
+	TxnTableRowCount int    `json:"txnTableRowCount,omitempty"`
 }
 
 type canalJSONMessageWithTiDBExtension struct {
@@ -148,9 +172,20 @@ func (c *canalJSONMessageWithTiDBExtension) getCommitTs() uint64 {
 	return c.Extensions.CommitTs
 }
 
+func (c *canalJSONMessageWithTiDBExtension) getTxnBoundary() (uint64, *model.TxnBoundary) {
+	if c.Extensions.TxnTableRowCount == 0 {
+		return 0, nil
+	}
+	return c.Extensions.TxnID, &model.TxnBoundary{
+		TableRowIndex: c.Extensions.TxnTableRowIndex,
+		TableRowCount: c.Extensions.TxnTableRowCount,
+	}
+}
+
 func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChangedEvent, error) {
 	result := new(model.RowChangedEvent)
 	result.CommitTs = msg.getCommitTs()
+	result.StartTs, result.TxnBoundary = msg.getTxnBoundary()
 	result.Table = &model.TableName{
 		Schema: *msg.getSchema(),
 		Table:  *msg.getTable(),
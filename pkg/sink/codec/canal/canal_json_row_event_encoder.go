@@ -235,6 +235,14 @@ func newJSONMessageForDML(
 		out.RawByte('{')
 		out.RawString("\"commitTs\":")
 		out.Uint64(e.CommitTs)
+		if e.TxnBoundary != nil {
+			out.RawString(",\"txnId\":")
+			out.Uint64(e.StartTs)
+			out.RawString(",\"txnTableRowIndex\":")
+			out.Int(e.TxnBoundary.TableRowIndex)
+			out.RawString(",\"txnTableRowCount\":")
+			out.Int(e.TxnBoundary.TableRowCount)
+		}
 		out.RawByte('}')
 	}
 	out.RawByte('}')
@@ -263,20 +271,47 @@ type JSONRowEventEncoder struct {
 	messages            []*common.Message
 
 	onlyOutputUpdatedColumns bool
+
+	// claimCheck is set if messages larger than maxMessageBytes
+	// are sent by claim check.
+	claimCheck *common.ClaimCheck
 }
 
 // newJSONRowEventEncoder creates a new JSONRowEventEncoder
-func newJSONRowEventEncoder(config *common.Config) codec.RowEventEncoder {
+func newJSONRowEventEncoder(
+	config *common.Config, claimCheck *common.ClaimCheck,
+) codec.RowEventEncoder {
 	encoder := &JSONRowEventEncoder{
 		builder:                  newCanalEntryBuilder(),
 		enableTiDBExtension:      config.EnableTiDBExtension,
 		onlyOutputUpdatedColumns: config.OnlyOutputUpdatedColumns,
 		messages:                 make([]*common.Message, 0, 1),
 		maxMessageBytes:          config.MaxMessageBytes,
+		claimCheck:               claimCheck,
 	}
 	return encoder
 }
 
+// newJSONMessageForClaimCheck creates a message which only contains the
+// meta of the row changed event and the location of the full message.
+func newJSONMessageForClaimCheck(
+	e *model.RowChangedEvent, location string,
+) *canalJSONMessageWithTiDBExtension {
+	return &canalJSONMessageWithTiDBExtension{
+		JSONMessage: &JSONMessage{
+			ID:            0, // ignored by both Canal Adapter and Flink
+			Schema:        e.Table.Schema,
+			Table:         e.Table.Table,
+			PKNames:       e.PrimaryKeyColumnNames(),
+			IsDDL:         false,
+			EventType:     eventTypeString(e),
+			ExecutionTime: convertToCanalTs(e.CommitTs),
+			BuildTime:     time.Now().UnixMilli(), // ignored by both Canal Adapter and Flink
+		},
+		Extensions: &tidbExtension{CommitTs: e.CommitTs, ClaimCheckLocation: location},
+	}
+}
+
 func (c *JSONRowEventEncoder) newJSONMessageForDDL(e *model.DDLEvent) canalJSONMessageInterface {
 	msg := &JSONMessage{
 		ID:            0, // ignored by both Canal Adapter and Flink
@@ -328,9 +363,25 @@ func (c *JSONRowEventEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message,
 	return common.NewResolvedMsg(config.ProtocolCanalJSON, nil, value, ts), nil
 }
 
+// EncodeSyncPointEvent implements the RowEventEncoder interface
+func (c *JSONRowEventEncoder) EncodeSyncPointEvent(ts uint64) (*common.Message, error) {
+	if !c.enableTiDBExtension {
+		return nil, nil
+	}
+
+	msg := c.newJSONMessage4CheckpointEvent(ts)
+	msg.EventType = tidbSyncPointType
+	msg.Extensions = &tidbExtension{SyncPointTs: ts}
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+	}
+	return common.NewSyncPointMsg(config.ProtocolCanalJSON, nil, value, ts), nil
+}
+
 // AppendRowChangedEvent implements the interface EventJSONBatchEncoder
 func (c *JSONRowEventEncoder) AppendRowChangedEvent(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	e *model.RowChangedEvent,
 	callback func(),
@@ -342,6 +393,23 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 	}
 
 	length := len(value) + common.MaxRecordOverhead
+	if length > c.maxMessageBytes && c.claimCheck != nil {
+		// send a reference to the message written to the claim check storage instead.
+		location, err := c.claimCheck.WriteMessage(ctx, nil, value, e.CommitTs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Single message is too large for canal-json, send it by claim check",
+			zap.Int("maxMessageBytes", c.maxMessageBytes),
+			zap.Int("length", length),
+			zap.Any("table", e.Table),
+			zap.String("location", location))
+		value, err = json.Marshal(newJSONMessageForClaimCheck(e, location))
+		if err != nil {
+			return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
+		}
+		length = len(value) + common.MaxRecordOverhead
+	}
 	// for single message that is longer than max-message-bytes, do not send it.
 	if length > c.maxMessageBytes {
 		log.Warn("Single message is too large for canal-json",
@@ -389,17 +457,28 @@ func (c *JSONRowEventEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message
 }
 
 type jsonRowEventEncoderBuilder struct {
-	config *common.Config
+	config     *common.Config
+	claimCheck *common.ClaimCheck
 }
 
 // NewJSONRowEventEncoderBuilder creates a canal-json batchEncoderBuilder.
-func NewJSONRowEventEncoderBuilder(config *common.Config) codec.RowEventEncoderBuilder {
-	return &jsonRowEventEncoderBuilder{config: config}
+func NewJSONRowEventEncoderBuilder(
+	ctx context.Context, config *common.Config,
+) (codec.RowEventEncoderBuilder, error) {
+	var claimCheck *common.ClaimCheck
+	if config.ClaimCheckEnabled() {
+		var err error
+		claimCheck, err = common.NewClaimCheck(ctx, config.ClaimCheckStorageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &jsonRowEventEncoderBuilder{config: config, claimCheck: claimCheck}, nil
 }
 
 // Build a `jsonRowEventEncoderBuilder`
 func (b *jsonRowEventEncoderBuilder) Build() codec.RowEventEncoder {
-	return newJSONRowEventEncoder(b.config)
+	return newJSONRowEventEncoder(b.config, b.claimCheck)
 }
 
 func shouldIgnoreColumn(col *model.Column,
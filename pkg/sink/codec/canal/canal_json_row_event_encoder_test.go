@@ -16,6 +16,7 @@ package canal
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/parser/mysql"
@@ -47,7 +48,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	e := newJSONRowEventEncoder(&common.Config{
 		EnableTiDBExtension: false,
 		Terminator:          "",
-	})
+	}, nil)
 	require.NotNil(t, e)
 
 	encoder, ok := e.(*JSONRowEventEncoder)
@@ -120,7 +121,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	e = newJSONRowEventEncoder(&common.Config{
 		EnableTiDBExtension: true,
 		Terminator:          "",
-	})
+	}, nil)
 	require.NotNil(t, e)
 
 	encoder, ok = e.(*JSONRowEventEncoder)
@@ -187,7 +188,7 @@ func TestBatching(t *testing.T) {
 		EnableTiDBExtension: false,
 		Terminator:          "",
 		MaxMessageBytes:     config.DefaultMaxMessageBytes,
-	})
+	}, nil)
 	require.NotNil(t, encoder)
 
 	updateCase := *testCaseUpdate
@@ -236,7 +237,7 @@ func TestEncodeCheckpointEvent(t *testing.T) {
 		}
 
 		require.NotNil(t, msg)
-		decoder := NewBatchDecoder(enable, "")
+		decoder := NewBatchDecoder(enable, "", nil)
 
 		err = decoder.AddKeyValue(msg.Key, msg.Value)
 		require.NoError(t, err)
@@ -261,6 +262,36 @@ func TestEncodeCheckpointEvent(t *testing.T) {
 	}
 }
 
+func TestEncodeSyncPointEvent(t *testing.T) {
+	t.Parallel()
+	var primaryTs uint64 = 2333
+	encoder := &JSONRowEventEncoder{
+		builder:             newCanalEntryBuilder(),
+		enableTiDBExtension: false,
+	}
+	msg, err := encoder.EncodeSyncPointEvent(primaryTs)
+	require.Nil(t, err)
+	require.Nil(t, msg)
+
+	encoder.enableTiDBExtension = true
+	msg, err = encoder.EncodeSyncPointEvent(primaryTs)
+	require.Nil(t, err)
+	require.NotNil(t, msg)
+	require.Equal(t, model.MessageTypeSyncPoint, msg.Type)
+	require.Contains(t, string(msg.Value), `"type":"TIDB_SYNCPOINT"`)
+
+	decoder := NewBatchDecoder(true, "", nil)
+	err = decoder.AddKeyValue(msg.Key, msg.Value)
+	require.NoError(t, err)
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeSyncPoint, ty)
+	consumed, err := decoder.NextResolvedEvent()
+	require.Nil(t, err)
+	require.Equal(t, primaryTs, consumed)
+}
+
 func TestCheckpointEventValueMarshal(t *testing.T) {
 	t.Parallel()
 	var watermark uint64 = 1024
@@ -352,7 +383,7 @@ func TestCanalJSONAppendRowChangedEventWithCallback(t *testing.T) {
 		EnableTiDBExtension: true,
 		Terminator:          "",
 		MaxMessageBytes:     config.DefaultMaxMessageBytes,
-	})
+	}, nil)
 	require.NotNil(t, encoder)
 
 	count := 0
@@ -446,13 +477,107 @@ func TestMaxMessageBytes(t *testing.T) {
 	// the test message length is smaller than max-message-bytes
 	maxMessageBytes := 300
 	cfg := common.NewConfig(config.ProtocolCanalJSON).WithMaxMessageBytes(maxMessageBytes)
-	encoder := NewJSONRowEventEncoderBuilder(cfg).Build()
-	err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
+	builder, err := NewJSONRowEventEncoderBuilder(ctx, cfg)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.Nil(t, err)
 
 	// the test message length is larger than max-message-bytes
 	cfg = cfg.WithMaxMessageBytes(100)
-	encoder = NewJSONRowEventEncoderBuilder(cfg).Build()
+	encoder = builder.Build()
 	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.NotNil(t, err)
 }
+
+func TestCanalJSONClaimCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testEvent := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte(strings.Repeat("a", 1024)),
+		}},
+	}
+
+	cfg := common.NewConfig(config.ProtocolCanalJSON).WithMaxMessageBytes(512)
+	cfg.EnableTiDBExtension = true
+	cfg.LargeMessageHandleOption = common.LargeMessageHandleOptionClaimCheck
+	cfg.ClaimCheckStorageURI = "file://" + t.TempDir()
+	builder, err := NewJSONRowEventEncoderBuilder(ctx, cfg)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", testEvent, nil))
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.LessOrEqual(t, messages[0].Length(), 512)
+
+	// the reference message keeps the meta of the event.
+	reference := &canalJSONMessageWithTiDBExtension{}
+	require.NoError(t, json.Unmarshal(messages[0].Value, reference))
+	require.Equal(t, "a", reference.Schema)
+	require.Equal(t, "b", reference.Table)
+	require.Equal(t, "INSERT", reference.EventType)
+	require.Equal(t, testEvent.CommitTs, reference.Extensions.CommitTs)
+	require.NotEmpty(t, reference.Extensions.ClaimCheckLocation)
+	require.Nil(t, reference.Data)
+
+	claimCheck, err := common.NewClaimCheck(ctx, cfg.ClaimCheckStorageURI)
+	require.NoError(t, err)
+	decoder := NewBatchDecoder(true, "", claimCheck)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	decoded, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, testEvent.CommitTs, decoded.CommitTs)
+	require.Equal(t, testEvent.Table.Schema, decoded.Table.Schema)
+	require.Equal(t, testEvent.Table.Table, decoded.Table.Table)
+	require.Equal(t, strings.Repeat("a", 1024), decoded.Columns[0].Value)
+
+	// the decoder without claim check storage can't decode the reference message.
+	decoder = NewBatchDecoder(true, "", nil)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	_, _, err = decoder.HasNext()
+	require.Error(t, err)
+}
+
+func TestCanalJSONTxnBoundary(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testEvent := &model.RowChangedEvent{
+		StartTs:     5,
+		CommitTs:    10,
+		Table:       &model.TableName{Schema: "a", Table: "b"},
+		Columns:     []*model.Column{{Name: "col1", Type: mysql.TypeVarchar, Value: []byte("aa")}},
+		TxnBoundary: &model.TxnBoundary{TableRowIndex: 2, TableRowCount: 3},
+	}
+
+	cfg := common.NewConfig(config.ProtocolCanalJSON)
+	cfg.EnableTiDBExtension = true
+	cfg.EnableTxnBoundary = true
+	builder, err := NewJSONRowEventEncoderBuilder(ctx, cfg)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", testEvent, nil))
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.Contains(t, string(messages[0].Value),
+		`"_tidb":{"commitTs":10,"txnId":5,"txnTableRowIndex":2,"txnTableRowCount":3}`)
+
+	decoder := NewBatchDecoder(true, "", nil)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	tp, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	decoded, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, testEvent.StartTs, decoded.StartTs)
+	require.Equal(t, testEvent.TxnBoundary, decoded.TxnBoundary)
+}
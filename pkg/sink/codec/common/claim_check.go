@@ -0,0 +1,97 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+)
+
+// claimCheckReadTimeout is the timeout of fetching a message in decoders,
+// which don't have a context.
+const claimCheckReadTimeout = time.Minute
+
+// ClaimCheckMessage is the object written to the claim check storage.
+// Key and value are the ones which would be sent to the MQ
+// if the message was not too large.
+type ClaimCheckMessage struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// ClaimCheck writes oversized messages to an external storage, so that
+// only references to them are sent to the MQ.
+//
+// Objects are never deleted by TiCDC or consumers, since a message may be
+// consumed by several consumer groups or consumed again from an earlier
+// offset. Their retention should be managed by the storage, for example by
+// a lifecycle rule of the bucket, and be longer than the retention of the
+// topic, otherwise consumers fail to read reference messages which are
+// still in the topic. Objects are named by their commit ts, so the expired
+// ones can also be found by the prefix of their names.
+type ClaimCheck struct {
+	storage storage.ExternalStorage
+}
+
+// NewClaimCheck creates a ClaimCheck with the given storage URI.
+func NewClaimCheck(ctx context.Context, storageURI string) (*ClaimCheck, error) {
+	s, err := util.GetExternalStorageFromURI(ctx, storageURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	return &ClaimCheck{storage: s}, nil
+}
+
+// NewClaimCheckWithStorage creates a ClaimCheck with the given storage.
+func NewClaimCheckWithStorage(s storage.ExternalStorage) *ClaimCheck {
+	return &ClaimCheck{storage: s}
+}
+
+// WriteMessage writes the message to the storage, and returns the location
+// of the object, which is relative to the storage URI.
+func (c *ClaimCheck) WriteMessage(
+	ctx context.Context, key, value []byte, commitTs uint64,
+) (string, error) {
+	data, err := json.Marshal(&ClaimCheckMessage{Key: key, Value: value})
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	location := fmt.Sprintf("%d-%s.json", commitTs, uuid.New().String())
+	if err := c.storage.WriteFile(ctx, location, data); err != nil {
+		return "", cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	return location, nil
+}
+
+// ReadMessage reads the message at the location from the storage.
+func (c *ClaimCheck) ReadMessage(location string) (*ClaimCheckMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), claimCheckReadTimeout)
+	defer cancel()
+	data, err := c.storage.ReadFile(ctx, location)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrExternalStorageAPI, err)
+	}
+	msg := &ClaimCheckMessage{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	return msg, nil
+}
@@ -54,6 +54,16 @@ type Config struct {
 
 	// for open protocol
 	OnlyOutputUpdatedColumns bool
+
+	// for open protocol and canal-json, controls how messages larger
+	// than `MaxMessageBytes` are handled.
+	LargeMessageHandleOption string
+	ClaimCheckStorageURI     string
+
+	// for open protocol, canal-json and avro, outputs the transaction id, and
+	// the index and the count of rows in the transaction in the same table of
+	// each row.
+	EnableTxnBoundary bool
 }
 
 // NewConfig return a Config for codec
@@ -73,6 +83,8 @@ func NewConfig(protocol config.Protocol) *Config {
 		AvroEnableWatermark:            false,
 
 		OnlyOutputUpdatedColumns: false,
+
+		LargeMessageHandleOption: LargeMessageHandleOptionNone,
 	}
 }
 
@@ -89,6 +101,8 @@ const (
 	// confluent official consumer cannot handle watermark.
 	codecOPTAvroEnableWatermark      = "avro-enable-watermark"
 	codecOPTOnlyOutputUpdatedColumns = "only-output-updated-columns"
+	codecOPTLargeMessageHandleOption = "large-message-handle-option"
+	codecOPTClaimCheckStorageURI     = "claim-check-storage-uri"
 )
 
 const (
@@ -100,6 +114,13 @@ const (
 	BigintUnsignedHandlingModeString = "string"
 	// BigintUnsignedHandlingModeLong is the long mode for unsigned bigint handling
 	BigintUnsignedHandlingModeLong = "long"
+
+	// LargeMessageHandleOptionNone fails the changefeed if a message is too large.
+	LargeMessageHandleOptionNone = "none"
+	// LargeMessageHandleOptionClaimCheck writes a message which is too large to
+	// the claim check storage, and sends a reference message to the MQ instead.
+	// Objects in the claim check storage are not deleted, see ClaimCheck.
+	LargeMessageHandleOptionClaimCheck = "claim-check"
 )
 
 // Apply fill the Config
@@ -161,6 +182,7 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		}
 
 		c.OnlyOutputUpdatedColumns = replicaConfig.Sink.OnlyOutputUpdatedColumns
+		c.EnableTxnBoundary = replicaConfig.Sink.EnableTxnBoundary
 	}
 	if s := params.Get(codecOPTOnlyOutputUpdatedColumns); s != "" {
 		a, err := strconv.ParseBool(s)
@@ -176,6 +198,13 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		)
 	}
 
+	if s := params.Get(codecOPTLargeMessageHandleOption); s != "" {
+		c.LargeMessageHandleOption = s
+	}
+	if s := params.Get(codecOPTClaimCheckStorageURI); s != "" {
+		c.ClaimCheckStorageURI = s
+	}
+
 	if replicaConfig.Integrity != nil {
 		c.EnableRowChecksum = replicaConfig.Integrity.Enabled()
 	}
@@ -189,6 +218,11 @@ func (c *Config) WithMaxMessageBytes(bytes int) *Config {
 	return c
 }
 
+// ClaimCheckEnabled returns true if oversized messages are sent by claim check.
+func (c *Config) ClaimCheckEnabled() bool {
+	return c.LargeMessageHandleOption == LargeMessageHandleOptionClaimCheck
+}
+
 // Validate the Config
 func (c *Config) Validate() error {
 	if c.EnableTiDBExtension &&
@@ -240,6 +274,52 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	switch c.LargeMessageHandleOption {
+	case LargeMessageHandleOptionNone:
+	case LargeMessageHandleOptionClaimCheck:
+		if c.Protocol != config.ProtocolDefault && c.Protocol != config.ProtocolOpen &&
+			c.Protocol != config.ProtocolCanalJSON {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s "%s" only supports open-protocol and canal-json, `+
+					`other decoders can not read messages from the claim check storage`,
+				codecOPTLargeMessageHandleOption, LargeMessageHandleOptionClaimCheck)
+		}
+		if c.Protocol == config.ProtocolCanalJSON && !c.EnableTiDBExtension {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s "%s" requires "%s" to be "true" for canal-json`,
+				codecOPTLargeMessageHandleOption, LargeMessageHandleOptionClaimCheck,
+				codecOPTEnableTiDBExtension)
+		}
+		if c.ClaimCheckStorageURI == "" {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s "%s" requires parameter "%s"`,
+				codecOPTLargeMessageHandleOption, LargeMessageHandleOptionClaimCheck,
+				codecOPTClaimCheckStorageURI)
+		}
+	default:
+		return cerror.ErrCodecInvalidConfig.GenWithStack(
+			`%s value could only be "%s" or "%s"`,
+			codecOPTLargeMessageHandleOption,
+			LargeMessageHandleOptionNone,
+			LargeMessageHandleOptionClaimCheck)
+	}
+
+	if c.EnableTxnBoundary {
+		switch c.Protocol {
+		case config.ProtocolDefault, config.ProtocolOpen:
+		case config.ProtocolCanalJSON, config.ProtocolAvro:
+			if !c.EnableTiDBExtension {
+				return cerror.ErrCodecInvalidConfig.GenWithStack(
+					`enable-txn-boundary requires "%s" to be "true" for %s`,
+					codecOPTEnableTiDBExtension, c.Protocol.String())
+			}
+		default:
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`enable-txn-boundary only supports open-protocol, canal-json and avro, but got %s`,
+				c.Protocol.String())
+		}
+	}
+
 	if c.MaxMessageBytes <= 0 {
 		return cerror.ErrCodecInvalidConfig.Wrap(
 			errors.Errorf("invalid max-message-bytes %d", c.MaxMessageBytes),
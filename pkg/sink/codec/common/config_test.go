@@ -279,3 +279,94 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.ErrorContains(t, err, "invalid max-batch-size -1")
 }
+
+func TestConfigApplyValidate4ClaimCheck(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	validSinkURI := []string{
+		"kafka://127.0.0.1:9092/abc?protocol=open-protocol&" +
+			"large-message-handle-option=claim-check&claim-check-storage-uri=file%3A%2F%2F%2Ftmp%2Fcc",
+		"kafka://127.0.0.1:9092/abc?protocol=canal-json&enable-tidb-extension=true&" +
+			"large-message-handle-option=claim-check&claim-check-storage-uri=file%3A%2F%2F%2Ftmp%2Fcc",
+		"kafka://127.0.0.1:9092/abc?protocol=canal-json&large-message-handle-option=none",
+	}
+	for _, uri := range validSinkURI {
+		sinkURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		p, err := config.ParseSinkProtocolFromString(sinkURI.Query().Get("protocol"))
+		require.NoError(t, err)
+		c := NewConfig(p)
+		require.NoError(t, c.Apply(sinkURI, replicaConfig))
+		require.NoError(t, c.Validate(), uri)
+	}
+
+	sinkURI, err := url.Parse(validSinkURI[0])
+	require.NoError(t, err)
+	c := NewConfig(config.ProtocolOpen)
+	require.NoError(t, c.Apply(sinkURI, replicaConfig))
+	require.True(t, c.ClaimCheckEnabled())
+	require.Equal(t, "file:///tmp/cc", c.ClaimCheckStorageURI)
+
+	invalidSinkURI := []string{
+		// claim check storage is not set
+		"kafka://127.0.0.1:9092/abc?protocol=open-protocol&large-message-handle-option=claim-check",
+		// canal-json requires the tidb extension
+		"kafka://127.0.0.1:9092/abc?protocol=canal-json&" +
+			"large-message-handle-option=claim-check&claim-check-storage-uri=file%3A%2F%2F%2Ftmp%2Fcc",
+		// unsupported protocol
+		"kafka://127.0.0.1:9092/abc?protocol=maxwell&" +
+			"large-message-handle-option=claim-check&claim-check-storage-uri=file%3A%2F%2F%2Ftmp%2Fcc",
+		// unknown option
+		"kafka://127.0.0.1:9092/abc?protocol=open-protocol&large-message-handle-option=unknown",
+	}
+	for _, uri := range invalidSinkURI {
+		sinkURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		p, err := config.ParseSinkProtocolFromString(sinkURI.Query().Get("protocol"))
+		require.NoError(t, err)
+		c := NewConfig(p)
+		require.NoError(t, c.Apply(sinkURI, replicaConfig))
+		require.ErrorContains(t, c.Validate(), "large-message-handle-option", uri)
+	}
+}
+
+func TestConfigApplyValidate4TxnBoundary(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.EnableTxnBoundary = true
+	replicaConfig.Sink.SchemaRegistry = "http://127.0.0.1:8081"
+	validSinkURI := []string{
+		"kafka://127.0.0.1:9092/abc?protocol=open-protocol",
+		"kafka://127.0.0.1:9092/abc?protocol=canal-json&enable-tidb-extension=true",
+		"kafka://127.0.0.1:9092/abc?protocol=avro&enable-tidb-extension=true",
+	}
+	for _, uri := range validSinkURI {
+		sinkURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		p, err := config.ParseSinkProtocolFromString(sinkURI.Query().Get("protocol"))
+		require.NoError(t, err)
+		c := NewConfig(p)
+		require.NoError(t, c.Apply(sinkURI, replicaConfig))
+		require.True(t, c.EnableTxnBoundary)
+		require.NoError(t, c.Validate(), uri)
+	}
+
+	invalidSinkURI := []string{
+		// canal-json and avro require the tidb extension
+		"kafka://127.0.0.1:9092/abc?protocol=canal-json",
+		"kafka://127.0.0.1:9092/abc?protocol=avro",
+		// unsupported protocol
+		"kafka://127.0.0.1:9092/abc?protocol=maxwell",
+	}
+	for _, uri := range invalidSinkURI {
+		sinkURI, err := url.Parse(uri)
+		require.NoError(t, err)
+		p, err := config.ParseSinkProtocolFromString(sinkURI.Query().Get("protocol"))
+		require.NoError(t, err)
+		c := NewConfig(p)
+		require.NoError(t, c.Apply(sinkURI, replicaConfig))
+		require.ErrorContains(t, c.Validate(), "enable-txn-boundary", uri)
+	}
+}
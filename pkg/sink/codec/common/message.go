@@ -87,6 +87,11 @@ func NewResolvedMsg(proto config.Protocol, key, value []byte, ts uint64) *Messag
 	return NewMsg(proto, key, value, ts, model.MessageTypeResolved, nil, nil)
 }
 
+// NewSyncPointMsg creates a syncpoint message.
+func NewSyncPointMsg(proto config.Protocol, key, value []byte, ts uint64) *Message {
+	return NewMsg(proto, key, value, ts, model.MessageTypeSyncPoint, nil, nil)
+}
+
 // NewMsg should be used when creating a Message struct.
 // It copies the input byte slices to avoid any surprises in asynchronous MQ writes.
 func NewMsg(
@@ -145,6 +145,7 @@ func (b *batchDecoder) AddKeyValue(_, value []byte) error {
 	}
 	b.decoder = decoder
 	b.headers = headers
+	b.index = 0
 
 	return nil
 }
@@ -42,6 +42,12 @@ func (e *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 		NewResolvedEventEncoder(e.allocator, ts).Encode(), ts), nil
 }
 
+// EncodeSyncPointEvent implements the RowEventEncoder interface
+func (e *BatchEncoder) EncodeSyncPointEvent(ts uint64) (*common.Message, error) {
+	// There is no syncpoint event in craft so far, the event is ignored.
+	return nil, nil
+}
+
 // AppendRowChangedEvent implements the RowEventEncoder interface
 func (e *BatchEncoder) AppendRowChangedEvent(
 	_ context.Context,
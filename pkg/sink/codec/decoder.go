@@ -28,7 +28,8 @@ type RowEventDecoder interface {
 	//     2. a bool if the next event is exist
 	//     3. error
 	HasNext() (model.MessageType, bool, error)
-	// NextResolvedEvent returns the next resolved event if exists
+	// NextResolvedEvent returns the next resolved event if exists,
+	// it also returns the primary ts of the next syncpoint event.
 	NextResolvedEvent() (uint64, error)
 	// NextRowChangedEvent returns the next row changed event if exists
 	NextRowChangedEvent() (*model.RowChangedEvent, error)
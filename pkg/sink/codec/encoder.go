@@ -31,6 +31,10 @@ type DDLEventBatchEncoder interface {
 	// EncodeCheckpointEvent appends a checkpoint event into the batch.
 	// This event will be broadcast to all partitions to signal a global checkpoint.
 	EncodeCheckpointEvent(ts uint64) (*common.Message, error)
+	// EncodeSyncPointEvent encodes a syncpoint event, which will be broadcast
+	// to all partitions to mark that all events before or at the ts are sent.
+	// It returns nil if the protocol does not support syncpoint events.
+	EncodeSyncPointEvent(ts uint64) (*common.Message, error)
 	// EncodeDDLEvent appends a DDL event into the batch
 	EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error)
 }
@@ -28,6 +28,15 @@ type MessageKey struct {
 	RowID     int64             `json:"rid,omitempty"`
 	Partition *int64            `json:"ptn,omitempty"`
 	Type      model.MessageType `json:"t"`
+	// ClaimCheckLocation is set if the message is a reference to a message
+	// written to the claim check storage.
+	ClaimCheckLocation string `json:"ccl,omitempty"`
+	// TxnID, TxnTableRowIndex and TxnTableRowCount are the transaction
+	// boundary of the row in its table, they are set if transaction
+	// boundaries are enabled.
+	TxnID            uint64 `json:"tid,omitempty"`
+	TxnTableRowIndex int    `json:"ttri,omitempty"`
+	TxnTableRowCount int    `json:"ttrc,omitempty"`
 }
 
 // Encode encodes the message key to a byte slice.
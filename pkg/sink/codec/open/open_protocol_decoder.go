@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 )
 
@@ -49,7 +50,7 @@ func (b *BatchMixedDecoder) NextResolvedEvent() (uint64, error) {
 		}
 	}
 	b.mixedBytes = b.mixedBytes[b.nextKeyLen+8:]
-	if b.nextKey.Type != model.MessageTypeResolved {
+	if b.nextKey.Type != model.MessageTypeResolved && b.nextKey.Type != model.MessageTypeSyncPoint {
 		return 0, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found resolved event message")
 	}
 	valueLen := binary.BigEndian.Uint64(b.mixedBytes[:8])
@@ -129,6 +130,8 @@ type BatchDecoder struct {
 	valueBytes []byte
 	nextKey    *internal.MessageKey
 	nextKeyLen uint64
+
+	claimCheck *common.ClaimCheck
 }
 
 // HasNext implements the RowEventDecoder interface
@@ -150,7 +153,7 @@ func (b *BatchDecoder) NextResolvedEvent() (uint64, error) {
 		}
 	}
 	b.keyBytes = b.keyBytes[b.nextKeyLen+8:]
-	if b.nextKey.Type != model.MessageTypeResolved {
+	if b.nextKey.Type != model.MessageTypeResolved && b.nextKey.Type != model.MessageTypeSyncPoint {
 		return 0, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack("not found resolved event message")
 	}
 	valueLen := binary.BigEndian.Uint64(b.valueBytes[:8])
@@ -174,15 +177,41 @@ func (b *BatchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 	valueLen := binary.BigEndian.Uint64(b.valueBytes[:8])
 	value := b.valueBytes[8 : valueLen+8]
 	b.valueBytes = b.valueBytes[valueLen+8:]
+	key := b.nextKey
+	if key.ClaimCheckLocation != "" {
+		var err error
+		key, value, err = b.fetchClaimCheckMessage(key.ClaimCheckLocation)
+		if err != nil {
+			return nil, err
+		}
+	}
 	rowMsg := new(messageRow)
 	if err := rowMsg.decode(value); err != nil {
 		return nil, errors.Trace(err)
 	}
-	rowEvent := msgToRowChange(b.nextKey, rowMsg)
+	rowEvent := msgToRowChange(key, rowMsg)
 	b.nextKey = nil
 	return rowEvent, nil
 }
 
+// fetchClaimCheckMessage reads the key and value of a message which was
+// sent by claim check.
+func (b *BatchDecoder) fetchClaimCheckMessage(location string) (*internal.MessageKey, []byte, error) {
+	if b.claimCheck == nil {
+		return nil, nil, cerror.ErrOpenProtocolCodecInvalidData.
+			GenWithStack("claim check message found, but claim check storage is not set")
+	}
+	msg, err := b.claimCheck.ReadMessage(location)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	key := new(internal.MessageKey)
+	if err := key.Decode(msg.Key); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return key, msg.Value, nil
+}
+
 // NextDDLEvent implements the RowEventDecoder interface
 func (b *BatchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
 	if b.nextKey == nil {
@@ -223,10 +252,10 @@ func (b *BatchDecoder) decodeNextKey() error {
 	return nil
 }
 
-// NewBatchDecoder creates a new BatchDecoder.
-func NewBatchDecoder() codec.RowEventDecoder {
-	return &BatchDecoder{}
-
+// NewBatchDecoder creates a new BatchDecoder. claimCheck is used to fetch
+// messages sent by claim check, it can be nil if claim check is not enabled.
+func NewBatchDecoder(claimCheck *common.ClaimCheck) codec.RowEventDecoder {
+	return &BatchDecoder{claimCheck: claimCheck}
 }
 
 // AddKeyValue implements the RowEventDecoder interface
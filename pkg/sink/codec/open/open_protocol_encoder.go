@@ -25,6 +25,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 	"go.uber.org/zap"
 )
 
@@ -38,11 +39,15 @@ type BatchEncoder struct {
 	MaxMessageBytes          int
 	MaxBatchSize             int
 	OnlyOutputUpdatedColumns bool
+
+	// claimCheck is set if messages larger than MaxMessageBytes
+	// are sent by claim check.
+	claimCheck *common.ClaimCheck
 }
 
 // AppendRowChangedEvent implements the RowEventEncoder interface
 func (d *BatchEncoder) AppendRowChangedEvent(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	e *model.RowChangedEvent,
 	callback func(),
@@ -57,14 +62,26 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 		return errors.Trace(err)
 	}
 
-	var keyLenByte [8]byte
-	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
-	var valueLenByte [8]byte
-	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
-
 	// for single message that is longer than max-message-bytes, do not send it.
 	// 16 is the length of `keyLenByte` and `valueLenByte`, 8 is the length of `versionHead`
 	length := len(key) + len(value) + common.MaxRecordOverhead + 16 + 8
+	if length > d.MaxMessageBytes && d.claimCheck != nil {
+		// send a reference to the message written to the claim check storage instead.
+		keyMsg.ClaimCheckLocation, err = d.claimCheck.WriteMessage(ctx, key, value, e.CommitTs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Single message is too large for open-protocol, send it by claim check",
+			zap.Int("maxMessageBytes", d.MaxMessageBytes),
+			zap.Int("length", length),
+			zap.Any("table", e.Table),
+			zap.String("location", keyMsg.ClaimCheckLocation))
+		if key, err = keyMsg.Encode(); err != nil {
+			return errors.Trace(err)
+		}
+		value = nil
+		length = len(key) + common.MaxRecordOverhead + 16 + 8
+	}
 	if length > d.MaxMessageBytes {
 		log.Warn("Single message is too large for open-protocol",
 			zap.Int("maxMessageBytes", d.MaxMessageBytes),
@@ -75,6 +92,11 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 		return cerror.ErrMessageTooLarge.GenWithStackByArgs()
 	}
 
+	var keyLenByte [8]byte
+	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
+	var valueLenByte [8]byte
+	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
+
 	if len(d.messageBuf) == 0 ||
 		d.curBatchSize >= d.MaxBatchSize ||
 		d.messageBuf[len(d.messageBuf)-1].Length()+len(key)+len(value)+16 > d.MaxMessageBytes {
@@ -140,11 +162,28 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 
 // EncodeCheckpointEvent implements the RowEventEncoder interface
 func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
-	keyMsg := newResolvedMessage(ts)
-	key, err := keyMsg.Encode()
+	key, value, err := encodeKeyOnlyMessage(newResolvedMessage(ts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewResolvedMsg(config.ProtocolOpen, key, value, ts), nil
+}
+
+// EncodeSyncPointEvent implements the RowEventEncoder interface
+func (d *BatchEncoder) EncodeSyncPointEvent(ts uint64) (*common.Message, error) {
+	key, value, err := encodeKeyOnlyMessage(newSyncPointMessage(ts))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	return common.NewSyncPointMsg(config.ProtocolOpen, key, value, ts), nil
+}
+
+// encodeKeyOnlyMessage encodes a batch which contains a message with an empty value.
+func encodeKeyOnlyMessage(keyMsg *internal.MessageKey) ([]byte, []byte, error) {
+	key, err := keyMsg.Encode()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 
 	var keyLenByte [8]byte
 	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
@@ -161,8 +200,7 @@ func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error)
 	valueBuf := new(bytes.Buffer)
 	valueBuf.Write(valueLenByte[:])
 
-	ret := common.NewResolvedMsg(config.ProtocolOpen, keyBuf.Bytes(), valueBuf.Bytes(), ts)
-	return ret, nil
+	return keyBuf.Bytes(), valueBuf.Bytes(), nil
 }
 
 // Build implements the RowEventEncoder interface
@@ -188,7 +226,8 @@ func (d *BatchEncoder) tryBuildCallback() {
 }
 
 type batchEncoderBuilder struct {
-	config *common.Config
+	config     *common.Config
+	claimCheck *common.ClaimCheck
 }
 
 // Build a BatchEncoder
@@ -197,13 +236,24 @@ func (b *batchEncoderBuilder) Build() codec.RowEventEncoder {
 	encoder.(*BatchEncoder).MaxMessageBytes = b.config.MaxMessageBytes
 	encoder.(*BatchEncoder).MaxBatchSize = b.config.MaxBatchSize
 	encoder.(*BatchEncoder).OnlyOutputUpdatedColumns = b.config.OnlyOutputUpdatedColumns
+	encoder.(*BatchEncoder).claimCheck = b.claimCheck
 
 	return encoder
 }
 
 // NewBatchEncoderBuilder creates an open-protocol batchEncoderBuilder.
-func NewBatchEncoderBuilder(config *common.Config) codec.RowEventEncoderBuilder {
-	return &batchEncoderBuilder{config: config}
+func NewBatchEncoderBuilder(
+	ctx context.Context, config *common.Config,
+) (codec.RowEventEncoderBuilder, error) {
+	var claimCheck *common.ClaimCheck
+	if config.ClaimCheckEnabled() {
+		var err error
+		claimCheck, err = common.NewClaimCheck(ctx, config.ClaimCheckStorageURI)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &batchEncoderBuilder{config: config, claimCheck: claimCheck}, nil
 }
 
 // NewBatchEncoder creates a new BatchEncoder.
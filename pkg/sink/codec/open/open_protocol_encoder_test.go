@@ -15,6 +15,8 @@ package open
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/parser/mysql"
@@ -54,19 +56,21 @@ func TestMaxMessageBytes(t *testing.T) {
 	// for a single message, the overhead is 36(maxRecordOverhead) + 8(versionHea) = 44, just can hold it.
 	a := 88 + 44
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(a)
-	encoder := NewBatchEncoderBuilder(config).Build()
-	err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
+	builder, err := NewBatchEncoderBuilder(ctx, config)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.Nil(t, err)
 
 	// cannot hold a single message
 	config = config.WithMaxMessageBytes(a - 1)
-	encoder = NewBatchEncoderBuilder(config).Build()
+	encoder = builder.Build()
 	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.NotNil(t, err)
 
 	// make sure each batch's `Length` not greater than `max-message-bytes`
 	config = config.WithMaxMessageBytes(256)
-	encoder = NewBatchEncoderBuilder(config).Build()
+	encoder = builder.Build()
 	for i := 0; i < 10000; i++ {
 		err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 		require.Nil(t, err)
@@ -82,7 +86,9 @@ func TestMaxBatchSize(t *testing.T) {
 	t.Parallel()
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(1048576)
 	config.MaxBatchSize = 64
-	encoder := NewBatchEncoderBuilder(config).Build()
+	builder, err := NewBatchEncoderBuilder(context.Background(), config)
+	require.NoError(t, err)
+	encoder := builder.Build()
 
 	testEvent := &model.RowChangedEvent{
 		CommitTs: 1,
@@ -100,7 +106,7 @@ func TestMaxBatchSize(t *testing.T) {
 	}
 
 	messages := encoder.Build()
-	decoder := NewBatchDecoder()
+	decoder := NewBatchDecoder(nil)
 	sum := 0
 	for _, msg := range messages {
 		err := decoder.AddKeyValue(msg.Key, msg.Value)
@@ -207,11 +213,142 @@ func TestOpenProtocolAppendRowChangedEventWithCallback(t *testing.T) {
 func TestOpenProtocolBatchCodec(t *testing.T) {
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(8192)
 	config.MaxBatchSize = 64
+	builder, err := NewBatchEncoderBuilder(context.Background(), config)
+	require.NoError(t, err)
 	tester := internal.NewDefaultBatchTester()
-	tester.TestBatchCodec(t, NewBatchEncoderBuilder(config),
+	tester.TestBatchCodec(t, builder,
 		func(key []byte, value []byte) (codec.RowEventDecoder, error) {
-			decoder := NewBatchDecoder()
+			decoder := NewBatchDecoder(nil)
 			err := decoder.AddKeyValue(key, value)
 			return decoder, err
 		})
 }
+
+func TestEncodeSyncPointEvent(t *testing.T) {
+	t.Parallel()
+	msg, err := NewBatchEncoder().EncodeSyncPointEvent(2333)
+	require.NoError(t, err)
+	require.Equal(t, model.MessageTypeSyncPoint, msg.Type)
+
+	decoder := NewBatchDecoder(nil)
+	require.NoError(t, decoder.AddKeyValue(msg.Key, msg.Value))
+	ty, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeSyncPoint, ty)
+	ts, err := decoder.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2333), ts)
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+}
+
+func TestClaimCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	largeEvent := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte(strings.Repeat("a", 1024)),
+		}},
+	}
+	smallEvent := &model.RowChangedEvent{
+		CommitTs: 2,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte("aa"),
+		}},
+	}
+
+	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(512)
+	config.LargeMessageHandleOption = common.LargeMessageHandleOptionClaimCheck
+	config.ClaimCheckStorageURI = "file://" + t.TempDir()
+	builder, err := NewBatchEncoderBuilder(ctx, config)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", largeEvent, nil))
+	require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", smallEvent, nil))
+	messages := encoder.Build()
+	for _, msg := range messages {
+		require.LessOrEqual(t, msg.Length(), 512)
+	}
+
+	claimCheck, err := common.NewClaimCheck(ctx, config.ClaimCheckStorageURI)
+	require.NoError(t, err)
+	decoder := NewBatchDecoder(claimCheck)
+	var decoded []*model.RowChangedEvent
+	for _, msg := range messages {
+		require.NoError(t, decoder.AddKeyValue(msg.Key, msg.Value))
+		for {
+			tp, hasNext, err := decoder.HasNext()
+			require.NoError(t, err)
+			if !hasNext {
+				break
+			}
+			require.Equal(t, model.MessageTypeRow, tp)
+			event, err := decoder.NextRowChangedEvent()
+			require.NoError(t, err)
+			decoded = append(decoded, event)
+		}
+	}
+	require.Len(t, decoded, 2)
+	for i, expected := range []*model.RowChangedEvent{largeEvent, smallEvent} {
+		require.Equal(t, expected.CommitTs, decoded[i].CommitTs)
+		require.Equal(t, expected.Table, decoded[i].Table)
+		require.Equal(t, fmt.Sprint(expected.Columns[0].Value), fmt.Sprint(decoded[i].Columns[0].Value))
+	}
+
+	// the decoder without claim check storage can't decode the reference message.
+	decoder = NewBatchDecoder(nil)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	_, err = decoder.NextRowChangedEvent()
+	require.Error(t, err)
+
+	// the message is still too large if claim check is disabled.
+	config.LargeMessageHandleOption = common.LargeMessageHandleOptionNone
+	builder, err = NewBatchEncoderBuilder(ctx, config)
+	require.NoError(t, err)
+	err = builder.Build().AppendRowChangedEvent(ctx, "", largeEvent, nil)
+	require.ErrorContains(t, err, "ErrMessageTooLarge")
+}
+
+func TestTxnBoundary(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	builder, err := NewBatchEncoderBuilder(ctx, common.NewConfig(config.ProtocolOpen))
+	require.NoError(t, err)
+	encoder := builder.Build()
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, encoder.AppendRowChangedEvent(ctx, "", &model.RowChangedEvent{
+			StartTs:     5,
+			CommitTs:    10,
+			Table:       &model.TableName{Schema: "a", Table: "b"},
+			Columns:     []*model.Column{{Name: "col1", Type: mysql.TypeLong, Value: int64(i)}},
+			TxnBoundary: &model.TxnBoundary{TableRowIndex: i, TableRowCount: 2},
+		}, nil))
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	decoder := NewBatchDecoder(nil)
+	require.NoError(t, decoder.AddKeyValue(messages[0].Key, messages[0].Value))
+	for i := 1; i <= 2; i++ {
+		tp, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+		event, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), event.StartTs)
+		require.Equal(t, &model.TxnBoundary{TableRowIndex: i, TableRowCount: 2}, event.TxnBoundary)
+	}
+}
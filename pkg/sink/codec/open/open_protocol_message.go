@@ -94,6 +94,13 @@ func newResolvedMessage(ts uint64) *internal.MessageKey {
 	}
 }
 
+func newSyncPointMessage(ts uint64) *internal.MessageKey {
+	return &internal.MessageKey{
+		Ts:   ts,
+		Type: model.MessageTypeSyncPoint,
+	}
+}
+
 func rowChangeToMsg(e *model.RowChangedEvent) (*internal.MessageKey, *messageRow) {
 	var partition *int64
 	if e.Table.IsPartition {
@@ -107,6 +114,11 @@ func rowChangeToMsg(e *model.RowChangedEvent) (*internal.MessageKey, *messageRow
 		Partition: partition,
 		Type:      model.MessageTypeRow,
 	}
+	if e.TxnBoundary != nil {
+		key.TxnID = e.StartTs
+		key.TxnTableRowIndex = e.TxnBoundary.TableRowIndex
+		key.TxnTableRowCount = e.TxnBoundary.TableRowCount
+	}
 	value := &messageRow{}
 	if e.IsDelete() {
 		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns)
@@ -122,6 +134,13 @@ func msgToRowChange(key *internal.MessageKey, value *messageRow) *model.RowChang
 	// TODO: we lost the startTs from kafka message
 	// startTs-based txn filter is out of work
 	e.CommitTs = key.Ts
+	if key.TxnTableRowCount != 0 {
+		e.StartTs = key.TxnID
+		e.TxnBoundary = &model.TxnBoundary{
+			TableRowIndex: key.TxnTableRowIndex,
+			TableRowCount: key.TxnTableRowCount,
+		}
+	}
 	e.Table = &model.TableName{
 		Schema: key.Schema,
 		Table:  key.Table,
@@ -0,0 +1,563 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package consumer implements a library to replay messages written by the
+// TiCDC MQ sink to a downstream, which is used by cmd/kafka-consumer.
+package consumer
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dispatcher"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"go.uber.org/zap"
+)
+
+const defaultFlushInterval = 100 * time.Millisecond
+
+// Message is a message consumed from a partition of the MQ.
+type Message struct {
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+}
+
+// Handler handles events decoded from the MQ.
+//
+// AppendRowChangedEvents is called by goroutines consuming partitions, and
+// other methods are called by Consumer.Run, so they can be called concurrently.
+type Handler interface {
+	// AppendRowChangedEvents appends resolved events of a table, which are
+	// sorted by commit ts. Events can be buffered until they are flushed.
+	AppendRowChangedEvents(ctx context.Context, tableID int64, events []*model.RowChangedEvent) error
+	// FlushRowChangedEvents writes all appended events whose commit ts are
+	// less than or equal to resolvedTs to the downstream.
+	FlushRowChangedEvents(ctx context.Context, resolvedTs uint64) error
+	// WriteDDLEvent executes the DDL in the downstream. All events before
+	// the DDL are flushed when it's called.
+	WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error
+}
+
+// SyncPointHandler is an optional interface of Handler, which handles
+// syncpoint markers sent by the MQ sink if syncpoint is enabled.
+type SyncPointHandler interface {
+	// WriteSyncPoint is called after all events before or at the primary ts
+	// are flushed, and before any event after it is flushed, so that the
+	// downstream has a consistent view of all tables at the primary ts.
+	WriteSyncPoint(ctx context.Context, primaryTs uint64) error
+}
+
+// Config is the configuration of Consumer.
+type Config struct {
+	// PartitionNum is the number of partitions of the topic.
+	PartitionNum int32
+	// NewDecoder creates a decoder for a partition, see NewDecoderFunc.
+	NewDecoder func() (codec.RowEventDecoder, error)
+	// EventRouter is used to check that row changed events are dispatched
+	// to the expected partitions. It's optional.
+	EventRouter *dispatcher.EventRouter
+	// MaxMessageBytes and MaxBatchSize are used to check that messages don't
+	// exceed limits of the sink. They are not checked if they are 0.
+	MaxMessageBytes int
+	MaxBatchSize    int
+	// FlushInterval is the interval of advancing the global resolved ts.
+	FlushInterval time.Duration
+}
+
+// Consumer consumes messages written by the TiCDC MQ sink, and replays them
+// to a Handler. Row changed events are grouped by tables and appended to the
+// handler after they are resolved in their partitions. They are flushed with
+// the global resolved ts, which is the min resolved ts of all partitions.
+// DDLs are executed after all events before them are flushed.
+//
+// The offset of a message can be committed after all events in it are
+// flushed, see CommittableOffset, so messages are consumed at least once.
+type Consumer struct {
+	config     *Config
+	handler    Handler
+	partitions []*partitionState
+
+	tableIDGenerator *fakeTableIDGenerator
+
+	ddlMu              sync.Mutex
+	ddlList            []*model.DDLEvent
+	ddlWithMaxCommitTs *model.DDLEvent
+	// syncPoints are primary ts of syncpoints wait to be handled, they are
+	// also guarded by ddlMu.
+	syncPoints     []uint64
+	maxSyncPointTs uint64
+
+	globalResolvedTs atomic.Uint64
+}
+
+// New creates a Consumer.
+func New(config *Config, handler Handler) (*Consumer, error) {
+	if config.PartitionNum <= 0 {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack(
+			"invalid partition number %d", config.PartitionNum)
+	}
+	if config.NewDecoder == nil {
+		return nil, cerror.ErrKafkaInvalidConfig.GenWithStack("decoder is not set")
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+	c := &Consumer{
+		config:           config,
+		handler:          handler,
+		partitions:       make([]*partitionState, config.PartitionNum),
+		tableIDGenerator: newFakeTableIDGenerator(),
+	}
+	for i := range c.partitions {
+		decoder, err := config.NewDecoder()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.partitions[i] = newPartitionState(int32(i), decoder)
+	}
+	return c, nil
+}
+
+func (c *Consumer) getPartition(partition int32) (*partitionState, error) {
+	if partition < 0 || int(partition) >= len(c.partitions) {
+		return nil, cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+			"partition out of range")
+	}
+	return c.partitions[partition], nil
+}
+
+// AddMessage decodes the message and handles events in it. Messages of a
+// partition must be added by one goroutine in the order of offsets.
+func (c *Consumer) AddMessage(ctx context.Context, message *Message) error {
+	p, err := c.getPartition(message.Partition)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := p.decoder.AddKeyValue(message.Key, message.Value); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Events of a redelivered message which are resolved by the partition
+	// are written to the handler already.
+	replayed := p.isReplayed(message.Offset)
+	// maxCommitTs is the max commit ts of events in the message which must be
+	// flushed before the offset of the message is committed.
+	maxCommitTs := uint64(0)
+	counter := 0
+	for {
+		tp, hasNext, err := p.decoder.HasNext()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !hasNext {
+			break
+		}
+
+		counter++
+		// If the message containing only one event exceeds the length limit, CDC will allow it and issue a warning.
+		if c.config.MaxMessageBytes > 0 && counter > 1 &&
+			len(message.Key)+len(message.Value) > c.config.MaxMessageBytes {
+			return cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+				"max-message-bytes exceeded")
+		}
+
+		switch tp {
+		case model.MessageTypeDDL:
+			// for some protocol, DDL would be dispatched to all partitions,
+			// Consider that DDL a, b, c received from partition-0, the latest DDL is c,
+			// if we receive `a` from partition-1, which would be seemed as DDL regression,
+			// then cause the consumer fails, but it was a duplicate one.
+			// so we only handle DDL received from partition-0 should be enough.
+			// but all DDL event messages should be consumed.
+			ddl, err := p.decoder.NextDDLEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if p.partition == 0 && !(replayed && c.isDuplicateDDL(ddl)) {
+				added, err := c.appendDDL(ddl)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if added && ddl.CommitTs > maxCommitTs {
+					maxCommitTs = ddl.CommitTs
+				}
+			}
+		case model.MessageTypeRow:
+			row, err := p.decoder.NextRowChangedEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err := c.checkPartition(row, p.partition); err != nil {
+				return errors.Trace(err)
+			}
+			if replayed && row.CommitTs <= p.resolvedTs.Load() {
+				log.Debug("ignore redelivered row",
+					zap.Uint64("commitTs", row.CommitTs),
+					zap.Int32("partition", p.partition))
+				break
+			}
+			commitTs := c.appendRow(p, row)
+			if commitTs > maxCommitTs {
+				maxCommitTs = commitTs
+			}
+		case model.MessageTypeResolved:
+			ts, err := p.decoder.NextResolvedEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if replayed && ts <= p.resolvedTs.Load() {
+				log.Debug("ignore redelivered partition resolved ts",
+					zap.Uint64("ts", ts), zap.Int32("partition", p.partition))
+				break
+			}
+			if err := c.resolve(ctx, p, ts); err != nil {
+				return errors.Trace(err)
+			}
+		case model.MessageTypeSyncPoint:
+			// syncpoints are broadcast to all partitions like DDLs, so we
+			// only handle syncpoints received from partition-0.
+			ts, err := p.decoder.NextResolvedEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if p.partition == 0 && c.appendSyncPoint(ts) && ts > maxCommitTs {
+				maxCommitTs = ts
+			}
+			// All events before the syncpoint are sent to the partition,
+			// so it also resolves the partition.
+			if ts > p.resolvedTs.Load() {
+				if err := c.resolve(ctx, p, ts); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+
+	if c.config.MaxBatchSize > 0 && counter > c.config.MaxBatchSize {
+		return cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+			"max-batch-size exceeded")
+	}
+	p.addOffset(message.Offset, maxCommitTs)
+	return nil
+}
+
+func (c *Consumer) checkPartition(row *model.RowChangedEvent, partition int32) error {
+	if c.config.EventRouter == nil {
+		return nil
+	}
+	target := c.config.EventRouter.GetPartitionForRowChange(row, c.config.PartitionNum)
+	if partition != target {
+		log.Error("RowChangedEvent dispatched to wrong partition",
+			zap.Int32("obtained", partition),
+			zap.Int32("expected", target),
+			zap.Int32("partitionNum", c.config.PartitionNum),
+			zap.Any("row", row))
+		return cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+			"row changed event dispatched to wrong partition")
+	}
+	return nil
+}
+
+// appendRow adds the row to the events group of its table. It returns the
+// commit ts which must be flushed before the row is written to the handler.
+func (c *Consumer) appendRow(p *partitionState, row *model.RowChangedEvent) uint64 {
+	globalResolvedTs := c.globalResolvedTs.Load()
+	resolvedTs := p.resolvedTs.Load()
+	if row.CommitTs <= globalResolvedTs || row.CommitTs <= resolvedTs {
+		log.Warn("RowChangedEvent fallback row, ignore it",
+			zap.Uint64("commitTs", row.CommitTs),
+			zap.Uint64("globalResolvedTs", globalResolvedTs),
+			zap.Uint64("partitionResolvedTs", resolvedTs),
+			zap.Int32("partition", p.partition),
+			zap.Any("row", row))
+	}
+	var partitionID int64
+	if row.Table.IsPartition {
+		partitionID = row.Table.TableID
+	}
+	tableID := c.tableIDGenerator.generateFakeTableID(row.Table.Schema, row.Table.Table, partitionID)
+	row.Table.TableID = tableID
+
+	group, ok := p.groups[tableID]
+	if !ok {
+		group = newEventsGroup()
+		p.groups[tableID] = group
+	}
+	group.Append(row)
+
+	// A fallback row is written to the handler with the next resolved ts of
+	// the partition, so it's flushed with a greater global resolved ts.
+	if row.CommitTs <= resolvedTs {
+		return resolvedTs + 1
+	}
+	return row.CommitTs
+}
+
+// resolve writes events resolved by the ts of the partition to the handler.
+func (c *Consumer) resolve(ctx context.Context, p *partitionState, ts uint64) error {
+	resolvedTs := p.resolvedTs.Load()
+	// `resolvedTs` should be monotonically increasing, it's allowed to receive redundant one.
+	if ts < resolvedTs {
+		log.Error("partition resolved ts fallback",
+			zap.Uint64("ts", ts),
+			zap.Uint64("resolvedTs", resolvedTs),
+			zap.Int32("partition", p.partition))
+		return cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+			"partition resolved ts fallback")
+	}
+	if ts == resolvedTs {
+		log.Info("redundant partition resolved ts",
+			zap.Uint64("ts", ts), zap.Int32("partition", p.partition))
+		return nil
+	}
+	for tableID, group := range p.groups {
+		events := group.Resolve(ts)
+		if len(events) == 0 {
+			continue
+		}
+		if err := c.handler.AppendRowChangedEvents(ctx, tableID, events); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	log.Debug("update partition resolved ts",
+		zap.Uint64("ts", ts), zap.Int32("partition", p.partition))
+	p.resolvedTs.Store(ts)
+	return nil
+}
+
+// appendDDL appends DDL wait to be handled, only consider the constraint among DDLs.
+// for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
+// It returns false if the DDL is redundant.
+func (c *Consumer) appendDDL(ddl *model.DDLEvent) (bool, error) {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	// DDL CommitTs fallback, it indicates a bug.
+	if c.ddlWithMaxCommitTs != nil && ddl.CommitTs < c.ddlWithMaxCommitTs.CommitTs {
+		log.Error("DDL CommitTs < maxCommitTsDDL.CommitTs",
+			zap.Uint64("commitTs", ddl.CommitTs),
+			zap.Uint64("maxCommitTs", c.ddlWithMaxCommitTs.CommitTs),
+			zap.Any("DDL", ddl))
+		return false, cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+			"DDL commit ts fallback")
+	}
+
+	// A rename tables DDL job contains multiple DDL events with same CommitTs.
+	// So to tell if a DDL is redundant or not, we must check the equivalence of
+	// the current DDL and the DDL with max CommitTs.
+	if c.ddlWithMaxCommitTs != nil && ddl.CommitTs == c.ddlWithMaxCommitTs.CommitTs &&
+		ddl.Query == c.ddlWithMaxCommitTs.Query {
+		log.Info("ignore redundant DDL, the DDL is equal to ddlWithMaxCommitTs",
+			zap.Any("DDL", ddl))
+		return false, nil
+	}
+
+	c.ddlList = append(c.ddlList, ddl)
+	log.Info("DDL event received", zap.Any("DDL", ddl))
+	c.ddlWithMaxCommitTs = ddl
+	return true, nil
+}
+
+// isDuplicateDDL returns true if the DDL is received before.
+func (c *Consumer) isDuplicateDDL(ddl *model.DDLEvent) bool {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	return c.ddlWithMaxCommitTs != nil && ddl.CommitTs < c.ddlWithMaxCommitTs.CommitTs
+}
+
+func (c *Consumer) getFrontDDL() *model.DDLEvent {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	if len(c.ddlList) > 0 {
+		return c.ddlList[0]
+	}
+	return nil
+}
+
+func (c *Consumer) popDDL() {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	if len(c.ddlList) > 0 {
+		c.ddlList = c.ddlList[1:]
+	}
+}
+
+// appendSyncPoint appends a syncpoint wait to be handled.
+// It returns false if the syncpoint is redundant.
+func (c *Consumer) appendSyncPoint(ts uint64) bool {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	if ts <= c.maxSyncPointTs {
+		log.Info("ignore redundant syncpoint", zap.Uint64("primaryTs", ts),
+			zap.Uint64("maxSyncPointTs", c.maxSyncPointTs))
+		return false
+	}
+	c.syncPoints = append(c.syncPoints, ts)
+	c.maxSyncPointTs = ts
+	log.Info("syncpoint received", zap.Uint64("primaryTs", ts))
+	return true
+}
+
+func (c *Consumer) getFrontSyncPoint() (uint64, bool) {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	if len(c.syncPoints) > 0 {
+		return c.syncPoints[0], true
+	}
+	return 0, false
+}
+
+func (c *Consumer) popSyncPoint() {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	if len(c.syncPoints) > 0 {
+		c.syncPoints = c.syncPoints[1:]
+	}
+}
+
+func (c *Consumer) getMinPartitionResolvedTs() uint64 {
+	result := uint64(math.MaxUint64)
+	for _, p := range c.partitions {
+		if ts := p.resolvedTs.Load(); ts < result {
+			result = ts
+		}
+	}
+	return result
+}
+
+// Run advances the global resolved ts, flushes events and executes DDLs
+// until the context is canceled or an error occurs.
+func (c *Consumer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := c.tick(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+func (c *Consumer) tick(ctx context.Context) error {
+	minPartitionResolvedTs := c.getMinPartitionResolvedTs()
+
+	// handle syncpoint, it's handled after DDLs before or at it,
+	// and before DDLs after it.
+	todoDDL := c.getFrontDDL()
+	syncPointTs, ok := c.getFrontSyncPoint()
+	if ok && syncPointTs <= minPartitionResolvedTs &&
+		(todoDDL == nil || syncPointTs < todoDDL.CommitTs) {
+		// flush DMLs
+		if err := c.handler.FlushRowChangedEvents(ctx, syncPointTs); err != nil {
+			return errors.Trace(err)
+		}
+		if h, ok := c.handler.(SyncPointHandler); ok {
+			if err := h.WriteSyncPoint(ctx, syncPointTs); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		c.popSyncPoint()
+		minPartitionResolvedTs = syncPointTs
+		// the next DDL is handled in the next tick.
+		todoDDL = nil
+	}
+
+	// handle DDL
+	if todoDDL != nil && todoDDL.CommitTs <= minPartitionResolvedTs {
+		// flush DMLs
+		if err := c.handler.FlushRowChangedEvents(ctx, todoDDL.CommitTs); err != nil {
+			return errors.Trace(err)
+		}
+		// DDL can be executed, do it first.
+		if err := c.handler.WriteDDLEvent(ctx, todoDDL); err != nil {
+			return errors.Trace(err)
+		}
+		c.popDDL()
+
+		if todoDDL.CommitTs < minPartitionResolvedTs {
+			log.Info("update minPartitionResolvedTs by DDL",
+				zap.Uint64("minPartitionResolvedTs", minPartitionResolvedTs),
+				zap.Any("DDL", todoDDL))
+		}
+		minPartitionResolvedTs = todoDDL.CommitTs
+	}
+
+	// update global resolved ts
+	globalResolvedTs := c.globalResolvedTs.Load()
+	if globalResolvedTs > minPartitionResolvedTs {
+		log.Error("global ResolvedTs fallback",
+			zap.Uint64("globalResolvedTs", globalResolvedTs),
+			zap.Uint64("minPartitionResolvedTs", minPartitionResolvedTs))
+		return cerror.ErrMQConsumerInvalidEvent.GenWithStackByArgs(
+			"global resolved ts fallback")
+	}
+	if globalResolvedTs == minPartitionResolvedTs {
+		return nil
+	}
+
+	if err := c.handler.FlushRowChangedEvents(ctx, minPartitionResolvedTs); err != nil {
+		return errors.Trace(err)
+	}
+	c.globalResolvedTs.Store(minPartitionResolvedTs)
+	for _, p := range c.partitions {
+		p.flushed(minPartitionResolvedTs)
+	}
+	return nil
+}
+
+// GlobalResolvedTs returns the global resolved ts, all events before or at
+// it are flushed.
+func (c *Consumer) GlobalResolvedTs() uint64 {
+	return c.globalResolvedTs.Load()
+}
+
+// CommittableOffset returns the offset of the partition which can be
+// committed, that is, the offset of the next message to consume after
+// restarting. It returns false if no message of the partition is added.
+func (c *Consumer) CommittableOffset(partition int32) (int64, bool) {
+	p, err := c.getPartition(partition)
+	if err != nil {
+		return 0, false
+	}
+	return p.committableOffset()
+}
+
+// ResetPartition drops events of the partition which are not written to the
+// handler, and consumes the partition from the given offset. It should be
+// called when the partition is assigned to the consumer again, messages from
+// the last committed offset are redelivered in this case. Redelivered events
+// which are resolved by the partition already are ignored.
+func (c *Consumer) ResetPartition(partition int32, offset int64) error {
+	p, err := c.getPartition(partition)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	decoder, err := c.config.NewDecoder()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p.reset(decoder, offset)
+	return nil
+}
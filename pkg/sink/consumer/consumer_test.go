@@ -0,0 +1,296 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/open"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHandler struct {
+	mu         sync.Mutex
+	appended   map[int64][]uint64
+	flushed    []uint64
+	ddls       []uint64
+	syncPoints []uint64
+}
+
+func newMockHandler() *mockHandler {
+	return &mockHandler{appended: make(map[int64][]uint64)}
+}
+
+func (h *mockHandler) AppendRowChangedEvents(
+	_ context.Context, tableID int64, events []*model.RowChangedEvent,
+) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range events {
+		h.appended[tableID] = append(h.appended[tableID], e.CommitTs)
+	}
+	return nil
+}
+
+func (h *mockHandler) FlushRowChangedEvents(_ context.Context, resolvedTs uint64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flushed = append(h.flushed, resolvedTs)
+	return nil
+}
+
+func (h *mockHandler) WriteDDLEvent(_ context.Context, ddl *model.DDLEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ddls = append(h.ddls, ddl.CommitTs)
+	return nil
+}
+
+func (h *mockHandler) WriteSyncPoint(_ context.Context, primaryTs uint64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.syncPoints = append(h.syncPoints, primaryTs)
+	return nil
+}
+
+func newRowMessage(t *testing.T, table string, commitTs uint64) *common.Message {
+	builder, err := open.NewBatchEncoderBuilder(context.Background(), common.NewConfig(config.ProtocolOpen))
+	require.NoError(t, err)
+	encoder := builder.Build()
+	err = encoder.AppendRowChangedEvent(context.Background(), "", &model.RowChangedEvent{
+		CommitTs: commitTs,
+		Table:    &model.TableName{Schema: "test", Table: table},
+		Columns: []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Value: 1,
+		}},
+	}, nil)
+	require.NoError(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	return messages[0]
+}
+
+func newDDLMessage(t *testing.T, commitTs uint64) *common.Message {
+	encoder := open.NewBatchEncoder()
+	msg, err := encoder.EncodeDDLEvent(&model.DDLEvent{
+		CommitTs: commitTs,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t1"},
+		},
+		Query: "alter table t1 add column c int",
+		Type:  1,
+	})
+	require.NoError(t, err)
+	return msg
+}
+
+func newResolvedMessage(t *testing.T, ts uint64) *common.Message {
+	msg, err := open.NewBatchEncoder().EncodeCheckpointEvent(ts)
+	require.NoError(t, err)
+	return msg
+}
+
+func newSyncPointMessage(t *testing.T, ts uint64) *common.Message {
+	msg, err := open.NewBatchEncoder().EncodeSyncPointEvent(ts)
+	require.NoError(t, err)
+	return msg
+}
+
+func newTestConsumer(t *testing.T, partitionNum int32) (*Consumer, *mockHandler) {
+	newDecoder, err := NewDecoderFunc(config.ProtocolOpen, false, nil)
+	require.NoError(t, err)
+	handler := newMockHandler()
+	c, err := New(&Config{PartitionNum: partitionNum, NewDecoder: newDecoder}, handler)
+	require.NoError(t, err)
+	return c, handler
+}
+
+func TestConsumer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, handler := newTestConsumer(t, 2)
+	add := func(partition int32, offset int64, msg *common.Message) {
+		require.NoError(t, c.AddMessage(ctx, &Message{
+			Partition: partition, Offset: offset, Key: msg.Key, Value: msg.Value,
+		}))
+	}
+	requireOffsets := func(offset0, offset1 int64) {
+		offset, ok := c.CommittableOffset(0)
+		require.True(t, ok)
+		require.Equal(t, offset0, offset)
+		offset, ok = c.CommittableOffset(1)
+		require.True(t, ok)
+		require.Equal(t, offset1, offset)
+	}
+
+	_, ok := c.CommittableOffset(0)
+	require.False(t, ok)
+	add(0, 0, newRowMessage(t, "t1", 10))
+	add(0, 1, newDDLMessage(t, 15))
+	add(0, 2, newResolvedMessage(t, 25))
+	add(1, 0, newRowMessage(t, "t2", 20))
+	add(1, 1, newResolvedMessage(t, 12))
+	// Events are appended after they are resolved in the partition.
+	require.Equal(t, map[int64][]uint64{1: {10}}, handler.appended)
+	requireOffsets(0, 0)
+
+	// The DDL is blocked by the global resolved ts.
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{12}, handler.flushed)
+	require.Empty(t, handler.ddls)
+	requireOffsets(1, 0)
+
+	// The DDL is executed after events before it are flushed.
+	add(1, 2, newResolvedMessage(t, 30))
+	require.Equal(t, map[int64][]uint64{1: {10}, 2: {20}}, handler.appended)
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{12, 15, 15}, handler.flushed)
+	require.Equal(t, []uint64{15}, handler.ddls)
+	require.Equal(t, uint64(15), c.GlobalResolvedTs())
+	requireOffsets(3, 0)
+
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{12, 15, 15, 25}, handler.flushed)
+	requireOffsets(3, 3)
+
+	// A fallback row is committed after the next resolved ts is flushed.
+	add(0, 3, newRowMessage(t, "t1", 5))
+	add(0, 4, newResolvedMessage(t, 40))
+	requireOffsets(3, 3)
+	add(1, 3, newResolvedMessage(t, 40))
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{10, 5}, handler.appended[1])
+	requireOffsets(5, 4)
+
+	// Events which are not resolved are dropped after the partition is reset.
+	add(1, 4, newRowMessage(t, "t2", 50))
+	requireOffsets(5, 4)
+	require.NoError(t, c.ResetPartition(1, 4))
+	add(1, 4, newResolvedMessage(t, 60))
+	require.Equal(t, []uint64{20}, handler.appended[2])
+
+	// Partition resolved ts can't fall back.
+	require.Error(t, c.AddMessage(ctx, &Message{
+		Partition: 1, Offset: 5,
+		Key: newResolvedMessage(t, 50).Key, Value: newResolvedMessage(t, 50).Value,
+	}))
+
+	// Redelivered events which are resolved already are ignored after the
+	// partition is reset to an older offset.
+	require.NoError(t, c.ResetPartition(1, 0))
+	add(1, 0, newRowMessage(t, "t2", 20))
+	add(1, 1, newResolvedMessage(t, 12))
+	add(1, 2, newResolvedMessage(t, 30))
+	add(1, 3, newResolvedMessage(t, 40))
+	add(1, 4, newResolvedMessage(t, 60))
+	require.Equal(t, []uint64{20}, handler.appended[2])
+	add(1, 5, newRowMessage(t, "t2", 65))
+	add(1, 6, newResolvedMessage(t, 70))
+	require.Equal(t, []uint64{20, 65}, handler.appended[2])
+	require.Error(t, c.AddMessage(ctx, &Message{
+		Partition: 1, Offset: 7,
+		Key: newResolvedMessage(t, 60).Key, Value: newResolvedMessage(t, 60).Value,
+	}))
+	require.Error(t, c.AddMessage(ctx, &Message{Partition: 2}))
+}
+
+func TestConsumerSyncPoint(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, handler := newTestConsumer(t, 2)
+	add := func(partition int32, offset int64, msg *common.Message) {
+		require.NoError(t, c.AddMessage(ctx, &Message{
+			Partition: partition, Offset: offset, Key: msg.Key, Value: msg.Value,
+		}))
+	}
+
+	add(0, 0, newRowMessage(t, "t1", 10))
+	add(0, 1, newSyncPointMessage(t, 20))
+	add(0, 2, newDDLMessage(t, 25))
+	add(1, 0, newRowMessage(t, "t2", 15))
+	// The syncpoint resolves the partition.
+	require.Equal(t, map[int64][]uint64{1: {10}}, handler.appended)
+
+	// The syncpoint is blocked until it's received from all partitions.
+	require.NoError(t, c.tick(ctx))
+	require.Empty(t, handler.syncPoints)
+	add(1, 1, newSyncPointMessage(t, 20))
+	add(1, 2, newResolvedMessage(t, 30))
+	require.Equal(t, map[int64][]uint64{1: {10}, 2: {15}}, handler.appended)
+	require.Equal(t, uint64(20), c.getMinPartitionResolvedTs())
+
+	// The syncpoint is handled after events before it are flushed,
+	// and the DDL after it is handled in the next tick.
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{20, 20}, handler.flushed)
+	require.Equal(t, []uint64{20}, handler.syncPoints)
+	require.Empty(t, handler.ddls)
+	require.Equal(t, uint64(20), c.GlobalResolvedTs())
+	offset, _ := c.CommittableOffset(0)
+	require.Equal(t, int64(2), offset)
+
+	// A redundant syncpoint is ignored.
+	add(0, 3, newSyncPointMessage(t, 20))
+	add(0, 4, newResolvedMessage(t, 30))
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{25}, handler.ddls)
+	require.NoError(t, c.tick(ctx))
+	require.Equal(t, []uint64{20}, handler.syncPoints)
+	require.Equal(t, uint64(30), c.GlobalResolvedTs())
+}
+
+func TestEventsGroup(t *testing.T) {
+	t.Parallel()
+	group := newEventsGroup()
+	for _, ts := range []uint64{3, 1, 2, 5, 2} {
+		group.Append(&model.RowChangedEvent{CommitTs: ts})
+	}
+	var resolved []uint64
+	for _, e := range group.Resolve(2) {
+		resolved = append(resolved, e.CommitTs)
+	}
+	require.Equal(t, []uint64{1, 2, 2}, resolved)
+	require.Empty(t, group.Resolve(2))
+	require.Len(t, group.Resolve(5), 2)
+}
+
+func TestPartitionOffsets(t *testing.T) {
+	t.Parallel()
+	p := newPartitionState(0, nil)
+	p.addOffset(0, 10)
+	p.addOffset(1, 5)
+	p.addOffset(2, 0)
+	p.addOffset(3, 20)
+	offset, ok := p.committableOffset()
+	require.True(t, ok)
+	require.Equal(t, int64(0), offset)
+	p.flushed(10)
+	offset, _ = p.committableOffset()
+	require.Equal(t, int64(3), offset)
+	p.flushed(20)
+	offset, _ = p.committableOffset()
+	require.Equal(t, int64(4), offset)
+	// Messages whose events are flushed are committed directly.
+	p.addOffset(4, 15)
+	offset, _ = p.committableOffset()
+	require.Equal(t, int64(5), offset)
+}
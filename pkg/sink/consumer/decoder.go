@@ -0,0 +1,50 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+	"github.com/pingcap/tiflow/pkg/sink/codec/canal"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/sink/codec/craft"
+	"github.com/pingcap/tiflow/pkg/sink/codec/open"
+)
+
+// NewDecoderFunc returns a function creating decoders of the protocol, which
+// can be used as Config.NewDecoder. claimCheck can be nil if claim check is
+// not enabled in the sink.
+func NewDecoderFunc(
+	protocol config.Protocol,
+	enableTiDBExtension bool,
+	claimCheck *common.ClaimCheck,
+) (func() (codec.RowEventDecoder, error), error) {
+	switch protocol {
+	case config.ProtocolOpen, config.ProtocolDefault:
+		return func() (codec.RowEventDecoder, error) {
+			return open.NewBatchDecoder(claimCheck), nil
+		}, nil
+	case config.ProtocolCanalJSON:
+		return func() (codec.RowEventDecoder, error) {
+			return canal.NewBatchDecoder(enableTiDBExtension, "", claimCheck), nil
+		}, nil
+	case config.ProtocolCraft:
+		return func() (codec.RowEventDecoder, error) {
+			return craft.NewBatchDecoderWithAllocator(craft.NewSliceAllocator(64)), nil
+		}, nil
+	default:
+		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(protocol)
+	}
+}
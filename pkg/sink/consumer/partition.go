@@ -0,0 +1,177 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink/codec"
+)
+
+// pendingOffset is a message whose events are not flushed yet.
+type pendingOffset struct {
+	offset   int64
+	commitTs uint64
+}
+
+type partitionState struct {
+	partition int32
+
+	// decoder and groups are only accessed by the goroutine adding messages.
+	decoder codec.RowEventDecoder
+	groups  map[int64]*eventsGroup
+
+	resolvedTs atomic.Uint64
+
+	mu sync.Mutex
+	// pending are messages which can't be committed, commit ts of them are
+	// strictly increasing. A message with a commit ts not greater than the
+	// last pending one isn't recorded, because it can be committed together.
+	pending    []pendingOffset
+	flushedTs  uint64
+	nextOffset int64
+	hasOffset  bool
+	// replayEnd is the offset of the first message which is not consumed
+	// before the last reset. Messages before it are redelivered, events
+	// in them which are resolved already are duplicates.
+	replayEnd int64
+}
+
+func newPartitionState(partition int32, decoder codec.RowEventDecoder) *partitionState {
+	return &partitionState{
+		partition: partition,
+		decoder:   decoder,
+		groups:    make(map[int64]*eventsGroup),
+	}
+}
+
+// addOffset records the offset of a message, which can be committed after
+// the given commit ts is flushed.
+func (p *partitionState) addOffset(offset int64, commitTs uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if commitTs > p.flushedTs &&
+		(len(p.pending) == 0 || commitTs > p.pending[len(p.pending)-1].commitTs) {
+		p.pending = append(p.pending, pendingOffset{offset: offset, commitTs: commitTs})
+	}
+	p.nextOffset = offset + 1
+	p.hasOffset = true
+}
+
+// flushed is called after events before or at ts are flushed.
+func (p *partitionState) flushed(ts uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ts <= p.flushedTs {
+		return
+	}
+	p.flushedTs = ts
+	i := 0
+	for i < len(p.pending) && p.pending[i].commitTs <= ts {
+		i++
+	}
+	p.pending = p.pending[i:]
+}
+
+func (p *partitionState) committableOffset() (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.pending) > 0 {
+		return p.pending[0].offset, true
+	}
+	return p.nextOffset, p.hasOffset
+}
+
+func (p *partitionState) reset(decoder codec.RowEventDecoder, offset int64) {
+	p.decoder = decoder
+	p.groups = make(map[int64]*eventsGroup)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hasOffset && p.nextOffset > p.replayEnd {
+		p.replayEnd = p.nextOffset
+	}
+	p.pending = nil
+	p.nextOffset = offset
+	p.hasOffset = false
+}
+
+// isReplayed returns true if the message at the offset is consumed before
+// the last reset.
+func (p *partitionState) isReplayed(offset int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return offset < p.replayEnd
+}
+
+type eventsGroup struct {
+	events []*model.RowChangedEvent
+}
+
+func newEventsGroup() *eventsGroup {
+	return &eventsGroup{
+		events: make([]*model.RowChangedEvent, 0),
+	}
+}
+
+func (g *eventsGroup) Append(e *model.RowChangedEvent) {
+	g.events = append(g.events, e)
+}
+
+func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
+	sort.SliceStable(g.events, func(i, j int) bool {
+		return g.events[i].CommitTs < g.events[j].CommitTs
+	})
+
+	i := sort.Search(len(g.events), func(i int) bool {
+		return g.events[i].CommitTs > resolveTs
+	})
+	result := g.events[:i]
+	g.events = g.events[i:]
+
+	return result
+}
+
+type fakeTableIDGenerator struct {
+	tableIDs       map[string]int64
+	currentTableID int64
+	mu             sync.Mutex
+}
+
+func newFakeTableIDGenerator() *fakeTableIDGenerator {
+	return &fakeTableIDGenerator{
+		tableIDs: make(map[string]int64),
+	}
+}
+
+// generateFakeTableID returns an ID which is unique for the table in the
+// consumer, because most protocols don't carry table IDs.
+func (g *fakeTableIDGenerator) generateFakeTableID(schema, table string, partition int64) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := quotes.QuoteSchema(schema, table)
+	if partition != 0 {
+		key = fmt.Sprintf("%s.`%d`", key, partition)
+	}
+	if tableID, ok := g.tableIDs[key]; ok {
+		return tableID
+	}
+	g.currentTableID++
+	g.tableIDs[key] = g.currentTableID
+	return g.currentTableID
+}
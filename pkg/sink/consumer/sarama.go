@@ -0,0 +1,100 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+)
+
+const markOffsetInterval = time.Second
+
+// ConsumerGroupHandler returns a sarama.ConsumerGroupHandler which adds
+// messages of claims to the consumer. Offsets are marked only after events
+// in messages are flushed, so they are consumed at least once.
+func (c *Consumer) ConsumerGroupHandler() sarama.ConsumerGroupHandler {
+	return &consumerGroupHandler{consumer: c}
+}
+
+type consumerGroupHandler struct {
+	consumer *Consumer
+}
+
+// Setup implements sarama.ConsumerGroupHandler.
+func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// Cleanup implements sarama.ConsumerGroupHandler.
+func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim implements sarama.ConsumerGroupHandler.
+func (h *consumerGroupHandler) ConsumeClaim(
+	session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim,
+) error {
+	partition := claim.Partition()
+	// Messages after the last committed offset are redelivered, so events
+	// which are not written to the handler are dropped.
+	if err := h.consumer.ResetPartition(partition, claim.InitialOffset()); err != nil {
+		return errors.Trace(err)
+	}
+	log.Info("start consuming partition",
+		zap.String("topic", claim.Topic()),
+		zap.Int32("partition", partition),
+		zap.Int64("initialOffset", claim.InitialOffset()))
+
+	marked := int64(-1)
+	mark := func() {
+		offset, ok := h.consumer.CommittableOffset(partition)
+		if ok && offset > marked {
+			session.MarkOffset(claim.Topic(), partition, offset, "")
+			marked = offset
+		}
+	}
+	ticker := time.NewTicker(markOffsetInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case message, ok := <-claim.Messages():
+			if !ok {
+				mark()
+				return nil
+			}
+			if err := h.consumer.AddMessage(session.Context(), &Message{
+				Partition: message.Partition,
+				Offset:    message.Offset,
+				Key:       message.Key,
+				Value:     message.Value,
+			}); err != nil {
+				log.Error("add message to the consumer failed",
+					zap.String("topic", message.Topic),
+					zap.Int32("partition", message.Partition),
+					zap.Int64("offset", message.Offset),
+					zap.Error(err))
+				return errors.Trace(err)
+			}
+			mark()
+		case <-ticker.C:
+			mark()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSession struct {
+	sarama.ConsumerGroupSession
+	ctx context.Context
+
+	mu     sync.Mutex
+	marked map[int32]int64
+}
+
+func (s *mockSession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockSession) MarkOffset(_ string, partition int32, offset int64, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked[partition] = offset
+}
+
+func (s *mockSession) getMarked(partition int32) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offset, ok := s.marked[partition]
+	return offset, ok
+}
+
+type mockClaim struct {
+	sarama.ConsumerGroupClaim
+	partition int32
+	messages  chan *sarama.ConsumerMessage
+}
+
+func (c *mockClaim) Topic() string                            { return "test" }
+func (c *mockClaim) Partition() int32                         { return c.partition }
+func (c *mockClaim) InitialOffset() int64                     { return 0 }
+func (c *mockClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func TestConsumerGroupHandler(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, handler := newTestConsumer(t, 1)
+	c.config.FlushInterval = 10 * time.Millisecond
+
+	session := &mockSession{ctx: ctx, marked: make(map[int32]int64)}
+	claim := &mockClaim{messages: make(chan *sarama.ConsumerMessage, 16)}
+	send := func(offset int64, msg *common.Message) {
+		claim.messages <- &sarama.ConsumerMessage{
+			Topic: "test", Offset: offset, Key: msg.Key, Value: msg.Value,
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, c.ConsumerGroupHandler().ConsumeClaim(session, claim))
+	}()
+	go func() {
+		defer wg.Done()
+		require.ErrorIs(t, c.Run(ctx), context.Canceled)
+	}()
+
+	// The offset of a message isn't marked before its events are flushed.
+	send(0, newRowMessage(t, "t1", 10))
+	send(1, newResolvedMessage(t, 5))
+	require.Eventually(t, func() bool {
+		return c.GlobalResolvedTs() == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	offset, ok := session.getMarked(0)
+	require.True(t, !ok || offset == 0)
+
+	send(2, newResolvedMessage(t, 20))
+	require.Eventually(t, func() bool {
+		offset, ok := session.getMarked(0)
+		return ok && offset == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	handler.mu.Lock()
+	require.Equal(t, []uint64{10}, handler.appended[1])
+	handler.mu.Unlock()
+
+	close(claim.messages)
+	cancel()
+	wg.Wait()
+}
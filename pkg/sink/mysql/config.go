@@ -100,6 +100,8 @@ type Config struct {
 	BatchDMLEnable  bool
 	MultiStmtEnable bool
 	CachePrepStmts  bool
+	// BDRConflict is not nil if conflicts must be detected in BDR mode.
+	BDRConflict *config.BDRConflictConfig
 }
 
 // NewConfig returns the default mysql backend config.
@@ -181,6 +183,9 @@ func (c *Config) Apply(
 	c.EnableOldValue = replicaConfig.EnableOldValue
 	c.ForceReplicate = replicaConfig.ForceReplicate
 	c.SourceID = replicaConfig.Sink.TiDBSourceID
+	if replicaConfig.BDRMode {
+		c.BDRConflict = replicaConfig.BDRConflict
+	}
 
 	return nil
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/tikv/client-go/v2/oracle"
 	pd "github.com/tikv/pd/client"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
@@ -46,6 +47,9 @@ type Manager interface {
 	// IgnoreFailedChangeFeed verifies whether a failed changefeed should be
 	// disregarded. When calculating the GC safepoint of the related upstream,
 	IgnoreFailedChangeFeed(checkpointTs uint64) bool
+	// LastSafePointTs returns the TiCDC service GC safepoint that is
+	// reported by PD in the last successful update.
+	LastSafePointTs() model.Ts
 }
 
 type gcManager struct {
@@ -56,7 +60,7 @@ type gcManager struct {
 
 	lastUpdatedTime   time.Time
 	lastSucceededTime time.Time
-	lastSafePointTs   uint64
+	lastSafePointTs   atomic.Uint64
 }
 
 // NewManager creates a new Manager.
@@ -103,7 +107,7 @@ func (m *gcManager) TryUpdateGCSafePoint(
 		log.Warn("update gc safe point failed, the gc safe point is larger than checkpointTs",
 			zap.Uint64("actual", actual), zap.Uint64("checkpointTs", checkpointTs))
 	}
-	m.lastSafePointTs = actual
+	m.lastSafePointTs.Store(actual)
 	m.lastSucceededTime = time.Now()
 	return nil
 }
@@ -113,11 +117,12 @@ func (m *gcManager) CheckStaleCheckpointTs(
 ) error {
 	gcSafepointUpperBound := checkpointTs - 1
 	// if there is another service gc point less than the min checkpoint ts.
-	if gcSafepointUpperBound < m.lastSafePointTs {
+	lastSafePointTs := m.lastSafePointTs.Load()
+	if gcSafepointUpperBound < lastSafePointTs {
 		return cerror.ErrSnapshotLostByGC.
 			GenWithStackByArgs(
 				checkpointTs,
-				m.lastSafePointTs,
+				lastSafePointTs,
 			)
 	}
 	return nil
@@ -141,3 +146,7 @@ func (m *gcManager) IgnoreFailedChangeFeed(
 		oracle.GetTimeFromTS(gcSafepointUpperBound),
 	) > gcTTL
 }
+
+func (m *gcManager) LastSafePointTs() model.Ts {
+	return m.lastSafePointTs.Load()
+}
@@ -99,7 +99,8 @@ func TestCheckStaleCheckpointTs(t *testing.T) {
 	err := gcManager.CheckStaleCheckpointTs(ctx, cfID, oracle.GoTimeToTS(time.Now()))
 	require.Nil(t, err)
 
-	gcManager.lastSafePointTs = 20
+	gcManager.lastSafePointTs.Store(20)
+	require.Equal(t, uint64(20), gcManager.LastSafePointTs())
 	err = gcManager.CheckStaleCheckpointTs(ctx, cfID, 10)
 	require.True(t, cerror.ErrSnapshotLostByGC.Equal(errors.Cause(err)))
 	require.True(t, cerror.IsChangefeedFastFailError(err))
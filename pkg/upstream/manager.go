@@ -160,6 +160,25 @@ func (m *Manager) Get(upstreamID uint64) (*Upstream, bool) {
 	return up, true
 }
 
+// Remove closes an upstream and removes it from the manager.
+// The default upstream can not be removed.
+func (m *Manager) Remove(upstreamID uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.ups.Load(upstreamID)
+	if !ok {
+		return cerror.ErrUpstreamNotFound.GenWithStackByArgs(upstreamID)
+	}
+	up := v.(*Upstream)
+	if up.isDefaultUpstream {
+		return cerror.ErrUpstreamIsDefault.GenWithStackByArgs(upstreamID)
+	}
+	m.ups.Delete(upstreamID)
+	go up.Close()
+	log.Info("upstream is removed from manager", zap.Uint64("id", upstreamID))
+	return nil
+}
+
 // Close closes all upstreams.
 // Please make sure it will only be called once when capture exits.
 func (m *Manager) Close() {
@@ -225,6 +244,15 @@ func (m *Manager) Tick(ctx context.Context,
 		if ok {
 			return true
 		}
+		// The upstream is removed from the TiCDC cluster, the upstream
+		// manager of each capture closes it.
+		if _, ok := globalState.Upstreams[id]; !ok {
+			log.Info("upstream info is removed, remove it from manager",
+				zap.Uint64("id", up.ID))
+			go up.Close()
+			m.ups.Delete(id)
+			return true
+		}
 
 		up.trySetIdleTime()
 		log.Info("no active changefeed found, try to close upstream",
@@ -17,10 +17,12 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
@@ -55,10 +57,17 @@ func TestUpstream(t *testing.T) {
 	require.NotNil(t, up)
 
 	// test Tick
-	_ = manager.Tick(context.Background(), &orchestrator.GlobalReactorState{})
+	state := &orchestrator.GlobalReactorState{
+		Upstreams: map[model.UpstreamID]*model.UpstreamInfo{
+			testID: {ID: testID},
+		},
+	}
+	_ = manager.Tick(context.Background(), state)
+	_, ok = manager.Get(testID)
+	require.True(t, ok)
 	mockClock.Add(maxIdleDuration * 2)
 	manager.lastTickTime = atomic.Time{}
-	_ = manager.Tick(context.Background(), &orchestrator.GlobalReactorState{})
+	_ = manager.Tick(context.Background(), state)
 	// wait until up2 is closed
 	for !up2.IsClosed() {
 	}
@@ -73,6 +82,28 @@ func TestUpstream(t *testing.T) {
 	require.NotNil(t, up)
 }
 
+func TestRemoveUpstream(t *testing.T) {
+	pdClient := &gc.MockPDClient{}
+	manager := NewManager4Test(pdClient)
+	up1 := NewUpstream4Test(pdClient)
+	up1.ID = 4
+	manager.ups.Store(uint64(4), up1)
+
+	// the default upstream can not be removed
+	err := manager.Remove(testUpstreamID)
+	require.True(t, cerror.ErrUpstreamIsDefault.Equal(errors.Cause(err)))
+	_, ok := manager.Get(testUpstreamID)
+	require.True(t, ok)
+
+	require.Nil(t, manager.Remove(4))
+	_, ok = manager.Get(4)
+	require.False(t, ok)
+	require.Eventually(t, up1.IsClosed, 5*time.Second, 10*time.Millisecond)
+
+	err = manager.Remove(4)
+	require.True(t, cerror.ErrUpstreamNotFound.Equal(errors.Cause(err)))
+}
+
 func TestRemoveErrorUpstream(t *testing.T) {
 	pdClient := &gc.MockPDClient{}
 	manager := NewManager4Test(pdClient)
@@ -175,8 +206,38 @@ func TestRemoveThenAddAgain(t *testing.T) {
 	up := m.AddUpstream(&model.UpstreamInfo{ID: uint64(3)})
 	require.NotNil(t, up)
 	// test Tick
-	_ = m.Tick(context.Background(), &orchestrator.GlobalReactorState{})
+	_ = m.Tick(context.Background(), &orchestrator.GlobalReactorState{
+		Upstreams: map[model.UpstreamID]*model.UpstreamInfo{
+			3: {ID: 3},
+		},
+	})
 	require.False(t, up.idleTime.IsZero())
 	_ = m.AddUpstream(&model.UpstreamInfo{ID: uint64(3)})
 	require.True(t, up.idleTime.IsZero())
 }
+
+func TestRemoveUpstreamWithoutInfo(t *testing.T) {
+	pdClient := &gc.MockPDClient{}
+	manager := NewManager4Test(pdClient)
+	up1 := NewUpstream4Test(pdClient)
+	up1.ID = 4
+	manager.ups.Store(uint64(4), up1)
+	up2 := NewUpstream4Test(pdClient)
+	up2.ID = 5
+	manager.ups.Store(uint64(5), up2)
+
+	// the upstream whose info is removed is closed at once, while the
+	// default upstream is kept.
+	_ = manager.Tick(context.Background(), &orchestrator.GlobalReactorState{
+		Upstreams: map[model.UpstreamID]*model.UpstreamInfo{
+			5: {ID: 5},
+		},
+	})
+	_, ok := manager.Get(4)
+	require.False(t, ok)
+	require.Eventually(t, up1.IsClosed, 5*time.Second, 10*time.Millisecond)
+	_, ok = manager.Get(5)
+	require.True(t, ok)
+	_, ok = manager.Get(testUpstreamID)
+	require.True(t, ok)
+}
@@ -259,6 +259,11 @@ func (up *Upstream) IsNormal() bool {
 	return atomic.LoadInt32(&up.status) == normal && up.err.Load() == nil
 }
 
+// IsDefault returns true if the upstream is the default upstream.
+func (up *Upstream) IsDefault() bool {
+	return up.isDefaultUpstream
+}
+
 // IsClosed returns true if the upstream is closed.
 func (up *Upstream) IsClosed() bool {
 	return atomic.LoadInt32(&up.status) == closed
@@ -0,0 +1,160 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// State is the state of a verification.
+type State string
+
+const (
+	// StateRunning means the verification is running.
+	StateRunning State = "running"
+	// StateFinished means the verification is finished, and the report is available.
+	StateFinished State = "finished"
+	// StateFailed means the verification is stopped by an error.
+	StateFailed State = "failed"
+	// StateCanceled means the verification is canceled by the user.
+	StateCanceled State = "canceled"
+)
+
+// Status is the status of a verification.
+type Status struct {
+	State     State     `json:"state"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time,omitempty"`
+	Progress  Progress  `json:"progress"`
+	Error     string    `json:"error,omitempty"`
+	Report    *Report   `json:"report,omitempty"`
+}
+
+// task is a verification of a changefeed.
+type task struct {
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	status   Status
+	verifier *verifier
+}
+
+func (t *task) getStatus() *Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := t.status
+	if t.verifier != nil {
+		status.Progress = t.verifier.progress()
+	}
+	return &status
+}
+
+// Manager runs verifications of changefeeds in the background, at most one
+// verification of a changefeed runs at the same time. Verifications are kept
+// in memory only, so they are lost if the owner changes.
+type Manager struct {
+	mu    sync.Mutex
+	tasks map[model.ChangeFeedID]*task
+
+	// runFn is used to mock the verification in tests.
+	runFn func(ctx context.Context, cfg *Config, onStart func(*verifier)) (*Report, error)
+}
+
+// NewManager creates a Manager.
+func NewManager() *Manager {
+	return &Manager{
+		tasks: make(map[model.ChangeFeedID]*task),
+		runFn: run,
+	}
+}
+
+// Start starts a verification of the changefeed in the background.
+func (m *Manager) Start(cfg *Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.tasks[cfg.ChangefeedID]; ok && t.getStatus().State == StateRunning {
+		return cerror.ErrVerificationIsRunning.GenWithStackByArgs(cfg.ChangefeedID.ID)
+	}
+
+	cfg.adjust()
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &task{
+		cancel: cancel,
+		status: Status{State: StateRunning, StartTime: time.Now()},
+	}
+	m.tasks[cfg.ChangefeedID] = t
+	runFn := m.runFn
+	go func() {
+		defer cancel()
+		report, err := runFn(ctx, cfg, func(v *verifier) {
+			t.mu.Lock()
+			t.verifier = v
+			t.mu.Unlock()
+		})
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.verifier != nil {
+			t.status.Progress = t.verifier.progress()
+			t.verifier = nil
+		}
+		t.status.EndTime = time.Now()
+		switch {
+		case ctx.Err() != nil:
+			t.status.State = StateCanceled
+		case err != nil:
+			t.status.State = StateFailed
+			t.status.Error = err.Error()
+		default:
+			t.status.State = StateFinished
+			t.status.Report = report
+		}
+		log.Info("verification of changefeed is stopped",
+			zap.String("namespace", cfg.ChangefeedID.Namespace),
+			zap.String("changefeed", cfg.ChangefeedID.ID),
+			zap.String("state", string(t.status.State)),
+			zap.Error(err))
+	}()
+	return nil
+}
+
+// Status returns the status of the last verification of the changefeed.
+func (m *Manager) Status(id model.ChangeFeedID) (*Status, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tasks[id]
+	if !ok {
+		return nil, cerror.ErrVerificationNotFound.GenWithStackByArgs(id.ID)
+	}
+	return t.getStatus(), nil
+}
+
+// Cancel cancels the running verification of the changefeed.
+func (m *Manager) Cancel(id model.ChangeFeedID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tasks[id]
+	if !ok {
+		return cerror.ErrVerificationNotFound.GenWithStackByArgs(id.ID)
+	}
+	t.cancel()
+	return nil
+}
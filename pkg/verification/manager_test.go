@@ -0,0 +1,82 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager()
+	id := model.DefaultChangeFeedID("test")
+	_, err := m.Status(id)
+	require.True(t, cerror.ErrVerificationNotFound.Equal(err))
+	require.True(t, cerror.ErrVerificationNotFound.Equal(m.Cancel(id)))
+
+	// A verification which runs until it is canceled.
+	m.runFn = func(ctx context.Context, cfg *Config, onStart func(*verifier)) (*Report, error) {
+		require.Equal(t, defaultChunkSize, cfg.ChunkSize)
+		require.Equal(t, defaultConcurrency, cfg.Concurrency)
+		v := newVerifier(cfg, nil, nil)
+		v.totalTables.Store(3)
+		onStart(v)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	require.Nil(t, m.Start(&Config{ChangefeedID: id}))
+	require.Eventually(t, func() bool {
+		status, err := m.Status(id)
+		require.Nil(t, err)
+		return status.Progress.TotalTables == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	err = m.Start(&Config{ChangefeedID: id})
+	require.True(t, cerror.ErrVerificationIsRunning.Equal(err))
+	require.Nil(t, m.Cancel(id))
+	require.Eventually(t, func() bool {
+		status, err := m.Status(id)
+		require.Nil(t, err)
+		return status.State == StateCanceled
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A verification which fails.
+	m.runFn = func(ctx context.Context, cfg *Config, onStart func(*verifier)) (*Report, error) {
+		return nil, errors.New("syncpoint not found")
+	}
+	require.Nil(t, m.Start(&Config{ChangefeedID: id}))
+	require.Eventually(t, func() bool {
+		status, err := m.Status(id)
+		require.Nil(t, err)
+		return status.State == StateFailed && status.Error == "syncpoint not found"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A verification which finishes.
+	m.runFn = func(ctx context.Context, cfg *Config, onStart func(*verifier)) (*Report, error) {
+		return &Report{PrimaryTs: 10, SecondaryTs: 20, Consistent: true}, nil
+	}
+	require.Nil(t, m.Start(&Config{ChangefeedID: id}))
+	require.Eventually(t, func() bool {
+		status, err := m.Status(id)
+		require.Nil(t, err)
+		return status.State == StateFinished && status.Report.Consistent
+	}, 5*time.Second, 10*time.Millisecond)
+}
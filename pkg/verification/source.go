@@ -0,0 +1,373 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink/mysql"
+	"golang.org/x/time/rate"
+)
+
+// source reads a snapshot of the upstream or the downstream.
+type source interface {
+	// listTables returns all base tables in the snapshot.
+	listTables(ctx context.Context) ([]model.TableName, error)
+	// getTableMeta returns the metadata of a table,
+	// it returns nil if the table does not exist.
+	getTableMeta(ctx context.Context, schema, table string) (*tableMeta, error)
+	// nextBoundary returns the upper bound of the chunk after the lower bound,
+	// which contains at most chunkSize rows. It returns nil if there are no
+	// more than chunkSize rows after the lower bound.
+	nextBoundary(ctx context.Context, t *tableMeta, lower []string, chunkSize int) ([]string, error)
+	// checksum returns the checksum of rows in the chunk.
+	checksum(ctx context.Context, t *tableMeta, c *chunk) (checksum, error)
+	// rows returns rows in the chunk, which are grouped by their keys.
+	rows(ctx context.Context, t *tableMeta, c *chunk) (map[string][]string, error)
+	close() error
+}
+
+// tableMeta is the metadata of a table to verify.
+type tableMeta struct {
+	schema  string
+	table   string
+	columns []string
+	// keys are the primary key columns, which are used to split chunks.
+	// Tables without primary key are not supported, since their _tidb_rowid
+	// values are assigned independently in the upstream and the downstream.
+	keys []string
+}
+
+func (t *tableMeta) quotedName() string {
+	return quotes.QuoteSchema(t.schema, t.table)
+}
+
+// chunk is a range of rows in a table, rows with keys in (lower, upper]
+// are in it. A nil bound means the range is unbounded.
+type chunk struct {
+	lower []string
+	upper []string
+}
+
+// where returns the condition and arguments to select rows in the chunk.
+func (c *chunk) where(t *tableMeta) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	keys, placeholders := quoteColumns(t.keys), placeholders(len(t.keys))
+	if c.lower != nil {
+		conds = append(conds, fmt.Sprintf("(%s) > (%s)", keys, placeholders))
+		for _, v := range c.lower {
+			args = append(args, v)
+		}
+	}
+	if c.upper != nil {
+		conds = append(conds, fmt.Sprintf("(%s) <= (%s)", keys, placeholders))
+		for _, v := range c.upper {
+			args = append(args, v)
+		}
+	}
+	if len(conds) == 0 {
+		return "TRUE", nil
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// String returns the readable range of the chunk.
+func (c *chunk) String() string {
+	bound := func(values []string, unbounded string) string {
+		if values == nil {
+			return unbounded
+		}
+		quoted := make([]string, 0, len(values))
+		for _, v := range values {
+			quoted = append(quoted, strconv.Quote(v))
+		}
+		return "(" + strings.Join(quoted, ", ") + ")"
+	}
+	return fmt.Sprintf("(%s, %s]", bound(c.lower, "-inf"), bound(c.upper, "+inf"))
+}
+
+// checksum is the number of rows and the xor of row checksums of a chunk.
+type checksum struct {
+	count int64
+	sum   uint64
+}
+
+// nullValue stands for NULL in row values of the report.
+const nullValue = "NULL"
+
+// snapshotSource reads a snapshot of a TiDB cluster at a ts, by setting
+// tidb_snapshot for all connections.
+type snapshotSource struct {
+	db      *sql.DB
+	limiter *rate.Limiter
+}
+
+// newSnapshotSource creates a snapshotSource with a MySQL-compatible URI.
+func newSnapshotSource(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	uri string,
+	snapshotTs uint64,
+	limiter *rate.Limiter,
+) (*snapshotSource, error) {
+	db, err := openDB(ctx, changefeedID, uri, snapshotTs)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotSource{db: db, limiter: limiter}, nil
+}
+
+// openDB opens a db with the MySQL-compatible URI, all connections read the
+// snapshot at snapshotTs if it is not 0. Time zones of all connections are
+// UTC, so that checksums of timestamp values are the same on both sides.
+func openDB(
+	ctx context.Context, changefeedID model.ChangeFeedID, uri string, snapshotTs uint64,
+) (*sql.DB, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	cfg := mysql.NewConfig()
+	if err := cfg.Apply(ctx, changefeedID, u, config.GetDefaultReplicaConfig()); err != nil {
+		return nil, err
+	}
+	dsn, err := mysql.GenBasicDSN(u, cfg)
+	if err != nil {
+		return nil, err
+	}
+	dsn.Params["time_zone"] = `"+00:00"`
+	if snapshotTs != 0 {
+		dsn.Params["tidb_snapshot"] = strconv.FormatUint(snapshotTs, 10)
+	}
+	return mysql.CreateMySQLDBConn(ctx, dsn.FormatDSN())
+}
+
+func (s *snapshotSource) query(
+	ctx context.Context, query string, args ...interface{},
+) (*sql.Rows, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return rows, nil
+}
+
+func (s *snapshotSource) listTables(ctx context.Context) ([]model.TableName, error) {
+	rows, err := s.query(ctx, "SELECT TABLE_SCHEMA, TABLE_NAME FROM information_schema.TABLES "+
+		"WHERE TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_SCHEMA, TABLE_NAME")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []model.TableName
+	for rows.Next() {
+		var table model.TableName
+		if err := rows.Scan(&table.Schema, &table.Table); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, cerror.WrapError(cerror.ErrMySQLQueryError, rows.Err())
+}
+
+func (s *snapshotSource) queryColumnNames(
+	ctx context.Context, query string, args ...interface{},
+) ([]string, error) {
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+		names = append(names, name)
+	}
+	return names, cerror.WrapError(cerror.ErrMySQLQueryError, rows.Err())
+}
+
+func (s *snapshotSource) getTableMeta(
+	ctx context.Context, schema, table string,
+) (*tableMeta, error) {
+	columns, err := s.queryColumnNames(ctx, "SELECT COLUMN_NAME FROM information_schema.COLUMNS "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION", schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	keys, err := s.queryColumnNames(ctx, "SELECT COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_NAME = 'PRIMARY' "+
+		"ORDER BY ORDINAL_POSITION", schema, table)
+	if err != nil {
+		return nil, err
+	}
+	return &tableMeta{schema: schema, table: table, columns: columns, keys: keys}, nil
+}
+
+func (s *snapshotSource) nextBoundary(
+	ctx context.Context, t *tableMeta, lower []string, chunkSize int,
+) ([]string, error) {
+	where, args := (&chunk{lower: lower}).where(t)
+	keys := quoteColumns(t.keys)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT 1 OFFSET %d",
+		keys, t.quotedName(), where, keys, chunkSize-1)
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, rows.Err())
+	}
+	values, err := scanValues(rows, len(t.keys))
+	if err != nil {
+		return nil, err
+	}
+	upper := make([]string, 0, len(values))
+	for _, v := range values {
+		upper = append(upper, v.String)
+	}
+	return upper, nil
+}
+
+func (s *snapshotSource) checksum(
+	ctx context.Context, t *tableMeta, c *chunk,
+) (checksum, error) {
+	where, args := c.where(t)
+	columns := quoteColumns(t.columns)
+	isNulls := make([]string, 0, len(t.columns))
+	for _, col := range t.columns {
+		isNulls = append(isNulls, fmt.Sprintf("ISNULL(%s)", quotes.QuoteName(col)))
+	}
+	query := fmt.Sprintf("SELECT COUNT(*), "+
+		"COALESCE(BIT_XOR(CAST(CRC32(CONCAT_WS(',', %s, %s)) AS UNSIGNED)), 0) "+
+		"FROM %s WHERE %s", columns, strings.Join(isNulls, ", "), t.quotedName(), where)
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return checksum{}, err
+	}
+	defer rows.Close()
+	var res checksum
+	if rows.Next() {
+		if err := rows.Scan(&res.count, &res.sum); err != nil {
+			return checksum{}, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+		}
+	}
+	return res, cerror.WrapError(cerror.ErrMySQLQueryError, rows.Err())
+}
+
+func (s *snapshotSource) rows(
+	ctx context.Context, t *tableMeta, c *chunk,
+) (map[string][]string, error) {
+	where, args := c.where(t)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		quoteColumns(t.columns), t.quotedName(), where)
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keyIndexes := make([]int, 0, len(t.keys))
+	for _, key := range t.keys {
+		for i, col := range t.columns {
+			if col == key {
+				keyIndexes = append(keyIndexes, i)
+			}
+		}
+	}
+	res := make(map[string][]string)
+	for rows.Next() {
+		values, err := scanValues(rows, len(t.columns))
+		if err != nil {
+			return nil, err
+		}
+		keyColumns := make([]string, 0, len(keyIndexes))
+		keyValues := make([]sql.NullString, 0, len(keyIndexes))
+		for _, i := range keyIndexes {
+			keyColumns = append(keyColumns, t.columns[i])
+			keyValues = append(keyValues, values[i])
+		}
+		key := formatValues(keyColumns, keyValues)
+		res[key] = append(res[key], formatValues(t.columns, values))
+	}
+	return res, cerror.WrapError(cerror.ErrMySQLQueryError, rows.Err())
+}
+
+func (s *snapshotSource) close() error {
+	return cerror.WrapError(cerror.ErrMySQLConnectionError, s.db.Close())
+}
+
+func scanValues(rows *sql.Rows, n int) ([]sql.NullString, error) {
+	values := make([]sql.NullString, n)
+	dest := make([]interface{}, n)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return values, nil
+}
+
+// formatValues formats values of columns like "`a` = 1, `b` = NULL".
+func formatValues(columns []string, values []sql.NullString) string {
+	var b strings.Builder
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quotes.QuoteName(columns[i]))
+		b.WriteString(" = ")
+		if v.Valid {
+			b.WriteString(strconv.Quote(v.String))
+		} else {
+			b.WriteString(nullValue)
+		}
+	}
+	return b.String()
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, col := range columns {
+		quoted = append(quoted, quotes.QuoteName(col))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+var _ source = (*snapshotSource)(nil)
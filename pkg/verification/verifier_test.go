@@ -0,0 +1,238 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"hash/crc32"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTable is a table whose first column is named id, which is the primary
+// key unless keys are cleared. Rows are ordered by ids.
+type fakeTable struct {
+	columns []string
+	keys    []string
+	rows    [][]string
+}
+
+// fakeSource is an in-memory source for tests.
+type fakeSource struct {
+	tables map[model.TableName]*fakeTable
+	err    error
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{tables: make(map[model.TableName]*fakeTable)}
+}
+
+func (s *fakeSource) addTable(schema, table string, columns []string, rows ...[]string) {
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	s.tables[model.TableName{Schema: schema, Table: table}] = &fakeTable{
+		columns: columns, keys: []string{"id"}, rows: rows,
+	}
+}
+
+func (s *fakeSource) listTables(ctx context.Context) ([]model.TableName, error) {
+	var tables []model.TableName
+	for table := range s.tables {
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].String() < tables[j].String() })
+	return tables, nil
+}
+
+func (s *fakeSource) getTableMeta(
+	ctx context.Context, schema, table string,
+) (*tableMeta, error) {
+	t, ok := s.tables[model.TableName{Schema: schema, Table: table}]
+	if !ok {
+		return nil, nil
+	}
+	return &tableMeta{schema: schema, table: table, columns: t.columns, keys: t.keys}, nil
+}
+
+func (s *fakeSource) chunkRows(t *tableMeta, c *chunk) [][]string {
+	var rows [][]string
+	for _, row := range s.tables[model.TableName{Schema: t.schema, Table: t.table}].rows {
+		if c.lower != nil && row[0] <= c.lower[0] {
+			continue
+		}
+		if c.upper != nil && row[0] > c.upper[0] {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (s *fakeSource) nextBoundary(
+	ctx context.Context, t *tableMeta, lower []string, chunkSize int,
+) ([]string, error) {
+	rows := s.chunkRows(t, &chunk{lower: lower})
+	if len(rows) <= chunkSize {
+		return nil, nil
+	}
+	return []string{rows[chunkSize-1][0]}, nil
+}
+
+func (s *fakeSource) checksum(
+	ctx context.Context, t *tableMeta, c *chunk,
+) (checksum, error) {
+	if s.err != nil {
+		return checksum{}, s.err
+	}
+	var res checksum
+	for _, row := range s.chunkRows(t, c) {
+		res.count++
+		res.sum ^= uint64(crc32.ChecksumIEEE([]byte(strings.Join(row, ","))))
+	}
+	return res, nil
+}
+
+func (s *fakeSource) rows(
+	ctx context.Context, t *tableMeta, c *chunk,
+) (map[string][]string, error) {
+	res := make(map[string][]string)
+	for _, row := range s.chunkRows(t, c) {
+		res[row[0]] = append(res[row[0]], strings.Join(row, ","))
+	}
+	return res, nil
+}
+
+func (s *fakeSource) close() error {
+	return nil
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	upstream, downstream := newFakeSource(), newFakeSource()
+	columns := []string{"id", "v"}
+	upstream.addTable("test", "same", columns,
+		[]string{"1", "a"}, []string{"2", "b"}, []string{"3", "c"}, []string{"4", "d"})
+	downstream.addTable("test", "same", columns,
+		[]string{"1", "a"}, []string{"2", "b"}, []string{"3", "c"}, []string{"4", "d"})
+	upstream.addTable("test", "diff", columns,
+		[]string{"1", "a"}, []string{"2", "b"}, []string{"3", "c"}, []string{"4", "d"},
+		[]string{"5", "e"})
+	downstream.addTable("test", "diff", columns,
+		[]string{"1", "a"}, []string{"2", "x"}, []string{"4", "d"},
+		[]string{"5", "e"}, []string{"6", "f"})
+	upstream.addTable("test", "missing", columns, []string{"1", "a"})
+	upstream.addTable("test", "missing_column", columns, []string{"1", "a"})
+	downstream.addTable("test", "missing_column", []string{"id"}, []string{"1"})
+	upstream.addTable("test", "no_key", columns, []string{"1", "a"})
+	upstream.tables[model.TableName{Schema: "test", Table: "no_key"}].keys = nil
+	downstream.addTable("test", "no_key", columns, []string{"1", "a"})
+	upstream.addTable("ignored", "t", columns, []string{"1", "a"})
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Filter.Rules = []string{"test.*"}
+	cfg := &Config{
+		ChangefeedID:  model.DefaultChangeFeedID("test"),
+		ReplicaConfig: replicaConfig,
+		ChunkSize:     2,
+		Concurrency:   2,
+	}
+	v := newVerifier(cfg, upstream, downstream)
+	reports, err := v.verify(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []*TableReport{
+		{
+			Schema: "test",
+			Table:  "diff",
+			Chunks: 3,
+			DifferentChunks: []*ChunkReport{
+				{
+					Range:              `(-inf, ("2")]`,
+					UpstreamCount:      2,
+					DownstreamCount:    2,
+					UpstreamChecksum:   uint64(crc32.ChecksumIEEE([]byte("1,a")) ^ crc32.ChecksumIEEE([]byte("2,b"))),
+					DownstreamChecksum: uint64(crc32.ChecksumIEEE([]byte("1,a")) ^ crc32.ChecksumIEEE([]byte("2,x"))),
+					DifferentRows: []*RowDiff{
+						{Type: RowDiffChanged, Key: "2", Upstream: "2,b", Downstream: "2,x"},
+					},
+				},
+				{
+					Range:              `(("2"), ("4")]`,
+					UpstreamCount:      2,
+					DownstreamCount:    1,
+					UpstreamChecksum:   uint64(crc32.ChecksumIEEE([]byte("3,c")) ^ crc32.ChecksumIEEE([]byte("4,d"))),
+					DownstreamChecksum: uint64(crc32.ChecksumIEEE([]byte("4,d"))),
+					DifferentRows: []*RowDiff{
+						{Type: RowDiffMissing, Key: "3", Upstream: "3,c"},
+					},
+					index: 1,
+				},
+				{
+					Range:              `(("4"), +inf]`,
+					UpstreamCount:      1,
+					DownstreamCount:    2,
+					UpstreamChecksum:   uint64(crc32.ChecksumIEEE([]byte("5,e"))),
+					DownstreamChecksum: uint64(crc32.ChecksumIEEE([]byte("5,e")) ^ crc32.ChecksumIEEE([]byte("6,f"))),
+					DifferentRows: []*RowDiff{
+						{Type: RowDiffExtra, Key: "6", Downstream: "6,f"},
+					},
+					index: 2,
+				},
+			},
+		},
+		{Schema: "test", Table: "missing", Error: "table does not exist in the downstream"},
+		{Schema: "test", Table: "missing_column", Error: "column v does not exist in the downstream"},
+		{Schema: "test", Table: "no_key", Error: "table without primary key is not supported"},
+		{Schema: "test", Table: "same", Chunks: 2},
+	}, reports)
+	require.Equal(t, Progress{
+		TotalTables:     5,
+		SplitTables:     5,
+		TotalChunks:     5,
+		CheckedChunks:   5,
+		DifferentChunks: 3,
+	}, v.progress())
+
+	// errors of checking chunks stop the verification
+	downstream.err = errors.New("test")
+	_, err = newVerifier(cfg, upstream, downstream).verify(context.Background())
+	require.ErrorContains(t, err, "test")
+}
+
+func TestDiffRows(t *testing.T) {
+	t.Parallel()
+
+	upRows := map[string][]string{
+		"a": {"a,1", "a,1"},
+		"b": {"b,1"},
+		"c": {"c,1"},
+	}
+	downRows := map[string][]string{
+		"a": {"a,1"},
+		"b": {"b,2"},
+		"d": {"d,1"},
+	}
+	require.Equal(t, []*RowDiff{
+		{Type: RowDiffMissing, Key: "a", Upstream: "a,1"},
+		{Type: RowDiffChanged, Key: "b", Upstream: "b,1", Downstream: "b,2"},
+		{Type: RowDiffMissing, Key: "c", Upstream: "c,1"},
+		{Type: RowDiffExtra, Key: "d", Downstream: "d,1"},
+	}, diffRows(upRows, downRows, 10))
+	require.Len(t, diffRows(upRows, downRows, 2), 2)
+}
@@ -57,6 +57,13 @@ var (
 	// MaxTiCDCVersion is the version of the maximum allowed TiCDC version.
 	// for version `x.y.z`, max allowed `x+2.0.0`
 	MaxTiCDCVersion = semver.New("8.0.0-alpha")
+
+	// changefeedHistoryMinVersion is the minimal TiCDC version which
+	// recognizes the history key of changefeeds in etcd.
+	changefeedHistoryMinVersion = semver.New("7.2.0-alpha")
+	// namespaceInfoMinVersion is the minimal TiCDC version which recognizes
+	// the namespace key in etcd.
+	namespaceInfoMinVersion = semver.New("7.2.0-alpha")
 )
 
 var versionHash = regexp.MustCompile("-[0-9]+-g[0-9a-f]{7,}(-dev)?")
@@ -263,6 +270,22 @@ func (v *TiCDCClusterVersion) ShouldRunCliWithOpenAPI() bool {
 	return !v.LessThan(*semver.New("6.2.0")) || (v.Major == 6 && v.Minor == 2 && v.Patch == 0)
 }
 
+// ShouldRecordChangefeedHistory returns whether the history of changefeeds
+// can be written to etcd. Captures of older versions fail to parse the
+// history key, so it is not written until all captures are upgraded.
+func (v *TiCDCClusterVersion) ShouldRecordChangefeedHistory() bool {
+	// we assume the unknown version to be the latest version
+	return v.Version == nil || !v.LessThan(*changefeedHistoryMinVersion)
+}
+
+// ShouldStoreNamespaceInfo returns whether the namespace info can be written
+// to etcd. Captures of older versions fail to parse the namespace key, so
+// namespaces can not be managed until all captures are upgraded.
+func (v *TiCDCClusterVersion) ShouldStoreNamespaceInfo() bool {
+	// we assume the unknown version to be the latest version
+	return v.Version == nil || !v.LessThan(*namespaceInfoMinVersion)
+}
+
 // ticdcClusterVersionUnknown is a read-only variable to represent the unknown cluster version
 var ticdcClusterVersionUnknown = TiCDCClusterVersion{}
 
@@ -395,8 +395,18 @@ func TestTiCDCClusterVersionFeaturesCompatible(t *testing.T) {
 	require.Equal(t, ver.ShouldEnableUnifiedSorterByDefault(), true)
 	require.Equal(t, ver.ShouldEnableOldValueByDefault(), true)
 
+	ver = TiCDCClusterVersion{semver.New("7.1.0")}
+	require.Equal(t, ver.ShouldRecordChangefeedHistory(), false)
+	require.Equal(t, ver.ShouldStoreNamespaceInfo(), false)
+
+	ver = TiCDCClusterVersion{semver.New("7.2.0-alpha")}
+	require.Equal(t, ver.ShouldRecordChangefeedHistory(), true)
+	require.Equal(t, ver.ShouldStoreNamespaceInfo(), true)
+
 	require.Equal(t, ticdcClusterVersionUnknown.ShouldEnableUnifiedSorterByDefault(), true)
 	require.Equal(t, ticdcClusterVersionUnknown.ShouldEnableOldValueByDefault(), true)
+	require.Equal(t, ticdcClusterVersionUnknown.ShouldRecordChangefeedHistory(), true)
+	require.Equal(t, ticdcClusterVersionUnknown.ShouldStoreNamespaceInfo(), true)
 }
 
 func TestCheckPDVersionError(t *testing.T) {